@@ -0,0 +1,135 @@
+// Package apperr defines typed application errors and maps database errors
+// onto them, so the web service and the bot can report failures consistently.
+package apperr
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/lib/pq"
+)
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindTooManyRequests
+	KindTooLarge
+)
+
+type Error struct {
+	Kind    Kind
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NotFound(message string) error {
+	return &Error{Kind: KindNotFound, Message: message}
+}
+
+func Conflict(message string) error {
+	return &Error{Kind: KindConflict, Message: message}
+}
+
+func Validation(message string) error {
+	return &Error{Kind: KindValidation, Message: message}
+}
+
+func Unauthorized(message string) error {
+	return &Error{Kind: KindUnauthorized, Message: message}
+}
+
+func Forbidden(message string) error {
+	return &Error{Kind: KindForbidden, Message: message}
+}
+
+func TooManyRequests(message string) error {
+	return &Error{Kind: KindTooManyRequests, Message: message}
+}
+
+func TooLarge(message string) error {
+	return &Error{Kind: KindTooLarge, Message: message}
+}
+
+// KindOf returns the kind of err, or KindInternal if err is not an *Error.
+func KindOf(err error) Kind {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Kind
+	}
+	return KindInternal
+}
+
+// Message returns a message that is safe to show to the user.
+func Message(err error) string {
+	var appErr *Error
+	if errors.As(err, &appErr) && appErr.Kind != KindInternal {
+		return appErr.Message
+	}
+	return "Internal server error"
+}
+
+// FromDB maps sql.ErrNoRows and Postgres error codes onto typed errors.
+// Unknown errors are returned unchanged and treated as internal.
+func FromDB(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &Error{Kind: KindNotFound, Message: "Not found", Err: err}
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Name() {
+		case "unique_violation":
+			return &Error{Kind: KindConflict, Message: "Already exists", Err: err}
+		case "foreign_key_violation":
+			return &Error{Kind: KindNotFound, Message: "Referenced record does not exist", Err: err}
+		case "not_null_violation", "check_violation", "string_data_right_truncation", "invalid_text_representation":
+			return &Error{Kind: KindValidation, Message: "Invalid value", Err: err}
+		}
+	}
+
+	return err
+}
+
+// HTTPStatus returns the HTTP status code matching the kind of err.
+func HTTPStatus(err error) int {
+	switch KindOf(err) {
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+	case KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
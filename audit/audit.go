@@ -0,0 +1,182 @@
+// Package audit keeps the log of changes admins make to events and their
+// participants, who made each one and what exactly changed, so a wrong
+// deletion or a surprising draw can be traced back to someone.
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+type Action string
+
+const (
+	EventCreated          Action = "event_created"
+	EventUpdated          Action = "event_updated"
+	EventDeleted          Action = "event_deleted"
+	EventStatusSet        Action = "event_status_set"
+	RegistrationWindowSet Action = "registration_window_set"
+	EventArchived         Action = "event_archived"
+	EventUnarchived       Action = "event_unarchived"
+	CapacitySet           Action = "capacity_set"
+	CurrentEventSet       Action = "current_event_set"
+	FlagSet               Action = "flag_set"
+	WinnersDrawn          Action = "winners_drawn"
+	UserDeleted           Action = "user_deleted"
+	UserEntriesSet        Action = "user_entries_set"
+	PaymentRefunded       Action = "payment_refunded"
+	AdminCreated          Action = "admin_created"
+	AdminDeleted          Action = "admin_deleted"
+	AdminPasswordSet      Action = "admin_password_set"
+	TokenCreated          Action = "token_created"
+	TokenRevoked          Action = "token_revoked"
+	SessionRevoked        Action = "session_revoked"
+)
+
+// Actors of changes that weren't made by a signed-in admin.
+const (
+	ActorAPI    = "api"
+	ActorSystem = "system"
+)
+
+// hidden are the fields left out of diffs because they are secrets.
+var hidden = map[string]bool{
+	"kiosk_token":   true,
+	"password_hash": true,
+	"token_hash":    true,
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx naming who makes the changes in it.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns the actor stored by WithActor, or ActorSystem if there is
+// none.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok {
+		return actor
+	}
+	return ActorSystem
+}
+
+// Record adds action on the event to the log, made by the actor of ctx,
+// with what changed from before to after. before is nil for things
+// created and after for things deleted. eventID is 0 for changes to no
+// event in particular.
+func Record(ctx context.Context, st store.Store, action Action, eventID int64, before, after any) error {
+	diff, err := Diff(before, after)
+	if err != nil {
+		return err
+	}
+	return st.CreateAuditRecord(ctx, &sqlc.CreateAuditRecordParams{
+		Actor:   Actor(ctx),
+		Action:  string(action),
+		EventID: sql.NullInt64{Int64: eventID, Valid: eventID != 0},
+		Diff:    diff,
+	})
+}
+
+type change struct {
+	From any `json:"from,omitempty"`
+	To   any `json:"to,omitempty"`
+}
+
+// Diff returns a JSON object with the fields of before and after, as they
+// are encoded to JSON, that differ, each as {"from": ..., "to": ...}.
+// Either may be nil.
+func Diff(before, after any) (json.RawMessage, error) {
+	from, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fields of things created or deleted that are left empty say nothing
+	changes := make(map[string]change)
+	for name, value := range from {
+		if next, ok := to[name]; ok && !reflect.DeepEqual(value, next) || !ok && !empty(value) {
+			changes[name] = change{From: value, To: next}
+		}
+	}
+	for name, value := range to {
+		if _, ok := from[name]; !ok && !empty(value) {
+			changes[name] = change{To: value}
+		}
+	}
+	return json.Marshal(changes)
+}
+
+// empty reports whether a JSON value is null, false, 0, "" or empty.
+func empty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// fields returns the JSON fields of v without the hidden ones. Nullable
+// database values, encoded as {"Valid": ..., "String": ...} and the like,
+// are flattened to their value or null.
+func fields(v any) (map[string]any, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for name, value := range m {
+		if hidden[name] {
+			delete(m, name)
+			continue
+		}
+		m[name] = flatten(value)
+	}
+	return m, nil
+}
+
+func flatten(value any) any {
+	null, ok := value.(map[string]any)
+	if !ok || len(null) != 2 {
+		return value
+	}
+	valid, ok := null["Valid"].(bool)
+	if !ok {
+		return value
+	}
+	if !valid {
+		return nil
+	}
+	for name, v := range null {
+		if name != "Valid" {
+			return v
+		}
+	}
+	return value
+}
@@ -0,0 +1,168 @@
+// Package authz holds the admin roles and the policy deciding which role
+// may perform which action, so handlers and templates ask one place instead
+// of checking roles themselves.
+package authz
+
+import (
+	"context"
+	"slices"
+)
+
+type Role string
+
+const (
+	// Owner can do everything, including irreversible actions.
+	Owner Role = "owner"
+	// Admin runs events day to day but cannot destroy data, run draws or
+	// manage other admins.
+	Admin Role = "admin"
+	// Moderator looks after participants, e.g. a volunteer at the
+	// entrance: they check people in and edit registrations but cannot
+	// change events.
+	Moderator Role = "moderator"
+	// Viewer can only look at events and their participants.
+	Viewer Role = "viewer"
+)
+
+// Roles lists the roles from the least to the most privileged. Every role
+// may do whatever the roles before it may.
+var Roles = []Role{Viewer, Moderator, Admin, Owner}
+
+type Action string
+
+const (
+	DeleteEvent        Action = "delete_event"
+	ArchiveEvent       Action = "archive_event"
+	RunDraw            Action = "run_draw"
+	ManageAdmins       Action = "manage_admins"
+	RunCleanup         Action = "run_cleanup"
+	ManageEvents       Action = "manage_events"
+	ManageParticipants Action = "manage_participants"
+	CheckIn            Action = "check_in"
+)
+
+// required is the least privileged role allowed each action. Actions not
+// listed here are restricted to owners.
+var required = map[Action]Role{
+	DeleteEvent:        Owner,
+	ArchiveEvent:       Owner,
+	RunDraw:            Owner,
+	ManageAdmins:       Owner,
+	RunCleanup:         Owner,
+	ManageEvents:       Admin,
+	ManageParticipants: Moderator,
+	CheckIn:            Moderator,
+}
+
+// AtLeast reports whether role is min or a more privileged role.
+func AtLeast(role, min Role) bool {
+	return slices.Index(Roles, role) >= slices.Index(Roles, min)
+}
+
+// Allowed reports whether role may perform action.
+func Allowed(role Role, action Action) bool {
+	min, ok := required[action]
+	if !ok {
+		min = Owner
+	}
+	return AtLeast(role, min)
+}
+
+// AllEvents reports whether role manages every event. The other roles only
+// see and manage the events whose organizers they are.
+func AllEvents(role Role) bool {
+	return role == Owner
+}
+
+// Scope limits what an API token may do, on top of the role of the admin
+// who minted it.
+type Scope string
+
+const (
+	ReadEvents        Scope = "events:read"
+	WriteEvents       Scope = "events:write"
+	DeleteEvents      Scope = "events:delete"
+	ReadParticipants  Scope = "participants:read"
+	WriteParticipants Scope = "participants:write"
+	RunDraws          Scope = "draws:run"
+)
+
+// Scopes lists every scope a token may have.
+var Scopes = []Scope{ReadEvents, WriteEvents, DeleteEvents, ReadParticipants, WriteParticipants, RunDraws}
+
+// scopeActions is the action each scope lets a token perform. Scopes not
+// listed here only read, which every role may do.
+var scopeActions = map[Scope]Action{
+	WriteEvents:       ManageEvents,
+	DeleteEvents:      DeleteEvent,
+	WriteParticipants: ManageParticipants,
+	RunDraws:          RunDraw,
+}
+
+// Grants reports whether role may use scope, so that a token never does
+// more than the admin who minted it.
+func Grants(role Role, scope Scope) bool {
+	if !slices.Contains(Scopes, scope) {
+		return false
+	}
+	action, ok := scopeActions[scope]
+	return !ok || Allowed(role, action)
+}
+
+// ParseRole returns the role named s, or Viewer for anything else so that
+// a malformed value never grants more than the least privileged role.
+func ParseRole(s string) Role {
+	if role := Role(s); slices.Contains(Roles, role) {
+		return role
+	}
+	return Viewer
+}
+
+type roleKey struct{}
+
+type scopesKey struct{}
+
+type eventsKey struct{}
+
+// WithRole returns a copy of ctx carrying the role of the signed-in admin.
+func WithRole(ctx context.Context, role Role) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// RoleFromContext returns the role stored by WithRole, or Viewer if there
+// is none.
+func RoleFromContext(ctx context.Context) Role {
+	if role, ok := ctx.Value(roleKey{}).(Role); ok {
+		return role
+	}
+	return Viewer
+}
+
+// WithScopes returns a copy of ctx limited to scopes, for requests made
+// with an API token.
+func WithScopes(ctx context.Context, scopes []Scope) context.Context {
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// Permits reports whether the request of ctx may use scope: its role must
+// grant it and, for requests made with an API token, so must the token.
+func Permits(ctx context.Context, scope Scope) bool {
+	if !Grants(RoleFromContext(ctx), scope) {
+		return false
+	}
+	scopes, ok := ctx.Value(scopesKey{}).([]Scope)
+	return !ok || slices.Contains(scopes, scope)
+}
+
+// WithEvents returns a copy of ctx limited to the events with eventIDs,
+// for admins whose role doesn't manage every event.
+func WithEvents(ctx context.Context, eventIDs []int64) context.Context {
+	return context.WithValue(ctx, eventsKey{}, eventIDs)
+}
+
+// EventAllowed reports whether the request of ctx may see and manage the
+// event with eventID: any event, unless WithEvents limited it to others.
+func EventAllowed(ctx context.Context, eventID int64) bool {
+	eventIDs, ok := ctx.Value(eventsKey{}).([]int64)
+	return !ok || slices.Contains(eventIDs, eventID)
+}
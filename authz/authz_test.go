@@ -0,0 +1,150 @@
+package authz_test
+
+import (
+	"context"
+	"testing"
+
+	"giveaway-tool/authz"
+)
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		action authz.Action
+		// want lists whether each of authz.Roles, from Viewer to Owner, may
+		// perform the action
+		want [4]bool
+	}{
+		{action: authz.DeleteEvent, want: [4]bool{false, false, false, true}},
+		{action: authz.ArchiveEvent, want: [4]bool{false, false, false, true}},
+		{action: authz.RunDraw, want: [4]bool{false, false, false, true}},
+		{action: authz.ManageAdmins, want: [4]bool{false, false, false, true}},
+		{action: authz.RunCleanup, want: [4]bool{false, false, false, true}},
+		{action: authz.ManageEvents, want: [4]bool{false, false, true, true}},
+		{action: authz.ManageParticipants, want: [4]bool{false, true, true, true}},
+		{action: authz.CheckIn, want: [4]bool{false, true, true, true}},
+		{action: "unknown_action", want: [4]bool{false, false, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.action), func(t *testing.T) {
+			for i, role := range authz.Roles {
+				if got := authz.Allowed(role, tt.action); got != tt.want[i] {
+					t.Errorf("Allowed(%s, %s) = %t, want %t", role, tt.action, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAllowedUnknownRole(t *testing.T) {
+	for _, action := range []authz.Action{authz.CheckIn, authz.ManageEvents, authz.DeleteEvent} {
+		if authz.Allowed("superuser", action) {
+			t.Errorf("Allowed(superuser, %s) = true for a role that doesn't exist", action)
+		}
+	}
+}
+
+func TestGrants(t *testing.T) {
+	tests := []struct {
+		scope authz.Scope
+		// want lists whether each of authz.Roles, from Viewer to Owner, may
+		// mint a token with the scope
+		want [4]bool
+	}{
+		{scope: authz.ReadEvents, want: [4]bool{true, true, true, true}},
+		{scope: authz.ReadParticipants, want: [4]bool{true, true, true, true}},
+		{scope: authz.WriteParticipants, want: [4]bool{false, true, true, true}},
+		{scope: authz.WriteEvents, want: [4]bool{false, false, true, true}},
+		{scope: authz.DeleteEvents, want: [4]bool{false, false, false, true}},
+		{scope: authz.RunDraws, want: [4]bool{false, false, false, true}},
+		{scope: "admin:everything", want: [4]bool{false, false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.scope), func(t *testing.T) {
+			for i, role := range authz.Roles {
+				if got := authz.Grants(role, tt.scope); got != tt.want[i] {
+					t.Errorf("Grants(%s, %s) = %t, want %t", role, tt.scope, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		in   string
+		want authz.Role
+	}{
+		{in: "owner", want: authz.Owner},
+		{in: "admin", want: authz.Admin},
+		{in: "moderator", want: authz.Moderator},
+		{in: "viewer", want: authz.Viewer},
+		{in: "Owner", want: authz.Viewer},
+		{in: "", want: authz.Viewer},
+		{in: "root", want: authz.Viewer},
+	}
+
+	for _, tt := range tests {
+		if got := authz.ParseRole(tt.in); got != tt.want {
+			t.Errorf("ParseRole(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPermits(t *testing.T) {
+	tests := []struct {
+		name string
+		role authz.Role
+		// scopes are those of the API token, nil for the admin session
+		scopes []authz.Scope
+		scope  authz.Scope
+		want   bool
+	}{
+		{name: "session", role: authz.Admin, scope: authz.WriteEvents, want: true},
+		{name: "session beyond role", role: authz.Moderator, scope: authz.WriteEvents},
+		{name: "token with scope", role: authz.Admin, scopes: []authz.Scope{authz.ReadEvents, authz.WriteEvents}, scope: authz.WriteEvents, want: true},
+		{name: "token without scope", role: authz.Owner, scopes: []authz.Scope{authz.ReadEvents}, scope: authz.WriteEvents},
+		{name: "token without scopes", role: authz.Owner, scopes: []authz.Scope{}, scope: authz.ReadEvents},
+		{name: "token beyond role", role: authz.Viewer, scopes: []authz.Scope{authz.WriteEvents}, scope: authz.WriteEvents},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := authz.WithRole(context.Background(), tt.role)
+			if tt.scopes != nil {
+				ctx = authz.WithScopes(ctx, tt.scopes)
+			}
+			if got := authz.Permits(ctx, tt.scope); got != tt.want {
+				t.Errorf("Permits(%s) = %t, want %t", tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventAllowed(t *testing.T) {
+	tests := []struct {
+		name string
+		// eventIDs are those the admin organizes, nil for every event
+		eventIDs []int64
+		eventID  int64
+		want     bool
+	}{
+		{name: "every event", eventID: 1, want: true},
+		{name: "organized", eventIDs: []int64{1, 2}, eventID: 2, want: true},
+		{name: "not organized", eventIDs: []int64{1, 2}, eventID: 3},
+		{name: "none organized", eventIDs: []int64{}, eventID: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.eventIDs != nil {
+				ctx = authz.WithEvents(ctx, tt.eventIDs)
+			}
+			if got := authz.EventAllowed(ctx, tt.eventID); got != tt.want {
+				t.Errorf("EventAllowed(%d) = %t, want %t", tt.eventID, got, tt.want)
+			}
+		})
+	}
+}
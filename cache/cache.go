@@ -0,0 +1,64 @@
+// Package cache provides a small in-memory key/value cache with per-entry
+// expiry.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+type Cache[K comparable, V any] struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	items map[K]entry[V]
+}
+
+func New[K comparable, V any](ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{
+		ttl:   ttl,
+		items: make(map[K]entry[V]),
+	}
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.items[key]
+	if !ok || time.Now().After(item.expiresAt) {
+		delete(c.items, key)
+		var zero V
+		return zero, false
+	}
+	return item.value, true
+}
+
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = entry[V]{
+		value:     value,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
+
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, key)
+}
+
+// Purge removes every entry.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.items)
+}
@@ -0,0 +1,70 @@
+// Package capacity limits how many participants can register for an
+// event. Events with a capacity of 0 take everyone.
+package capacity
+
+import (
+	"context"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+// pendingOrderWindow is how long an unpaid ticket order holds its place,
+// as it does for ticket types.
+const pendingOrderWindow = 3600
+
+// ErrFull is returned when the event has no places left.
+var ErrFull = apperr.Conflict("Registration is full")
+
+// Reserve checks that the event still has a place in tx, which should be
+// the transaction registering the participant or creating their order.
+// The event stays locked until tx ends, so concurrent registrations can't
+// go over its capacity.
+func Reserve(ctx context.Context, tx store.Store, eventID int64) error {
+	event, err := tx.GetEventForUpdate(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if event.Capacity == 0 {
+		return nil
+	}
+
+	left, err := Left(ctx, tx, event)
+	if err != nil {
+		return err
+	}
+	if left <= 0 {
+		return ErrFull
+	}
+	return nil
+}
+
+// Left returns how many places of a limited event are left.
+func Left(ctx context.Context, st store.Store, event *sqlc.Events) (int32, error) {
+	taken, err := st.CountEventTaken(ctx, &sqlc.CountEventTakenParams{
+		ID:             event.ID,
+		PendingSeconds: pendingOrderWindow,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return max(event.Capacity-taken, 0), nil
+}
+
+// Places returns the places left of each limited event, for pages that
+// list events. Events without a capacity are left out.
+func Places(ctx context.Context, st store.Store, events []*sqlc.Events) (map[int64]int32, error) {
+	places := make(map[int64]int32)
+	for _, event := range events {
+		if event.Capacity == 0 {
+			continue
+		}
+		left, err := Left(ctx, st, event)
+		if err != nil {
+			return nil, err
+		}
+		places[event.ID] = left
+	}
+	return places, nil
+}
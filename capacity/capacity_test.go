@@ -0,0 +1,83 @@
+package capacity_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"giveaway-tool/capacity"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+	"giveaway-tool/store/memory"
+)
+
+func TestReserve(t *testing.T) {
+	tests := []struct {
+		name         string
+		capacity     int32
+		participants int
+		pending      int
+		want         error
+	}{
+		{name: "unlimited", capacity: 0, participants: 5},
+		{name: "places left", capacity: 3, participants: 2},
+		{name: "full", capacity: 2, participants: 2, want: capacity.ErrFull},
+		{name: "over capacity", capacity: 1, participants: 3, want: capacity.ErrFull},
+		{name: "pending orders hold places", capacity: 3, participants: 1, pending: 2, want: capacity.ErrFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			st := memory.New()
+			event := newEvent(t, st, tt.capacity)
+			for i := range tt.participants {
+				if _, err := st.CreateUser(ctx, &sqlc.CreateUserParams{
+					Name:        fmt.Sprint("User ", i),
+					EventID:     event.ID,
+					CheckInCode: store.NewCheckInCode(),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for i := range tt.pending {
+				if _, err := st.CreateTicketOrder(ctx, &sqlc.CreateTicketOrderParams{
+					OrderID: fmt.Sprint("order-", i),
+					EventID: event.ID,
+					Name:    fmt.Sprint("Buyer ", i),
+					Amount:  100,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := st.InTx(ctx, func(tx store.Store) error {
+				return capacity.Reserve(ctx, tx, event.ID)
+			})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Reserve() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReserveMissingEvent(t *testing.T) {
+	if err := capacity.Reserve(context.Background(), memory.New(), 42); err == nil {
+		t.Error("Reserve() = nil for a missing event")
+	}
+}
+
+func newEvent(t *testing.T, st *memory.Store, places int32) *sqlc.Events {
+	t.Helper()
+	ctx := context.Background()
+	event, err := st.CreateEvent(ctx, &sqlc.CreateEventParams{Name: "Event", Date: time.Now().Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event, err = st.SetEventCapacity(ctx, &sqlc.SetEventCapacityParams{ID: event.ID, Capacity: places}); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
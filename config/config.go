@@ -9,7 +9,7 @@ import (
 	"strconv"
 	"sync"
 
-	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
 )
 
 type Config struct {
@@ -22,7 +22,7 @@ var (
 	mutex          sync.Mutex
 )
 
-func InitConfig(ctx context.Context, queries *sqlc.Queries) {
+func InitConfig(ctx context.Context, events store.EventStore) {
 	configInstance = &Config{}
 
 	// Try to load from file first
@@ -41,7 +41,7 @@ func InitConfig(ctx context.Context, queries *sqlc.Queries) {
 	}
 
 	if configInstance.CurrentEventID == nil {
-		event, err := queries.GetLastEvent(ctx)
+		event, err := events.GetLastEvent(ctx)
 		if err != nil {
 			slog.LogAttrs(ctx, slog.LevelError, "Failed to get last event from database", slog.Any("error", err))
 			return
@@ -74,14 +74,14 @@ func GetCurrentEventID() int64 {
 	return *configInstance.CurrentEventID
 }
 
-func SetCurrentEventID(eventID int64) {
+func SetCurrentEventID(ctx context.Context, eventID int64) {
 	mutex.Lock()
 	defer mutex.Unlock()
 
 	// Update environment variable (optional, for backward compatibility)
 	err := os.Setenv("CURRENT_EVENT_ID", strconv.FormatInt(eventID, 10))
 	if err != nil {
-		slog.LogAttrs(context.Background(), slog.LevelError, "Failed to set CURRENT_EVENT_ID", slog.String("error", err.Error()))
+		slog.LogAttrs(ctx, slog.LevelError, "Failed to set CURRENT_EVENT_ID", slog.String("error", err.Error()))
 	}
 
 	// Update in-memory config
@@ -92,13 +92,25 @@ func SetCurrentEventID(eventID int64) {
 
 	// Save to file for persistence
 	if err := saveConfigToFile(); err != nil {
-		slog.LogAttrs(context.Background(), slog.LevelError, "Failed to save config to file", slog.String("error", err.Error()))
+		slog.LogAttrs(ctx, slog.LevelError, "Failed to save config to file", slog.String("error", err.Error()))
 	} else {
-		slog.LogAttrs(context.Background(), slog.LevelInfo, "Set CURRENT_EVENT_ID", slog.Int64("event_id", *configInstance.CurrentEventID))
+		slog.LogAttrs(ctx, slog.LevelInfo, "Set CURRENT_EVENT_ID", slog.Int64("event_id", *configInstance.CurrentEventID))
 	}
 }
 
+// SetConfigFile changes where the current event is persisted. With an empty
+// path it is kept in memory only.
+func SetConfigFile(path string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	configFile = path
+}
+
 func loadConfigFromFile() error {
+	if configFile == "" {
+		return os.ErrNotExist
+	}
 	data, err := ioutil.ReadFile(configFile)
 	if err != nil {
 		return err
@@ -108,6 +120,9 @@ func loadConfigFromFile() error {
 }
 
 func saveConfigToFile() error {
+	if configFile == "" {
+		return nil
+	}
 	data, err := json.MarshalIndent(configInstance, "", "  ")
 	if err != nil {
 		return err
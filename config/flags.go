@@ -0,0 +1,107 @@
+package config
+
+import (
+	"context"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+type Flag string
+
+const (
+	FlagPublicRegistration Flag = "public_registration"
+	FlagWaitlist           Flag = "waitlist"
+	FlagPayments           Flag = "payments"
+	FlagProvablyFairDraws  Flag = "provably_fair_draws"
+)
+
+type FlagInfo struct {
+	Name        Flag
+	Description string
+	Enabled     bool
+}
+
+// knownFlags lists every flag in display order. Flags missing from the
+// database are disabled.
+var knownFlags = []FlagInfo{
+	{Name: FlagPublicRegistration, Description: "Реєстрація через публічну веб-форму"},
+	{Name: FlagWaitlist, Description: "Лист очікування для заповнених івентів"},
+	{Name: FlagPayments, Description: "Платні додаткові участі в розіграшах"},
+	{Name: FlagProvablyFairDraws, Description: "Розіграші з опублікованим seed, які можна перевірити"},
+}
+
+var flags = make(map[Flag]bool)
+
+// LoadFlags reads the flag values from the database.
+func LoadFlags(ctx context.Context, st store.FlagStore) error {
+	rows, err := st.GetFeatureFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	clear(flags)
+	for _, row := range rows {
+		flags[Flag(row.Name)] = row.Enabled
+	}
+	return nil
+}
+
+func FlagEnabled(flag Flag) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return flags[flag]
+}
+
+// Flags returns every known flag with its current value.
+func Flags() []FlagInfo {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	result := make([]FlagInfo, len(knownFlags))
+	for i, info := range knownFlags {
+		info.Enabled = flags[info.Name]
+		result[i] = info
+	}
+	return result
+}
+
+func IsKnownFlag(flag Flag) bool {
+	for _, info := range knownFlags {
+		if info.Name == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFlag persists the flag value and applies it immediately.
+func SetFlag(ctx context.Context, st store.FlagStore, flag Flag, enabled bool) error {
+	if err := SaveFlag(ctx, st, flag, enabled); err != nil {
+		return err
+	}
+	ApplyFlag(flag, enabled)
+	return nil
+}
+
+// SaveFlag persists the flag value without applying it, for saving it in a
+// transaction. Call ApplyFlag once the transaction is committed.
+func SaveFlag(ctx context.Context, st store.FlagStore, flag Flag, enabled bool) error {
+	_, err := st.SetFeatureFlag(ctx, &sqlc.SetFeatureFlagParams{
+		Name:    string(flag),
+		Enabled: enabled,
+	})
+	return err
+}
+
+// ApplyFlag sets the flag value in use, without persisting it.
+func ApplyFlag(flag Flag, enabled bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	flags[flag] = enabled
+}
@@ -0,0 +1,62 @@
+// Package consent keeps the audit trail of participants agreeing to
+// notifications and data processing, opting out and asking for deletion,
+// so the organizers can answer the university's compliance questions.
+package consent
+
+import (
+	"context"
+	"database/sql"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+// Text is the consent participants give by registering. It is shown
+// wherever they register.
+const Text = "Реєструючись, ти погоджуєшся на обробку своїх даних для проведення івенту та на повідомлення про нього. Відкликати згоду чи попросити видалити дані можна будь-коли: заблокуй бота, скасуй реєстрацію за посиланням керування або напиши організаторам."
+
+// Version identifies Text in the log. Bump it whenever Text changes, so
+// it's clear which wording each participant agreed to.
+const Version = "2025-07-31"
+
+type Action string
+
+const (
+	Consented Action = "consented"
+	// OptedOut is a participant blocking the bot, after which they get no
+	// more messages; OptedIn is them writing to it again
+	OptedOut          Action = "opted_out"
+	OptedIn           Action = "opted_in"
+	DeletionRequested Action = "deletion_requested"
+	// Deleted is a participant removed by an admin, who can restore them
+	// from the trash; Restored is them being restored
+	Deleted  Action = "deleted"
+	Restored Action = "restored"
+)
+
+// Where a participant gave or withdrew consent.
+const (
+	SourceTelegram    = "telegram"
+	SourceWebsite     = "website"
+	SourceAPI         = "api"
+	SourceKiosk       = "kiosk"
+	SourceSelfService = "self_service"
+	SourceAdmin       = "admin"
+)
+
+// Record adds action by the participant to the log. Consent is recorded
+// with the current Version.
+func Record(ctx context.Context, st store.Store, user *sqlc.Users, action Action, source string) error {
+	var version string
+	if action == Consented {
+		version = Version
+	}
+	return st.CreateConsentRecord(ctx, &sqlc.CreateConsentRecordParams{
+		EventID:      user.EventID,
+		UserID:       sql.NullInt64{Int64: user.ID, Valid: true},
+		TicketNumber: user.TicketNumber,
+		Action:       string(action),
+		Source:       source,
+		Version:      version,
+	})
+}
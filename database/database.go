@@ -9,7 +9,23 @@ import (
 	"github.com/pressly/goose/v3"
 )
 
+const MigrationsDir = "./database/migrations"
+
 func New(ctx context.Context) (*sql.DB, error) {
+	db, err := Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Migrate(ctx, db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Open connects to the database from DATABASE_URL without running migrations.
+func Open(ctx context.Context) (*sql.DB, error) {
 	dbURL := os.Getenv("DATABASE_URL")
 
 	db, err := sql.Open("postgres", dbURL)
@@ -17,17 +33,41 @@ func New(ctx context.Context) (*sql.DB, error) {
 		return nil, err
 	}
 
-	if err = db.Ping(); err != nil {
+	if err = db.PingContext(ctx); err != nil {
 		db.Close()
 		return nil, err
 	}
 
+	return db, nil
+}
+
+func Migrate(ctx context.Context, db *sql.DB) error {
 	goose.SetDialect("postgres")
 	goose.SetBaseFS(nil)
 
-	if err := goose.Up(db, "./database/migrations"); err != nil {
-		return nil, err
+	return goose.UpContext(ctx, db, MigrationsDir)
+}
+
+// MigrationStatus returns the applied migration version and the latest
+// available one.
+func MigrationStatus(ctx context.Context, db *sql.DB) (current, latest int64, err error) {
+	goose.SetDialect("postgres")
+	goose.SetBaseFS(nil)
+
+	current, err = goose.GetDBVersionContext(ctx, db)
+	if err != nil {
+		return 0, 0, err
 	}
 
-	return db, nil
+	migrations, err := goose.CollectMigrations(MigrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	last, err := migrations.Last()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return current, last.Version, nil
 }
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Participants registered through the website or API don't have a Telegram ID
+ALTER TABLE users ALTER COLUMN tg_id DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key TEXT NOT NULL,
+    scope TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    content_type TEXT NOT NULL,
+    body BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS idempotency_keys;
+DELETE FROM users WHERE tg_id IS NULL;
+ALTER TABLE users ALTER COLUMN tg_id SET NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- version is bumped on every update so concurrent edits can be detected
+ALTER TABLE events ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
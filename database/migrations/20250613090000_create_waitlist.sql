@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS waitlist (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    username TEXT NOT NULL,
+    tg_id BIGINT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_waitlist_tg_event_id UNIQUE (tg_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_waitlist_event_id ON waitlist(event_id, created_at, id);
+
+-- When set, the first person on the waitlist takes the place of a removed participant
+ALTER TABLE events ADD COLUMN waitlist_auto_promote BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS waitlist_auto_promote;
+DROP TABLE IF EXISTS waitlist;
+-- +goose StatementEnd
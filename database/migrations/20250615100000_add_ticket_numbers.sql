@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- last_ticket_number is bumped with a row lock on every registration, so
+-- ticket numbers are sequential per event even under concurrent sign-ups
+ALTER TABLE events ADD COLUMN last_ticket_number INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN ticket_number INTEGER;
+
+UPDATE users SET ticket_number = numbered.ticket_number
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY event_id ORDER BY id) AS ticket_number
+    FROM users
+) AS numbered
+WHERE users.id = numbered.id;
+
+UPDATE events SET last_ticket_number = COALESCE(
+    (SELECT MAX(ticket_number) FROM users WHERE users.event_id = events.id), 0);
+
+ALTER TABLE users ALTER COLUMN ticket_number SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT unique_ticket_event_id UNIQUE (event_id, ticket_number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT IF EXISTS unique_ticket_event_id;
+ALTER TABLE users DROP COLUMN IF EXISTS ticket_number;
+ALTER TABLE events DROP COLUMN IF EXISTS last_ticket_number;
+-- +goose StatementEnd
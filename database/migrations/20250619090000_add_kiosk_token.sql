@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- kiosk_token grants access to the on-site registration page of one event
+ALTER TABLE events ADD COLUMN kiosk_token TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS kiosk_token;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Short codes volunteers can type in when a ticket QR code won't scan. The
+-- alphabet leaves out 0/O and 1/I, which are easy to mix up when read aloud.
+ALTER TABLE users ADD COLUMN check_in_code TEXT;
+
+-- The WHERE clause correlates the subquery with the row, so Postgres
+-- evaluates it once per participant instead of once for the whole update
+UPDATE users SET check_in_code = (
+    SELECT string_agg(substr('ABCDEFGHJKLMNPQRSTUVWXYZ23456789', 1 + floor(random() * 32)::int, 1), '')
+    FROM generate_series(1, 6)
+    WHERE users.id IS NOT NULL
+);
+
+ALTER TABLE users ALTER COLUMN check_in_code SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT unique_check_in_code_event_id UNIQUE (event_id, check_in_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT IF EXISTS unique_check_in_code_event_id;
+ALTER TABLE users DROP COLUMN IF EXISTS check_in_code;
+-- +goose StatementEnd
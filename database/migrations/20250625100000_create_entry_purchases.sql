@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- max_paid_entries = 0 turns purchases off for the event; entry_price is
+-- in kopecks
+ALTER TABLE events ADD COLUMN max_paid_entries INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN entry_price INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN paid_entries INTEGER NOT NULL DEFAULT 0;
+
+-- Purchases outlive cancelled registrations, so payments can still be
+-- reconciled with the provider
+CREATE TABLE IF NOT EXISTS entry_purchases (
+    id BIGSERIAL PRIMARY KEY,
+    order_id TEXT NOT NULL UNIQUE,
+    user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    entries INTEGER NOT NULL CHECK (entries > 0),
+    amount INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    paid_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_entry_purchases_user_id ON entry_purchases(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS entry_purchases;
+ALTER TABLE users DROP COLUMN IF EXISTS paid_entries;
+ALTER TABLE events DROP COLUMN IF EXISTS entry_price;
+ALTER TABLE events DROP COLUMN IF EXISTS max_paid_entries;
+-- +goose StatementEnd
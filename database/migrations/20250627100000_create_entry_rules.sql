@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A rule gives bonus entries to participants matching all of its set
+-- conditions: a ticket number up to max_ticket (the first N registrants)
+-- and registration before registered_before
+CREATE TABLE IF NOT EXISTS entry_rules (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    max_ticket INTEGER,
+    registered_before TIMESTAMP,
+    bonus INTEGER NOT NULL CHECK (bonus > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT entry_rules_condition CHECK (max_ticket IS NOT NULL OR registered_before IS NOT NULL)
+);
+CREATE INDEX IF NOT EXISTS idx_entry_rules_event_id ON entry_rules(event_id);
+
+-- The rules applied at registration or the last recalculation
+ALTER TABLE users ADD COLUMN bonus_entries INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN applied_rule_ids BIGINT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS applied_rule_ids;
+ALTER TABLE users DROP COLUMN IF EXISTS bonus_entries;
+DROP TABLE IF EXISTS entry_rules;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- share_clicks_required = 0 turns share bonuses off for the event
+ALTER TABLE events ADD COLUMN share_clicks_required INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN share_bonus INTEGER NOT NULL DEFAULT 1;
+
+-- share_entries is kept apart from bonus_entries, which entry rule
+-- recalculation overwrites
+ALTER TABLE users ADD COLUMN share_code TEXT UNIQUE;
+ALTER TABLE users ADD COLUMN share_entries INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN share_bonus_granted_at TIMESTAMP;
+
+-- visitor_hash identifies who opened a share link without storing their
+-- IP address
+CREATE TABLE IF NOT EXISTS share_clicks (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    visitor_hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, visitor_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_share_clicks_event_visitor ON share_clicks(event_id, visitor_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS share_clicks;
+ALTER TABLE users DROP COLUMN IF EXISTS share_bonus_granted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS share_entries;
+ALTER TABLE users DROP COLUMN IF EXISTS share_code;
+ALTER TABLE events DROP COLUMN IF EXISTS share_bonus;
+ALTER TABLE events DROP COLUMN IF EXISTS share_clicks_required;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- flag_reason is set by the suspicious-registration scan; a flagged
+-- participant stays out of draws until an admin approves them
+ALTER TABLE users ADD COLUMN flag_reason TEXT;
+ALTER TABLE users ADD COLUMN reviewed_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE users DROP COLUMN IF EXISTS flag_reason;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Each row is an admin's Telegram chat receiving the weekly digest.
+-- weekday follows Go's time.Weekday (0 = Sunday); weekday and hour are in
+-- the server's time zone
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    chat_id BIGINT NOT NULL UNIQUE,
+    weekday INTEGER NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    hour INTEGER NOT NULL CHECK (hour BETWEEN 0 AND 23),
+    include_anomalies BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS digest_subscriptions;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Templates hold an event's reusable setup: no date and no participants
+CREATE TABLE IF NOT EXISTS event_templates (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    waitlist_auto_promote BOOLEAN NOT NULL DEFAULT FALSE,
+    max_paid_entries INTEGER NOT NULL DEFAULT 0,
+    entry_price INTEGER NOT NULL DEFAULT 0,
+    share_clicks_required INTEGER NOT NULL DEFAULT 0,
+    share_bonus INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Date-based rules are kept relative to the event date, so they still
+-- make sense for an event on another day
+CREATE TABLE IF NOT EXISTS event_template_rules (
+    id BIGSERIAL PRIMARY KEY,
+    template_id BIGINT NOT NULL REFERENCES event_templates(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    max_ticket INTEGER,
+    seconds_before_event INTEGER,
+    bonus INTEGER NOT NULL CHECK (bonus > 0),
+    CONSTRAINT event_template_rules_condition CHECK (max_ticket IS NOT NULL OR seconds_before_event IS NOT NULL)
+);
+CREATE INDEX IF NOT EXISTS idx_event_template_rules_template_id ON event_template_rules(template_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS event_template_rules;
+DROP TABLE IF EXISTS event_templates;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Digest recipients choose which notifications they get: notify_kinds
+-- lists the kinds (registration, milestone, draw, error) and
+-- notify_event_ids limits event notifications to some events, all events
+-- when empty
+ALTER TABLE digest_subscriptions ADD COLUMN weekly_digest BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE digest_subscriptions ADD COLUMN notify_kinds TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE digest_subscriptions ADD COLUMN notify_event_ids BIGINT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE digest_subscriptions DROP COLUMN IF EXISTS notify_event_ids;
+ALTER TABLE digest_subscriptions DROP COLUMN IF EXISTS notify_kinds;
+ALTER TABLE digest_subscriptions DROP COLUMN IF EXISTS weekly_digest;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The team running an event, shown on its admin page
+CREATE TABLE IF NOT EXISTS event_organizers (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    username TEXT NOT NULL DEFAULT '',
+    responsibility TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT event_organizers_event_id_name_key UNIQUE (event_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS event_organizers;
+-- +goose StatementEnd
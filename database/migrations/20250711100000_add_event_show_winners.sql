@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Whether the event's winners appear on the public wall of fame
+ALTER TABLE events ADD COLUMN show_winners BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS show_winners;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Admin labels such as "volunteer" or "speaker", scoped to the participant's event
+ALTER TABLE users ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS tags;
+-- +goose StatementEnd
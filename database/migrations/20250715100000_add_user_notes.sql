@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Admin-only remarks such as "prize handed over"
+ALTER TABLE users ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS notes;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Set by the archiving job once an event is long over
+ALTER TABLE events ADD COLUMN archived_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS archived_at;
+-- +goose StatementEnd
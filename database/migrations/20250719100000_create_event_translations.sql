@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Names and descriptions of events in languages other than the one they
+-- were written in
+CREATE TABLE IF NOT EXISTS event_translations (
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    language TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, language)
+);
+CREATE INDEX IF NOT EXISTS idx_event_translations_language ON event_translations(language);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS event_translations;
+-- +goose StatementEnd
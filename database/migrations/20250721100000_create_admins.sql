@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Admins who sign in with a link sent to their Telegram chat instead of
+-- the shared password
+CREATE TABLE IF NOT EXISTS admins (
+    id BIGSERIAL PRIMARY KEY,
+    username TEXT NOT NULL UNIQUE,
+    role TEXT NOT NULL DEFAULT 'manager',
+    chat_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- Login links that haven't been used yet; a link works only while its
+-- nonce is here
+CREATE TABLE IF NOT EXISTS admin_login_tokens (
+    nonce TEXT PRIMARY KEY,
+    admin_id BIGINT NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS admin_login_tokens;
+DROP TABLE IF EXISTS admins;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Messages composed with Telegram's Markdown, like winner announcements
+ALTER TABLE outbox ADD COLUMN markdown BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox DROP COLUMN IF EXISTS markdown;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Broadcasts scheduled for later; queued_at is set once the messages are
+-- put in the outbox, after which the broadcast can't be changed
+CREATE TABLE IF NOT EXISTS broadcasts (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    send_at TIMESTAMP NOT NULL,
+    queued_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_broadcasts_event_id ON broadcasts(event_id);
+CREATE INDEX IF NOT EXISTS idx_broadcasts_pending ON broadcasts(send_at)
+    WHERE queued_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS broadcasts;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The message a broadcast sent to one participant and what became of it:
+-- pending, sent, failed, or blocked when the participant blocked the bot.
+-- text is kept so failed messages can be retried.
+CREATE TABLE IF NOT EXISTS broadcast_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    broadcast_id BIGINT NOT NULL REFERENCES broadcasts(id) ON DELETE CASCADE,
+    user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    chat_id BIGINT NOT NULL,
+    text TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    error TEXT,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_broadcast_deliveries_broadcast_id ON broadcast_deliveries(broadcast_id);
+
+-- Set on outbox messages sent by a broadcast, so the sender can record
+-- the result
+ALTER TABLE outbox ADD COLUMN delivery_id BIGINT REFERENCES broadcast_deliveries(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox DROP COLUMN IF EXISTS delivery_id;
+DROP TABLE IF EXISTS broadcast_deliveries;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Set when Telegram refuses a message because the participant blocked the
+-- bot, and cleared when they write to it again
+ALTER TABLE users ADD COLUMN bot_blocked_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS bot_blocked_at;
+-- +goose StatementEnd
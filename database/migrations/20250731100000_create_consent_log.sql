@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Audit trail of participants' consent: when they agreed to notifications
+-- and data processing and to which version of the wording, when they opted
+-- out or back in, and when they asked for their data to be deleted. Rows
+-- outlive the participant, who is then identified by ticket number only.
+CREATE TABLE IF NOT EXISTS consent_log (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    ticket_number INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    source TEXT NOT NULL,
+    version TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_consent_log_event_id ON consent_log(event_id, created_at, id);
+
+-- Participants registered before the log existed consented by registering,
+-- with wording that wasn't versioned
+INSERT INTO consent_log (event_id, user_id, ticket_number, action, source, created_at)
+SELECT event_id, id, ticket_number, 'consented', 'unknown', COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM users;
+INSERT INTO consent_log (event_id, user_id, ticket_number, action, source, created_at)
+SELECT event_id, id, ticket_number, 'opted_out', 'telegram', bot_blocked_at
+FROM users
+WHERE bot_blocked_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS consent_log;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Set on messages that are texted to the participant's phone instead when
+-- Telegram can't deliver them
+ALTER TABLE outbox ADD COLUMN sms_user_id BIGINT REFERENCES users(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox DROP COLUMN IF EXISTS sms_user_id;
+-- +goose StatementEnd
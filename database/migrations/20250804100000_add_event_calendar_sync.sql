@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The event's copy in the community Google Calendar, and the version of
+-- the event it was last synced at; events whose version is ahead are
+-- pushed to the calendar again
+ALTER TABLE events ADD COLUMN calendar_event_id TEXT;
+ALTER TABLE events ADD COLUMN calendar_synced_version INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS calendar_synced_version;
+ALTER TABLE events DROP COLUMN IF EXISTS calendar_event_id;
+-- +goose StatementEnd
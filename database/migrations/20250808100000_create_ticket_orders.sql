@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- ticket_price = 0 makes registration free; it is in kopecks like
+-- entry_price
+ALTER TABLE events ADD COLUMN ticket_price INTEGER NOT NULL DEFAULT 0;
+
+-- A paid registration through the web form. The participant is only
+-- created once the provider confirms the payment; until then the order
+-- keeps what they entered. Orders outlive cancelled registrations, like
+-- entry purchases, so payments can still be reconciled.
+CREATE TABLE IF NOT EXISTS ticket_orders (
+    id BIGSERIAL PRIMARY KEY,
+    order_id TEXT NOT NULL UNIQUE,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    name TEXT NOT NULL,
+    username TEXT NOT NULL DEFAULT '',
+    amount INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    paid_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_ticket_orders_event_id ON ticket_orders(event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ticket_orders;
+ALTER TABLE events DROP COLUMN IF EXISTS ticket_price;
+-- +goose StatementEnd
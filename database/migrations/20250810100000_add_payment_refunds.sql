@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- payment_id is the provider's ID of the payment, which some providers
+-- need to refund it. Refunded payments get the status 'refunded'.
+ALTER TABLE ticket_orders ADD COLUMN payment_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE ticket_orders ADD COLUMN refunded_at TIMESTAMP;
+ALTER TABLE entry_purchases ADD COLUMN payment_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE entry_purchases ADD COLUMN refunded_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE entry_purchases DROP COLUMN IF EXISTS refunded_at;
+ALTER TABLE entry_purchases DROP COLUMN IF EXISTS payment_id;
+ALTER TABLE ticket_orders DROP COLUMN IF EXISTS refunded_at;
+ALTER TABLE ticket_orders DROP COLUMN IF EXISTS payment_id;
+-- +goose StatementEnd
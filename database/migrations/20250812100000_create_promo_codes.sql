@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- kind is 'free' (the ticket costs nothing), 'discount' (value percent off
+-- the ticket) or 'entries' (value extra entries in the draw). Codes are
+-- stored upper-case and max_uses = 0 means unlimited.
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    code TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    value INTEGER NOT NULL DEFAULT 0,
+    max_uses INTEGER NOT NULL DEFAULT 0,
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (event_id, code)
+);
+
+-- A use of a code. Uses of paid tickets carry the order until it is paid;
+-- they outlive cancelled registrations, like payments, for the statistics.
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    id BIGSERIAL PRIMARY KEY,
+    promo_code_id BIGINT NOT NULL REFERENCES promo_codes(id) ON DELETE CASCADE,
+    user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    order_id TEXT,
+    discount INTEGER NOT NULL DEFAULT 0,
+    entries INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_promo_code_id ON promo_redemptions(promo_code_id);
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_order_id ON promo_redemptions(order_id);
+
+-- promo_entries is kept apart from bonus_entries, which entry rule
+-- recalculation overwrites
+ALTER TABLE users ADD COLUMN promo_entries INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS promo_entries;
+DROP TABLE IF EXISTS promo_redemptions;
+DROP TABLE IF EXISTS promo_codes;
+-- +goose StatementEnd
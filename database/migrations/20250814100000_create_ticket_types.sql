@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Events with ticket types sell their tickets by type instead of at the
+-- event's ticket price. capacity = 0 means unlimited, and weight is the
+-- number of entries in the draw a participant of the type starts with.
+CREATE TABLE IF NOT EXISTS ticket_types (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    price INTEGER NOT NULL DEFAULT 0,
+    capacity INTEGER NOT NULL DEFAULT 0,
+    weight INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (event_id, name)
+);
+
+ALTER TABLE users ADD COLUMN ticket_type_id BIGINT REFERENCES ticket_types(id) ON DELETE SET NULL;
+ALTER TABLE ticket_orders ADD COLUMN ticket_type_id BIGINT REFERENCES ticket_types(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_users_ticket_type_id ON users(ticket_type_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ticket_orders DROP COLUMN IF EXISTS ticket_type_id;
+ALTER TABLE users DROP COLUMN IF EXISTS ticket_type_id;
+DROP TABLE IF EXISTS ticket_types;
+-- +goose StatementEnd
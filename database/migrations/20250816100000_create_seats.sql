@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Seats of seated events, or time slots, labelled the way they are printed
+-- on tickets. A seat holds at most one participant, and cancelling the
+-- registration frees it.
+CREATE TABLE IF NOT EXISTS seats (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    label TEXT NOT NULL,
+    user_id BIGINT UNIQUE REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (event_id, label)
+);
+
+-- seat_assignment is when participants get a free seat: 'registration',
+-- 'check_in' or 'manual' when organizers seat them from the admin panel.
+ALTER TABLE events ADD COLUMN seat_assignment TEXT NOT NULL DEFAULT 'registration';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS seat_assignment;
+DROP TABLE IF EXISTS seats;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- unarchived_at is set when an admin takes an event out of the archive, so
+-- automatic archiving leaves it alone from then on.
+ALTER TABLE events ADD COLUMN unarchived_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS unarchived_at;
+-- +goose StatementEnd
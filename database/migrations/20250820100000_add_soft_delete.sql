@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deleted events and participants stay in the trash until a cleanup after
+-- the retention period removes them for good, so they can be restored.
+ALTER TABLE events ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+-- A participant in the trash doesn't stop the same account registering
+-- again.
+ALTER TABLE users DROP CONSTRAINT unique_tg_event_id;
+CREATE UNIQUE INDEX unique_tg_event_id ON users (tg_id, event_id) WHERE deleted_at IS NULL;
+CREATE INDEX users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+DELETE FROM events WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS users_deleted_at_idx;
+DROP INDEX IF EXISTS unique_tg_event_id;
+ALTER TABLE users ADD CONSTRAINT unique_tg_event_id UNIQUE (tg_id, event_id);
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE events DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
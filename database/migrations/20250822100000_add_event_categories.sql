@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Categories like "lecture" or "party" separate the club's kinds of
+-- activities on the events page and the dashboard.
+ALTER TABLE events ADD COLUMN categories TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS categories;
+-- +goose StatementEnd
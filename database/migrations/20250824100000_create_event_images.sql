@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Event banners are kept in the database, since the app has no persistent
+-- disk. image_updated_at tells pages whether an event has an image and
+-- versions its URL, so browsers can cache it.
+CREATE TABLE event_images (
+    event_id BIGINT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    content_type TEXT NOT NULL,
+    data BYTEA NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE events ADD COLUMN image_updated_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS image_updated_at;
+DROP TABLE IF EXISTS event_images;
+-- +goose StatementEnd
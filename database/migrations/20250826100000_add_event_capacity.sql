@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- capacity = 0 means unlimited, as for ticket types.
+ALTER TABLE events ADD COLUMN capacity INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS capacity;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- status is where the event is in its lifecycle: 'draft', 'published',
+-- 'registration_open' or 'closed'. Existing events keep taking
+-- registrations; new ones start as drafts.
+ALTER TABLE events ADD COLUMN status TEXT NOT NULL DEFAULT 'registration_open';
+ALTER TABLE events ALTER COLUMN status SET DEFAULT 'draft';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
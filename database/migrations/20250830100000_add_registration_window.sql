@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The window in which an event with open registration takes participants.
+-- Either end may be left open.
+ALTER TABLE events ADD COLUMN registration_opens_at TIMESTAMP;
+ALTER TABLE events ADD COLUMN registration_closes_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS registration_closes_at;
+ALTER TABLE events DROP COLUMN IF EXISTS registration_opens_at;
+-- +goose StatementEnd
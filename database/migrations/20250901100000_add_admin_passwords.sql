@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Admins sign in with a password, kept as a bcrypt hash, with a login link
+-- sent to their Telegram chat, or both, so either may be missing.
+ALTER TABLE admins ADD COLUMN password_hash TEXT;
+ALTER TABLE admins ALTER COLUMN chat_id DROP NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM admins WHERE chat_id IS NULL;
+ALTER TABLE admins ALTER COLUMN chat_id SET NOT NULL;
+ALTER TABLE admins DROP COLUMN IF EXISTS password_hash;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Managers became admins when moderators and viewers were added below
+-- them
+UPDATE admins SET role = 'admin' WHERE role = 'manager';
+ALTER TABLE admins ALTER COLUMN role SET DEFAULT 'viewer';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Only owners and managers existed before, so everyone else becomes a
+-- manager
+UPDATE admins SET role = 'manager' WHERE role <> 'owner';
+ALTER TABLE admins ALTER COLUMN role SET DEFAULT 'manager';
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Changes made by admins, with what changed as a JSON object of
+-- {"field": {"from": ..., "to": ...}}. event_id has no foreign key so the
+-- log outlives events emptied from the trash.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    event_id BIGINT,
+    diff JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_log;
+-- +goose StatementEnd
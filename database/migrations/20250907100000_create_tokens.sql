@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Bearer tokens admins mint for scripts calling the JSON API. A token acts
+-- as the admin who minted it, limited to its scopes, and only its SHA-256
+-- hash is kept.
+CREATE TABLE IF NOT EXISTS tokens (
+    id BIGSERIAL PRIMARY KEY,
+    admin_id BIGINT NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS tokens_admin_id_idx ON tokens (admin_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tokens;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Browser sessions. The cookie holds only a random token, of which the
+-- SHA-256 hash is kept here, so sessions don't depend on a signing key and
+-- can be listed and revoked.
+CREATE TABLE IF NOT EXISTS sessions (
+    id BIGSERIAL PRIMARY KEY,
+    token_hash TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    -- The admin signed in with the session, if any
+    admin_id BIGINT REFERENCES admins(id) ON DELETE CASCADE,
+    data BYTEA NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS sessions_admin_id_idx ON sessions (admin_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sessions;
+-- +goose StatementEnd
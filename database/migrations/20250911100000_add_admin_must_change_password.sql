@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Admins flagged have to choose a new password before they can do
+-- anything else, as the owner created from the environment may have to.
+ALTER TABLE admins ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE admins DROP COLUMN IF EXISTS must_change_password;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- slug names the event's public page, /events/{slug}. Deleted events keep
+-- theirs, so they come back with the same link.
+ALTER TABLE events ADD COLUMN slug TEXT UNIQUE;
+UPDATE events SET slug = 'event-' || id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS slug;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A short link, /e/{code}, that organizers hand out in one promo channel.
+-- target is 'bot' (the Telegram deep link) or 'page' (the public page),
+-- and clicks counts the visits that came through it.
+CREATE TABLE IF NOT EXISTS short_links (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    code TEXT NOT NULL UNIQUE,
+    channel TEXT NOT NULL,
+    target TEXT NOT NULL,
+    clicks INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_short_links_event_id ON short_links(event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS short_links;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- People on the waitlist are registered with the ticket type they chose and
+-- the consent they gave where they joined it, once a place frees up.
+ALTER TABLE waitlist ADD COLUMN ticket_type_id BIGINT REFERENCES ticket_types(id) ON DELETE SET NULL;
+ALTER TABLE waitlist ADD COLUMN consent_source TEXT NOT NULL DEFAULT 'website';
+UPDATE waitlist SET consent_source = 'telegram' WHERE tg_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE waitlist DROP COLUMN IF EXISTS consent_source;
+ALTER TABLE waitlist DROP COLUMN IF EXISTS ticket_type_id;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Organizers linked to an admin account. Admins other than owners only
+-- manage the events they organize.
+ALTER TABLE event_organizers ADD COLUMN admin_id BIGINT REFERENCES admins(id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS event_organizers_event_id_admin_id_key ON event_organizers (event_id, admin_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS event_organizers_event_id_admin_id_key;
+ALTER TABLE event_organizers DROP COLUMN IF EXISTS admin_id;
+-- +goose StatementEnd
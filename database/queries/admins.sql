@@ -0,0 +1,52 @@
+-- name: CreateAdmin :one
+INSERT INTO admins (
+    username,
+    role,
+    chat_id,
+    password_hash,
+    must_change_password
+) VALUES (
+    sqlc.arg(username),
+    sqlc.arg(role),
+    sqlc.narg(chat_id),
+    sqlc.narg(password_hash),
+    sqlc.arg(must_change_password)
+) RETURNING *;
+-- name: GetAdmins :many
+SELECT * FROM admins
+ORDER BY username;
+-- name: GetAdminByID :one
+SELECT * FROM admins
+WHERE id = sqlc.arg(id);
+-- name: GetAdminByUsername :one
+SELECT * FROM admins
+WHERE username = sqlc.arg(username);
+-- name: SetAdminPassword :exec
+-- A new password is one the admin no longer has to change.
+UPDATE admins
+SET password_hash = sqlc.arg(password_hash),
+    must_change_password = false
+WHERE id = sqlc.arg(id);
+-- name: DeleteAdmin :exec
+DELETE FROM admins
+WHERE id = sqlc.arg(id);
+-- name: CreateAdminLoginToken :exec
+INSERT INTO admin_login_tokens (
+    nonce,
+    admin_id,
+    expires_at
+) VALUES (
+    sqlc.arg(nonce),
+    sqlc.arg(admin_id),
+    sqlc.arg(expires_at)
+);
+-- name: ConsumeAdminLoginToken :one
+-- Deletes the token so its link works only once, returning the admin it
+-- was issued to.
+DELETE FROM admin_login_tokens
+WHERE nonce = sqlc.arg(nonce)
+AND expires_at > sqlc.arg(now)::timestamp
+RETURNING admin_id;
+-- name: DeleteAdminLoginTokensBefore :execrows
+DELETE FROM admin_login_tokens
+WHERE expires_at < sqlc.arg(before)::timestamp;
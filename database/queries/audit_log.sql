@@ -0,0 +1,21 @@
+-- name: CreateAuditRecord :exec
+INSERT INTO audit_log (
+    actor,
+    action,
+    event_id,
+    diff
+) VALUES (
+    sqlc.arg(actor),
+    sqlc.arg(action),
+    sqlc.narg(event_id),
+    sqlc.arg(diff)
+);
+-- name: GetAuditLogPage :many
+-- An organizer_id of 0 lists every record, otherwise only those of the
+-- events that admin organizes.
+SELECT * FROM audit_log
+WHERE sqlc.arg(organizer_id)::bigint = 0 OR event_id IN (
+    SELECT event_id FROM event_organizers WHERE admin_id = sqlc.arg(organizer_id)::bigint
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size)::int OFFSET sqlc.arg(page_offset)::int;
@@ -0,0 +1,65 @@
+-- name: CreateBroadcast :one
+INSERT INTO broadcasts (
+    event_id,
+    text,
+    send_at
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(text),
+    sqlc.arg(send_at)
+) RETURNING *;
+-- name: GetBroadcastByID :one
+SELECT * FROM broadcasts
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id);
+-- name: GetBroadcastsByEventID :many
+SELECT * FROM broadcasts
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY send_at, id;
+-- name: UpdateBroadcast :execrows
+UPDATE broadcasts
+SET text = sqlc.arg(text),
+    send_at = sqlc.arg(send_at)
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id)
+AND queued_at IS NULL;
+-- name: DeleteBroadcast :execrows
+DELETE FROM broadcasts
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id)
+AND queued_at IS NULL;
+-- name: GetDueBroadcasts :many
+SELECT * FROM broadcasts
+WHERE queued_at IS NULL
+AND send_at <= sqlc.arg(now)::timestamp
+ORDER BY send_at, id;
+-- name: MarkBroadcastQueued :execrows
+-- Claims a due broadcast, so only one instance queues its messages.
+UPDATE broadcasts
+SET queued_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+AND queued_at IS NULL;
+-- name: CreateBroadcastDelivery :one
+INSERT INTO broadcast_deliveries (
+    broadcast_id,
+    user_id,
+    chat_id,
+    text
+) VALUES (
+    sqlc.arg(broadcast_id),
+    sqlc.arg(user_id),
+    sqlc.arg(chat_id),
+    sqlc.arg(text)
+) RETURNING *;
+-- name: GetBroadcastDeliveries :many
+SELECT sqlc.embed(broadcast_deliveries), users.name, users.ticket_number
+FROM broadcast_deliveries
+LEFT JOIN users ON users.id = broadcast_deliveries.user_id
+WHERE broadcast_deliveries.broadcast_id = sqlc.arg(broadcast_id)
+ORDER BY broadcast_deliveries.id;
+-- name: SetBroadcastDeliveryStatus :exec
+UPDATE broadcast_deliveries
+SET status = sqlc.arg(status),
+    error = sqlc.narg(error),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
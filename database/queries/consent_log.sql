@@ -0,0 +1,20 @@
+-- name: CreateConsentRecord :exec
+INSERT INTO consent_log (
+    event_id,
+    user_id,
+    ticket_number,
+    action,
+    source,
+    version
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(user_id),
+    sqlc.arg(ticket_number),
+    sqlc.arg(action),
+    sqlc.arg(source),
+    sqlc.arg(version)
+);
+-- name: GetConsentLogByEventID :many
+SELECT * FROM consent_log
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY created_at, id;
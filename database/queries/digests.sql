@@ -0,0 +1,106 @@
+-- name: CreateDigestSubscription :one
+INSERT INTO digest_subscriptions (
+    name,
+    chat_id,
+    weekday,
+    hour,
+    include_anomalies
+) VALUES (
+    sqlc.arg(name),
+    sqlc.arg(chat_id),
+    sqlc.arg(weekday),
+    sqlc.arg(hour),
+    sqlc.arg(include_anomalies)
+) RETURNING *;
+-- name: GetDigestSubscriptions :many
+SELECT * FROM digest_subscriptions
+ORDER BY id;
+-- name: GetDigestSubscription :one
+SELECT * FROM digest_subscriptions
+WHERE id = sqlc.arg(id);
+-- name: DeleteDigestSubscription :exec
+DELETE FROM digest_subscriptions
+WHERE id = sqlc.arg(id);
+-- name: MarkDigestSent :execrows
+-- Returns 0 if the digest was already sent after sent_before, so only one
+-- instance sends it when several are running.
+UPDATE digest_subscriptions
+SET last_sent_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+AND (last_sent_at IS NULL OR last_sent_at < sqlc.arg(sent_before)::timestamp);
+-- name: GetRegistrationsSince :many
+-- New registrations per event, for events that got any.
+SELECT events.id, events.name, COUNT(users.id)::int AS registrations
+FROM events
+JOIN users ON users.event_id = events.id
+WHERE users.created_at >= sqlc.arg(since)::timestamp
+AND users.deleted_at IS NULL
+AND events.deleted_at IS NULL
+GROUP BY events.id
+ORDER BY registrations DESC, events.id;
+-- name: GetEventsBetween :many
+SELECT * FROM events
+WHERE date >= sqlc.arg(from_date)::timestamp
+AND date < sqlc.arg(to_date)::timestamp
+AND deleted_at IS NULL
+ORDER BY date;
+-- name: GetDrawsSince :many
+SELECT draws.*, events.name AS event_name
+FROM draws
+JOIN events ON events.id = draws.event_id
+WHERE draws.created_at >= sqlc.arg(since)::timestamp
+AND events.deleted_at IS NULL
+ORDER BY draws.created_at;
+-- name: GetAnomalyCounts :one
+-- Things worth an admin's attention: registrations waiting for review,
+-- undeliverable Telegram messages and failed payments.
+SELECT
+    (SELECT COUNT(*) FROM users
+     WHERE flag_reason IS NOT NULL AND reviewed_at IS NULL AND deleted_at IS NULL)::int AS pending_review,
+    (SELECT COUNT(*) FROM outbox
+     WHERE failed_at >= sqlc.arg(since)::timestamp)::int AS failed_messages,
+    ((SELECT COUNT(*) FROM entry_purchases
+      WHERE status = 'failed' AND created_at >= sqlc.arg(since)::timestamp)
+     + (SELECT COUNT(*) FROM ticket_orders
+      WHERE status = 'failed' AND created_at >= sqlc.arg(since)::timestamp))::int AS failed_payments;
+-- name: UpdateNotificationPreferences :one
+UPDATE digest_subscriptions
+SET chat_id = sqlc.arg(chat_id),
+    weekday = sqlc.arg(weekday),
+    hour = sqlc.arg(hour),
+    weekly_digest = sqlc.arg(weekly_digest),
+    include_anomalies = sqlc.arg(include_anomalies),
+    notify_kinds = sqlc.arg(notify_kinds)::text[],
+    notify_event_ids = sqlc.arg(notify_event_ids)::bigint[]
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: GetNotificationRecipients :many
+-- Recipients of a notification kind for the event. Notifications that
+-- aren't about an event pass event_id 0 and reach every recipient of the
+-- kind.
+SELECT * FROM digest_subscriptions
+WHERE sqlc.arg(kind)::text = ANY(notify_kinds)
+AND (
+    sqlc.arg(event_id)::bigint = 0
+    OR cardinality(notify_event_ids) = 0
+    OR sqlc.arg(event_id)::bigint = ANY(notify_event_ids)
+)
+ORDER BY id;
+-- name: PruneNotifyEventIDs :execrows
+-- Drops deleted events from notification preferences. Lists left with no
+-- existing event are kept as they are, since an empty list means all
+-- events.
+UPDATE digest_subscriptions
+SET notify_event_ids = ARRAY(
+    SELECT events.id FROM events
+    WHERE events.id = ANY(digest_subscriptions.notify_event_ids)
+    ORDER BY events.id
+)
+WHERE EXISTS (
+    SELECT 1 FROM unnest(notify_event_ids) AS selected(id)
+    WHERE NOT EXISTS (SELECT 1 FROM events WHERE events.id = selected.id)
+)
+AND EXISTS (
+    SELECT 1 FROM events
+    WHERE events.id = ANY(digest_subscriptions.notify_event_ids)
+);
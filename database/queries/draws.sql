@@ -0,0 +1,65 @@
+-- name: CreateDraw :one
+INSERT INTO draws (
+    event_id,
+    winners_count,
+    seed
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(winners_count),
+    sqlc.narg(seed)
+) RETURNING *;
+-- name: ImportDraw :one
+-- Recreates a draw from an event export at its original time.
+INSERT INTO draws (
+    event_id,
+    winners_count,
+    seed,
+    created_at
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(winners_count),
+    sqlc.narg(seed),
+    sqlc.narg(created_at)
+) RETURNING *;
+-- name: CreateDrawWinner :exec
+INSERT INTO draw_winners (
+    draw_id,
+    user_id,
+    position
+) VALUES (
+    sqlc.arg(draw_id),
+    sqlc.arg(user_id),
+    sqlc.arg(position)
+);
+-- name: GetDrawsByEventID :many
+SELECT * FROM draws
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY created_at DESC;
+-- name: GetDrawWinners :many
+SELECT sqlc.embed(users), draw_winners.position FROM draw_winners
+JOIN users ON users.id = draw_winners.user_id
+WHERE draw_winners.draw_id = sqlc.arg(draw_id)
+AND users.deleted_at IS NULL
+ORDER BY draw_winners.position;
+-- name: GetPublicWinners :many
+-- Winners of the latest draw of past events shown on the wall of fame, a
+-- page of events at a time, newest first.
+WITH shown AS (
+    SELECT * FROM events
+    WHERE show_winners AND date < NOW()
+    AND deleted_at IS NULL
+    ORDER BY date DESC, id DESC
+    LIMIT sqlc.arg(page_size)::int OFFSET sqlc.arg(page_offset)::int
+)
+SELECT shown.id AS event_id, shown.name AS event_name, shown.date AS event_date,
+    users.name AS winner_name, users.ticket_number, draw_winners.position
+FROM shown
+JOIN LATERAL (
+    SELECT id FROM draws
+    WHERE draws.event_id = shown.id
+    ORDER BY created_at DESC, id DESC
+    LIMIT 1
+) latest ON TRUE
+JOIN draw_winners ON draw_winners.draw_id = latest.id
+JOIN users ON users.id = draw_winners.user_id AND users.deleted_at IS NULL
+ORDER BY shown.date DESC, shown.id DESC, draw_winners.position;
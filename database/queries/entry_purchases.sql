@@ -0,0 +1,44 @@
+-- name: CreateEntryPurchase :one
+INSERT INTO entry_purchases (
+    order_id,
+    user_id,
+    event_id,
+    entries,
+    amount
+) VALUES (
+    sqlc.arg(order_id),
+    sqlc.arg(user_id)::bigint,
+    sqlc.arg(event_id),
+    sqlc.arg(entries),
+    sqlc.arg(amount)
+) RETURNING *;
+-- name: CountReservedPaidEntries :one
+-- Pending purchases count towards the limit for a while, so a participant
+-- can't open several checkouts at once to get past it.
+SELECT COALESCE(SUM(entries), 0)::int FROM entry_purchases
+WHERE user_id = sqlc.arg(user_id)::bigint
+AND (
+    status = 'paid'
+    OR (status = 'pending' AND created_at > CURRENT_TIMESTAMP - make_interval(secs => sqlc.arg(pending_seconds)::int))
+);
+-- name: MarkEntryPurchasePaid :one
+-- Returns no rows if the purchase was already settled, so repeated
+-- provider callbacks credit the entries once.
+UPDATE entry_purchases
+SET status = 'paid',
+    paid_at = CURRENT_TIMESTAMP,
+    payment_id = sqlc.arg(payment_id)
+WHERE order_id = sqlc.arg(order_id)
+AND status = 'pending'
+RETURNING *;
+-- name: MarkEntryPurchaseFailed :exec
+UPDATE entry_purchases
+SET status = 'failed'
+WHERE order_id = sqlc.arg(order_id)
+AND status = 'pending';
+-- name: MarkEntryPurchaseRefunded :execrows
+UPDATE entry_purchases
+SET status = 'refunded',
+    refunded_at = CURRENT_TIMESTAMP
+WHERE order_id = sqlc.arg(order_id)
+AND status = 'paid';
@@ -0,0 +1,40 @@
+-- name: CreateEntryRule :one
+INSERT INTO entry_rules (
+    event_id,
+    name,
+    max_ticket,
+    registered_before,
+    bonus
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(name),
+    sqlc.narg(max_ticket),
+    sqlc.narg(registered_before),
+    sqlc.arg(bonus)
+) RETURNING *;
+-- name: GetEntryRulesByEventID :many
+SELECT * FROM entry_rules
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY id;
+-- name: DeleteEntryRule :exec
+DELETE FROM entry_rules
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id);
+-- name: RecalculateEntryBonuses :execrows
+-- Re-evaluates the event's rules for every participant, with the same
+-- conditions CreateUser applies at registration.
+UPDATE users
+SET bonus_entries = computed.bonus,
+    applied_rule_ids = computed.ids
+FROM (
+    SELECT u.id, COALESCE(rules.bonus, 0) AS bonus, COALESCE(rules.ids, '{}') AS ids
+    FROM users u, LATERAL (
+        SELECT SUM(r.bonus)::int AS bonus, array_agg(r.id ORDER BY r.id) AS ids
+        FROM entry_rules r
+        WHERE r.event_id = u.event_id
+        AND (r.max_ticket IS NULL OR u.ticket_number <= r.max_ticket)
+        AND (r.registered_before IS NULL OR u.created_at < r.registered_before)
+    ) rules
+    WHERE u.event_id = sqlc.arg(event_id)
+) AS computed
+WHERE users.id = computed.id;
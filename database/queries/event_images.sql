@@ -0,0 +1,38 @@
+-- name: SetEventImage :one
+-- Replaces the event's image and returns the event with its new
+-- image_updated_at.
+WITH image AS (
+    INSERT INTO event_images (
+        event_id,
+        content_type,
+        data
+    ) VALUES (
+        sqlc.arg(event_id),
+        sqlc.arg(content_type),
+        sqlc.arg(data)
+    )
+    ON CONFLICT (event_id) DO UPDATE
+    SET content_type = EXCLUDED.content_type,
+        data = EXCLUDED.data,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING event_images.event_id, event_images.updated_at
+)
+UPDATE events
+SET image_updated_at = (SELECT image.updated_at FROM image)
+WHERE id = (SELECT image.event_id FROM image)
+AND deleted_at IS NULL
+RETURNING *;
+-- name: GetEventImage :one
+SELECT * FROM event_images
+WHERE event_id = sqlc.arg(event_id)
+AND event_id IN (SELECT id FROM events WHERE deleted_at IS NULL);
+-- name: DeleteEventImage :one
+WITH image AS (
+    DELETE FROM event_images
+    WHERE event_images.event_id = sqlc.arg(event_id)
+)
+UPDATE events
+SET image_updated_at = NULL
+WHERE id = sqlc.arg(event_id)
+AND deleted_at IS NULL
+RETURNING *;
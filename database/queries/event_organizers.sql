@@ -0,0 +1,26 @@
+-- name: CreateEventOrganizer :one
+INSERT INTO event_organizers (
+    event_id,
+    name,
+    username,
+    responsibility,
+    admin_id
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(name),
+    sqlc.arg(username),
+    sqlc.arg(responsibility),
+    sqlc.narg(admin_id)
+) RETURNING *;
+-- name: GetEventOrganizers :many
+SELECT * FROM event_organizers
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY id;
+-- name: GetOrganizedEventIDs :many
+SELECT event_id FROM event_organizers
+WHERE admin_id = sqlc.arg(admin_id)::bigint
+ORDER BY event_id;
+-- name: DeleteEventOrganizer :exec
+DELETE FROM event_organizers
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id);
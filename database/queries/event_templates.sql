@@ -0,0 +1,54 @@
+-- name: CreateEventTemplate :one
+INSERT INTO event_templates (
+    name,
+    description,
+    waitlist_auto_promote,
+    max_paid_entries,
+    entry_price,
+    share_clicks_required,
+    share_bonus
+) VALUES (
+    sqlc.arg(name),
+    sqlc.arg(description),
+    sqlc.arg(waitlist_auto_promote),
+    sqlc.arg(max_paid_entries),
+    sqlc.arg(entry_price),
+    sqlc.arg(share_clicks_required),
+    sqlc.arg(share_bonus)
+) RETURNING *;
+-- name: CreateEventTemplateRule :exec
+INSERT INTO event_template_rules (
+    template_id,
+    name,
+    max_ticket,
+    seconds_before_event,
+    bonus
+) VALUES (
+    sqlc.arg(template_id),
+    sqlc.arg(name),
+    sqlc.narg(max_ticket),
+    sqlc.narg(seconds_before_event),
+    sqlc.arg(bonus)
+);
+-- name: GetEventTemplates :many
+SELECT sqlc.embed(event_templates), COUNT(event_template_rules.id)::int AS rules
+FROM event_templates
+LEFT JOIN event_template_rules ON event_template_rules.template_id = event_templates.id
+GROUP BY event_templates.id
+ORDER BY event_templates.name;
+-- name: GetEventTemplate :one
+SELECT * FROM event_templates
+WHERE id = sqlc.arg(id);
+-- name: GetEventTemplateRules :many
+SELECT * FROM event_template_rules
+WHERE template_id = sqlc.arg(template_id)
+ORDER BY id;
+-- name: UpdateEventTemplate :one
+UPDATE event_templates
+SET name = sqlc.arg(name),
+    description = sqlc.arg(description)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: DeleteEventTemplate :exec
+DELETE FROM event_templates
+WHERE id = sqlc.arg(id);
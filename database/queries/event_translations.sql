@@ -0,0 +1,33 @@
+-- name: UpsertEventTranslation :one
+INSERT INTO event_translations (
+    event_id,
+    language,
+    name,
+    description
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(language),
+    sqlc.arg(name),
+    sqlc.arg(description)
+)
+ON CONFLICT (event_id, language) DO UPDATE
+SET name = EXCLUDED.name,
+    description = EXCLUDED.description,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+-- name: GetEventTranslations :many
+SELECT * FROM event_translations
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY language;
+-- name: GetEventTranslation :one
+SELECT * FROM event_translations
+WHERE event_id = sqlc.arg(event_id)
+AND language = sqlc.arg(language);
+-- name: GetTranslationsByLanguage :many
+SELECT * FROM event_translations
+WHERE language = sqlc.arg(language)
+ORDER BY event_id;
+-- name: DeleteEventTranslation :exec
+DELETE FROM event_translations
+WHERE event_id = sqlc.arg(event_id)
+AND language = sqlc.arg(language);
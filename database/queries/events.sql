@@ -13,21 +13,213 @@ RETURNING *;
 UPDATE events
 SET name = sqlc.arg(name),
     description = sqlc.arg(description),
-    date = COALESCE(sqlc.arg(date), date)
+    date = COALESCE(sqlc.arg(date), date),
+    version = version + 1
 WHERE id = sqlc.arg(id)
+AND version = sqlc.arg(version)
 RETURNING *;
 -- name: GetEvents :many    
-SELECT * FROM events ORDER BY created_at DESC;
+SELECT * FROM events
+WHERE deleted_at IS NULL
+ORDER BY created_at DESC;
+-- name: GetEventsPage :many
+-- A page of the dashboard, which lists either the active events or the
+-- archive. An empty category lists every event, and an organizer_id of 0
+-- the events of every organizer.
+SELECT * FROM events
+WHERE (archived_at IS NOT NULL) = sqlc.arg(archived)::boolean
+AND deleted_at IS NULL
+AND (sqlc.arg(category)::text = '' OR sqlc.arg(category)::text = ANY(categories))
+AND (sqlc.arg(organizer_id)::bigint = 0 OR id IN (
+    SELECT event_id FROM event_organizers WHERE admin_id = sqlc.arg(organizer_id)::bigint
+))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size)::int OFFSET sqlc.arg(page_offset)::int;
 -- name: DeleteEvent :exec
-DELETE FROM events
-WHERE id = sqlc.arg(id);
+-- Moves the event to the trash with its participants and draws.
+UPDATE events
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+AND deleted_at IS NULL;
 -- name: GetEventByID :one  
 SELECT * FROM events
-WHERE id = sqlc.arg(id);
+WHERE events.id = sqlc.arg(id)
+AND deleted_at IS NULL;
 -- name: GetLastEvent :one
 SELECT * FROM events
 WHERE id = (
     SELECT id FROM events
+    WHERE deleted_at IS NULL
     ORDER BY created_at DESC
     LIMIT 1
 );
+-- name: SetEventWaitlistAutoPromote :one
+UPDATE events
+SET waitlist_auto_promote = sqlc.arg(waitlist_auto_promote)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: SetEventKioskToken :one
+UPDATE events
+SET kiosk_token = sqlc.narg(kiosk_token)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: SetEventPaidEntries :one
+UPDATE events
+SET max_paid_entries = sqlc.arg(max_paid_entries),
+    entry_price = sqlc.arg(entry_price)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: SetEventTicketPrice :one
+UPDATE events
+SET ticket_price = sqlc.arg(ticket_price)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: SetEventShareBonus :one
+UPDATE events
+SET share_clicks_required = sqlc.arg(share_clicks_required),
+    share_bonus = sqlc.arg(share_bonus)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: SetEventShowWinners :one
+UPDATE events
+SET show_winners = sqlc.arg(show_winners)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: SyncLastTicketNumber :exec
+-- Continues ticket numbering after the highest ticket in the event.
+UPDATE events
+SET last_ticket_number = GREATEST(last_ticket_number, (
+    SELECT COALESCE(MAX(ticket_number), 0) FROM users WHERE users.event_id = events.id
+))
+WHERE events.id = sqlc.arg(id);
+-- name: ArchiveEventsBefore :execrows
+-- Archives events that took place before the cutoff, except those an admin
+-- took out of the archive.
+UPDATE events
+SET archived_at = CURRENT_TIMESTAMP
+WHERE archived_at IS NULL
+AND unarchived_at IS NULL
+AND date < sqlc.arg(cutoff);
+-- name: ArchiveEvent :one
+UPDATE events
+SET archived_at = COALESCE(archived_at, CURRENT_TIMESTAMP)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: UnarchiveEvent :one
+UPDATE events
+SET archived_at = NULL,
+    unarchived_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: GetEventsToSyncToCalendar :many
+-- Lists events changed since they were last pushed to the calendar.
+SELECT * FROM events
+WHERE calendar_synced_version <> version
+AND archived_at IS NULL
+AND deleted_at IS NULL
+ORDER BY id;
+-- name: LockEventForCalendarSync :one
+-- Returns the event if it still needs syncing and no other instance is
+-- syncing it, holding it until the transaction ends.
+SELECT * FROM events
+WHERE id = sqlc.arg(id)
+AND calendar_synced_version <> version
+AND deleted_at IS NULL
+FOR UPDATE SKIP LOCKED;
+-- name: SetEventCalendarSynced :exec
+UPDATE events
+SET calendar_event_id = sqlc.arg(calendar_event_id),
+    calendar_synced_version = sqlc.arg(calendar_synced_version)
+WHERE id = sqlc.arg(id);
+-- name: SetEventSeatAssignment :one
+UPDATE events
+SET seat_assignment = sqlc.arg(seat_assignment)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: GetDeletedEvents :many
+-- The trash, most recently deleted first.
+SELECT * FROM events
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC;
+-- name: RestoreEvent :one
+-- Takes the event out of the trash. It was removed from the calendar on
+-- deletion, so it's pushed there again as a new calendar event.
+UPDATE events
+SET deleted_at = NULL,
+    calendar_event_id = NULL,
+    version = version + 1
+WHERE id = sqlc.arg(id)
+AND deleted_at IS NOT NULL
+RETURNING *;
+-- name: PurgeDeletedEventsBefore :execrows
+-- Removes for good the events deleted before the cutoff.
+DELETE FROM events
+WHERE deleted_at < sqlc.arg(cutoff)::timestamp;
+-- name: SetEventCategories :one
+UPDATE events
+SET categories = sqlc.arg(categories)::text[]
+WHERE id = sqlc.arg(id)
+AND deleted_at IS NULL
+RETURNING *;
+-- name: SetEventCapacity :one
+UPDATE events
+SET capacity = sqlc.arg(capacity)
+WHERE id = sqlc.arg(id)
+AND deleted_at IS NULL
+RETURNING *;
+-- name: GetEventForUpdate :one
+-- Locks the event while a participant is registered, so concurrent
+-- registrations can't go over its capacity.
+SELECT * FROM events
+WHERE id = sqlc.arg(id)
+AND deleted_at IS NULL
+FOR UPDATE;
+-- name: CountEventTaken :one
+-- Registered participants of the event and its pending ticket orders,
+-- which hold their place for a while like those of ticket types.
+SELECT (
+    (SELECT COUNT(*) FROM users
+     WHERE users.event_id = sqlc.arg(id)::bigint
+     AND users.deleted_at IS NULL)
+    + (SELECT COUNT(*) FROM ticket_orders
+       WHERE ticket_orders.event_id = sqlc.arg(id)::bigint
+       AND status = 'pending'
+       AND created_at > CURRENT_TIMESTAMP - make_interval(secs => sqlc.arg(pending_seconds)::int))
+)::int;
+-- name: GetEventCategories :many
+-- The categories used by the active events or the archive, for filtering
+-- the dashboard.
+SELECT DISTINCT category::text FROM events, unnest(events.categories) AS category
+WHERE (events.archived_at IS NOT NULL) = sqlc.arg(archived)::boolean
+AND events.deleted_at IS NULL
+ORDER BY category;
+-- name: SetEventStatus :one
+UPDATE events
+SET status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+AND deleted_at IS NULL
+RETURNING *;
+-- name: SetEventRegistrationWindow :one
+UPDATE events
+SET registration_opens_at = sqlc.narg(registration_opens_at),
+    registration_closes_at = sqlc.narg(registration_closes_at)
+WHERE id = sqlc.arg(id)
+AND deleted_at IS NULL
+RETURNING *;
+-- name: GetEventBySlug :one
+SELECT * FROM events
+WHERE slug = sqlc.arg(slug)::text
+AND deleted_at IS NULL;
+-- name: EventSlugTaken :one
+-- Reports whether another event, deleted ones included, has the slug.
+SELECT EXISTS (
+    SELECT 1 FROM events
+    WHERE slug = sqlc.arg(slug)::text
+    AND id <> sqlc.arg(id)
+)::boolean;
+-- name: SetEventSlug :one
+UPDATE events
+SET slug = sqlc.arg(slug)::text
+WHERE id = sqlc.arg(id)
+AND deleted_at IS NULL
+RETURNING *;
@@ -0,0 +1,15 @@
+-- name: GetFeatureFlags :many
+SELECT * FROM feature_flags
+ORDER BY name;
+-- name: SetFeatureFlag :one
+INSERT INTO feature_flags (
+    name,
+    enabled
+) VALUES (
+    sqlc.arg(name),
+    sqlc.arg(enabled)
+)
+ON CONFLICT (name) DO UPDATE
+SET enabled = EXCLUDED.enabled,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
@@ -0,0 +1,61 @@
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE scope = sqlc.arg(scope)
+AND key = sqlc.arg(key)
+AND created_at > sqlc.arg(since);
+-- name: ClaimIdempotencyKey :execrows
+-- Marks the key as in flight with status code 0, unless a request holds it
+-- or its response is still kept. Claims from before claimed_before were
+-- left by requests that never finished and are taken over.
+INSERT INTO idempotency_keys (
+    key,
+    scope,
+    request_hash,
+    status_code,
+    content_type,
+    body
+) VALUES (
+    sqlc.arg(key),
+    sqlc.arg(scope),
+    sqlc.arg(request_hash),
+    0,
+    '',
+    ''
+) ON CONFLICT (scope, key) DO UPDATE SET
+    request_hash = EXCLUDED.request_hash,
+    status_code = 0,
+    content_type = '',
+    body = '',
+    created_at = CURRENT_TIMESTAMP
+WHERE idempotency_keys.created_at <= sqlc.arg(stored_before)
+OR (idempotency_keys.status_code = 0 AND idempotency_keys.created_at <= sqlc.arg(claimed_before));
+-- name: ReleaseIdempotencyKey :exec
+-- Drops an in-flight claim whose request failed, so it can be retried.
+DELETE FROM idempotency_keys
+WHERE scope = sqlc.arg(scope)
+AND key = sqlc.arg(key)
+AND status_code = 0;
+-- name: SaveIdempotencyKey :exec
+INSERT INTO idempotency_keys (
+    key,
+    scope,
+    request_hash,
+    status_code,
+    content_type,
+    body
+) VALUES (
+    sqlc.arg(key),
+    sqlc.arg(scope),
+    sqlc.arg(request_hash),
+    sqlc.arg(status_code),
+    sqlc.arg(content_type),
+    sqlc.arg(body)
+) ON CONFLICT (scope, key) DO UPDATE SET
+    request_hash = EXCLUDED.request_hash,
+    status_code = EXCLUDED.status_code,
+    content_type = EXCLUDED.content_type,
+    body = EXCLUDED.body,
+    created_at = CURRENT_TIMESTAMP;
+-- name: DeleteIdempotencyKeysBefore :execrows
+DELETE FROM idempotency_keys
+WHERE created_at <= sqlc.arg(before);
@@ -0,0 +1,44 @@
+-- name: EnqueueOutboxMessage :one
+INSERT INTO outbox (
+    chat_id,
+    text,
+    markdown,
+    delivery_id,
+    sms_user_id
+) VALUES (
+    sqlc.arg(chat_id),
+    sqlc.arg(text),
+    sqlc.arg(markdown),
+    sqlc.narg(delivery_id),
+    sqlc.narg(sms_user_id)
+) RETURNING *;
+-- name: ClaimOutboxMessages :many
+UPDATE outbox
+SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE id IN (
+    SELECT id FROM outbox
+    WHERE sent_at IS NULL
+    AND failed_at IS NULL
+    AND next_attempt_at <= CURRENT_TIMESTAMP
+    ORDER BY id
+    LIMIT sqlc.arg(batch_size)::int
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+-- name: MarkOutboxMessageSent :exec
+UPDATE outbox
+SET sent_at = CURRENT_TIMESTAMP,
+    attempts = attempts + 1
+WHERE id = sqlc.arg(id);
+-- name: MarkOutboxMessageFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg(retry_after_seconds)::int),
+    failed_at = CASE WHEN sqlc.arg(give_up)::boolean THEN CURRENT_TIMESTAMP END
+WHERE id = sqlc.arg(id);
+-- name: DeleteOutboxBefore :execrows
+-- Removes messages that were delivered or given up on before the cutoff.
+DELETE FROM outbox
+WHERE sent_at < sqlc.arg(before)::timestamp
+OR failed_at < sqlc.arg(before)::timestamp;
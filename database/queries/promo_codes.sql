@@ -0,0 +1,84 @@
+-- name: CreatePromoCode :one
+INSERT INTO promo_codes (
+    event_id,
+    code,
+    kind,
+    value,
+    max_uses,
+    expires_at
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(code),
+    sqlc.arg(kind),
+    sqlc.arg(value),
+    sqlc.arg(max_uses),
+    sqlc.narg(expires_at)
+) RETURNING *;
+-- name: DeletePromoCode :exec
+DELETE FROM promo_codes
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id);
+-- name: GetPromoCode :one
+SELECT * FROM promo_codes
+WHERE event_id = sqlc.arg(event_id)
+AND code = sqlc.arg(code);
+-- name: UsePromoCode :one
+-- Takes one use of the code. Returns no rows if the code doesn't exist, has
+-- expired or is used up, so concurrent uses can't go over max_uses.
+UPDATE promo_codes
+SET uses = uses + 1
+WHERE event_id = sqlc.arg(event_id)
+AND code = sqlc.arg(code)
+AND (max_uses = 0 OR uses < max_uses)
+AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+RETURNING *;
+-- name: CreatePromoRedemption :exec
+INSERT INTO promo_redemptions (
+    promo_code_id,
+    user_id,
+    order_id,
+    discount,
+    entries
+) VALUES (
+    sqlc.arg(promo_code_id),
+    sqlc.narg(user_id),
+    sqlc.narg(order_id),
+    sqlc.arg(discount),
+    sqlc.arg(entries)
+);
+-- name: SetPromoRedemptionUser :one
+-- Links the use of a paid ticket order to the buyer once they are
+-- registered. Returns no rows if the order had no code.
+UPDATE promo_redemptions
+SET user_id = sqlc.arg(user_id)::bigint
+WHERE order_id = sqlc.arg(order_id)::text
+RETURNING *;
+-- name: ReleasePromoRedemption :exec
+-- Gives back the use taken by a ticket order whose payment failed.
+WITH released AS (
+    DELETE FROM promo_redemptions
+    USING ticket_orders
+    WHERE promo_redemptions.order_id = ticket_orders.order_id
+    AND ticket_orders.order_id = sqlc.arg(order_id)::text
+    AND ticket_orders.status = 'failed'
+    RETURNING promo_redemptions.promo_code_id
+)
+UPDATE promo_codes
+SET uses = uses - 1
+WHERE id IN (SELECT promo_code_id FROM released);
+-- name: GetPromoCodeStats :many
+-- The event's codes with what their uses brought: participants still
+-- registered, the discount given, bonus entries granted and the revenue of
+-- paid tickets.
+SELECT
+    sqlc.embed(promo_codes),
+    COUNT(promo_redemptions.user_id)::int AS participants,
+    COALESCE(SUM(promo_redemptions.discount), 0)::int AS discount,
+    COALESCE(SUM(promo_redemptions.entries), 0)::int AS entries,
+    COALESCE(SUM(ticket_orders.amount) FILTER (WHERE ticket_orders.status = 'paid'), 0)::int AS revenue
+FROM promo_codes
+LEFT JOIN promo_redemptions ON promo_redemptions.promo_code_id = promo_codes.id
+LEFT JOIN ticket_orders ON ticket_orders.order_id = promo_redemptions.order_id
+WHERE promo_codes.event_id = sqlc.arg(event_id)
+GROUP BY promo_codes.id
+ORDER BY promo_codes.created_at DESC, promo_codes.id DESC;
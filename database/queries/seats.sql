@@ -0,0 +1,55 @@
+-- name: CreateSeats :execrows
+-- Seats keep the order of labels; labels the event already has are
+-- skipped.
+INSERT INTO seats (event_id, label)
+SELECT sqlc.arg(event_id)::bigint, label
+FROM unnest(sqlc.arg(labels)::text[]) WITH ORDINALITY AS t(label, i)
+ORDER BY i
+ON CONFLICT (event_id, label) DO NOTHING;
+-- name: DeleteSeat :exec
+DELETE FROM seats
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id);
+-- name: GetSeatsByEventID :many
+-- The event's seats with the participants sitting in them.
+SELECT
+    sqlc.embed(seats),
+    COALESCE(users.name, '')::text AS user_name,
+    COALESCE(users.ticket_number, 0)::int AS ticket_number
+FROM seats
+LEFT JOIN users ON users.id = seats.user_id
+WHERE seats.event_id = sqlc.arg(event_id)
+ORDER BY seats.id;
+-- name: GetSeatByUserID :one
+SELECT * FROM seats
+WHERE user_id = sqlc.arg(user_id)::bigint;
+-- name: GetSeatByLabelForUpdate :one
+-- Locks the seat while a participant is moved into it.
+SELECT * FROM seats
+WHERE event_id = sqlc.arg(event_id)
+AND label = sqlc.arg(label)
+FOR UPDATE;
+-- name: AssignFreeSeat :one
+-- Gives the participant the event's first free seat, unless they already
+-- have one. Seats taken by concurrent registrations are skipped rather
+-- than waited for.
+UPDATE seats
+SET user_id = sqlc.arg(user_id)::bigint
+WHERE id = (
+    SELECT free.id FROM seats free
+    WHERE free.event_id = sqlc.arg(event_id)::bigint
+    AND free.user_id IS NULL
+    AND NOT EXISTS (SELECT 1 FROM seats taken WHERE taken.user_id = sqlc.arg(user_id)::bigint)
+    ORDER BY free.id
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+-- name: SetSeatUser :exec
+UPDATE seats
+SET user_id = sqlc.narg(user_id)
+WHERE id = sqlc.arg(id);
+-- name: ReleaseSeat :exec
+UPDATE seats
+SET user_id = NULL
+WHERE user_id = sqlc.arg(user_id)::bigint;
@@ -0,0 +1,50 @@
+-- name: SaveSession :exec
+INSERT INTO sessions (
+    token_hash,
+    name,
+    admin_id,
+    data,
+    user_agent,
+    ip,
+    expires_at
+) VALUES (
+    sqlc.arg(token_hash),
+    sqlc.arg(name),
+    sqlc.narg(admin_id),
+    sqlc.arg(data),
+    sqlc.arg(user_agent),
+    sqlc.arg(ip),
+    sqlc.arg(expires_at)
+) ON CONFLICT (token_hash) DO UPDATE SET
+    admin_id = EXCLUDED.admin_id,
+    data = EXCLUDED.data,
+    user_agent = EXCLUDED.user_agent,
+    ip = EXCLUDED.ip,
+    updated_at = CURRENT_TIMESTAMP,
+    expires_at = EXCLUDED.expires_at;
+-- name: GetSession :one
+SELECT * FROM sessions
+WHERE token_hash = sqlc.arg(token_hash)
+AND expires_at > sqlc.arg(now)::timestamp;
+-- name: GetAdminSessions :many
+-- Lists the unexpired sessions admins are signed in with.
+SELECT sessions.id, sessions.token_hash, sessions.admin_id, admins.username,
+    sessions.user_agent, sessions.ip, sessions.created_at, sessions.updated_at, sessions.expires_at
+FROM sessions
+JOIN admins ON admins.id = sessions.admin_id
+WHERE sessions.expires_at > sqlc.arg(now)::timestamp
+ORDER BY admins.username, sessions.updated_at DESC, sessions.id DESC;
+-- name: DeleteSession :exec
+DELETE FROM sessions
+WHERE token_hash = sqlc.arg(token_hash);
+-- name: DeleteSessionByID :execrows
+DELETE FROM sessions
+WHERE id = sqlc.arg(id);
+-- name: DeleteOtherAdminSessions :execrows
+-- Signs the admin out everywhere but the session with token_hash.
+DELETE FROM sessions
+WHERE admin_id = sqlc.arg(admin_id)
+AND token_hash <> sqlc.arg(token_hash);
+-- name: DeleteSessionsBefore :execrows
+DELETE FROM sessions
+WHERE expires_at < sqlc.arg(before)::timestamp;
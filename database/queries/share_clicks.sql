@@ -0,0 +1,26 @@
+-- name: RecordShareClick :execrows
+-- Returns 0 if the visitor already opened this participant's link.
+INSERT INTO share_clicks (
+    user_id,
+    event_id,
+    visitor_hash
+) VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(event_id),
+    sqlc.arg(visitor_hash)
+) ON CONFLICT DO NOTHING;
+-- name: CountShareClicks :one
+SELECT COUNT(*) FROM share_clicks
+WHERE user_id = sqlc.arg(user_id);
+-- name: CountShareClicksByVisitor :one
+SELECT COUNT(*) FROM share_clicks
+WHERE event_id = sqlc.arg(event_id)
+AND visitor_hash = sqlc.arg(visitor_hash);
+-- name: GetShareReport :many
+SELECT sqlc.embed(users), COUNT(share_clicks.user_id)::int AS clicks
+FROM users
+JOIN share_clicks ON share_clicks.user_id = users.id
+WHERE users.event_id = sqlc.arg(event_id)
+AND users.deleted_at IS NULL
+GROUP BY users.id
+ORDER BY clicks DESC, users.id;
@@ -0,0 +1,28 @@
+-- name: CreateShortLink :one
+INSERT INTO short_links (
+    event_id,
+    code,
+    channel,
+    target
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(code),
+    sqlc.arg(channel),
+    sqlc.arg(target)
+) RETURNING *;
+-- name: DeleteShortLink :exec
+DELETE FROM short_links
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id);
+-- name: ClickShortLink :exec
+-- Counts a visit through the link.
+UPDATE short_links
+SET clicks = clicks + 1
+WHERE id = sqlc.arg(id);
+-- name: GetShortLinkByCode :one
+SELECT * FROM short_links
+WHERE code = sqlc.arg(code);
+-- name: GetShortLinksByEventID :many
+SELECT * FROM short_links
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY created_at DESC, id DESC;
@@ -0,0 +1,84 @@
+-- name: CreateTicketOrder :one
+INSERT INTO ticket_orders (
+    order_id,
+    event_id,
+    name,
+    username,
+    amount,
+    ticket_type_id
+) VALUES (
+    sqlc.arg(order_id),
+    sqlc.arg(event_id),
+    sqlc.arg(name),
+    sqlc.arg(username),
+    sqlc.arg(amount),
+    sqlc.narg(ticket_type_id)
+) RETURNING *;
+-- name: GetTicketOrder :one
+SELECT * FROM ticket_orders
+WHERE order_id = sqlc.arg(order_id);
+-- name: MarkTicketOrderPaid :one
+-- Returns no rows if the order was already settled, so repeated provider
+-- callbacks register the participant once.
+UPDATE ticket_orders
+SET status = 'paid',
+    paid_at = CURRENT_TIMESTAMP,
+    payment_id = sqlc.arg(payment_id)
+WHERE order_id = sqlc.arg(order_id)
+AND status = 'pending'
+RETURNING *;
+-- name: MarkTicketOrderFailed :exec
+UPDATE ticket_orders
+SET status = 'failed'
+WHERE order_id = sqlc.arg(order_id)
+AND status = 'pending';
+-- name: MarkTicketOrderRefunded :execrows
+-- Affects no rows if the order isn't paid, so a payment is recorded as
+-- refunded once.
+UPDATE ticket_orders
+SET status = 'refunded',
+    refunded_at = CURRENT_TIMESTAMP
+WHERE order_id = sqlc.arg(order_id)
+AND status = 'paid';
+-- name: SetTicketOrderUser :exec
+UPDATE ticket_orders
+SET user_id = sqlc.arg(user_id)::bigint
+WHERE id = sqlc.arg(id);
+-- name: GetPaymentsByEventID :many
+-- Ticket orders and entry purchases of an event, newest first. Ticket
+-- orders are named after what the buyer entered, since they may not have
+-- a participant yet.
+SELECT
+    'ticket'::text AS kind,
+    ticket_orders.order_id,
+    ticket_orders.payment_id,
+    ticket_orders.user_id,
+    ticket_orders.name,
+    users.ticket_number,
+    0::int AS entries,
+    ticket_orders.amount,
+    ticket_orders.status,
+    ticket_orders.created_at,
+    ticket_orders.paid_at,
+    ticket_orders.refunded_at
+FROM ticket_orders
+LEFT JOIN users ON users.id = ticket_orders.user_id
+WHERE ticket_orders.event_id = sqlc.arg(event_id)
+UNION ALL
+SELECT
+    'entries'::text AS kind,
+    entry_purchases.order_id,
+    entry_purchases.payment_id,
+    entry_purchases.user_id,
+    COALESCE(users.name, '')::text AS name,
+    users.ticket_number,
+    entry_purchases.entries,
+    entry_purchases.amount,
+    entry_purchases.status,
+    entry_purchases.created_at,
+    entry_purchases.paid_at,
+    entry_purchases.refunded_at
+FROM entry_purchases
+LEFT JOIN users ON users.id = entry_purchases.user_id
+WHERE entry_purchases.event_id = sqlc.arg(event_id)
+ORDER BY created_at DESC;
@@ -0,0 +1,57 @@
+-- name: CreateTicketType :one
+INSERT INTO ticket_types (
+    event_id,
+    name,
+    price,
+    capacity,
+    weight
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(name),
+    sqlc.arg(price),
+    sqlc.arg(capacity),
+    sqlc.arg(weight)
+) RETURNING *;
+-- name: DeleteTicketType :exec
+DELETE FROM ticket_types
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id);
+-- name: GetTicketType :one
+SELECT * FROM ticket_types
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id);
+-- name: GetTicketTypeForUpdate :one
+-- Locks the type while a ticket of it is taken, so concurrent
+-- registrations can't go over its capacity.
+SELECT * FROM ticket_types
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id)
+FOR UPDATE;
+-- name: GetTicketTypesByEventID :many
+SELECT * FROM ticket_types
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY price, id;
+-- name: CountTicketTypeTaken :one
+-- Registered participants of the type and its pending orders, which hold
+-- their place for a while so it isn't sold twice.
+SELECT (
+    (SELECT COUNT(*) FROM users
+     WHERE users.ticket_type_id = sqlc.arg(id)::bigint
+     AND users.deleted_at IS NULL)
+    + (SELECT COUNT(*) FROM ticket_orders
+       WHERE ticket_orders.ticket_type_id = sqlc.arg(id)::bigint
+       AND status = 'pending'
+       AND created_at > CURRENT_TIMESTAMP - make_interval(secs => sqlc.arg(pending_seconds)::int))
+)::int;
+-- name: GetTicketTypeStats :many
+-- The event's types with how many participants have them and how many of
+-- those are checked in.
+SELECT
+    sqlc.embed(ticket_types),
+    COUNT(users.id)::int AS participants,
+    COUNT(users.checked_in_at)::int AS checked_in
+FROM ticket_types
+LEFT JOIN users ON users.ticket_type_id = ticket_types.id AND users.deleted_at IS NULL
+WHERE ticket_types.event_id = sqlc.arg(event_id)
+GROUP BY ticket_types.id
+ORDER BY ticket_types.price, ticket_types.id;
@@ -0,0 +1,28 @@
+-- name: CreateToken :one
+INSERT INTO tokens (
+    admin_id,
+    name,
+    token_hash,
+    scopes
+) VALUES (
+    sqlc.arg(admin_id),
+    sqlc.arg(name),
+    sqlc.arg(token_hash),
+    sqlc.arg(scopes)
+) RETURNING *;
+-- name: GetTokenByHash :one
+SELECT * FROM tokens
+WHERE token_hash = sqlc.arg(token_hash);
+-- name: GetTokensByAdminID :many
+SELECT * FROM tokens
+WHERE admin_id = sqlc.arg(admin_id)
+ORDER BY created_at DESC, id DESC;
+-- name: TouchToken :exec
+UPDATE tokens
+SET last_used_at = sqlc.arg(last_used_at)
+WHERE id = sqlc.arg(id);
+-- name: DeleteToken :execrows
+-- Only the admin who minted a token may revoke it.
+DELETE FROM tokens
+WHERE id = sqlc.arg(id)
+AND admin_id = sqlc.arg(admin_id);
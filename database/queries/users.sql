@@ -1,32 +1,381 @@
 -- name: CreateUser :one
+WITH ticket AS (
+    UPDATE events
+    SET last_ticket_number = last_ticket_number + 1
+    WHERE id = sqlc.arg(event_id)::bigint
+    RETURNING last_ticket_number
+)
 INSERT INTO users (
     name, 
     username,
     tg_id,
-    event_id
-) VALUES (
-    sqlc.arg(name),
-    sqlc.arg(username),
-    sqlc.arg(tg_id),
-    sqlc.arg(event_id)
-) RETURNING *;
+    event_id,
+    ticket_number,
+    check_in_code,
+    bonus_entries,
+    applied_rule_ids
+)
+SELECT
+    sqlc.arg(name)::text,
+    sqlc.arg(username)::text,
+    sqlc.narg(tg_id)::bigint,
+    sqlc.arg(event_id)::bigint,
+    ticket.last_ticket_number,
+    sqlc.arg(check_in_code)::text,
+    COALESCE(rules.bonus, 0),
+    COALESCE(rules.ids, '{}')
+FROM ticket, LATERAL (
+    SELECT SUM(r.bonus)::int AS bonus, array_agg(r.id ORDER BY r.id) AS ids
+    FROM entry_rules r
+    WHERE r.event_id = sqlc.arg(event_id)::bigint
+    AND (r.max_ticket IS NULL OR ticket.last_ticket_number <= r.max_ticket)
+    AND (r.registered_before IS NULL OR CURRENT_TIMESTAMP < r.registered_before)
+) rules
+RETURNING *;
 -- name: DeleteUser :exec
 DELETE FROM users
 WHERE id = sqlc.arg(id);
 -- name: GetUserByID :one
 SELECT * FROM users
-WHERE id = sqlc.arg(id);
+WHERE id = sqlc.arg(id)
+AND deleted_at IS NULL;
 -- name: GetUserByUsername :one
 SELECT * FROM users
-WHERE username = sqlc.arg(username);
+WHERE username = sqlc.arg(username)
+AND deleted_at IS NULL;
 -- name: GetUsersByEventID :many
 SELECT * FROM users
-WHERE event_id = sqlc.arg(event_id);
+WHERE event_id = sqlc.arg(event_id)
+AND deleted_at IS NULL
+ORDER BY id;
 -- name: DeleteUsersByIdAndEventId :exec
-DELETE FROM users
-WHERE id = sqlc.arg(id) AND event_id = sqlc.arg(event_id);
+-- Moves the participant to the trash, freeing their seat for someone else.
+WITH deleted AS (
+    UPDATE users
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE users.id = sqlc.arg(id)
+    AND users.event_id = sqlc.arg(event_id)
+    AND users.deleted_at IS NULL
+    RETURNING users.id
+)
+UPDATE seats
+SET user_id = NULL
+WHERE user_id IN (SELECT deleted.id FROM deleted);
 -- name: UpdateUserN :exec
 UPDATE users
 SET n = sqlc.arg(n)
 WHERE id = sqlc.arg(id)
 AND event_id = sqlc.arg(event_id);
+-- name: CountUsersByEventID :one
+SELECT COUNT(*) FROM users
+WHERE event_id = sqlc.arg(event_id)
+AND deleted_at IS NULL;
+-- name: CreateUsersBatch :execrows
+-- tg_ids uses 0 for participants without a Telegram account, since array
+-- elements can't be passed as NULL. Rows skipped as duplicates leave gaps
+-- in the ticket numbers.
+WITH ticket AS (
+    UPDATE events
+    SET last_ticket_number = last_ticket_number + cardinality(sqlc.arg(names)::text[])
+    WHERE id = sqlc.arg(event_id)::bigint
+    RETURNING last_ticket_number - cardinality(sqlc.arg(names)::text[]) AS base
+)
+INSERT INTO users (
+    name,
+    username,
+    tg_id,
+    event_id,
+    ticket_number,
+    check_in_code,
+    bonus_entries,
+    applied_rule_ids
+)
+SELECT
+    (sqlc.arg(names)::text[])[i],
+    (sqlc.arg(usernames)::text[])[i],
+    NULLIF((sqlc.arg(tg_ids)::bigint[])[i], 0),
+    sqlc.arg(event_id)::bigint,
+    ticket.base + i,
+    (sqlc.arg(check_in_codes)::text[])[i],
+    COALESCE(rules.bonus, 0),
+    COALESCE(rules.ids, '{}')
+FROM ticket, generate_series(1, cardinality(sqlc.arg(names)::text[])) AS i, LATERAL (
+    SELECT SUM(r.bonus)::int AS bonus, array_agg(r.id ORDER BY r.id) AS ids
+    FROM entry_rules r
+    WHERE r.event_id = sqlc.arg(event_id)::bigint
+    AND (r.max_ticket IS NULL OR ticket.base + i <= r.max_ticket)
+    AND (r.registered_before IS NULL OR CURRENT_TIMESTAMP < r.registered_before)
+) rules
+ON CONFLICT (tg_id, event_id) WHERE deleted_at IS NULL DO NOTHING;
+-- name: GetUsersByEventIDAfter :many
+-- Keyset pagination over an event's participants: pass the last ID of the
+-- previous page (0 for the first page).
+SELECT * FROM users
+WHERE event_id = sqlc.arg(event_id)
+AND deleted_at IS NULL
+AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_size)::int;
+-- name: GetUsersPage :many
+-- A page of the admin participants table. An empty tag lists everyone; a
+-- query keeps the participants whose name or username contains it, with %
+-- and _ escaped so they match themselves. sort is
+-- name, username, n or registered; ties and other values keep the order of
+-- registration.
+SELECT * FROM users
+WHERE event_id = sqlc.arg(event_id)
+AND deleted_at IS NULL
+AND (sqlc.arg(tag)::text = '' OR sqlc.arg(tag)::text = ANY(tags))
+AND (
+    sqlc.arg(query)::text = ''
+    OR name ILIKE '%' || regexp_replace(sqlc.arg(query)::text, '([\\%_])', '\\\1', 'g') || '%'
+    OR username ILIKE '%' || regexp_replace(sqlc.arg(query)::text, '([\\%_])', '\\\1', 'g') || '%'
+)
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'name' AND NOT sqlc.arg(descending)::boolean THEN lower(name) END,
+    CASE WHEN sqlc.arg(sort)::text = 'name' AND sqlc.arg(descending)::boolean THEN lower(name) END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'username' AND NOT sqlc.arg(descending)::boolean THEN lower(username) END,
+    CASE WHEN sqlc.arg(sort)::text = 'username' AND sqlc.arg(descending)::boolean THEN lower(username) END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'n' AND NOT sqlc.arg(descending)::boolean THEN n END,
+    CASE WHEN sqlc.arg(sort)::text = 'n' AND sqlc.arg(descending)::boolean THEN n END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'registered' AND NOT sqlc.arg(descending)::boolean THEN created_at END,
+    CASE WHEN sqlc.arg(sort)::text = 'registered' AND sqlc.arg(descending)::boolean THEN created_at END DESC,
+    id
+LIMIT sqlc.arg(page_size)::int OFFSET sqlc.arg(page_offset)::int;
+-- name: CountUsersWithTag :one
+-- An empty tag counts everyone.
+SELECT COUNT(*) FROM users
+WHERE event_id = sqlc.arg(event_id)
+AND deleted_at IS NULL
+AND (sqlc.arg(tag)::text = '' OR sqlc.arg(tag)::text = ANY(tags));
+-- name: GetEventTags :many
+SELECT DISTINCT tag::text FROM users, unnest(users.tags) AS tag
+WHERE users.event_id = sqlc.arg(event_id)
+AND users.deleted_at IS NULL
+ORDER BY tag;
+-- name: CountUsersReach :one
+-- Participants the bot can message, and those it can't because they
+-- blocked it.
+SELECT
+    COUNT(*) FILTER (WHERE tg_id IS NOT NULL AND bot_blocked_at IS NULL) AS reachable,
+    COUNT(*) FILTER (WHERE bot_blocked_at IS NOT NULL) AS blocked
+FROM users
+WHERE event_id = sqlc.arg(event_id)
+AND deleted_at IS NULL;
+-- name: GetLatestUsersByEventID :many
+-- Newest participants first, the order polling triggers like Zapier's expect.
+SELECT * FROM users
+WHERE event_id = sqlc.arg(event_id)
+AND deleted_at IS NULL
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size)::int;
+-- name: GetUserByTicketNumber :one
+SELECT * FROM users
+WHERE event_id = sqlc.arg(event_id)
+AND ticket_number = sqlc.arg(ticket_number)
+AND deleted_at IS NULL;
+-- name: GetUserByTgIDAndEventID :one
+SELECT * FROM users
+WHERE event_id = sqlc.arg(event_id)
+AND tg_id = sqlc.arg(tg_id)::bigint
+AND deleted_at IS NULL;
+-- name: CheckInUser :one
+-- Checking in twice keeps the time of the first check-in.
+UPDATE users
+SET checked_in_at = COALESCE(checked_in_at, CURRENT_TIMESTAMP)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: GetUserByCheckInCode :one
+SELECT * FROM users
+WHERE event_id = sqlc.arg(event_id)
+AND check_in_code = sqlc.arg(check_in_code)
+AND deleted_at IS NULL;
+-- name: UpdateUserProfile :one
+UPDATE users
+SET name = sqlc.arg(name),
+    phone = sqlc.arg(phone)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: ConfirmUserAttendance :one
+UPDATE users
+SET attendance_confirmed_at = COALESCE(attendance_confirmed_at, CURRENT_TIMESTAMP)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: AddUserPaidEntries :exec
+UPDATE users
+SET paid_entries = paid_entries + sqlc.arg(entries)
+WHERE id = sqlc.arg(id);
+-- name: AddUserPromoEntries :exec
+UPDATE users
+SET promo_entries = promo_entries + sqlc.arg(entries)
+WHERE id = sqlc.arg(id);
+-- name: SetUserTicketType :one
+-- Gives the participant the type's entries in the draw along with it.
+UPDATE users
+SET ticket_type_id = sqlc.arg(ticket_type_id)::bigint,
+    n = sqlc.arg(weight)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: SetUserShareCode :one
+-- Keeps an existing code, so a participant's share link never changes.
+UPDATE users
+SET share_code = COALESCE(share_code, sqlc.arg(share_code)::text)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: GetUserByShareCode :one
+SELECT * FROM users
+WHERE share_code = sqlc.arg(share_code)::text
+AND deleted_at IS NULL;
+-- name: GrantShareBonus :execrows
+-- Grants the bonus at most once per participant.
+UPDATE users
+SET share_entries = share_entries + sqlc.arg(entries),
+    share_bonus_granted_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+AND share_bonus_granted_at IS NULL;
+-- name: FlagSuspiciousUsers :execrows
+-- Flags participants who share a phone or a name with someone else in the
+-- event, or whose Telegram account ID is close to another participant's
+-- who registered around the same time, since fresh accounts created in a
+-- row get sequential IDs. Reviewed participants are never flagged again.
+WITH reasons AS (
+    SELECT u.id, CASE
+        WHEN u.phone <> '' AND EXISTS (
+            SELECT 1 FROM users o
+            WHERE o.event_id = u.event_id AND o.id <> u.id AND o.deleted_at IS NULL AND o.phone = u.phone
+        ) THEN 'same_phone'
+        WHEN EXISTS (
+            SELECT 1 FROM users o
+            WHERE o.event_id = u.event_id AND o.id <> u.id AND o.deleted_at IS NULL AND lower(btrim(o.name)) = lower(btrim(u.name))
+        ) THEN 'duplicate_name'
+        WHEN u.tg_id IS NOT NULL AND EXISTS (
+            SELECT 1 FROM users o
+            WHERE o.event_id = u.event_id AND o.id <> u.id AND o.deleted_at IS NULL
+            AND o.tg_id BETWEEN u.tg_id - sqlc.arg(max_tg_id_gap)::bigint AND u.tg_id + sqlc.arg(max_tg_id_gap)::bigint
+            AND o.created_at BETWEEN u.created_at - make_interval(secs => sqlc.arg(burst_seconds)::int)
+                AND u.created_at + make_interval(secs => sqlc.arg(burst_seconds)::int)
+        ) THEN 'sequential_tg_id'
+    END AS reason
+    FROM users u
+    WHERE u.event_id = sqlc.arg(event_id)::bigint
+    AND u.deleted_at IS NULL
+    AND u.flag_reason IS NULL
+    AND u.reviewed_at IS NULL
+)
+UPDATE users
+SET flag_reason = reasons.reason
+FROM reasons
+WHERE users.id = reasons.id
+AND reasons.reason IS NOT NULL;
+-- name: GetFlaggedUsers :many
+-- Participants waiting for review.
+SELECT * FROM users
+WHERE event_id = sqlc.arg(event_id)
+AND deleted_at IS NULL
+AND flag_reason IS NOT NULL
+AND reviewed_at IS NULL
+ORDER BY flag_reason, lower(name), id;
+-- name: ApproveUser :one
+UPDATE users
+SET reviewed_at = COALESCE(reviewed_at, CURRENT_TIMESTAMP)
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id)
+RETURNING *;
+-- name: SetUserTags :one
+UPDATE users
+SET tags = sqlc.arg(tags)::text[]
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id)
+RETURNING *;
+-- name: SetUserNotes :one
+UPDATE users
+SET notes = sqlc.arg(notes)
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id)
+RETURNING *;
+-- name: ImportUser :one
+-- Recreates a participant from an event export with their ticket, entries
+-- and history. Ticket numbering is caught up by SyncLastTicketNumber.
+INSERT INTO users (
+    name,
+    username,
+    tg_id,
+    event_id,
+    created_at,
+    n,
+    ticket_number,
+    checked_in_at,
+    check_in_code,
+    phone,
+    attendance_confirmed_at,
+    paid_entries,
+    bonus_entries,
+    applied_rule_ids,
+    share_entries,
+    share_bonus_granted_at,
+    promo_entries,
+    ticket_type_id,
+    flag_reason,
+    reviewed_at,
+    tags,
+    notes,
+    bot_blocked_at
+) VALUES (
+    sqlc.arg(name),
+    sqlc.arg(username),
+    sqlc.narg(tg_id),
+    sqlc.arg(event_id),
+    sqlc.narg(created_at),
+    sqlc.arg(n),
+    sqlc.arg(ticket_number),
+    sqlc.narg(checked_in_at),
+    sqlc.arg(check_in_code),
+    sqlc.arg(phone),
+    sqlc.narg(attendance_confirmed_at),
+    sqlc.arg(paid_entries),
+    sqlc.arg(bonus_entries),
+    sqlc.arg(applied_rule_ids),
+    sqlc.arg(share_entries),
+    sqlc.narg(share_bonus_granted_at),
+    sqlc.arg(promo_entries),
+    sqlc.narg(ticket_type_id),
+    sqlc.narg(flag_reason),
+    sqlc.narg(reviewed_at),
+    sqlc.arg(tags),
+    sqlc.arg(notes),
+    sqlc.narg(bot_blocked_at)
+) RETURNING *;
+-- name: MarkChatBlocked :many
+-- Flags the participants of every event registered from the chat.
+UPDATE users
+SET bot_blocked_at = CURRENT_TIMESTAMP
+WHERE tg_id = sqlc.arg(tg_id)::bigint
+AND bot_blocked_at IS NULL
+RETURNING *;
+-- name: ClearChatBlocked :many
+UPDATE users
+SET bot_blocked_at = NULL
+WHERE tg_id = sqlc.arg(tg_id)::bigint
+AND bot_blocked_at IS NOT NULL
+RETURNING *;
+-- name: GetDeletedUsers :many
+-- Participants in the trash, most recently deleted first. Those of deleted
+-- events are restored with their event instead.
+SELECT sqlc.embed(users), events.name AS event_name
+FROM users
+JOIN events ON events.id = users.event_id
+WHERE users.deleted_at IS NOT NULL
+AND events.deleted_at IS NULL
+ORDER BY users.deleted_at DESC, users.id DESC;
+-- name: RestoreUser :one
+-- Fails with a unique violation if the account has registered for the
+-- event again since.
+UPDATE users
+SET deleted_at = NULL
+WHERE id = sqlc.arg(id)
+AND deleted_at IS NOT NULL
+RETURNING *;
+-- name: PurgeDeletedUsersBefore :execrows
+-- Removes for good the participants deleted before the cutoff.
+DELETE FROM users
+WHERE deleted_at < sqlc.arg(cutoff)::timestamp;
@@ -0,0 +1,47 @@
+-- name: AddToWaitlist :one
+INSERT INTO waitlist (
+    event_id,
+    name,
+    username,
+    tg_id,
+    ticket_type_id,
+    consent_source
+) VALUES (
+    sqlc.arg(event_id),
+    sqlc.arg(name),
+    sqlc.arg(username),
+    sqlc.narg(tg_id),
+    sqlc.narg(ticket_type_id),
+    sqlc.arg(consent_source)
+) RETURNING *;
+-- name: GetWaitlistByEventID :many
+SELECT sqlc.embed(waitlist), ROW_NUMBER() OVER (ORDER BY created_at, id)::int AS position
+FROM waitlist
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY created_at, id;
+-- name: GetWaitlistEntry :one
+SELECT * FROM waitlist
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id);
+-- name: GetNextWaitlistEntry :one
+SELECT * FROM waitlist
+WHERE event_id = sqlc.arg(event_id)
+ORDER BY created_at, id
+LIMIT 1
+FOR UPDATE;
+-- name: DeleteWaitlistEntry :exec
+DELETE FROM waitlist
+WHERE id = sqlc.arg(id)
+AND event_id = sqlc.arg(event_id);
+-- name: GetWaitlistEntryByTgID :one
+SELECT * FROM waitlist
+WHERE tg_id = sqlc.arg(tg_id)
+AND event_id = sqlc.arg(event_id);
+-- name: GetWaitlistPosition :one
+-- The entry's place in the queue, counting from 1.
+SELECT COUNT(*)::int FROM waitlist
+WHERE waitlist.event_id = sqlc.arg(event_id)
+AND (waitlist.created_at, waitlist.id) <= (
+    SELECT entry.created_at, entry.id FROM waitlist entry
+    WHERE entry.id = sqlc.arg(id)
+);
@@ -0,0 +1,38 @@
+-- name: EnqueueWebhook :exec
+INSERT INTO webhooks (
+    url,
+    payload
+) VALUES (
+    sqlc.arg(url),
+    sqlc.arg(payload)
+);
+-- name: ClaimWebhooks :many
+UPDATE webhooks
+SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE id IN (
+    SELECT id FROM webhooks
+    WHERE sent_at IS NULL
+    AND failed_at IS NULL
+    AND next_attempt_at <= CURRENT_TIMESTAMP
+    ORDER BY id
+    LIMIT sqlc.arg(batch_size)::int
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+-- name: MarkWebhookSent :exec
+UPDATE webhooks
+SET sent_at = CURRENT_TIMESTAMP,
+    attempts = attempts + 1
+WHERE id = sqlc.arg(id);
+-- name: MarkWebhookFailed :exec
+UPDATE webhooks
+SET attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg(retry_after_seconds)::int),
+    failed_at = CASE WHEN sqlc.arg(give_up)::boolean THEN CURRENT_TIMESTAMP END
+WHERE id = sqlc.arg(id);
+-- name: DeleteWebhooksBefore :execrows
+-- Removes calls that were delivered or given up on before the cutoff.
+DELETE FROM webhooks
+WHERE sent_at < sqlc.arg(before)::timestamp
+OR failed_at < sqlc.arg(before)::timestamp;
@@ -24,113 +24,1737 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.addToWaitlistStmt, err = db.PrepareContext(ctx, addToWaitlist); err != nil {
+		return nil, fmt.Errorf("error preparing query AddToWaitlist: %w", err)
+	}
+	if q.addUserPaidEntriesStmt, err = db.PrepareContext(ctx, addUserPaidEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query AddUserPaidEntries: %w", err)
+	}
+	if q.addUserPromoEntriesStmt, err = db.PrepareContext(ctx, addUserPromoEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query AddUserPromoEntries: %w", err)
+	}
+	if q.approveUserStmt, err = db.PrepareContext(ctx, approveUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ApproveUser: %w", err)
+	}
+	if q.archiveEventStmt, err = db.PrepareContext(ctx, archiveEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query ArchiveEvent: %w", err)
+	}
+	if q.archiveEventsBeforeStmt, err = db.PrepareContext(ctx, archiveEventsBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query ArchiveEventsBefore: %w", err)
+	}
+	if q.assignFreeSeatStmt, err = db.PrepareContext(ctx, assignFreeSeat); err != nil {
+		return nil, fmt.Errorf("error preparing query AssignFreeSeat: %w", err)
+	}
+	if q.checkInUserStmt, err = db.PrepareContext(ctx, checkInUser); err != nil {
+		return nil, fmt.Errorf("error preparing query CheckInUser: %w", err)
+	}
+	if q.claimIdempotencyKeyStmt, err = db.PrepareContext(ctx, claimIdempotencyKey); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimIdempotencyKey: %w", err)
+	}
+	if q.claimOutboxMessagesStmt, err = db.PrepareContext(ctx, claimOutboxMessages); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimOutboxMessages: %w", err)
+	}
+	if q.claimWebhooksStmt, err = db.PrepareContext(ctx, claimWebhooks); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimWebhooks: %w", err)
+	}
+	if q.clearChatBlockedStmt, err = db.PrepareContext(ctx, clearChatBlocked); err != nil {
+		return nil, fmt.Errorf("error preparing query ClearChatBlocked: %w", err)
+	}
+	if q.clickShortLinkStmt, err = db.PrepareContext(ctx, clickShortLink); err != nil {
+		return nil, fmt.Errorf("error preparing query ClickShortLink: %w", err)
+	}
+	if q.confirmUserAttendanceStmt, err = db.PrepareContext(ctx, confirmUserAttendance); err != nil {
+		return nil, fmt.Errorf("error preparing query ConfirmUserAttendance: %w", err)
+	}
+	if q.consumeAdminLoginTokenStmt, err = db.PrepareContext(ctx, consumeAdminLoginToken); err != nil {
+		return nil, fmt.Errorf("error preparing query ConsumeAdminLoginToken: %w", err)
+	}
+	if q.countEventTakenStmt, err = db.PrepareContext(ctx, countEventTaken); err != nil {
+		return nil, fmt.Errorf("error preparing query CountEventTaken: %w", err)
+	}
+	if q.countReservedPaidEntriesStmt, err = db.PrepareContext(ctx, countReservedPaidEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query CountReservedPaidEntries: %w", err)
+	}
+	if q.countShareClicksStmt, err = db.PrepareContext(ctx, countShareClicks); err != nil {
+		return nil, fmt.Errorf("error preparing query CountShareClicks: %w", err)
+	}
+	if q.countShareClicksByVisitorStmt, err = db.PrepareContext(ctx, countShareClicksByVisitor); err != nil {
+		return nil, fmt.Errorf("error preparing query CountShareClicksByVisitor: %w", err)
+	}
+	if q.countTicketTypeTakenStmt, err = db.PrepareContext(ctx, countTicketTypeTaken); err != nil {
+		return nil, fmt.Errorf("error preparing query CountTicketTypeTaken: %w", err)
+	}
+	if q.countUsersByEventIDStmt, err = db.PrepareContext(ctx, countUsersByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query CountUsersByEventID: %w", err)
+	}
+	if q.countUsersReachStmt, err = db.PrepareContext(ctx, countUsersReach); err != nil {
+		return nil, fmt.Errorf("error preparing query CountUsersReach: %w", err)
+	}
+	if q.countUsersWithTagStmt, err = db.PrepareContext(ctx, countUsersWithTag); err != nil {
+		return nil, fmt.Errorf("error preparing query CountUsersWithTag: %w", err)
+	}
+	if q.createAdminStmt, err = db.PrepareContext(ctx, createAdmin); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAdmin: %w", err)
+	}
+	if q.createAdminLoginTokenStmt, err = db.PrepareContext(ctx, createAdminLoginToken); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAdminLoginToken: %w", err)
+	}
+	if q.createAuditRecordStmt, err = db.PrepareContext(ctx, createAuditRecord); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAuditRecord: %w", err)
+	}
+	if q.createBroadcastStmt, err = db.PrepareContext(ctx, createBroadcast); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBroadcast: %w", err)
+	}
+	if q.createBroadcastDeliveryStmt, err = db.PrepareContext(ctx, createBroadcastDelivery); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBroadcastDelivery: %w", err)
+	}
+	if q.createConsentRecordStmt, err = db.PrepareContext(ctx, createConsentRecord); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateConsentRecord: %w", err)
+	}
+	if q.createDigestSubscriptionStmt, err = db.PrepareContext(ctx, createDigestSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDigestSubscription: %w", err)
+	}
+	if q.createDrawStmt, err = db.PrepareContext(ctx, createDraw); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDraw: %w", err)
+	}
+	if q.createDrawWinnerStmt, err = db.PrepareContext(ctx, createDrawWinner); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDrawWinner: %w", err)
+	}
+	if q.createEntryPurchaseStmt, err = db.PrepareContext(ctx, createEntryPurchase); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateEntryPurchase: %w", err)
+	}
+	if q.createEntryRuleStmt, err = db.PrepareContext(ctx, createEntryRule); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateEntryRule: %w", err)
+	}
 	if q.createEventStmt, err = db.PrepareContext(ctx, createEvent); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateEvent: %w", err)
 	}
+	if q.createEventOrganizerStmt, err = db.PrepareContext(ctx, createEventOrganizer); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateEventOrganizer: %w", err)
+	}
+	if q.createEventTemplateStmt, err = db.PrepareContext(ctx, createEventTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateEventTemplate: %w", err)
+	}
+	if q.createEventTemplateRuleStmt, err = db.PrepareContext(ctx, createEventTemplateRule); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateEventTemplateRule: %w", err)
+	}
+	if q.createPromoCodeStmt, err = db.PrepareContext(ctx, createPromoCode); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePromoCode: %w", err)
+	}
+	if q.createPromoRedemptionStmt, err = db.PrepareContext(ctx, createPromoRedemption); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePromoRedemption: %w", err)
+	}
+	if q.createSeatsStmt, err = db.PrepareContext(ctx, createSeats); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSeats: %w", err)
+	}
+	if q.createShortLinkStmt, err = db.PrepareContext(ctx, createShortLink); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateShortLink: %w", err)
+	}
+	if q.createTicketOrderStmt, err = db.PrepareContext(ctx, createTicketOrder); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateTicketOrder: %w", err)
+	}
+	if q.createTicketTypeStmt, err = db.PrepareContext(ctx, createTicketType); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateTicketType: %w", err)
+	}
+	if q.createTokenStmt, err = db.PrepareContext(ctx, createToken); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateToken: %w", err)
+	}
 	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
 	}
+	if q.createUsersBatchStmt, err = db.PrepareContext(ctx, createUsersBatch); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUsersBatch: %w", err)
+	}
+	if q.deleteAdminStmt, err = db.PrepareContext(ctx, deleteAdmin); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAdmin: %w", err)
+	}
+	if q.deleteAdminLoginTokensBeforeStmt, err = db.PrepareContext(ctx, deleteAdminLoginTokensBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAdminLoginTokensBefore: %w", err)
+	}
+	if q.deleteBroadcastStmt, err = db.PrepareContext(ctx, deleteBroadcast); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteBroadcast: %w", err)
+	}
+	if q.deleteDigestSubscriptionStmt, err = db.PrepareContext(ctx, deleteDigestSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteDigestSubscription: %w", err)
+	}
+	if q.deleteEntryRuleStmt, err = db.PrepareContext(ctx, deleteEntryRule); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteEntryRule: %w", err)
+	}
 	if q.deleteEventStmt, err = db.PrepareContext(ctx, deleteEvent); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteEvent: %w", err)
 	}
+	if q.deleteEventImageStmt, err = db.PrepareContext(ctx, deleteEventImage); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteEventImage: %w", err)
+	}
+	if q.deleteEventOrganizerStmt, err = db.PrepareContext(ctx, deleteEventOrganizer); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteEventOrganizer: %w", err)
+	}
+	if q.deleteEventTemplateStmt, err = db.PrepareContext(ctx, deleteEventTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteEventTemplate: %w", err)
+	}
+	if q.deleteEventTranslationStmt, err = db.PrepareContext(ctx, deleteEventTranslation); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteEventTranslation: %w", err)
+	}
+	if q.deleteIdempotencyKeysBeforeStmt, err = db.PrepareContext(ctx, deleteIdempotencyKeysBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteIdempotencyKeysBefore: %w", err)
+	}
+	if q.deleteOtherAdminSessionsStmt, err = db.PrepareContext(ctx, deleteOtherAdminSessions); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteOtherAdminSessions: %w", err)
+	}
+	if q.deleteOutboxBeforeStmt, err = db.PrepareContext(ctx, deleteOutboxBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteOutboxBefore: %w", err)
+	}
+	if q.deletePromoCodeStmt, err = db.PrepareContext(ctx, deletePromoCode); err != nil {
+		return nil, fmt.Errorf("error preparing query DeletePromoCode: %w", err)
+	}
+	if q.deleteSeatStmt, err = db.PrepareContext(ctx, deleteSeat); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSeat: %w", err)
+	}
+	if q.deleteSessionStmt, err = db.PrepareContext(ctx, deleteSession); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSession: %w", err)
+	}
+	if q.deleteSessionByIDStmt, err = db.PrepareContext(ctx, deleteSessionByID); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSessionByID: %w", err)
+	}
+	if q.deleteSessionsBeforeStmt, err = db.PrepareContext(ctx, deleteSessionsBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSessionsBefore: %w", err)
+	}
+	if q.deleteShortLinkStmt, err = db.PrepareContext(ctx, deleteShortLink); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteShortLink: %w", err)
+	}
+	if q.deleteTicketTypeStmt, err = db.PrepareContext(ctx, deleteTicketType); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTicketType: %w", err)
+	}
+	if q.deleteTokenStmt, err = db.PrepareContext(ctx, deleteToken); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteToken: %w", err)
+	}
 	if q.deleteUserStmt, err = db.PrepareContext(ctx, deleteUser); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteUser: %w", err)
 	}
 	if q.deleteUsersByIdAndEventIdStmt, err = db.PrepareContext(ctx, deleteUsersByIdAndEventId); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteUsersByIdAndEventId: %w", err)
 	}
+	if q.deleteWaitlistEntryStmt, err = db.PrepareContext(ctx, deleteWaitlistEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteWaitlistEntry: %w", err)
+	}
+	if q.deleteWebhooksBeforeStmt, err = db.PrepareContext(ctx, deleteWebhooksBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteWebhooksBefore: %w", err)
+	}
+	if q.enqueueOutboxMessageStmt, err = db.PrepareContext(ctx, enqueueOutboxMessage); err != nil {
+		return nil, fmt.Errorf("error preparing query EnqueueOutboxMessage: %w", err)
+	}
+	if q.enqueueWebhookStmt, err = db.PrepareContext(ctx, enqueueWebhook); err != nil {
+		return nil, fmt.Errorf("error preparing query EnqueueWebhook: %w", err)
+	}
+	if q.eventSlugTakenStmt, err = db.PrepareContext(ctx, eventSlugTaken); err != nil {
+		return nil, fmt.Errorf("error preparing query EventSlugTaken: %w", err)
+	}
+	if q.flagSuspiciousUsersStmt, err = db.PrepareContext(ctx, flagSuspiciousUsers); err != nil {
+		return nil, fmt.Errorf("error preparing query FlagSuspiciousUsers: %w", err)
+	}
+	if q.getAdminByIDStmt, err = db.PrepareContext(ctx, getAdminByID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAdminByID: %w", err)
+	}
+	if q.getAdminByUsernameStmt, err = db.PrepareContext(ctx, getAdminByUsername); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAdminByUsername: %w", err)
+	}
+	if q.getAdminSessionsStmt, err = db.PrepareContext(ctx, getAdminSessions); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAdminSessions: %w", err)
+	}
+	if q.getAdminsStmt, err = db.PrepareContext(ctx, getAdmins); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAdmins: %w", err)
+	}
+	if q.getAnomalyCountsStmt, err = db.PrepareContext(ctx, getAnomalyCounts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAnomalyCounts: %w", err)
+	}
+	if q.getAuditLogPageStmt, err = db.PrepareContext(ctx, getAuditLogPage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAuditLogPage: %w", err)
+	}
+	if q.getBroadcastByIDStmt, err = db.PrepareContext(ctx, getBroadcastByID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBroadcastByID: %w", err)
+	}
+	if q.getBroadcastDeliveriesStmt, err = db.PrepareContext(ctx, getBroadcastDeliveries); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBroadcastDeliveries: %w", err)
+	}
+	if q.getBroadcastsByEventIDStmt, err = db.PrepareContext(ctx, getBroadcastsByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBroadcastsByEventID: %w", err)
+	}
+	if q.getConsentLogByEventIDStmt, err = db.PrepareContext(ctx, getConsentLogByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetConsentLogByEventID: %w", err)
+	}
+	if q.getDeletedEventsStmt, err = db.PrepareContext(ctx, getDeletedEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeletedEvents: %w", err)
+	}
+	if q.getDeletedUsersStmt, err = db.PrepareContext(ctx, getDeletedUsers); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeletedUsers: %w", err)
+	}
+	if q.getDigestSubscriptionStmt, err = db.PrepareContext(ctx, getDigestSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDigestSubscription: %w", err)
+	}
+	if q.getDigestSubscriptionsStmt, err = db.PrepareContext(ctx, getDigestSubscriptions); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDigestSubscriptions: %w", err)
+	}
+	if q.getDrawWinnersStmt, err = db.PrepareContext(ctx, getDrawWinners); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDrawWinners: %w", err)
+	}
+	if q.getDrawsByEventIDStmt, err = db.PrepareContext(ctx, getDrawsByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDrawsByEventID: %w", err)
+	}
+	if q.getDrawsSinceStmt, err = db.PrepareContext(ctx, getDrawsSince); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDrawsSince: %w", err)
+	}
+	if q.getDueBroadcastsStmt, err = db.PrepareContext(ctx, getDueBroadcasts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDueBroadcasts: %w", err)
+	}
+	if q.getEntryRulesByEventIDStmt, err = db.PrepareContext(ctx, getEntryRulesByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEntryRulesByEventID: %w", err)
+	}
 	if q.getEventByIDStmt, err = db.PrepareContext(ctx, getEventByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetEventByID: %w", err)
 	}
+	if q.getEventBySlugStmt, err = db.PrepareContext(ctx, getEventBySlug); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventBySlug: %w", err)
+	}
+	if q.getEventCategoriesStmt, err = db.PrepareContext(ctx, getEventCategories); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventCategories: %w", err)
+	}
+	if q.getEventForUpdateStmt, err = db.PrepareContext(ctx, getEventForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventForUpdate: %w", err)
+	}
+	if q.getEventImageStmt, err = db.PrepareContext(ctx, getEventImage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventImage: %w", err)
+	}
+	if q.getEventOrganizersStmt, err = db.PrepareContext(ctx, getEventOrganizers); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventOrganizers: %w", err)
+	}
+	if q.getEventTagsStmt, err = db.PrepareContext(ctx, getEventTags); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventTags: %w", err)
+	}
+	if q.getEventTemplateStmt, err = db.PrepareContext(ctx, getEventTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventTemplate: %w", err)
+	}
+	if q.getEventTemplateRulesStmt, err = db.PrepareContext(ctx, getEventTemplateRules); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventTemplateRules: %w", err)
+	}
+	if q.getEventTemplatesStmt, err = db.PrepareContext(ctx, getEventTemplates); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventTemplates: %w", err)
+	}
+	if q.getEventTranslationStmt, err = db.PrepareContext(ctx, getEventTranslation); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventTranslation: %w", err)
+	}
+	if q.getEventTranslationsStmt, err = db.PrepareContext(ctx, getEventTranslations); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventTranslations: %w", err)
+	}
 	if q.getEventsStmt, err = db.PrepareContext(ctx, getEvents); err != nil {
 		return nil, fmt.Errorf("error preparing query GetEvents: %w", err)
 	}
+	if q.getEventsBetweenStmt, err = db.PrepareContext(ctx, getEventsBetween); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventsBetween: %w", err)
+	}
+	if q.getEventsPageStmt, err = db.PrepareContext(ctx, getEventsPage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventsPage: %w", err)
+	}
+	if q.getEventsToSyncToCalendarStmt, err = db.PrepareContext(ctx, getEventsToSyncToCalendar); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEventsToSyncToCalendar: %w", err)
+	}
+	if q.getFeatureFlagsStmt, err = db.PrepareContext(ctx, getFeatureFlags); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFeatureFlags: %w", err)
+	}
+	if q.getFlaggedUsersStmt, err = db.PrepareContext(ctx, getFlaggedUsers); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFlaggedUsers: %w", err)
+	}
+	if q.getIdempotencyKeyStmt, err = db.PrepareContext(ctx, getIdempotencyKey); err != nil {
+		return nil, fmt.Errorf("error preparing query GetIdempotencyKey: %w", err)
+	}
 	if q.getLastEventStmt, err = db.PrepareContext(ctx, getLastEvent); err != nil {
 		return nil, fmt.Errorf("error preparing query GetLastEvent: %w", err)
 	}
+	if q.getLatestUsersByEventIDStmt, err = db.PrepareContext(ctx, getLatestUsersByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestUsersByEventID: %w", err)
+	}
+	if q.getNextWaitlistEntryStmt, err = db.PrepareContext(ctx, getNextWaitlistEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNextWaitlistEntry: %w", err)
+	}
+	if q.getNotificationRecipientsStmt, err = db.PrepareContext(ctx, getNotificationRecipients); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNotificationRecipients: %w", err)
+	}
+	if q.getOrganizedEventIDsStmt, err = db.PrepareContext(ctx, getOrganizedEventIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOrganizedEventIDs: %w", err)
+	}
+	if q.getPaymentsByEventIDStmt, err = db.PrepareContext(ctx, getPaymentsByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPaymentsByEventID: %w", err)
+	}
+	if q.getPromoCodeStmt, err = db.PrepareContext(ctx, getPromoCode); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPromoCode: %w", err)
+	}
+	if q.getPromoCodeStatsStmt, err = db.PrepareContext(ctx, getPromoCodeStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPromoCodeStats: %w", err)
+	}
+	if q.getPublicWinnersStmt, err = db.PrepareContext(ctx, getPublicWinners); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPublicWinners: %w", err)
+	}
+	if q.getRegistrationsSinceStmt, err = db.PrepareContext(ctx, getRegistrationsSince); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRegistrationsSince: %w", err)
+	}
+	if q.getSeatByLabelForUpdateStmt, err = db.PrepareContext(ctx, getSeatByLabelForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSeatByLabelForUpdate: %w", err)
+	}
+	if q.getSeatByUserIDStmt, err = db.PrepareContext(ctx, getSeatByUserID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSeatByUserID: %w", err)
+	}
+	if q.getSeatsByEventIDStmt, err = db.PrepareContext(ctx, getSeatsByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSeatsByEventID: %w", err)
+	}
+	if q.getSessionStmt, err = db.PrepareContext(ctx, getSession); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSession: %w", err)
+	}
+	if q.getShareReportStmt, err = db.PrepareContext(ctx, getShareReport); err != nil {
+		return nil, fmt.Errorf("error preparing query GetShareReport: %w", err)
+	}
+	if q.getShortLinkByCodeStmt, err = db.PrepareContext(ctx, getShortLinkByCode); err != nil {
+		return nil, fmt.Errorf("error preparing query GetShortLinkByCode: %w", err)
+	}
+	if q.getShortLinksByEventIDStmt, err = db.PrepareContext(ctx, getShortLinksByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetShortLinksByEventID: %w", err)
+	}
+	if q.getTicketOrderStmt, err = db.PrepareContext(ctx, getTicketOrder); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTicketOrder: %w", err)
+	}
+	if q.getTicketTypeStmt, err = db.PrepareContext(ctx, getTicketType); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTicketType: %w", err)
+	}
+	if q.getTicketTypeForUpdateStmt, err = db.PrepareContext(ctx, getTicketTypeForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTicketTypeForUpdate: %w", err)
+	}
+	if q.getTicketTypeStatsStmt, err = db.PrepareContext(ctx, getTicketTypeStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTicketTypeStats: %w", err)
+	}
+	if q.getTicketTypesByEventIDStmt, err = db.PrepareContext(ctx, getTicketTypesByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTicketTypesByEventID: %w", err)
+	}
+	if q.getTokenByHashStmt, err = db.PrepareContext(ctx, getTokenByHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTokenByHash: %w", err)
+	}
+	if q.getTokensByAdminIDStmt, err = db.PrepareContext(ctx, getTokensByAdminID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTokensByAdminID: %w", err)
+	}
+	if q.getTranslationsByLanguageStmt, err = db.PrepareContext(ctx, getTranslationsByLanguage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTranslationsByLanguage: %w", err)
+	}
+	if q.getUserByCheckInCodeStmt, err = db.PrepareContext(ctx, getUserByCheckInCode); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserByCheckInCode: %w", err)
+	}
 	if q.getUserByIDStmt, err = db.PrepareContext(ctx, getUserByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserByID: %w", err)
 	}
+	if q.getUserByShareCodeStmt, err = db.PrepareContext(ctx, getUserByShareCode); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserByShareCode: %w", err)
+	}
+	if q.getUserByTgIDAndEventIDStmt, err = db.PrepareContext(ctx, getUserByTgIDAndEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserByTgIDAndEventID: %w", err)
+	}
+	if q.getUserByTicketNumberStmt, err = db.PrepareContext(ctx, getUserByTicketNumber); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserByTicketNumber: %w", err)
+	}
 	if q.getUserByUsernameStmt, err = db.PrepareContext(ctx, getUserByUsername); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserByUsername: %w", err)
 	}
 	if q.getUsersByEventIDStmt, err = db.PrepareContext(ctx, getUsersByEventID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUsersByEventID: %w", err)
 	}
+	if q.getUsersByEventIDAfterStmt, err = db.PrepareContext(ctx, getUsersByEventIDAfter); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUsersByEventIDAfter: %w", err)
+	}
+	if q.getUsersPageStmt, err = db.PrepareContext(ctx, getUsersPage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUsersPage: %w", err)
+	}
+	if q.getWaitlistByEventIDStmt, err = db.PrepareContext(ctx, getWaitlistByEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWaitlistByEventID: %w", err)
+	}
+	if q.getWaitlistEntryStmt, err = db.PrepareContext(ctx, getWaitlistEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWaitlistEntry: %w", err)
+	}
+	if q.getWaitlistEntryByTgIDStmt, err = db.PrepareContext(ctx, getWaitlistEntryByTgID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWaitlistEntryByTgID: %w", err)
+	}
+	if q.getWaitlistPositionStmt, err = db.PrepareContext(ctx, getWaitlistPosition); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWaitlistPosition: %w", err)
+	}
+	if q.grantShareBonusStmt, err = db.PrepareContext(ctx, grantShareBonus); err != nil {
+		return nil, fmt.Errorf("error preparing query GrantShareBonus: %w", err)
+	}
+	if q.importDrawStmt, err = db.PrepareContext(ctx, importDraw); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportDraw: %w", err)
+	}
+	if q.importUserStmt, err = db.PrepareContext(ctx, importUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportUser: %w", err)
+	}
+	if q.lockEventForCalendarSyncStmt, err = db.PrepareContext(ctx, lockEventForCalendarSync); err != nil {
+		return nil, fmt.Errorf("error preparing query LockEventForCalendarSync: %w", err)
+	}
+	if q.markBroadcastQueuedStmt, err = db.PrepareContext(ctx, markBroadcastQueued); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkBroadcastQueued: %w", err)
+	}
+	if q.markChatBlockedStmt, err = db.PrepareContext(ctx, markChatBlocked); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkChatBlocked: %w", err)
+	}
+	if q.markDigestSentStmt, err = db.PrepareContext(ctx, markDigestSent); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkDigestSent: %w", err)
+	}
+	if q.markEntryPurchaseFailedStmt, err = db.PrepareContext(ctx, markEntryPurchaseFailed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkEntryPurchaseFailed: %w", err)
+	}
+	if q.markEntryPurchasePaidStmt, err = db.PrepareContext(ctx, markEntryPurchasePaid); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkEntryPurchasePaid: %w", err)
+	}
+	if q.markEntryPurchaseRefundedStmt, err = db.PrepareContext(ctx, markEntryPurchaseRefunded); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkEntryPurchaseRefunded: %w", err)
+	}
+	if q.markOutboxMessageFailedStmt, err = db.PrepareContext(ctx, markOutboxMessageFailed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkOutboxMessageFailed: %w", err)
+	}
+	if q.markOutboxMessageSentStmt, err = db.PrepareContext(ctx, markOutboxMessageSent); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkOutboxMessageSent: %w", err)
+	}
+	if q.markTicketOrderFailedStmt, err = db.PrepareContext(ctx, markTicketOrderFailed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkTicketOrderFailed: %w", err)
+	}
+	if q.markTicketOrderPaidStmt, err = db.PrepareContext(ctx, markTicketOrderPaid); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkTicketOrderPaid: %w", err)
+	}
+	if q.markTicketOrderRefundedStmt, err = db.PrepareContext(ctx, markTicketOrderRefunded); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkTicketOrderRefunded: %w", err)
+	}
+	if q.markWebhookFailedStmt, err = db.PrepareContext(ctx, markWebhookFailed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkWebhookFailed: %w", err)
+	}
+	if q.markWebhookSentStmt, err = db.PrepareContext(ctx, markWebhookSent); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkWebhookSent: %w", err)
+	}
+	if q.pruneNotifyEventIDsStmt, err = db.PrepareContext(ctx, pruneNotifyEventIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query PruneNotifyEventIDs: %w", err)
+	}
+	if q.purgeDeletedEventsBeforeStmt, err = db.PrepareContext(ctx, purgeDeletedEventsBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query PurgeDeletedEventsBefore: %w", err)
+	}
+	if q.purgeDeletedUsersBeforeStmt, err = db.PrepareContext(ctx, purgeDeletedUsersBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query PurgeDeletedUsersBefore: %w", err)
+	}
+	if q.recalculateEntryBonusesStmt, err = db.PrepareContext(ctx, recalculateEntryBonuses); err != nil {
+		return nil, fmt.Errorf("error preparing query RecalculateEntryBonuses: %w", err)
+	}
+	if q.recordShareClickStmt, err = db.PrepareContext(ctx, recordShareClick); err != nil {
+		return nil, fmt.Errorf("error preparing query RecordShareClick: %w", err)
+	}
+	if q.releaseIdempotencyKeyStmt, err = db.PrepareContext(ctx, releaseIdempotencyKey); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleaseIdempotencyKey: %w", err)
+	}
+	if q.releasePromoRedemptionStmt, err = db.PrepareContext(ctx, releasePromoRedemption); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleasePromoRedemption: %w", err)
+	}
+	if q.releaseSeatStmt, err = db.PrepareContext(ctx, releaseSeat); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleaseSeat: %w", err)
+	}
+	if q.restoreEventStmt, err = db.PrepareContext(ctx, restoreEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreEvent: %w", err)
+	}
+	if q.restoreUserStmt, err = db.PrepareContext(ctx, restoreUser); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreUser: %w", err)
+	}
+	if q.saveIdempotencyKeyStmt, err = db.PrepareContext(ctx, saveIdempotencyKey); err != nil {
+		return nil, fmt.Errorf("error preparing query SaveIdempotencyKey: %w", err)
+	}
+	if q.saveSessionStmt, err = db.PrepareContext(ctx, saveSession); err != nil {
+		return nil, fmt.Errorf("error preparing query SaveSession: %w", err)
+	}
+	if q.setAdminPasswordStmt, err = db.PrepareContext(ctx, setAdminPassword); err != nil {
+		return nil, fmt.Errorf("error preparing query SetAdminPassword: %w", err)
+	}
+	if q.setBroadcastDeliveryStatusStmt, err = db.PrepareContext(ctx, setBroadcastDeliveryStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query SetBroadcastDeliveryStatus: %w", err)
+	}
+	if q.setEventCalendarSyncedStmt, err = db.PrepareContext(ctx, setEventCalendarSynced); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventCalendarSynced: %w", err)
+	}
+	if q.setEventCapacityStmt, err = db.PrepareContext(ctx, setEventCapacity); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventCapacity: %w", err)
+	}
+	if q.setEventCategoriesStmt, err = db.PrepareContext(ctx, setEventCategories); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventCategories: %w", err)
+	}
+	if q.setEventImageStmt, err = db.PrepareContext(ctx, setEventImage); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventImage: %w", err)
+	}
+	if q.setEventKioskTokenStmt, err = db.PrepareContext(ctx, setEventKioskToken); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventKioskToken: %w", err)
+	}
+	if q.setEventPaidEntriesStmt, err = db.PrepareContext(ctx, setEventPaidEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventPaidEntries: %w", err)
+	}
+	if q.setEventRegistrationWindowStmt, err = db.PrepareContext(ctx, setEventRegistrationWindow); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventRegistrationWindow: %w", err)
+	}
+	if q.setEventSeatAssignmentStmt, err = db.PrepareContext(ctx, setEventSeatAssignment); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventSeatAssignment: %w", err)
+	}
+	if q.setEventShareBonusStmt, err = db.PrepareContext(ctx, setEventShareBonus); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventShareBonus: %w", err)
+	}
+	if q.setEventShowWinnersStmt, err = db.PrepareContext(ctx, setEventShowWinners); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventShowWinners: %w", err)
+	}
+	if q.setEventSlugStmt, err = db.PrepareContext(ctx, setEventSlug); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventSlug: %w", err)
+	}
+	if q.setEventStatusStmt, err = db.PrepareContext(ctx, setEventStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventStatus: %w", err)
+	}
+	if q.setEventTicketPriceStmt, err = db.PrepareContext(ctx, setEventTicketPrice); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventTicketPrice: %w", err)
+	}
+	if q.setEventWaitlistAutoPromoteStmt, err = db.PrepareContext(ctx, setEventWaitlistAutoPromote); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEventWaitlistAutoPromote: %w", err)
+	}
+	if q.setFeatureFlagStmt, err = db.PrepareContext(ctx, setFeatureFlag); err != nil {
+		return nil, fmt.Errorf("error preparing query SetFeatureFlag: %w", err)
+	}
+	if q.setPromoRedemptionUserStmt, err = db.PrepareContext(ctx, setPromoRedemptionUser); err != nil {
+		return nil, fmt.Errorf("error preparing query SetPromoRedemptionUser: %w", err)
+	}
+	if q.setSeatUserStmt, err = db.PrepareContext(ctx, setSeatUser); err != nil {
+		return nil, fmt.Errorf("error preparing query SetSeatUser: %w", err)
+	}
+	if q.setTicketOrderUserStmt, err = db.PrepareContext(ctx, setTicketOrderUser); err != nil {
+		return nil, fmt.Errorf("error preparing query SetTicketOrderUser: %w", err)
+	}
+	if q.setUserNotesStmt, err = db.PrepareContext(ctx, setUserNotes); err != nil {
+		return nil, fmt.Errorf("error preparing query SetUserNotes: %w", err)
+	}
+	if q.setUserShareCodeStmt, err = db.PrepareContext(ctx, setUserShareCode); err != nil {
+		return nil, fmt.Errorf("error preparing query SetUserShareCode: %w", err)
+	}
+	if q.setUserTagsStmt, err = db.PrepareContext(ctx, setUserTags); err != nil {
+		return nil, fmt.Errorf("error preparing query SetUserTags: %w", err)
+	}
+	if q.setUserTicketTypeStmt, err = db.PrepareContext(ctx, setUserTicketType); err != nil {
+		return nil, fmt.Errorf("error preparing query SetUserTicketType: %w", err)
+	}
+	if q.syncLastTicketNumberStmt, err = db.PrepareContext(ctx, syncLastTicketNumber); err != nil {
+		return nil, fmt.Errorf("error preparing query SyncLastTicketNumber: %w", err)
+	}
+	if q.touchTokenStmt, err = db.PrepareContext(ctx, touchToken); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchToken: %w", err)
+	}
+	if q.unarchiveEventStmt, err = db.PrepareContext(ctx, unarchiveEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query UnarchiveEvent: %w", err)
+	}
+	if q.updateBroadcastStmt, err = db.PrepareContext(ctx, updateBroadcast); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateBroadcast: %w", err)
+	}
 	if q.updateEventStmt, err = db.PrepareContext(ctx, updateEvent); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateEvent: %w", err)
 	}
+	if q.updateEventTemplateStmt, err = db.PrepareContext(ctx, updateEventTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateEventTemplate: %w", err)
+	}
+	if q.updateNotificationPreferencesStmt, err = db.PrepareContext(ctx, updateNotificationPreferences); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateNotificationPreferences: %w", err)
+	}
 	if q.updateUserNStmt, err = db.PrepareContext(ctx, updateUserN); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUserN: %w", err)
 	}
+	if q.updateUserProfileStmt, err = db.PrepareContext(ctx, updateUserProfile); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserProfile: %w", err)
+	}
+	if q.upsertEventTranslationStmt, err = db.PrepareContext(ctx, upsertEventTranslation); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertEventTranslation: %w", err)
+	}
+	if q.usePromoCodeStmt, err = db.PrepareContext(ctx, usePromoCode); err != nil {
+		return nil, fmt.Errorf("error preparing query UsePromoCode: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
-	if q.createEventStmt != nil {
-		if cerr := q.createEventStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing createEventStmt: %w", cerr)
+	if q.addToWaitlistStmt != nil {
+		if cerr := q.addToWaitlistStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addToWaitlistStmt: %w", cerr)
 		}
 	}
-	if q.createUserStmt != nil {
-		if cerr := q.createUserStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
+	if q.addUserPaidEntriesStmt != nil {
+		if cerr := q.addUserPaidEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addUserPaidEntriesStmt: %w", cerr)
 		}
 	}
-	if q.deleteEventStmt != nil {
-		if cerr := q.deleteEventStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing deleteEventStmt: %w", cerr)
+	if q.addUserPromoEntriesStmt != nil {
+		if cerr := q.addUserPromoEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addUserPromoEntriesStmt: %w", cerr)
 		}
 	}
-	if q.deleteUserStmt != nil {
-		if cerr := q.deleteUserStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing deleteUserStmt: %w", cerr)
+	if q.approveUserStmt != nil {
+		if cerr := q.approveUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing approveUserStmt: %w", cerr)
 		}
 	}
-	if q.deleteUsersByIdAndEventIdStmt != nil {
-		if cerr := q.deleteUsersByIdAndEventIdStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing deleteUsersByIdAndEventIdStmt: %w", cerr)
+	if q.archiveEventStmt != nil {
+		if cerr := q.archiveEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing archiveEventStmt: %w", cerr)
 		}
 	}
-	if q.getEventByIDStmt != nil {
-		if cerr := q.getEventByIDStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getEventByIDStmt: %w", cerr)
+	if q.archiveEventsBeforeStmt != nil {
+		if cerr := q.archiveEventsBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing archiveEventsBeforeStmt: %w", cerr)
 		}
 	}
-	if q.getEventsStmt != nil {
-		if cerr := q.getEventsStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getEventsStmt: %w", cerr)
+	if q.assignFreeSeatStmt != nil {
+		if cerr := q.assignFreeSeatStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing assignFreeSeatStmt: %w", cerr)
 		}
 	}
-	if q.getLastEventStmt != nil {
-		if cerr := q.getLastEventStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getLastEventStmt: %w", cerr)
+	if q.checkInUserStmt != nil {
+		if cerr := q.checkInUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing checkInUserStmt: %w", cerr)
 		}
 	}
-	if q.getUserByIDStmt != nil {
-		if cerr := q.getUserByIDStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getUserByIDStmt: %w", cerr)
+	if q.claimIdempotencyKeyStmt != nil {
+		if cerr := q.claimIdempotencyKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimIdempotencyKeyStmt: %w", cerr)
 		}
 	}
-	if q.getUserByUsernameStmt != nil {
-		if cerr := q.getUserByUsernameStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getUserByUsernameStmt: %w", cerr)
+	if q.claimOutboxMessagesStmt != nil {
+		if cerr := q.claimOutboxMessagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimOutboxMessagesStmt: %w", cerr)
 		}
 	}
-	if q.getUsersByEventIDStmt != nil {
-		if cerr := q.getUsersByEventIDStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getUsersByEventIDStmt: %w", cerr)
+	if q.claimWebhooksStmt != nil {
+		if cerr := q.claimWebhooksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimWebhooksStmt: %w", cerr)
 		}
 	}
-	if q.updateEventStmt != nil {
-		if cerr := q.updateEventStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing updateEventStmt: %w", cerr)
+	if q.clearChatBlockedStmt != nil {
+		if cerr := q.clearChatBlockedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clearChatBlockedStmt: %w", cerr)
 		}
 	}
-	if q.updateUserNStmt != nil {
-		if cerr := q.updateUserNStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing updateUserNStmt: %w", cerr)
+	if q.clickShortLinkStmt != nil {
+		if cerr := q.clickShortLinkStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clickShortLinkStmt: %w", cerr)
+		}
+	}
+	if q.confirmUserAttendanceStmt != nil {
+		if cerr := q.confirmUserAttendanceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing confirmUserAttendanceStmt: %w", cerr)
+		}
+	}
+	if q.consumeAdminLoginTokenStmt != nil {
+		if cerr := q.consumeAdminLoginTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing consumeAdminLoginTokenStmt: %w", cerr)
+		}
+	}
+	if q.countEventTakenStmt != nil {
+		if cerr := q.countEventTakenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countEventTakenStmt: %w", cerr)
+		}
+	}
+	if q.countReservedPaidEntriesStmt != nil {
+		if cerr := q.countReservedPaidEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countReservedPaidEntriesStmt: %w", cerr)
+		}
+	}
+	if q.countShareClicksStmt != nil {
+		if cerr := q.countShareClicksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countShareClicksStmt: %w", cerr)
+		}
+	}
+	if q.countShareClicksByVisitorStmt != nil {
+		if cerr := q.countShareClicksByVisitorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countShareClicksByVisitorStmt: %w", cerr)
+		}
+	}
+	if q.countTicketTypeTakenStmt != nil {
+		if cerr := q.countTicketTypeTakenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countTicketTypeTakenStmt: %w", cerr)
+		}
+	}
+	if q.countUsersByEventIDStmt != nil {
+		if cerr := q.countUsersByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countUsersByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.countUsersReachStmt != nil {
+		if cerr := q.countUsersReachStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countUsersReachStmt: %w", cerr)
+		}
+	}
+	if q.countUsersWithTagStmt != nil {
+		if cerr := q.countUsersWithTagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countUsersWithTagStmt: %w", cerr)
+		}
+	}
+	if q.createAdminStmt != nil {
+		if cerr := q.createAdminStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAdminStmt: %w", cerr)
+		}
+	}
+	if q.createAdminLoginTokenStmt != nil {
+		if cerr := q.createAdminLoginTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAdminLoginTokenStmt: %w", cerr)
+		}
+	}
+	if q.createAuditRecordStmt != nil {
+		if cerr := q.createAuditRecordStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAuditRecordStmt: %w", cerr)
+		}
+	}
+	if q.createBroadcastStmt != nil {
+		if cerr := q.createBroadcastStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBroadcastStmt: %w", cerr)
+		}
+	}
+	if q.createBroadcastDeliveryStmt != nil {
+		if cerr := q.createBroadcastDeliveryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBroadcastDeliveryStmt: %w", cerr)
+		}
+	}
+	if q.createConsentRecordStmt != nil {
+		if cerr := q.createConsentRecordStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createConsentRecordStmt: %w", cerr)
+		}
+	}
+	if q.createDigestSubscriptionStmt != nil {
+		if cerr := q.createDigestSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDigestSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.createDrawStmt != nil {
+		if cerr := q.createDrawStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDrawStmt: %w", cerr)
+		}
+	}
+	if q.createDrawWinnerStmt != nil {
+		if cerr := q.createDrawWinnerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDrawWinnerStmt: %w", cerr)
+		}
+	}
+	if q.createEntryPurchaseStmt != nil {
+		if cerr := q.createEntryPurchaseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createEntryPurchaseStmt: %w", cerr)
+		}
+	}
+	if q.createEntryRuleStmt != nil {
+		if cerr := q.createEntryRuleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createEntryRuleStmt: %w", cerr)
+		}
+	}
+	if q.createEventStmt != nil {
+		if cerr := q.createEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createEventStmt: %w", cerr)
+		}
+	}
+	if q.createEventOrganizerStmt != nil {
+		if cerr := q.createEventOrganizerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createEventOrganizerStmt: %w", cerr)
+		}
+	}
+	if q.createEventTemplateStmt != nil {
+		if cerr := q.createEventTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createEventTemplateStmt: %w", cerr)
+		}
+	}
+	if q.createEventTemplateRuleStmt != nil {
+		if cerr := q.createEventTemplateRuleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createEventTemplateRuleStmt: %w", cerr)
+		}
+	}
+	if q.createPromoCodeStmt != nil {
+		if cerr := q.createPromoCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPromoCodeStmt: %w", cerr)
+		}
+	}
+	if q.createPromoRedemptionStmt != nil {
+		if cerr := q.createPromoRedemptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPromoRedemptionStmt: %w", cerr)
+		}
+	}
+	if q.createSeatsStmt != nil {
+		if cerr := q.createSeatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSeatsStmt: %w", cerr)
+		}
+	}
+	if q.createShortLinkStmt != nil {
+		if cerr := q.createShortLinkStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createShortLinkStmt: %w", cerr)
+		}
+	}
+	if q.createTicketOrderStmt != nil {
+		if cerr := q.createTicketOrderStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createTicketOrderStmt: %w", cerr)
+		}
+	}
+	if q.createTicketTypeStmt != nil {
+		if cerr := q.createTicketTypeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createTicketTypeStmt: %w", cerr)
+		}
+	}
+	if q.createTokenStmt != nil {
+		if cerr := q.createTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createTokenStmt: %w", cerr)
+		}
+	}
+	if q.createUserStmt != nil {
+		if cerr := q.createUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
+		}
+	}
+	if q.createUsersBatchStmt != nil {
+		if cerr := q.createUsersBatchStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUsersBatchStmt: %w", cerr)
+		}
+	}
+	if q.deleteAdminStmt != nil {
+		if cerr := q.deleteAdminStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAdminStmt: %w", cerr)
+		}
+	}
+	if q.deleteAdminLoginTokensBeforeStmt != nil {
+		if cerr := q.deleteAdminLoginTokensBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAdminLoginTokensBeforeStmt: %w", cerr)
+		}
+	}
+	if q.deleteBroadcastStmt != nil {
+		if cerr := q.deleteBroadcastStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteBroadcastStmt: %w", cerr)
+		}
+	}
+	if q.deleteDigestSubscriptionStmt != nil {
+		if cerr := q.deleteDigestSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteDigestSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.deleteEntryRuleStmt != nil {
+		if cerr := q.deleteEntryRuleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteEntryRuleStmt: %w", cerr)
+		}
+	}
+	if q.deleteEventStmt != nil {
+		if cerr := q.deleteEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteEventStmt: %w", cerr)
+		}
+	}
+	if q.deleteEventImageStmt != nil {
+		if cerr := q.deleteEventImageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteEventImageStmt: %w", cerr)
+		}
+	}
+	if q.deleteEventOrganizerStmt != nil {
+		if cerr := q.deleteEventOrganizerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteEventOrganizerStmt: %w", cerr)
+		}
+	}
+	if q.deleteEventTemplateStmt != nil {
+		if cerr := q.deleteEventTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteEventTemplateStmt: %w", cerr)
+		}
+	}
+	if q.deleteEventTranslationStmt != nil {
+		if cerr := q.deleteEventTranslationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteEventTranslationStmt: %w", cerr)
+		}
+	}
+	if q.deleteIdempotencyKeysBeforeStmt != nil {
+		if cerr := q.deleteIdempotencyKeysBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteIdempotencyKeysBeforeStmt: %w", cerr)
+		}
+	}
+	if q.deleteOtherAdminSessionsStmt != nil {
+		if cerr := q.deleteOtherAdminSessionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteOtherAdminSessionsStmt: %w", cerr)
+		}
+	}
+	if q.deleteOutboxBeforeStmt != nil {
+		if cerr := q.deleteOutboxBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteOutboxBeforeStmt: %w", cerr)
+		}
+	}
+	if q.deletePromoCodeStmt != nil {
+		if cerr := q.deletePromoCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deletePromoCodeStmt: %w", cerr)
+		}
+	}
+	if q.deleteSeatStmt != nil {
+		if cerr := q.deleteSeatStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSeatStmt: %w", cerr)
+		}
+	}
+	if q.deleteSessionStmt != nil {
+		if cerr := q.deleteSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSessionStmt: %w", cerr)
+		}
+	}
+	if q.deleteSessionByIDStmt != nil {
+		if cerr := q.deleteSessionByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSessionByIDStmt: %w", cerr)
+		}
+	}
+	if q.deleteSessionsBeforeStmt != nil {
+		if cerr := q.deleteSessionsBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSessionsBeforeStmt: %w", cerr)
+		}
+	}
+	if q.deleteShortLinkStmt != nil {
+		if cerr := q.deleteShortLinkStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteShortLinkStmt: %w", cerr)
+		}
+	}
+	if q.deleteTicketTypeStmt != nil {
+		if cerr := q.deleteTicketTypeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTicketTypeStmt: %w", cerr)
+		}
+	}
+	if q.deleteTokenStmt != nil {
+		if cerr := q.deleteTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTokenStmt: %w", cerr)
+		}
+	}
+	if q.deleteUserStmt != nil {
+		if cerr := q.deleteUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserStmt: %w", cerr)
+		}
+	}
+	if q.deleteUsersByIdAndEventIdStmt != nil {
+		if cerr := q.deleteUsersByIdAndEventIdStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUsersByIdAndEventIdStmt: %w", cerr)
+		}
+	}
+	if q.deleteWaitlistEntryStmt != nil {
+		if cerr := q.deleteWaitlistEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteWaitlistEntryStmt: %w", cerr)
+		}
+	}
+	if q.deleteWebhooksBeforeStmt != nil {
+		if cerr := q.deleteWebhooksBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteWebhooksBeforeStmt: %w", cerr)
+		}
+	}
+	if q.enqueueOutboxMessageStmt != nil {
+		if cerr := q.enqueueOutboxMessageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing enqueueOutboxMessageStmt: %w", cerr)
+		}
+	}
+	if q.enqueueWebhookStmt != nil {
+		if cerr := q.enqueueWebhookStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing enqueueWebhookStmt: %w", cerr)
+		}
+	}
+	if q.eventSlugTakenStmt != nil {
+		if cerr := q.eventSlugTakenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing eventSlugTakenStmt: %w", cerr)
+		}
+	}
+	if q.flagSuspiciousUsersStmt != nil {
+		if cerr := q.flagSuspiciousUsersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing flagSuspiciousUsersStmt: %w", cerr)
+		}
+	}
+	if q.getAdminByIDStmt != nil {
+		if cerr := q.getAdminByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAdminByIDStmt: %w", cerr)
+		}
+	}
+	if q.getAdminByUsernameStmt != nil {
+		if cerr := q.getAdminByUsernameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAdminByUsernameStmt: %w", cerr)
+		}
+	}
+	if q.getAdminSessionsStmt != nil {
+		if cerr := q.getAdminSessionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAdminSessionsStmt: %w", cerr)
+		}
+	}
+	if q.getAdminsStmt != nil {
+		if cerr := q.getAdminsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAdminsStmt: %w", cerr)
+		}
+	}
+	if q.getAnomalyCountsStmt != nil {
+		if cerr := q.getAnomalyCountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAnomalyCountsStmt: %w", cerr)
+		}
+	}
+	if q.getAuditLogPageStmt != nil {
+		if cerr := q.getAuditLogPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAuditLogPageStmt: %w", cerr)
+		}
+	}
+	if q.getBroadcastByIDStmt != nil {
+		if cerr := q.getBroadcastByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBroadcastByIDStmt: %w", cerr)
+		}
+	}
+	if q.getBroadcastDeliveriesStmt != nil {
+		if cerr := q.getBroadcastDeliveriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBroadcastDeliveriesStmt: %w", cerr)
+		}
+	}
+	if q.getBroadcastsByEventIDStmt != nil {
+		if cerr := q.getBroadcastsByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBroadcastsByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getConsentLogByEventIDStmt != nil {
+		if cerr := q.getConsentLogByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getConsentLogByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getDeletedEventsStmt != nil {
+		if cerr := q.getDeletedEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeletedEventsStmt: %w", cerr)
+		}
+	}
+	if q.getDeletedUsersStmt != nil {
+		if cerr := q.getDeletedUsersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeletedUsersStmt: %w", cerr)
+		}
+	}
+	if q.getDigestSubscriptionStmt != nil {
+		if cerr := q.getDigestSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDigestSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.getDigestSubscriptionsStmt != nil {
+		if cerr := q.getDigestSubscriptionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDigestSubscriptionsStmt: %w", cerr)
+		}
+	}
+	if q.getDrawWinnersStmt != nil {
+		if cerr := q.getDrawWinnersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDrawWinnersStmt: %w", cerr)
+		}
+	}
+	if q.getDrawsByEventIDStmt != nil {
+		if cerr := q.getDrawsByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDrawsByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getDrawsSinceStmt != nil {
+		if cerr := q.getDrawsSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDrawsSinceStmt: %w", cerr)
+		}
+	}
+	if q.getDueBroadcastsStmt != nil {
+		if cerr := q.getDueBroadcastsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDueBroadcastsStmt: %w", cerr)
+		}
+	}
+	if q.getEntryRulesByEventIDStmt != nil {
+		if cerr := q.getEntryRulesByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEntryRulesByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getEventByIDStmt != nil {
+		if cerr := q.getEventByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventByIDStmt: %w", cerr)
+		}
+	}
+	if q.getEventBySlugStmt != nil {
+		if cerr := q.getEventBySlugStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventBySlugStmt: %w", cerr)
+		}
+	}
+	if q.getEventCategoriesStmt != nil {
+		if cerr := q.getEventCategoriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventCategoriesStmt: %w", cerr)
+		}
+	}
+	if q.getEventForUpdateStmt != nil {
+		if cerr := q.getEventForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getEventImageStmt != nil {
+		if cerr := q.getEventImageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventImageStmt: %w", cerr)
+		}
+	}
+	if q.getEventOrganizersStmt != nil {
+		if cerr := q.getEventOrganizersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventOrganizersStmt: %w", cerr)
+		}
+	}
+	if q.getEventTagsStmt != nil {
+		if cerr := q.getEventTagsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventTagsStmt: %w", cerr)
+		}
+	}
+	if q.getEventTemplateStmt != nil {
+		if cerr := q.getEventTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventTemplateStmt: %w", cerr)
+		}
+	}
+	if q.getEventTemplateRulesStmt != nil {
+		if cerr := q.getEventTemplateRulesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventTemplateRulesStmt: %w", cerr)
+		}
+	}
+	if q.getEventTemplatesStmt != nil {
+		if cerr := q.getEventTemplatesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventTemplatesStmt: %w", cerr)
+		}
+	}
+	if q.getEventTranslationStmt != nil {
+		if cerr := q.getEventTranslationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventTranslationStmt: %w", cerr)
+		}
+	}
+	if q.getEventTranslationsStmt != nil {
+		if cerr := q.getEventTranslationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventTranslationsStmt: %w", cerr)
+		}
+	}
+	if q.getEventsStmt != nil {
+		if cerr := q.getEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventsStmt: %w", cerr)
+		}
+	}
+	if q.getEventsBetweenStmt != nil {
+		if cerr := q.getEventsBetweenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventsBetweenStmt: %w", cerr)
+		}
+	}
+	if q.getEventsPageStmt != nil {
+		if cerr := q.getEventsPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventsPageStmt: %w", cerr)
+		}
+	}
+	if q.getEventsToSyncToCalendarStmt != nil {
+		if cerr := q.getEventsToSyncToCalendarStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEventsToSyncToCalendarStmt: %w", cerr)
+		}
+	}
+	if q.getFeatureFlagsStmt != nil {
+		if cerr := q.getFeatureFlagsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFeatureFlagsStmt: %w", cerr)
+		}
+	}
+	if q.getFlaggedUsersStmt != nil {
+		if cerr := q.getFlaggedUsersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFlaggedUsersStmt: %w", cerr)
+		}
+	}
+	if q.getIdempotencyKeyStmt != nil {
+		if cerr := q.getIdempotencyKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getIdempotencyKeyStmt: %w", cerr)
+		}
+	}
+	if q.getLastEventStmt != nil {
+		if cerr := q.getLastEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLastEventStmt: %w", cerr)
+		}
+	}
+	if q.getLatestUsersByEventIDStmt != nil {
+		if cerr := q.getLatestUsersByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestUsersByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getNextWaitlistEntryStmt != nil {
+		if cerr := q.getNextWaitlistEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNextWaitlistEntryStmt: %w", cerr)
+		}
+	}
+	if q.getNotificationRecipientsStmt != nil {
+		if cerr := q.getNotificationRecipientsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNotificationRecipientsStmt: %w", cerr)
+		}
+	}
+	if q.getOrganizedEventIDsStmt != nil {
+		if cerr := q.getOrganizedEventIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOrganizedEventIDsStmt: %w", cerr)
+		}
+	}
+	if q.getPaymentsByEventIDStmt != nil {
+		if cerr := q.getPaymentsByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPaymentsByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getPromoCodeStmt != nil {
+		if cerr := q.getPromoCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPromoCodeStmt: %w", cerr)
+		}
+	}
+	if q.getPromoCodeStatsStmt != nil {
+		if cerr := q.getPromoCodeStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPromoCodeStatsStmt: %w", cerr)
+		}
+	}
+	if q.getPublicWinnersStmt != nil {
+		if cerr := q.getPublicWinnersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPublicWinnersStmt: %w", cerr)
+		}
+	}
+	if q.getRegistrationsSinceStmt != nil {
+		if cerr := q.getRegistrationsSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRegistrationsSinceStmt: %w", cerr)
+		}
+	}
+	if q.getSeatByLabelForUpdateStmt != nil {
+		if cerr := q.getSeatByLabelForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSeatByLabelForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getSeatByUserIDStmt != nil {
+		if cerr := q.getSeatByUserIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSeatByUserIDStmt: %w", cerr)
+		}
+	}
+	if q.getSeatsByEventIDStmt != nil {
+		if cerr := q.getSeatsByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSeatsByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getSessionStmt != nil {
+		if cerr := q.getSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSessionStmt: %w", cerr)
+		}
+	}
+	if q.getShareReportStmt != nil {
+		if cerr := q.getShareReportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getShareReportStmt: %w", cerr)
+		}
+	}
+	if q.getShortLinkByCodeStmt != nil {
+		if cerr := q.getShortLinkByCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getShortLinkByCodeStmt: %w", cerr)
+		}
+	}
+	if q.getShortLinksByEventIDStmt != nil {
+		if cerr := q.getShortLinksByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getShortLinksByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getTicketOrderStmt != nil {
+		if cerr := q.getTicketOrderStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTicketOrderStmt: %w", cerr)
+		}
+	}
+	if q.getTicketTypeStmt != nil {
+		if cerr := q.getTicketTypeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTicketTypeStmt: %w", cerr)
+		}
+	}
+	if q.getTicketTypeForUpdateStmt != nil {
+		if cerr := q.getTicketTypeForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTicketTypeForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getTicketTypeStatsStmt != nil {
+		if cerr := q.getTicketTypeStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTicketTypeStatsStmt: %w", cerr)
+		}
+	}
+	if q.getTicketTypesByEventIDStmt != nil {
+		if cerr := q.getTicketTypesByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTicketTypesByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getTokenByHashStmt != nil {
+		if cerr := q.getTokenByHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTokenByHashStmt: %w", cerr)
+		}
+	}
+	if q.getTokensByAdminIDStmt != nil {
+		if cerr := q.getTokensByAdminIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTokensByAdminIDStmt: %w", cerr)
+		}
+	}
+	if q.getTranslationsByLanguageStmt != nil {
+		if cerr := q.getTranslationsByLanguageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTranslationsByLanguageStmt: %w", cerr)
+		}
+	}
+	if q.getUserByCheckInCodeStmt != nil {
+		if cerr := q.getUserByCheckInCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserByCheckInCodeStmt: %w", cerr)
+		}
+	}
+	if q.getUserByIDStmt != nil {
+		if cerr := q.getUserByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserByIDStmt: %w", cerr)
+		}
+	}
+	if q.getUserByShareCodeStmt != nil {
+		if cerr := q.getUserByShareCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserByShareCodeStmt: %w", cerr)
+		}
+	}
+	if q.getUserByTgIDAndEventIDStmt != nil {
+		if cerr := q.getUserByTgIDAndEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserByTgIDAndEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getUserByTicketNumberStmt != nil {
+		if cerr := q.getUserByTicketNumberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserByTicketNumberStmt: %w", cerr)
+		}
+	}
+	if q.getUserByUsernameStmt != nil {
+		if cerr := q.getUserByUsernameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserByUsernameStmt: %w", cerr)
+		}
+	}
+	if q.getUsersByEventIDStmt != nil {
+		if cerr := q.getUsersByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUsersByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getUsersByEventIDAfterStmt != nil {
+		if cerr := q.getUsersByEventIDAfterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUsersByEventIDAfterStmt: %w", cerr)
+		}
+	}
+	if q.getUsersPageStmt != nil {
+		if cerr := q.getUsersPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUsersPageStmt: %w", cerr)
+		}
+	}
+	if q.getWaitlistByEventIDStmt != nil {
+		if cerr := q.getWaitlistByEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWaitlistByEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getWaitlistEntryStmt != nil {
+		if cerr := q.getWaitlistEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWaitlistEntryStmt: %w", cerr)
+		}
+	}
+	if q.getWaitlistEntryByTgIDStmt != nil {
+		if cerr := q.getWaitlistEntryByTgIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWaitlistEntryByTgIDStmt: %w", cerr)
+		}
+	}
+	if q.getWaitlistPositionStmt != nil {
+		if cerr := q.getWaitlistPositionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWaitlistPositionStmt: %w", cerr)
+		}
+	}
+	if q.grantShareBonusStmt != nil {
+		if cerr := q.grantShareBonusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing grantShareBonusStmt: %w", cerr)
+		}
+	}
+	if q.importDrawStmt != nil {
+		if cerr := q.importDrawStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importDrawStmt: %w", cerr)
+		}
+	}
+	if q.importUserStmt != nil {
+		if cerr := q.importUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importUserStmt: %w", cerr)
+		}
+	}
+	if q.lockEventForCalendarSyncStmt != nil {
+		if cerr := q.lockEventForCalendarSyncStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockEventForCalendarSyncStmt: %w", cerr)
+		}
+	}
+	if q.markBroadcastQueuedStmt != nil {
+		if cerr := q.markBroadcastQueuedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markBroadcastQueuedStmt: %w", cerr)
+		}
+	}
+	if q.markChatBlockedStmt != nil {
+		if cerr := q.markChatBlockedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markChatBlockedStmt: %w", cerr)
+		}
+	}
+	if q.markDigestSentStmt != nil {
+		if cerr := q.markDigestSentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markDigestSentStmt: %w", cerr)
+		}
+	}
+	if q.markEntryPurchaseFailedStmt != nil {
+		if cerr := q.markEntryPurchaseFailedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markEntryPurchaseFailedStmt: %w", cerr)
+		}
+	}
+	if q.markEntryPurchasePaidStmt != nil {
+		if cerr := q.markEntryPurchasePaidStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markEntryPurchasePaidStmt: %w", cerr)
+		}
+	}
+	if q.markEntryPurchaseRefundedStmt != nil {
+		if cerr := q.markEntryPurchaseRefundedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markEntryPurchaseRefundedStmt: %w", cerr)
+		}
+	}
+	if q.markOutboxMessageFailedStmt != nil {
+		if cerr := q.markOutboxMessageFailedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markOutboxMessageFailedStmt: %w", cerr)
+		}
+	}
+	if q.markOutboxMessageSentStmt != nil {
+		if cerr := q.markOutboxMessageSentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markOutboxMessageSentStmt: %w", cerr)
+		}
+	}
+	if q.markTicketOrderFailedStmt != nil {
+		if cerr := q.markTicketOrderFailedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markTicketOrderFailedStmt: %w", cerr)
+		}
+	}
+	if q.markTicketOrderPaidStmt != nil {
+		if cerr := q.markTicketOrderPaidStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markTicketOrderPaidStmt: %w", cerr)
+		}
+	}
+	if q.markTicketOrderRefundedStmt != nil {
+		if cerr := q.markTicketOrderRefundedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markTicketOrderRefundedStmt: %w", cerr)
+		}
+	}
+	if q.markWebhookFailedStmt != nil {
+		if cerr := q.markWebhookFailedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markWebhookFailedStmt: %w", cerr)
+		}
+	}
+	if q.markWebhookSentStmt != nil {
+		if cerr := q.markWebhookSentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markWebhookSentStmt: %w", cerr)
+		}
+	}
+	if q.pruneNotifyEventIDsStmt != nil {
+		if cerr := q.pruneNotifyEventIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing pruneNotifyEventIDsStmt: %w", cerr)
+		}
+	}
+	if q.purgeDeletedEventsBeforeStmt != nil {
+		if cerr := q.purgeDeletedEventsBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing purgeDeletedEventsBeforeStmt: %w", cerr)
+		}
+	}
+	if q.purgeDeletedUsersBeforeStmt != nil {
+		if cerr := q.purgeDeletedUsersBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing purgeDeletedUsersBeforeStmt: %w", cerr)
+		}
+	}
+	if q.recalculateEntryBonusesStmt != nil {
+		if cerr := q.recalculateEntryBonusesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing recalculateEntryBonusesStmt: %w", cerr)
+		}
+	}
+	if q.recordShareClickStmt != nil {
+		if cerr := q.recordShareClickStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing recordShareClickStmt: %w", cerr)
+		}
+	}
+	if q.releaseIdempotencyKeyStmt != nil {
+		if cerr := q.releaseIdempotencyKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releaseIdempotencyKeyStmt: %w", cerr)
+		}
+	}
+	if q.releasePromoRedemptionStmt != nil {
+		if cerr := q.releasePromoRedemptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releasePromoRedemptionStmt: %w", cerr)
+		}
+	}
+	if q.releaseSeatStmt != nil {
+		if cerr := q.releaseSeatStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releaseSeatStmt: %w", cerr)
+		}
+	}
+	if q.restoreEventStmt != nil {
+		if cerr := q.restoreEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreEventStmt: %w", cerr)
+		}
+	}
+	if q.restoreUserStmt != nil {
+		if cerr := q.restoreUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreUserStmt: %w", cerr)
+		}
+	}
+	if q.saveIdempotencyKeyStmt != nil {
+		if cerr := q.saveIdempotencyKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing saveIdempotencyKeyStmt: %w", cerr)
+		}
+	}
+	if q.saveSessionStmt != nil {
+		if cerr := q.saveSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing saveSessionStmt: %w", cerr)
+		}
+	}
+	if q.setAdminPasswordStmt != nil {
+		if cerr := q.setAdminPasswordStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setAdminPasswordStmt: %w", cerr)
+		}
+	}
+	if q.setBroadcastDeliveryStatusStmt != nil {
+		if cerr := q.setBroadcastDeliveryStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setBroadcastDeliveryStatusStmt: %w", cerr)
+		}
+	}
+	if q.setEventCalendarSyncedStmt != nil {
+		if cerr := q.setEventCalendarSyncedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventCalendarSyncedStmt: %w", cerr)
+		}
+	}
+	if q.setEventCapacityStmt != nil {
+		if cerr := q.setEventCapacityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventCapacityStmt: %w", cerr)
+		}
+	}
+	if q.setEventCategoriesStmt != nil {
+		if cerr := q.setEventCategoriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventCategoriesStmt: %w", cerr)
+		}
+	}
+	if q.setEventImageStmt != nil {
+		if cerr := q.setEventImageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventImageStmt: %w", cerr)
+		}
+	}
+	if q.setEventKioskTokenStmt != nil {
+		if cerr := q.setEventKioskTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventKioskTokenStmt: %w", cerr)
+		}
+	}
+	if q.setEventPaidEntriesStmt != nil {
+		if cerr := q.setEventPaidEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventPaidEntriesStmt: %w", cerr)
+		}
+	}
+	if q.setEventRegistrationWindowStmt != nil {
+		if cerr := q.setEventRegistrationWindowStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventRegistrationWindowStmt: %w", cerr)
+		}
+	}
+	if q.setEventSeatAssignmentStmt != nil {
+		if cerr := q.setEventSeatAssignmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventSeatAssignmentStmt: %w", cerr)
+		}
+	}
+	if q.setEventShareBonusStmt != nil {
+		if cerr := q.setEventShareBonusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventShareBonusStmt: %w", cerr)
+		}
+	}
+	if q.setEventShowWinnersStmt != nil {
+		if cerr := q.setEventShowWinnersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventShowWinnersStmt: %w", cerr)
+		}
+	}
+	if q.setEventSlugStmt != nil {
+		if cerr := q.setEventSlugStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventSlugStmt: %w", cerr)
+		}
+	}
+	if q.setEventStatusStmt != nil {
+		if cerr := q.setEventStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventStatusStmt: %w", cerr)
+		}
+	}
+	if q.setEventTicketPriceStmt != nil {
+		if cerr := q.setEventTicketPriceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventTicketPriceStmt: %w", cerr)
+		}
+	}
+	if q.setEventWaitlistAutoPromoteStmt != nil {
+		if cerr := q.setEventWaitlistAutoPromoteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEventWaitlistAutoPromoteStmt: %w", cerr)
+		}
+	}
+	if q.setFeatureFlagStmt != nil {
+		if cerr := q.setFeatureFlagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setFeatureFlagStmt: %w", cerr)
+		}
+	}
+	if q.setPromoRedemptionUserStmt != nil {
+		if cerr := q.setPromoRedemptionUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setPromoRedemptionUserStmt: %w", cerr)
+		}
+	}
+	if q.setSeatUserStmt != nil {
+		if cerr := q.setSeatUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setSeatUserStmt: %w", cerr)
+		}
+	}
+	if q.setTicketOrderUserStmt != nil {
+		if cerr := q.setTicketOrderUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setTicketOrderUserStmt: %w", cerr)
+		}
+	}
+	if q.setUserNotesStmt != nil {
+		if cerr := q.setUserNotesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setUserNotesStmt: %w", cerr)
+		}
+	}
+	if q.setUserShareCodeStmt != nil {
+		if cerr := q.setUserShareCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setUserShareCodeStmt: %w", cerr)
+		}
+	}
+	if q.setUserTagsStmt != nil {
+		if cerr := q.setUserTagsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setUserTagsStmt: %w", cerr)
+		}
+	}
+	if q.setUserTicketTypeStmt != nil {
+		if cerr := q.setUserTicketTypeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setUserTicketTypeStmt: %w", cerr)
+		}
+	}
+	if q.syncLastTicketNumberStmt != nil {
+		if cerr := q.syncLastTicketNumberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing syncLastTicketNumberStmt: %w", cerr)
+		}
+	}
+	if q.touchTokenStmt != nil {
+		if cerr := q.touchTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchTokenStmt: %w", cerr)
+		}
+	}
+	if q.unarchiveEventStmt != nil {
+		if cerr := q.unarchiveEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unarchiveEventStmt: %w", cerr)
+		}
+	}
+	if q.updateBroadcastStmt != nil {
+		if cerr := q.updateBroadcastStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateBroadcastStmt: %w", cerr)
+		}
+	}
+	if q.updateEventStmt != nil {
+		if cerr := q.updateEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateEventStmt: %w", cerr)
+		}
+	}
+	if q.updateEventTemplateStmt != nil {
+		if cerr := q.updateEventTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateEventTemplateStmt: %w", cerr)
+		}
+	}
+	if q.updateNotificationPreferencesStmt != nil {
+		if cerr := q.updateNotificationPreferencesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateNotificationPreferencesStmt: %w", cerr)
+		}
+	}
+	if q.updateUserNStmt != nil {
+		if cerr := q.updateUserNStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserNStmt: %w", cerr)
+		}
+	}
+	if q.updateUserProfileStmt != nil {
+		if cerr := q.updateUserProfileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserProfileStmt: %w", cerr)
+		}
+	}
+	if q.upsertEventTranslationStmt != nil {
+		if cerr := q.upsertEventTranslationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertEventTranslationStmt: %w", cerr)
+		}
+	}
+	if q.usePromoCodeStmt != nil {
+		if cerr := q.usePromoCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing usePromoCodeStmt: %w", cerr)
 		}
 	}
 	return err
@@ -170,39 +1794,445 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                            DBTX
-	tx                            *sql.Tx
-	createEventStmt               *sql.Stmt
-	createUserStmt                *sql.Stmt
-	deleteEventStmt               *sql.Stmt
-	deleteUserStmt                *sql.Stmt
-	deleteUsersByIdAndEventIdStmt *sql.Stmt
-	getEventByIDStmt              *sql.Stmt
-	getEventsStmt                 *sql.Stmt
-	getLastEventStmt              *sql.Stmt
-	getUserByIDStmt               *sql.Stmt
-	getUserByUsernameStmt         *sql.Stmt
-	getUsersByEventIDStmt         *sql.Stmt
-	updateEventStmt               *sql.Stmt
-	updateUserNStmt               *sql.Stmt
+	db                                DBTX
+	tx                                *sql.Tx
+	addToWaitlistStmt                 *sql.Stmt
+	addUserPaidEntriesStmt            *sql.Stmt
+	addUserPromoEntriesStmt           *sql.Stmt
+	approveUserStmt                   *sql.Stmt
+	archiveEventStmt                  *sql.Stmt
+	archiveEventsBeforeStmt           *sql.Stmt
+	assignFreeSeatStmt                *sql.Stmt
+	checkInUserStmt                   *sql.Stmt
+	claimIdempotencyKeyStmt           *sql.Stmt
+	claimOutboxMessagesStmt           *sql.Stmt
+	claimWebhooksStmt                 *sql.Stmt
+	clearChatBlockedStmt              *sql.Stmt
+	clickShortLinkStmt                *sql.Stmt
+	confirmUserAttendanceStmt         *sql.Stmt
+	consumeAdminLoginTokenStmt        *sql.Stmt
+	countEventTakenStmt               *sql.Stmt
+	countReservedPaidEntriesStmt      *sql.Stmt
+	countShareClicksStmt              *sql.Stmt
+	countShareClicksByVisitorStmt     *sql.Stmt
+	countTicketTypeTakenStmt          *sql.Stmt
+	countUsersByEventIDStmt           *sql.Stmt
+	countUsersReachStmt               *sql.Stmt
+	countUsersWithTagStmt             *sql.Stmt
+	createAdminStmt                   *sql.Stmt
+	createAdminLoginTokenStmt         *sql.Stmt
+	createAuditRecordStmt             *sql.Stmt
+	createBroadcastStmt               *sql.Stmt
+	createBroadcastDeliveryStmt       *sql.Stmt
+	createConsentRecordStmt           *sql.Stmt
+	createDigestSubscriptionStmt      *sql.Stmt
+	createDrawStmt                    *sql.Stmt
+	createDrawWinnerStmt              *sql.Stmt
+	createEntryPurchaseStmt           *sql.Stmt
+	createEntryRuleStmt               *sql.Stmt
+	createEventStmt                   *sql.Stmt
+	createEventOrganizerStmt          *sql.Stmt
+	createEventTemplateStmt           *sql.Stmt
+	createEventTemplateRuleStmt       *sql.Stmt
+	createPromoCodeStmt               *sql.Stmt
+	createPromoRedemptionStmt         *sql.Stmt
+	createSeatsStmt                   *sql.Stmt
+	createShortLinkStmt               *sql.Stmt
+	createTicketOrderStmt             *sql.Stmt
+	createTicketTypeStmt              *sql.Stmt
+	createTokenStmt                   *sql.Stmt
+	createUserStmt                    *sql.Stmt
+	createUsersBatchStmt              *sql.Stmt
+	deleteAdminStmt                   *sql.Stmt
+	deleteAdminLoginTokensBeforeStmt  *sql.Stmt
+	deleteBroadcastStmt               *sql.Stmt
+	deleteDigestSubscriptionStmt      *sql.Stmt
+	deleteEntryRuleStmt               *sql.Stmt
+	deleteEventStmt                   *sql.Stmt
+	deleteEventImageStmt              *sql.Stmt
+	deleteEventOrganizerStmt          *sql.Stmt
+	deleteEventTemplateStmt           *sql.Stmt
+	deleteEventTranslationStmt        *sql.Stmt
+	deleteIdempotencyKeysBeforeStmt   *sql.Stmt
+	deleteOtherAdminSessionsStmt      *sql.Stmt
+	deleteOutboxBeforeStmt            *sql.Stmt
+	deletePromoCodeStmt               *sql.Stmt
+	deleteSeatStmt                    *sql.Stmt
+	deleteSessionStmt                 *sql.Stmt
+	deleteSessionByIDStmt             *sql.Stmt
+	deleteSessionsBeforeStmt          *sql.Stmt
+	deleteShortLinkStmt               *sql.Stmt
+	deleteTicketTypeStmt              *sql.Stmt
+	deleteTokenStmt                   *sql.Stmt
+	deleteUserStmt                    *sql.Stmt
+	deleteUsersByIdAndEventIdStmt     *sql.Stmt
+	deleteWaitlistEntryStmt           *sql.Stmt
+	deleteWebhooksBeforeStmt          *sql.Stmt
+	enqueueOutboxMessageStmt          *sql.Stmt
+	enqueueWebhookStmt                *sql.Stmt
+	eventSlugTakenStmt                *sql.Stmt
+	flagSuspiciousUsersStmt           *sql.Stmt
+	getAdminByIDStmt                  *sql.Stmt
+	getAdminByUsernameStmt            *sql.Stmt
+	getAdminSessionsStmt              *sql.Stmt
+	getAdminsStmt                     *sql.Stmt
+	getAnomalyCountsStmt              *sql.Stmt
+	getAuditLogPageStmt               *sql.Stmt
+	getBroadcastByIDStmt              *sql.Stmt
+	getBroadcastDeliveriesStmt        *sql.Stmt
+	getBroadcastsByEventIDStmt        *sql.Stmt
+	getConsentLogByEventIDStmt        *sql.Stmt
+	getDeletedEventsStmt              *sql.Stmt
+	getDeletedUsersStmt               *sql.Stmt
+	getDigestSubscriptionStmt         *sql.Stmt
+	getDigestSubscriptionsStmt        *sql.Stmt
+	getDrawWinnersStmt                *sql.Stmt
+	getDrawsByEventIDStmt             *sql.Stmt
+	getDrawsSinceStmt                 *sql.Stmt
+	getDueBroadcastsStmt              *sql.Stmt
+	getEntryRulesByEventIDStmt        *sql.Stmt
+	getEventByIDStmt                  *sql.Stmt
+	getEventBySlugStmt                *sql.Stmt
+	getEventCategoriesStmt            *sql.Stmt
+	getEventForUpdateStmt             *sql.Stmt
+	getEventImageStmt                 *sql.Stmt
+	getEventOrganizersStmt            *sql.Stmt
+	getEventTagsStmt                  *sql.Stmt
+	getEventTemplateStmt              *sql.Stmt
+	getEventTemplateRulesStmt         *sql.Stmt
+	getEventTemplatesStmt             *sql.Stmt
+	getEventTranslationStmt           *sql.Stmt
+	getEventTranslationsStmt          *sql.Stmt
+	getEventsStmt                     *sql.Stmt
+	getEventsBetweenStmt              *sql.Stmt
+	getEventsPageStmt                 *sql.Stmt
+	getEventsToSyncToCalendarStmt     *sql.Stmt
+	getFeatureFlagsStmt               *sql.Stmt
+	getFlaggedUsersStmt               *sql.Stmt
+	getIdempotencyKeyStmt             *sql.Stmt
+	getLastEventStmt                  *sql.Stmt
+	getLatestUsersByEventIDStmt       *sql.Stmt
+	getNextWaitlistEntryStmt          *sql.Stmt
+	getNotificationRecipientsStmt     *sql.Stmt
+	getOrganizedEventIDsStmt          *sql.Stmt
+	getPaymentsByEventIDStmt          *sql.Stmt
+	getPromoCodeStmt                  *sql.Stmt
+	getPromoCodeStatsStmt             *sql.Stmt
+	getPublicWinnersStmt              *sql.Stmt
+	getRegistrationsSinceStmt         *sql.Stmt
+	getSeatByLabelForUpdateStmt       *sql.Stmt
+	getSeatByUserIDStmt               *sql.Stmt
+	getSeatsByEventIDStmt             *sql.Stmt
+	getSessionStmt                    *sql.Stmt
+	getShareReportStmt                *sql.Stmt
+	getShortLinkByCodeStmt            *sql.Stmt
+	getShortLinksByEventIDStmt        *sql.Stmt
+	getTicketOrderStmt                *sql.Stmt
+	getTicketTypeStmt                 *sql.Stmt
+	getTicketTypeForUpdateStmt        *sql.Stmt
+	getTicketTypeStatsStmt            *sql.Stmt
+	getTicketTypesByEventIDStmt       *sql.Stmt
+	getTokenByHashStmt                *sql.Stmt
+	getTokensByAdminIDStmt            *sql.Stmt
+	getTranslationsByLanguageStmt     *sql.Stmt
+	getUserByCheckInCodeStmt          *sql.Stmt
+	getUserByIDStmt                   *sql.Stmt
+	getUserByShareCodeStmt            *sql.Stmt
+	getUserByTgIDAndEventIDStmt       *sql.Stmt
+	getUserByTicketNumberStmt         *sql.Stmt
+	getUserByUsernameStmt             *sql.Stmt
+	getUsersByEventIDStmt             *sql.Stmt
+	getUsersByEventIDAfterStmt        *sql.Stmt
+	getUsersPageStmt                  *sql.Stmt
+	getWaitlistByEventIDStmt          *sql.Stmt
+	getWaitlistEntryStmt              *sql.Stmt
+	getWaitlistEntryByTgIDStmt        *sql.Stmt
+	getWaitlistPositionStmt           *sql.Stmt
+	grantShareBonusStmt               *sql.Stmt
+	importDrawStmt                    *sql.Stmt
+	importUserStmt                    *sql.Stmt
+	lockEventForCalendarSyncStmt      *sql.Stmt
+	markBroadcastQueuedStmt           *sql.Stmt
+	markChatBlockedStmt               *sql.Stmt
+	markDigestSentStmt                *sql.Stmt
+	markEntryPurchaseFailedStmt       *sql.Stmt
+	markEntryPurchasePaidStmt         *sql.Stmt
+	markEntryPurchaseRefundedStmt     *sql.Stmt
+	markOutboxMessageFailedStmt       *sql.Stmt
+	markOutboxMessageSentStmt         *sql.Stmt
+	markTicketOrderFailedStmt         *sql.Stmt
+	markTicketOrderPaidStmt           *sql.Stmt
+	markTicketOrderRefundedStmt       *sql.Stmt
+	markWebhookFailedStmt             *sql.Stmt
+	markWebhookSentStmt               *sql.Stmt
+	pruneNotifyEventIDsStmt           *sql.Stmt
+	purgeDeletedEventsBeforeStmt      *sql.Stmt
+	purgeDeletedUsersBeforeStmt       *sql.Stmt
+	recalculateEntryBonusesStmt       *sql.Stmt
+	recordShareClickStmt              *sql.Stmt
+	releaseIdempotencyKeyStmt         *sql.Stmt
+	releasePromoRedemptionStmt        *sql.Stmt
+	releaseSeatStmt                   *sql.Stmt
+	restoreEventStmt                  *sql.Stmt
+	restoreUserStmt                   *sql.Stmt
+	saveIdempotencyKeyStmt            *sql.Stmt
+	saveSessionStmt                   *sql.Stmt
+	setAdminPasswordStmt              *sql.Stmt
+	setBroadcastDeliveryStatusStmt    *sql.Stmt
+	setEventCalendarSyncedStmt        *sql.Stmt
+	setEventCapacityStmt              *sql.Stmt
+	setEventCategoriesStmt            *sql.Stmt
+	setEventImageStmt                 *sql.Stmt
+	setEventKioskTokenStmt            *sql.Stmt
+	setEventPaidEntriesStmt           *sql.Stmt
+	setEventRegistrationWindowStmt    *sql.Stmt
+	setEventSeatAssignmentStmt        *sql.Stmt
+	setEventShareBonusStmt            *sql.Stmt
+	setEventShowWinnersStmt           *sql.Stmt
+	setEventSlugStmt                  *sql.Stmt
+	setEventStatusStmt                *sql.Stmt
+	setEventTicketPriceStmt           *sql.Stmt
+	setEventWaitlistAutoPromoteStmt   *sql.Stmt
+	setFeatureFlagStmt                *sql.Stmt
+	setPromoRedemptionUserStmt        *sql.Stmt
+	setSeatUserStmt                   *sql.Stmt
+	setTicketOrderUserStmt            *sql.Stmt
+	setUserNotesStmt                  *sql.Stmt
+	setUserShareCodeStmt              *sql.Stmt
+	setUserTagsStmt                   *sql.Stmt
+	setUserTicketTypeStmt             *sql.Stmt
+	syncLastTicketNumberStmt          *sql.Stmt
+	touchTokenStmt                    *sql.Stmt
+	unarchiveEventStmt                *sql.Stmt
+	updateBroadcastStmt               *sql.Stmt
+	updateEventStmt                   *sql.Stmt
+	updateEventTemplateStmt           *sql.Stmt
+	updateNotificationPreferencesStmt *sql.Stmt
+	updateUserNStmt                   *sql.Stmt
+	updateUserProfileStmt             *sql.Stmt
+	upsertEventTranslationStmt        *sql.Stmt
+	usePromoCodeStmt                  *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                            tx,
-		tx:                            tx,
-		createEventStmt:               q.createEventStmt,
-		createUserStmt:                q.createUserStmt,
-		deleteEventStmt:               q.deleteEventStmt,
-		deleteUserStmt:                q.deleteUserStmt,
-		deleteUsersByIdAndEventIdStmt: q.deleteUsersByIdAndEventIdStmt,
-		getEventByIDStmt:              q.getEventByIDStmt,
-		getEventsStmt:                 q.getEventsStmt,
-		getLastEventStmt:              q.getLastEventStmt,
-		getUserByIDStmt:               q.getUserByIDStmt,
-		getUserByUsernameStmt:         q.getUserByUsernameStmt,
-		getUsersByEventIDStmt:         q.getUsersByEventIDStmt,
-		updateEventStmt:               q.updateEventStmt,
-		updateUserNStmt:               q.updateUserNStmt,
+		db:                                tx,
+		tx:                                tx,
+		addToWaitlistStmt:                 q.addToWaitlistStmt,
+		addUserPaidEntriesStmt:            q.addUserPaidEntriesStmt,
+		addUserPromoEntriesStmt:           q.addUserPromoEntriesStmt,
+		approveUserStmt:                   q.approveUserStmt,
+		archiveEventStmt:                  q.archiveEventStmt,
+		archiveEventsBeforeStmt:           q.archiveEventsBeforeStmt,
+		assignFreeSeatStmt:                q.assignFreeSeatStmt,
+		checkInUserStmt:                   q.checkInUserStmt,
+		claimIdempotencyKeyStmt:           q.claimIdempotencyKeyStmt,
+		claimOutboxMessagesStmt:           q.claimOutboxMessagesStmt,
+		claimWebhooksStmt:                 q.claimWebhooksStmt,
+		clearChatBlockedStmt:              q.clearChatBlockedStmt,
+		clickShortLinkStmt:                q.clickShortLinkStmt,
+		confirmUserAttendanceStmt:         q.confirmUserAttendanceStmt,
+		consumeAdminLoginTokenStmt:        q.consumeAdminLoginTokenStmt,
+		countEventTakenStmt:               q.countEventTakenStmt,
+		countReservedPaidEntriesStmt:      q.countReservedPaidEntriesStmt,
+		countShareClicksStmt:              q.countShareClicksStmt,
+		countShareClicksByVisitorStmt:     q.countShareClicksByVisitorStmt,
+		countTicketTypeTakenStmt:          q.countTicketTypeTakenStmt,
+		countUsersByEventIDStmt:           q.countUsersByEventIDStmt,
+		countUsersReachStmt:               q.countUsersReachStmt,
+		countUsersWithTagStmt:             q.countUsersWithTagStmt,
+		createAdminStmt:                   q.createAdminStmt,
+		createAdminLoginTokenStmt:         q.createAdminLoginTokenStmt,
+		createAuditRecordStmt:             q.createAuditRecordStmt,
+		createBroadcastStmt:               q.createBroadcastStmt,
+		createBroadcastDeliveryStmt:       q.createBroadcastDeliveryStmt,
+		createConsentRecordStmt:           q.createConsentRecordStmt,
+		createDigestSubscriptionStmt:      q.createDigestSubscriptionStmt,
+		createDrawStmt:                    q.createDrawStmt,
+		createDrawWinnerStmt:              q.createDrawWinnerStmt,
+		createEntryPurchaseStmt:           q.createEntryPurchaseStmt,
+		createEntryRuleStmt:               q.createEntryRuleStmt,
+		createEventStmt:                   q.createEventStmt,
+		createEventOrganizerStmt:          q.createEventOrganizerStmt,
+		createEventTemplateStmt:           q.createEventTemplateStmt,
+		createEventTemplateRuleStmt:       q.createEventTemplateRuleStmt,
+		createPromoCodeStmt:               q.createPromoCodeStmt,
+		createPromoRedemptionStmt:         q.createPromoRedemptionStmt,
+		createSeatsStmt:                   q.createSeatsStmt,
+		createShortLinkStmt:               q.createShortLinkStmt,
+		createTicketOrderStmt:             q.createTicketOrderStmt,
+		createTicketTypeStmt:              q.createTicketTypeStmt,
+		createTokenStmt:                   q.createTokenStmt,
+		createUserStmt:                    q.createUserStmt,
+		createUsersBatchStmt:              q.createUsersBatchStmt,
+		deleteAdminStmt:                   q.deleteAdminStmt,
+		deleteAdminLoginTokensBeforeStmt:  q.deleteAdminLoginTokensBeforeStmt,
+		deleteBroadcastStmt:               q.deleteBroadcastStmt,
+		deleteDigestSubscriptionStmt:      q.deleteDigestSubscriptionStmt,
+		deleteEntryRuleStmt:               q.deleteEntryRuleStmt,
+		deleteEventStmt:                   q.deleteEventStmt,
+		deleteEventImageStmt:              q.deleteEventImageStmt,
+		deleteEventOrganizerStmt:          q.deleteEventOrganizerStmt,
+		deleteEventTemplateStmt:           q.deleteEventTemplateStmt,
+		deleteEventTranslationStmt:        q.deleteEventTranslationStmt,
+		deleteIdempotencyKeysBeforeStmt:   q.deleteIdempotencyKeysBeforeStmt,
+		deleteOtherAdminSessionsStmt:      q.deleteOtherAdminSessionsStmt,
+		deleteOutboxBeforeStmt:            q.deleteOutboxBeforeStmt,
+		deletePromoCodeStmt:               q.deletePromoCodeStmt,
+		deleteSeatStmt:                    q.deleteSeatStmt,
+		deleteSessionStmt:                 q.deleteSessionStmt,
+		deleteSessionByIDStmt:             q.deleteSessionByIDStmt,
+		deleteSessionsBeforeStmt:          q.deleteSessionsBeforeStmt,
+		deleteShortLinkStmt:               q.deleteShortLinkStmt,
+		deleteTicketTypeStmt:              q.deleteTicketTypeStmt,
+		deleteTokenStmt:                   q.deleteTokenStmt,
+		deleteUserStmt:                    q.deleteUserStmt,
+		deleteUsersByIdAndEventIdStmt:     q.deleteUsersByIdAndEventIdStmt,
+		deleteWaitlistEntryStmt:           q.deleteWaitlistEntryStmt,
+		deleteWebhooksBeforeStmt:          q.deleteWebhooksBeforeStmt,
+		enqueueOutboxMessageStmt:          q.enqueueOutboxMessageStmt,
+		enqueueWebhookStmt:                q.enqueueWebhookStmt,
+		eventSlugTakenStmt:                q.eventSlugTakenStmt,
+		flagSuspiciousUsersStmt:           q.flagSuspiciousUsersStmt,
+		getAdminByIDStmt:                  q.getAdminByIDStmt,
+		getAdminByUsernameStmt:            q.getAdminByUsernameStmt,
+		getAdminSessionsStmt:              q.getAdminSessionsStmt,
+		getAdminsStmt:                     q.getAdminsStmt,
+		getAnomalyCountsStmt:              q.getAnomalyCountsStmt,
+		getAuditLogPageStmt:               q.getAuditLogPageStmt,
+		getBroadcastByIDStmt:              q.getBroadcastByIDStmt,
+		getBroadcastDeliveriesStmt:        q.getBroadcastDeliveriesStmt,
+		getBroadcastsByEventIDStmt:        q.getBroadcastsByEventIDStmt,
+		getConsentLogByEventIDStmt:        q.getConsentLogByEventIDStmt,
+		getDeletedEventsStmt:              q.getDeletedEventsStmt,
+		getDeletedUsersStmt:               q.getDeletedUsersStmt,
+		getDigestSubscriptionStmt:         q.getDigestSubscriptionStmt,
+		getDigestSubscriptionsStmt:        q.getDigestSubscriptionsStmt,
+		getDrawWinnersStmt:                q.getDrawWinnersStmt,
+		getDrawsByEventIDStmt:             q.getDrawsByEventIDStmt,
+		getDrawsSinceStmt:                 q.getDrawsSinceStmt,
+		getDueBroadcastsStmt:              q.getDueBroadcastsStmt,
+		getEntryRulesByEventIDStmt:        q.getEntryRulesByEventIDStmt,
+		getEventByIDStmt:                  q.getEventByIDStmt,
+		getEventBySlugStmt:                q.getEventBySlugStmt,
+		getEventCategoriesStmt:            q.getEventCategoriesStmt,
+		getEventForUpdateStmt:             q.getEventForUpdateStmt,
+		getEventImageStmt:                 q.getEventImageStmt,
+		getEventOrganizersStmt:            q.getEventOrganizersStmt,
+		getEventTagsStmt:                  q.getEventTagsStmt,
+		getEventTemplateStmt:              q.getEventTemplateStmt,
+		getEventTemplateRulesStmt:         q.getEventTemplateRulesStmt,
+		getEventTemplatesStmt:             q.getEventTemplatesStmt,
+		getEventTranslationStmt:           q.getEventTranslationStmt,
+		getEventTranslationsStmt:          q.getEventTranslationsStmt,
+		getEventsStmt:                     q.getEventsStmt,
+		getEventsBetweenStmt:              q.getEventsBetweenStmt,
+		getEventsPageStmt:                 q.getEventsPageStmt,
+		getEventsToSyncToCalendarStmt:     q.getEventsToSyncToCalendarStmt,
+		getFeatureFlagsStmt:               q.getFeatureFlagsStmt,
+		getFlaggedUsersStmt:               q.getFlaggedUsersStmt,
+		getIdempotencyKeyStmt:             q.getIdempotencyKeyStmt,
+		getLastEventStmt:                  q.getLastEventStmt,
+		getLatestUsersByEventIDStmt:       q.getLatestUsersByEventIDStmt,
+		getNextWaitlistEntryStmt:          q.getNextWaitlistEntryStmt,
+		getNotificationRecipientsStmt:     q.getNotificationRecipientsStmt,
+		getOrganizedEventIDsStmt:          q.getOrganizedEventIDsStmt,
+		getPaymentsByEventIDStmt:          q.getPaymentsByEventIDStmt,
+		getPromoCodeStmt:                  q.getPromoCodeStmt,
+		getPromoCodeStatsStmt:             q.getPromoCodeStatsStmt,
+		getPublicWinnersStmt:              q.getPublicWinnersStmt,
+		getRegistrationsSinceStmt:         q.getRegistrationsSinceStmt,
+		getSeatByLabelForUpdateStmt:       q.getSeatByLabelForUpdateStmt,
+		getSeatByUserIDStmt:               q.getSeatByUserIDStmt,
+		getSeatsByEventIDStmt:             q.getSeatsByEventIDStmt,
+		getSessionStmt:                    q.getSessionStmt,
+		getShareReportStmt:                q.getShareReportStmt,
+		getShortLinkByCodeStmt:            q.getShortLinkByCodeStmt,
+		getShortLinksByEventIDStmt:        q.getShortLinksByEventIDStmt,
+		getTicketOrderStmt:                q.getTicketOrderStmt,
+		getTicketTypeStmt:                 q.getTicketTypeStmt,
+		getTicketTypeForUpdateStmt:        q.getTicketTypeForUpdateStmt,
+		getTicketTypeStatsStmt:            q.getTicketTypeStatsStmt,
+		getTicketTypesByEventIDStmt:       q.getTicketTypesByEventIDStmt,
+		getTokenByHashStmt:                q.getTokenByHashStmt,
+		getTokensByAdminIDStmt:            q.getTokensByAdminIDStmt,
+		getTranslationsByLanguageStmt:     q.getTranslationsByLanguageStmt,
+		getUserByCheckInCodeStmt:          q.getUserByCheckInCodeStmt,
+		getUserByIDStmt:                   q.getUserByIDStmt,
+		getUserByShareCodeStmt:            q.getUserByShareCodeStmt,
+		getUserByTgIDAndEventIDStmt:       q.getUserByTgIDAndEventIDStmt,
+		getUserByTicketNumberStmt:         q.getUserByTicketNumberStmt,
+		getUserByUsernameStmt:             q.getUserByUsernameStmt,
+		getUsersByEventIDStmt:             q.getUsersByEventIDStmt,
+		getUsersByEventIDAfterStmt:        q.getUsersByEventIDAfterStmt,
+		getUsersPageStmt:                  q.getUsersPageStmt,
+		getWaitlistByEventIDStmt:          q.getWaitlistByEventIDStmt,
+		getWaitlistEntryStmt:              q.getWaitlistEntryStmt,
+		getWaitlistEntryByTgIDStmt:        q.getWaitlistEntryByTgIDStmt,
+		getWaitlistPositionStmt:           q.getWaitlistPositionStmt,
+		grantShareBonusStmt:               q.grantShareBonusStmt,
+		importDrawStmt:                    q.importDrawStmt,
+		importUserStmt:                    q.importUserStmt,
+		lockEventForCalendarSyncStmt:      q.lockEventForCalendarSyncStmt,
+		markBroadcastQueuedStmt:           q.markBroadcastQueuedStmt,
+		markChatBlockedStmt:               q.markChatBlockedStmt,
+		markDigestSentStmt:                q.markDigestSentStmt,
+		markEntryPurchaseFailedStmt:       q.markEntryPurchaseFailedStmt,
+		markEntryPurchasePaidStmt:         q.markEntryPurchasePaidStmt,
+		markEntryPurchaseRefundedStmt:     q.markEntryPurchaseRefundedStmt,
+		markOutboxMessageFailedStmt:       q.markOutboxMessageFailedStmt,
+		markOutboxMessageSentStmt:         q.markOutboxMessageSentStmt,
+		markTicketOrderFailedStmt:         q.markTicketOrderFailedStmt,
+		markTicketOrderPaidStmt:           q.markTicketOrderPaidStmt,
+		markTicketOrderRefundedStmt:       q.markTicketOrderRefundedStmt,
+		markWebhookFailedStmt:             q.markWebhookFailedStmt,
+		markWebhookSentStmt:               q.markWebhookSentStmt,
+		pruneNotifyEventIDsStmt:           q.pruneNotifyEventIDsStmt,
+		purgeDeletedEventsBeforeStmt:      q.purgeDeletedEventsBeforeStmt,
+		purgeDeletedUsersBeforeStmt:       q.purgeDeletedUsersBeforeStmt,
+		recalculateEntryBonusesStmt:       q.recalculateEntryBonusesStmt,
+		recordShareClickStmt:              q.recordShareClickStmt,
+		releaseIdempotencyKeyStmt:         q.releaseIdempotencyKeyStmt,
+		releasePromoRedemptionStmt:        q.releasePromoRedemptionStmt,
+		releaseSeatStmt:                   q.releaseSeatStmt,
+		restoreEventStmt:                  q.restoreEventStmt,
+		restoreUserStmt:                   q.restoreUserStmt,
+		saveIdempotencyKeyStmt:            q.saveIdempotencyKeyStmt,
+		saveSessionStmt:                   q.saveSessionStmt,
+		setAdminPasswordStmt:              q.setAdminPasswordStmt,
+		setBroadcastDeliveryStatusStmt:    q.setBroadcastDeliveryStatusStmt,
+		setEventCalendarSyncedStmt:        q.setEventCalendarSyncedStmt,
+		setEventCapacityStmt:              q.setEventCapacityStmt,
+		setEventCategoriesStmt:            q.setEventCategoriesStmt,
+		setEventImageStmt:                 q.setEventImageStmt,
+		setEventKioskTokenStmt:            q.setEventKioskTokenStmt,
+		setEventPaidEntriesStmt:           q.setEventPaidEntriesStmt,
+		setEventRegistrationWindowStmt:    q.setEventRegistrationWindowStmt,
+		setEventSeatAssignmentStmt:        q.setEventSeatAssignmentStmt,
+		setEventShareBonusStmt:            q.setEventShareBonusStmt,
+		setEventShowWinnersStmt:           q.setEventShowWinnersStmt,
+		setEventSlugStmt:                  q.setEventSlugStmt,
+		setEventStatusStmt:                q.setEventStatusStmt,
+		setEventTicketPriceStmt:           q.setEventTicketPriceStmt,
+		setEventWaitlistAutoPromoteStmt:   q.setEventWaitlistAutoPromoteStmt,
+		setFeatureFlagStmt:                q.setFeatureFlagStmt,
+		setPromoRedemptionUserStmt:        q.setPromoRedemptionUserStmt,
+		setSeatUserStmt:                   q.setSeatUserStmt,
+		setTicketOrderUserStmt:            q.setTicketOrderUserStmt,
+		setUserNotesStmt:                  q.setUserNotesStmt,
+		setUserShareCodeStmt:              q.setUserShareCodeStmt,
+		setUserTagsStmt:                   q.setUserTagsStmt,
+		setUserTicketTypeStmt:             q.setUserTicketTypeStmt,
+		syncLastTicketNumberStmt:          q.syncLastTicketNumberStmt,
+		touchTokenStmt:                    q.touchTokenStmt,
+		unarchiveEventStmt:                q.unarchiveEventStmt,
+		updateBroadcastStmt:               q.updateBroadcastStmt,
+		updateEventStmt:                   q.updateEventStmt,
+		updateEventTemplateStmt:           q.updateEventTemplateStmt,
+		updateNotificationPreferencesStmt: q.updateNotificationPreferencesStmt,
+		updateUserNStmt:                   q.updateUserNStmt,
+		updateUserProfileStmt:             q.updateUserProfileStmt,
+		upsertEventTranslationStmt:        q.upsertEventTranslationStmt,
+		usePromoCodeStmt:                  q.usePromoCodeStmt,
 	}
 }
@@ -9,8 +9,97 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
+const archiveEvent = `-- name: ArchiveEvent :one
+UPDATE events
+SET archived_at = COALESCE(archived_at, CURRENT_TIMESTAMP)
+WHERE id = $1
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+func (q *Queries) ArchiveEvent(ctx context.Context, id int64) (*Events, error) {
+	row := q.queryRow(ctx, q.archiveEventStmt, archiveEvent, id)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const archiveEventsBefore = `-- name: ArchiveEventsBefore :execrows
+UPDATE events
+SET archived_at = CURRENT_TIMESTAMP
+WHERE archived_at IS NULL
+AND unarchived_at IS NULL
+AND date < $1
+`
+
+// Archives events that took place before the cutoff, except those an admin
+// took out of the archive.
+func (q *Queries) ArchiveEventsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.exec(ctx, q.archiveEventsBeforeStmt, archiveEventsBefore, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countEventTaken = `-- name: CountEventTaken :one
+SELECT (
+    (SELECT COUNT(*) FROM users
+     WHERE users.event_id = $1::bigint
+     AND users.deleted_at IS NULL)
+    + (SELECT COUNT(*) FROM ticket_orders
+       WHERE ticket_orders.event_id = $1::bigint
+       AND status = 'pending'
+       AND created_at > CURRENT_TIMESTAMP - make_interval(secs => $2::int))
+)::int
+`
+
+type CountEventTakenParams struct {
+	ID             int64 `db:"id" json:"id"`
+	PendingSeconds int32 `db:"pending_seconds" json:"pending_seconds"`
+}
+
+// Registered participants of the event and its pending ticket orders,
+// which hold their place for a while like those of ticket types.
+func (q *Queries) CountEventTaken(ctx context.Context, arg *CountEventTakenParams) (int32, error) {
+	row := q.queryRow(ctx, q.countEventTakenStmt, countEventTaken, arg.ID, arg.PendingSeconds)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const createEvent = `-- name: CreateEvent :one
 INSERT INTO events (
     name, 
@@ -21,7 +110,7 @@ INSERT INTO events (
     $2,
     $3
 )
-RETURNING id, name, description, date, created_at
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
 `
 
 type CreateEventParams struct {
@@ -39,23 +128,130 @@ func (q *Queries) CreateEvent(ctx context.Context, arg *CreateEventParams) (*Eve
 		&i.Description,
 		&i.Date,
 		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
 	)
 	return &i, err
 }
 
 const deleteEvent = `-- name: DeleteEvent :exec
-DELETE FROM events
+UPDATE events
+SET deleted_at = CURRENT_TIMESTAMP
 WHERE id = $1
+AND deleted_at IS NULL
 `
 
+// Moves the event to the trash with its participants and draws.
 func (q *Queries) DeleteEvent(ctx context.Context, id int64) error {
 	_, err := q.exec(ctx, q.deleteEventStmt, deleteEvent, id)
 	return err
 }
 
+const eventSlugTaken = `-- name: EventSlugTaken :one
+SELECT EXISTS (
+    SELECT 1 FROM events
+    WHERE slug = $1::text
+    AND id <> $2
+)::boolean
+`
+
+type EventSlugTakenParams struct {
+	Slug string `db:"slug" json:"slug"`
+	ID   int64  `db:"id" json:"id"`
+}
+
+// Reports whether another event, deleted ones included, has the slug.
+func (q *Queries) EventSlugTaken(ctx context.Context, arg *EventSlugTakenParams) (bool, error) {
+	row := q.queryRow(ctx, q.eventSlugTakenStmt, eventSlugTaken, arg.Slug, arg.ID)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getDeletedEvents = `-- name: GetDeletedEvents :many
+SELECT id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug FROM events
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC
+`
+
+// The trash, most recently deleted first.
+func (q *Queries) GetDeletedEvents(ctx context.Context) ([]*Events, error) {
+	rows, err := q.query(ctx, q.getDeletedEventsStmt, getDeletedEvents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Events{}
+	for rows.Next() {
+		var i Events
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Date,
+			&i.CreatedAt,
+			&i.Version,
+			&i.WaitlistAutoPromote,
+			&i.LastTicketNumber,
+			&i.KioskToken,
+			&i.MaxPaidEntries,
+			&i.EntryPrice,
+			&i.ShareClicksRequired,
+			&i.ShareBonus,
+			&i.ShowWinners,
+			&i.ArchivedAt,
+			&i.CalendarEventID,
+			&i.CalendarSyncedVersion,
+			&i.TicketPrice,
+			&i.SeatAssignment,
+			&i.UnarchivedAt,
+			&i.DeletedAt,
+			pq.Array(&i.Categories),
+			&i.ImageUpdatedAt,
+			&i.Capacity,
+			&i.Status,
+			&i.RegistrationOpensAt,
+			&i.RegistrationClosesAt,
+			&i.Slug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getEventByID = `-- name: GetEventByID :one
-SELECT id, name, description, date, created_at FROM events
-WHERE id = $1
+SELECT id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug FROM events
+WHERE events.id = $1
+AND deleted_at IS NULL
 `
 
 func (q *Queries) GetEventByID(ctx context.Context, id int64) (*Events, error) {
@@ -67,12 +263,156 @@ func (q *Queries) GetEventByID(ctx context.Context, id int64) (*Events, error) {
 		&i.Description,
 		&i.Date,
 		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const getEventBySlug = `-- name: GetEventBySlug :one
+SELECT id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug FROM events
+WHERE slug = $1::text
+AND deleted_at IS NULL
+`
+
+func (q *Queries) GetEventBySlug(ctx context.Context, slug string) (*Events, error) {
+	row := q.queryRow(ctx, q.getEventBySlugStmt, getEventBySlug, slug)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const getEventCategories = `-- name: GetEventCategories :many
+SELECT DISTINCT category::text FROM events, unnest(events.categories) AS category
+WHERE (events.archived_at IS NOT NULL) = $1::boolean
+AND events.deleted_at IS NULL
+ORDER BY category
+`
+
+// The categories used by the active events or the archive, for filtering
+// the dashboard.
+func (q *Queries) GetEventCategories(ctx context.Context, archived bool) ([]string, error) {
+	rows, err := q.query(ctx, q.getEventCategoriesStmt, getEventCategories, archived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var category string
+		if err := rows.Scan(&category); err != nil {
+			return nil, err
+		}
+		items = append(items, category)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEventForUpdate = `-- name: GetEventForUpdate :one
+SELECT id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug FROM events
+WHERE id = $1
+AND deleted_at IS NULL
+FOR UPDATE
+`
+
+// Locks the event while a participant is registered, so concurrent
+// registrations can't go over its capacity.
+func (q *Queries) GetEventForUpdate(ctx context.Context, id int64) (*Events, error) {
+	row := q.queryRow(ctx, q.getEventForUpdateStmt, getEventForUpdate, id)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
 	)
 	return &i, err
 }
 
 const getEvents = `-- name: GetEvents :many
-SELECT id, name, description, date, created_at FROM events ORDER BY created_at DESC
+SELECT id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug FROM events
+WHERE deleted_at IS NULL
+ORDER BY created_at DESC
 `
 
 func (q *Queries) GetEvents(ctx context.Context) ([]*Events, error) {
@@ -90,6 +430,171 @@ func (q *Queries) GetEvents(ctx context.Context) ([]*Events, error) {
 			&i.Description,
 			&i.Date,
 			&i.CreatedAt,
+			&i.Version,
+			&i.WaitlistAutoPromote,
+			&i.LastTicketNumber,
+			&i.KioskToken,
+			&i.MaxPaidEntries,
+			&i.EntryPrice,
+			&i.ShareClicksRequired,
+			&i.ShareBonus,
+			&i.ShowWinners,
+			&i.ArchivedAt,
+			&i.CalendarEventID,
+			&i.CalendarSyncedVersion,
+			&i.TicketPrice,
+			&i.SeatAssignment,
+			&i.UnarchivedAt,
+			&i.DeletedAt,
+			pq.Array(&i.Categories),
+			&i.ImageUpdatedAt,
+			&i.Capacity,
+			&i.Status,
+			&i.RegistrationOpensAt,
+			&i.RegistrationClosesAt,
+			&i.Slug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEventsPage = `-- name: GetEventsPage :many
+SELECT id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug FROM events
+WHERE (archived_at IS NOT NULL) = $1::boolean
+AND deleted_at IS NULL
+AND ($2::text = '' OR $2::text = ANY(categories))
+AND ($3::bigint = 0 OR id IN (
+    SELECT event_id FROM event_organizers WHERE admin_id = $3::bigint
+))
+ORDER BY created_at DESC, id DESC
+LIMIT $5::int OFFSET $4::int
+`
+
+type GetEventsPageParams struct {
+	Archived    bool   `db:"archived" json:"archived"`
+	Category    string `db:"category" json:"category"`
+	OrganizerID int64  `db:"organizer_id" json:"organizer_id"`
+	PageOffset  int32  `db:"page_offset" json:"page_offset"`
+	PageSize    int32  `db:"page_size" json:"page_size"`
+}
+
+// A page of the dashboard, which lists either the active events or the
+// archive. An empty category lists every event, and an organizer_id of 0
+// the events of every organizer.
+func (q *Queries) GetEventsPage(ctx context.Context, arg *GetEventsPageParams) ([]*Events, error) {
+	rows, err := q.query(ctx, q.getEventsPageStmt, getEventsPage,
+		arg.Archived,
+		arg.Category,
+		arg.OrganizerID,
+		arg.PageOffset,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Events{}
+	for rows.Next() {
+		var i Events
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Date,
+			&i.CreatedAt,
+			&i.Version,
+			&i.WaitlistAutoPromote,
+			&i.LastTicketNumber,
+			&i.KioskToken,
+			&i.MaxPaidEntries,
+			&i.EntryPrice,
+			&i.ShareClicksRequired,
+			&i.ShareBonus,
+			&i.ShowWinners,
+			&i.ArchivedAt,
+			&i.CalendarEventID,
+			&i.CalendarSyncedVersion,
+			&i.TicketPrice,
+			&i.SeatAssignment,
+			&i.UnarchivedAt,
+			&i.DeletedAt,
+			pq.Array(&i.Categories),
+			&i.ImageUpdatedAt,
+			&i.Capacity,
+			&i.Status,
+			&i.RegistrationOpensAt,
+			&i.RegistrationClosesAt,
+			&i.Slug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEventsToSyncToCalendar = `-- name: GetEventsToSyncToCalendar :many
+SELECT id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug FROM events
+WHERE calendar_synced_version <> version
+AND archived_at IS NULL
+AND deleted_at IS NULL
+ORDER BY id
+`
+
+// Lists events changed since they were last pushed to the calendar.
+func (q *Queries) GetEventsToSyncToCalendar(ctx context.Context) ([]*Events, error) {
+	rows, err := q.query(ctx, q.getEventsToSyncToCalendarStmt, getEventsToSyncToCalendar)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Events{}
+	for rows.Next() {
+		var i Events
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Date,
+			&i.CreatedAt,
+			&i.Version,
+			&i.WaitlistAutoPromote,
+			&i.LastTicketNumber,
+			&i.KioskToken,
+			&i.MaxPaidEntries,
+			&i.EntryPrice,
+			&i.ShareClicksRequired,
+			&i.ShareBonus,
+			&i.ShowWinners,
+			&i.ArchivedAt,
+			&i.CalendarEventID,
+			&i.CalendarSyncedVersion,
+			&i.TicketPrice,
+			&i.SeatAssignment,
+			&i.UnarchivedAt,
+			&i.DeletedAt,
+			pq.Array(&i.Categories),
+			&i.ImageUpdatedAt,
+			&i.Capacity,
+			&i.Status,
+			&i.RegistrationOpensAt,
+			&i.RegistrationClosesAt,
+			&i.Slug,
 		); err != nil {
 			return nil, err
 		}
@@ -105,9 +610,10 @@ func (q *Queries) GetEvents(ctx context.Context) ([]*Events, error) {
 }
 
 const getLastEvent = `-- name: GetLastEvent :one
-SELECT id, name, description, date, created_at FROM events
+SELECT id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug FROM events
 WHERE id = (
     SELECT id FROM events
+    WHERE deleted_at IS NULL
     ORDER BY created_at DESC
     LIMIT 1
 )
@@ -122,6 +628,800 @@ func (q *Queries) GetLastEvent(ctx context.Context) (*Events, error) {
 		&i.Description,
 		&i.Date,
 		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const lockEventForCalendarSync = `-- name: LockEventForCalendarSync :one
+SELECT id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug FROM events
+WHERE id = $1
+AND calendar_synced_version <> version
+AND deleted_at IS NULL
+FOR UPDATE SKIP LOCKED
+`
+
+// Returns the event if it still needs syncing and no other instance is
+// syncing it, holding it until the transaction ends.
+func (q *Queries) LockEventForCalendarSync(ctx context.Context, id int64) (*Events, error) {
+	row := q.queryRow(ctx, q.lockEventForCalendarSyncStmt, lockEventForCalendarSync, id)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const purgeDeletedEventsBefore = `-- name: PurgeDeletedEventsBefore :execrows
+DELETE FROM events
+WHERE deleted_at < $1::timestamp
+`
+
+// Removes for good the events deleted before the cutoff.
+func (q *Queries) PurgeDeletedEventsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.exec(ctx, q.purgeDeletedEventsBeforeStmt, purgeDeletedEventsBefore, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreEvent = `-- name: RestoreEvent :one
+UPDATE events
+SET deleted_at = NULL,
+    calendar_event_id = NULL,
+    version = version + 1
+WHERE id = $1
+AND deleted_at IS NOT NULL
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+// Takes the event out of the trash. It was removed from the calendar on
+// deletion, so it's pushed there again as a new calendar event.
+func (q *Queries) RestoreEvent(ctx context.Context, id int64) (*Events, error) {
+	row := q.queryRow(ctx, q.restoreEventStmt, restoreEvent, id)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventCalendarSynced = `-- name: SetEventCalendarSynced :exec
+UPDATE events
+SET calendar_event_id = $1,
+    calendar_synced_version = $2
+WHERE id = $3
+`
+
+type SetEventCalendarSyncedParams struct {
+	CalendarEventID       sql.NullString `db:"calendar_event_id" json:"calendar_event_id"`
+	CalendarSyncedVersion int32          `db:"calendar_synced_version" json:"calendar_synced_version"`
+	ID                    int64          `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventCalendarSynced(ctx context.Context, arg *SetEventCalendarSyncedParams) error {
+	_, err := q.exec(ctx, q.setEventCalendarSyncedStmt, setEventCalendarSynced, arg.CalendarEventID, arg.CalendarSyncedVersion, arg.ID)
+	return err
+}
+
+const setEventCapacity = `-- name: SetEventCapacity :one
+UPDATE events
+SET capacity = $1
+WHERE id = $2
+AND deleted_at IS NULL
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventCapacityParams struct {
+	Capacity int32 `db:"capacity" json:"capacity"`
+	ID       int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventCapacity(ctx context.Context, arg *SetEventCapacityParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventCapacityStmt, setEventCapacity, arg.Capacity, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventCategories = `-- name: SetEventCategories :one
+UPDATE events
+SET categories = $1::text[]
+WHERE id = $2
+AND deleted_at IS NULL
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventCategoriesParams struct {
+	Categories []string `db:"categories" json:"categories"`
+	ID         int64    `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventCategories(ctx context.Context, arg *SetEventCategoriesParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventCategoriesStmt, setEventCategories, pq.Array(arg.Categories), arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventKioskToken = `-- name: SetEventKioskToken :one
+UPDATE events
+SET kiosk_token = $1
+WHERE id = $2
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventKioskTokenParams struct {
+	KioskToken sql.NullString `db:"kiosk_token" json:"kiosk_token"`
+	ID         int64          `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventKioskToken(ctx context.Context, arg *SetEventKioskTokenParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventKioskTokenStmt, setEventKioskToken, arg.KioskToken, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventPaidEntries = `-- name: SetEventPaidEntries :one
+UPDATE events
+SET max_paid_entries = $1,
+    entry_price = $2
+WHERE id = $3
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventPaidEntriesParams struct {
+	MaxPaidEntries int32 `db:"max_paid_entries" json:"max_paid_entries"`
+	EntryPrice     int32 `db:"entry_price" json:"entry_price"`
+	ID             int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventPaidEntries(ctx context.Context, arg *SetEventPaidEntriesParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventPaidEntriesStmt, setEventPaidEntries, arg.MaxPaidEntries, arg.EntryPrice, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventRegistrationWindow = `-- name: SetEventRegistrationWindow :one
+UPDATE events
+SET registration_opens_at = $1,
+    registration_closes_at = $2
+WHERE id = $3
+AND deleted_at IS NULL
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventRegistrationWindowParams struct {
+	RegistrationOpensAt  sql.NullTime `db:"registration_opens_at" json:"registration_opens_at"`
+	RegistrationClosesAt sql.NullTime `db:"registration_closes_at" json:"registration_closes_at"`
+	ID                   int64        `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventRegistrationWindow(ctx context.Context, arg *SetEventRegistrationWindowParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventRegistrationWindowStmt, setEventRegistrationWindow, arg.RegistrationOpensAt, arg.RegistrationClosesAt, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventSeatAssignment = `-- name: SetEventSeatAssignment :one
+UPDATE events
+SET seat_assignment = $1
+WHERE id = $2
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventSeatAssignmentParams struct {
+	SeatAssignment string `db:"seat_assignment" json:"seat_assignment"`
+	ID             int64  `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventSeatAssignment(ctx context.Context, arg *SetEventSeatAssignmentParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventSeatAssignmentStmt, setEventSeatAssignment, arg.SeatAssignment, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventShareBonus = `-- name: SetEventShareBonus :one
+UPDATE events
+SET share_clicks_required = $1,
+    share_bonus = $2
+WHERE id = $3
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventShareBonusParams struct {
+	ShareClicksRequired int32 `db:"share_clicks_required" json:"share_clicks_required"`
+	ShareBonus          int32 `db:"share_bonus" json:"share_bonus"`
+	ID                  int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventShareBonus(ctx context.Context, arg *SetEventShareBonusParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventShareBonusStmt, setEventShareBonus, arg.ShareClicksRequired, arg.ShareBonus, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventShowWinners = `-- name: SetEventShowWinners :one
+UPDATE events
+SET show_winners = $1
+WHERE id = $2
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventShowWinnersParams struct {
+	ShowWinners bool  `db:"show_winners" json:"show_winners"`
+	ID          int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventShowWinners(ctx context.Context, arg *SetEventShowWinnersParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventShowWinnersStmt, setEventShowWinners, arg.ShowWinners, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventSlug = `-- name: SetEventSlug :one
+UPDATE events
+SET slug = $1::text
+WHERE id = $2
+AND deleted_at IS NULL
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventSlugParams struct {
+	Slug string `db:"slug" json:"slug"`
+	ID   int64  `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventSlug(ctx context.Context, arg *SetEventSlugParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventSlugStmt, setEventSlug, arg.Slug, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventStatus = `-- name: SetEventStatus :one
+UPDATE events
+SET status = $1
+WHERE id = $2
+AND deleted_at IS NULL
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventStatusParams struct {
+	Status string `db:"status" json:"status"`
+	ID     int64  `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventStatus(ctx context.Context, arg *SetEventStatusParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventStatusStmt, setEventStatus, arg.Status, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventTicketPrice = `-- name: SetEventTicketPrice :one
+UPDATE events
+SET ticket_price = $1
+WHERE id = $2
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventTicketPriceParams struct {
+	TicketPrice int32 `db:"ticket_price" json:"ticket_price"`
+	ID          int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventTicketPrice(ctx context.Context, arg *SetEventTicketPriceParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventTicketPriceStmt, setEventTicketPrice, arg.TicketPrice, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const setEventWaitlistAutoPromote = `-- name: SetEventWaitlistAutoPromote :one
+UPDATE events
+SET waitlist_auto_promote = $1
+WHERE id = $2
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+type SetEventWaitlistAutoPromoteParams struct {
+	WaitlistAutoPromote bool  `db:"waitlist_auto_promote" json:"waitlist_auto_promote"`
+	ID                  int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEventWaitlistAutoPromote(ctx context.Context, arg *SetEventWaitlistAutoPromoteParams) (*Events, error) {
+	row := q.queryRow(ctx, q.setEventWaitlistAutoPromoteStmt, setEventWaitlistAutoPromote, arg.WaitlistAutoPromote, arg.ID)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
+	)
+	return &i, err
+}
+
+const syncLastTicketNumber = `-- name: SyncLastTicketNumber :exec
+UPDATE events
+SET last_ticket_number = GREATEST(last_ticket_number, (
+    SELECT COALESCE(MAX(ticket_number), 0) FROM users WHERE users.event_id = events.id
+))
+WHERE events.id = $1
+`
+
+// Continues ticket numbering after the highest ticket in the event.
+func (q *Queries) SyncLastTicketNumber(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.syncLastTicketNumberStmt, syncLastTicketNumber, id)
+	return err
+}
+
+const unarchiveEvent = `-- name: UnarchiveEvent :one
+UPDATE events
+SET archived_at = NULL,
+    unarchived_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
+`
+
+func (q *Queries) UnarchiveEvent(ctx context.Context, id int64) (*Events, error) {
+	row := q.queryRow(ctx, q.unarchiveEventStmt, unarchiveEvent, id)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Date,
+		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
 	)
 	return &i, err
 }
@@ -130,9 +1430,11 @@ const updateEvent = `-- name: UpdateEvent :one
 UPDATE events
 SET name = $1,
     description = $2,
-    date = COALESCE($3, date)
+    date = COALESCE($3, date),
+    version = version + 1
 WHERE id = $4
-RETURNING id, name, description, date, created_at
+AND version = $5
+RETURNING id, name, description, date, created_at, version, waitlist_auto_promote, last_ticket_number, kiosk_token, max_paid_entries, entry_price, share_clicks_required, share_bonus, show_winners, archived_at, calendar_event_id, calendar_synced_version, ticket_price, seat_assignment, unarchived_at, deleted_at, categories, image_updated_at, capacity, status, registration_opens_at, registration_closes_at, slug
 `
 
 type UpdateEventParams struct {
@@ -140,6 +1442,7 @@ type UpdateEventParams struct {
 	Description sql.NullString `db:"description" json:"description"`
 	Date        time.Time      `db:"date" json:"date"`
 	ID          int64          `db:"id" json:"id"`
+	Version     int32          `db:"version" json:"version"`
 }
 
 func (q *Queries) UpdateEvent(ctx context.Context, arg *UpdateEventParams) (*Events, error) {
@@ -148,6 +1451,7 @@ func (q *Queries) UpdateEvent(ctx context.Context, arg *UpdateEventParams) (*Eve
 		arg.Description,
 		arg.Date,
 		arg.ID,
+		arg.Version,
 	)
 	var i Events
 	err := row.Scan(
@@ -156,6 +1460,29 @@ func (q *Queries) UpdateEvent(ctx context.Context, arg *UpdateEventParams) (*Eve
 		&i.Description,
 		&i.Date,
 		&i.CreatedAt,
+		&i.Version,
+		&i.WaitlistAutoPromote,
+		&i.LastTicketNumber,
+		&i.KioskToken,
+		&i.MaxPaidEntries,
+		&i.EntryPrice,
+		&i.ShareClicksRequired,
+		&i.ShareBonus,
+		&i.ShowWinners,
+		&i.ArchivedAt,
+		&i.CalendarEventID,
+		&i.CalendarSyncedVersion,
+		&i.TicketPrice,
+		&i.SeatAssignment,
+		&i.UnarchivedAt,
+		&i.DeletedAt,
+		pq.Array(&i.Categories),
+		&i.ImageUpdatedAt,
+		&i.Capacity,
+		&i.Status,
+		&i.RegistrationOpensAt,
+		&i.RegistrationClosesAt,
+		&i.Slug,
 	)
 	return &i, err
 }
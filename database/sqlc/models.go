@@ -6,23 +6,370 @@ package sqlc
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
-type Events struct {
+type AdminLoginTokens struct {
+	Nonce     string    `db:"nonce" json:"nonce"`
+	AdminID   int64     `db:"admin_id" json:"admin_id"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+}
+
+type Admins struct {
+	ID                 int64          `db:"id" json:"id"`
+	Username           string         `db:"username" json:"username"`
+	Role               string         `db:"role" json:"role"`
+	ChatID             sql.NullInt64  `db:"chat_id" json:"chat_id"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	PasswordHash       sql.NullString `db:"password_hash" json:"password_hash"`
+	MustChangePassword bool           `db:"must_change_password" json:"must_change_password"`
+}
+
+type AuditLog struct {
+	ID        int64           `db:"id" json:"id"`
+	Actor     string          `db:"actor" json:"actor"`
+	Action    string          `db:"action" json:"action"`
+	EventID   sql.NullInt64   `db:"event_id" json:"event_id"`
+	Diff      json.RawMessage `db:"diff" json:"diff"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+type BroadcastDeliveries struct {
 	ID          int64          `db:"id" json:"id"`
-	Name        string         `db:"name" json:"name"`
-	Description sql.NullString `db:"description" json:"description"`
-	Date        time.Time      `db:"date" json:"date"`
-	CreatedAt   sql.NullTime   `db:"created_at" json:"created_at"`
+	BroadcastID int64          `db:"broadcast_id" json:"broadcast_id"`
+	UserID      sql.NullInt64  `db:"user_id" json:"user_id"`
+	ChatID      int64          `db:"chat_id" json:"chat_id"`
+	Text        string         `db:"text" json:"text"`
+	Status      string         `db:"status" json:"status"`
+	Error       sql.NullString `db:"error" json:"error"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
 }
 
-type Users struct {
+type Broadcasts struct {
 	ID        int64        `db:"id" json:"id"`
+	EventID   int64        `db:"event_id" json:"event_id"`
+	Text      string       `db:"text" json:"text"`
+	SendAt    time.Time    `db:"send_at" json:"send_at"`
+	QueuedAt  sql.NullTime `db:"queued_at" json:"queued_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
+
+type ConsentLog struct {
+	ID           int64         `db:"id" json:"id"`
+	EventID      int64         `db:"event_id" json:"event_id"`
+	UserID       sql.NullInt64 `db:"user_id" json:"user_id"`
+	TicketNumber int32         `db:"ticket_number" json:"ticket_number"`
+	Action       string        `db:"action" json:"action"`
+	Source       string        `db:"source" json:"source"`
+	Version      string        `db:"version" json:"version"`
+	CreatedAt    time.Time     `db:"created_at" json:"created_at"`
+}
+
+type DigestSubscriptions struct {
+	ID               int64        `db:"id" json:"id"`
+	Name             string       `db:"name" json:"name"`
+	ChatID           int64        `db:"chat_id" json:"chat_id"`
+	Weekday          int32        `db:"weekday" json:"weekday"`
+	Hour             int32        `db:"hour" json:"hour"`
+	IncludeAnomalies bool         `db:"include_anomalies" json:"include_anomalies"`
+	LastSentAt       sql.NullTime `db:"last_sent_at" json:"last_sent_at"`
+	CreatedAt        time.Time    `db:"created_at" json:"created_at"`
+	WeeklyDigest     bool         `db:"weekly_digest" json:"weekly_digest"`
+	NotifyKinds      []string     `db:"notify_kinds" json:"notify_kinds"`
+	NotifyEventIds   []int64      `db:"notify_event_ids" json:"notify_event_ids"`
+}
+
+type DrawWinners struct {
+	DrawID   int64 `db:"draw_id" json:"draw_id"`
+	UserID   int64 `db:"user_id" json:"user_id"`
+	Position int32 `db:"position" json:"position"`
+}
+
+type Draws struct {
+	ID           int64          `db:"id" json:"id"`
+	EventID      int64          `db:"event_id" json:"event_id"`
+	WinnersCount int32          `db:"winners_count" json:"winners_count"`
+	CreatedAt    sql.NullTime   `db:"created_at" json:"created_at"`
+	Seed         sql.NullString `db:"seed" json:"seed"`
+}
+
+type EntryPurchases struct {
+	ID         int64         `db:"id" json:"id"`
+	OrderID    string        `db:"order_id" json:"order_id"`
+	UserID     sql.NullInt64 `db:"user_id" json:"user_id"`
+	EventID    int64         `db:"event_id" json:"event_id"`
+	Entries    int32         `db:"entries" json:"entries"`
+	Amount     int32         `db:"amount" json:"amount"`
+	Status     string        `db:"status" json:"status"`
+	CreatedAt  time.Time     `db:"created_at" json:"created_at"`
+	PaidAt     sql.NullTime  `db:"paid_at" json:"paid_at"`
+	PaymentID  string        `db:"payment_id" json:"payment_id"`
+	RefundedAt sql.NullTime  `db:"refunded_at" json:"refunded_at"`
+}
+
+type EntryRules struct {
+	ID               int64         `db:"id" json:"id"`
+	EventID          int64         `db:"event_id" json:"event_id"`
+	Name             string        `db:"name" json:"name"`
+	MaxTicket        sql.NullInt32 `db:"max_ticket" json:"max_ticket"`
+	RegisteredBefore sql.NullTime  `db:"registered_before" json:"registered_before"`
+	Bonus            int32         `db:"bonus" json:"bonus"`
+	CreatedAt        time.Time     `db:"created_at" json:"created_at"`
+}
+
+type EventImages struct {
+	EventID     int64     `db:"event_id" json:"event_id"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Data        []byte    `db:"data" json:"data"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type EventOrganizers struct {
+	ID             int64         `db:"id" json:"id"`
+	EventID        int64         `db:"event_id" json:"event_id"`
+	Name           string        `db:"name" json:"name"`
+	Username       string        `db:"username" json:"username"`
+	Responsibility string        `db:"responsibility" json:"responsibility"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+	AdminID        sql.NullInt64 `db:"admin_id" json:"admin_id"`
+}
+
+type EventTemplateRules struct {
+	ID                 int64         `db:"id" json:"id"`
+	TemplateID         int64         `db:"template_id" json:"template_id"`
+	Name               string        `db:"name" json:"name"`
+	MaxTicket          sql.NullInt32 `db:"max_ticket" json:"max_ticket"`
+	SecondsBeforeEvent sql.NullInt32 `db:"seconds_before_event" json:"seconds_before_event"`
+	Bonus              int32         `db:"bonus" json:"bonus"`
+}
+
+type EventTemplates struct {
+	ID                  int64          `db:"id" json:"id"`
+	Name                string         `db:"name" json:"name"`
+	Description         sql.NullString `db:"description" json:"description"`
+	WaitlistAutoPromote bool           `db:"waitlist_auto_promote" json:"waitlist_auto_promote"`
+	MaxPaidEntries      int32          `db:"max_paid_entries" json:"max_paid_entries"`
+	EntryPrice          int32          `db:"entry_price" json:"entry_price"`
+	ShareClicksRequired int32          `db:"share_clicks_required" json:"share_clicks_required"`
+	ShareBonus          int32          `db:"share_bonus" json:"share_bonus"`
+	CreatedAt           time.Time      `db:"created_at" json:"created_at"`
+}
+
+type EventTranslations struct {
+	EventID     int64     `db:"event_id" json:"event_id"`
+	Language    string    `db:"language" json:"language"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type Events struct {
+	ID                    int64          `db:"id" json:"id"`
+	Name                  string         `db:"name" json:"name"`
+	Description           sql.NullString `db:"description" json:"description"`
+	Date                  time.Time      `db:"date" json:"date"`
+	CreatedAt             sql.NullTime   `db:"created_at" json:"created_at"`
+	Version               int32          `db:"version" json:"version"`
+	WaitlistAutoPromote   bool           `db:"waitlist_auto_promote" json:"waitlist_auto_promote"`
+	LastTicketNumber      int32          `db:"last_ticket_number" json:"last_ticket_number"`
+	KioskToken            sql.NullString `db:"kiosk_token" json:"kiosk_token"`
+	MaxPaidEntries        int32          `db:"max_paid_entries" json:"max_paid_entries"`
+	EntryPrice            int32          `db:"entry_price" json:"entry_price"`
+	ShareClicksRequired   int32          `db:"share_clicks_required" json:"share_clicks_required"`
+	ShareBonus            int32          `db:"share_bonus" json:"share_bonus"`
+	ShowWinners           bool           `db:"show_winners" json:"show_winners"`
+	ArchivedAt            sql.NullTime   `db:"archived_at" json:"archived_at"`
+	CalendarEventID       sql.NullString `db:"calendar_event_id" json:"calendar_event_id"`
+	CalendarSyncedVersion int32          `db:"calendar_synced_version" json:"calendar_synced_version"`
+	TicketPrice           int32          `db:"ticket_price" json:"ticket_price"`
+	SeatAssignment        string         `db:"seat_assignment" json:"seat_assignment"`
+	UnarchivedAt          sql.NullTime   `db:"unarchived_at" json:"unarchived_at"`
+	DeletedAt             sql.NullTime   `db:"deleted_at" json:"deleted_at"`
+	Categories            []string       `db:"categories" json:"categories"`
+	ImageUpdatedAt        sql.NullTime   `db:"image_updated_at" json:"image_updated_at"`
+	Capacity              int32          `db:"capacity" json:"capacity"`
+	Status                string         `db:"status" json:"status"`
+	RegistrationOpensAt   sql.NullTime   `db:"registration_opens_at" json:"registration_opens_at"`
+	RegistrationClosesAt  sql.NullTime   `db:"registration_closes_at" json:"registration_closes_at"`
+	Slug                  sql.NullString `db:"slug" json:"slug"`
+}
+
+type FeatureFlags struct {
 	Name      string       `db:"name" json:"name"`
-	Username  string       `db:"username" json:"username"`
-	TgID      int64        `db:"tg_id" json:"tg_id"`
+	Enabled   bool         `db:"enabled" json:"enabled"`
+	UpdatedAt sql.NullTime `db:"updated_at" json:"updated_at"`
+}
+
+type IdempotencyKeys struct {
+	Key         string    `db:"key" json:"key"`
+	Scope       string    `db:"scope" json:"scope"`
+	RequestHash string    `db:"request_hash" json:"request_hash"`
+	StatusCode  int32     `db:"status_code" json:"status_code"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Body        []byte    `db:"body" json:"body"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+type Outbox struct {
+	ID            int64          `db:"id" json:"id"`
+	ChatID        int64          `db:"chat_id" json:"chat_id"`
+	Text          string         `db:"text" json:"text"`
+	Attempts      int32          `db:"attempts" json:"attempts"`
+	LastError     sql.NullString `db:"last_error" json:"last_error"`
+	NextAttemptAt time.Time      `db:"next_attempt_at" json:"next_attempt_at"`
+	SentAt        sql.NullTime   `db:"sent_at" json:"sent_at"`
+	FailedAt      sql.NullTime   `db:"failed_at" json:"failed_at"`
+	CreatedAt     sql.NullTime   `db:"created_at" json:"created_at"`
+	Markdown      bool           `db:"markdown" json:"markdown"`
+	DeliveryID    sql.NullInt64  `db:"delivery_id" json:"delivery_id"`
+	SmsUserID     sql.NullInt64  `db:"sms_user_id" json:"sms_user_id"`
+}
+
+type PromoCodes struct {
+	ID        int64        `db:"id" json:"id"`
 	EventID   int64        `db:"event_id" json:"event_id"`
-	CreatedAt sql.NullTime `db:"created_at" json:"created_at"`
-	N         int32        `db:"n" json:"n"`
+	Code      string       `db:"code" json:"code"`
+	Kind      string       `db:"kind" json:"kind"`
+	Value     int32        `db:"value" json:"value"`
+	MaxUses   int32        `db:"max_uses" json:"max_uses"`
+	Uses      int32        `db:"uses" json:"uses"`
+	ExpiresAt sql.NullTime `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
+
+type PromoRedemptions struct {
+	ID          int64          `db:"id" json:"id"`
+	PromoCodeID int64          `db:"promo_code_id" json:"promo_code_id"`
+	UserID      sql.NullInt64  `db:"user_id" json:"user_id"`
+	OrderID     sql.NullString `db:"order_id" json:"order_id"`
+	Discount    int32          `db:"discount" json:"discount"`
+	Entries     int32          `db:"entries" json:"entries"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+}
+
+type Seats struct {
+	ID        int64         `db:"id" json:"id"`
+	EventID   int64         `db:"event_id" json:"event_id"`
+	Label     string        `db:"label" json:"label"`
+	UserID    sql.NullInt64 `db:"user_id" json:"user_id"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+}
+
+type Sessions struct {
+	ID        int64         `db:"id" json:"id"`
+	TokenHash string        `db:"token_hash" json:"token_hash"`
+	Name      string        `db:"name" json:"name"`
+	AdminID   sql.NullInt64 `db:"admin_id" json:"admin_id"`
+	Data      []byte        `db:"data" json:"data"`
+	UserAgent string        `db:"user_agent" json:"user_agent"`
+	Ip        string        `db:"ip" json:"ip"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at" json:"updated_at"`
+	ExpiresAt time.Time     `db:"expires_at" json:"expires_at"`
+}
+
+type ShareClicks struct {
+	UserID      int64     `db:"user_id" json:"user_id"`
+	EventID     int64     `db:"event_id" json:"event_id"`
+	VisitorHash string    `db:"visitor_hash" json:"visitor_hash"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+type ShortLinks struct {
+	ID        int64     `db:"id" json:"id"`
+	EventID   int64     `db:"event_id" json:"event_id"`
+	Code      string    `db:"code" json:"code"`
+	Channel   string    `db:"channel" json:"channel"`
+	Target    string    `db:"target" json:"target"`
+	Clicks    int32     `db:"clicks" json:"clicks"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type TicketOrders struct {
+	ID           int64         `db:"id" json:"id"`
+	OrderID      string        `db:"order_id" json:"order_id"`
+	EventID      int64         `db:"event_id" json:"event_id"`
+	UserID       sql.NullInt64 `db:"user_id" json:"user_id"`
+	Name         string        `db:"name" json:"name"`
+	Username     string        `db:"username" json:"username"`
+	Amount       int32         `db:"amount" json:"amount"`
+	Status       string        `db:"status" json:"status"`
+	CreatedAt    time.Time     `db:"created_at" json:"created_at"`
+	PaidAt       sql.NullTime  `db:"paid_at" json:"paid_at"`
+	PaymentID    string        `db:"payment_id" json:"payment_id"`
+	RefundedAt   sql.NullTime  `db:"refunded_at" json:"refunded_at"`
+	TicketTypeID sql.NullInt64 `db:"ticket_type_id" json:"ticket_type_id"`
+}
+
+type TicketTypes struct {
+	ID        int64     `db:"id" json:"id"`
+	EventID   int64     `db:"event_id" json:"event_id"`
+	Name      string    `db:"name" json:"name"`
+	Price     int32     `db:"price" json:"price"`
+	Capacity  int32     `db:"capacity" json:"capacity"`
+	Weight    int32     `db:"weight" json:"weight"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type Tokens struct {
+	ID         int64        `db:"id" json:"id"`
+	AdminID    int64        `db:"admin_id" json:"admin_id"`
+	Name       string       `db:"name" json:"name"`
+	TokenHash  string       `db:"token_hash" json:"token_hash"`
+	Scopes     []string     `db:"scopes" json:"scopes"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+}
+
+type Users struct {
+	ID                    int64          `db:"id" json:"id"`
+	Name                  string         `db:"name" json:"name"`
+	Username              string         `db:"username" json:"username"`
+	TgID                  sql.NullInt64  `db:"tg_id" json:"tg_id"`
+	EventID               int64          `db:"event_id" json:"event_id"`
+	CreatedAt             sql.NullTime   `db:"created_at" json:"created_at"`
+	N                     int32          `db:"n" json:"n"`
+	TicketNumber          int32          `db:"ticket_number" json:"ticket_number"`
+	CheckedInAt           sql.NullTime   `db:"checked_in_at" json:"checked_in_at"`
+	CheckInCode           string         `db:"check_in_code" json:"check_in_code"`
+	Phone                 string         `db:"phone" json:"phone"`
+	AttendanceConfirmedAt sql.NullTime   `db:"attendance_confirmed_at" json:"attendance_confirmed_at"`
+	PaidEntries           int32          `db:"paid_entries" json:"paid_entries"`
+	BonusEntries          int32          `db:"bonus_entries" json:"bonus_entries"`
+	AppliedRuleIds        []int64        `db:"applied_rule_ids" json:"applied_rule_ids"`
+	ShareCode             sql.NullString `db:"share_code" json:"share_code"`
+	ShareEntries          int32          `db:"share_entries" json:"share_entries"`
+	ShareBonusGrantedAt   sql.NullTime   `db:"share_bonus_granted_at" json:"share_bonus_granted_at"`
+	FlagReason            sql.NullString `db:"flag_reason" json:"flag_reason"`
+	ReviewedAt            sql.NullTime   `db:"reviewed_at" json:"reviewed_at"`
+	Tags                  []string       `db:"tags" json:"tags"`
+	Notes                 string         `db:"notes" json:"notes"`
+	BotBlockedAt          sql.NullTime   `db:"bot_blocked_at" json:"bot_blocked_at"`
+	PromoEntries          int32          `db:"promo_entries" json:"promo_entries"`
+	TicketTypeID          sql.NullInt64  `db:"ticket_type_id" json:"ticket_type_id"`
+	DeletedAt             sql.NullTime   `db:"deleted_at" json:"deleted_at"`
+}
+
+type Waitlist struct {
+	ID            int64         `db:"id" json:"id"`
+	EventID       int64         `db:"event_id" json:"event_id"`
+	Name          string        `db:"name" json:"name"`
+	Username      string        `db:"username" json:"username"`
+	TgID          sql.NullInt64 `db:"tg_id" json:"tg_id"`
+	CreatedAt     time.Time     `db:"created_at" json:"created_at"`
+	TicketTypeID  sql.NullInt64 `db:"ticket_type_id" json:"ticket_type_id"`
+	ConsentSource string        `db:"consent_source" json:"consent_source"`
+}
+
+type Webhooks struct {
+	ID            int64          `db:"id" json:"id"`
+	Url           string         `db:"url" json:"url"`
+	Payload       string         `db:"payload" json:"payload"`
+	Attempts      int32          `db:"attempts" json:"attempts"`
+	LastError     sql.NullString `db:"last_error" json:"last_error"`
+	NextAttemptAt time.Time      `db:"next_attempt_at" json:"next_attempt_at"`
+	SentAt        sql.NullTime   `db:"sent_at" json:"sent_at"`
+	FailedAt      sql.NullTime   `db:"failed_at" json:"failed_at"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
 }
@@ -6,22 +6,347 @@ package sqlc
 
 import (
 	"context"
+	"time"
 )
 
 type Querier interface {
+	AddToWaitlist(ctx context.Context, arg *AddToWaitlistParams) (*Waitlist, error)
+	AddUserPaidEntries(ctx context.Context, arg *AddUserPaidEntriesParams) error
+	AddUserPromoEntries(ctx context.Context, arg *AddUserPromoEntriesParams) error
+	ApproveUser(ctx context.Context, arg *ApproveUserParams) (*Users, error)
+	ArchiveEvent(ctx context.Context, id int64) (*Events, error)
+	// Archives events that took place before the cutoff, except those an admin
+	// took out of the archive.
+	ArchiveEventsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	// Gives the participant the event's first free seat, unless they already
+	// have one. Seats taken by concurrent registrations are skipped rather
+	// than waited for.
+	AssignFreeSeat(ctx context.Context, arg *AssignFreeSeatParams) (*Seats, error)
+	// Checking in twice keeps the time of the first check-in.
+	CheckInUser(ctx context.Context, id int64) (*Users, error)
+	// Marks the key as in flight with status code 0, unless a request holds it
+	// or its response is still kept. Claims from before claimed_before were
+	// left by requests that never finished and are taken over.
+	ClaimIdempotencyKey(ctx context.Context, arg *ClaimIdempotencyKeyParams) (int64, error)
+	ClaimOutboxMessages(ctx context.Context, arg *ClaimOutboxMessagesParams) ([]*Outbox, error)
+	ClaimWebhooks(ctx context.Context, arg *ClaimWebhooksParams) ([]*Webhooks, error)
+	ClearChatBlocked(ctx context.Context, tgID int64) ([]*Users, error)
+	// Counts a visit through the link.
+	ClickShortLink(ctx context.Context, id int64) error
+	ConfirmUserAttendance(ctx context.Context, id int64) (*Users, error)
+	// Deletes the token so its link works only once, returning the admin it
+	// was issued to.
+	ConsumeAdminLoginToken(ctx context.Context, arg *ConsumeAdminLoginTokenParams) (int64, error)
+	// Registered participants of the event and its pending ticket orders,
+	// which hold their place for a while like those of ticket types.
+	CountEventTaken(ctx context.Context, arg *CountEventTakenParams) (int32, error)
+	// Pending purchases count towards the limit for a while, so a participant
+	// can't open several checkouts at once to get past it.
+	CountReservedPaidEntries(ctx context.Context, arg *CountReservedPaidEntriesParams) (int32, error)
+	CountShareClicks(ctx context.Context, userID int64) (int64, error)
+	CountShareClicksByVisitor(ctx context.Context, arg *CountShareClicksByVisitorParams) (int64, error)
+	// Registered participants of the type and its pending orders, which hold
+	// their place for a while so it isn't sold twice.
+	CountTicketTypeTaken(ctx context.Context, arg *CountTicketTypeTakenParams) (int32, error)
+	CountUsersByEventID(ctx context.Context, eventID int64) (int64, error)
+	// Participants the bot can message, and those it can't because they
+	// blocked it.
+	CountUsersReach(ctx context.Context, eventID int64) (*CountUsersReachRow, error)
+	// An empty tag counts everyone.
+	CountUsersWithTag(ctx context.Context, arg *CountUsersWithTagParams) (int64, error)
+	CreateAdmin(ctx context.Context, arg *CreateAdminParams) (*Admins, error)
+	CreateAdminLoginToken(ctx context.Context, arg *CreateAdminLoginTokenParams) error
+	CreateAuditRecord(ctx context.Context, arg *CreateAuditRecordParams) error
+	CreateBroadcast(ctx context.Context, arg *CreateBroadcastParams) (*Broadcasts, error)
+	CreateBroadcastDelivery(ctx context.Context, arg *CreateBroadcastDeliveryParams) (*BroadcastDeliveries, error)
+	CreateConsentRecord(ctx context.Context, arg *CreateConsentRecordParams) error
+	CreateDigestSubscription(ctx context.Context, arg *CreateDigestSubscriptionParams) (*DigestSubscriptions, error)
+	CreateDraw(ctx context.Context, arg *CreateDrawParams) (*Draws, error)
+	CreateDrawWinner(ctx context.Context, arg *CreateDrawWinnerParams) error
+	CreateEntryPurchase(ctx context.Context, arg *CreateEntryPurchaseParams) (*EntryPurchases, error)
+	CreateEntryRule(ctx context.Context, arg *CreateEntryRuleParams) (*EntryRules, error)
 	CreateEvent(ctx context.Context, arg *CreateEventParams) (*Events, error)
+	CreateEventOrganizer(ctx context.Context, arg *CreateEventOrganizerParams) (*EventOrganizers, error)
+	CreateEventTemplate(ctx context.Context, arg *CreateEventTemplateParams) (*EventTemplates, error)
+	CreateEventTemplateRule(ctx context.Context, arg *CreateEventTemplateRuleParams) error
+	CreatePromoCode(ctx context.Context, arg *CreatePromoCodeParams) (*PromoCodes, error)
+	CreatePromoRedemption(ctx context.Context, arg *CreatePromoRedemptionParams) error
+	// Seats keep the order of labels; labels the event already has are
+	// skipped.
+	CreateSeats(ctx context.Context, arg *CreateSeatsParams) (int64, error)
+	CreateShortLink(ctx context.Context, arg *CreateShortLinkParams) (*ShortLinks, error)
+	CreateTicketOrder(ctx context.Context, arg *CreateTicketOrderParams) (*TicketOrders, error)
+	CreateTicketType(ctx context.Context, arg *CreateTicketTypeParams) (*TicketTypes, error)
+	CreateToken(ctx context.Context, arg *CreateTokenParams) (*Tokens, error)
 	CreateUser(ctx context.Context, arg *CreateUserParams) (*Users, error)
+	// tg_ids uses 0 for participants without a Telegram account, since array
+	// elements can't be passed as NULL. Rows skipped as duplicates leave gaps
+	// in the ticket numbers.
+	CreateUsersBatch(ctx context.Context, arg *CreateUsersBatchParams) (int64, error)
+	DeleteAdmin(ctx context.Context, id int64) error
+	DeleteAdminLoginTokensBefore(ctx context.Context, before time.Time) (int64, error)
+	DeleteBroadcast(ctx context.Context, arg *DeleteBroadcastParams) (int64, error)
+	DeleteDigestSubscription(ctx context.Context, id int64) error
+	DeleteEntryRule(ctx context.Context, arg *DeleteEntryRuleParams) error
+	// Moves the event to the trash with its participants and draws.
 	DeleteEvent(ctx context.Context, id int64) error
+	DeleteEventImage(ctx context.Context, eventID int64) (*Events, error)
+	DeleteEventOrganizer(ctx context.Context, arg *DeleteEventOrganizerParams) error
+	DeleteEventTemplate(ctx context.Context, id int64) error
+	DeleteEventTranslation(ctx context.Context, arg *DeleteEventTranslationParams) error
+	DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int64, error)
+	// Signs the admin out everywhere but the session with token_hash.
+	DeleteOtherAdminSessions(ctx context.Context, arg *DeleteOtherAdminSessionsParams) (int64, error)
+	// Removes messages that were delivered or given up on before the cutoff.
+	DeleteOutboxBefore(ctx context.Context, before time.Time) (int64, error)
+	DeletePromoCode(ctx context.Context, arg *DeletePromoCodeParams) error
+	DeleteSeat(ctx context.Context, arg *DeleteSeatParams) error
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteSessionByID(ctx context.Context, id int64) (int64, error)
+	DeleteSessionsBefore(ctx context.Context, before time.Time) (int64, error)
+	DeleteShortLink(ctx context.Context, arg *DeleteShortLinkParams) error
+	DeleteTicketType(ctx context.Context, arg *DeleteTicketTypeParams) error
+	// Only the admin who minted a token may revoke it.
+	DeleteToken(ctx context.Context, arg *DeleteTokenParams) (int64, error)
 	DeleteUser(ctx context.Context, id int64) error
+	// Moves the participant to the trash, freeing their seat for someone else.
 	DeleteUsersByIdAndEventId(ctx context.Context, arg *DeleteUsersByIdAndEventIdParams) error
+	DeleteWaitlistEntry(ctx context.Context, arg *DeleteWaitlistEntryParams) error
+	// Removes calls that were delivered or given up on before the cutoff.
+	DeleteWebhooksBefore(ctx context.Context, before time.Time) (int64, error)
+	EnqueueOutboxMessage(ctx context.Context, arg *EnqueueOutboxMessageParams) (*Outbox, error)
+	EnqueueWebhook(ctx context.Context, arg *EnqueueWebhookParams) error
+	// Reports whether another event, deleted ones included, has the slug.
+	EventSlugTaken(ctx context.Context, arg *EventSlugTakenParams) (bool, error)
+	// Flags participants who share a phone or a name with someone else in the
+	// event, or whose Telegram account ID is close to another participant's
+	// who registered around the same time, since fresh accounts created in a
+	// row get sequential IDs. Reviewed participants are never flagged again.
+	FlagSuspiciousUsers(ctx context.Context, arg *FlagSuspiciousUsersParams) (int64, error)
+	GetAdminByID(ctx context.Context, id int64) (*Admins, error)
+	GetAdminByUsername(ctx context.Context, username string) (*Admins, error)
+	// Lists the unexpired sessions admins are signed in with.
+	GetAdminSessions(ctx context.Context, now time.Time) ([]*GetAdminSessionsRow, error)
+	GetAdmins(ctx context.Context) ([]*Admins, error)
+	// Things worth an admin's attention: registrations waiting for review,
+	// undeliverable Telegram messages and failed payments.
+	GetAnomalyCounts(ctx context.Context, since time.Time) (*GetAnomalyCountsRow, error)
+	// An organizer_id of 0 lists every record, otherwise only those of the
+	// events that admin organizes.
+	GetAuditLogPage(ctx context.Context, arg *GetAuditLogPageParams) ([]*AuditLog, error)
+	GetBroadcastByID(ctx context.Context, arg *GetBroadcastByIDParams) (*Broadcasts, error)
+	GetBroadcastDeliveries(ctx context.Context, broadcastID int64) ([]*GetBroadcastDeliveriesRow, error)
+	GetBroadcastsByEventID(ctx context.Context, eventID int64) ([]*Broadcasts, error)
+	GetConsentLogByEventID(ctx context.Context, eventID int64) ([]*ConsentLog, error)
+	// The trash, most recently deleted first.
+	GetDeletedEvents(ctx context.Context) ([]*Events, error)
+	// Participants in the trash, most recently deleted first. Those of deleted
+	// events are restored with their event instead.
+	GetDeletedUsers(ctx context.Context) ([]*GetDeletedUsersRow, error)
+	GetDigestSubscription(ctx context.Context, id int64) (*DigestSubscriptions, error)
+	GetDigestSubscriptions(ctx context.Context) ([]*DigestSubscriptions, error)
+	GetDrawWinners(ctx context.Context, drawID int64) ([]*GetDrawWinnersRow, error)
+	GetDrawsByEventID(ctx context.Context, eventID int64) ([]*Draws, error)
+	GetDrawsSince(ctx context.Context, since time.Time) ([]*GetDrawsSinceRow, error)
+	GetDueBroadcasts(ctx context.Context, now time.Time) ([]*Broadcasts, error)
+	GetEntryRulesByEventID(ctx context.Context, eventID int64) ([]*EntryRules, error)
 	GetEventByID(ctx context.Context, id int64) (*Events, error)
+	GetEventBySlug(ctx context.Context, slug string) (*Events, error)
+	// The categories used by the active events or the archive, for filtering
+	// the dashboard.
+	GetEventCategories(ctx context.Context, archived bool) ([]string, error)
+	// Locks the event while a participant is registered, so concurrent
+	// registrations can't go over its capacity.
+	GetEventForUpdate(ctx context.Context, id int64) (*Events, error)
+	GetEventImage(ctx context.Context, eventID int64) (*EventImages, error)
+	GetEventOrganizers(ctx context.Context, eventID int64) ([]*EventOrganizers, error)
+	GetEventTags(ctx context.Context, eventID int64) ([]string, error)
+	GetEventTemplate(ctx context.Context, id int64) (*EventTemplates, error)
+	GetEventTemplateRules(ctx context.Context, templateID int64) ([]*EventTemplateRules, error)
+	GetEventTemplates(ctx context.Context) ([]*GetEventTemplatesRow, error)
+	GetEventTranslation(ctx context.Context, arg *GetEventTranslationParams) (*EventTranslations, error)
+	GetEventTranslations(ctx context.Context, eventID int64) ([]*EventTranslations, error)
 	GetEvents(ctx context.Context) ([]*Events, error)
+	GetEventsBetween(ctx context.Context, arg *GetEventsBetweenParams) ([]*Events, error)
+	// A page of the dashboard, which lists either the active events or the
+	// archive. An empty category lists every event, and an organizer_id of 0
+	// the events of every organizer.
+	GetEventsPage(ctx context.Context, arg *GetEventsPageParams) ([]*Events, error)
+	// Lists events changed since they were last pushed to the calendar.
+	GetEventsToSyncToCalendar(ctx context.Context) ([]*Events, error)
+	GetFeatureFlags(ctx context.Context) ([]*FeatureFlags, error)
+	// Participants waiting for review.
+	GetFlaggedUsers(ctx context.Context, eventID int64) ([]*Users, error)
+	GetIdempotencyKey(ctx context.Context, arg *GetIdempotencyKeyParams) (*IdempotencyKeys, error)
 	GetLastEvent(ctx context.Context) (*Events, error)
+	// Newest participants first, the order polling triggers like Zapier's expect.
+	GetLatestUsersByEventID(ctx context.Context, arg *GetLatestUsersByEventIDParams) ([]*Users, error)
+	GetNextWaitlistEntry(ctx context.Context, eventID int64) (*Waitlist, error)
+	// Recipients of a notification kind for the event. Notifications that
+	// aren't about an event pass event_id 0 and reach every recipient of the
+	// kind.
+	GetNotificationRecipients(ctx context.Context, arg *GetNotificationRecipientsParams) ([]*DigestSubscriptions, error)
+	// Ticket orders and entry purchases of an event, newest first. Ticket
+	// orders are named after what the buyer entered, since they may not have
+	// a participant yet.
+	GetOrganizedEventIDs(ctx context.Context, adminID int64) ([]int64, error)
+	GetPaymentsByEventID(ctx context.Context, eventID int64) ([]*GetPaymentsByEventIDRow, error)
+	GetPromoCode(ctx context.Context, arg *GetPromoCodeParams) (*PromoCodes, error)
+	// The event's codes with what their uses brought: participants still
+	// registered, the discount given, bonus entries granted and the revenue of
+	// paid tickets.
+	GetPromoCodeStats(ctx context.Context, eventID int64) ([]*GetPromoCodeStatsRow, error)
+	// Winners of the latest draw of past events shown on the wall of fame, a
+	// page of events at a time, newest first.
+	GetPublicWinners(ctx context.Context, arg *GetPublicWinnersParams) ([]*GetPublicWinnersRow, error)
+	// New registrations per event, for events that got any.
+	GetRegistrationsSince(ctx context.Context, since time.Time) ([]*GetRegistrationsSinceRow, error)
+	// Locks the seat while a participant is moved into it.
+	GetSeatByLabelForUpdate(ctx context.Context, arg *GetSeatByLabelForUpdateParams) (*Seats, error)
+	GetSeatByUserID(ctx context.Context, userID int64) (*Seats, error)
+	// The event's seats with the participants sitting in them.
+	GetSeatsByEventID(ctx context.Context, eventID int64) ([]*GetSeatsByEventIDRow, error)
+	GetSession(ctx context.Context, arg *GetSessionParams) (*Sessions, error)
+	GetShareReport(ctx context.Context, eventID int64) ([]*GetShareReportRow, error)
+	GetShortLinkByCode(ctx context.Context, code string) (*ShortLinks, error)
+	GetShortLinksByEventID(ctx context.Context, eventID int64) ([]*ShortLinks, error)
+	GetTicketOrder(ctx context.Context, orderID string) (*TicketOrders, error)
+	GetTicketType(ctx context.Context, arg *GetTicketTypeParams) (*TicketTypes, error)
+	// Locks the type while a ticket of it is taken, so concurrent
+	// registrations can't go over its capacity.
+	GetTicketTypeForUpdate(ctx context.Context, arg *GetTicketTypeForUpdateParams) (*TicketTypes, error)
+	// The event's types with how many participants have them and how many of
+	// those are checked in.
+	GetTicketTypeStats(ctx context.Context, eventID int64) ([]*GetTicketTypeStatsRow, error)
+	GetTicketTypesByEventID(ctx context.Context, eventID int64) ([]*TicketTypes, error)
+	GetTokenByHash(ctx context.Context, tokenHash string) (*Tokens, error)
+	GetTokensByAdminID(ctx context.Context, adminID int64) ([]*Tokens, error)
+	GetTranslationsByLanguage(ctx context.Context, language string) ([]*EventTranslations, error)
+	GetUserByCheckInCode(ctx context.Context, arg *GetUserByCheckInCodeParams) (*Users, error)
 	GetUserByID(ctx context.Context, id int64) (*Users, error)
+	GetUserByShareCode(ctx context.Context, shareCode string) (*Users, error)
+	GetUserByTgIDAndEventID(ctx context.Context, arg *GetUserByTgIDAndEventIDParams) (*Users, error)
+	GetUserByTicketNumber(ctx context.Context, arg *GetUserByTicketNumberParams) (*Users, error)
 	GetUserByUsername(ctx context.Context, username string) (*Users, error)
 	GetUsersByEventID(ctx context.Context, eventID int64) ([]*Users, error)
+	// Keyset pagination over an event's participants: pass the last ID of the
+	// previous page (0 for the first page).
+	GetUsersByEventIDAfter(ctx context.Context, arg *GetUsersByEventIDAfterParams) ([]*Users, error)
+	// A page of the admin participants table. An empty tag lists everyone; a
+	// query keeps the participants whose name or username contains it, with %
+	// and _ escaped so they match themselves. sort is
+	// name, username, n or registered; ties and other values keep the order of
+	// registration.
+	GetUsersPage(ctx context.Context, arg *GetUsersPageParams) ([]*Users, error)
+	GetWaitlistByEventID(ctx context.Context, eventID int64) ([]*GetWaitlistByEventIDRow, error)
+	GetWaitlistEntry(ctx context.Context, arg *GetWaitlistEntryParams) (*Waitlist, error)
+	GetWaitlistEntryByTgID(ctx context.Context, arg *GetWaitlistEntryByTgIDParams) (*Waitlist, error)
+	// The entry's place in the queue, counting from 1.
+	GetWaitlistPosition(ctx context.Context, arg *GetWaitlistPositionParams) (int32, error)
+	// Grants the bonus at most once per participant.
+	GrantShareBonus(ctx context.Context, arg *GrantShareBonusParams) (int64, error)
+	// Recreates a draw from an event export at its original time.
+	ImportDraw(ctx context.Context, arg *ImportDrawParams) (*Draws, error)
+	// Recreates a participant from an event export with their ticket, entries
+	// and history. Ticket numbering is caught up by SyncLastTicketNumber.
+	ImportUser(ctx context.Context, arg *ImportUserParams) (*Users, error)
+	// Returns the event if it still needs syncing and no other instance is
+	// syncing it, holding it until the transaction ends.
+	LockEventForCalendarSync(ctx context.Context, id int64) (*Events, error)
+	// Claims a due broadcast, so only one instance queues its messages.
+	MarkBroadcastQueued(ctx context.Context, id int64) (int64, error)
+	// Flags the participants of every event registered from the chat.
+	MarkChatBlocked(ctx context.Context, tgID int64) ([]*Users, error)
+	// Returns 0 if the digest was already sent after sent_before, so only one
+	// instance sends it when several are running.
+	MarkDigestSent(ctx context.Context, arg *MarkDigestSentParams) (int64, error)
+	MarkEntryPurchaseFailed(ctx context.Context, orderID string) error
+	// Returns no rows if the purchase was already settled, so repeated
+	// provider callbacks credit the entries once.
+	MarkEntryPurchasePaid(ctx context.Context, arg *MarkEntryPurchasePaidParams) (*EntryPurchases, error)
+	MarkEntryPurchaseRefunded(ctx context.Context, orderID string) (int64, error)
+	MarkOutboxMessageFailed(ctx context.Context, arg *MarkOutboxMessageFailedParams) error
+	MarkOutboxMessageSent(ctx context.Context, id int64) error
+	MarkTicketOrderFailed(ctx context.Context, orderID string) error
+	// Returns no rows if the order was already settled, so repeated provider
+	// callbacks register the participant once.
+	MarkTicketOrderPaid(ctx context.Context, arg *MarkTicketOrderPaidParams) (*TicketOrders, error)
+	// Affects no rows if the order isn't paid, so a payment is recorded as
+	// refunded once.
+	MarkTicketOrderRefunded(ctx context.Context, orderID string) (int64, error)
+	MarkWebhookFailed(ctx context.Context, arg *MarkWebhookFailedParams) error
+	MarkWebhookSent(ctx context.Context, id int64) error
+	// Drops deleted events from notification preferences. Lists left with no
+	// existing event are kept as they are, since an empty list means all
+	// events.
+	PruneNotifyEventIDs(ctx context.Context) (int64, error)
+	// Removes for good the events deleted before the cutoff.
+	PurgeDeletedEventsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	// Removes for good the participants deleted before the cutoff.
+	PurgeDeletedUsersBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	// Re-evaluates the event's rules for every participant, with the same
+	// conditions CreateUser applies at registration.
+	RecalculateEntryBonuses(ctx context.Context, eventID int64) (int64, error)
+	// Returns 0 if the visitor already opened this participant's link.
+	RecordShareClick(ctx context.Context, arg *RecordShareClickParams) (int64, error)
+	// Drops an in-flight claim whose request failed, so it can be retried.
+	ReleaseIdempotencyKey(ctx context.Context, arg *ReleaseIdempotencyKeyParams) error
+	// Gives back the use taken by a ticket order whose payment failed.
+	ReleasePromoRedemption(ctx context.Context, orderID string) error
+	ReleaseSeat(ctx context.Context, userID int64) error
+	// Takes the event out of the trash. It was removed from the calendar on
+	// deletion, so it's pushed there again as a new calendar event.
+	RestoreEvent(ctx context.Context, id int64) (*Events, error)
+	// Fails with a unique violation if the account has registered for the
+	// event again since.
+	RestoreUser(ctx context.Context, id int64) (*Users, error)
+	SaveIdempotencyKey(ctx context.Context, arg *SaveIdempotencyKeyParams) error
+	SaveSession(ctx context.Context, arg *SaveSessionParams) error
+	// A new password is one the admin no longer has to change.
+	SetAdminPassword(ctx context.Context, arg *SetAdminPasswordParams) error
+	SetBroadcastDeliveryStatus(ctx context.Context, arg *SetBroadcastDeliveryStatusParams) error
+	SetEventCalendarSynced(ctx context.Context, arg *SetEventCalendarSyncedParams) error
+	SetEventCapacity(ctx context.Context, arg *SetEventCapacityParams) (*Events, error)
+	SetEventCategories(ctx context.Context, arg *SetEventCategoriesParams) (*Events, error)
+	// Replaces the event's image and returns the event with its new
+	// image_updated_at.
+	SetEventImage(ctx context.Context, arg *SetEventImageParams) (*Events, error)
+	SetEventKioskToken(ctx context.Context, arg *SetEventKioskTokenParams) (*Events, error)
+	SetEventPaidEntries(ctx context.Context, arg *SetEventPaidEntriesParams) (*Events, error)
+	SetEventRegistrationWindow(ctx context.Context, arg *SetEventRegistrationWindowParams) (*Events, error)
+	SetEventSeatAssignment(ctx context.Context, arg *SetEventSeatAssignmentParams) (*Events, error)
+	SetEventShareBonus(ctx context.Context, arg *SetEventShareBonusParams) (*Events, error)
+	SetEventShowWinners(ctx context.Context, arg *SetEventShowWinnersParams) (*Events, error)
+	SetEventSlug(ctx context.Context, arg *SetEventSlugParams) (*Events, error)
+	SetEventStatus(ctx context.Context, arg *SetEventStatusParams) (*Events, error)
+	SetEventTicketPrice(ctx context.Context, arg *SetEventTicketPriceParams) (*Events, error)
+	SetEventWaitlistAutoPromote(ctx context.Context, arg *SetEventWaitlistAutoPromoteParams) (*Events, error)
+	SetFeatureFlag(ctx context.Context, arg *SetFeatureFlagParams) (*FeatureFlags, error)
+	// Links the use of a paid ticket order to the buyer once they are
+	// registered. Returns no rows if the order had no code.
+	SetPromoRedemptionUser(ctx context.Context, arg *SetPromoRedemptionUserParams) (*PromoRedemptions, error)
+	SetSeatUser(ctx context.Context, arg *SetSeatUserParams) error
+	SetTicketOrderUser(ctx context.Context, arg *SetTicketOrderUserParams) error
+	SetUserNotes(ctx context.Context, arg *SetUserNotesParams) (*Users, error)
+	// Keeps an existing code, so a participant's share link never changes.
+	SetUserShareCode(ctx context.Context, arg *SetUserShareCodeParams) (*Users, error)
+	SetUserTags(ctx context.Context, arg *SetUserTagsParams) (*Users, error)
+	// Gives the participant the type's entries in the draw along with it.
+	SetUserTicketType(ctx context.Context, arg *SetUserTicketTypeParams) (*Users, error)
+	// Continues ticket numbering after the highest ticket in the event.
+	SyncLastTicketNumber(ctx context.Context, id int64) error
+	TouchToken(ctx context.Context, arg *TouchTokenParams) error
+	UnarchiveEvent(ctx context.Context, id int64) (*Events, error)
+	UpdateBroadcast(ctx context.Context, arg *UpdateBroadcastParams) (int64, error)
 	UpdateEvent(ctx context.Context, arg *UpdateEventParams) (*Events, error)
+	UpdateEventTemplate(ctx context.Context, arg *UpdateEventTemplateParams) (*EventTemplates, error)
+	UpdateNotificationPreferences(ctx context.Context, arg *UpdateNotificationPreferencesParams) (*DigestSubscriptions, error)
 	UpdateUserN(ctx context.Context, arg *UpdateUserNParams) error
+	UpdateUserProfile(ctx context.Context, arg *UpdateUserProfileParams) (*Users, error)
+	UpsertEventTranslation(ctx context.Context, arg *UpsertEventTranslationParams) (*EventTranslations, error)
+	// Takes one use of the code. Returns no rows if the code doesn't exist, has
+	// expired or is used up, so concurrent uses can't go over max_uses.
+	UsePromoCode(ctx context.Context, arg *UsePromoCodeParams) (*PromoCodes, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -7,27 +7,330 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+const addUserPaidEntries = `-- name: AddUserPaidEntries :exec
+UPDATE users
+SET paid_entries = paid_entries + $1
+WHERE id = $2
+`
+
+type AddUserPaidEntriesParams struct {
+	Entries int32 `db:"entries" json:"entries"`
+	ID      int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) AddUserPaidEntries(ctx context.Context, arg *AddUserPaidEntriesParams) error {
+	_, err := q.exec(ctx, q.addUserPaidEntriesStmt, addUserPaidEntries, arg.Entries, arg.ID)
+	return err
+}
+
+const addUserPromoEntries = `-- name: AddUserPromoEntries :exec
+UPDATE users
+SET promo_entries = promo_entries + $1
+WHERE id = $2
+`
+
+type AddUserPromoEntriesParams struct {
+	Entries int32 `db:"entries" json:"entries"`
+	ID      int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) AddUserPromoEntries(ctx context.Context, arg *AddUserPromoEntriesParams) error {
+	_, err := q.exec(ctx, q.addUserPromoEntriesStmt, addUserPromoEntries, arg.Entries, arg.ID)
+	return err
+}
+
+const approveUser = `-- name: ApproveUser :one
+UPDATE users
+SET reviewed_at = COALESCE(reviewed_at, CURRENT_TIMESTAMP)
+WHERE id = $1
+AND event_id = $2
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+type ApproveUserParams struct {
+	ID      int64 `db:"id" json:"id"`
+	EventID int64 `db:"event_id" json:"event_id"`
+}
+
+func (q *Queries) ApproveUser(ctx context.Context, arg *ApproveUserParams) (*Users, error) {
+	row := q.queryRow(ctx, q.approveUserStmt, approveUser, arg.ID, arg.EventID)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const checkInUser = `-- name: CheckInUser :one
+UPDATE users
+SET checked_in_at = COALESCE(checked_in_at, CURRENT_TIMESTAMP)
+WHERE id = $1
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+// Checking in twice keeps the time of the first check-in.
+func (q *Queries) CheckInUser(ctx context.Context, id int64) (*Users, error) {
+	row := q.queryRow(ctx, q.checkInUserStmt, checkInUser, id)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const clearChatBlocked = `-- name: ClearChatBlocked :many
+UPDATE users
+SET bot_blocked_at = NULL
+WHERE tg_id = $1::bigint
+AND bot_blocked_at IS NOT NULL
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+func (q *Queries) ClearChatBlocked(ctx context.Context, tgID int64) ([]*Users, error) {
+	rows, err := q.query(ctx, q.clearChatBlockedStmt, clearChatBlocked, tgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Username,
+			&i.TgID,
+			&i.EventID,
+			&i.CreatedAt,
+			&i.N,
+			&i.TicketNumber,
+			&i.CheckedInAt,
+			&i.CheckInCode,
+			&i.Phone,
+			&i.AttendanceConfirmedAt,
+			&i.PaidEntries,
+			&i.BonusEntries,
+			pq.Array(&i.AppliedRuleIds),
+			&i.ShareCode,
+			&i.ShareEntries,
+			&i.ShareBonusGrantedAt,
+			&i.FlagReason,
+			&i.ReviewedAt,
+			pq.Array(&i.Tags),
+			&i.Notes,
+			&i.BotBlockedAt,
+			&i.PromoEntries,
+			&i.TicketTypeID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const confirmUserAttendance = `-- name: ConfirmUserAttendance :one
+UPDATE users
+SET attendance_confirmed_at = COALESCE(attendance_confirmed_at, CURRENT_TIMESTAMP)
+WHERE id = $1
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+func (q *Queries) ConfirmUserAttendance(ctx context.Context, id int64) (*Users, error) {
+	row := q.queryRow(ctx, q.confirmUserAttendanceStmt, confirmUserAttendance, id)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const countUsersByEventID = `-- name: CountUsersByEventID :one
+SELECT COUNT(*) FROM users
+WHERE event_id = $1
+AND deleted_at IS NULL
+`
+
+func (q *Queries) CountUsersByEventID(ctx context.Context, eventID int64) (int64, error) {
+	row := q.queryRow(ctx, q.countUsersByEventIDStmt, countUsersByEventID, eventID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUsersReach = `-- name: CountUsersReach :one
+SELECT
+    COUNT(*) FILTER (WHERE tg_id IS NOT NULL AND bot_blocked_at IS NULL) AS reachable,
+    COUNT(*) FILTER (WHERE bot_blocked_at IS NOT NULL) AS blocked
+FROM users
+WHERE event_id = $1
+AND deleted_at IS NULL
+`
+
+type CountUsersReachRow struct {
+	Reachable int64 `db:"reachable" json:"reachable"`
+	Blocked   int64 `db:"blocked" json:"blocked"`
+}
+
+// Participants the bot can message, and those it can't because they
+// blocked it.
+func (q *Queries) CountUsersReach(ctx context.Context, eventID int64) (*CountUsersReachRow, error) {
+	row := q.queryRow(ctx, q.countUsersReachStmt, countUsersReach, eventID)
+	var i CountUsersReachRow
+	err := row.Scan(&i.Reachable, &i.Blocked)
+	return &i, err
+}
+
+const countUsersWithTag = `-- name: CountUsersWithTag :one
+SELECT COUNT(*) FROM users
+WHERE event_id = $1
+AND deleted_at IS NULL
+AND ($2::text = '' OR $2::text = ANY(tags))
+`
+
+type CountUsersWithTagParams struct {
+	EventID int64  `db:"event_id" json:"event_id"`
+	Tag     string `db:"tag" json:"tag"`
+}
+
+// An empty tag counts everyone.
+func (q *Queries) CountUsersWithTag(ctx context.Context, arg *CountUsersWithTagParams) (int64, error) {
+	row := q.queryRow(ctx, q.countUsersWithTagStmt, countUsersWithTag, arg.EventID, arg.Tag)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
+WITH ticket AS (
+    UPDATE events
+    SET last_ticket_number = last_ticket_number + 1
+    WHERE id = $4::bigint
+    RETURNING last_ticket_number
+)
 INSERT INTO users (
     name, 
     username,
     tg_id,
-    event_id
-) VALUES (
-    $1,
-    $2,
-    $3,
-    $4
-) RETURNING id, name, username, tg_id, event_id, created_at, n
+    event_id,
+    ticket_number,
+    check_in_code,
+    bonus_entries,
+    applied_rule_ids
+)
+SELECT
+    $1::text,
+    $2::text,
+    $3::bigint,
+    $4::bigint,
+    ticket.last_ticket_number,
+    $5::text,
+    COALESCE(rules.bonus, 0),
+    COALESCE(rules.ids, '{}')
+FROM ticket, LATERAL (
+    SELECT SUM(r.bonus)::int AS bonus, array_agg(r.id ORDER BY r.id) AS ids
+    FROM entry_rules r
+    WHERE r.event_id = $4::bigint
+    AND (r.max_ticket IS NULL OR ticket.last_ticket_number <= r.max_ticket)
+    AND (r.registered_before IS NULL OR CURRENT_TIMESTAMP < r.registered_before)
+) rules
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
 `
 
 type CreateUserParams struct {
-	Name     string `db:"name" json:"name"`
-	Username string `db:"username" json:"username"`
-	TgID     int64  `db:"tg_id" json:"tg_id"`
-	EventID  int64  `db:"event_id" json:"event_id"`
+	Name        string        `db:"name" json:"name"`
+	Username    string        `db:"username" json:"username"`
+	TgID        sql.NullInt64 `db:"tg_id" json:"tg_id"`
+	EventID     int64         `db:"event_id" json:"event_id"`
+	CheckInCode string        `db:"check_in_code" json:"check_in_code"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (*Users, error) {
@@ -36,6 +339,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (*Users
 		arg.Username,
 		arg.TgID,
 		arg.EventID,
+		arg.CheckInCode,
 	)
 	var i Users
 	err := row.Scan(
@@ -46,10 +350,90 @@ func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (*Users
 		&i.EventID,
 		&i.CreatedAt,
 		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
 	)
 	return &i, err
 }
 
+const createUsersBatch = `-- name: CreateUsersBatch :execrows
+WITH ticket AS (
+    UPDATE events
+    SET last_ticket_number = last_ticket_number + cardinality($1::text[])
+    WHERE id = $4::bigint
+    RETURNING last_ticket_number - cardinality($1::text[]) AS base
+)
+INSERT INTO users (
+    name,
+    username,
+    tg_id,
+    event_id,
+    ticket_number,
+    check_in_code,
+    bonus_entries,
+    applied_rule_ids
+)
+SELECT
+    ($1::text[])[i],
+    ($2::text[])[i],
+    NULLIF(($3::bigint[])[i], 0),
+    $4::bigint,
+    ticket.base + i,
+    ($5::text[])[i],
+    COALESCE(rules.bonus, 0),
+    COALESCE(rules.ids, '{}')
+FROM ticket, generate_series(1, cardinality($1::text[])) AS i, LATERAL (
+    SELECT SUM(r.bonus)::int AS bonus, array_agg(r.id ORDER BY r.id) AS ids
+    FROM entry_rules r
+    WHERE r.event_id = $4::bigint
+    AND (r.max_ticket IS NULL OR ticket.base + i <= r.max_ticket)
+    AND (r.registered_before IS NULL OR CURRENT_TIMESTAMP < r.registered_before)
+) rules
+ON CONFLICT (tg_id, event_id) WHERE deleted_at IS NULL DO NOTHING
+`
+
+type CreateUsersBatchParams struct {
+	Names        []string `db:"names" json:"names"`
+	Usernames    []string `db:"usernames" json:"usernames"`
+	TgIds        []int64  `db:"tg_ids" json:"tg_ids"`
+	EventID      int64    `db:"event_id" json:"event_id"`
+	CheckInCodes []string `db:"check_in_codes" json:"check_in_codes"`
+}
+
+// tg_ids uses 0 for participants without a Telegram account, since array
+// elements can't be passed as NULL. Rows skipped as duplicates leave gaps
+// in the ticket numbers.
+func (q *Queries) CreateUsersBatch(ctx context.Context, arg *CreateUsersBatchParams) (int64, error) {
+	result, err := q.exec(ctx, q.createUsersBatchStmt, createUsersBatch,
+		pq.Array(arg.Names),
+		pq.Array(arg.Usernames),
+		pq.Array(arg.TgIds),
+		arg.EventID,
+		pq.Array(arg.CheckInCodes),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteUser = `-- name: DeleteUser :exec
 DELETE FROM users
 WHERE id = $1
@@ -61,8 +445,17 @@ func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
 }
 
 const deleteUsersByIdAndEventId = `-- name: DeleteUsersByIdAndEventId :exec
-DELETE FROM users
-WHERE id = $1 AND event_id = $2
+WITH deleted AS (
+    UPDATE users
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE users.id = $1
+    AND users.event_id = $2
+    AND users.deleted_at IS NULL
+    RETURNING users.id
+)
+UPDATE seats
+SET user_id = NULL
+WHERE user_id IN (SELECT deleted.id FROM deleted)
 `
 
 type DeleteUsersByIdAndEventIdParams struct {
@@ -70,58 +463,171 @@ type DeleteUsersByIdAndEventIdParams struct {
 	EventID int64 `db:"event_id" json:"event_id"`
 }
 
+// Moves the participant to the trash, freeing their seat for someone else.
 func (q *Queries) DeleteUsersByIdAndEventId(ctx context.Context, arg *DeleteUsersByIdAndEventIdParams) error {
 	_, err := q.exec(ctx, q.deleteUsersByIdAndEventIdStmt, deleteUsersByIdAndEventId, arg.ID, arg.EventID)
 	return err
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT id, name, username, tg_id, event_id, created_at, n FROM users
-WHERE id = $1
+const flagSuspiciousUsers = `-- name: FlagSuspiciousUsers :execrows
+WITH reasons AS (
+    SELECT u.id, CASE
+        WHEN u.phone <> '' AND EXISTS (
+            SELECT 1 FROM users o
+            WHERE o.event_id = u.event_id AND o.id <> u.id AND o.deleted_at IS NULL AND o.phone = u.phone
+        ) THEN 'same_phone'
+        WHEN EXISTS (
+            SELECT 1 FROM users o
+            WHERE o.event_id = u.event_id AND o.id <> u.id AND o.deleted_at IS NULL AND lower(btrim(o.name)) = lower(btrim(u.name))
+        ) THEN 'duplicate_name'
+        WHEN u.tg_id IS NOT NULL AND EXISTS (
+            SELECT 1 FROM users o
+            WHERE o.event_id = u.event_id AND o.id <> u.id AND o.deleted_at IS NULL
+            AND o.tg_id BETWEEN u.tg_id - $1::bigint AND u.tg_id + $1::bigint
+            AND o.created_at BETWEEN u.created_at - make_interval(secs => $2::int)
+                AND u.created_at + make_interval(secs => $2::int)
+        ) THEN 'sequential_tg_id'
+    END AS reason
+    FROM users u
+    WHERE u.event_id = $3::bigint
+    AND u.deleted_at IS NULL
+    AND u.flag_reason IS NULL
+    AND u.reviewed_at IS NULL
+)
+UPDATE users
+SET flag_reason = reasons.reason
+FROM reasons
+WHERE users.id = reasons.id
+AND reasons.reason IS NOT NULL
 `
 
-func (q *Queries) GetUserByID(ctx context.Context, id int64) (*Users, error) {
-	row := q.queryRow(ctx, q.getUserByIDStmt, getUserByID, id)
-	var i Users
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Username,
-		&i.TgID,
-		&i.EventID,
-		&i.CreatedAt,
-		&i.N,
-	)
-	return &i, err
+type FlagSuspiciousUsersParams struct {
+	MaxTgIDGap   int64 `db:"max_tg_id_gap" json:"max_tg_id_gap"`
+	BurstSeconds int32 `db:"burst_seconds" json:"burst_seconds"`
+	EventID      int64 `db:"event_id" json:"event_id"`
 }
 
-const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, name, username, tg_id, event_id, created_at, n FROM users
-WHERE username = $1
+// Flags participants who share a phone or a name with someone else in the
+// event, or whose Telegram account ID is close to another participant's
+// who registered around the same time, since fresh accounts created in a
+// row get sequential IDs. Reviewed participants are never flagged again.
+func (q *Queries) FlagSuspiciousUsers(ctx context.Context, arg *FlagSuspiciousUsersParams) (int64, error) {
+	result, err := q.exec(ctx, q.flagSuspiciousUsersStmt, flagSuspiciousUsers, arg.MaxTgIDGap, arg.BurstSeconds, arg.EventID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getDeletedUsers = `-- name: GetDeletedUsers :many
+SELECT users.id, users.name, users.username, users.tg_id, users.event_id, users.created_at, users.n, users.ticket_number, users.checked_in_at, users.check_in_code, users.phone, users.attendance_confirmed_at, users.paid_entries, users.bonus_entries, users.applied_rule_ids, users.share_code, users.share_entries, users.share_bonus_granted_at, users.flag_reason, users.reviewed_at, users.tags, users.notes, users.bot_blocked_at, users.promo_entries, users.ticket_type_id, users.deleted_at, events.name AS event_name
+FROM users
+JOIN events ON events.id = users.event_id
+WHERE users.deleted_at IS NOT NULL
+AND events.deleted_at IS NULL
+ORDER BY users.deleted_at DESC, users.id DESC
 `
 
-func (q *Queries) GetUserByUsername(ctx context.Context, username string) (*Users, error) {
-	row := q.queryRow(ctx, q.getUserByUsernameStmt, getUserByUsername, username)
-	var i Users
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Username,
-		&i.TgID,
-		&i.EventID,
-		&i.CreatedAt,
-		&i.N,
-	)
-	return &i, err
+type GetDeletedUsersRow struct {
+	Users     Users  `db:"users" json:"users"`
+	EventName string `db:"event_name" json:"event_name"`
 }
 
-const getUsersByEventID = `-- name: GetUsersByEventID :many
-SELECT id, name, username, tg_id, event_id, created_at, n FROM users
+// Participants in the trash, most recently deleted first. Those of deleted
+// events are restored with their event instead.
+func (q *Queries) GetDeletedUsers(ctx context.Context) ([]*GetDeletedUsersRow, error) {
+	rows, err := q.query(ctx, q.getDeletedUsersStmt, getDeletedUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetDeletedUsersRow{}
+	for rows.Next() {
+		var i GetDeletedUsersRow
+		if err := rows.Scan(
+			&i.Users.ID,
+			&i.Users.Name,
+			&i.Users.Username,
+			&i.Users.TgID,
+			&i.Users.EventID,
+			&i.Users.CreatedAt,
+			&i.Users.N,
+			&i.Users.TicketNumber,
+			&i.Users.CheckedInAt,
+			&i.Users.CheckInCode,
+			&i.Users.Phone,
+			&i.Users.AttendanceConfirmedAt,
+			&i.Users.PaidEntries,
+			&i.Users.BonusEntries,
+			pq.Array(&i.Users.AppliedRuleIds),
+			&i.Users.ShareCode,
+			&i.Users.ShareEntries,
+			&i.Users.ShareBonusGrantedAt,
+			&i.Users.FlagReason,
+			&i.Users.ReviewedAt,
+			pq.Array(&i.Users.Tags),
+			&i.Users.Notes,
+			&i.Users.BotBlockedAt,
+			&i.Users.PromoEntries,
+			&i.Users.TicketTypeID,
+			&i.Users.DeletedAt,
+			&i.EventName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEventTags = `-- name: GetEventTags :many
+SELECT DISTINCT tag::text FROM users, unnest(users.tags) AS tag
+WHERE users.event_id = $1
+AND users.deleted_at IS NULL
+ORDER BY tag
+`
+
+func (q *Queries) GetEventTags(ctx context.Context, eventID int64) ([]string, error) {
+	rows, err := q.query(ctx, q.getEventTagsStmt, getEventTags, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		items = append(items, tag)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFlaggedUsers = `-- name: GetFlaggedUsers :many
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
 WHERE event_id = $1
+AND deleted_at IS NULL
+AND flag_reason IS NOT NULL
+AND reviewed_at IS NULL
+ORDER BY flag_reason, lower(name), id
 `
 
-func (q *Queries) GetUsersByEventID(ctx context.Context, eventID int64) ([]*Users, error) {
-	rows, err := q.query(ctx, q.getUsersByEventIDStmt, getUsersByEventID, eventID)
+// Participants waiting for review.
+func (q *Queries) GetFlaggedUsers(ctx context.Context, eventID int64) ([]*Users, error) {
+	rows, err := q.query(ctx, q.getFlaggedUsersStmt, getFlaggedUsers, eventID)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +643,25 @@ func (q *Queries) GetUsersByEventID(ctx context.Context, eventID int64) ([]*User
 			&i.EventID,
 			&i.CreatedAt,
 			&i.N,
+			&i.TicketNumber,
+			&i.CheckedInAt,
+			&i.CheckInCode,
+			&i.Phone,
+			&i.AttendanceConfirmedAt,
+			&i.PaidEntries,
+			&i.BonusEntries,
+			pq.Array(&i.AppliedRuleIds),
+			&i.ShareCode,
+			&i.ShareEntries,
+			&i.ShareBonusGrantedAt,
+			&i.FlagReason,
+			&i.ReviewedAt,
+			pq.Array(&i.Tags),
+			&i.Notes,
+			&i.BotBlockedAt,
+			&i.PromoEntries,
+			&i.TicketTypeID,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -151,20 +676,1079 @@ func (q *Queries) GetUsersByEventID(ctx context.Context, eventID int64) ([]*User
 	return items, nil
 }
 
-const updateUserN = `-- name: UpdateUserN :exec
-UPDATE users
-SET n = $1
-WHERE id = $2
-AND event_id = $3
+const getLatestUsersByEventID = `-- name: GetLatestUsersByEventID :many
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
+WHERE event_id = $1
+AND deleted_at IS NULL
+ORDER BY id DESC
+LIMIT $2::int
 `
 
-type UpdateUserNParams struct {
-	N       int32 `db:"n" json:"n"`
-	ID      int64 `db:"id" json:"id"`
-	EventID int64 `db:"event_id" json:"event_id"`
+type GetLatestUsersByEventIDParams struct {
+	EventID  int64 `db:"event_id" json:"event_id"`
+	PageSize int32 `db:"page_size" json:"page_size"`
 }
 
-func (q *Queries) UpdateUserN(ctx context.Context, arg *UpdateUserNParams) error {
-	_, err := q.exec(ctx, q.updateUserNStmt, updateUserN, arg.N, arg.ID, arg.EventID)
-	return err
+// Newest participants first, the order polling triggers like Zapier's expect.
+func (q *Queries) GetLatestUsersByEventID(ctx context.Context, arg *GetLatestUsersByEventIDParams) ([]*Users, error) {
+	rows, err := q.query(ctx, q.getLatestUsersByEventIDStmt, getLatestUsersByEventID, arg.EventID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Username,
+			&i.TgID,
+			&i.EventID,
+			&i.CreatedAt,
+			&i.N,
+			&i.TicketNumber,
+			&i.CheckedInAt,
+			&i.CheckInCode,
+			&i.Phone,
+			&i.AttendanceConfirmedAt,
+			&i.PaidEntries,
+			&i.BonusEntries,
+			pq.Array(&i.AppliedRuleIds),
+			&i.ShareCode,
+			&i.ShareEntries,
+			&i.ShareBonusGrantedAt,
+			&i.FlagReason,
+			&i.ReviewedAt,
+			pq.Array(&i.Tags),
+			&i.Notes,
+			&i.BotBlockedAt,
+			&i.PromoEntries,
+			&i.TicketTypeID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserByCheckInCode = `-- name: GetUserByCheckInCode :one
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
+WHERE event_id = $1
+AND check_in_code = $2
+AND deleted_at IS NULL
+`
+
+type GetUserByCheckInCodeParams struct {
+	EventID     int64  `db:"event_id" json:"event_id"`
+	CheckInCode string `db:"check_in_code" json:"check_in_code"`
+}
+
+func (q *Queries) GetUserByCheckInCode(ctx context.Context, arg *GetUserByCheckInCodeParams) (*Users, error) {
+	row := q.queryRow(ctx, q.getUserByCheckInCodeStmt, getUserByCheckInCode, arg.EventID, arg.CheckInCode)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id int64) (*Users, error) {
+	row := q.queryRow(ctx, q.getUserByIDStmt, getUserByID, id)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const getUserByShareCode = `-- name: GetUserByShareCode :one
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
+WHERE share_code = $1::text
+AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserByShareCode(ctx context.Context, shareCode string) (*Users, error) {
+	row := q.queryRow(ctx, q.getUserByShareCodeStmt, getUserByShareCode, shareCode)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const getUserByTgIDAndEventID = `-- name: GetUserByTgIDAndEventID :one
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
+WHERE event_id = $1
+AND tg_id = $2::bigint
+AND deleted_at IS NULL
+`
+
+type GetUserByTgIDAndEventIDParams struct {
+	EventID int64 `db:"event_id" json:"event_id"`
+	TgID    int64 `db:"tg_id" json:"tg_id"`
+}
+
+func (q *Queries) GetUserByTgIDAndEventID(ctx context.Context, arg *GetUserByTgIDAndEventIDParams) (*Users, error) {
+	row := q.queryRow(ctx, q.getUserByTgIDAndEventIDStmt, getUserByTgIDAndEventID, arg.EventID, arg.TgID)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const getUserByTicketNumber = `-- name: GetUserByTicketNumber :one
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
+WHERE event_id = $1
+AND ticket_number = $2
+AND deleted_at IS NULL
+`
+
+type GetUserByTicketNumberParams struct {
+	EventID      int64 `db:"event_id" json:"event_id"`
+	TicketNumber int32 `db:"ticket_number" json:"ticket_number"`
+}
+
+func (q *Queries) GetUserByTicketNumber(ctx context.Context, arg *GetUserByTicketNumberParams) (*Users, error) {
+	row := q.queryRow(ctx, q.getUserByTicketNumberStmt, getUserByTicketNumber, arg.EventID, arg.TicketNumber)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const getUserByUsername = `-- name: GetUserByUsername :one
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
+WHERE username = $1
+AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserByUsername(ctx context.Context, username string) (*Users, error) {
+	row := q.queryRow(ctx, q.getUserByUsernameStmt, getUserByUsername, username)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const getUsersByEventID = `-- name: GetUsersByEventID :many
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
+WHERE event_id = $1
+AND deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) GetUsersByEventID(ctx context.Context, eventID int64) ([]*Users, error) {
+	rows, err := q.query(ctx, q.getUsersByEventIDStmt, getUsersByEventID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Username,
+			&i.TgID,
+			&i.EventID,
+			&i.CreatedAt,
+			&i.N,
+			&i.TicketNumber,
+			&i.CheckedInAt,
+			&i.CheckInCode,
+			&i.Phone,
+			&i.AttendanceConfirmedAt,
+			&i.PaidEntries,
+			&i.BonusEntries,
+			pq.Array(&i.AppliedRuleIds),
+			&i.ShareCode,
+			&i.ShareEntries,
+			&i.ShareBonusGrantedAt,
+			&i.FlagReason,
+			&i.ReviewedAt,
+			pq.Array(&i.Tags),
+			&i.Notes,
+			&i.BotBlockedAt,
+			&i.PromoEntries,
+			&i.TicketTypeID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersByEventIDAfter = `-- name: GetUsersByEventIDAfter :many
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
+WHERE event_id = $1
+AND deleted_at IS NULL
+AND id > $2
+ORDER BY id
+LIMIT $3::int
+`
+
+type GetUsersByEventIDAfterParams struct {
+	EventID  int64 `db:"event_id" json:"event_id"`
+	AfterID  int64 `db:"after_id" json:"after_id"`
+	PageSize int32 `db:"page_size" json:"page_size"`
+}
+
+// Keyset pagination over an event's participants: pass the last ID of the
+// previous page (0 for the first page).
+func (q *Queries) GetUsersByEventIDAfter(ctx context.Context, arg *GetUsersByEventIDAfterParams) ([]*Users, error) {
+	rows, err := q.query(ctx, q.getUsersByEventIDAfterStmt, getUsersByEventIDAfter, arg.EventID, arg.AfterID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Username,
+			&i.TgID,
+			&i.EventID,
+			&i.CreatedAt,
+			&i.N,
+			&i.TicketNumber,
+			&i.CheckedInAt,
+			&i.CheckInCode,
+			&i.Phone,
+			&i.AttendanceConfirmedAt,
+			&i.PaidEntries,
+			&i.BonusEntries,
+			pq.Array(&i.AppliedRuleIds),
+			&i.ShareCode,
+			&i.ShareEntries,
+			&i.ShareBonusGrantedAt,
+			&i.FlagReason,
+			&i.ReviewedAt,
+			pq.Array(&i.Tags),
+			&i.Notes,
+			&i.BotBlockedAt,
+			&i.PromoEntries,
+			&i.TicketTypeID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersPage = `-- name: GetUsersPage :many
+SELECT id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at FROM users
+WHERE event_id = $1
+AND deleted_at IS NULL
+AND ($2::text = '' OR $2::text = ANY(tags))
+AND (
+    $3::text = ''
+    OR name ILIKE '%' || regexp_replace($3::text, '([\\%_])', '\\\1', 'g') || '%'
+    OR username ILIKE '%' || regexp_replace($3::text, '([\\%_])', '\\\1', 'g') || '%'
+)
+ORDER BY
+    CASE WHEN $4::text = 'name' AND NOT $5::boolean THEN lower(name) END,
+    CASE WHEN $4::text = 'name' AND $5::boolean THEN lower(name) END DESC,
+    CASE WHEN $4::text = 'username' AND NOT $5::boolean THEN lower(username) END,
+    CASE WHEN $4::text = 'username' AND $5::boolean THEN lower(username) END DESC,
+    CASE WHEN $4::text = 'n' AND NOT $5::boolean THEN n END,
+    CASE WHEN $4::text = 'n' AND $5::boolean THEN n END DESC,
+    CASE WHEN $4::text = 'registered' AND NOT $5::boolean THEN created_at END,
+    CASE WHEN $4::text = 'registered' AND $5::boolean THEN created_at END DESC,
+    id
+LIMIT $7::int OFFSET $6::int
+`
+
+type GetUsersPageParams struct {
+	EventID    int64  `db:"event_id" json:"event_id"`
+	Tag        string `db:"tag" json:"tag"`
+	Query      string `db:"query" json:"query"`
+	Sort       string `db:"sort" json:"sort"`
+	Descending bool   `db:"descending" json:"descending"`
+	PageOffset int32  `db:"page_offset" json:"page_offset"`
+	PageSize   int32  `db:"page_size" json:"page_size"`
+}
+
+// A page of the admin participants table. An empty tag lists everyone; a
+// query keeps the participants whose name or username contains it, with %
+// and _ escaped so they match themselves. sort is
+// name, username, n or registered; ties and other values keep the order of
+// registration.
+func (q *Queries) GetUsersPage(ctx context.Context, arg *GetUsersPageParams) ([]*Users, error) {
+	rows, err := q.query(ctx, q.getUsersPageStmt, getUsersPage,
+		arg.EventID,
+		arg.Tag,
+		arg.Query,
+		arg.Sort,
+		arg.Descending,
+		arg.PageOffset,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Username,
+			&i.TgID,
+			&i.EventID,
+			&i.CreatedAt,
+			&i.N,
+			&i.TicketNumber,
+			&i.CheckedInAt,
+			&i.CheckInCode,
+			&i.Phone,
+			&i.AttendanceConfirmedAt,
+			&i.PaidEntries,
+			&i.BonusEntries,
+			pq.Array(&i.AppliedRuleIds),
+			&i.ShareCode,
+			&i.ShareEntries,
+			&i.ShareBonusGrantedAt,
+			&i.FlagReason,
+			&i.ReviewedAt,
+			pq.Array(&i.Tags),
+			&i.Notes,
+			&i.BotBlockedAt,
+			&i.PromoEntries,
+			&i.TicketTypeID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const grantShareBonus = `-- name: GrantShareBonus :execrows
+UPDATE users
+SET share_entries = share_entries + $1,
+    share_bonus_granted_at = CURRENT_TIMESTAMP
+WHERE id = $2
+AND share_bonus_granted_at IS NULL
+`
+
+type GrantShareBonusParams struct {
+	Entries int32 `db:"entries" json:"entries"`
+	ID      int64 `db:"id" json:"id"`
+}
+
+// Grants the bonus at most once per participant.
+func (q *Queries) GrantShareBonus(ctx context.Context, arg *GrantShareBonusParams) (int64, error) {
+	result, err := q.exec(ctx, q.grantShareBonusStmt, grantShareBonus, arg.Entries, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importUser = `-- name: ImportUser :one
+INSERT INTO users (
+    name,
+    username,
+    tg_id,
+    event_id,
+    created_at,
+    n,
+    ticket_number,
+    checked_in_at,
+    check_in_code,
+    phone,
+    attendance_confirmed_at,
+    paid_entries,
+    bonus_entries,
+    applied_rule_ids,
+    share_entries,
+    share_bonus_granted_at,
+    promo_entries,
+    ticket_type_id,
+    flag_reason,
+    reviewed_at,
+    tags,
+    notes,
+    bot_blocked_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14,
+    $15,
+    $16,
+    $17,
+    $18,
+    $19,
+    $20,
+    $21,
+    $22,
+    $23
+) RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+type ImportUserParams struct {
+	Name                  string         `db:"name" json:"name"`
+	Username              string         `db:"username" json:"username"`
+	TgID                  sql.NullInt64  `db:"tg_id" json:"tg_id"`
+	EventID               int64          `db:"event_id" json:"event_id"`
+	CreatedAt             sql.NullTime   `db:"created_at" json:"created_at"`
+	N                     int32          `db:"n" json:"n"`
+	TicketNumber          int32          `db:"ticket_number" json:"ticket_number"`
+	CheckedInAt           sql.NullTime   `db:"checked_in_at" json:"checked_in_at"`
+	CheckInCode           string         `db:"check_in_code" json:"check_in_code"`
+	Phone                 string         `db:"phone" json:"phone"`
+	AttendanceConfirmedAt sql.NullTime   `db:"attendance_confirmed_at" json:"attendance_confirmed_at"`
+	PaidEntries           int32          `db:"paid_entries" json:"paid_entries"`
+	BonusEntries          int32          `db:"bonus_entries" json:"bonus_entries"`
+	AppliedRuleIds        []int64        `db:"applied_rule_ids" json:"applied_rule_ids"`
+	ShareEntries          int32          `db:"share_entries" json:"share_entries"`
+	ShareBonusGrantedAt   sql.NullTime   `db:"share_bonus_granted_at" json:"share_bonus_granted_at"`
+	PromoEntries          int32          `db:"promo_entries" json:"promo_entries"`
+	TicketTypeID          sql.NullInt64  `db:"ticket_type_id" json:"ticket_type_id"`
+	FlagReason            sql.NullString `db:"flag_reason" json:"flag_reason"`
+	ReviewedAt            sql.NullTime   `db:"reviewed_at" json:"reviewed_at"`
+	Tags                  []string       `db:"tags" json:"tags"`
+	Notes                 string         `db:"notes" json:"notes"`
+	BotBlockedAt          sql.NullTime   `db:"bot_blocked_at" json:"bot_blocked_at"`
+}
+
+// Recreates a participant from an event export with their ticket, entries
+// and history. Ticket numbering is caught up by SyncLastTicketNumber.
+func (q *Queries) ImportUser(ctx context.Context, arg *ImportUserParams) (*Users, error) {
+	row := q.queryRow(ctx, q.importUserStmt, importUser,
+		arg.Name,
+		arg.Username,
+		arg.TgID,
+		arg.EventID,
+		arg.CreatedAt,
+		arg.N,
+		arg.TicketNumber,
+		arg.CheckedInAt,
+		arg.CheckInCode,
+		arg.Phone,
+		arg.AttendanceConfirmedAt,
+		arg.PaidEntries,
+		arg.BonusEntries,
+		pq.Array(arg.AppliedRuleIds),
+		arg.ShareEntries,
+		arg.ShareBonusGrantedAt,
+		arg.PromoEntries,
+		arg.TicketTypeID,
+		arg.FlagReason,
+		arg.ReviewedAt,
+		pq.Array(arg.Tags),
+		arg.Notes,
+		arg.BotBlockedAt,
+	)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const markChatBlocked = `-- name: MarkChatBlocked :many
+UPDATE users
+SET bot_blocked_at = CURRENT_TIMESTAMP
+WHERE tg_id = $1::bigint
+AND bot_blocked_at IS NULL
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+// Flags the participants of every event registered from the chat.
+func (q *Queries) MarkChatBlocked(ctx context.Context, tgID int64) ([]*Users, error) {
+	rows, err := q.query(ctx, q.markChatBlockedStmt, markChatBlocked, tgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Username,
+			&i.TgID,
+			&i.EventID,
+			&i.CreatedAt,
+			&i.N,
+			&i.TicketNumber,
+			&i.CheckedInAt,
+			&i.CheckInCode,
+			&i.Phone,
+			&i.AttendanceConfirmedAt,
+			&i.PaidEntries,
+			&i.BonusEntries,
+			pq.Array(&i.AppliedRuleIds),
+			&i.ShareCode,
+			&i.ShareEntries,
+			&i.ShareBonusGrantedAt,
+			&i.FlagReason,
+			&i.ReviewedAt,
+			pq.Array(&i.Tags),
+			&i.Notes,
+			&i.BotBlockedAt,
+			&i.PromoEntries,
+			&i.TicketTypeID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedUsersBefore = `-- name: PurgeDeletedUsersBefore :execrows
+DELETE FROM users
+WHERE deleted_at < $1::timestamp
+`
+
+// Removes for good the participants deleted before the cutoff.
+func (q *Queries) PurgeDeletedUsersBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.exec(ctx, q.purgeDeletedUsersBeforeStmt, purgeDeletedUsersBefore, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1
+AND deleted_at IS NOT NULL
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+// Fails with a unique violation if the account has registered for the
+// event again since.
+func (q *Queries) RestoreUser(ctx context.Context, id int64) (*Users, error) {
+	row := q.queryRow(ctx, q.restoreUserStmt, restoreUser, id)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const setUserNotes = `-- name: SetUserNotes :one
+UPDATE users
+SET notes = $1
+WHERE id = $2
+AND event_id = $3
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+type SetUserNotesParams struct {
+	Notes   string `db:"notes" json:"notes"`
+	ID      int64  `db:"id" json:"id"`
+	EventID int64  `db:"event_id" json:"event_id"`
+}
+
+func (q *Queries) SetUserNotes(ctx context.Context, arg *SetUserNotesParams) (*Users, error) {
+	row := q.queryRow(ctx, q.setUserNotesStmt, setUserNotes, arg.Notes, arg.ID, arg.EventID)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const setUserShareCode = `-- name: SetUserShareCode :one
+UPDATE users
+SET share_code = COALESCE(share_code, $1::text)
+WHERE id = $2
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+type SetUserShareCodeParams struct {
+	ShareCode string `db:"share_code" json:"share_code"`
+	ID        int64  `db:"id" json:"id"`
+}
+
+// Keeps an existing code, so a participant's share link never changes.
+func (q *Queries) SetUserShareCode(ctx context.Context, arg *SetUserShareCodeParams) (*Users, error) {
+	row := q.queryRow(ctx, q.setUserShareCodeStmt, setUserShareCode, arg.ShareCode, arg.ID)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const setUserTags = `-- name: SetUserTags :one
+UPDATE users
+SET tags = $1::text[]
+WHERE id = $2
+AND event_id = $3
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+type SetUserTagsParams struct {
+	Tags    []string `db:"tags" json:"tags"`
+	ID      int64    `db:"id" json:"id"`
+	EventID int64    `db:"event_id" json:"event_id"`
+}
+
+func (q *Queries) SetUserTags(ctx context.Context, arg *SetUserTagsParams) (*Users, error) {
+	row := q.queryRow(ctx, q.setUserTagsStmt, setUserTags, pq.Array(arg.Tags), arg.ID, arg.EventID)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const setUserTicketType = `-- name: SetUserTicketType :one
+UPDATE users
+SET ticket_type_id = $1::bigint,
+    n = $2
+WHERE id = $3
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+type SetUserTicketTypeParams struct {
+	TicketTypeID int64 `db:"ticket_type_id" json:"ticket_type_id"`
+	Weight       int32 `db:"weight" json:"weight"`
+	ID           int64 `db:"id" json:"id"`
+}
+
+// Gives the participant the type's entries in the draw along with it.
+func (q *Queries) SetUserTicketType(ctx context.Context, arg *SetUserTicketTypeParams) (*Users, error) {
+	row := q.queryRow(ctx, q.setUserTicketTypeStmt, setUserTicketType, arg.TicketTypeID, arg.Weight, arg.ID)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const updateUserN = `-- name: UpdateUserN :exec
+UPDATE users
+SET n = $1
+WHERE id = $2
+AND event_id = $3
+`
+
+type UpdateUserNParams struct {
+	N       int32 `db:"n" json:"n"`
+	ID      int64 `db:"id" json:"id"`
+	EventID int64 `db:"event_id" json:"event_id"`
+}
+
+func (q *Queries) UpdateUserN(ctx context.Context, arg *UpdateUserNParams) error {
+	_, err := q.exec(ctx, q.updateUserNStmt, updateUserN, arg.N, arg.ID, arg.EventID)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET name = $1,
+    phone = $2
+WHERE id = $3
+RETURNING id, name, username, tg_id, event_id, created_at, n, ticket_number, checked_in_at, check_in_code, phone, attendance_confirmed_at, paid_entries, bonus_entries, applied_rule_ids, share_code, share_entries, share_bonus_granted_at, flag_reason, reviewed_at, tags, notes, bot_blocked_at, promo_entries, ticket_type_id, deleted_at
+`
+
+type UpdateUserProfileParams struct {
+	Name  string `db:"name" json:"name"`
+	Phone string `db:"phone" json:"phone"`
+	ID    int64  `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg *UpdateUserProfileParams) (*Users, error) {
+	row := q.queryRow(ctx, q.updateUserProfileStmt, updateUserProfile, arg.Name, arg.Phone, arg.ID)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.TgID,
+		&i.EventID,
+		&i.CreatedAt,
+		&i.N,
+		&i.TicketNumber,
+		&i.CheckedInAt,
+		&i.CheckInCode,
+		&i.Phone,
+		&i.AttendanceConfirmedAt,
+		&i.PaidEntries,
+		&i.BonusEntries,
+		pq.Array(&i.AppliedRuleIds),
+		&i.ShareCode,
+		&i.ShareEntries,
+		&i.ShareBonusGrantedAt,
+		&i.FlagReason,
+		&i.ReviewedAt,
+		pq.Array(&i.Tags),
+		&i.Notes,
+		&i.BotBlockedAt,
+		&i.PromoEntries,
+		&i.TicketTypeID,
+		&i.DeletedAt,
+	)
+	return &i, err
 }
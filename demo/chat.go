@@ -0,0 +1,132 @@
+package demo
+
+import (
+	"context"
+	_ "embed"
+	"html/template"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"giveaway-tool/logging"
+	"giveaway-tool/router"
+	"giveaway-tool/telegram"
+)
+
+// replyTimeout is how long sending a message waits for the bot to answer
+// before showing the chat without the reply.
+const replyTimeout = 2 * time.Second
+
+const chatCookie = "demo_chat"
+
+//go:embed chat.htmx
+var chatTemplate string
+
+type chatEntry struct {
+	FromUser bool
+	Text     string
+	At       time.Time
+}
+
+// chat lets visitors talk to the simulated bot from the browser. Every
+// visitor gets their own chat, remembered in a cookie.
+type chat struct {
+	bot  *telegram.FakeBot
+	tmpl *template.Template
+
+	mu       sync.Mutex
+	messages map[int64][]chatEntry
+}
+
+// Start serves the bot simulator on /demo/bot.
+func Start(ctx context.Context, mux *http.ServeMux, logger *slog.Logger, bot *telegram.FakeBot) {
+	c := &chat{
+		bot:      bot,
+		tmpl:     template.Must(template.New("chat").Parse(chatTemplate)),
+		messages: make(map[int64][]chatEntry),
+	}
+
+	r := router.New(mux, router.Logging(logger), router.Recovery(router.PlainError), router.CSRF(router.PlainError))
+	r.HandleFunc("GET /demo/bot", c.handlePage)
+	r.HandleFunc("GET /demo/bot/log", c.handleLog)
+	r.HandleFunc("POST /demo/bot", c.handleSend)
+
+	logger.LogAttrs(ctx, slog.LevelInfo, "Demo bot simulator started", slog.String("path", "/demo/bot"))
+}
+
+// chatID returns the visitor's chat, starting a new one on the first visit.
+// IDs start high to stay clear of the seeded participants.
+func (c *chat) chatID(w http.ResponseWriter, r *http.Request) int64 {
+	if cookie, err := r.Cookie(chatCookie); err == nil {
+		if id, err := strconv.ParseInt(cookie.Value, 10, 64); err == nil {
+			return id
+		}
+	}
+	id := 1_000_000 + rand.Int64N(1_000_000_000)
+	http.SetCookie(w, &http.Cookie{Name: chatCookie, Value: strconv.FormatInt(id, 10), Path: "/demo", HttpOnly: true})
+	return id
+}
+
+// log merges what the visitor wrote with the bot's replies.
+func (c *chat) log(chatID int64) []chatEntry {
+	c.mu.Lock()
+	entries := slices.Clone(c.messages[chatID])
+	c.mu.Unlock()
+
+	for _, msg := range c.bot.Sent(chatID) {
+		text := msg.Text
+		if msg.Photo != nil {
+			text = "[Зображення івенту]"
+		}
+		entries = append(entries, chatEntry{Text: text, At: msg.SentAt})
+	}
+	slices.SortStableFunc(entries, func(a, b chatEntry) int { return a.At.Compare(b.At) })
+	return entries
+}
+
+func (c *chat) render(w http.ResponseWriter, r *http.Request, name string, chatID int64) {
+	w.Header().Set("Content-Type", "text/html")
+	if err := c.tmpl.ExecuteTemplate(w, name, c.log(chatID)); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to execute template", slog.Any("error", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (c *chat) handlePage(w http.ResponseWriter, r *http.Request) {
+	c.render(w, r, "demo_bot", c.chatID(w, r))
+}
+
+func (c *chat) handleLog(w http.ResponseWriter, r *http.Request) {
+	c.render(w, r, "demo_bot_log", c.chatID(w, r))
+}
+
+func (c *chat) handleSend(w http.ResponseWriter, r *http.Request) {
+	chatID := c.chatID(w, r)
+	text := strings.TrimSpace(r.FormValue("text"))
+	if text == "" {
+		c.render(w, r, "demo_bot_log", chatID)
+		return
+	}
+
+	c.mu.Lock()
+	c.messages[chatID] = append(c.messages[chatID], chatEntry{FromUser: true, Text: text, At: time.Now()})
+	c.mu.Unlock()
+
+	replies := len(c.bot.Sent(chatID))
+	if err := c.bot.Write(r.Context(), chatID, "demo_visitor", text); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to deliver demo message", slog.Any("error", err))
+	}
+
+	// The bot answers asynchronously, like the real one
+	deadline := time.Now().Add(replyTimeout)
+	for len(c.bot.Sent(chatID)) == replies && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	c.render(w, r, "demo_bot_log", chatID)
+}
@@ -0,0 +1,124 @@
+// Package demo runs the project without Postgres or Telegram: the service
+// uses an in-memory store filled with sample events and a simulated bot
+// that visitors chat with in the browser.
+package demo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/slug"
+	"giveaway-tool/store"
+)
+
+// Enabled reports whether DEMO_MODE is on.
+func Enabled() bool {
+	return os.Getenv("DEMO_MODE") == "true"
+}
+
+var names = []string{
+	"Петренко Іван", "Коваленко Олена", "Шевченко Андрій", "Бондаренко Марія",
+	"Ткаченко Дмитро", "Кравченко Анна", "Олійник Максим", "Мельник Софія",
+	"Лисенко Богдан", "Руденко Юлія", "Савченко Артем", "Мороз Катерина",
+	"Гончаренко Тарас", "Поліщук Ірина",
+}
+
+// Seed fills st with a past event that has a draw and an upcoming event
+// open for registration, returning the upcoming one.
+func Seed(ctx context.Context, st store.Store) (*sqlc.Events, error) {
+	now := time.Now()
+
+	past, err := st.CreateEvent(ctx, &sqlc.CreateEventParams{
+		Name:        "Новорічна вечірка ФІТКІ",
+		Description: sql.NullString{String: "Святкова вечірка з розіграшем призів для студентів факультету.", Valid: true},
+		Date:        now.AddDate(0, 0, -21),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if past, err = slug.Assign(ctx, st, past, ""); err != nil {
+		return nil, err
+	}
+	users, err := seedUsers(ctx, st, past.ID, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users[:9] {
+		if _, err := st.CheckInUser(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	draw, err := st.CreateDraw(ctx, &sqlc.CreateDrawParams{
+		EventID:      past.ID,
+		WinnersCount: 3,
+		Seed:         sql.NullString{String: "demo", Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, user := range []*sqlc.Users{users[4], users[0], users[7]} {
+		if err := st.CreateDrawWinner(ctx, &sqlc.CreateDrawWinnerParams{
+			DrawID:   draw.ID,
+			UserID:   user.ID,
+			Position: int32(i + 1),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := st.SetEventShowWinners(ctx, &sqlc.SetEventShowWinnersParams{ID: past.ID, ShowWinners: true}); err != nil {
+		return nil, err
+	}
+	if _, err := st.SetEventStatus(ctx, &sqlc.SetEventStatusParams{ID: past.ID, Status: lifecycle.Closed}); err != nil {
+		return nil, err
+	}
+
+	upcoming, err := st.CreateEvent(ctx, &sqlc.CreateEventParams{
+		Name:        "Квіз-вечір ФІТКІ",
+		Description: sql.NullString{String: "Командний квіз про IT та університетське життя. Реєструйся через бота!", Valid: true},
+		Date:        now.AddDate(0, 0, 7),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if upcoming, err = slug.Assign(ctx, st, upcoming, ""); err != nil {
+		return nil, err
+	}
+	if _, err := st.SetEventStatus(ctx, &sqlc.SetEventStatusParams{ID: upcoming.ID, Status: lifecycle.RegistrationOpen}); err != nil {
+		return nil, err
+	}
+	if _, err := seedUsers(ctx, st, upcoming.ID, names[:6]); err != nil {
+		return nil, err
+	}
+	if _, err := st.UpsertEventTranslation(ctx, &sqlc.UpsertEventTranslationParams{
+		EventID:     upcoming.ID,
+		Language:    "en",
+		Name:        "FITKI Quiz Night",
+		Description: "A team quiz about IT and university life. Sign up with the bot!",
+	}); err != nil {
+		return nil, err
+	}
+	return upcoming, nil
+}
+
+func seedUsers(ctx context.Context, st store.Store, eventID int64, names []string) ([]*sqlc.Users, error) {
+	users := make([]*sqlc.Users, 0, len(names))
+	for i, name := range names {
+		user, err := st.CreateUser(ctx, &sqlc.CreateUserParams{
+			Name:        name,
+			Username:    fmt.Sprintf("demo_user%d", i+1),
+			TgID:        sql.NullInt64{Int64: int64(1000 + i), Valid: true},
+			EventID:     eventID,
+			CheckInCode: store.NewCheckInCode(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
@@ -0,0 +1,305 @@
+// Package doctor runs pre-flight checks against the deployment
+// configuration and prints a readable pass/fail report.
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"giveaway-tool/database"
+	"giveaway-tool/demo"
+	"giveaway-tool/router"
+	"giveaway-tool/static"
+	"giveaway-tool/tracing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+type status string
+
+const (
+	pass status = "PASS"
+	warn status = "WARN"
+	fail status = "FAIL"
+)
+
+type report struct {
+	out    io.Writer
+	failed bool
+}
+
+func (r *report) add(st status, name, detail string) {
+	if st == fail {
+		r.failed = true
+	}
+	fmt.Fprintf(r.out, "[%s] %-20s %s\n", st, name, detail)
+}
+
+// Run executes every check, writes the report to out and reports whether
+// all checks passed (warnings don't count as failures).
+func Run(ctx context.Context, out io.Writer) bool {
+	r := &report{out: out}
+
+	checkEnv(r)
+	checkAssets(r)
+
+	db := checkDatabase(ctx, r)
+	if db != nil {
+		checkMigrations(ctx, r, db)
+		db.Close()
+	}
+
+	bot := checkBot(r)
+	if bot != nil {
+		checkWebhook(r, bot)
+	}
+
+	if r.failed {
+		fmt.Fprintln(out, "\nSome checks failed.")
+	} else {
+		fmt.Fprintln(out, "\nAll checks passed.")
+	}
+	return !r.failed
+}
+
+func checkAssets(r *report) {
+	if missing := static.Missing(); len(missing) > 0 {
+		r.add(fail, "Static assets", strings.Join(missing, ", ")+" not built, run go generate ./static before go build")
+	} else {
+		r.add(pass, "Static assets", "built")
+	}
+}
+
+func checkEnv(r *report) {
+	if demo.Enabled() {
+		r.add(fail, "DEMO_MODE", "on, data is kept in memory and the bot is simulated")
+	}
+
+	for _, key := range []string{"DATABASE_URL", "TELEGRAM_BOT_TOKEN"} {
+		if os.Getenv(key) == "" {
+			r.add(fail, key, "not set")
+		} else {
+			r.add(pass, key, "set")
+		}
+	}
+
+	// With TLS_DOMAINS the app listens on ports 443 and 80 instead
+	switch port := os.Getenv("PORT"); {
+	case os.Getenv("TLS_DOMAINS") != "":
+		if port != "" {
+			r.add(warn, "PORT", "set but ignored, TLS_DOMAINS serves on ports 443 and 80")
+		}
+	case port == "":
+		r.add(fail, "PORT", "not set")
+	default:
+		r.add(pass, "PORT", "set")
+	}
+
+	for _, key := range []string{"ADMIN_USERNAME", "ADMIN_PASSWORD"} {
+		if os.Getenv(key) == "" {
+			r.add(warn, key, "not set, the default is used for the first owner account")
+		} else {
+			r.add(pass, key, "set")
+		}
+	}
+
+	if os.Getenv("CHECKIN_API_KEY") == "" {
+		r.add(warn, "CHECKIN_API_KEY", "not set, the check-in and registration polling APIs are disabled")
+	} else {
+		r.add(pass, "CHECKIN_API_KEY", "set")
+	}
+
+	if os.Getenv("ADMIN_API_KEY") == "" {
+		r.add(warn, "ADMIN_API_KEY", "not set, the event management API only accepts tokens from /admin/tokens")
+	} else {
+		r.add(pass, "ADMIN_API_KEY", "set")
+	}
+
+	for _, key := range []string{"MAGIC_LINK_SECRET", "PUBLIC_URL"} {
+		if os.Getenv(key) == "" {
+			r.add(warn, key, "not set, participants get no self-service links and share links count no visits")
+		} else {
+			r.add(pass, key, "set")
+		}
+	}
+
+	switch v := os.Getenv("CLIENT_IP_HEADER"); {
+	case v == "":
+		r.add(warn, "CLIENT_IP_HEADER", "not set, behind a proxy every visitor shares the proxy's address in rate limits")
+	case router.ProxyFromEnv().Header == "":
+		r.add(fail, "CLIENT_IP_HEADER", "must be "+router.HeaderFlyClientIP+" or "+router.HeaderForwardedFor)
+	default:
+		r.add(pass, "CLIENT_IP_HEADER", v)
+	}
+
+	if tracing.Enabled() {
+		r.add(pass, "OTEL_EXPORTER_OTLP_ENDPOINT", "set, traces are exported")
+	} else {
+		r.add(warn, "OTEL_EXPORTER_OTLP_ENDPOINT", "not set, traces aren't exported")
+	}
+
+	switch {
+	case os.Getenv("STRIPE_SECRET_KEY") != "" && os.Getenv("STRIPE_WEBHOOK_SECRET") != "":
+		r.add(pass, "Payments", "Stripe")
+	case os.Getenv("LIQPAY_PUBLIC_KEY") != "" && os.Getenv("LIQPAY_PRIVATE_KEY") != "":
+		r.add(pass, "Payments", "LiqPay")
+	default:
+		r.add(warn, "Payments", "STRIPE_* or LIQPAY_* not set, tickets and extra entries can't be sold")
+	}
+
+	if os.Getenv("GOOGLE_CALENDAR_ID") == "" {
+		r.add(warn, "GOOGLE_CALENDAR_ID", "not set, events aren't synced to Google Calendar")
+	} else if _, err := os.Stat(os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")); err != nil {
+		r.add(fail, "GOOGLE_APPLICATION_CREDENTIALS", "service account key file not readable, calendar sync is disabled")
+	} else {
+		r.add(pass, "GOOGLE_CALENDAR_ID", os.Getenv("GOOGLE_CALENDAR_ID"))
+	}
+
+	switch {
+	case os.Getenv("TURBOSMS_TOKEN") != "":
+		r.add(pass, "SMS", "TurboSMS")
+	case os.Getenv("TWILIO_ACCOUNT_SID") != "" && os.Getenv("TWILIO_AUTH_TOKEN") != "" && os.Getenv("TWILIO_FROM") != "":
+		r.add(pass, "SMS", "Twilio")
+	default:
+		r.add(warn, "SMS", "TURBOSMS_TOKEN or TWILIO_* not set, undelivered winner notifications aren't texted")
+	}
+
+	if v := os.Getenv("WEBHOOK_URL"); v == "" {
+		r.add(warn, "WEBHOOK_URL", "not set, registrations aren't sent to Zapier or other webhooks")
+	} else if u, err := url.Parse(v); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		r.add(fail, "WEBHOOK_URL", "not an http(s) URL, webhook calls will fail")
+	} else if os.Getenv("WEBHOOK_SECRET") == "" {
+		r.add(warn, "WEBHOOK_SECRET", "not set, webhook calls aren't signed")
+	} else {
+		r.add(pass, "WEBHOOK_URL", u.Host)
+	}
+
+	if v := os.Getenv("TELEGRAM_CHANNEL_ID"); v == "" {
+		r.add(warn, "TELEGRAM_CHANNEL_ID", "not set, winner announcements can't be posted to a channel")
+	} else if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+		r.add(warn, "TELEGRAM_CHANNEL_ID", "not a numeric chat ID, posting announcements is disabled")
+	} else {
+		r.add(pass, "TELEGRAM_CHANNEL_ID", v)
+	}
+
+	if os.Getenv("CORS_ALLOWED_ORIGINS") == "" {
+		r.add(pass, "CORS_ALLOWED_ORIGINS", "not set, browsers on other sites can't call the API")
+	} else {
+		r.add(pass, "CORS_ALLOWED_ORIGINS", os.Getenv("CORS_ALLOWED_ORIGINS"))
+	}
+
+	for _, key := range []string{"RATE_LIMIT_PER_IP", "RATE_LIMIT_PER_KEY"} {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(v); err != nil || n < 0 {
+			r.add(warn, key, "not a number of requests per minute, the default will be used")
+		} else if n == 0 {
+			r.add(warn, key, "0, rate limiting is disabled")
+		} else {
+			r.add(pass, key, fmt.Sprintf("%d requests per minute", n))
+		}
+	}
+
+	if v := os.Getenv("TLS_DOMAINS"); v != "" {
+		r.add(pass, "TLS_DOMAINS", "set, HTTPS is served on port 443 for "+v+" and port 80 redirects to it")
+	}
+
+	if v := os.Getenv("SESSION_MAX_AGE_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 {
+			r.add(warn, "SESSION_MAX_AGE_DAYS", "not a positive number of days, the default will be used")
+		} else {
+			r.add(pass, "SESSION_MAX_AGE_DAYS", fmt.Sprintf("admins who are remembered stay signed in for %d days", n))
+		}
+	}
+
+	if v := os.Getenv("ARCHIVE_AFTER_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n < 0 {
+			r.add(warn, "ARCHIVE_AFTER_DAYS", "not a number of days, the default will be used")
+		} else if n == 0 {
+			r.add(pass, "ARCHIVE_AFTER_DAYS", "0, events are never archived")
+		} else {
+			r.add(pass, "ARCHIVE_AFTER_DAYS", fmt.Sprintf("events are archived %d days after their date", n))
+		}
+	}
+}
+
+func checkDatabase(ctx context.Context, r *report) *sql.DB {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db, err := database.Open(ctx)
+	if err != nil {
+		r.add(fail, "Database", err.Error())
+		return nil
+	}
+	r.add(pass, "Database", "connected")
+	return db
+}
+
+func checkMigrations(ctx context.Context, r *report, db *sql.DB) {
+	current, latest, err := database.MigrationStatus(ctx, db)
+	if err != nil {
+		r.add(fail, "Migrations", err.Error())
+		return
+	}
+	if current < latest {
+		r.add(warn, "Migrations", fmt.Sprintf("at %d, %d is available (applied on next start)", current, latest))
+		return
+	}
+	r.add(pass, "Migrations", fmt.Sprintf("up to date (%d)", current))
+}
+
+func checkBot(r *report) *tgbotapi.BotAPI {
+	token := os.Getenv("TELEGRAM_BOT_TOKEN")
+	if token == "" {
+		r.add(fail, "Bot token", "skipped, TELEGRAM_BOT_TOKEN not set")
+		return nil
+	}
+
+	// NewBotAPI calls getMe to validate the token
+	bot, err := tgbotapi.NewBotAPI(token)
+	if err != nil {
+		r.add(fail, "Bot token", err.Error())
+		return nil
+	}
+	r.add(pass, "Bot token", "valid, @"+bot.Self.UserName)
+	return bot
+}
+
+func checkWebhook(r *report, bot *tgbotapi.BotAPI) {
+	info, err := bot.GetWebhookInfo()
+	if err != nil {
+		r.add(fail, "Webhook", err.Error())
+		return
+	}
+
+	if !info.IsSet() {
+		r.add(pass, "Webhook", "not set, using long polling")
+		return
+	}
+
+	// The bot uses long polling, which Telegram refuses while a webhook is set
+	r.add(fail, "Webhook", "set to "+info.URL+", long polling will not receive updates")
+	if info.LastErrorDate != 0 {
+		r.add(fail, "Webhook delivery", info.LastErrorMessage)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(info.URL)
+	if err != nil {
+		r.add(fail, "Webhook reachable", err.Error())
+		return
+	}
+	resp.Body.Close()
+	r.add(pass, "Webhook reachable", resp.Status)
+}
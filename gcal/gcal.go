@@ -0,0 +1,256 @@
+// Package gcal keeps events in a Google Calendar, signing in as a service
+// account the calendar is shared with.
+package gcal
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	calendarAPIURL = "https://www.googleapis.com/calendar/v3/calendars/"
+	calendarScope  = "https://www.googleapis.com/auth/calendar.events"
+	wallClock      = "2006-01-02T15:04:05"
+)
+
+// ErrNotFound is returned for calendar events that were deleted in the
+// calendar.
+var ErrNotFound = errors.New("calendar event not found")
+
+// Event is what the calendar shows for an event. Start and End are
+// wall-clock times in TimeZone, an IANA name like Europe/Kyiv; their own
+// location is ignored.
+type Event struct {
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	TimeZone    string
+}
+
+// Client creates, updates and deletes the events of one calendar.
+type Client struct {
+	calendarID string
+	email      string
+	tokenURL   string
+	key        *rsa.PrivateKey
+	http       *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// serviceAccount is the part of a service account's JSON key file the
+// client needs.
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// New returns a client for the calendar, authenticated with the service
+// account JSON key.
+func New(calendarID string, serviceAccountKey []byte) (*Client, error) {
+	var account serviceAccount
+	if err := json.Unmarshal(serviceAccountKey, &account); err != nil {
+		return nil, fmt.Errorf("parse service account key: %w", err)
+	}
+	if account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, errors.New("service account key has no client_email or token_uri")
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, errors.New("service account key has no PEM private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse service account private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private key isn't RSA")
+	}
+
+	return &Client{
+		calendarID: calendarID,
+		email:      account.ClientEmail,
+		tokenURL:   account.TokenURI,
+		key:        key,
+		http:       &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Upsert updates the calendar event with the given ID, or creates one if
+// id is empty or the event was deleted in the calendar, and returns its ID.
+func (c *Client) Upsert(ctx context.Context, id string, event Event) (string, error) {
+	if id != "" {
+		err := c.call(ctx, http.MethodPut, "/events/"+url.PathEscape(id), &event, nil)
+		if !errors.Is(err, ErrNotFound) {
+			return id, err
+		}
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/events", &event, &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// Delete removes the calendar event. Events already deleted in the
+// calendar are not an error.
+func (c *Client) Delete(ctx context.Context, id string) error {
+	err := c.call(ctx, http.MethodDelete, "/events/"+url.PathEscape(id), nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+type eventTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+type eventResource struct {
+	Summary     string    `json:"summary"`
+	Description string    `json:"description"`
+	Start       eventTime `json:"start"`
+	End         eventTime `json:"end"`
+}
+
+// call sends event, if it isn't nil, to the calendar API and decodes the
+// response into result, if it isn't nil.
+func (c *Client) call(ctx context.Context, method, path string, event *Event, result any) error {
+	var body io.Reader
+	if event != nil {
+		raw, err := json.Marshal(eventResource{
+			Summary:     event.Summary,
+			Description: event.Description,
+			Start:       eventTime{DateTime: event.Start.Format(wallClock), TimeZone: event.TimeZone},
+			End:         eventTime{DateTime: event.End.Format(wallClock), TimeZone: event.TimeZone},
+		})
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, calendarAPIURL+url.PathEscape(c.calendarID)+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrNotFound
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("google calendar: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	case result != nil:
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
+
+// accessToken returns an OAuth token for the service account, exchanging a
+// signed JWT for a new one shortly before the current one expires.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Until(c.tokenExpiry) > time.Minute {
+		return c.token, nil
+	}
+
+	assertion, err := c.signJWT(time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("google token: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	c.token = token.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+func (c *Client) signJWT(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iss":   c.email,
+		"scope": calendarScope,
+		"aud":   c.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
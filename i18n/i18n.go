@@ -0,0 +1,96 @@
+// Package i18n picks the language to show events in and applies their
+// translations.
+package i18n
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"giveaway-tool/database/sqlc"
+)
+
+// Default is the language events are written in, which needs no
+// translation.
+const Default = "uk"
+
+type Language struct {
+	Code string
+	Name string
+}
+
+// Languages lists the languages pages can be shown in, Default first.
+var Languages = []Language{
+	{Code: Default, Name: "Українська"},
+	{Code: "en", Name: "English"},
+	{Code: "pl", Name: "Polski"},
+}
+
+// Translatable returns the languages events can be translated to.
+func Translatable() []Language {
+	return Languages[1:]
+}
+
+// Find returns the language with code, if it is one of Languages.
+func Find(code string) (Language, bool) {
+	i := slices.IndexFunc(Languages, func(l Language) bool { return l.Code == code })
+	if i < 0 {
+		return Language{}, false
+	}
+	return Languages[i], true
+}
+
+// Supported reports whether code is one of Languages.
+func Supported(code string) bool {
+	_, ok := Find(code)
+	return ok
+}
+
+// Normalize maps a language tag such as "en-US" or Telegram's "en" to a
+// supported code, or returns "" if there is none.
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	base, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	if Supported(base) {
+		return base
+	}
+	return ""
+}
+
+// FromAcceptLanguage returns the supported language the browser prefers
+// most, or Default if it accepts none of them.
+func FromAcceptLanguage(header string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if code := Normalize(tag); code != "" && q > bestQ {
+			best, bestQ = code, q
+		}
+	}
+	return best
+}
+
+// Translate returns a copy of event with the name and description of t.
+// Fields t leaves empty keep the original text, so a partly translated
+// event still shows everything.
+func Translate(event *sqlc.Events, t *sqlc.EventTranslations) *sqlc.Events {
+	translated := *event
+	if t == nil {
+		return &translated
+	}
+	if t.Name != "" {
+		translated.Name = t.Name
+	}
+	if t.Description != "" {
+		translated.Description.String = t.Description
+		translated.Description.Valid = true
+	}
+	return &translated
+}
@@ -0,0 +1,119 @@
+// Package ical writes iCalendar feeds (RFC 5545) that calendar apps like
+// Google Calendar and Apple Calendar subscribe to. Only what the event
+// feeds need is supported: events with a start, an end and some text.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of calendars.
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineLength is the longest line, in bytes, calendars may have. Longer
+// ones are folded onto continuation lines.
+const maxLineLength = 75
+
+// utcFormat is how times are written, in UTC.
+const utcFormat = "20060102T150405Z"
+
+// Calendar is a named set of events.
+type Calendar struct {
+	// ID identifies the app that made the calendar
+	ID   string
+	Name string
+	// RefreshInterval suggests how often apps fetch the calendar again
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event is what the calendar shows for one event. Start and End are
+// written in UTC, so they must be in the event's location.
+type Event struct {
+	// UID stays the same for the event across fetches, so apps update it
+	// instead of adding it again
+	UID string
+	// Sequence goes up with every change of the event
+	Sequence    int32
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+}
+
+// Write writes cal to w.
+func Write(w io.Writer, cal Calendar) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format(utcFormat)
+	line := func(name, value string) {
+		writeLine(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", cal.ID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escape(cal.Name))
+	}
+	if cal.RefreshInterval > 0 {
+		refresh := fmt.Sprintf("PT%dM", max(int(cal.RefreshInterval.Minutes()), 1))
+		writeLine(bw, "REFRESH-INTERVAL;VALUE=DURATION:"+refresh)
+		line("X-PUBLISHED-TTL", refresh)
+	}
+	for _, event := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escape(event.UID))
+		line("SEQUENCE", strconv.Itoa(int(event.Sequence)))
+		line("DTSTAMP", stamp)
+		line("DTSTART", event.Start.UTC().Format(utcFormat))
+		line("DTEND", event.End.UTC().Format(utcFormat))
+		line("SUMMARY", escape(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escape(event.Description))
+		}
+		if event.URL != "" {
+			line("URL", event.URL)
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// escape escapes the characters that have a meaning in text values.
+var escape = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+).Replace
+
+// writeLine writes s as a content line, folded so no line is longer than
+// maxLineLength without splitting a character.
+func writeLine(w *bufio.Writer, s string) {
+	limit := maxLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts
+		limit = maxLineLength - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,95 @@
+// Package lifecycle moves events through their statuses: drafts are only
+// seen by admins, published events are listed publicly, and participants
+// can register only while registration is open, until it's closed, and
+// within the event's registration window if it has one.
+package lifecycle
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+// Statuses of an event, stored in events.status.
+const (
+	Draft            = "draft"
+	Published        = "published"
+	RegistrationOpen = "registration_open"
+	Closed           = "closed"
+)
+
+// transitions lists the statuses each status can move to. Events move
+// forward one step at a time, and can go back a step to be unpublished or
+// to reopen registration.
+var transitions = map[string][]string{
+	Draft:            {Published},
+	Published:        {Draft, RegistrationOpen},
+	RegistrationOpen: {Closed},
+	Closed:           {RegistrationOpen},
+}
+
+var (
+	// ErrNotOpen is returned when registering for an event whose
+	// registration isn't open.
+	ErrNotOpen = apperr.Conflict("Registration for this event is not open")
+	// ErrTransition is returned for moves the lifecycle doesn't allow.
+	ErrTransition = apperr.Conflict("Event can't move to this status")
+)
+
+// Valid reports whether status is one of the constants above.
+func Valid(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// Next returns the statuses an event with status can move to.
+func Next(status string) []string {
+	return transitions[status]
+}
+
+// CanMove reports whether an event can move from one status to another.
+func CanMove(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// Move changes the event's status in tx, failing with ErrTransition if it
+// can't move there from the one it has.
+func Move(ctx context.Context, tx store.Store, eventID int64, status string) (*sqlc.Events, error) {
+	event, err := tx.GetEventForUpdate(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !CanMove(event.Status, status) {
+		return nil, ErrTransition
+	}
+	return tx.SetEventStatus(ctx, &sqlc.SetEventStatusParams{ID: eventID, Status: status})
+}
+
+// CheckOpen returns ErrNotOpen unless the event takes registrations: its
+// registration is open and now is within its registration window.
+func CheckOpen(event *sqlc.Events) error {
+	if event.Status != RegistrationOpen || NotYetOpen(event) || WindowClosed(event) {
+		return ErrNotOpen
+	}
+	return nil
+}
+
+// NotYetOpen reports whether the event's registration window hasn't
+// started yet.
+func NotYetOpen(event *sqlc.Events) bool {
+	return event.RegistrationOpensAt.Valid && time.Now().Before(event.RegistrationOpensAt.Time)
+}
+
+// WindowClosed reports whether the event's registration window is over.
+func WindowClosed(event *sqlc.Events) bool {
+	return event.RegistrationClosesAt.Valid && !time.Now().Before(event.RegistrationClosesAt.Time)
+}
+
+// Public reports whether the event is shown outside the admin panel.
+func Public(event *sqlc.Events) bool {
+	return event.Status != Draft
+}
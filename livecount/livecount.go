@@ -0,0 +1,55 @@
+// Package livecount passes the participant counts of events to the admin
+// pages showing them live. Counts are kept in memory, so with several
+// instances a page only hears of registrations made on its instance.
+package livecount
+
+import "sync"
+
+var live = struct {
+	mu   sync.Mutex
+	subs map[int64]map[chan int64]struct{}
+}{subs: make(map[int64]map[chan int64]struct{})}
+
+// Subscribe returns a channel receiving the participant counts of the
+// event published from now on and a function ending the subscription. A
+// subscriber that falls behind only gets the latest count.
+func Subscribe(eventID int64) (<-chan int64, func()) {
+	ch := make(chan int64, 1)
+	live.mu.Lock()
+	if live.subs[eventID] == nil {
+		live.subs[eventID] = make(map[chan int64]struct{})
+	}
+	live.subs[eventID][ch] = struct{}{}
+	live.mu.Unlock()
+
+	return ch, func() {
+		live.mu.Lock()
+		delete(live.subs[eventID], ch)
+		if len(live.subs[eventID]) == 0 {
+			delete(live.subs, eventID)
+		}
+		live.mu.Unlock()
+	}
+}
+
+// Watched reports whether anyone is subscribed to the event's count, so
+// callers can skip counting when nobody is.
+func Watched(eventID int64) bool {
+	live.mu.Lock()
+	defer live.mu.Unlock()
+	return len(live.subs[eventID]) > 0
+}
+
+// Publish sends the event's participant count to its subscribers. It never
+// blocks: a count not yet received is replaced.
+func Publish(eventID, count int64) {
+	live.mu.Lock()
+	defer live.mu.Unlock()
+	for ch := range live.subs[eventID] {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- count
+	}
+}
@@ -0,0 +1,75 @@
+// Package logging builds the application logger shared by all subsystems.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// New builds a logger configured by the environment:
+//
+//   - LOG_FORMAT: "text" (default) or "json"
+//   - LOG_LEVEL: "debug", "info" (default), "warn" or "error"
+//   - LOG_FILE: write to this file instead of stdout, rotating it once it
+//     reaches LOG_MAX_SIZE_MB (default 100) and keeping LOG_MAX_BACKUPS
+//     (default 5) old files
+//
+// The returned closer releases the log file and must be called on exit.
+func New() (*slog.Logger, io.Closer, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(envOr("LOG_LEVEL", "info"))); err != nil {
+		return nil, nil, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+
+	var out io.WriteCloser = nopCloser{os.Stdout}
+	if path := os.Getenv("LOG_FILE"); path != "" {
+		maxSize, err := strconv.Atoi(envOr("LOG_MAX_SIZE_MB", "100"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid LOG_MAX_SIZE_MB: %w", err)
+		}
+		maxBackups, err := strconv.Atoi(envOr("LOG_MAX_BACKUPS", "5"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid LOG_MAX_BACKUPS: %w", err)
+		}
+		out = &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSize,
+			MaxBackups: maxBackups,
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch format := strings.ToLower(envOr("LOG_FORMAT", "text")); format {
+	case "text":
+		handler = slog.NewTextHandler(out, opts)
+	case "json":
+		handler = slog.NewJSONHandler(out, opts)
+	default:
+		return nil, nil, fmt.Errorf("invalid LOG_FORMAT %q", format)
+	}
+
+	return slog.New(handler), out, nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,87 @@
+package magiclink
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scope is what an access link lets a volunteer do with an event.
+type Scope string
+
+const (
+	// ScopeView shows the event and its participants.
+	ScopeView Scope = "view"
+	// ScopeCheckIn also lets the volunteer check participants in.
+	ScopeCheckIn Scope = "checkin"
+)
+
+// ParseScope returns the scope named s, or ScopeView for anything else so
+// that a malformed value never grants more than viewing.
+func ParseScope(s string) Scope {
+	if Scope(s) == ScopeCheckIn {
+		return ScopeCheckIn
+	}
+	return ScopeView
+}
+
+// Access is what a verified access link grants.
+type Access struct {
+	EventID int64
+	Scope   Scope
+	Expires time.Time
+}
+
+// accessPrefix starts the payload of access tokens, so they can't be
+// passed off as other kinds of token or the other way round.
+const accessPrefix = "access"
+
+// AccessToken returns a token granting scope on eventID until ttl from now.
+// Tokens are "access.<event id>.<scope>.<expiry unix time>.<hex hmac>".
+func (s *Signer) AccessToken(eventID int64, scope Scope, ttl time.Duration) string {
+	payload := fmt.Sprintf("%s.%d.%s.%d", accessPrefix, eventID, scope, time.Now().Add(ttl).Unix())
+	return payload + "." + s.sign(payload)
+}
+
+// AccessURL returns the link that opens eventID's volunteer page.
+func (s *Signer) AccessURL(eventID int64, scope Scope, ttl time.Duration) string {
+	return s.baseURL + "/access/" + s.AccessToken(eventID, scope, ttl)
+}
+
+// VerifyAccess returns what an access token grants.
+func (s *Signer) VerifyAccess(token string) (*Access, error) {
+	fields, err := s.fields(token, accessPrefix, 3)
+	if err != nil {
+		return nil, err
+	}
+	eventID, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	expiry, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil || time.Now().Unix() > expiry {
+		return nil, ErrInvalid
+	}
+	return &Access{EventID: eventID, Scope: ParseScope(fields[1]), Expires: time.Unix(expiry, 0)}, nil
+}
+
+// fields checks the signature of a "<prefix>.<field>....<hmac>" token and
+// returns its n fields.
+func (s *Signer) fields(token, prefix string, n int) ([]string, error) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return nil, ErrInvalid
+	}
+	payload, sig := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.sign(payload))) {
+		return nil, ErrInvalid
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != n+1 || parts[0] != prefix {
+		return nil, ErrInvalid
+	}
+	return parts[1:], nil
+}
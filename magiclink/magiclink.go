@@ -0,0 +1,83 @@
+// Package magiclink signs the links the bot sends to participants, which
+// open a page where they can manage their registration without logging in,
+// and the time-limited links admins give volunteers to help with one event.
+package magiclink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TTL is how long a link stays valid. Participants can ask the bot for a
+// fresh one at any time.
+const TTL = 30 * 24 * time.Hour
+
+var ErrInvalid = errors.New("invalid or expired link")
+
+type Signer struct {
+	secret  []byte
+	baseURL string
+}
+
+// FromEnv returns a signer configured by MAGIC_LINK_SECRET and PUBLIC_URL,
+// or nil if either is unset, in which case no links are issued.
+func FromEnv() *Signer {
+	secret := os.Getenv("MAGIC_LINK_SECRET")
+	baseURL := strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/")
+	if secret == "" || baseURL == "" {
+		return nil
+	}
+	return &Signer{secret: []byte(secret), baseURL: baseURL}
+}
+
+// Token returns a token for userID that expires after TTL. Tokens are
+// "<user id>.<expiry unix time>.<hex hmac>", which needs no escaping in URLs
+// or Telegram Markdown.
+func (s *Signer) Token(userID int64) string {
+	payload := fmt.Sprintf("%d.%d", userID, time.Now().Add(TTL).Unix())
+	return payload + "." + s.sign(payload)
+}
+
+// URL returns the link to the self-service page for userID.
+func (s *Signer) URL(userID int64) string {
+	return s.baseURL + "/me/" + s.Token(userID)
+}
+
+// Verify returns the user ID a token was issued for.
+func (s *Signer) Verify(token string) (int64, error) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return 0, ErrInvalid
+	}
+	payload, sig := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.sign(payload))) {
+		return 0, ErrInvalid
+	}
+
+	rawID, rawExpiry, ok := strings.Cut(payload, ".")
+	if !ok {
+		return 0, ErrInvalid
+	}
+	expiry, err := strconv.ParseInt(rawExpiry, 10, 64)
+	if err != nil || time.Now().Unix() > expiry {
+		return 0, ErrInvalid
+	}
+	userID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	return userID, nil
+}
+
+func (s *Signer) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
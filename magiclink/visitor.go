@@ -0,0 +1,32 @@
+package magiclink
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// visitorPrefix starts the payload of visitor tokens, so they can't be
+// passed off as other kinds of token or the other way round.
+const visitorPrefix = "visitor"
+
+// VisitorToken returns a token for the first-party cookie that marks a
+// browser as a visitor of share links, valid until ttl from now. Tokens are
+// "visitor.<id>.<expiry unix time>.<hex hmac>", id having no dots.
+func (s *Signer) VisitorToken(id string, ttl time.Duration) string {
+	payload := fmt.Sprintf("%s.%s.%d", visitorPrefix, id, time.Now().Add(ttl).Unix())
+	return payload + "." + s.sign(payload)
+}
+
+// VerifyVisitor returns the ID a visitor token was issued for.
+func (s *Signer) VerifyVisitor(token string) (string, error) {
+	fields, err := s.fields(token, visitorPrefix, 2)
+	if err != nil {
+		return "", err
+	}
+	expiry, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || time.Now().Unix() > expiry || fields[0] == "" {
+		return "", ErrInvalid
+	}
+	return fields[0], nil
+}
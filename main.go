@@ -4,44 +4,235 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"giveaway-tool/config"
 	"giveaway-tool/database"
 	"giveaway-tool/database/sqlc"
+	"giveaway-tool/demo"
+	"giveaway-tool/doctor"
+	"giveaway-tool/logging"
 	"giveaway-tool/service"
+	"giveaway-tool/store"
+	"giveaway-tool/store/memory"
 	"giveaway-tool/telegram"
+	"giveaway-tool/tracing"
 
 	"github.com/joho/godotenv"
+	"golang.org/x/crypto/acme/autocert"
 )
 
+// shutdownTimeout is how long requests and Telegram updates in flight get
+// to finish after a stop signal. It stays under the kill_timeout in
+// fly.toml, after which the process is killed.
+const shutdownTimeout = 25 * time.Second
+
 func main() {
-	ctx := context.TODO()
-	logger := slog.Default()
-	router := http.NewServeMux()
-	err := godotenv.Load()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	envErr := godotenv.Load()
+
+	logger, logCloser, err := logging.New()
 	if err != nil {
-		logger.LogAttrs(ctx, slog.LevelError, "Failed to load .env file", slog.Any("error", err))
+		slog.LogAttrs(ctx, slog.LevelError, "Failed to configure logger", slog.Any("error", err))
+		return
 	}
+	defer logCloser.Close()
+	slog.SetDefault(logger)
 
-	db, err := database.New(ctx)
-	if err != nil {
-		logger.LogAttrs(ctx, slog.LevelError, "Failed to connect to database", slog.Any("error", err))
+	if envErr != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "Failed to load .env file", slog.Any("error", envErr))
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		if !doctor.Run(ctx, os.Stdout) {
+			os.Exit(1)
+		}
 		return
 	}
 
-	config.InitConfig(ctx, sqlc.New(db))
+	shutdownTracing, err := tracing.Setup(ctx)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "Failed to set up tracing", slog.Any("error", err))
+	} else {
+		defer func() {
+			// Deferred first, so it runs once the requests finished during
+			// shutdown have their spans
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				logger.LogAttrs(ctx, slog.LevelError, "Failed to send traces", slog.Any("error", err))
+			}
+		}()
+	}
+
+	router := http.NewServeMux()
+
+	var st store.Store
+	var demoEvent *sqlc.Events
+	if demo.Enabled() {
+		logger.LogAttrs(ctx, slog.LevelWarn, "Running in demo mode, data is kept in memory only")
+		mem := memory.New()
+		if demoEvent, err = demo.Seed(ctx, mem); err != nil {
+			logger.LogAttrs(ctx, slog.LevelError, "Failed to seed demo data", slog.Any("error", err))
+			return
+		}
+		st = mem
+		// Don't let the demo overwrite the current event of a real setup
+		config.SetConfigFile("")
+	} else {
+		db, err := database.New(ctx)
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelError, "Failed to connect to database", slog.Any("error", err))
+			return
+		}
+
+		cacheTTL := 30 * time.Second
+		if v := os.Getenv("CACHE_TTL"); v != "" {
+			if cacheTTL, err = time.ParseDuration(v); err != nil {
+				logger.LogAttrs(ctx, slog.LevelError, "Invalid CACHE_TTL value", slog.Any("error", err))
+				return
+			}
+		}
+
+		st = store.NewCached(store.NewSQL(db), cacheTTL)
+	}
+	// Admin pages show participant counts as they change
+	st = store.NewLive(st)
+
+	config.InitConfig(ctx, st)
+	if demoEvent != nil {
+		config.SetCurrentEventID(ctx, demoEvent.ID)
+	}
+	if err := config.LoadFlags(ctx, st); err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "Failed to load feature flags", slog.Any("error", err))
+	}
 	logger.LogAttrs(ctx, slog.LevelInfo, "Current event ID", slog.Int64("event_id", config.GetCurrentEventID()))
 
-	service.Start(router, logger, db)
-	telegram.Start(ctx, logger, db)
+	// The pages link to the bot by the username Telegram gave it
+	var tgBot telegram.Bot
+	var fakeBot *telegram.FakeBot
+	if demo.Enabled() {
+		fakeBot = telegram.NewFakeBot()
+		tgBot = fakeBot
+	} else if apiBot, err := telegram.NewBot(os.Getenv("TELEGRAM_BOT_TOKEN")); err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "Failed to create Telegram bot", slog.Any("error", err))
+	} else {
+		tgBot = apiBot
+	}
+
+	var botUsername string
+	if tgBot != nil {
+		botUsername = tgBot.Username()
+	}
+	service.Start(ctx, router, logger, st, botUsername)
+	var bot *telegram.Service
+	if tgBot != nil {
+		bot = telegram.Start(ctx, logger, st, tgBot)
+	}
+	if fakeBot != nil {
+		demo.Start(ctx, router, logger, fakeBot)
+	}
 
 	port := os.Getenv("PORT")
+	if port == "" && demo.Enabled() {
+		port = "8080"
+	}
 
-	logger.LogAttrs(ctx, slog.LevelInfo, "Starting server", slog.String("port", port))
-	if err := http.ListenAndServe(fmt.Sprintf(":%s", port), router); err != nil {
+	// Requests aren't cancelled by a stop signal, so draws and
+	// registrations in flight can finish, but only for shutdownTimeout
+	requests, cancelRequests := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelRequests()
+	servers := []*http.Server{{
+		Addr:    fmt.Sprintf(":%s", port),
+		Handler: router,
+		BaseContext: func(net.Listener) context.Context {
+			return requests
+		},
+	}}
+	// With TLS_DOMAINS set the app serves HTTPS on port 443 itself, and
+	// port 80 answers certificate challenges and redirects to HTTPS. PORT
+	// is ignored then.
+	if certs := autocertFromEnv(); certs != nil {
+		servers[0].Addr = ":https"
+		servers[0].TLSConfig = certs.TLSConfig()
+		servers = append(servers, &http.Server{
+			Addr:              ":http",
+			Handler:           certs.HTTPHandler(nil),
+			ReadHeaderTimeout: 10 * time.Second,
+		})
+	}
+
+	serveErr := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			logger.LogAttrs(ctx, slog.LevelInfo, "Starting server", slog.String("addr", server.Addr))
+			if server.TLSConfig != nil {
+				serveErr <- server.ListenAndServeTLS("", "")
+			} else {
+				serveErr <- server.ListenAndServe()
+			}
+		}()
+	}
+
+	select {
+	case err := <-serveErr:
 		logger.LogAttrs(ctx, slog.LevelError, "Failed to start server", slog.Any("error", err))
 		return
+	case <-ctx.Done():
+	}
+	// A second signal stops the process right away
+	stop()
+
+	logger.LogAttrs(context.Background(), slog.LevelInfo, "Stopping server", slog.Duration("timeout", shutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, server := range servers {
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.LogAttrs(shutdownCtx, slog.LevelError, "Failed to finish requests before shutdown", slog.Any("error", err))
+			cancelRequests()
+			server.Close()
+		}
+	}
+	if bot != nil {
+		if err := bot.Wait(shutdownCtx); err != nil {
+			logger.LogAttrs(shutdownCtx, slog.LevelError, "Failed to finish Telegram updates before shutdown", slog.Any("error", err))
+		}
+	}
+	logger.LogAttrs(context.Background(), slog.LevelInfo, "Server stopped")
+}
+
+// autocertFromEnv returns a manager getting certificates for the
+// comma-separated TLS_DOMAINS from Let's Encrypt, or nil if there are none.
+// Certificates are kept in TLS_CACHE_DIR, "certs" by default, so restarts
+// don't run into the rate limits. Let's Encrypt sends expiry notices to
+// TLS_EMAIL, if set.
+func autocertFromEnv() *autocert.Manager {
+	var domains []string
+	for _, domain := range strings.Split(os.Getenv("TLS_DOMAINS"), ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	if len(domains) == 0 {
+		return nil
+	}
+
+	cacheDir := os.Getenv("TLS_CACHE_DIR")
+	if cacheDir == "" {
+		cacheDir = "certs"
+	}
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      os.Getenv("TLS_EMAIL"),
 	}
 }
@@ -0,0 +1,228 @@
+// Package markdown renders the Markdown admins write in event descriptions:
+// as sanitized HTML for the web pages and as Telegram's Markdown for bot
+// messages.
+package markdown
+
+import (
+	"bytes"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"giveaway-tool/sanitize"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Line breaks are kept as typed, since descriptions were plain text before
+// and admins expect them to show.
+var md = goldmark.New(
+	goldmark.WithExtensions(extension.Linkify, extension.Strikethrough),
+	// HTML in the source is kept and cleaned along with the rest of the
+	// output
+	goldmark.WithRendererOptions(html.WithHardWraps(), html.WithUnsafe()),
+)
+
+// HTML renders src as HTML cleaned by the sanitize package.
+func HTML(src string) template.HTML {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(src), &buf); err != nil {
+		return template.HTML(template.HTMLEscapeString(src))
+	}
+	return template.HTML(sanitize.HTML(buf.String()))
+}
+
+var telegramEscaper = strings.NewReplacer("_", `\_`, "*", `\*`, "`", "\\`", "[", `\[`)
+
+// EscapeTelegram escapes text so Telegram's Markdown shows it as is.
+func EscapeTelegram(s string) string {
+	return telegramEscaper.Replace(s)
+}
+
+// Telegram converts src to the Markdown of the Bot API's "Markdown" parse
+// mode. It has bold, italic, links and code only, and they can't nest, so
+// headings become bold lines, lists get bullets and other formatting is
+// reduced to its text.
+func Telegram(src string) string {
+	source := []byte(src)
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	var b strings.Builder
+	// style is the bold, italic or link node being written; no other
+	// formatting may start inside it. Escapes don't work there either, so
+	// its closing character is dropped from the text instead.
+	var (
+		style   ast.Node
+		closing string
+	)
+	open := func(n ast.Node, marker, end string) {
+		if style == nil {
+			style, closing = n, end
+			b.WriteString(marker)
+		}
+	}
+	closeStyle := func(n ast.Node, marker string) {
+		if style == n {
+			style = nil
+			b.WriteString(marker)
+		}
+	}
+	write := func(s string) {
+		if style == nil {
+			b.WriteString(EscapeTelegram(s))
+		} else {
+			b.WriteString(strings.ReplaceAll(s, closing, ""))
+		}
+	}
+	var writeText func(n ast.Node)
+	writeText = func(n ast.Node) {
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			if t, ok := c.(*ast.Text); ok {
+				write(string(unescape(t.Segment.Value(source))))
+			}
+			writeText(c)
+		}
+	}
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch n := n.(type) {
+		case *ast.Paragraph, *ast.Blockquote:
+			if !entering && n.Parent() == doc {
+				b.WriteString("\n\n")
+			} else if !entering {
+				b.WriteString("\n")
+			}
+		case *ast.Heading:
+			if entering {
+				open(n, "*", "*")
+			} else {
+				closeStyle(n, "*")
+				b.WriteString("\n\n")
+			}
+		case *ast.ThematicBreak:
+			if entering {
+				b.WriteString("———\n\n")
+			}
+		case *ast.List:
+			if !entering && n.Parent() == doc {
+				b.WriteString("\n")
+			}
+		case *ast.ListItem:
+			if entering {
+				list := n.Parent().(*ast.List)
+				if list.IsOrdered() {
+					index := list.Start
+					for c := list.FirstChild(); c != nil && c != n; c = c.NextSibling() {
+						index++
+					}
+					b.WriteString(strconv.Itoa(index) + ". ")
+				} else {
+					b.WriteString("• ")
+				}
+			} else if !strings.HasSuffix(b.String(), "\n") {
+				b.WriteString("\n")
+			}
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			if entering {
+				b.WriteString("```\n")
+				lines := n.Lines()
+				for i := range lines.Len() {
+					line := lines.At(i)
+					b.Write(bytes.ReplaceAll(line.Value(source), []byte("```"), nil))
+				}
+				b.WriteString("```\n\n")
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Emphasis:
+			marker := "_"
+			if n.Level == 2 {
+				marker = "*"
+			}
+			if entering {
+				open(n, marker, marker)
+			} else {
+				closeStyle(n, marker)
+			}
+		case *ast.Link:
+			if !linkable(n.Destination) {
+				break
+			}
+			if entering {
+				open(n, "[", "]")
+			} else {
+				closeStyle(n, "]("+string(n.Destination)+")")
+			}
+		case *ast.Image:
+			if entering && linkable(n.Destination) {
+				open(n, "[", "]")
+				writeText(n)
+				closeStyle(n, "]("+string(n.Destination)+")")
+			} else if entering {
+				writeText(n)
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.AutoLink:
+			// As link text the URL needs no escapes, which Telegram would
+			// otherwise have to undo before recognizing it
+			if entering {
+				url := string(n.URL(source))
+				if n.AutoLinkType == ast.AutoLinkEmail {
+					url = "mailto:" + url
+				}
+				open(n, "[", "]")
+				write(string(n.Label(source)))
+				closeStyle(n, "]("+url+")")
+			}
+		case *ast.CodeSpan:
+			if entering {
+				var code strings.Builder
+				for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+					if t, ok := c.(*ast.Text); ok {
+						code.Write(t.Segment.Value(source))
+					}
+				}
+				if style == nil {
+					b.WriteString("`" + strings.ReplaceAll(code.String(), "`", "'") + "`")
+				} else {
+					write(code.String())
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			if entering {
+				write(string(unescape(n.Segment.Value(source))))
+				if n.HardLineBreak() || n.SoftLineBreak() {
+					b.WriteString("\n")
+				}
+			}
+		case *ast.String:
+			if entering {
+				write(string(n.Value))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(b.String())
+}
+
+// linkable reports whether Telegram can open dest.
+func linkable(dest []byte) bool {
+	for _, scheme := range []string{"http://", "https://", "tg://", "mailto:"} {
+		if bytes.HasPrefix(bytes.ToLower(dest), []byte(scheme)) {
+			return true
+		}
+	}
+	return false
+}
+
+func unescape(value []byte) []byte {
+	return util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(value)))
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Notice is a notification shown right away to admins who have the admin
+// panel open, unlike Send, which only reaches recipients who opted in.
+// Notices are kept in memory, so with several instances each admin sees
+// those of the instance serving them.
+type Notice struct {
+	Kind    Kind      `json:"kind"`
+	EventID int64     `json:"event_id,omitempty"`
+	Text    string    `json:"text"`
+	At      time.Time `json:"at"`
+}
+
+// subscriberBuffer is how many notices a slow subscriber may fall behind
+// before further ones are dropped for it.
+const subscriberBuffer = 16
+
+// jobFailureInterval is how long a failing job stays quiet after a notice,
+// since jobs that run every few seconds would otherwise flood the panel.
+const jobFailureInterval = 10 * time.Minute
+
+var live = struct {
+	mu   sync.Mutex
+	subs map[chan Notice]struct{}
+	// jobFailures has the time of each job's last failure notice
+	jobFailures map[string]time.Time
+}{subs: make(map[chan Notice]struct{}), jobFailures: make(map[string]time.Time)}
+
+// Subscribe returns a channel receiving the notices published from now on
+// and a function ending the subscription.
+func Subscribe() (<-chan Notice, func()) {
+	ch := make(chan Notice, subscriberBuffer)
+	live.mu.Lock()
+	live.subs[ch] = struct{}{}
+	live.mu.Unlock()
+
+	return ch, func() {
+		live.mu.Lock()
+		delete(live.subs, ch)
+		live.mu.Unlock()
+	}
+}
+
+// Publish shows a notice to every subscriber. It never blocks: a
+// subscriber that isn't keeping up misses the notice.
+func Publish(kind Kind, eventID int64, text string) {
+	notice := Notice{Kind: kind, EventID: eventID, Text: text, At: time.Now()}
+
+	live.mu.Lock()
+	defer live.mu.Unlock()
+	for ch := range live.subs {
+		select {
+		case ch <- notice:
+		default:
+		}
+	}
+}
+
+// JobFailed tells admins watching the panel that a background job failed.
+// Jobs retry on their next tick, so the notice isn't sent to Telegram.
+func JobFailed(job string, err error) {
+	live.mu.Lock()
+	if time.Since(live.jobFailures[job]) < jobFailureInterval {
+		live.mu.Unlock()
+		return
+	}
+	live.jobFailures[job] = time.Now()
+	live.mu.Unlock()
+
+	Publish(Error, 0, fmt.Sprintf("Фонова задача «%s» завершилася з помилкою: %v", job, err))
+}
@@ -0,0 +1,104 @@
+// Package notify sends admin notifications through the Telegram outbox to
+// the recipients who opted in to them.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+)
+
+type Kind string
+
+const (
+	Registration Kind = "registration"
+	Milestone    Kind = "milestone"
+	Draw         Kind = "draw"
+	Error        Kind = "error"
+)
+
+type KindInfo struct {
+	Kind        Kind
+	Description string
+}
+
+// Kinds lists the notification kinds recipients can choose from.
+var Kinds = []KindInfo{
+	{Registration, "Кожна нова реєстрація"},
+	{Milestone, "Кількість учасників досягла позначки (10, 25, 50, кожні 100)"},
+	{Draw, "Результати розіграшів"},
+	{Error, "Помилки сервера"},
+}
+
+// IsKnownKind reports whether kind is one of Kinds.
+func IsKnownKind(kind Kind) bool {
+	for _, info := range Kinds {
+		if info.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Send queues text for every recipient of kind notifications about the
+// event. Notifications that aren't about an event pass eventID 0.
+func Send(ctx context.Context, st store.Store, kind Kind, eventID int64, text string) error {
+	recipients, err := st.GetNotificationRecipients(ctx, &sqlc.GetNotificationRecipientsParams{
+		Kind:    string(kind),
+		EventID: eventID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range recipients {
+		if _, err := st.EnqueueOutboxMessage(ctx, &sqlc.EnqueueOutboxMessageParams{
+			ChatID: recipient.ChatID,
+			Text:   text,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isMilestone reports whether reaching count participants is worth a
+// notification.
+func isMilestone(count int64) bool {
+	return count == 10 || count == 25 || count == 50 || (count > 0 && count%100 == 0)
+}
+
+// Registered notifies recipients about a new participant and, if the
+// event reached a milestone, about that too. Failures are only logged,
+// since the registration itself has succeeded.
+func Registered(ctx context.Context, st store.Store, user *sqlc.Users) {
+	if err := registered(ctx, st, user); err != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to send registration notifications",
+			slog.Int64("user_id", user.ID), slog.Any("error", err))
+	}
+}
+
+func registered(ctx context.Context, st store.Store, user *sqlc.Users) error {
+	event, err := st.GetEventByID(ctx, user.EventID)
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("Нова реєстрація на \"%s\": %s, квиток №%d", event.Name, user.Name, user.TicketNumber)
+	Publish(Registration, event.ID, text)
+	if err := Send(ctx, st, Registration, event.ID, text); err != nil {
+		return err
+	}
+
+	count, err := st.CountUsersByEventID(ctx, event.ID)
+	if err != nil || !isMilestone(count) {
+		return err
+	}
+	text = fmt.Sprintf("На \"%s\" зареєструвалося вже %d учасників!", event.Name, count)
+	Publish(Milestone, event.ID, text)
+	return Send(ctx, st, Milestone, event.ID, text)
+}
@@ -0,0 +1,78 @@
+// Package payments talks to the payment provider participants use to pay
+// for tickets and buy extra raffle entries.
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+)
+
+type Status int
+
+const (
+	StatusPending Status = iota
+	StatusPaid
+	StatusFailed
+)
+
+var ErrInvalidCallback = errors.New("invalid payment callback")
+
+type Order struct {
+	ID          string
+	Description string
+	// Amount is in kopecks.
+	Amount int32
+	// ResultURL is where the participant returns after paying.
+	ResultURL string
+}
+
+// Callback is a verified payment notification from the provider.
+type Callback struct {
+	OrderID string
+	// PaymentID is the provider's ID of the payment, kept for refunds
+	PaymentID string
+	Status    Status
+}
+
+// Refund returns a settled payment to the payer.
+type Refund struct {
+	OrderID   string
+	PaymentID string
+	// Amount is in kopecks.
+	Amount int32
+}
+
+type Provider interface {
+	// CheckoutURL returns the page where the participant pays for order.
+	CheckoutURL(ctx context.Context, order Order) (string, error)
+	// ParseCallback verifies a server-to-server notification about a
+	// payment, returning ErrInvalidCallback if it isn't authentic.
+	ParseCallback(r *http.Request) (*Callback, error)
+	// Refund returns a payment in full. It fails if the payment was
+	// already refunded.
+	Refund(ctx context.Context, refund Refund) error
+}
+
+// FromEnv returns the Stripe provider configured by STRIPE_SECRET_KEY and
+// STRIPE_WEBHOOK_SECRET or, failing that, the LiqPay provider configured by
+// LIQPAY_PUBLIC_KEY and LIQPAY_PRIVATE_KEY. It returns nil if neither is
+// set, in which case payments are disabled. LiqPay callbacks are sent to
+// PUBLIC_URL.
+func FromEnv() Provider {
+	if secretKey, webhookSecret := os.Getenv("STRIPE_SECRET_KEY"), os.Getenv("STRIPE_WEBHOOK_SECRET"); secretKey != "" && webhookSecret != "" {
+		return &Stripe{SecretKey: secretKey, WebhookSecret: webhookSecret}
+	}
+
+	publicKey := os.Getenv("LIQPAY_PUBLIC_KEY")
+	privateKey := os.Getenv("LIQPAY_PRIVATE_KEY")
+	if publicKey == "" || privateKey == "" {
+		return nil
+	}
+	return &LiqPay{
+		PublicKey:   publicKey,
+		PrivateKey:  privateKey,
+		CallbackURL: os.Getenv("PUBLIC_URL") + "/payments/callback",
+	}
+}
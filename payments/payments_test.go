@@ -0,0 +1,130 @@
+package payments_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"giveaway-tool/payments"
+)
+
+func TestLiqPayParseCallback(t *testing.T) {
+	liqPay := &payments.LiqPay{PublicKey: "public", PrivateKey: "private"}
+	data := base64.StdEncoding.EncodeToString([]byte(`{"order_id":"order-1","payment_id":42,"status":"success"}`))
+	tampered := base64.StdEncoding.EncodeToString([]byte(`{"order_id":"order-2","payment_id":42,"status":"success"}`))
+
+	tests := []struct {
+		name       string
+		data       string
+		signature  string
+		wantErr    bool
+		wantStatus payments.Status
+	}{
+		{name: "valid", data: data, signature: liqPaySign("private", data), wantStatus: payments.StatusPaid},
+		{name: "wrong key", data: data, signature: liqPaySign("other", data), wantErr: true},
+		{name: "data changed", data: tampered, signature: liqPaySign("private", data), wantErr: true},
+		{name: "no signature", data: data, wantErr: true},
+		{name: "no data", signature: liqPaySign("private", ""), wantErr: true},
+		{name: "signed garbage", data: "not base64", signature: liqPaySign("private", "not base64"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{"data": {tt.data}, "signature": {tt.signature}}
+			r := httptest.NewRequest(http.MethodPost, "/payments/callback", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			callback, err := liqPay.ParseCallback(r)
+			if tt.wantErr {
+				if !errors.Is(err, payments.ErrInvalidCallback) {
+					t.Errorf("ParseCallback() = %v, want %v", err, payments.ErrInvalidCallback)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCallback() = %v", err)
+			}
+			if callback.OrderID != "order-1" || callback.PaymentID != "42" || callback.Status != tt.wantStatus {
+				t.Errorf("ParseCallback() = %+v", callback)
+			}
+		})
+	}
+}
+
+func TestStripeParseCallback(t *testing.T) {
+	stripe := &payments.Stripe{SecretKey: "sk_test", WebhookSecret: "whsec_test"}
+	body := `{"type":"checkout.session.completed","data":{"object":{"client_reference_id":"order-1","payment_status":"paid","payment_intent":"pi_1"}}}`
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		wantErr   bool
+	}{
+		{name: "valid", body: body, signature: stripeHeader("whsec_test", now, body)},
+		{
+			name:      "one of several signatures",
+			body:      body,
+			signature: stripeHeader("whsec_old", now, body) + ",v1=" + stripeSign("whsec_test", now, body),
+		},
+		{name: "wrong secret", body: body, signature: stripeHeader("whsec_other", now, body), wantErr: true},
+		{name: "body changed", body: strings.Replace(body, "order-1", "order-2", 1), signature: stripeHeader("whsec_test", now, body), wantErr: true},
+		{name: "too old", body: body, signature: stripeHeader("whsec_test", now-600, body), wantErr: true},
+		{name: "from the future", body: body, signature: stripeHeader("whsec_test", now+600, body), wantErr: true},
+		{name: "no timestamp", body: body, signature: "v1=" + stripeSign("whsec_test", now, body), wantErr: true},
+		{name: "not hex", body: body, signature: fmt.Sprintf("t=%d,v1=zz", now), wantErr: true},
+		{name: "no header", body: body, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/payments/callback", strings.NewReader(tt.body))
+			if tt.signature != "" {
+				r.Header.Set("Stripe-Signature", tt.signature)
+			}
+
+			callback, err := stripe.ParseCallback(r)
+			if tt.wantErr {
+				if !errors.Is(err, payments.ErrInvalidCallback) {
+					t.Errorf("ParseCallback() = %v, want %v", err, payments.ErrInvalidCallback)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCallback() = %v", err)
+			}
+			if callback.OrderID != "order-1" || callback.PaymentID != "pi_1" || callback.Status != payments.StatusPaid {
+				t.Errorf("ParseCallback() = %+v", callback)
+			}
+		})
+	}
+}
+
+// liqPaySign signs data as LiqPay documents it.
+func liqPaySign(privateKey, data string) string {
+	sum := sha1.Sum([]byte(privateKey + data + privateKey))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// stripeHeader returns a Stripe-Signature header as Stripe documents it.
+func stripeHeader(secret string, timestamp int64, body string) string {
+	return "t=" + strconv.FormatInt(timestamp, 10) + ",v1=" + stripeSign(secret, timestamp, body)
+}
+
+func stripeSign(secret string, timestamp int64, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", timestamp, body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,131 @@
+// Package promo applies per-event promo codes, which organizers hand out
+// to partners and sponsors: a free ticket, a discount on it, or extra
+// entries in the draw. Codes are used in the transaction that registers
+// the participant or creates their ticket order, so a failed registration
+// doesn't use up the code.
+package promo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+// Kinds of promo codes. The code's value is the discount in percent for
+// KindDiscount and the number of entries for KindEntries.
+const (
+	KindFree     = "free"
+	KindDiscount = "discount"
+	KindEntries  = "entries"
+)
+
+// ErrInvalid is returned for codes that don't exist, have expired or are
+// used up.
+var ErrInvalid = apperr.Validation("Promo code is invalid, expired or used up")
+
+// ErrTicketOnly is returned for free and discount codes of free tickets.
+var ErrTicketOnly = apperr.Validation("This promo code only applies to paid tickets")
+
+// Normalize returns code the way it is stored, so codes can be typed in
+// any case.
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Get looks the event's code up without using it, so it can be checked
+// before the participant has finished registering. price is what their
+// ticket costs without the code.
+func Get(ctx context.Context, st store.Store, eventID int64, price int32, code string) (*sqlc.PromoCodes, error) {
+	promo, err := st.GetPromoCode(ctx, &sqlc.GetPromoCodeParams{EventID: eventID, Code: Normalize(code)})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if promo.Kind != KindEntries && price == 0 {
+		return nil, ErrTicketOnly
+	}
+	return promo, nil
+}
+
+// Use takes one use of the event's code in st, which should be the
+// transaction registering the participant, for a ticket costing price.
+func Use(ctx context.Context, st store.Store, eventID int64, price int32, code string) (*sqlc.PromoCodes, error) {
+	promo, err := st.UsePromoCode(ctx, &sqlc.UsePromoCodeParams{EventID: eventID, Code: Normalize(code)})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if promo.Kind != KindEntries && price == 0 {
+		return nil, ErrTicketOnly
+	}
+	return promo, nil
+}
+
+// Price returns what a ticket costing price costs with the code.
+func Price(promo *sqlc.PromoCodes, price int32) int32 {
+	switch promo.Kind {
+	case KindFree:
+		return 0
+	case KindDiscount:
+		return price - price*promo.Value/100
+	}
+	return price
+}
+
+// Redeem records the use of the code by a registered participant, who
+// saved discount kopecks on their ticket, and grants the code's entries.
+func Redeem(ctx context.Context, st store.Store, promo *sqlc.PromoCodes, user *sqlc.Users, discount int32) error {
+	var entries int32
+	if promo.Kind == KindEntries {
+		entries = promo.Value
+		if err := st.AddUserPromoEntries(ctx, &sqlc.AddUserPromoEntriesParams{ID: user.ID, Entries: entries}); err != nil {
+			return err
+		}
+	}
+	return st.CreatePromoRedemption(ctx, &sqlc.CreatePromoRedemptionParams{
+		PromoCodeID: promo.ID,
+		UserID:      sql.NullInt64{Int64: user.ID, Valid: true},
+		Discount:    discount,
+		Entries:     entries,
+	})
+}
+
+// Reserve records the use of the code by a ticket order that hasn't been
+// paid yet. Settle completes it once the buyer is registered.
+func Reserve(ctx context.Context, st store.Store, promo *sqlc.PromoCodes, orderID string, discount int32) error {
+	var entries int32
+	if promo.Kind == KindEntries {
+		entries = promo.Value
+	}
+	return st.CreatePromoRedemption(ctx, &sqlc.CreatePromoRedemptionParams{
+		PromoCodeID: promo.ID,
+		OrderID:     sql.NullString{String: orderID, Valid: true},
+		Discount:    discount,
+		Entries:     entries,
+	})
+}
+
+// Settle links the use reserved by a paid ticket order to its buyer and
+// grants them the code's entries. Orders without a code are left alone.
+func Settle(ctx context.Context, st store.Store, orderID string, user *sqlc.Users) error {
+	redemption, err := st.SetPromoRedemptionUser(ctx, &sqlc.SetPromoRedemptionUserParams{
+		OrderID: orderID,
+		UserID:  user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil || redemption.Entries == 0 {
+		return err
+	}
+	return st.AddUserPromoEntries(ctx, &sqlc.AddUserPromoEntriesParams{ID: user.ID, Entries: redemption.Entries})
+}
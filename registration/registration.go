@@ -0,0 +1,51 @@
+// Package registration holds the steps every way of registering for an
+// event shares, from the web form and the bot to paid orders and the
+// waitlist, so participants end up the same whichever way they came.
+package registration
+
+import (
+	"context"
+
+	"giveaway-tool/capacity"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/seating"
+	"giveaway-tool/store"
+	"giveaway-tool/tickettype"
+	"giveaway-tool/webhook"
+)
+
+// Reserve takes a place of the event and of the ticket type with the given
+// ID in tx, returning the type, which is nil for events without types. It
+// fails with capacity.ErrFull or one of the tickettype errors.
+func Reserve(ctx context.Context, tx store.Store, eventID, ticketTypeID int64) (*sqlc.TicketTypes, error) {
+	if err := capacity.Reserve(ctx, tx, eventID); err != nil {
+		return nil, err
+	}
+	return tickettype.Reserve(ctx, tx, eventID, ticketTypeID)
+}
+
+// Create creates a participant of the ticket type in tx, gives them a seat
+// if the event seats at registration, records the consent they gave by
+// registering through source and queues webhooks. The check-in code is
+// generated here.
+func Create(ctx context.Context, tx store.Store, arg *sqlc.CreateUserParams, ticketType *sqlc.TicketTypes, source string) (*sqlc.Users, error) {
+	arg.CheckInCode = store.NewCheckInCode()
+	user, err := tx.CreateUser(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	if user, err = tickettype.Assign(ctx, tx, user, ticketType); err != nil {
+		return nil, err
+	}
+	if _, err := seating.Assign(ctx, tx, user, seating.AtRegistration); err != nil {
+		return nil, err
+	}
+	if err := consent.Record(ctx, tx, user, consent.Consented, source); err != nil {
+		return nil, err
+	}
+	if err := webhook.Registered(ctx, tx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,136 @@
+package router
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// Headers proxies report the client's address in.
+const (
+	HeaderFlyClientIP   = "Fly-Client-IP"
+	HeaderForwardedFor  = "X-Forwarded-For"
+	defaultTrustedProxy = "127.0.0.0/8, ::1/128, 10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16, fc00::/7"
+)
+
+// ProxyConfig says where the client's address is found when the app runs
+// behind a proxy. With no Header, it is the address of the connection.
+type ProxyConfig struct {
+	// Header is HeaderFlyClientIP, which the proxy sets itself, or
+	// HeaderForwardedFor, where each proxy appends the address it got the
+	// request from
+	Header string
+	// Trusted are the proxies' own addresses, skipped from the right of
+	// X-Forwarded-For. Hops left of the first untrusted one may be forged
+	// by the client.
+	Trusted []netip.Prefix
+}
+
+// ProxyFromEnv reads the header from CLIENT_IP_HEADER and the trusted proxy
+// networks from the comma-separated TRUSTED_PROXIES, which default to the
+// loopback and private ones. Headers are only read when CLIENT_IP_HEADER
+// is set, since clients can send them too.
+func ProxyFromEnv() ProxyConfig {
+	var c ProxyConfig
+	for _, header := range []string{HeaderFlyClientIP, HeaderForwardedFor} {
+		if strings.EqualFold(os.Getenv("CLIENT_IP_HEADER"), header) {
+			c.Header = header
+		}
+	}
+	for _, s := range splitList(envOr("TRUSTED_PROXIES", defaultTrustedProxy)) {
+		if prefix, err := netip.ParsePrefix(s); err == nil {
+			c.Trusted = append(c.Trusted, prefix)
+		} else if addr, err := netip.ParseAddr(s); err == nil {
+			c.Trusted = append(c.Trusted, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return c
+}
+
+func (c ProxyConfig) trusts(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range c.Trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAddr returns the client's address as the proxy reported it, or ""
+// to use the connection's.
+func (c ProxyConfig) clientAddr(r *http.Request) string {
+	switch c.Header {
+	case HeaderFlyClientIP:
+		if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(c.Header))); err == nil {
+			return addr.Unmap().String()
+		}
+	case HeaderForwardedFor:
+		remote, err := netip.ParseAddr(remoteHost(r))
+		if err != nil || !c.trusts(remote) {
+			return ""
+		}
+		var hops []string
+		for _, v := range r.Header.Values(c.Header) {
+			hops = append(hops, strings.Split(v, ",")...)
+		}
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				return ""
+			}
+			if !c.trusts(addr) {
+				return addr.Unmap().String()
+			}
+		}
+	}
+	return ""
+}
+
+type clientIPKey struct{}
+
+// RealIP finds the client's address as configured by c, for ClientIP. It
+// goes first, before anything that reads the address.
+func RealIP(c ProxyConfig) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if addr := c.clientAddr(r); addr != "" {
+				r = r.WithContext(context.WithValue(r.Context(), clientIPKey{}, addr))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns the address of the client that sent r, without the
+// port: the one the proxy reported when RealIP found one, and the
+// connection's otherwise.
+func ClientIP(r *http.Request) string {
+	if addr, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return addr
+	}
+	return remoteHost(r)
+}
+
+// IsHTTPS reports whether the client reached the app over HTTPS: directly,
+// when it serves TLS itself, or through a proxy that says so in
+// X-Forwarded-Proto. The header only tells the app to be stricter, so it
+// is believed from anyone.
+func IsHTTPS(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	proto, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Proto"), ",")
+	return strings.EqualFold(strings.TrimSpace(proto), "https")
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
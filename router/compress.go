@@ -0,0 +1,150 @@
+package router
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// compressible are the content types worth compressing. Images and XLSX
+// files are compressed already, and event streams must reach the browser
+// as they are written.
+var compressible = []string{"text/html", "text/css", "text/javascript", "text/csv", "application/json"}
+
+var gzipWriters = sync.Pool{
+	New: func() any { return gzip.NewWriter(io.Discard) },
+}
+
+// Compress compresses HTML, CSS, JS, CSV and JSON responses with gzip or deflate,
+// whichever the client accepts, preferring gzip. Other responses are sent
+// as they are.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := acceptedEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// acceptedEncoding returns "gzip" or "deflate" if the Accept-Encoding
+// header allows it, or "" if neither is.
+func acceptedEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		accepted[coding] = true
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				accepted[coding] = false
+			}
+		}
+	}
+	for _, coding := range []string{"gzip", "deflate"} {
+		if enabled, ok := accepted[coding]; ok && enabled || !ok && accepted["*"] {
+			return coding
+		}
+	}
+	return ""
+}
+
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressWriter compresses the response once its headers show it is
+// worth it.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	decided  bool
+	// writer is nil unless the response is compressed
+	writer compressor
+}
+
+func (c *compressWriter) decide(status int) {
+	c.decided = true
+	header := c.Header()
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusPartialContent || status == http.StatusNotModified ||
+		header.Get("Content-Encoding") != "" || !slices.Contains(compressible, mediaType) {
+		return
+	}
+
+	header.Set("Content-Encoding", c.encoding)
+	header.Del("Content-Length")
+	header.Add("Vary", "Accept-Encoding")
+	if c.encoding == "gzip" {
+		gz := gzipWriters.Get().(*gzip.Writer)
+		gz.Reset(c.ResponseWriter)
+		c.writer = gz
+	} else {
+		// Only fails for invalid levels
+		c.writer, _ = flate.NewWriter(c.ResponseWriter, flate.DefaultCompression)
+	}
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if !c.decided {
+		c.decide(status)
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if !c.decided {
+		// Sniff the type as net/http would, as it can't see the body
+		// once it is compressed
+		if c.Header().Get("Content-Type") == "" {
+			c.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.writer == nil {
+		return c.ResponseWriter.Write(b)
+	}
+	return c.writer.Write(b)
+}
+
+// FlushError sends what was compressed so far, for
+// http.ResponseController.
+func (c *compressWriter) FlushError() error {
+	if c.writer != nil {
+		if err := c.writer.Flush(); err != nil {
+			return err
+		}
+	}
+	return http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+func (c *compressWriter) Flush() {
+	c.FlushError()
+}
+
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+func (c *compressWriter) close() {
+	if c.writer == nil {
+		return
+	}
+	c.writer.Close()
+	if gz, ok := c.writer.(*gzip.Writer); ok {
+		gz.Reset(io.Discard)
+		gzipWriters.Put(gz)
+	}
+}
@@ -0,0 +1,415 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/logging"
+	"giveaway-tool/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Hijack hands the connection over to WebSocket handlers, which answer
+// with 101 Switching Protocols on their own.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.status = http.StatusSwitchingProtocols
+	return http.NewResponseController(r.ResponseWriter).Hijack()
+}
+
+// Logging attaches a request-scoped logger carrying the request ID and the
+// matched route to the request context, and logs every request with its
+// status and duration.
+func Logging(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			requestID := r.Header.Get("X-Request-ID")
+			if requestID == "" {
+				requestID = newRequestID()
+			}
+			w.Header().Set("X-Request-ID", requestID)
+
+			reqLogger := logger.With(
+				slog.String("request_id", requestID),
+				slog.String("route", r.Pattern))
+			r = r.WithContext(logging.WithLogger(r.Context(), reqLogger))
+
+			next.ServeHTTP(rec, r)
+
+			reqLogger.LogAttrs(r.Context(), slog.LevelInfo, "Handled request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Duration("duration", time.Since(start)))
+		})
+	}
+}
+
+// Tracing records each request in a span named after its route, continuing
+// the trace of callers that send trace headers. It goes after Logging, so
+// the request's log lines carry the trace ID.
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, r.Pattern,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.HTTPRoute(r.Pattern),
+				semconv.URLPath(r.URL.Path)))
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Recovery turns panics in handlers into 500 responses, reported with
+// render.
+func Recovery(render RenderError) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if err := recover(); err != nil {
+					if err == http.ErrAbortHandler {
+						panic(err)
+					}
+					logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Recovered from panic",
+						slog.String("stack", string(debug.Stack())))
+					render(w, r, "Handler panicked", fmt.Errorf("panic: %v", err))
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CSRF rejects state-changing requests that were issued by another site,
+// based on the Sec-Fetch-Site and Origin headers sent by browsers, using
+// render to report them.
+func CSRF(render RenderError) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+				if site != "same-origin" && site != "none" {
+					render(w, r, "Cross-site request rejected", errCrossSite)
+					return
+				}
+			} else if origin := r.Header.Get("Origin"); origin != "" {
+				u, err := url.Parse(origin)
+				if err != nil || u.Host != r.Host {
+					render(w, r, "Cross-site request rejected", errCrossSite)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+var errCrossSite = apperr.Forbidden("Cross-site request rejected")
+
+// Timeout bounds the lifetime of the request context, and with it every
+// database call made while handling the request.
+func Timeout(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// MaxBodySize caps request bodies at n bytes. Reading past the limit
+// fails with *http.MaxBytesError and makes the server close the connection.
+func MaxBodySize(n int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CORSConfig lists what cross-origin browser requests may do. With no
+// allowed origins every cross-origin request is denied.
+type CORSConfig struct {
+	// AllowedOrigins are full origins like "https://example.com", or "*"
+	// for any origin
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// ExposedHeaders are response headers scripts may read
+	ExposedHeaders []string
+	MaxAge         time.Duration
+}
+
+// CORSFromEnv reads allowed origins, methods and headers from the
+// comma-separated CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS and
+// CORS_ALLOWED_HEADERS. Only the origins have no default.
+func CORSFromEnv() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: splitList(os.Getenv("CORS_ALLOWED_ORIGINS")),
+		AllowedMethods: splitList(envOr("CORS_ALLOWED_METHODS", "GET, POST")),
+		AllowedHeaders: splitList(envOr("CORS_ALLOWED_HEADERS", "Content-Type, Authorization, X-API-Key, Idempotency-Key")),
+		ExposedHeaders: []string{"Retry-After", "Idempotent-Replayed", "X-Request-ID"},
+		MaxAge:         time.Hour,
+	}
+}
+
+func (c CORSConfig) allowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c CORSConfig) allowsMethod(method string) bool {
+	for _, allowed := range c.AllowedMethods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS adds the headers letting allowed origins call the routes from a
+// browser, and answers their preflight requests. Requests from other
+// origins get no CORS headers, so browsers refuse to expose the response.
+// Preflight requests only reach the routes when registered for OPTIONS.
+// Refused preflight requests are reported with render.
+func CORS(c CORSConfig, render RenderError) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Origin")
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if !c.allowsOrigin(origin) {
+				if preflight {
+					render(w, r, "Preflight request rejected", apperr.Forbidden("Origin not allowed"))
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if !preflight {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(c.ExposedHeaders, ", "))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !c.allowsMethod(r.Header.Get("Access-Control-Request-Method")) {
+				render(w, r, "Preflight request rejected", apperr.Forbidden("Method not allowed"))
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(c.AllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(c.AllowedHeaders, ", "))
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge.Seconds())))
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// cacheRecorder buffers a response so its validators can be computed
+// before anything is sent.
+type cacheRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (c *cacheRecorder) Header() http.Header         { return c.header }
+func (c *cacheRecorder) WriteHeader(status int)      { c.status = status }
+func (c *cacheRecorder) Write(b []byte) (int, error) { return c.body.Write(b) }
+
+// maxCacheEntries bounds how many pages Cache remembers validators for.
+const maxCacheEntries = 1024
+
+type cacheEntry struct {
+	etag     string
+	modified time.Time
+}
+
+// Cache lets clients and proxies reuse successful GET responses for
+// maxAge, then revalidate them. The ETag is a hash of the body and
+// Last-Modified is when that body was first served, for each path and
+// query. Pages rendered from changed data get new validators, and
+// conditional requests for unchanged pages get 304 Not Modified.
+//
+// Handlers name what else their pages depend on in Vary, as for
+// Accept-Language. Pages that vary with the Cookie, and so with who is
+// signed in, are only cached by the browser.
+func Cache(maxAge time.Duration) Middleware {
+	var (
+		mu      sync.Mutex
+		entries = make(map[string]cacheEntry)
+	)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &cacheRecorder{header: w.Header(), status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if rec.status != http.StatusOK {
+				w.WriteHeader(rec.status)
+				w.Write(rec.body.Bytes())
+				return
+			}
+
+			sum := sha256.Sum256(rec.body.Bytes())
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+			mu.Lock()
+			key := r.URL.RequestURI()
+			entry, ok := entries[key]
+			if !ok || entry.etag != etag {
+				// Forgetting every page only makes their Last-Modified
+				// newer, so a flood of distinct URLs can't grow the map
+				if len(entries) >= maxCacheEntries {
+					clear(entries)
+				}
+				// Last-Modified has whole seconds, so a page changing twice
+				// within one must still move it forward
+				modified := time.Now().UTC().Truncate(time.Second)
+				if ok && !modified.After(entry.modified) {
+					modified = entry.modified.Add(time.Second)
+				}
+				entry = cacheEntry{etag: etag, modified: modified}
+				entries[key] = entry
+			}
+			mu.Unlock()
+
+			w.Header().Set("ETag", entry.etag)
+			w.Header().Set("Last-Modified", entry.modified.Format(http.TimeFormat))
+			visibility := "public"
+			if varies(w.Header(), "Cookie") {
+				visibility = "private"
+			}
+			w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(maxAge.Seconds())))
+
+			if notModified(r, entry) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(rec.body.Bytes())
+		})
+	}
+}
+
+// varies reports whether header lists name in Vary.
+func varies(header http.Header, name string) bool {
+	for _, v := range header.Values("Vary") {
+		for _, field := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), name) || strings.TrimSpace(field) == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// notModified evaluates the conditional headers of r against entry. As in
+// RFC 9110, If-Modified-Since is ignored when If-None-Match is present.
+func notModified(r *http.Request, entry cacheEntry) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == entry.etag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	return err == nil && !entry.modified.After(since)
+}
@@ -0,0 +1,59 @@
+// Package router groups http.ServeMux routes so each group shares a
+// middleware chain.
+package router
+
+import (
+	"net/http"
+
+	"giveaway-tool/apperr"
+)
+
+type Middleware func(http.Handler) http.Handler
+
+// RenderError writes err as the response in the format of the route, msg
+// saying what failed for the log. Middleware rejecting requests takes one,
+// so its errors look like those of the handlers behind it.
+type RenderError func(w http.ResponseWriter, r *http.Request, msg string, err error)
+
+// PlainError is a RenderError answering with the message of err as plain
+// text, for routes without error pages of their own.
+func PlainError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	http.Error(w, apperr.Message(err), apperr.HTTPStatus(err))
+}
+
+type Router struct {
+	mux        *http.ServeMux
+	middleware []Middleware
+}
+
+func New(mux *http.ServeMux, middleware ...Middleware) *Router {
+	return &Router{
+		mux:        mux,
+		middleware: middleware,
+	}
+}
+
+// Group returns a sub-router registering on the same mux. Its middleware runs
+// after the middleware of the parent router.
+func (r *Router) Group(middleware ...Middleware) *Router {
+	return &Router{
+		mux:        r.mux,
+		middleware: append(append([]Middleware{}, r.middleware...), middleware...),
+	}
+}
+
+func (r *Router) Handle(pattern string, handler http.Handler) {
+	r.mux.Handle(pattern, Chain(handler, r.middleware...))
+}
+
+func (r *Router) HandleFunc(pattern string, handler http.HandlerFunc) {
+	r.Handle(pattern, handler)
+}
+
+// Chain wraps handler with middleware, the first middleware being the outermost.
+func Chain(handler http.Handler, middleware ...Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
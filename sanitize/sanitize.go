@@ -0,0 +1,98 @@
+// Package sanitize keeps the formatting admins may use in event
+// descriptions to an allowlist of HTML tags and attributes. Markdown cleans
+// text before it is stored and HTML cleans it again when it is rendered, so
+// rows saved before a change to the list are still safe to show.
+package sanitize
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// allowed lists the tags that may appear in descriptions, with the
+// attributes each may have. It covers what Markdown renders to, so admins
+// may also write these tags as HTML.
+var allowed = map[string][]string{
+	"p": nil, "br": nil, "hr": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "del": nil, "s": nil,
+	"blockquote": nil, "code": nil, "pre": nil,
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "title"},
+}
+
+var policy = newPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowStandardURLs()
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	for tag, attrs := range allowed {
+		p.AllowElements(tag)
+		if len(attrs) > 0 {
+			p.AllowAttrs(attrs...).OnElements(tag)
+		}
+	}
+	return p
+}
+
+// HTML removes the tags and attributes that aren't allowed from s, along
+// with links to anything but http, https and mailto.
+func HTML(s string) string {
+	return policy.Sanitize(s)
+}
+
+// Markdown cleans the HTML written inside a Markdown document, leaving the
+// Markdown itself as it is.
+func Markdown(src string) string {
+	source := []byte(src)
+	doc := goldmark.DefaultParser().Parse(text.NewReader(source))
+
+	var segments []text.Segment
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.RawHTML:
+			for i := range n.Segments.Len() {
+				segments = append(segments, n.Segments.At(i))
+			}
+		case *ast.HTMLBlock:
+			// Line by line, since in a list or quote the lines are
+			// separated by the Markdown around them
+			for i := range n.Lines().Len() {
+				segments = append(segments, n.Lines().At(i))
+			}
+			if n.HasClosure() {
+				segments = append(segments, n.ClosureLine)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	if len(segments) == 0 {
+		return src
+	}
+	slices.SortFunc(segments, func(a, b text.Segment) int { return a.Start - b.Start })
+
+	var b strings.Builder
+	last := 0
+	for _, segment := range segments {
+		b.Write(source[last:segment.Start])
+		raw := string(segment.Value(source))
+		// Keep the line break, which the policy would leave out of a
+		// segment ending in a tag
+		body := strings.TrimRight(raw, "\n")
+		b.WriteString(HTML(body) + raw[len(body):])
+		last = segment.Stop
+	}
+	b.Write(source[last:])
+	return b.String()
+}
@@ -0,0 +1,130 @@
+// Package seating seats participants of seated events. Time slots work the
+// same way, with a slot per seat. Participants get the first free seat when
+// they register or check in, depending on the event, and organizers move
+// them around from the admin panel.
+package seating
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+// When participants get a seat, stored in events.seat_assignment. Events
+// with Manual are only seated by organizers.
+const (
+	AtRegistration = "registration"
+	AtCheckIn      = "check_in"
+	Manual         = "manual"
+)
+
+// ErrNotFound is returned for labels the event has no seat for.
+var ErrNotFound = apperr.NotFound("Seat not found")
+
+// ValidAssignment reports whether assignment is one of the constants above.
+func ValidAssignment(assignment string) bool {
+	switch assignment {
+	case AtRegistration, AtCheckIn, Manual:
+		return true
+	}
+	return false
+}
+
+// Row returns labels for a row of count seats, such as A1, A2 and so on.
+func Row(prefix string, count int) []string {
+	labels := make([]string, 0, count)
+	for i := 1; i <= count; i++ {
+		labels = append(labels, fmt.Sprintf("%s%d", prefix, i))
+	}
+	return labels
+}
+
+// Of returns the participant's seat, or nil if they have none.
+func Of(ctx context.Context, st store.Store, userID int64) (*sqlc.Seats, error) {
+	seat, err := st.GetSeatByUserID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return seat, err
+}
+
+// Assign returns the participant's seat, giving them the first free one
+// if they have none and their event seats participants at assignment. It
+// returns nil when they are left without a seat, which isn't an error:
+// organizers can seat them by hand once seats are freed or added.
+func Assign(ctx context.Context, tx store.Store, user *sqlc.Users, assignment string) (*sqlc.Seats, error) {
+	seat, err := Of(ctx, tx, user.ID)
+	if err != nil || seat != nil {
+		return seat, err
+	}
+	event, err := tx.GetEventByID(ctx, user.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.SeatAssignment != assignment {
+		return nil, nil
+	}
+	return AssignFree(ctx, tx, user)
+}
+
+// AssignFree gives the participant the event's first free seat, whatever
+// the event's assignment. It returns nil if they already have a seat or
+// none is free.
+func AssignFree(ctx context.Context, tx store.Store, user *sqlc.Users) (*sqlc.Seats, error) {
+	seat, err := tx.AssignFreeSeat(ctx, &sqlc.AssignFreeSeatParams{UserID: user.ID, EventID: user.EventID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return seat, err
+}
+
+// Move seats the participant in the seat with the label. Whoever sat there
+// swaps with them, taking their old seat, or is left without a seat if the
+// participant had none. It returns the ID of the seat's previous occupant,
+// or 0 if it was free.
+func Move(ctx context.Context, tx store.Store, user *sqlc.Users, label string) (int64, error) {
+	target, err := tx.GetSeatByLabelForUpdate(ctx, &sqlc.GetSeatByLabelForUpdateParams{
+		EventID: user.EventID,
+		Label:   strings.TrimSpace(label),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if target.UserID.Valid && target.UserID.Int64 == user.ID {
+		return 0, nil
+	}
+	current, err := Of(ctx, tx, user.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	// A participant holds one seat at a time, so both seats are emptied
+	// before they are taken again
+	if err := tx.ReleaseSeat(ctx, user.ID); err != nil {
+		return 0, err
+	}
+	if err := tx.SetSeatUser(ctx, &sqlc.SetSeatUserParams{ID: target.ID}); err != nil {
+		return 0, err
+	}
+	if err := tx.SetSeatUser(ctx, &sqlc.SetSeatUserParams{
+		ID:     target.ID,
+		UserID: sql.NullInt64{Int64: user.ID, Valid: true},
+	}); err != nil {
+		return 0, err
+	}
+	if target.UserID.Valid && current != nil {
+		if err := tx.SetSeatUser(ctx, &sqlc.SetSeatUserParams{ID: current.ID, UserID: target.UserID}); err != nil {
+			return 0, err
+		}
+	}
+	return target.UserID.Int64, nil
+}
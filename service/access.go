@@ -0,0 +1,160 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/magiclink"
+	"giveaway-tool/router"
+	"giveaway-tool/validate"
+)
+
+const accessSession = "access"
+
+// volunteerAccess returns the event of a volunteer request and what the
+// access link stored in the session grants on it. The link is verified on
+// every request, so access ends when it expires.
+func (s *Service) volunteerAccess(r *http.Request) (*sqlc.Events, *magiclink.Access, error) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return nil, nil, apperr.Validation("Invalid event ID")
+	}
+	if s.links == nil {
+		return nil, nil, apperr.Unauthorized("Access links are disabled")
+	}
+
+	session, _ := s.sessionStore.Get(r, accessSession)
+	token, _ := session.Values[kioskTokenKey(eventID)].(string)
+	access, err := s.links.VerifyAccess(token)
+	if err != nil || access.EventID != eventID {
+		return nil, nil, apperr.Unauthorized("Access link is invalid or expired")
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		return nil, nil, apperr.FromDB(err)
+	}
+	return event, access, nil
+}
+
+// handleAccessLink opens a volunteer's access link. Like the kiosk link,
+// the token is moved into the session so it doesn't stay in the address
+// bar.
+func (s *Service) handleAccessLink(w http.ResponseWriter, r *http.Request) {
+	if s.links == nil {
+		s.renderError(w, r, "Failed to open access link", apperr.Unauthorized("Access links are disabled"))
+		return
+	}
+
+	token := r.PathValue("token")
+	access, err := s.links.VerifyAccess(token)
+	if err != nil {
+		s.renderError(w, r, "Failed to open access link", apperr.Unauthorized("Access link is invalid or expired"))
+		return
+	}
+
+	session, _ := s.sessionStore.Get(r, accessSession)
+	session.Values[kioskTokenKey(access.EventID)] = token
+	if err := session.Save(r, w); err != nil {
+		s.renderError(w, r, "Failed to save access session", err)
+		return
+	}
+	http.Redirect(w, r, "/volunteer/events/"+strconv.FormatInt(access.EventID, 10), http.StatusSeeOther)
+}
+
+// handleVolunteerPage shows the event and its participants to a volunteer.
+func (s *Service) handleVolunteerPage(w http.ResponseWriter, r *http.Request) {
+	event, access, err := s.volunteerAccess(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to open event", err)
+		return
+	}
+
+	users, err := s.store.GetUsersByEventID(r.Context(), event.ID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get users", apperr.FromDB(err))
+		return
+	}
+	var checkedIn int
+	for _, user := range users {
+		if user.CheckedInAt.Valid {
+			checkedIn++
+		}
+	}
+
+	s.runTemplate(w, r, "volunteer_event", struct {
+		Event     *sqlc.Events
+		Users     []*sqlc.Users
+		CheckedIn int
+		Access    *magiclink.Access
+	}{
+		Event:     event,
+		Users:     users,
+		CheckedIn: checkedIn,
+		Access:    access,
+	})
+}
+
+func (s *Service) handleVolunteerCheckIn(w http.ResponseWriter, r *http.Request) {
+	event, access, err := s.volunteerAccess(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to open event", err)
+		return
+	}
+	if access.Scope != magiclink.ScopeCheckIn {
+		s.renderError(w, r, "Failed to check in participant", apperr.Forbidden("This link only allows viewing the event"))
+		return
+	}
+
+	s.checkInAtDoor(w, r, "ip:"+router.ClientIP(r), event.ID)
+}
+
+// handleAdminCheckIn checks in a participant from the admin panel, for
+// moderators working the entrance with their own account instead of an
+// access link.
+func (s *Service) handleAdminCheckIn(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	s.checkInAtDoor(w, r, "admin:"+currentAdmin(r.Context()).Username, eventID)
+}
+
+// handleCreateAccessLink issues a link giving a volunteer access to the
+// event for the chosen number of hours. Links can't be revoked, so they
+// should be kept short.
+func (s *Service) handleCreateAccessLink(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+	if s.links == nil {
+		s.renderError(w, r, "Failed to create access link", apperr.Validation("Access links need MAGIC_LINK_SECRET and PUBLIC_URL"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	hours := form.Int("hours", 1, maxAccessLinkHours)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid access link", err)
+		return
+	}
+	scope := magiclink.ParseScope(r.FormValue("scope"))
+	ttl := time.Duration(hours) * time.Hour
+
+	s.runTemplate(w, r, "admin_access_link", struct {
+		URL     string
+		Scope   magiclink.Scope
+		Expires time.Time
+	}{
+		URL:     s.links.AccessURL(eventID, scope, ttl),
+		Scope:   scope,
+		Expires: time.Now().Add(ttl),
+	})
+}
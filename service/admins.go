@@ -0,0 +1,459 @@
+package service
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/authz"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/demo"
+	"giveaway-tool/logging"
+	"giveaway-tool/magiclink"
+	"giveaway-tool/sessionstore"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// loginLinkSent is shown whether or not the username exists, so the form
+// doesn't reveal who the admins are.
+const loginLinkSent = "Якщо такий адміністратор існує, посилання для входу надіслано йому в Telegram."
+
+// dummyHash is compared against when a login names no admin with a
+// password, so the response takes as long as for a wrong password.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+
+// hashPassword checks password against the length limits and returns its
+// bcrypt hash.
+func hashPassword(password string) (string, error) {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return "", apperr.Validation(fmt.Sprintf("Password must be at least %d characters", minPasswordLength))
+	}
+	if len(password) > maxPasswordLength {
+		return "", apperr.Validation(fmt.Sprintf("Password must be at most %d bytes", maxPasswordLength))
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// checkPassword returns the admin with username if password is theirs.
+func (s *Service) checkPassword(ctx context.Context, username, password string) (*sqlc.Admins, error) {
+	admin, err := s.store.GetAdminByUsername(ctx, username)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, apperr.FromDB(err)
+	}
+	if err != nil || !admin.PasswordHash.Valid {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, apperr.Unauthorized("Invalid username or password")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash.String), []byte(password)) != nil {
+		return nil, apperr.Unauthorized("Invalid username or password")
+	}
+	return admin, nil
+}
+
+// bootstrapAdmin creates an owner from ADMIN_USERNAME and ADMIN_PASSWORD
+// when there are no admins yet, so a fresh deployment can be signed in to.
+// Once there are admins the environment is ignored, so deleting or
+// renaming the first owner doesn't bring it back on the next start.
+func (s *Service) bootstrapAdmin(ctx context.Context) error {
+	admins, err := s.store.GetAdmins(ctx)
+	if err != nil || len(admins) > 0 {
+		return err
+	}
+
+	username := os.Getenv("ADMIN_USERNAME")
+	if username == "" {
+		username = "admin"
+		s.logger.LogAttrs(ctx, slog.LevelWarn,
+			"Using default admin username. Set ADMIN_USERNAME environment variable in production.")
+	}
+
+	// Set ADMIN_FORCE_PASSWORD_CHANGE so the password from the environment,
+	// which whoever deploys knows, works only until the first sign in. The
+	// default password always has to be changed, except in the demo
+	mustChange := os.Getenv("ADMIN_FORCE_PASSWORD_CHANGE") == "true"
+	password := os.Getenv("ADMIN_PASSWORD")
+	if password == "" {
+		password = "password"
+		mustChange = mustChange || !demo.Enabled()
+		s.logger.LogAttrs(ctx, slog.LevelWarn,
+			"Using default admin password. Set ADMIN_PASSWORD environment variable in production.")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if _, err := s.store.CreateAdmin(ctx, &sqlc.CreateAdminParams{
+		Username:           username,
+		Role:               string(authz.Owner),
+		PasswordHash:       sql.NullString{String: string(hash), Valid: true},
+		MustChangePassword: mustChange,
+	}); err != nil {
+		return err
+	}
+	s.logger.LogAttrs(ctx, slog.LevelInfo, "Created owner account from environment", slog.String("username", username))
+	return nil
+}
+
+type adminKey struct{}
+
+// withAdmin returns a copy of ctx acting as admin: with their role, as the
+// actor of audited changes and in the log, and limited to the events they
+// organize unless their role manages every event.
+func (s *Service) withAdmin(ctx context.Context, admin *sqlc.Admins) (context.Context, error) {
+	role := authz.ParseRole(admin.Role)
+	if !authz.AllEvents(role) {
+		eventIDs, err := s.store.GetOrganizedEventIDs(ctx, admin.ID)
+		if err != nil {
+			return nil, err
+		}
+		ctx = authz.WithEvents(ctx, eventIDs)
+	}
+	ctx = logging.With(ctx, slog.String("admin", admin.Username), slog.String("role", string(role)))
+	ctx = authz.WithRole(ctx, role)
+	ctx = audit.WithActor(ctx, admin.Username)
+	return context.WithValue(ctx, adminKey{}, admin), nil
+}
+
+// currentAdmin returns the admin stored by withAdmin, or nil for requests
+// made with ADMIN_API_KEY.
+func currentAdmin(ctx context.Context) *sqlc.Admins {
+	admin, _ := ctx.Value(adminKey{}).(*sqlc.Admins)
+	return admin
+}
+
+// signIn starts an admin session for admin, with a new CSRF token. The
+// session gets a new token too, so one planted in the browser before
+// signing in doesn't become an admin session. Unless remember is set, the
+// session ends when the browser is closed.
+func (s *Service) signIn(w http.ResponseWriter, r *http.Request, admin *sqlc.Admins, remember bool) error {
+	session, _ := s.sessionStore.Get(r, "session")
+	if !session.IsNew {
+		if err := s.store.DeleteSession(r.Context(), sessionstore.HashToken(session.ID)); err != nil {
+			return err
+		}
+	}
+	session.ID = ""
+	session.Options.MaxAge = 0
+	if remember {
+		session.Options.MaxAge = s.sessionStore.Options.MaxAge
+	}
+	token := cryptoRand.Text()
+	session.Values["isAdmin"] = true
+	session.Values["adminID"] = admin.ID
+	session.Values["username"] = admin.Username
+	session.Values["role"] = admin.Role
+	session.Values["csrf"] = token
+	if err := session.Save(r, w); err != nil {
+		return err
+	}
+	s.setCSRFCookie(w, r, token, session.Options.MaxAge)
+	return nil
+}
+
+// handleRequestLoginLink sends a one-time login link to the Telegram chat
+// of the admin with the given username.
+func (s *Service) handleRequestLoginLink(w http.ResponseWriter, r *http.Request) {
+	if s.links == nil {
+		s.renderError(w, r, "Failed to send login link", apperr.Validation("Login links are disabled"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	username := strings.TrimPrefix(form.RequiredText("username", maxUsernameLength), "@")
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid login", err)
+		return
+	}
+
+	admin, err := s.store.GetAdminByUsername(r.Context(), username)
+	if errors.Is(err, sql.ErrNoRows) {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelWarn, "Login link requested for unknown admin", slog.String("username", username))
+		fmt.Fprintf(w, successHTML, loginLinkSent)
+		return
+	}
+	if err != nil {
+		s.renderError(w, r, "Failed to get admin", apperr.FromDB(err))
+		return
+	}
+
+	// Admins without a chat sign in with their password only
+	if !admin.ChatID.Valid {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelWarn, "Login link requested for admin without chat", slog.String("username", username))
+		fmt.Fprintf(w, successHTML, loginLinkSent)
+		return
+	}
+
+	if err := s.sendLoginLink(r.Context(), admin); err != nil {
+		s.renderError(w, r, "Failed to send login link", apperr.FromDB(err))
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, loginLinkSent)
+}
+
+func (s *Service) sendLoginLink(ctx context.Context, admin *sqlc.Admins) error {
+	nonce := cryptoRand.Text()
+	expires := time.Now().Add(magiclink.LoginTTL)
+	return s.store.InTx(ctx, func(tx store.Store) error {
+		if err := tx.CreateAdminLoginToken(ctx, &sqlc.CreateAdminLoginTokenParams{
+			Nonce:     nonce,
+			AdminID:   admin.ID,
+			ExpiresAt: expires,
+		}); err != nil {
+			return err
+		}
+		_, err := tx.EnqueueOutboxMessage(ctx, &sqlc.EnqueueOutboxMessageParams{
+			ChatID: admin.ChatID.Int64,
+			Text: fmt.Sprintf("Посилання для входу в адмін-панель (діє %d хв, спрацює один раз):\n%s\n\nЯкщо ти не входив, просто проігноруй це повідомлення.",
+				int(magiclink.LoginTTL.Minutes()), s.links.LoginURL(admin.ID, nonce, expires)),
+		})
+		return err
+	})
+}
+
+// handleLoginLink signs in the admin a login link was sent to. The link's
+// nonce is deleted on use, so opening it again fails.
+func (s *Service) handleLoginLink(w http.ResponseWriter, r *http.Request) {
+	invalid := apperr.Unauthorized("Login link is invalid, expired or already used")
+	if s.links == nil {
+		s.renderError(w, r, "Failed to sign in", invalid)
+		return
+	}
+
+	adminID, nonce, err := s.links.VerifyLogin(r.PathValue("token"))
+	if err != nil {
+		s.renderError(w, r, "Failed to sign in", invalid)
+		return
+	}
+
+	consumedID, err := s.store.ConsumeAdminLoginToken(r.Context(), &sqlc.ConsumeAdminLoginTokenParams{Nonce: nonce, Now: time.Now()})
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && consumedID != adminID) {
+		s.renderError(w, r, "Failed to sign in", invalid)
+		return
+	}
+	if err != nil {
+		s.renderError(w, r, "Failed to sign in", apperr.FromDB(err))
+		return
+	}
+
+	admin, err := s.store.GetAdminByID(r.Context(), adminID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get admin", apperr.FromDB(err))
+		return
+	}
+
+	// Login links are sent to the admin's own Telegram, so they are
+	// likely opened on a device of their own
+	if err := s.signIn(w, r, admin, true); err != nil {
+		s.renderError(w, r, "Failed to save session", err)
+		return
+	}
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Admin signed in with login link", slog.String("username", admin.Username))
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+type adminsData struct {
+	Admins []*sqlc.Admins
+	Roles  []authz.Role
+	// LoginLinks is false when login links can't be sent
+	LoginLinks bool
+}
+
+func (s *Service) adminsData(ctx context.Context) (*adminsData, error) {
+	admins, err := s.store.GetAdmins(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &adminsData{
+		Admins:     admins,
+		Roles:      authz.Roles,
+		LoginLinks: s.links != nil,
+	}, nil
+}
+
+func (s *Service) handleAdminsPage(w http.ResponseWriter, r *http.Request) {
+	data, err := s.adminsData(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get admins", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_admins", data)
+}
+
+func (s *Service) renderAdminsList(w http.ResponseWriter, r *http.Request) {
+	data, err := s.adminsData(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get admins", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_admins_list", data)
+}
+
+func (s *Service) handleCreateAdmin(w http.ResponseWriter, r *http.Request) {
+	form := validate.NewForm(r)
+	username := strings.TrimPrefix(form.RequiredText("username", maxUsernameLength), "@")
+	chatIDValue := form.Text("chat_id", 20)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid admin", err)
+		return
+	}
+
+	arg := &sqlc.CreateAdminParams{
+		Username: username,
+		Role:     string(authz.ParseRole(r.FormValue("role"))),
+	}
+	if chatIDValue != "" {
+		chatID, err := strconv.ParseInt(chatIDValue, 10, 64)
+		if err != nil {
+			s.renderError(w, r, "Invalid admin", apperr.Validation("Invalid chat ID"))
+			return
+		}
+		arg.ChatID = sql.NullInt64{Int64: chatID, Valid: true}
+	}
+	if password := r.FormValue("password"); password != "" {
+		hash, err := hashPassword(password)
+		if err != nil {
+			s.renderError(w, r, "Invalid admin", err)
+			return
+		}
+		arg.PasswordHash = sql.NullString{String: hash, Valid: true}
+	}
+	if !arg.ChatID.Valid && !arg.PasswordHash.Valid {
+		s.renderError(w, r, "Invalid admin", apperr.Validation("Set a password or a chat ID, or the admin can't sign in"))
+		return
+	}
+
+	err := s.store.InTx(r.Context(), func(tx store.Store) error {
+		admin, err := tx.CreateAdmin(r.Context(), arg)
+		if err != nil {
+			return err
+		}
+		return audit.Record(r.Context(), tx, audit.AdminCreated, 0, nil, admin)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to create admin", apperr.FromDB(err))
+		return
+	}
+
+	s.renderAdminsList(w, r)
+}
+
+// handleSetAdminPassword sets or replaces the password an admin signs in
+// with.
+func (s *Service) handleSetAdminPassword(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid admin ID", apperr.Validation("Invalid admin ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		s.renderError(w, r, "Invalid password", validate.BodyError(err))
+		return
+	}
+	hash, err := hashPassword(r.FormValue("password"))
+	if err != nil {
+		s.renderError(w, r, "Invalid password", err)
+		return
+	}
+
+	// Whoever the password was reset for may be signed in elsewhere, maybe
+	// by someone who took over the account. The session setting it is
+	// kept, in case admins set their own password here
+	session, _ := s.sessionStore.Get(r, "session")
+	var admin *sqlc.Admins
+	var ended int64
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		if admin, err = tx.GetAdminByID(r.Context(), id); err != nil {
+			return err
+		}
+		if err := tx.SetAdminPassword(r.Context(), &sqlc.SetAdminPasswordParams{
+			ID:           admin.ID,
+			PasswordHash: sql.NullString{String: hash, Valid: true},
+		}); err != nil {
+			return err
+		}
+		ended, err = tx.DeleteOtherAdminSessions(r.Context(), &sqlc.DeleteOtherAdminSessionsParams{
+			AdminID:   sql.NullInt64{Int64: admin.ID, Valid: true},
+			TokenHash: sessionstore.HashToken(session.ID),
+		})
+		if err != nil {
+			return err
+		}
+		return audit.Record(r.Context(), tx, audit.AdminPasswordSet, 0, nil, map[string]any{
+			"username":       admin.Username,
+			"sessions_ended": ended,
+		})
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to set password", apperr.FromDB(err))
+		return
+	}
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Admin password set",
+		slog.String("username", admin.Username), slog.Int64("sessions_ended", ended))
+
+	s.renderAdminsList(w, r)
+}
+
+func (s *Service) handleDeleteAdmin(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid admin ID", apperr.Validation("Invalid admin ID"))
+		return
+	}
+
+	// Someone has to be left to manage the admins
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		admins, err := tx.GetAdmins(r.Context())
+		if err != nil {
+			return err
+		}
+		owners := 0
+		var deleted *sqlc.Admins
+		for _, admin := range admins {
+			if authz.ParseRole(admin.Role) == authz.Owner {
+				owners++
+			}
+			if admin.ID == id {
+				deleted = admin
+			}
+		}
+		if deleted == nil {
+			return apperr.NotFound("Admin not found")
+		}
+		if authz.ParseRole(deleted.Role) == authz.Owner && owners == 1 {
+			return apperr.Conflict("The last owner can't be deleted")
+		}
+		if err := tx.DeleteAdmin(r.Context(), id); err != nil {
+			return err
+		}
+		return audit.Record(r.Context(), tx, audit.AdminDeleted, 0, deleted, nil)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to delete admin", apperr.FromDB(err))
+		return
+	}
+
+	s.renderAdminsList(w, r)
+}
@@ -0,0 +1,199 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"testing"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store/memory"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// password is the password of the admins created by newAdmin.
+const password = "correct horse battery"
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		password string
+		want     int
+	}{
+		{name: "right password", username: "owner", password: password, want: http.StatusSeeOther},
+		{name: "wrong password", username: "owner", password: "wrong horse battery", want: http.StatusUnauthorized},
+		{name: "unknown admin", username: "nobody", password: password, want: http.StatusUnauthorized},
+		{name: "no password set", username: "linked", password: "", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := memory.New()
+			server := newServer(t, st)
+			newAdmin(t, st, "owner", "owner")
+			// Admins who only sign in with Telegram login links have no password
+			if _, err := st.CreateAdmin(context.Background(), &sqlc.CreateAdminParams{Username: "linked", Role: "owner"}); err != nil {
+				t.Fatal(err)
+			}
+
+			client := newClient(t)
+			resp, err := client.PostForm(server.URL+"/login", url.Values{"username": {tt.username}, "password": {tt.password}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+			cookies := client.Jar.Cookies(mustParse(t, server.URL))
+			if signedIn := resp.StatusCode == http.StatusSeeOther; signedIn != (len(cookies) > 0) {
+				t.Errorf("cookies %v after status %d", cookies, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestSignInCookiesSecure(t *testing.T) {
+	tests := []struct {
+		name  string
+		proto string
+		want  bool
+	}{
+		{name: "http", want: false},
+		{name: "https proxy", proto: "https", want: true},
+		{name: "http proxy", proto: "http", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := memory.New()
+			server := newServer(t, st)
+			newAdmin(t, st, "owner", "owner")
+
+			form := url.Values{"username": {"owner"}, "password": {password}}
+			r, err := http.NewRequest(http.MethodPost, server.URL+"/login", strings.NewReader(form.Encode()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.proto != "" {
+				r.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			resp, err := newClient(t).Do(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			cookies := resp.Cookies()
+			if len(cookies) != 2 {
+				t.Fatalf("cookies %v, want the session and CSRF token", cookies)
+			}
+			for _, cookie := range cookies {
+				if cookie.Secure != tt.want {
+					t.Errorf("%s: Secure = %t, want %t", cookie.Name, cookie.Secure, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	t.Setenv("ADMIN_USERNAME", "first")
+	t.Setenv("ADMIN_PASSWORD", password)
+	st := memory.New()
+	server := newServer(t, st)
+
+	admin, err := st.GetAdminByUsername(context.Background(), "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admin.Role != "owner" {
+		t.Errorf("role %q, want owner", admin.Role)
+	}
+	if !admin.PasswordHash.Valid || strings.Contains(admin.PasswordHash.String, password) {
+		t.Fatalf("password stored as %v", admin.PasswordHash)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash.String), []byte(password)); err != nil {
+		t.Errorf("stored hash doesn't match the password: %v", err)
+	}
+	signIn(t, server.URL, "first")
+
+	// Once there are admins the environment is ignored
+	t.Setenv("ADMIN_USERNAME", "second")
+	newServer(t, st)
+	if _, err := st.GetAdminByUsername(context.Background(), "second"); err == nil {
+		t.Error("second owner created from the environment")
+	}
+}
+
+// newAdmin creates an admin with role who signs in with password.
+func newAdmin(t *testing.T, st *memory.Store, username, role string) *sqlc.Admins {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := st.CreateAdmin(context.Background(), &sqlc.CreateAdminParams{
+		Username:     username,
+		Role:         role,
+		PasswordHash: sql.NullString{String: string(hash), Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return admin
+}
+
+// signIn signs in as username and returns a client carrying the session,
+// along with the session's CSRF token.
+func signIn(t *testing.T, serverURL, username string) (*http.Client, string) {
+	t.Helper()
+	client := newClient(t)
+	resp, err := client.PostForm(serverURL+"/login", url.Values{"username": {username}, "password": {password}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("sign in as %s: status %d", username, resp.StatusCode)
+	}
+	for _, cookie := range client.Jar.Cookies(mustParse(t, serverURL)) {
+		if cookie.Name == "csrf_token" {
+			return client, cookie.Value
+		}
+	}
+	t.Fatal("no CSRF token after signing in")
+	return nil, ""
+}
+
+// newClient returns a client that keeps cookies and doesn't follow
+// redirects.
+func newClient(t *testing.T) *http.Client {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &http.Client{
+		Jar: jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func mustParse(t *testing.T, rawURL string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/markdown"
+	"giveaway-tool/validate"
+)
+
+// announcementChannelFromEnv reads the Telegram channel winner
+// announcements are posted to. Posting is disabled when it is 0.
+func announcementChannelFromEnv(ctx context.Context, logger *slog.Logger) int64 {
+	v := os.Getenv("TELEGRAM_CHANNEL_ID")
+	if v == "" {
+		return 0
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "Invalid TELEGRAM_CHANNEL_ID, posting announcements is disabled", slog.String("value", v))
+		return 0
+	}
+	return id
+}
+
+var medals = []string{"🥇", "🥈", "🥉"}
+
+// mention links a winner in Telegram's Markdown: by username when they
+// have one, otherwise by their Telegram ID, which notifies them too.
+// Participants registered on the website with neither are named only.
+func mention(user *sqlc.Users) string {
+	if username := strings.TrimPrefix(user.Username, "@"); username != "" {
+		return markdown.EscapeTelegram("@" + username)
+	}
+	if user.TgID.Valid {
+		// Escapes don't work inside link text, so the closing bracket is
+		// dropped instead
+		name := strings.ReplaceAll(user.Name, "]", "")
+		return fmt.Sprintf("[%s](tg://user?id=%d)", name, user.TgID.Int64)
+	}
+	return markdown.EscapeTelegram(user.Name)
+}
+
+// announcement composes the post announcing the winners of a draw. prizes
+// maps winners' ticket numbers to their prizes; winners without one are
+// listed without a prize.
+func announcement(event *sqlc.Events, winners []*sqlc.GetDrawWinnersRow, prizes map[int32]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🎉 *Переможці розіграшу ФІТКІ*\n%s\n", markdown.EscapeTelegram(event.Name))
+	for _, winner := range winners {
+		b.WriteString("\n")
+		if int(winner.Position) <= len(medals) {
+			b.WriteString(medals[winner.Position-1])
+		} else {
+			fmt.Fprintf(&b, "%d.", winner.Position)
+		}
+		fmt.Fprintf(&b, " %s (квиток №%d)", mention(&winner.Users), winner.Users.TicketNumber)
+		if prize := prizes[winner.Users.TicketNumber]; prize != "" {
+			fmt.Fprintf(&b, " — %s", markdown.EscapeTelegram(prize))
+		}
+	}
+	b.WriteString("\n\nВітаємо переможців і дякуємо всім, хто взяв участь! 💙\n#ФІТКІ")
+	return b.String()
+}
+
+// drawWinners returns the winners of a draw of the event in the request.
+func (s *Service) drawWinners(r *http.Request) (*sqlc.Events, []*sqlc.GetDrawWinnersRow, error) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return nil, nil, apperr.Validation("Invalid event ID")
+	}
+	drawID, err := strconv.ParseInt(r.PathValue("drawID"), 10, 64)
+	if err != nil {
+		return nil, nil, apperr.Validation("Invalid draw ID")
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		return nil, nil, apperr.FromDB(err)
+	}
+	draws, err := s.store.GetDrawsByEventID(r.Context(), eventID)
+	if err != nil {
+		return nil, nil, apperr.FromDB(err)
+	}
+	for _, draw := range draws {
+		if draw.ID != drawID {
+			continue
+		}
+		winners, err := s.store.GetDrawWinners(r.Context(), drawID)
+		if err != nil {
+			return nil, nil, apperr.FromDB(err)
+		}
+		return event, winners, nil
+	}
+	return nil, nil, apperr.NotFound("Draw not found")
+}
+
+// handleComposeAnnouncement drafts the winners post of a draw with the
+// prizes entered for each winner. The draft can be edited before it is
+// posted or copied.
+func (s *Service) handleComposeAnnouncement(w http.ResponseWriter, r *http.Request) {
+	event, winners, err := s.drawWinners(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get winners", err)
+		return
+	}
+
+	form := validate.NewForm(r)
+	prizes := make(map[int32]string, len(winners))
+	for _, winner := range winners {
+		ticket := winner.Users.TicketNumber
+		prizes[ticket] = form.Text(fmt.Sprintf("prize_%d", ticket), maxPrizeLength)
+	}
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid prizes", err)
+		return
+	}
+
+	s.runTemplate(w, r, "winners_announcement", struct {
+		EventID int64
+		DrawID  string
+		Text    string
+		// Channel is false when no channel is configured to post to
+		Channel bool
+	}{
+		EventID: event.ID,
+		DrawID:  r.PathValue("drawID"),
+		Text:    announcement(event, winners, prizes),
+		Channel: s.announcementChannel != 0,
+	})
+}
+
+// handlePostAnnouncement queues the winners post to the channel. It is
+// sent with Markdown, so mentions become links.
+func (s *Service) handlePostAnnouncement(w http.ResponseWriter, r *http.Request) {
+	if s.announcementChannel == 0 {
+		s.renderError(w, r, "Failed to post announcement", apperr.Validation("Posting needs TELEGRAM_CHANNEL_ID"))
+		return
+	}
+	if _, _, err := s.drawWinners(r); err != nil {
+		s.renderError(w, r, "Failed to get winners", err)
+		return
+	}
+
+	form := validate.NewForm(r)
+	text := form.RequiredText("text", maxMessageLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid announcement", err)
+		return
+	}
+
+	if _, err := s.store.EnqueueOutboxMessage(r.Context(), &sqlc.EnqueueOutboxMessageParams{
+		ChatID:   s.announcementChannel,
+		Text:     text,
+		Markdown: true,
+	}); err != nil {
+		s.renderError(w, r, "Failed to queue announcement", apperr.FromDB(err))
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, "Announcement queued for the channel")
+}
@@ -0,0 +1,740 @@
+package service
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/authz"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/logging"
+	"giveaway-tool/router"
+	"giveaway-tool/sanitize"
+	"giveaway-tool/slug"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+// apiPageSize is how many participants the management API lists at once.
+const apiPageSize = 100
+
+// apiEnvelope wraps the responses of the management API: the resource in
+// Data on success, the message in Error otherwise.
+type apiEnvelope struct {
+	Data  any    `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+	// NextCursor is passed as ?cursor= to get the next page of a list; it
+	// is left out on the last page
+	NextCursor int64 `json:"next_cursor,omitempty"`
+}
+
+// renderJSON writes data in the envelope with the given status.
+func renderJSON(w http.ResponseWriter, status int, data any, nextCursor int64) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiEnvelope{Data: data, NextCursor: nextCursor})
+}
+
+// requireAPIAuth authenticates tools managing events. They send either the
+// key from ADMIN_API_KEY, which acts as the owner, or a token minted on
+// /admin/tokens, which acts as the admin who minted it limited to its
+// scopes, as a bearer token or in the X-API-Key header. Scripts in the
+// admin panel's pages use its session instead, with the CSRF token for
+// changes.
+func (s *Service) requireAPIAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := requestAPIKey(r)
+		switch {
+		case validAPIKey(key, s.adminAPIKey):
+			ctx := logging.With(r.Context(), slog.String("admin", "api"), slog.String("role", string(authz.Owner)))
+			ctx = authz.WithRole(ctx, authz.Owner)
+			ctx = audit.WithActor(ctx, audit.ActorAPI)
+			next.ServeHTTP(w, r.WithContext(ctx))
+
+		case key != "":
+			ctx, err := s.tokenContext(r.Context(), key)
+			if err != nil {
+				s.renderJSONError(w, r, "Invalid API token", err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+
+		default:
+			admin, err := s.sessionAdmin(r)
+			if err != nil {
+				s.renderJSONError(w, r, "Failed to get admin", apperr.FromDB(err))
+				return
+			}
+			if admin == nil {
+				s.renderJSONError(w, r, "Missing credentials", apperr.Unauthorized("Send an API token or sign in"))
+				return
+			}
+			if admin.MustChangePassword {
+				s.renderJSONError(w, r, "Password change required", apperr.Forbidden("Change your password first"))
+				return
+			}
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				session, _ := s.sessionStore.Get(r, "session")
+				if !validCSRFToken(r, session) {
+					s.renderJSONError(w, r, "Invalid CSRF token", apperr.Forbidden("Invalid CSRF token"))
+					return
+				}
+			}
+			ctx, err := s.withAdmin(r.Context(), admin)
+			if err != nil {
+				s.renderJSONError(w, r, "Failed to get organized events", apperr.FromDB(err))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+	})
+}
+
+// requireScope rejects API calls that may not use scope, because of the
+// caller's role or the scopes of their token, and those about an event the
+// caller doesn't organize.
+func (s *Service) requireScope(scope authz.Scope) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !authz.Permits(r.Context(), scope) {
+				s.renderJSONError(w, r, "Action not allowed", apperr.Forbidden(fmt.Sprintf("Requires the %s scope", scope)))
+				return
+			}
+			if eventID := pathEventID(r); eventID != 0 && !authz.EventAllowed(r.Context(), eventID) {
+				s.renderJSONError(w, r, "Event not allowed", errNotOrganizer)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type eventResponse struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Date        time.Time `json:"date"`
+	// Version is sent back with updates to detect concurrent edits
+	Version     int32      `json:"version"`
+	TicketPrice int32      `json:"ticket_price"`
+	Status      string     `json:"status"`
+	Slug        string     `json:"slug"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	// The registration window; a missing end doesn't limit it
+	RegistrationOpensAt  *time.Time `json:"registration_opens_at,omitempty"`
+	RegistrationClosesAt *time.Time `json:"registration_closes_at,omitempty"`
+}
+
+func newEventResponse(event *sqlc.Events) eventResponse {
+	resp := eventResponse{
+		ID:          event.ID,
+		Name:        event.Name,
+		Description: event.Description.String,
+		Date:        event.Date,
+		Version:     event.Version,
+		TicketPrice: event.TicketPrice,
+		Status:      event.Status,
+		Slug:        event.Slug.String,
+	}
+	if event.ArchivedAt.Valid {
+		resp.ArchivedAt = &event.ArchivedAt.Time
+	}
+	if event.RegistrationOpensAt.Valid {
+		resp.RegistrationOpensAt = &event.RegistrationOpensAt.Time
+	}
+	if event.RegistrationClosesAt.Valid {
+		resp.RegistrationClosesAt = &event.RegistrationClosesAt.Time
+	}
+	return resp
+}
+
+type eventRequest struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Date        time.Time `json:"date"`
+	// Slug names the public page. It's made from the name when missing
+	// from a new event, and kept when missing from an update
+	Slug string `json:"slug"`
+	// Version is the version the update is based on
+	Version int32 `json:"version"`
+}
+
+// validate trims the request's fields and checks them.
+func (req *eventRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Name == "" {
+		return apperr.Validation("Name is required")
+	}
+	if err := validate.Length("name", req.Name, maxNameLength); err != nil {
+		return err
+	}
+	if err := validate.Length("description", req.Description, maxDescriptionLength); err != nil {
+		return err
+	}
+	if err := validate.Length("slug", req.Slug, maxNameLength); err != nil {
+		return err
+	}
+	if req.Date.IsZero() {
+		return apperr.Validation("Date is required")
+	}
+	req.Description = sanitize.Markdown(req.Description)
+	return nil
+}
+
+// eventStatusRequest moves an event to one of the lifecycle statuses.
+type eventStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// adminParticipantResponse is a participant with the fields only admins
+// see and change.
+type adminParticipantResponse struct {
+	participantResponse
+	N       int32    `json:"n"`
+	Entries int32    `json:"entries"`
+	Tags    []string `json:"tags"`
+	Notes   string   `json:"notes"`
+}
+
+func newAdminParticipantResponse(user *sqlc.Users, seat string) adminParticipantResponse {
+	resp := adminParticipantResponse{
+		participantResponse: newParticipantResponse(user),
+		N:                   user.N,
+		Entries:             userEntries(user),
+		Tags:                user.Tags,
+		Notes:               user.Notes,
+	}
+	resp.Seat = seat
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	return resp
+}
+
+// participantUpdateRequest changes a participant. Fields left out are kept.
+type participantUpdateRequest struct {
+	N     *int      `json:"n"`
+	Tags  *[]string `json:"tags"`
+	Notes *string   `json:"notes"`
+}
+
+type drawRequest struct {
+	Count       int      `json:"count"`
+	IncludeTags []string `json:"include_tags"`
+	ExcludeTags []string `json:"exclude_tags"`
+	// OnlyReachable leaves out participants the bot can't reach
+	OnlyReachable bool `json:"only_reachable"`
+}
+
+type winnerResponse struct {
+	Position    int32                    `json:"position"`
+	Participant adminParticipantResponse `json:"participant"`
+}
+
+type drawResponse struct {
+	ID        int64            `json:"id"`
+	CreatedAt *time.Time       `json:"created_at,omitempty"`
+	Seed      string           `json:"seed,omitempty"`
+	Winners   []winnerResponse `json:"winners"`
+	// PendingReview is only reported for new draws
+	PendingReview int `json:"pending_review,omitempty"`
+}
+
+// apiEventID parses the event ID of an event route.
+func apiEventID(r *http.Request) (int64, error) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return 0, apperr.Validation("Invalid event ID")
+	}
+	return eventID, nil
+}
+
+// handleAPIListEvents lists all events, newest first.
+func (s *Service) handleAPIListEvents(w http.ResponseWriter, r *http.Request) {
+	events, err := s.store.GetEvents(r.Context())
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get events", apperr.FromDB(err))
+		return
+	}
+	events = organizedEvents(r.Context(), events)
+
+	resp := make([]eventResponse, len(events))
+	for i, event := range events {
+		resp[i] = newEventResponse(event)
+	}
+	renderJSON(w, http.StatusOK, resp, 0)
+}
+
+func (s *Service) handleAPICreateEvent(w http.ResponseWriter, r *http.Request) {
+	var req eventRequest
+	if err := validate.JSON(r, &req); err != nil {
+		s.renderJSONError(w, r, "Failed to decode event", err)
+		return
+	}
+	if err := req.validate(); err != nil {
+		s.renderJSONError(w, r, "Invalid event", err)
+		return
+	}
+
+	var event *sqlc.Events
+	err := s.store.InTx(r.Context(), func(tx store.Store) error {
+		var err error
+		event, err = tx.CreateEvent(r.Context(), &sqlc.CreateEventParams{
+			Name:        req.Name,
+			Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+			Date:        req.Date,
+		})
+		if err != nil {
+			return err
+		}
+		if event, err = slug.Assign(r.Context(), tx, event, req.Slug); err != nil {
+			return err
+		}
+		if err := audit.Record(r.Context(), tx, audit.EventCreated, event.ID, nil, event); err != nil {
+			return err
+		}
+		_, err = organizeNewEvent(r.Context(), tx, event.ID)
+		return err
+	})
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to create event", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Created event", slog.Int64("event_id", event.ID))
+
+	renderJSON(w, http.StatusCreated, newEventResponse(event), 0)
+}
+
+func (s *Service) handleAPIGetEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := apiEventID(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+
+	renderJSON(w, http.StatusOK, newEventResponse(event), 0)
+}
+
+// handleAPIUpdateEvent replaces the event's name, description, date and
+// slug. Updates based on an outdated version are rejected with 409, like
+// saving a stale form.
+func (s *Service) handleAPIUpdateEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := apiEventID(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	var req eventRequest
+	if err := validate.JSON(r, &req); err != nil {
+		s.renderJSONError(w, r, "Failed to decode event", err)
+		return
+	}
+	if err := req.validate(); err != nil {
+		s.renderJSONError(w, r, "Invalid event", err)
+		return
+	}
+
+	var event *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		before, err := tx.GetEventByID(r.Context(), eventID)
+		if err != nil {
+			return err
+		}
+		event, err = tx.UpdateEvent(r.Context(), &sqlc.UpdateEventParams{
+			ID:          eventID,
+			Name:        req.Name,
+			Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+			Date:        req.Date,
+			Version:     req.Version,
+		})
+		if err != nil {
+			return err
+		}
+		if event, err = slug.Assign(r.Context(), tx, event, cmp.Or(req.Slug, before.Slug.String)); err != nil {
+			return err
+		}
+		return audit.Record(r.Context(), tx, audit.EventUpdated, eventID, before, event)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, getErr := s.store.GetEventByID(r.Context(), eventID); getErr == nil {
+			err = apperr.Conflict("Event was changed since it was loaded")
+		}
+	}
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to update event", apperr.FromDB(err))
+		return
+	}
+
+	renderJSON(w, http.StatusOK, newEventResponse(event), 0)
+}
+
+// handleAPISetEventStatus moves the event through its lifecycle like the
+// status buttons of the admin panel.
+func (s *Service) handleAPISetEventStatus(w http.ResponseWriter, r *http.Request) {
+	eventID, err := apiEventID(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	var req eventStatusRequest
+	if err := validate.JSON(r, &req); err != nil {
+		s.renderJSONError(w, r, "Failed to decode status", err)
+		return
+	}
+	if !lifecycle.Valid(req.Status) {
+		s.renderJSONError(w, r, "Invalid event status", apperr.Validation("Invalid event status"))
+		return
+	}
+
+	var event *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		event, err = moveEvent(r.Context(), tx, eventID, req.Status)
+		return err
+	})
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to update event status", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Changed event status",
+		slog.Int64("event_id", eventID), slog.String("status", req.Status))
+
+	renderJSON(w, http.StatusOK, newEventResponse(event), 0)
+}
+
+// handleAPIDeleteEvent moves the event to the trash with its participants
+// and draws. An admin can restore it until the trash is emptied.
+func (s *Service) handleAPIDeleteEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := apiEventID(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		if err := tx.DeleteEvent(r.Context(), eventID); err != nil {
+			return err
+		}
+		return audit.Record(r.Context(), tx, audit.EventDeleted, eventID, event, nil)
+	})
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to delete event", apperr.FromDB(err))
+		return
+	}
+	s.removeFromCalendar(r.Context(), event)
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Deleted event", slog.Int64("event_id", eventID))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAPIListParticipants lists the event's participants in order of
+// registration, a page at a time.
+func (s *Service) handleAPIListParticipants(w http.ResponseWriter, r *http.Request) {
+	eventID, err := apiEventID(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	var cursor int64
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		if cursor, err = strconv.ParseInt(v, 10, 64); err != nil {
+			s.renderJSONError(w, r, "Invalid cursor", apperr.Validation("Invalid cursor"))
+			return
+		}
+	}
+
+	if _, err := s.store.GetEventByID(r.Context(), eventID); err != nil {
+		s.renderJSONError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	users, err := s.store.GetUsersByEventIDAfter(r.Context(), &sqlc.GetUsersByEventIDAfterParams{
+		EventID:  eventID,
+		AfterID:  cursor,
+		PageSize: apiPageSize,
+	})
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get participants", apperr.FromDB(err))
+		return
+	}
+	seats, err := s.store.GetSeatsByEventID(r.Context(), eventID)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get seats", apperr.FromDB(err))
+		return
+	}
+
+	labels := seatLabels(seats)
+	resp := make([]adminParticipantResponse, len(users))
+	for i, user := range users {
+		resp[i] = newAdminParticipantResponse(user, labels[user.ID])
+	}
+
+	var next int64
+	if len(users) == apiPageSize {
+		next = users[len(users)-1].ID
+	}
+	renderJSON(w, http.StatusOK, resp, next)
+}
+
+// handleAPICreateParticipant adds a participant on the admin's behalf. It
+// takes a place of their ticket type like any registration, but doesn't
+// require registration to be open or the ticket to be paid.
+func (s *Service) handleAPICreateParticipant(w http.ResponseWriter, r *http.Request) {
+	eventID, err := apiEventID(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	var req registrationRequest
+	if err := validate.JSON(r, &req); err != nil {
+		s.renderJSONError(w, r, "Failed to decode participant", err)
+		return
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	user, err := s.register(r.Context(), event, req, consent.SourceAdmin)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to create participant", err)
+		return
+	}
+
+	renderJSON(w, http.StatusCreated, newAdminParticipantResponse(user, s.seatLabel(r.Context(), user.ID)), 0)
+}
+
+func (s *Service) handleAPIGetParticipant(w http.ResponseWriter, r *http.Request) {
+	user, err := s.eventUser(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get participant", err)
+		return
+	}
+
+	renderJSON(w, http.StatusOK, newAdminParticipantResponse(user, s.seatLabel(r.Context(), user.ID)), 0)
+}
+
+// handleAPIUpdateParticipant changes a participant's entries, tags and
+// notes.
+func (s *Service) handleAPIUpdateParticipant(w http.ResponseWriter, r *http.Request) {
+	user, err := s.eventUser(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get participant", err)
+		return
+	}
+
+	var req participantUpdateRequest
+	if err := validate.JSON(r, &req); err != nil {
+		s.renderJSONError(w, r, "Failed to decode participant", err)
+		return
+	}
+	if req.N != nil && (*req.N < 1 || *req.N > maxUserEntries) {
+		s.renderJSONError(w, r, "Invalid participant", apperr.Validation(fmt.Sprintf("N must be a number from 1 to %d", maxUserEntries)))
+		return
+	}
+	var tags []string
+	if req.Tags != nil {
+		if tags, err = validate.List("tags", *req.Tags, maxTagsPerUser, maxTagLength); err != nil {
+			s.renderJSONError(w, r, "Invalid participant", err)
+			return
+		}
+	}
+	if req.Notes != nil {
+		*req.Notes = strings.TrimSpace(*req.Notes)
+		if err := validate.Length("notes", *req.Notes, maxNoteLength); err != nil {
+			s.renderJSONError(w, r, "Invalid participant", err)
+			return
+		}
+	}
+
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		if req.N != nil {
+			if err := setUserEntries(r.Context(), tx, user.EventID, user.ID, int32(*req.N)); err != nil {
+				return err
+			}
+		}
+		if req.Tags != nil {
+			if _, err := tx.SetUserTags(r.Context(), &sqlc.SetUserTagsParams{
+				ID:      user.ID,
+				EventID: user.EventID,
+				Tags:    normalizeTags(tags),
+			}); err != nil {
+				return err
+			}
+		}
+		if req.Notes != nil {
+			if _, err := tx.SetUserNotes(r.Context(), &sqlc.SetUserNotesParams{
+				ID:      user.ID,
+				EventID: user.EventID,
+				Notes:   *req.Notes,
+			}); err != nil {
+				return err
+			}
+		}
+		var err error
+		user, err = tx.GetUserByID(r.Context(), user.ID)
+		return err
+	})
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to update participant", apperr.FromDB(err))
+		return
+	}
+
+	renderJSON(w, http.StatusOK, newAdminParticipantResponse(user, s.seatLabel(r.Context(), user.ID)), 0)
+}
+
+// handleAPIDeleteParticipant removes a participant, promoting the next
+// person on the waitlist if the event does that automatically.
+func (s *Service) handleAPIDeleteParticipant(w http.ResponseWriter, r *http.Request) {
+	user, err := s.eventUser(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get participant", err)
+		return
+	}
+
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		return removeParticipant(r.Context(), tx, user.EventID, user.ID, consent.Deleted, consent.SourceAdmin)
+	})
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to delete participant", apperr.FromDB(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAPIListWinners lists the event's draws with their winners, newest
+// first.
+func (s *Service) handleAPIListWinners(w http.ResponseWriter, r *http.Request) {
+	eventID, err := apiEventID(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	if _, err := s.store.GetEventByID(r.Context(), eventID); err != nil {
+		s.renderJSONError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	draws, err := s.store.GetDrawsByEventID(r.Context(), eventID)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get draws", apperr.FromDB(err))
+		return
+	}
+	seats, err := s.store.GetSeatsByEventID(r.Context(), eventID)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get seats", apperr.FromDB(err))
+		return
+	}
+
+	labels := seatLabels(seats)
+	resp := make([]drawResponse, 0, len(draws))
+	for _, draw := range draws {
+		winners, err := s.store.GetDrawWinners(r.Context(), draw.ID)
+		if err != nil {
+			s.renderJSONError(w, r, "Failed to get winners", apperr.FromDB(err))
+			return
+		}
+
+		d := drawResponse{ID: draw.ID, Seed: draw.Seed.String, Winners: make([]winnerResponse, len(winners))}
+		if draw.CreatedAt.Valid {
+			d.CreatedAt = &draw.CreatedAt.Time
+		}
+		for i, winner := range winners {
+			d.Winners[i] = winnerResponse{
+				Position:    winner.Position,
+				Participant: newAdminParticipantResponse(&winner.Users, labels[winner.Users.ID]),
+			}
+		}
+		resp = append(resp, d)
+	}
+	renderJSON(w, http.StatusOK, resp, 0)
+}
+
+// handleAPIRunDraw runs a draw like the admin page does, notifying the
+// winners.
+func (s *Service) handleAPIRunDraw(w http.ResponseWriter, r *http.Request) {
+	eventID, err := apiEventID(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	var req drawRequest
+	if err := validate.JSON(r, &req); err != nil {
+		s.renderJSONError(w, r, "Failed to decode draw", err)
+		return
+	}
+	if req.Count < 1 || req.Count > maxWinners {
+		s.renderJSONError(w, r, "Invalid winners count", apperr.Validation(fmt.Sprintf("Count must be a number from 1 to %d", maxWinners)))
+		return
+	}
+	include, err := validate.List("include_tags", req.IncludeTags, maxTagsPerUser, maxTagLength)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid draw", err)
+		return
+	}
+	exclude, err := validate.List("exclude_tags", req.ExcludeTags, maxTagsPerUser, maxTagLength)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid draw", err)
+		return
+	}
+
+	// Unknown events would otherwise only fail once the draw is saved
+	if _, err := s.store.GetEventByID(r.Context(), eventID); err != nil {
+		s.renderJSONError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	result, err := s.runDraw(r.Context(), eventID, drawOptions{
+		Count:         req.Count,
+		Filter:        tagFilter{Include: normalizeTags(include), Exclude: normalizeTags(exclude)},
+		OnlyReachable: req.OnlyReachable,
+	})
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to run draw", apperr.FromDB(err))
+		return
+	}
+
+	resp := drawResponse{
+		ID:            result.DrawID,
+		Seed:          result.Seed,
+		Winners:       make([]winnerResponse, len(result.Winners)),
+		PendingReview: result.PendingReview,
+	}
+	for i, winner := range result.Winners {
+		resp.Winners[i] = winnerResponse{
+			Position:    int32(i + 1),
+			Participant: newAdminParticipantResponse(winner, s.seatLabel(r.Context(), winner.ID)),
+		}
+	}
+	renderJSON(w, http.StatusCreated, resp, 0)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/logging"
+	"giveaway-tool/notify"
+	"giveaway-tool/store"
+)
+
+// defaultArchiveAfterDays is how long after its date an event is archived,
+// overridden by ARCHIVE_AFTER_DAYS.
+const defaultArchiveAfterDays = 30
+
+// archiveAfterFromEnv reads the archiving delay from ARCHIVE_AFTER_DAYS.
+// Zero disables archiving.
+func archiveAfterFromEnv(ctx context.Context, logger *slog.Logger) time.Duration {
+	days := defaultArchiveAfterDays
+	if v := os.Getenv("ARCHIVE_AFTER_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			logger.LogAttrs(ctx, slog.LevelWarn, "Invalid ARCHIVE_AFTER_DAYS, using the default",
+				slog.String("value", v), slog.Int("default", defaultArchiveAfterDays))
+		} else {
+			days = n
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// archiveEvents archives events once they are archiveAfter in the past, so
+// the dashboards only list events that still need attention.
+func (s *Service) archiveEvents(ctx context.Context) {
+	if s.archiveAfter == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			archived, err := s.store.ArchiveEventsBefore(ctx, now.Add(-s.archiveAfter))
+			if err != nil {
+				s.logger.LogAttrs(ctx, slog.LevelError, "Failed to archive events", slog.Any("error", err))
+				notify.JobFailed("архівування івентів", err)
+				continue
+			}
+			if archived > 0 {
+				s.logger.LogAttrs(ctx, slog.LevelInfo, "Archived events", slog.Int64("count", archived))
+			}
+		}
+	}
+}
+
+// handleArchiveEvent moves the event to the archive ahead of automatic
+// archiving.
+func (s *Service) handleArchiveEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		before, err := tx.GetEventByID(r.Context(), eventID)
+		if err != nil {
+			return err
+		}
+		event, err := tx.ArchiveEvent(r.Context(), eventID)
+		if err != nil {
+			return err
+		}
+		return audit.Record(r.Context(), tx, audit.EventArchived, eventID, before, event)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to archive event", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Archived event", slog.Int64("event_id", eventID))
+}
+
+// handleUnarchiveEvent takes the event out of the archive. Automatic
+// archiving won't archive it again.
+func (s *Service) handleUnarchiveEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		before, err := tx.GetEventByID(r.Context(), eventID)
+		if err != nil {
+			return err
+		}
+		event, err := tx.UnarchiveEvent(r.Context(), eventID)
+		if err != nil {
+			return err
+		}
+		return audit.Record(r.Context(), tx, audit.EventUnarchived, eventID, before, event)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to unarchive event", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Unarchived event", slog.Int64("event_id", eventID))
+}
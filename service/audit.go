@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/database/sqlc"
+)
+
+// auditLabels name the audit log actions in the admin panel.
+var auditLabels = map[string]string{
+	string(audit.EventCreated):          "Створено івент",
+	string(audit.EventUpdated):          "Змінено івент",
+	string(audit.EventDeleted):          "Видалено івент",
+	string(audit.EventStatusSet):        "Змінено статус івенту",
+	string(audit.RegistrationWindowSet): "Змінено період реєстрації",
+	string(audit.EventArchived):         "Івент перенесено в архів",
+	string(audit.EventUnarchived):       "Івент повернуто з архіву",
+	string(audit.CapacitySet):           "Змінено кількість місць",
+	string(audit.CurrentEventSet):       "Вибрано поточний івент",
+	string(audit.FlagSet):               "Змінено функцію",
+	string(audit.WinnersDrawn):          "Проведено розіграш",
+	string(audit.UserDeleted):           "Видалено учасника",
+	string(audit.UserEntriesSet):        "Змінено кількість шансів учасника",
+	string(audit.PaymentRefunded):       "Повернуто оплату",
+	string(audit.AdminCreated):          "Додано адміністратора",
+	string(audit.AdminDeleted):          "Видалено адміністратора",
+	string(audit.AdminPasswordSet):      "Змінено пароль адміністратора",
+	string(audit.TokenCreated):          "Створено API-токен",
+	string(audit.TokenRevoked):          "Відкликано API-токен",
+	string(audit.SessionRevoked):        "Завершено сеанс",
+}
+
+type auditEntry struct {
+	*sqlc.AuditLog
+	Label string
+	// Changes is the diff indented for reading
+	Changes string
+}
+
+// handleAuditPage lists the audit log a page at a time, newest first.
+func (s *Service) handleAuditPage(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// One extra record tells whether there is a next page
+	records, err := s.store.GetAuditLogPage(r.Context(), &sqlc.GetAuditLogPageParams{
+		OrganizerID: organizerID(r.Context()),
+		PageOffset:  int32((page - 1) * auditPageSize),
+		PageSize:    auditPageSize + 1,
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to get audit log", apperr.FromDB(err))
+		return
+	}
+	nextPage := 0
+	if len(records) > auditPageSize {
+		records = records[:auditPageSize]
+		nextPage = page + 1
+	}
+
+	entries := make([]auditEntry, len(records))
+	for i, record := range records {
+		label, ok := auditLabels[record.Action]
+		if !ok {
+			label = record.Action
+		}
+		var changes bytes.Buffer
+		if err := json.Indent(&changes, record.Diff, "", "  "); err != nil {
+			changes.Reset()
+			changes.Write(record.Diff)
+		}
+		entries[i] = auditEntry{AuditLog: record, Label: label, Changes: changes.String()}
+	}
+
+	s.runTemplate(w, r, "admin_audit", struct {
+		Entries []auditEntry
+		// PrevPage and NextPage are 0 on the first and last page
+		PrevPage int
+		NextPage int
+	}{
+		Entries:  entries,
+		PrevPage: page - 1,
+		NextPage: nextPage,
+	})
+}
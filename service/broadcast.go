@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+// sampleRecipient is shown in the broadcast preview when no participant
+// can receive the message.
+var sampleRecipient = &sqlc.Users{Name: "Коваленко Олена", TicketNumber: 1, N: 1}
+
+// userEntries is how many entries a participant has in the draw; paid,
+// bonus and promo code entries count the same as votes.
+func userEntries(user *sqlc.Users) int32 {
+	return user.N + user.PaidEntries + user.BonusEntries + user.ShareEntries + user.PromoEntries
+}
+
+// personalize fills the placeholders of a broadcast in for one recipient.
+func personalize(text string, user *sqlc.Users) string {
+	// Participants are asked for their surname and then their first name
+	firstName := user.Name
+	if fields := strings.Fields(user.Name); len(fields) > 1 {
+		firstName = fields[1]
+	}
+	return strings.NewReplacer(
+		"{first_name}", firstName,
+		"{ticket_number}", strconv.Itoa(int(user.TicketNumber)),
+		"{entries}", strconv.Itoa(int(userEntries(user))),
+	).Replace(text)
+}
+
+// handleBroadcast queues a Telegram message for every participant of the
+// event, with the placeholders filled in for each of them, and opens the
+// broadcast's delivery report. Delivery happens asynchronously through the
+// outbox.
+func (s *Service) handleBroadcast(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	text := form.RequiredText("text", maxMessageLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid message", err)
+		return
+	}
+
+	var broadcast *sqlc.Broadcasts
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		broadcast, err = tx.CreateBroadcast(r.Context(), &sqlc.CreateBroadcastParams{
+			EventID: eventID,
+			Text:    text,
+			SendAt:  time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.MarkBroadcastQueued(r.Context(), broadcast.ID); err != nil {
+			return err
+		}
+		_, err = queueBroadcast(r.Context(), tx, broadcast)
+		return err
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to queue broadcast", apperr.FromDB(err))
+		return
+	}
+
+	w.Header().Set("HX-Redirect", broadcastURL(broadcast))
+}
+
+// queueBroadcast queues the broadcast for every participant of its event
+// who can be messaged, with the placeholders filled in for each of them,
+// and returns how many messages were queued. Each message gets a delivery
+// row the outbox records its result in.
+func queueBroadcast(ctx context.Context, st store.Store, broadcast *sqlc.Broadcasts) (int, error) {
+	users, err := st.GetUsersByEventID(ctx, broadcast.EventID)
+	if err != nil {
+		return 0, err
+	}
+
+	var queued int
+	for _, user := range users {
+		if !reachable(user) {
+			continue
+		}
+		delivery, err := st.CreateBroadcastDelivery(ctx, &sqlc.CreateBroadcastDeliveryParams{
+			BroadcastID: broadcast.ID,
+			UserID:      sql.NullInt64{Int64: user.ID, Valid: true},
+			ChatID:      user.TgID.Int64,
+			Text:        personalize(broadcast.Text, user),
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := enqueueDelivery(ctx, st, delivery); err != nil {
+			return 0, err
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// reachable reports whether the bot can message the participant: those
+// registered on the website have no chat, and those who blocked the bot
+// would only get their messages refused.
+func reachable(user *sqlc.Users) bool {
+	return user.TgID.Valid && !user.BotBlockedAt.Valid
+}
+
+func enqueueDelivery(ctx context.Context, st store.Store, delivery *sqlc.BroadcastDeliveries) error {
+	_, err := st.EnqueueOutboxMessage(ctx, &sqlc.EnqueueOutboxMessageParams{
+		ChatID:     delivery.ChatID,
+		Text:       delivery.Text,
+		DeliveryID: sql.NullInt64{Int64: delivery.ID, Valid: true},
+	})
+	return err
+}
+
+// handleBroadcastPreview shows the broadcast as its first recipient would
+// get it, or as a made-up participant if nobody can receive it.
+func (s *Service) handleBroadcastPreview(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	text := form.RequiredText("text", maxMessageLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid message", err)
+		return
+	}
+
+	users, err := s.store.GetUsersByEventID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get users", apperr.FromDB(err))
+		return
+	}
+	recipient := sampleRecipient
+	for _, user := range users {
+		if reachable(user) {
+			recipient = user
+			break
+		}
+	}
+
+	s.runTemplate(w, r, "broadcast_preview", struct {
+		Recipient *sqlc.Users
+		Sample    bool
+		Text      string
+	}{
+		Recipient: recipient,
+		Sample:    recipient == sampleRecipient,
+		Text:      personalize(text, recipient),
+	})
+}
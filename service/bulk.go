@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+// Bulk actions on the participants selected in the admin table.
+const (
+	bulkDelete = "delete"
+	bulkSetN   = "set_n"
+)
+
+// handleBulkUpdateUsers deletes the selected participants or sets their
+// entries, all in one transaction, then renders the table again.
+func (s *Service) handleBulkUpdateUsers(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	userIDs := form.IDs("user_id", maxBulkUsers)
+	action := r.FormValue("action")
+	var n int
+	switch action {
+	case bulkDelete:
+	case bulkSetN:
+		n = form.Int("n", 1, maxUserEntries)
+	default:
+		if form.Err() == nil {
+			s.renderError(w, r, "Invalid bulk action", apperr.Validation("Unknown bulk action"))
+			return
+		}
+	}
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid bulk action", err)
+		return
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		return bulkUpdateUsers(r.Context(), tx, eventID, userIDs, action, n)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update users", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Bulk updated users",
+		slog.Int64("event_id", eventID), slog.String("action", action), slog.Int("users", len(userIDs)))
+
+	page, err := s.usersPage(r.Context(), event, newUsersView(r), 0)
+	if err != nil {
+		s.renderError(w, r, "Failed to get users", apperr.FromDB(err))
+		return
+	}
+	s.runTemplate(w, r, "admin_event_users_table", page)
+}
+
+// bulkUpdateUsers applies action to the event's participants with the
+// given IDs. IDs of other events' participants are ignored.
+func bulkUpdateUsers(ctx context.Context, tx store.Store, eventID int64, userIDs []int64, action string, n int) error {
+	for _, userID := range userIDs {
+		var err error
+		switch action {
+		case bulkDelete:
+			err = removeParticipant(ctx, tx, eventID, userID, consent.Deleted, consent.SourceAdmin)
+		case bulkSetN:
+			err = setUserEntries(ctx, tx, eventID, userID, int32(n))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setUserEntries sets how many entries the event's participant has in the
+// draw, recording the change in the audit log. Participants of other
+// events are left alone.
+func setUserEntries(ctx context.Context, tx store.Store, eventID, userID int64, n int32) error {
+	user, err := tx.GetUserByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && user.EventID != eventID {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tx.UpdateUserN(ctx, &sqlc.UpdateUserNParams{
+		ID:      userID,
+		EventID: eventID,
+		N:       n,
+	}); err != nil {
+		return err
+	}
+	return audit.Record(ctx, tx, audit.UserEntriesSet, eventID, map[string]int32{"n": user.N}, map[string]any{
+		"user_id":       user.ID,
+		"ticket_number": user.TicketNumber,
+		"n":             n,
+	})
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/gcal"
+	"giveaway-tool/notify"
+	"giveaway-tool/store"
+)
+
+const (
+	// calendarSyncInterval is how often changed events are pushed to the
+	// calendar
+	calendarSyncInterval = time.Minute
+	// calendarEventLength is how long events last in the calendar, since
+	// events only have a start time
+	calendarEventLength = 2 * time.Hour
+	// defaultCalendarTimeZone is the zone event dates are in, overridden by
+	// GOOGLE_CALENDAR_TIME_ZONE
+	defaultCalendarTimeZone = "Europe/Kyiv"
+)
+
+// calendarFromEnv returns the Google Calendar client configured by
+// GOOGLE_CALENDAR_ID and the service account key file named by
+// GOOGLE_APPLICATION_CREDENTIALS. Syncing is disabled when it returns nil.
+func calendarFromEnv(ctx context.Context, logger *slog.Logger) *gcal.Client {
+	calendarID := os.Getenv("GOOGLE_CALENDAR_ID")
+	if calendarID == "" {
+		return nil
+	}
+	key, err := os.ReadFile(os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"))
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "Can't read GOOGLE_APPLICATION_CREDENTIALS, calendar sync is disabled", slog.Any("error", err))
+		return nil
+	}
+	client, err := gcal.New(calendarID, key)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "Invalid service account key, calendar sync is disabled", slog.Any("error", err))
+		return nil
+	}
+	return client
+}
+
+func calendarTimeZone() string {
+	if tz := os.Getenv("GOOGLE_CALENDAR_TIME_ZONE"); tz != "" {
+		return tz
+	}
+	return defaultCalendarTimeZone
+}
+
+// syncCalendar pushes new and changed events to the Google Calendar until
+// ctx is done. Events are compared by version, so every edit is synced
+// once, and a failed push is retried on the next tick.
+func (s *Service) syncCalendar(ctx context.Context) {
+	if s.calendar == nil {
+		return
+	}
+
+	ticker := time.NewTicker(calendarSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.syncCalendarEvents(ctx); err != nil {
+				s.logger.LogAttrs(ctx, slog.LevelError, "Failed to sync calendar", slog.Any("error", err))
+				notify.JobFailed("синхронізація з Google Calendar", err)
+			}
+		}
+	}
+}
+
+// syncCalendarEvents pushes every event that changed since its last sync.
+// One event failing doesn't hold up the others.
+func (s *Service) syncCalendarEvents(ctx context.Context) error {
+	events, err := s.store.GetEventsToSyncToCalendar(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, event := range events {
+		// Locking the event keeps several instances from creating it in the
+		// calendar twice
+		err := s.store.InTx(ctx, func(tx store.Store) error {
+			locked, err := tx.LockEventForCalendarSync(ctx, event.ID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return s.pushToCalendar(ctx, tx, locked)
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) pushToCalendar(ctx context.Context, tx store.Store, event *sqlc.Events) error {
+	id, err := s.calendar.Upsert(ctx, event.CalendarEventID.String, gcal.Event{
+		Summary:     event.Name,
+		Description: event.Description.String,
+		Start:       event.Date,
+		End:         event.Date.Add(calendarEventLength),
+		TimeZone:    calendarTimeZone(),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.LogAttrs(ctx, slog.LevelInfo, "Synced event to calendar",
+		slog.Int64("event_id", event.ID), slog.String("calendar_event_id", id))
+	return tx.SetEventCalendarSynced(ctx, &sqlc.SetEventCalendarSyncedParams{
+		ID:                    event.ID,
+		CalendarEventID:       sql.NullString{String: id, Valid: true},
+		CalendarSyncedVersion: event.Version,
+	})
+}
+
+// removeFromCalendar deletes a deleted event's copy in the calendar. It is
+// best effort: a failure is logged and the copy has to be removed by hand.
+func (s *Service) removeFromCalendar(ctx context.Context, event *sqlc.Events) {
+	if s.calendar == nil || !event.CalendarEventID.Valid {
+		return
+	}
+	if err := s.calendar.Delete(ctx, event.CalendarEventID.String); err != nil {
+		s.logger.LogAttrs(ctx, slog.LevelError, "Failed to remove event from calendar",
+			slog.Int64("event_id", event.ID), slog.Any("error", err))
+	}
+}
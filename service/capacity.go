@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/capacity"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+	"giveaway-tool/waitlist"
+)
+
+// maxEventCapacity caps the places of one event, like those of a ticket
+// type.
+const maxEventCapacity = maxTicketTypeCapacity
+
+// handleSetCapacity limits how many participants can register for an
+// event. 0 removes the limit. Lowering it below the participants the event
+// has only closes registration; nobody is removed. Raising it gives the new
+// places to the people on the waitlist.
+func (s *Service) handleSetCapacity(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	places := form.Int("capacity", 0, maxEventCapacity)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid capacity", err)
+		return
+	}
+
+	var event *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		before, err := tx.GetEventForUpdate(r.Context(), eventID)
+		if err != nil {
+			return err
+		}
+		if event, err = tx.SetEventCapacity(r.Context(), &sqlc.SetEventCapacityParams{
+			ID:       eventID,
+			Capacity: int32(places),
+		}); err != nil {
+			return err
+		}
+		if err := audit.Record(r.Context(), tx, audit.CapacitySet, eventID, before, event); err != nil {
+			return err
+		}
+		promoted, err := waitlist.Fill(r.Context(), tx, eventID)
+		for _, user := range promoted {
+			logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Promoted from waitlist",
+				slog.Int64("event_id", eventID), slog.Int64("user_id", user.ID))
+		}
+		return err
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update capacity", apperr.FromDB(err))
+		return
+	}
+
+	if event.Capacity == 0 {
+		fmt.Fprintf(w, successHTML, "Збережено: кількість місць не обмежена")
+		return
+	}
+	left, err := capacity.Left(r.Context(), s.store, event)
+	if err != nil {
+		s.renderError(w, r, "Failed to count places", apperr.FromDB(err))
+		return
+	}
+	fmt.Fprintf(w, successHTML, fmt.Sprintf("Збережено: місць — %d, з них вільних — %d", event.Capacity, left))
+}
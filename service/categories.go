@@ -0,0 +1,50 @@
+package service
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/validate"
+)
+
+// eventCategories returns the categories of events, sorted without
+// duplicates, for the filter of the events page.
+func eventCategories(events []*sqlc.Events) []string {
+	categories := make([]string, 0)
+	for _, event := range events {
+		categories = append(categories, event.Categories...)
+	}
+	slices.Sort(categories)
+	return slices.Compact(categories)
+}
+
+// handleSetEventCategories replaces the categories of an event. They are
+// normalized like participant tags, so "Party" and "party" are one.
+func (s *Service) handleSetEventCategories(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	categories := form.List("categories", maxCategoriesPerEvent, maxTagLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid categories", err)
+		return
+	}
+
+	event, err := s.store.SetEventCategories(r.Context(), &sqlc.SetEventCategoriesParams{
+		ID:         eventID,
+		Categories: normalizeTags(categories),
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update categories", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_event_categories", event)
+}
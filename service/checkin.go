@@ -0,0 +1,127 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/router"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+type checkInRequest struct {
+	TicketNumber int32  `json:"ticket_number"`
+	TgID         int64  `json:"tg_id"`
+	Code         string `json:"code"`
+}
+
+type checkInResponse struct {
+	Participant      participantResponse `json:"participant"`
+	AlreadyCheckedIn bool                `json:"already_checked_in"`
+}
+
+// requireAPIKey authenticates scanner apps and integrations polling for
+// registrations with the key from CHECKIN_API_KEY, sent as a bearer token
+// or in the X-API-Key header.
+func (s *Service) requireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validAPIKey(requestAPIKey(r), s.checkInAPIKey) {
+			s.renderJSONError(w, r, "Invalid API key", apperr.Unauthorized("Invalid API key"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// findByCheckInCode looks up a participant by the short code from their
+// ticket. Since codes are short enough to guess, a client entering too
+// many unknown codes is locked out for a while. client names who is
+// looking up, so one guesser doesn't lock out the other scanners.
+func (s *Service) findByCheckInCode(r *http.Request, client string, eventID int64, code string) (*sqlc.Users, error) {
+	if !s.checkInCodeFails.allow(client) {
+		return nil, apperr.TooManyRequests("Too many wrong codes, try again later")
+	}
+
+	user, err := s.store.GetUserByCheckInCode(r.Context(), &sqlc.GetUserByCheckInCodeParams{
+		EventID:     eventID,
+		CheckInCode: store.NormalizeCheckInCode(code),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		s.checkInCodeFails.fail(client)
+	}
+	if err != nil {
+		return nil, apperr.FromDB(err)
+	}
+	return user, nil
+}
+
+// handleCheckIn marks a participant as arrived, looked up by ticket number,
+// check-in code or Telegram ID.
+func (s *Service) handleCheckIn(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	var req checkInRequest
+	if err := validate.JSON(r, &req); err != nil {
+		s.renderJSONError(w, r, "Failed to decode check-in", err)
+		return
+	}
+
+	var user *sqlc.Users
+	switch {
+	case req.TicketNumber > 0:
+		user, err = s.store.GetUserByTicketNumber(r.Context(), &sqlc.GetUserByTicketNumberParams{
+			EventID:      eventID,
+			TicketNumber: req.TicketNumber,
+		})
+	case req.Code != "":
+		// Every scanner has the same key, so they are told apart by address
+		user, err = s.findByCheckInCode(r, "key:checkin@"+router.ClientIP(r), eventID, req.Code)
+	case req.TgID != 0:
+		user, err = s.store.GetUserByTgIDAndEventID(r.Context(), &sqlc.GetUserByTgIDAndEventIDParams{
+			EventID: eventID,
+			TgID:    req.TgID,
+		})
+	default:
+		s.renderJSONError(w, r, "Missing participant", apperr.Validation("ticket_number, code or tg_id is required"))
+		return
+	}
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to find participant", apperr.FromDB(err))
+		return
+	}
+
+	alreadyCheckedIn := user.CheckedInAt.Valid
+	var seat string
+	if alreadyCheckedIn {
+		seat = s.seatLabel(r.Context(), user.ID)
+	} else if user, seat, err = s.checkIn(r.Context(), user); err != nil {
+		s.renderJSONError(w, r, "Failed to check in participant", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Checked in participant",
+		slog.Int64("event_id", eventID),
+		slog.Int64("user_id", user.ID),
+		slog.Bool("already_checked_in", alreadyCheckedIn))
+
+	participant := newParticipantResponse(user)
+	participant.Seat = seat
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(checkInResponse{
+		Participant:      participant,
+		AlreadyCheckedIn: alreadyCheckedIn,
+	})
+}
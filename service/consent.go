@@ -0,0 +1,58 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/logging"
+)
+
+// handleExportConsentLog sends the event's consent log as CSV, for
+// compliance questions. Entries of deleted participants have no user ID.
+func (s *Service) handleExportConsentLog(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	if _, err := s.store.GetEventByID(r.Context(), eventID); err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	records, err := s.store.GetConsentLogByEventID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get consent log", apperr.FromDB(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-consent.csv"`, eventID))
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"at", "ticket_number", "user_id", "action", "source", "version"})
+	for _, record := range records {
+		userID := ""
+		if record.UserID.Valid {
+			userID = strconv.FormatInt(record.UserID.Int64, 10)
+		}
+		out.Write([]string{
+			record.CreatedAt.Format(time.RFC3339),
+			strconv.Itoa(int(record.TicketNumber)),
+			userID,
+			record.Action,
+			record.Source,
+			record.Version,
+		})
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to write CSV", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/authz"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+)
+
+// handleCopyParticipants copies the participants of another event into this
+// one. Participants already registered here are skipped.
+func (s *Service) handleCopyParticipants(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	fromID, err := strconv.ParseInt(r.FormValue("from"), 10, 64)
+	if err != nil || fromID == eventID {
+		s.renderError(w, r, "Invalid source event", apperr.Validation("Choose another event to copy from"))
+		return
+	}
+	if !authz.EventAllowed(r.Context(), fromID) {
+		s.renderError(w, r, "Source event not allowed", errNotOrganizer)
+		return
+	}
+
+	users, err := s.store.GetUsersByEventID(r.Context(), fromID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get users", apperr.FromDB(err))
+		return
+	}
+
+	rows := make([]store.NewUser, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, store.NewUser{
+			Name:     user.Name,
+			Username: user.Username,
+			TgID:     user.TgID,
+		})
+	}
+
+	result := store.CreateUsers(r.Context(), s.store, eventID, rows, store.DefaultChunkSize)
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Copied participants",
+		slog.Int64("from_event_id", fromID),
+		slog.Int64("event_id", eventID),
+		slog.Int64("inserted", result.Inserted),
+		slog.Int("failed_chunks", len(result.Errors)))
+
+	if len(result.Errors) > 0 {
+		msgs := make([]string, 0, len(result.Errors))
+		for _, chunkErr := range result.Errors {
+			logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to copy participants chunk",
+				slog.Int("start", chunkErr.Start), slog.Int("end", chunkErr.End), slog.Any("error", chunkErr.Err))
+			msgs = append(msgs, fmt.Sprintf("rows %d-%d: %s", chunkErr.Start+1, chunkErr.End, apperr.Message(apperr.FromDB(chunkErr.Err))))
+		}
+		// The rows that were copied stay, so copying again only adds the rest
+		s.renderError(w, r, "Failed to copy some participants", apperr.Conflict(
+			fmt.Sprintf("Copied %d participants, some rows failed: %s", result.Inserted, strings.Join(msgs, "; "))))
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, fmt.Sprintf("Copied %d participants", result.Inserted))
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	cryptoRand "crypto/rand"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/logging"
+	"giveaway-tool/router"
+
+	"github.com/gorilla/sessions"
+)
+
+const (
+	// csrfCookie holds a copy of the session's CSRF token that the admin
+	// pages read and send back in csrfHeader. Other sites can't read it,
+	// so they can't make requests carrying it.
+	csrfCookie = "csrf_token"
+	csrfHeader = "X-CSRF-Token"
+)
+
+// setCSRFCookie lets the admin pages read token. It lives as long as the
+// session does, maxAge being the session's, and like the session cookie it
+// is only sent back over HTTPS when r came over HTTPS.
+func (s *Service) setCSRFCookie(w http.ResponseWriter, r *http.Request, token string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   router.IsHTTPS(r),
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// requireCSRFToken rejects state-changing requests that don't carry the
+// CSRF token of the admin's session, on top of the browser headers checked
+// by router.CSRF. Sessions started before tokens existed get one on their
+// next page load. It goes after requireRole, which checks the session.
+func (s *Service) requireCSRFToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := s.sessionStore.Get(r, "session")
+		if err != nil {
+			s.renderError(w, r, "Failed to get session", apperr.Unauthorized("Sign in again"))
+			return
+		}
+		token, _ := session.Values["csrf"].(string)
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			if token == "" {
+				token = cryptoRand.Text()
+				session.Values["csrf"] = token
+				if err := session.Save(r, w); err != nil {
+					logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to save session", slog.Any("error", err))
+				}
+			}
+			if cookie, err := r.Cookie(csrfCookie); err != nil || cookie.Value != token {
+				s.setCSRFCookie(w, r, token, session.Options.MaxAge)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !validCSRFToken(r, session) {
+			s.renderError(w, r, "Invalid CSRF token", apperr.Forbidden("The page has expired. Reload it and try again"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validCSRFToken reports whether r carries the CSRF token of session.
+func validCSRFToken(r *http.Request, session *sessions.Session) bool {
+	token, _ := session.Values["csrf"].(string)
+	return token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(csrfHeader)), []byte(token)) == 1
+}
@@ -0,0 +1,63 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"giveaway-tool/store/memory"
+)
+
+func TestCSRFToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token func(session string) string
+		site  string
+		want  int
+	}{
+		{name: "session token", token: func(session string) string { return session }, want: http.StatusOK},
+		{name: "no token", token: func(string) string { return "" }, want: http.StatusForbidden},
+		{name: "other token", token: func(string) string { return "forged" }, want: http.StatusForbidden},
+		{name: "cross-site", token: func(session string) string { return session }, site: "cross-site", want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := memory.New()
+			server := newServer(t, st)
+			newAdmin(t, st, "owner", "owner")
+			client, session := signIn(t, server.URL, "owner")
+
+			form := url.Values{"enabled": {"false"}}
+			r, err := http.NewRequest(http.MethodPost, server.URL+"/admin/flags/provably_fair_draws", strings.NewReader(form.Encode()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if token := tt.token(session); token != "" {
+				r.Header.Set("X-CSRF-Token", token)
+			}
+			if tt.site != "" {
+				r.Header.Set("Sec-Fetch-Site", tt.site)
+			}
+			resp, err := client.Do(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+			flags, err := st.GetFeatureFlags(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved := len(flags) > 0; saved != (tt.want == http.StatusOK) {
+				t.Errorf("flag saved = %t after status %d", saved, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+)
+
+// broadcastURL is the delivery report of a broadcast.
+func broadcastURL(broadcast *sqlc.Broadcasts) string {
+	return fmt.Sprintf("/admin/events/%d/broadcasts/%d", broadcast.EventID, broadcast.ID)
+}
+
+type broadcastReport struct {
+	Event      *sqlc.Events
+	Broadcast  *sqlc.Broadcasts
+	Deliveries []*sqlc.GetBroadcastDeliveriesRow
+	// Counts has the number of deliveries in each status
+	Counts map[string]int
+	// Done is the percentage of deliveries that aren't pending
+	Done int
+}
+
+func (s *Service) broadcastReport(r *http.Request) (*broadcastReport, error) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return nil, apperr.Validation("Invalid event ID")
+	}
+	broadcastID, err := strconv.ParseInt(r.PathValue("broadcastID"), 10, 64)
+	if err != nil {
+		return nil, apperr.Validation("Invalid broadcast ID")
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		return nil, apperr.FromDB(err)
+	}
+	broadcast, err := s.store.GetBroadcastByID(r.Context(), &sqlc.GetBroadcastByIDParams{ID: broadcastID, EventID: eventID})
+	if err != nil {
+		return nil, apperr.FromDB(err)
+	}
+	deliveries, err := s.store.GetBroadcastDeliveries(r.Context(), broadcastID)
+	if err != nil {
+		return nil, apperr.FromDB(err)
+	}
+
+	report := &broadcastReport{
+		Event:      event,
+		Broadcast:  broadcast,
+		Deliveries: deliveries,
+		Counts:     make(map[string]int),
+		Done:       100,
+	}
+	for _, delivery := range deliveries {
+		report.Counts[delivery.BroadcastDeliveries.Status]++
+	}
+	if len(deliveries) > 0 {
+		report.Done = 100 * (len(deliveries) - report.Counts[store.DeliveryPending]) / len(deliveries)
+	}
+	return report, nil
+}
+
+// handleBroadcastPage shows how a broadcast's messages were delivered.
+func (s *Service) handleBroadcastPage(w http.ResponseWriter, r *http.Request) {
+	report, err := s.broadcastReport(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get broadcast", err)
+		return
+	}
+
+	s.runTemplate(w, r, "admin_broadcast", report)
+}
+
+// handleBroadcastStatus renders the delivery report alone; the page polls
+// it while messages are pending.
+func (s *Service) handleBroadcastStatus(w http.ResponseWriter, r *http.Request) {
+	report, err := s.broadcastReport(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get broadcast", err)
+		return
+	}
+
+	s.runTemplate(w, r, "admin_broadcast_status", report)
+}
+
+// handleRetryBroadcast queues the broadcast's failed messages again.
+// Participants who blocked the bot are skipped, since Telegram would refuse
+// the message again.
+func (s *Service) handleRetryBroadcast(w http.ResponseWriter, r *http.Request) {
+	report, err := s.broadcastReport(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get broadcast", err)
+		return
+	}
+
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		for _, row := range report.Deliveries {
+			delivery := row.BroadcastDeliveries
+			if delivery.Status != store.DeliveryFailed {
+				continue
+			}
+			if err := tx.SetBroadcastDeliveryStatus(r.Context(), &sqlc.SetBroadcastDeliveryStatusParams{
+				ID:     delivery.ID,
+				Status: store.DeliveryPending,
+			}); err != nil {
+				return err
+			}
+			if err := enqueueDelivery(r.Context(), tx, &delivery); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to retry broadcast", apperr.FromDB(err))
+		return
+	}
+
+	s.handleBroadcastStatus(w, r)
+}
+
+// handleExportDeliveries sends the broadcast's deliveries as CSV.
+func (s *Service) handleExportDeliveries(w http.ResponseWriter, r *http.Request) {
+	report, err := s.broadcastReport(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get broadcast", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="broadcast-%d-deliveries.csv"`, report.Broadcast.ID))
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"ticket_number", "name", "chat_id", "status", "error", "updated_at"})
+	for _, row := range report.Deliveries {
+		delivery := row.BroadcastDeliveries
+		ticket := ""
+		if row.TicketNumber.Valid {
+			ticket = strconv.Itoa(int(row.TicketNumber.Int32))
+		}
+		out.Write([]string{
+			ticket,
+			csvSafe(row.Name.String),
+			strconv.FormatInt(delivery.ChatID, 10),
+			delivery.Status,
+			csvSafe(delivery.Error.String),
+			delivery.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to write CSV", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/notify"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+const (
+	// digestPeriod is how far back the weekly digest looks
+	digestPeriod = 7 * 24 * time.Hour
+	// digestUpcoming is how far ahead the digest lists upcoming events
+	digestUpcoming = 14 * 24 * time.Hour
+)
+
+// weekdays names time.Weekday values for the admin UI.
+var weekdays = []string{"Неділя", "Понеділок", "Вівторок", "Середа", "Четвер", "П'ятниця", "Субота"}
+
+type digestData struct {
+	Subscriptions []*sqlc.DigestSubscriptions
+	Weekdays      []string
+	TimeZone      string
+}
+
+// sendDigests periodically sends weekly digests that are due until ctx is
+// done.
+func (s *Service) sendDigests(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.sendDueDigests(ctx, now); err != nil {
+				s.logger.LogAttrs(ctx, slog.LevelError, "Failed to send digests", slog.Any("error", err))
+				notify.JobFailed("тижневі звіти", err)
+			}
+		}
+	}
+}
+
+func (s *Service) sendDueDigests(ctx context.Context, now time.Time) error {
+	subs, err := s.store.GetDigestSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		if !sub.WeeklyDigest || int32(now.Weekday()) != sub.Weekday || int32(now.Hour()) != sub.Hour {
+			continue
+		}
+
+		text, err := s.buildDigest(ctx, sub, now)
+		if err != nil {
+			return err
+		}
+
+		// Claiming the digest and queueing it together means it's sent
+		// once per week even if several instances are running
+		err = s.store.InTx(ctx, func(tx store.Store) error {
+			claimed, err := tx.MarkDigestSent(ctx, &sqlc.MarkDigestSentParams{
+				ID:         sub.ID,
+				SentBefore: now.Add(-24 * time.Hour),
+			})
+			if err != nil || claimed == 0 {
+				return err
+			}
+
+			s.logger.LogAttrs(ctx, slog.LevelInfo, "Sending weekly digest", slog.Int64("subscription_id", sub.ID))
+			_, err = tx.EnqueueOutboxMessage(ctx, &sqlc.EnqueueOutboxMessageParams{ChatID: sub.ChatID, Text: text})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildDigest summarises the week before now: new registrations, upcoming
+// events, completed draws and, if the admin wants them, anomalies.
+func (s *Service) buildDigest(ctx context.Context, sub *sqlc.DigestSubscriptions, now time.Time) (string, error) {
+	since := now.Add(-digestPeriod)
+
+	registrations, err := s.store.GetRegistrationsSince(ctx, since)
+	if err != nil {
+		return "", err
+	}
+
+	upcoming, err := s.store.GetEventsBetween(ctx, &sqlc.GetEventsBetweenParams{
+		FromDate: now,
+		ToDate:   now.Add(digestUpcoming),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	draws, err := s.store.GetDrawsSince(ctx, since)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Тижневий звіт: %s – %s\n", since.Format("02.01"), now.Format("02.01.2006"))
+
+	total := 0
+	for _, row := range registrations {
+		total += int(row.Registrations)
+	}
+	fmt.Fprintf(&b, "\nНові реєстрації: %d\n", total)
+	for _, row := range registrations {
+		fmt.Fprintf(&b, "• %s: %d\n", row.Name, row.Registrations)
+	}
+
+	b.WriteString("\nНайближчі івенти:\n")
+	for _, event := range upcoming {
+		count, err := s.store.CountUsersByEventID(ctx, event.ID)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "• %s — %s, учасників: %d\n", event.Date.Format("02.01 15:04"), event.Name, count)
+	}
+	if len(upcoming) == 0 {
+		b.WriteString("немає\n")
+	}
+
+	fmt.Fprintf(&b, "\nРозіграші: %d\n", len(draws))
+	for _, draw := range draws {
+		fmt.Fprintf(&b, "• %s — %s, переможців: %d\n", draw.CreatedAt.Time.Format("02.01"), draw.EventName, draw.WinnersCount)
+	}
+
+	if sub.IncludeAnomalies {
+		anomalies, err := s.store.GetAnomalyCounts(ctx, since)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString("\nВарто перевірити:\n")
+		if anomalies.PendingReview > 0 {
+			fmt.Fprintf(&b, "• Підозрілих реєстрацій чекають на перевірку: %d\n", anomalies.PendingReview)
+		}
+		if anomalies.FailedMessages > 0 {
+			fmt.Fprintf(&b, "• Недоставлених повідомлень у Telegram: %d\n", anomalies.FailedMessages)
+		}
+		if anomalies.FailedPayments > 0 {
+			fmt.Fprintf(&b, "• Невдалих платежів: %d\n", anomalies.FailedPayments)
+		}
+		if *anomalies == (sqlc.GetAnomalyCountsRow{}) {
+			b.WriteString("нічого\n")
+		}
+	}
+
+	return b.String(), nil
+}
+
+func (s *Service) digestData(ctx context.Context) (*digestData, error) {
+	subs, err := s.store.GetDigestSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &digestData{
+		Subscriptions: subs,
+		Weekdays:      weekdays,
+		TimeZone:      time.Now().Format("MST"),
+	}, nil
+}
+
+func (s *Service) handleDigestPage(w http.ResponseWriter, r *http.Request) {
+	data, err := s.digestData(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get digest subscriptions", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_digest", data)
+}
+
+// digestSchedule is where and when a recipient gets the weekly digest.
+type digestSchedule struct {
+	ChatID  int64
+	Weekday int32
+	Hour    int32
+}
+
+func parseDigestSchedule(r *http.Request) (*digestSchedule, error) {
+	chatID, err := strconv.ParseInt(strings.TrimSpace(r.FormValue("chat_id")), 10, 64)
+	if err != nil {
+		return nil, apperr.Validation("Invalid chat ID")
+	}
+
+	weekday, err := validate.Range("weekday", r.FormValue("weekday"), 0, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	hour, err := validate.Range("hour", r.FormValue("hour"), 0, 23)
+	if err != nil {
+		return nil, err
+	}
+	return &digestSchedule{ChatID: chatID, Weekday: int32(weekday), Hour: int32(hour)}, nil
+}
+
+func (s *Service) handleCreateDigestSubscription(w http.ResponseWriter, r *http.Request) {
+	form := validate.NewForm(r)
+	arg := &sqlc.CreateDigestSubscriptionParams{
+		Name:             form.RequiredText("name", maxNameLength),
+		IncludeAnomalies: r.FormValue("include_anomalies") == "true",
+	}
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid digest subscription", err)
+		return
+	}
+
+	schedule, err := parseDigestSchedule(r)
+	if err != nil {
+		s.renderError(w, r, "Invalid digest subscription", err)
+		return
+	}
+	arg.ChatID = schedule.ChatID
+	arg.Weekday = schedule.Weekday
+	arg.Hour = schedule.Hour
+
+	if _, err := s.store.CreateDigestSubscription(r.Context(), arg); err != nil {
+		s.renderError(w, r, "Failed to create digest subscription", apperr.FromDB(err))
+		return
+	}
+
+	s.renderDigestList(w, r)
+}
+
+func (s *Service) handleDeleteDigestSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid subscription ID", apperr.Validation("Invalid subscription ID"))
+		return
+	}
+
+	if err := s.store.DeleteDigestSubscription(r.Context(), id); err != nil {
+		s.renderError(w, r, "Failed to delete digest subscription", apperr.FromDB(err))
+		return
+	}
+
+	s.renderDigestList(w, r)
+}
+
+// handleSendDigest queues the digest right away, so admins can check what
+// it looks like without waiting for the scheduled time.
+func (s *Service) handleSendDigest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid subscription ID", apperr.Validation("Invalid subscription ID"))
+		return
+	}
+
+	sub, err := s.store.GetDigestSubscription(r.Context(), id)
+	if err != nil {
+		s.renderError(w, r, "Failed to get digest subscription", apperr.FromDB(err))
+		return
+	}
+
+	text, err := s.buildDigest(r.Context(), sub, time.Now())
+	if err != nil {
+		s.renderError(w, r, "Failed to build digest", apperr.FromDB(err))
+		return
+	}
+
+	if _, err := s.store.EnqueueOutboxMessage(r.Context(), &sqlc.EnqueueOutboxMessageParams{
+		ChatID: sub.ChatID,
+		Text:   text,
+	}); err != nil {
+		s.renderError(w, r, "Failed to queue digest", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Queued digest on demand",
+		slog.Int64("subscription_id", sub.ID))
+
+	fmt.Fprintf(w, successHTML, "Звіт поставлено в чергу на відправку")
+}
+
+func (s *Service) renderDigestList(w http.ResponseWriter, r *http.Request) {
+	data, err := s.digestData(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get digest subscriptions", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_digest_list", data)
+}
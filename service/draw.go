@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"giveaway-tool/audit"
+	"giveaway-tool/config"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/notify"
+	"giveaway-tool/store"
+)
+
+// drawOptions selects who can win a draw.
+type drawOptions struct {
+	Count  int
+	Filter tagFilter
+	// OnlyReachable leaves out participants the bot can't reach, for draws
+	// whose prizes are handed out over Telegram
+	OnlyReachable bool
+}
+
+// drawResult is a saved draw with its winners in order.
+type drawResult struct {
+	DrawID  int64
+	Winners []*sqlc.Users
+	// Seed is "" unless the draw was provably fair
+	Seed string
+	// PendingReview counts flagged participants left out of the draw
+	PendingReview int
+}
+
+// winnersData shows the winners of a draw with the form to announce them.
+type winnersData struct {
+	Users         []*sqlc.Users `json:"event"`
+	Seed          string        `json:"seed"`
+	PendingReview int           `json:"pending_review"`
+	EventID       int64         `json:"event_id"`
+	DrawID        int64         `json:"draw_id"`
+}
+
+func newWinnersData(eventID int64, result *drawResult) winnersData {
+	return winnersData{
+		Users:         result.Winners,
+		Seed:          result.Seed,
+		PendingReview: result.PendingReview,
+		EventID:       eventID,
+		DrawID:        result.DrawID,
+	}
+}
+
+// drawPool is the draw engine: it holds the entries of the participants
+// who can win and picks winners from them one at a time, so a draw can
+// pick all of its winners at once or reveal them live.
+type drawPool struct {
+	eventID int64
+	// entries has each participant's ID once per entry
+	entries []int64
+	// candidates is how many participants can still win
+	candidates int
+	won        map[int64]bool
+	intN       func(int) int
+	// seed is set for provably fair draws
+	seed          sql.NullString
+	pendingReview int
+}
+
+// newDrawPool takes a snapshot of who can win the event's draw.
+func (s *Service) newDrawPool(ctx context.Context, eventID int64, opts drawOptions) (*drawPool, error) {
+	// Flag suspicious registrations made since the last check, so they
+	// can't win before an admin has looked at them
+	if err := flagSuspicious(ctx, s.store, eventID); err != nil {
+		return nil, err
+	}
+
+	// Snapshot only the participant IDs, a page at a time, so large events
+	// aren't held in memory as full rows
+	users := make([]int64, 0)
+	votes := make([]int32, 0)
+	pendingReview := 0
+	for user, err := range store.EventUsers(ctx, s.store, eventID, store.DefaultPageSize) {
+		if err != nil {
+			return nil, err
+		}
+		if user.FlagReason.Valid && !user.ReviewedAt.Valid {
+			pendingReview++
+			continue
+		}
+		if !opts.Filter.match(user.Tags) {
+			continue
+		}
+		if opts.OnlyReachable && !reachable(user) {
+			continue
+		}
+		users = append(users, user.ID)
+		votes = append(votes, userEntries(user))
+	}
+
+	n := len(users)
+	for i := range n {
+		n := votes[i]
+		if n > 1 {
+			for range n - 1 {
+				users = append(users, users[i])
+			}
+		}
+	}
+
+	// Provably fair draws use a published seed, so anyone can replay the
+	// draw over the participants ordered by ID
+	intN := rand.IntN
+	var seed sql.NullString
+	if config.FlagEnabled(config.FlagProvablyFairDraws) {
+		var seedBytes [32]byte
+		if _, err := cryptoRand.Read(seedBytes[:]); err != nil {
+			return nil, fmt.Errorf("generate draw seed: %w", err)
+		}
+		seed = sql.NullString{String: hex.EncodeToString(seedBytes[:]), Valid: true}
+		intN = rand.New(rand.NewChaCha8(seedBytes)).IntN
+	}
+
+	return &drawPool{
+		eventID:       eventID,
+		entries:       users,
+		candidates:    n,
+		won:           make(map[int64]bool),
+		intN:          intN,
+		seed:          seed,
+		pendingReview: pendingReview,
+	}, nil
+}
+
+// next picks the ID of the next winner, or returns false once everyone
+// has won. Users with several entries still win only once.
+func (p *drawPool) next() (int64, bool) {
+	for p.candidates > 0 {
+		// Pick a random index within the valid range
+		index := p.intN(len(p.entries))
+		id := p.entries[index]
+		if p.won[id] {
+			continue
+		}
+		p.won[id] = true
+		p.candidates--
+
+		// Remove the selected entry from the pool
+		p.entries = append(p.entries[:index], p.entries[index+1:]...)
+		return id, true
+	}
+	return 0, false
+}
+
+// runDraw picks the event's winners, saves the draw and queues the winner
+// notifications.
+func (s *Service) runDraw(ctx context.Context, eventID int64, opts drawOptions) (*drawResult, error) {
+	pool, err := s.newDrawPool(ctx, eventID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	winners := make([]*sqlc.Users, 0, min(opts.Count, pool.candidates))
+	for len(winners) < opts.Count {
+		id, ok := pool.next()
+		if !ok {
+			break
+		}
+		winner, err := s.store.GetUserByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		winners = append(winners, winner)
+	}
+	return s.saveDraw(ctx, pool, winners)
+}
+
+// saveDraw persists the draw so its results can be looked up later, and
+// queues the winner notifications in the same transaction so none are
+// lost.
+func (s *Service) saveDraw(ctx context.Context, pool *drawPool, winners []*sqlc.Users) (*drawResult, error) {
+	var (
+		summary string
+		drawID  int64
+	)
+	err := s.store.InTx(ctx, func(tx store.Store) error {
+		event, err := tx.GetEventByID(ctx, pool.eventID)
+		if err != nil {
+			return err
+		}
+
+		draw, err := tx.CreateDraw(ctx, &sqlc.CreateDrawParams{
+			EventID:      pool.eventID,
+			WinnersCount: int32(len(winners)),
+			Seed:         pool.seed,
+		})
+		if err != nil {
+			return err
+		}
+		drawID = draw.ID
+
+		for i, winner := range winners {
+			if err := tx.CreateDrawWinner(ctx, &sqlc.CreateDrawWinnerParams{
+				DrawID:   draw.ID,
+				UserID:   winner.ID,
+				Position: int32(i + 1),
+			}); err != nil {
+				return err
+			}
+
+			// Winners who blocked the bot are still notified if they gave
+			// a phone number: the message then falls back to an SMS
+			if !winner.TgID.Valid || (winner.BotBlockedAt.Valid && winner.Phone == "") {
+				continue
+			}
+			if _, err := tx.EnqueueOutboxMessage(ctx, &sqlc.EnqueueOutboxMessageParams{
+				ChatID:    winner.TgID.Int64,
+				Text:      fmt.Sprintf("Вітаємо! Твій квиток №%d виграв у розіграші на івенті ФІТКІ \"%s\"!", winner.TicketNumber, event.Name),
+				SmsUserID: sql.NullInt64{Int64: winner.ID, Valid: true},
+			}); err != nil {
+				return err
+			}
+		}
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "Розіграш на \"%s\" завершено. Переможці:", event.Name)
+		for i, winner := range winners {
+			fmt.Fprintf(&text, "\n%d. №%d %s", i+1, winner.TicketNumber, winner.Name)
+		}
+		summary = text.String()
+
+		tickets := make([]int32, len(winners))
+		for i, winner := range winners {
+			tickets[i] = winner.TicketNumber
+		}
+		if err := audit.Record(ctx, tx, audit.WinnersDrawn, event.ID, nil, map[string]any{
+			"draw_id": draw.ID,
+			"seed":    pool.seed.String,
+			"winners": tickets,
+		}); err != nil {
+			return err
+		}
+		return notify.Send(ctx, tx, notify.Draw, event.ID, summary)
+	})
+	if err != nil {
+		return nil, err
+	}
+	notify.Publish(notify.Draw, pool.eventID, summary)
+
+	return &drawResult{
+		DrawID:        drawID,
+		Winners:       winners,
+		Seed:          pool.seed.String,
+		PendingReview: pool.pendingReview,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+// duplicateEvent creates a copy of the event's setup named name, without
+// its participants and draws. A non-zero date moves the copy, its rule
+// deadlines and its registration window by the same amount; a zero date
+// keeps the event's date.
+func duplicateEvent(ctx context.Context, tx store.Store, eventID int64, name string, date time.Time) (*sqlc.Events, error) {
+	data, err := exportEventSetup(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Event.Name = name
+	// The copy gets a slug of its own name
+	data.Event.Slug = sql.NullString{}
+	if !date.IsZero() {
+		shift := date.Sub(data.Event.Date)
+		data.Event.Date = date
+		for _, t := range []*sql.NullTime{&data.Event.RegistrationOpensAt, &data.Event.RegistrationClosesAt} {
+			if t.Valid {
+				t.Time = t.Time.Add(shift)
+			}
+		}
+		for _, rule := range data.Rules {
+			if rule.RegisteredBefore.Valid {
+				rule.RegisteredBefore.Time = rule.RegisteredBefore.Time.Add(shift)
+			}
+		}
+	}
+	return importEvent(ctx, tx, data)
+}
+
+// handleDuplicateEvent copies the event for its next edition, which is
+// usually the same apart from the date. The name defaults to the event's.
+func (s *Service) handleDuplicateEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	name := form.Text("name", maxNameLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid event", err)
+		return
+	}
+
+	var date time.Time
+	if v := r.FormValue("date"); v != "" {
+		if date, err = time.Parse("2006-01-02T15:04", v); err != nil {
+			s.renderError(w, r, "Invalid event", apperr.Validation("Invalid date"))
+			return
+		}
+	}
+
+	var event *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		if name == "" {
+			source, err := tx.GetEventByID(r.Context(), eventID)
+			if err != nil {
+				return err
+			}
+			name = source.Name
+		}
+		event, err = duplicateEvent(r.Context(), tx, eventID, name, date)
+		return err
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to duplicate event", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Duplicated event",
+		slog.Int64("source_event_id", eventID), slog.Int64("event_id", event.ID))
+
+	w.Header().Set("HX-Redirect", "/admin/events/"+strconv.FormatInt(event.ID, 10))
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/capacity"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/lifecycle"
+)
+
+// counterResponse is the registration count of an event, for other sites
+// to show.
+type counterResponse struct {
+	EventID    int64  `json:"event_id"`
+	Name       string `json:"name"`
+	Registered int64  `json:"registered"`
+	// Capacity and PlacesLeft are left out for events that take everyone
+	Capacity   int32  `json:"capacity,omitempty"`
+	PlacesLeft *int32 `json:"places_left,omitempty"`
+}
+
+// eventCounter counts the registrations of a published event.
+func (s *Service) eventCounter(r *http.Request) (*sqlc.Events, counterResponse, error) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return nil, counterResponse{}, apperr.Validation("Invalid event ID")
+	}
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		return nil, counterResponse{}, apperr.FromDB(err)
+	}
+	if !lifecycle.Public(event) {
+		return nil, counterResponse{}, apperr.NotFound("Event not found")
+	}
+
+	resp := counterResponse{EventID: event.ID, Name: event.Name, Capacity: event.Capacity}
+	if resp.Registered, err = s.store.CountUsersByEventID(r.Context(), event.ID); err != nil {
+		return nil, counterResponse{}, apperr.FromDB(err)
+	}
+	if event.Capacity > 0 {
+		left, err := capacity.Left(r.Context(), s.store, event)
+		if err != nil {
+			return nil, counterResponse{}, apperr.FromDB(err)
+		}
+		resp.PlacesLeft = &left
+	}
+	return event, resp, nil
+}
+
+// handleEmbedCounter renders a small page with the event's registration
+// count, for other FITKI sites to put in an iframe. It fetches the count
+// from the JSON variant again as often as the count may be cached.
+func (s *Service) handleEmbedCounter(w http.ResponseWriter, r *http.Request) {
+	event, counter, err := s.eventCounter(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get counter", err)
+		return
+	}
+	event, err = s.translateEvent(r.Context(), event, language(w, r))
+	if err != nil {
+		s.renderError(w, r, "Failed to get translation", apperr.FromDB(err))
+		return
+	}
+	s.runTemplate(w, r, "embed_counter", struct {
+		Event          *sqlc.Events
+		Counter        counterResponse
+		RefreshSeconds int
+	}{event, counter, int(publicPageMaxAge.Seconds())})
+}
+
+// handleEmbedCounterJSON returns the event's registration count. Any site
+// may read it.
+func (s *Service) handleEmbedCounterJSON(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	_, counter, err := s.eventCounter(r)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get counter", err)
+		return
+	}
+	renderJSON(w, http.StatusOK, counter, 0)
+}
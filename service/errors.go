@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/logging"
+	"giveaway-tool/notify"
+)
+
+// errorNotifyInterval limits server error notifications, so an outage
+// doesn't flood admins' chats.
+const errorNotifyInterval = 5 * time.Minute
+
+// renderError logs err and reports it to the client with the status code
+// matching its kind, in the form the client can show: an error fragment for
+// HTMX requests, JSON for API clients, an error page for browsers opening a
+// page and plain text for anything else.
+func (s *Service) renderError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	status := s.logError(r, msg, err)
+
+	switch {
+	case r.Header.Get("HX-Request") == "true":
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, errHTML, template.HTMLEscapeString(apperr.Message(err)))
+	case wantsJSON(r):
+		writeJSONError(w, status, err)
+	case strings.Contains(r.Header.Get("Accept"), "text/html"):
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(status)
+		if err := s.tmpl.ExecuteTemplate(w, "error", errorPage{Status: status, Message: apperr.Message(err)}); err != nil {
+			logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to execute template", slog.Any("error", err))
+		}
+	default:
+		http.Error(w, apperr.Message(err), status)
+	}
+}
+
+// renderJSONError is renderError for the JSON API, whose clients get JSON
+// whatever they accept.
+func (s *Service) renderJSONError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	status := s.logError(r, msg, err)
+	writeJSONError(w, status, err)
+}
+
+// logError logs err, notifies admins of server errors and returns the
+// status code to report err with.
+func (s *Service) logError(r *http.Request, msg string, err error) int {
+	status := apperr.HTTPStatus(err)
+
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), level, msg, slog.Any("error", err))
+	if status >= http.StatusInternalServerError {
+		s.notifyError(r, msg, err)
+	}
+	return status
+}
+
+// wantsJSON reports whether r comes from an API client: it calls the API or
+// accepts JSON but not HTML.
+func wantsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	return strings.HasPrefix(r.URL.Path, "/api/") ||
+		strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/html")
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiEnvelope{Error: apperr.Message(err)})
+}
+
+// errorPage is shown to browsers opening a page that failed.
+type errorPage struct {
+	Status  int
+	Message string
+}
+
+// notifyError tells the admins who want to know about a server error,
+// unless they were already told about one recently.
+func (s *Service) notifyError(r *http.Request, msg string, err error) {
+	now := time.Now().Unix()
+	last := s.lastErrorNotice.Load()
+	if now-last < int64(errorNotifyInterval.Seconds()) || !s.lastErrorNotice.CompareAndSwap(last, now) {
+		return
+	}
+
+	// The request may have failed because its context ran out
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), requestTimeout)
+	defer cancel()
+	text := fmt.Sprintf("Помилка сервера: %s\n%s %s\n%v", msg, r.Method, r.URL.Path, err)
+	if err := notify.Send(ctx, s.store, notify.Error, 0, text); err != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to send error notification", slog.Any("error", err))
+	}
+}
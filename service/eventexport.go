@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/i18n"
+	"giveaway-tool/logging"
+	"giveaway-tool/sanitize"
+	"giveaway-tool/seating"
+	"giveaway-tool/slug"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+// eventExportVersion is bumped whenever the export format changes in a way
+// older instances can't read.
+const eventExportVersion = 1
+
+// eventExport is a self-contained copy of one event for moving it between
+// deployments. IDs are those of the exporting instance and only link the
+// records within the file; import assigns new ones.
+type eventExport struct {
+	Version    int                     `json:"version"`
+	ExportedAt time.Time               `json:"exported_at"`
+	Event      *sqlc.Events            `json:"event"`
+	Rules      []*sqlc.EntryRules      `json:"rules"`
+	Organizers []*sqlc.EventOrganizers `json:"organizers"`
+	// Translations are missing from files exported before they existed
+	Translations []*sqlc.EventTranslations `json:"translations,omitempty"`
+	TicketTypes  []*sqlc.TicketTypes       `json:"ticket_types,omitempty"`
+	Seats        []sqlc.Seats              `json:"seats,omitempty"`
+	Image        *sqlc.EventImages         `json:"image,omitempty"`
+	Users        []*sqlc.Users             `json:"users"`
+	Draws        []*exportedDraw           `json:"draws"`
+}
+
+type exportedDraw struct {
+	*sqlc.Draws
+	Winners []sqlc.DrawWinners `json:"winners"`
+}
+
+// exportEvent collects the event with its settings, participants and draws.
+// The kiosk token is left out, as it would let the file's holder check
+// people in.
+func exportEvent(ctx context.Context, st store.Store, eventID int64) (*eventExport, error) {
+	data, err := exportEventSetup(ctx, st, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Users, err = st.GetUsersByEventID(ctx, eventID); err != nil {
+		return nil, err
+	}
+	draws, err := st.GetDrawsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	for _, draw := range draws {
+		winners, err := st.GetDrawWinners(ctx, draw.ID)
+		if err != nil {
+			return nil, err
+		}
+		d := &exportedDraw{Draws: draw, Winners: make([]sqlc.DrawWinners, 0, len(winners))}
+		for _, winner := range winners {
+			d.Winners = append(d.Winners, sqlc.DrawWinners{DrawID: draw.ID, UserID: winner.Users.ID, Position: winner.Position})
+		}
+		data.Draws = append(data.Draws, d)
+	}
+	return data, nil
+}
+
+// exportEventSetup collects the event with its settings but without
+// participants and draws, so importing it leaves every seat free.
+func exportEventSetup(ctx context.Context, st store.Store, eventID int64) (*eventExport, error) {
+	event, err := st.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	exported := *event
+	exported.KioskToken = sql.NullString{}
+
+	rules, err := st.GetEntryRulesByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	organizers, err := st.GetEventOrganizers(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	translations, err := st.GetEventTranslations(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	ticketTypes, err := st.GetTicketTypesByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	seats, err := st.GetSeatsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &eventExport{
+		Version:      eventExportVersion,
+		ExportedAt:   time.Now().UTC(),
+		Event:        &exported,
+		Rules:        rules,
+		Organizers:   organizers,
+		Translations: translations,
+		TicketTypes:  ticketTypes,
+		Users:        []*sqlc.Users{},
+		Draws:        []*exportedDraw{},
+	}
+	for _, seat := range seats {
+		data.Seats = append(data.Seats, seat.Seats)
+	}
+	if event.ImageUpdatedAt.Valid {
+		if data.Image, err = st.GetEventImage(ctx, eventID); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// importEvent recreates an exported event as a new event. Share codes are
+// not carried over since they are unique across the instance; participants
+// get new ones when they next open their links.
+func importEvent(ctx context.Context, tx store.Store, data *eventExport) (*sqlc.Events, error) {
+	if data.Version != eventExportVersion {
+		return nil, apperr.Validation(fmt.Sprintf("Unsupported export version %d", data.Version))
+	}
+	if data.Event == nil || data.Event.Name == "" {
+		return nil, apperr.Validation("Export file has no event")
+	}
+
+	// Export files may have been edited by hand, so descriptions are
+	// cleaned like those typed in the admin panel
+	event, err := tx.CreateEvent(ctx, &sqlc.CreateEventParams{
+		Name:        data.Event.Name,
+		Description: sql.NullString{String: sanitize.Markdown(data.Event.Description.String), Valid: data.Event.Description.Valid},
+		Date:        data.Event.Date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The exported slug is kept unless another event here has it
+	if event, err = slug.Assign(ctx, tx, event, data.Event.Slug.String); err != nil {
+		return nil, err
+	}
+	if err := audit.Record(ctx, tx, audit.EventCreated, event.ID, nil, event); err != nil {
+		return nil, err
+	}
+	creator, err := organizeNewEvent(ctx, tx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.SetEventWaitlistAutoPromote(ctx, &sqlc.SetEventWaitlistAutoPromoteParams{
+		ID:                  event.ID,
+		WaitlistAutoPromote: data.Event.WaitlistAutoPromote,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tx.SetEventPaidEntries(ctx, &sqlc.SetEventPaidEntriesParams{
+		ID:             event.ID,
+		MaxPaidEntries: data.Event.MaxPaidEntries,
+		EntryPrice:     data.Event.EntryPrice,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tx.SetEventTicketPrice(ctx, &sqlc.SetEventTicketPriceParams{
+		ID:          event.ID,
+		TicketPrice: data.Event.TicketPrice,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tx.SetEventShareBonus(ctx, &sqlc.SetEventShareBonusParams{
+		ID:                  event.ID,
+		ShareClicksRequired: data.Event.ShareClicksRequired,
+		ShareBonus:          data.Event.ShareBonus,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tx.SetEventShowWinners(ctx, &sqlc.SetEventShowWinnersParams{
+		ID:          event.ID,
+		ShowWinners: data.Event.ShowWinners,
+	}); err != nil {
+		return nil, err
+	}
+	if data.Event.Capacity > 0 {
+		if _, err := tx.SetEventCapacity(ctx, &sqlc.SetEventCapacityParams{
+			ID:       event.ID,
+			Capacity: min(data.Event.Capacity, maxEventCapacity),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if data.Event.RegistrationOpensAt.Valid || data.Event.RegistrationClosesAt.Valid {
+		if _, err := tx.SetEventRegistrationWindow(ctx, &sqlc.SetEventRegistrationWindowParams{
+			ID:                   event.ID,
+			RegistrationOpensAt:  data.Event.RegistrationOpensAt,
+			RegistrationClosesAt: data.Event.RegistrationClosesAt,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if len(data.Event.Categories) > 0 {
+		if _, err := tx.SetEventCategories(ctx, &sqlc.SetEventCategoriesParams{
+			ID:         event.ID,
+			Categories: normalizeTags(slices.Clone(data.Event.Categories)),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := importEventImage(ctx, tx, event.ID, data.Image); err != nil {
+		return nil, err
+	}
+	// Files exported before seats existed have no seat assignment
+	if seating.ValidAssignment(data.Event.SeatAssignment) {
+		if _, err := tx.SetEventSeatAssignment(ctx, &sqlc.SetEventSeatAssignmentParams{
+			ID:             event.ID,
+			SeatAssignment: data.Event.SeatAssignment,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	ruleIDs := make(map[int64]int64, len(data.Rules))
+	for _, rule := range data.Rules {
+		created, err := tx.CreateEntryRule(ctx, &sqlc.CreateEntryRuleParams{
+			EventID:          event.ID,
+			Name:             rule.Name,
+			MaxTicket:        rule.MaxTicket,
+			RegisteredBefore: rule.RegisteredBefore,
+			Bonus:            rule.Bonus,
+		})
+		if err != nil {
+			return nil, err
+		}
+		ruleIDs[rule.ID] = created.ID
+	}
+
+	// Admin accounts differ between instances, so organizers come without
+	// theirs. The admin importing was added above already
+	for _, organizer := range data.Organizers {
+		if organizer.Name == creator {
+			continue
+		}
+		if _, err := tx.CreateEventOrganizer(ctx, &sqlc.CreateEventOrganizerParams{
+			EventID:        event.ID,
+			Name:           organizer.Name,
+			Username:       organizer.Username,
+			Responsibility: organizer.Responsibility,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, translation := range data.Translations {
+		if !i18n.Supported(translation.Language) || translation.Language == i18n.Default {
+			continue
+		}
+		if _, err := tx.UpsertEventTranslation(ctx, &sqlc.UpsertEventTranslationParams{
+			EventID:     event.ID,
+			Language:    translation.Language,
+			Name:        translation.Name,
+			Description: sanitize.Markdown(translation.Description),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	typeIDs := make(map[int64]int64, len(data.TicketTypes))
+	for _, ticketType := range data.TicketTypes {
+		created, err := tx.CreateTicketType(ctx, &sqlc.CreateTicketTypeParams{
+			EventID:  event.ID,
+			Name:     ticketType.Name,
+			Price:    ticketType.Price,
+			Capacity: ticketType.Capacity,
+			Weight:   ticketType.Weight,
+		})
+		if err != nil {
+			return nil, err
+		}
+		typeIDs[ticketType.ID] = created.ID
+	}
+
+	userIDs := make(map[int64]int64, len(data.Users))
+	for _, user := range data.Users {
+		applied := make([]int64, 0, len(user.AppliedRuleIds))
+		for _, id := range user.AppliedRuleIds {
+			if newID, ok := ruleIDs[id]; ok {
+				applied = append(applied, newID)
+			}
+		}
+		tags := user.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		var ticketTypeID sql.NullInt64
+		if newID, ok := typeIDs[user.TicketTypeID.Int64]; ok && user.TicketTypeID.Valid {
+			ticketTypeID = sql.NullInt64{Int64: newID, Valid: true}
+		}
+
+		created, err := tx.ImportUser(ctx, &sqlc.ImportUserParams{
+			Name:                  user.Name,
+			Username:              user.Username,
+			TgID:                  user.TgID,
+			EventID:               event.ID,
+			CreatedAt:             user.CreatedAt,
+			N:                     user.N,
+			TicketNumber:          user.TicketNumber,
+			CheckedInAt:           user.CheckedInAt,
+			CheckInCode:           user.CheckInCode,
+			Phone:                 user.Phone,
+			AttendanceConfirmedAt: user.AttendanceConfirmedAt,
+			PaidEntries:           user.PaidEntries,
+			BonusEntries:          user.BonusEntries,
+			AppliedRuleIds:        applied,
+			ShareEntries:          user.ShareEntries,
+			ShareBonusGrantedAt:   user.ShareBonusGrantedAt,
+			PromoEntries:          user.PromoEntries,
+			TicketTypeID:          ticketTypeID,
+			FlagReason:            user.FlagReason,
+			ReviewedAt:            user.ReviewedAt,
+			Tags:                  tags,
+			Notes:                 user.Notes,
+			BotBlockedAt:          user.BotBlockedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		userIDs[user.ID] = created.ID
+	}
+	if err := tx.SyncLastTicketNumber(ctx, event.ID); err != nil {
+		return nil, err
+	}
+
+	if err := importSeats(ctx, tx, event.ID, data.Seats, userIDs); err != nil {
+		return nil, err
+	}
+
+	for _, draw := range data.Draws {
+		if draw.Draws == nil {
+			return nil, apperr.Validation("Export file has an empty draw")
+		}
+		created, err := tx.ImportDraw(ctx, &sqlc.ImportDrawParams{
+			EventID:      event.ID,
+			WinnersCount: draw.WinnersCount,
+			Seed:         draw.Seed,
+			CreatedAt:    draw.CreatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, winner := range draw.Winners {
+			userID, ok := userIDs[winner.UserID]
+			if !ok {
+				return nil, apperr.Validation(fmt.Sprintf("Draw winner %d is not a participant in the export file", winner.UserID))
+			}
+			if err := tx.CreateDrawWinner(ctx, &sqlc.CreateDrawWinnerParams{
+				DrawID:   created.ID,
+				UserID:   userID,
+				Position: winner.Position,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return event, nil
+}
+
+// importSeats recreates the exported seats with the participants sitting
+// in them.
+func importSeats(ctx context.Context, tx store.Store, eventID int64, seats []sqlc.Seats, userIDs map[int64]int64) error {
+	if len(seats) == 0 {
+		return nil
+	}
+	labels := make([]string, 0, len(seats))
+	for _, seat := range seats {
+		labels = append(labels, seat.Label)
+	}
+	if _, err := tx.CreateSeats(ctx, &sqlc.CreateSeatsParams{EventID: eventID, Labels: labels}); err != nil {
+		return err
+	}
+
+	created, err := tx.GetSeatsByEventID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	seatIDs := make(map[string]int64, len(created))
+	for _, seat := range created {
+		seatIDs[seat.Seats.Label] = seat.Seats.ID
+	}
+	for _, seat := range seats {
+		userID, ok := userIDs[seat.UserID.Int64]
+		if !ok || !seat.UserID.Valid {
+			continue
+		}
+		if err := tx.SetSeatUser(ctx, &sqlc.SetSeatUserParams{
+			ID:     seatIDs[seat.Label],
+			UserID: sql.NullInt64{Int64: userID, Valid: true},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleExportEvent downloads the event as a JSON file that
+// handleImportEvent can load on another instance.
+func (s *Service) handleExportEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	data, err := exportEvent(r.Context(), s.store, eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to export event", apperr.FromDB(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d.json"`, eventID))
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to write event export", slog.Any("error", err))
+	}
+}
+
+func (s *Service) handleImportEvent(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			err = apperr.Validation("Choose an export file")
+		} else {
+			err = validate.BodyError(err)
+		}
+		s.renderError(w, r, "Invalid event import", err)
+		return
+	}
+	defer file.Close()
+
+	var data eventExport
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		s.renderError(w, r, "Invalid event import", apperr.Validation("Invalid export file"))
+		return
+	}
+
+	var event *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		event, err = importEvent(r.Context(), tx, &data)
+		return err
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to import event", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Imported event",
+		slog.Int64("event_id", event.ID), slog.Int("users", len(data.Users)), slog.Int("draws", len(data.Draws)))
+
+	w.Header().Set("HX-Redirect", "/admin/events/"+strconv.FormatInt(event.ID, 10))
+}
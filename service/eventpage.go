@@ -0,0 +1,87 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/capacity"
+	"giveaway-tool/config"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/lifecycle"
+)
+
+// eventPageData is the public page of one event.
+type eventPageData struct {
+	Event      *sqlc.Events
+	Registered int64
+	// PlacesLeft is only set for events with a capacity
+	PlacesLeft int32
+	// BotURL opens the Telegram bot to register for the event, empty if
+	// there is no bot
+	BotURL             string
+	PublicRegistration bool
+	// ShareURL is the page's own address, for sharing it
+	ShareURL string
+	Language string
+}
+
+// siteURL returns the address other sites reach this one at: PUBLIC_URL
+// when set, and the one r was sent to otherwise.
+func (s *Service) siteURL(r *http.Request) string {
+	if s.publicURL != "" {
+		return s.publicURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// eventPageURL returns the address of the event's public page.
+func (s *Service) eventPageURL(r *http.Request, event *sqlc.Events) string {
+	return s.siteURL(r) + "/events/" + event.Slug.String
+}
+
+// handleEventPage renders the public page of the event with the slug,
+// with its description, how many registered and how to register. Drafts
+// have no public page. /events/{id}.ics is routed here too, since the
+// pattern can't have a suffix after the wildcard, and slugs have no dots.
+func (s *Service) handleEventPage(w http.ResponseWriter, r *http.Request) {
+	if id, ok := strings.CutSuffix(r.PathValue("slug"), ".ics"); ok {
+		s.handleEventCalendar(w, r, id)
+		return
+	}
+	event, err := s.store.GetEventBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	if !lifecycle.Public(event) {
+		s.renderError(w, r, "Event is not published", apperr.NotFound("Event not found"))
+		return
+	}
+
+	data := eventPageData{
+		BotURL:             s.botStartURL(event.ID),
+		PublicRegistration: config.FlagEnabled(config.FlagPublicRegistration),
+		ShareURL:           s.eventPageURL(r, event),
+		Language:           language(w, r),
+	}
+	if data.Registered, err = s.store.CountUsersByEventID(r.Context(), event.ID); err != nil {
+		s.renderError(w, r, "Failed to count users", apperr.FromDB(err))
+		return
+	}
+	if event.Capacity > 0 {
+		if data.PlacesLeft, err = capacity.Left(r.Context(), s.store, event); err != nil {
+			s.renderError(w, r, "Failed to count places", apperr.FromDB(err))
+			return
+		}
+	}
+	if data.Event, err = s.translateEvent(r.Context(), event, data.Language); err != nil {
+		s.renderError(w, r, "Failed to get translation", apperr.FromDB(err))
+		return
+	}
+	s.runTemplate(w, r, "event_page", data)
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/slug"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+// saveEventTemplate stores the event's setup as a template. Rules bound
+// to a registration deadline keep the deadline's distance from the event
+// date.
+func saveEventTemplate(ctx context.Context, tx store.Store, eventID int64, name string) (*sqlc.EventTemplates, error) {
+	event, err := tx.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := tx.GetEntryRulesByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := tx.CreateEventTemplate(ctx, &sqlc.CreateEventTemplateParams{
+		Name:                name,
+		Description:         event.Description,
+		WaitlistAutoPromote: event.WaitlistAutoPromote,
+		MaxPaidEntries:      event.MaxPaidEntries,
+		EntryPrice:          event.EntryPrice,
+		ShareClicksRequired: event.ShareClicksRequired,
+		ShareBonus:          event.ShareBonus,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		arg := &sqlc.CreateEventTemplateRuleParams{
+			TemplateID: tmpl.ID,
+			Name:       rule.Name,
+			MaxTicket:  rule.MaxTicket,
+			Bonus:      rule.Bonus,
+		}
+		if rule.RegisteredBefore.Valid {
+			arg.SecondsBeforeEvent = sql.NullInt32{
+				Int32: int32(event.Date.Sub(rule.RegisteredBefore.Time).Seconds()),
+				Valid: true,
+			}
+		}
+		if err := tx.CreateEventTemplateRule(ctx, arg); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// createEventFromTemplate creates an event on date with the template's
+// setup.
+func createEventFromTemplate(ctx context.Context, tx store.Store, templateID int64, name string, date time.Time) (*sqlc.Events, error) {
+	tmpl, err := tx.GetEventTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := tx.GetEventTemplateRules(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := tx.CreateEvent(ctx, &sqlc.CreateEventParams{
+		Name:        name,
+		Description: tmpl.Description,
+		Date:        date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if event, err = slug.Assign(ctx, tx, event, ""); err != nil {
+		return nil, err
+	}
+	if err := audit.Record(ctx, tx, audit.EventCreated, event.ID, nil, event); err != nil {
+		return nil, err
+	}
+	if _, err := organizeNewEvent(ctx, tx, event.ID); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.SetEventWaitlistAutoPromote(ctx, &sqlc.SetEventWaitlistAutoPromoteParams{
+		ID:                  event.ID,
+		WaitlistAutoPromote: tmpl.WaitlistAutoPromote,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tx.SetEventPaidEntries(ctx, &sqlc.SetEventPaidEntriesParams{
+		ID:             event.ID,
+		MaxPaidEntries: tmpl.MaxPaidEntries,
+		EntryPrice:     tmpl.EntryPrice,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tx.SetEventShareBonus(ctx, &sqlc.SetEventShareBonusParams{
+		ID:                  event.ID,
+		ShareClicksRequired: tmpl.ShareClicksRequired,
+		ShareBonus:          tmpl.ShareBonus,
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		arg := &sqlc.CreateEntryRuleParams{
+			EventID:   event.ID,
+			Name:      rule.Name,
+			MaxTicket: rule.MaxTicket,
+			Bonus:     rule.Bonus,
+		}
+		if rule.SecondsBeforeEvent.Valid {
+			arg.RegisteredBefore = sql.NullTime{
+				Time:  date.Add(-time.Duration(rule.SecondsBeforeEvent.Int32) * time.Second),
+				Valid: true,
+			}
+		}
+		if _, err := tx.CreateEntryRule(ctx, arg); err != nil {
+			return nil, err
+		}
+	}
+	return event, nil
+}
+
+func (s *Service) handleSaveEventTemplate(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	name := form.RequiredText("name", maxNameLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid template", err)
+		return
+	}
+
+	var tmpl *sqlc.EventTemplates
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		tmpl, err = saveEventTemplate(r.Context(), tx, eventID, name)
+		return err
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to save event template", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Saved event template",
+		slog.Int64("event_id", eventID), slog.Int64("template_id", tmpl.ID))
+
+	fmt.Fprintf(w, successHTML, fmt.Sprintf("Шаблон «%s» збережено", tmpl.Name))
+}
+
+func (s *Service) handleEventTemplatesPage(w http.ResponseWriter, r *http.Request) {
+	templates, err := s.store.GetEventTemplates(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get event templates", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_templates", templates)
+}
+
+func (s *Service) handleUpdateEventTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid template ID", apperr.Validation("Invalid template ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	name := form.RequiredText("name", maxNameLength)
+	description := form.RichText("description", maxDescriptionLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid template", err)
+		return
+	}
+
+	if _, err := s.store.UpdateEventTemplate(r.Context(), &sqlc.UpdateEventTemplateParams{
+		ID:          id,
+		Name:        name,
+		Description: sql.NullString{String: description, Valid: description != ""},
+	}); err != nil {
+		s.renderError(w, r, "Failed to update event template", apperr.FromDB(err))
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, "Шаблон оновлено")
+}
+
+func (s *Service) handleDeleteEventTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid template ID", apperr.Validation("Invalid template ID"))
+		return
+	}
+
+	if err := s.store.DeleteEventTemplate(r.Context(), id); err != nil {
+		s.renderError(w, r, "Failed to delete event template", apperr.FromDB(err))
+		return
+	}
+}
+
+func (s *Service) handleCreateEventFromTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid template ID", apperr.Validation("Invalid template ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	name := form.RequiredText("name", maxNameLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid event", err)
+		return
+	}
+
+	date, err := time.Parse("2006-01-02T15:04", r.FormValue("date"))
+	if err != nil {
+		s.renderError(w, r, "Invalid event", apperr.Validation("Invalid date"))
+		return
+	}
+
+	var event *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		event, err = createEventFromTemplate(r.Context(), tx, id, name, date)
+		return err
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to create event from template", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Created event from template",
+		slog.Int64("template_id", id), slog.Int64("event_id", event.ID))
+
+	w.Header().Set("HX-Redirect", "/admin/events/"+strconv.FormatInt(event.ID, 10))
+}
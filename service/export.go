@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+	"giveaway-tool/xlsx"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// participantExport holds what the participant exports show besides the
+// participants' own fields.
+type participantExport struct {
+	typeNames  map[int64]string
+	seatLabels map[int64]string
+	// places are the places participants took in the event's draws,
+	// newest draw first
+	places map[int64][]int32
+}
+
+func (s *Service) participantExport(ctx context.Context, eventID int64) (*participantExport, error) {
+	ticketTypes, err := s.store.GetTicketTypesByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	seats, err := s.store.GetSeatsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	draws, err := s.store.GetDrawsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &participantExport{
+		typeNames:  make(map[int64]string, len(ticketTypes)),
+		seatLabels: seatLabels(seats),
+		places:     make(map[int64][]int32),
+	}
+	for _, ticketType := range ticketTypes {
+		export.typeNames[ticketType.ID] = ticketType.Name
+	}
+	for _, draw := range draws {
+		winners, err := s.store.GetDrawWinners(ctx, draw.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, winner := range winners {
+			export.places[winner.Users.ID] = append(export.places[winner.Users.ID], winner.Position)
+		}
+	}
+	return export, nil
+}
+
+// exportFilter returns the tag filter of an export, which keeps only the
+// participants with the tag given by the tag query parameter if it is set.
+func exportFilter(r *http.Request) tagFilter {
+	var filter tagFilter
+	if tag := strings.ToLower(r.URL.Query().Get("tag")); tag != "" {
+		filter.Include = []string{tag}
+	}
+	return filter
+}
+
+// handleExportParticipants streams the participants of an event as CSV,
+// only those with the tag given by the tag query parameter if it is set.
+func (s *Service) handleExportParticipants(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	if _, err := s.store.GetEventByID(r.Context(), eventID); err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	export, err := s.participantExport(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to prepare export", apperr.FromDB(err))
+		return
+	}
+
+	filter := exportFilter(r)
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-participants.csv"`, eventID))
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"id", "ticket_number", "check_in_code", "name", "username", "phone", "tg_id", "ticket_type", "seat", "votes", "paid_entries", "bonus_entries", "share_entries", "promo_entries", "registered_at", "attendance_confirmed_at", "checked_in_at", "bot_blocked_at", "tags", "notes"})
+
+	rows := 0
+	for user, err := range store.EventUsers(r.Context(), s.store, eventID, store.DefaultPageSize) {
+		if err != nil {
+			// The header is already sent, so the client only sees a truncated file
+			logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to export participants",
+				slog.Int("rows", rows), slog.Any("error", err))
+			break
+		}
+		if !filter.match(user.Tags) {
+			continue
+		}
+
+		tgID := ""
+		if user.TgID.Valid {
+			tgID = strconv.FormatInt(user.TgID.Int64, 10)
+		}
+		confirmedAt := ""
+		if user.AttendanceConfirmedAt.Valid {
+			confirmedAt = user.AttendanceConfirmedAt.Time.Format(time.RFC3339)
+		}
+		checkedInAt := ""
+		if user.CheckedInAt.Valid {
+			checkedInAt = user.CheckedInAt.Time.Format(time.RFC3339)
+		}
+		blockedAt := ""
+		if user.BotBlockedAt.Valid {
+			blockedAt = user.BotBlockedAt.Time.Format(time.RFC3339)
+		}
+		out.Write([]string{
+			strconv.FormatInt(user.ID, 10),
+			strconv.Itoa(int(user.TicketNumber)),
+			user.CheckInCode,
+			csvSafe(user.Name),
+			csvSafe(user.Username),
+			csvSafe(user.Phone),
+			tgID,
+			csvSafe(export.typeNames[user.TicketTypeID.Int64]),
+			csvSafe(export.seatLabels[user.ID]),
+			strconv.Itoa(int(user.N)),
+			strconv.Itoa(int(user.PaidEntries)),
+			strconv.Itoa(int(user.BonusEntries)),
+			strconv.Itoa(int(user.ShareEntries)),
+			strconv.Itoa(int(user.PromoEntries)),
+			user.CreatedAt.Time.Format(time.RFC3339),
+			confirmedAt,
+			checkedInAt,
+			blockedAt,
+			csvSafe(strings.Join(user.Tags, ", ")),
+			csvSafe(user.Notes),
+		})
+
+		if rows++; rows%store.DefaultPageSize == 0 {
+			out.Flush()
+		}
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to write CSV", slog.Any("error", err))
+	}
+}
+
+// csvSafe neutralises participant-supplied values that spreadsheet apps
+// would otherwise evaluate as formulas.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// xlsxColumns are the columns of the Excel export: those of the CSV export
+// and the places participants won.
+var xlsxColumns = []string{"id", "ticket_number", "check_in_code", "name", "username", "phone", "tg_id", "ticket_type", "seat", "votes", "paid_entries", "bonus_entries", "share_entries", "promo_entries", "registered_at", "attendance_confirmed_at", "checked_in_at", "bot_blocked_at", "tags", "notes", "won_places"}
+
+// writeEventSheet adds a sheet with the event's participants to out,
+// highlighting the winners of its draws.
+func (s *Service) writeEventSheet(ctx context.Context, out *xlsx.Writer, event *sqlc.Events, export *participantExport, filter tagFilter) error {
+	if err := out.AddSheet(event.Name); err != nil {
+		return err
+	}
+	header := make([]xlsx.Cell, len(xlsxColumns))
+	for i, column := range xlsxColumns {
+		header[i] = xlsx.Text(column)
+	}
+	if err := out.WriteRow(xlsx.Header, header...); err != nil {
+		return err
+	}
+
+	for user, err := range store.EventUsers(ctx, s.store, event.ID, store.DefaultPageSize) {
+		if err != nil {
+			return err
+		}
+		if !filter.match(user.Tags) {
+			continue
+		}
+
+		tgID := xlsx.Cell{}
+		if user.TgID.Valid {
+			tgID = xlsx.Number(user.TgID.Int64)
+		}
+		places := export.places[user.ID]
+		won := make([]string, len(places))
+		for i, place := range places {
+			won[i] = strconv.Itoa(int(place))
+		}
+		style := xlsx.Plain
+		if len(places) > 0 {
+			style = xlsx.Highlight
+		}
+
+		if err := out.WriteRow(style,
+			xlsx.Number(user.ID),
+			xlsx.Number(int64(user.TicketNumber)),
+			xlsx.Text(user.CheckInCode),
+			xlsx.Text(user.Name),
+			xlsx.Text(user.Username),
+			xlsx.Text(user.Phone),
+			tgID,
+			xlsx.Text(export.typeNames[user.TicketTypeID.Int64]),
+			xlsx.Text(export.seatLabels[user.ID]),
+			xlsx.Number(int64(user.N)),
+			xlsx.Number(int64(user.PaidEntries)),
+			xlsx.Number(int64(user.BonusEntries)),
+			xlsx.Number(int64(user.ShareEntries)),
+			xlsx.Number(int64(user.PromoEntries)),
+			xlsx.Time(user.CreatedAt.Time),
+			xlsx.Time(user.AttendanceConfirmedAt.Time),
+			xlsx.Time(user.CheckedInAt.Time),
+			xlsx.Time(user.BotBlockedAt.Time),
+			xlsx.Text(strings.Join(user.Tags, ", ")),
+			xlsx.Text(user.Notes),
+			xlsx.Text(strings.Join(won, ", ")),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleExportParticipantsXLSX streams the participants of an event as an
+// Excel workbook, filtered by tag like the CSV export.
+func (s *Service) handleExportParticipantsXLSX(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	export, err := s.participantExport(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to prepare export", apperr.FromDB(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", xlsxContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-participants.xlsx"`, eventID))
+
+	out := xlsx.NewWriter(w)
+	if err := s.writeEventSheet(r.Context(), out, event, export, exportFilter(r)); err != nil {
+		// The header is already sent, so the client only gets a broken file
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to export participants", slog.Any("error", err))
+		return
+	}
+	if err := out.Close(); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to write workbook", slog.Any("error", err))
+	}
+}
+
+// handleExportEventsXLSX streams the participants of the events listed on
+// the dashboard, active or archived, as one workbook with a sheet per
+// event.
+func (s *Service) handleExportEventsXLSX(w http.ResponseWriter, r *http.Request) {
+	events, err := s.store.GetEvents(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get events", apperr.FromDB(err))
+		return
+	}
+	archived := r.URL.Query().Get("archived") == "true"
+	events = slices.DeleteFunc(organizedEvents(r.Context(), events), func(event *sqlc.Events) bool { return event.ArchivedAt.Valid != archived })
+
+	filename := "events.xlsx"
+	if archived {
+		filename = "archived-events.xlsx"
+	}
+	w.Header().Set("Content-Type", xlsxContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	out := xlsx.NewWriter(w)
+	for _, event := range events {
+		export, err := s.participantExport(r.Context(), event.ID)
+		if err == nil {
+			err = s.writeEventSheet(r.Context(), out, event, export, tagFilter{})
+		}
+		if err != nil {
+			// The header is already sent, so the client only gets a broken file
+			logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to export events",
+				slog.Int64("event_id", event.ID), slog.Any("error", err))
+			return
+		}
+	}
+	if err := out.Close(); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to write workbook", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/ical"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/markdown"
+)
+
+// calendarID identifies the app in the calendars it makes.
+const calendarID = "-//FITKI//Event Manager//UK"
+
+// calendarRefreshInterval is how often calendar apps are asked to fetch
+// the feed again. Google Calendar takes hours whatever it is told.
+const calendarRefreshInterval = time.Hour
+
+// calendarEvent turns the event into what calendar apps show. Event dates
+// are wall-clock times in calendarTimeZone, as for the Google Calendar.
+func (s *Service) calendarEvent(r *http.Request, loc *time.Location, event *sqlc.Events) ical.Event {
+	start := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(),
+		event.Date.Hour(), event.Date.Minute(), event.Date.Second(), 0, loc)
+	host := "localhost"
+	if u, err := url.Parse(s.siteURL(r)); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	return ical.Event{
+		UID:         "event-" + strconv.FormatInt(event.ID, 10) + "@" + host,
+		Sequence:    event.Version,
+		Summary:     event.Name,
+		Description: event.Description.String,
+		URL:         s.eventPageURL(r, event),
+		Start:       start,
+		End:         start.Add(calendarEventLength),
+	}
+}
+
+// writeCalendar writes cal with the events added.
+func (s *Service) writeCalendar(w http.ResponseWriter, r *http.Request, cal ical.Calendar, events []*sqlc.Events) {
+	loc, err := time.LoadLocation(calendarTimeZone())
+	if err != nil {
+		s.renderError(w, r, "Invalid calendar time zone", err)
+		return
+	}
+	cal.ID = calendarID
+	for _, event := range events {
+		cal.Events = append(cal.Events, s.calendarEvent(r, loc, event))
+	}
+	w.Header().Set("Content-Type", ical.ContentType)
+	ical.Write(w, cal)
+}
+
+// handleEventsCalendar returns the upcoming published events as a calendar
+// students can subscribe to in Google or Apple Calendar.
+func (s *Service) handleEventsCalendar(w http.ResponseWriter, r *http.Request) {
+	events, err := s.store.GetEvents(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get events", apperr.FromDB(err))
+		return
+	}
+	// The list may be shared with the store cache, so filter a copy
+	events = slices.DeleteFunc(slices.Clone(events), func(event *sqlc.Events) bool {
+		return event.ArchivedAt.Valid || !lifecycle.Public(event)
+	})
+	s.writeCalendar(w, r, ical.Calendar{Name: "Події FITKI", RefreshInterval: calendarRefreshInterval}, events)
+}
+
+// handleEventCalendar returns a calendar with just the event with the ID,
+// for adding it to a calendar once.
+func (s *Service) handleEventCalendar(w http.ResponseWriter, r *http.Request, id string) {
+	eventID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	if !lifecycle.Public(event) {
+		s.renderError(w, r, "Event is not published", apperr.NotFound("Event not found"))
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d.ics"`, eventID))
+	s.writeCalendar(w, r, ical.Calendar{Name: event.Name}, []*sqlc.Events{event})
+}
+
+// recentEventsPeriod is how long past events stay in the Atom feed after
+// they were created, so announcements of one-off events aren't missed.
+const recentEventsPeriod = 30 * 24 * time.Hour
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Link      atomLink    `xml:"link"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// atomEntry turns the event into a feed entry, with the date and the
+// rendered description as its content.
+func (s *Service) atomEntry(r *http.Request, event *sqlc.Events) atomEntry {
+	created := event.Date
+	if event.CreatedAt.Valid {
+		created = event.CreatedAt.Time
+	}
+	content := "<p>" + event.Date.Format("02.01.2006 15:04") + "</p>"
+	if event.Description.Valid {
+		content += string(markdown.HTML(event.Description.String))
+	}
+	return atomEntry{
+		ID:        s.siteURL(r) + "/events/" + strconv.FormatInt(event.ID, 10),
+		Title:     event.Name,
+		Published: created.UTC().Format(time.RFC3339),
+		Updated:   created.UTC().Format(time.RFC3339),
+		Link:      atomLink{Rel: "alternate", Type: "text/html", Href: s.eventPageURL(r, event)},
+		Content:   atomContent{Type: "html", Body: content},
+	}
+}
+
+// handleEventsFeed returns an Atom feed of the upcoming and recently created
+// published events, newest first, for channels that aggregate the club's
+// announcements.
+func (s *Service) handleEventsFeed(w http.ResponseWriter, r *http.Request) {
+	events, err := s.store.GetEvents(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get events", apperr.FromDB(err))
+		return
+	}
+	since := time.Now().Add(-recentEventsPeriod)
+
+	site := s.siteURL(r)
+	feed := atomFeed{
+		ID:    site + "/events.atom",
+		Title: "Події FITKI",
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: site + "/events.atom"},
+			{Rel: "alternate", Type: "text/html", Href: site + "/"},
+		},
+	}
+	// Events come newest first
+	for _, event := range events {
+		recent := event.CreatedAt.Valid && event.CreatedAt.Time.After(since)
+		if !lifecycle.Public(event) || event.ArchivedAt.Valid && !recent {
+			continue
+		}
+		entry := s.atomEntry(r, event)
+		feed.Entries = append(feed.Entries, entry)
+		feed.Updated = max(feed.Updated, entry.Updated)
+	}
+	if feed.Updated == "" {
+		feed.Updated = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(feed)
+}
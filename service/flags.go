@@ -0,0 +1,44 @@
+package service
+
+import (
+	"net/http"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/config"
+	"giveaway-tool/store"
+)
+
+func (s *Service) handleFlagsPage(w http.ResponseWriter, r *http.Request) {
+	s.runTemplate(w, r, "admin_flags", config.Flags())
+}
+
+func (s *Service) handleSetFlag(w http.ResponseWriter, r *http.Request) {
+	flag := config.Flag(r.PathValue("name"))
+	if !config.IsKnownFlag(flag) {
+		s.renderError(w, r, "Unknown feature flag", apperr.NotFound("Unknown feature flag"))
+		return
+	}
+
+	enabled := r.FormValue("enabled") == "true"
+	err := s.store.InTx(r.Context(), func(tx store.Store) error {
+		if err := audit.Record(r.Context(), tx, audit.FlagSet, 0,
+			map[string]bool{string(flag): config.FlagEnabled(flag)}, map[string]bool{string(flag): enabled}); err != nil {
+			return err
+		}
+		return config.SaveFlag(r.Context(), tx, flag, enabled)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to set feature flag", apperr.FromDB(err))
+		return
+	}
+	// Only once saved, so a rolled back change never takes effect
+	config.ApplyFlag(flag, enabled)
+
+	for _, info := range config.Flags() {
+		if info.Name == flag {
+			s.runTemplate(w, r, "admin_flag_row", info)
+			return
+		}
+	}
+}
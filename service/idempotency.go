@@ -0,0 +1,187 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/validate"
+)
+
+const (
+	// idempotencyWindow is how long a stored response is replayed for
+	// requests retried with the same Idempotency-Key.
+	idempotencyWindow = 24 * time.Hour
+
+	// idempotencyClaimTimeout is how long a request holds its key before a
+	// retry may take it over, for requests that never finished, such as
+	// when the server stopped.
+	idempotencyClaimTimeout = time.Minute
+
+	maxIdempotencyKeyLength = 255
+)
+
+// responseCapture forwards the response to the client while keeping a copy
+// so it can be stored for replay.
+type responseCapture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(status int) {
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *responseCapture) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	c.body.Write(b)
+	return c.ResponseWriter.Write(b)
+}
+
+func (c *responseCapture) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// idempotent replays the stored response for requests carrying an
+// Idempotency-Key that was already used for the same endpoint and API token
+// within idempotencyWindow. The key is claimed in the store while the
+// request runs, so a retry arriving meanwhile, even on another instance,
+// is turned away instead of creating a duplicate. Requests without the
+// header are passed through.
+func (s *Service) idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			s.renderError(w, r, "Invalid idempotency key", apperr.Validation("Idempotency-Key is too long"))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			s.renderError(w, r, "Failed to read request body", validate.BodyError(err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are the client's, so tokens can't see or block each other's
+		scope := r.Method + " " + r.URL.Path
+		if tokenID := currentTokenID(r.Context()); tokenID != 0 {
+			scope = "token:" + strconv.FormatInt(tokenID, 10) + " " + scope
+		}
+		hash := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(hash[:])
+
+		ctx := logging.With(r.Context(), slog.String("idempotency_key", key))
+		r = r.WithContext(ctx)
+
+		now := time.Now()
+		claimed, err := s.store.ClaimIdempotencyKey(ctx, &sqlc.ClaimIdempotencyKeyParams{
+			Key:           key,
+			Scope:         scope,
+			RequestHash:   requestHash,
+			StoredBefore:  now.Add(-idempotencyWindow),
+			ClaimedBefore: now.Add(-idempotencyClaimTimeout),
+		})
+		if err != nil {
+			s.renderError(w, r, "Failed to claim idempotency key", apperr.FromDB(err))
+			return
+		}
+		if claimed == 0 {
+			s.replay(w, r, scope, key, requestHash)
+			return
+		}
+
+		capture := &responseCapture{ResponseWriter: w}
+		saved := false
+		defer func() {
+			if saved {
+				return
+			}
+			// The claim is dropped even if the handler panicked, so the
+			// request can be retried right away
+			release := context.WithoutCancel(ctx)
+			if err := s.store.ReleaseIdempotencyKey(release, &sqlc.ReleaseIdempotencyKeyParams{
+				Scope: scope,
+				Key:   key,
+			}); err != nil {
+				logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to release idempotency key", slog.Any("error", err))
+			}
+		}()
+		next.ServeHTTP(capture, r)
+
+		// Only successful responses are stored, so a request that failed
+		// validation can be corrected and resubmitted with the same key
+		if capture.status < 200 || capture.status >= 300 {
+			return
+		}
+
+		contentType := w.Header().Get("Content-Type")
+		if contentType == "" {
+			contentType = http.DetectContentType(capture.body.Bytes())
+		}
+
+		if err := s.store.SaveIdempotencyKey(ctx, &sqlc.SaveIdempotencyKeyParams{
+			Key:         key,
+			Scope:       scope,
+			RequestHash: requestHash,
+			StatusCode:  int32(capture.status),
+			ContentType: contentType,
+			Body:        capture.body.Bytes(),
+		}); err != nil {
+			logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to save idempotency key", slog.Any("error", err))
+			return
+		}
+		saved = true
+	})
+}
+
+// replay answers a request whose key is taken with the stored response, or
+// with a conflict if the request holding the key is still running.
+func (s *Service) replay(w http.ResponseWriter, r *http.Request, scope, key, requestHash string) {
+	ctx := r.Context()
+	stored, err := s.store.GetIdempotencyKey(ctx, &sqlc.GetIdempotencyKeyParams{
+		Scope: scope,
+		Key:   key,
+		Since: time.Now().Add(-idempotencyWindow),
+	})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// The request holding the key failed since it was claimed
+		s.renderError(w, r, "Idempotency key in use", apperr.Conflict("A request with this Idempotency-Key is in progress, retry it later"))
+		return
+	case err != nil:
+		s.renderError(w, r, "Failed to look up idempotency key", apperr.FromDB(err))
+		return
+	case stored.RequestHash != requestHash:
+		s.renderError(w, r, "Idempotency key reused", apperr.Conflict("Idempotency-Key was already used for a different request"))
+		return
+	case stored.StatusCode == 0:
+		s.renderError(w, r, "Idempotency key in use", apperr.Conflict("A request with this Idempotency-Key is in progress, retry it later"))
+		return
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "Replaying stored response")
+	w.Header().Set("Content-Type", stored.ContentType)
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(int(stored.StatusCode))
+	w.Write(stored.Body)
+}
@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"giveaway-tool/config"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/store/memory"
+)
+
+func TestIdempotentRegistration(t *testing.T) {
+	ctx := context.Background()
+	st := memory.New()
+	server := newServer(t, st)
+	if err := config.SetFlag(ctx, st, config.FlagPublicRegistration, true); err != nil {
+		t.Fatal(err)
+	}
+	event := newEvent(t, st, lifecycle.RegistrationOpen, 0)
+	path := fmt.Sprintf("/api/v1/events/%d/registrations", event.ID)
+
+	// A request that never finished still holds its key
+	if _, err := st.ClaimIdempotencyKey(ctx, &sqlc.ClaimIdempotencyKeyParams{
+		Key:           "running",
+		Scope:         "POST " + path,
+		RequestHash:   "hash",
+		StoredBefore:  time.Now().Add(-time.Hour),
+		ClaimedBefore: time.Now().Add(-time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := []struct {
+		name     string
+		key      string
+		body     string
+		want     int
+		replayed bool
+	}{
+		{name: "first", key: "a", body: `{"name":"Ann"}`, want: http.StatusCreated},
+		{name: "retry", key: "a", body: `{"name":"Ann"}`, want: http.StatusCreated, replayed: true},
+		{name: "key reused", key: "a", body: `{"name":"Bob"}`, want: http.StatusConflict},
+		{name: "invalid", key: "b", body: `{"name":""}`, want: http.StatusBadRequest},
+		{name: "corrected", key: "b", body: `{"name":"Cara"}`, want: http.StatusCreated},
+		{name: "in progress", key: "running", body: `{"name":"Dan"}`, want: http.StatusConflict},
+		{name: "no key", body: `{"name":"Ann"}`, want: http.StatusCreated},
+		{name: "key too long", key: strings.Repeat("k", 256), body: `{"name":"Eve"}`, want: http.StatusBadRequest},
+	}
+
+	var first string
+	for _, req := range requests {
+		r, err := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(req.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("Content-Type", "application/json")
+		if req.key != "" {
+			r.Header.Set("Idempotency-Key", req.key)
+		}
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != req.want {
+			t.Errorf("%s: status %d, want %d: %s", req.name, resp.StatusCode, req.want, body)
+		}
+		if got := resp.Header.Get("Idempotent-Replayed") == "true"; got != req.replayed {
+			t.Errorf("%s: replayed = %t, want %t", req.name, got, req.replayed)
+		}
+		switch req.name {
+		case "first":
+			first = string(body)
+		case "retry":
+			if string(body) != first {
+				t.Errorf("retry: body %s, want %s", body, first)
+			}
+		}
+	}
+
+	users, err := st.GetUsersByEventID(ctx, event.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, user := range users {
+		names = append(names, user.Name)
+	}
+	if got, want := strings.Join(names, ","), "Ann,Cara,Ann"; got != want {
+		t.Errorf("participants %s, want %s", got, want)
+	}
+}
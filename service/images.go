@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+// imageTypes are the formats accepted for event images: those browsers
+// show and Telegram accepts as photos.
+var imageTypes = []string{"image/jpeg", "image/png", "image/webp"}
+
+// updateEventImage stores the image uploaded with an event form, or
+// removes the event's image if remove is set. Without either the image is
+// kept as it is.
+func updateEventImage(ctx context.Context, tx store.Store, eventID int64, image []byte, contentType string, remove bool) error {
+	var err error
+	switch {
+	case image != nil:
+		_, err = tx.SetEventImage(ctx, &sqlc.SetEventImageParams{
+			EventID:     eventID,
+			ContentType: contentType,
+			Data:        image,
+		})
+	case remove:
+		_, err = tx.DeleteEventImage(ctx, eventID)
+	}
+	return err
+}
+
+// importEventImage copies an exported image to the event. Export files
+// may have been edited by hand, so the image is checked like an upload.
+func importEventImage(ctx context.Context, tx store.Store, eventID int64, image *sqlc.EventImages) error {
+	if image == nil || len(image.Data) == 0 {
+		return nil
+	}
+	contentType := http.DetectContentType(image.Data)
+	if len(image.Data) > maxImageSize || !slices.Contains(imageTypes, contentType) {
+		return apperr.Validation("Export file has an invalid event image")
+	}
+	return updateEventImage(ctx, tx, eventID, image.Data, contentType, false)
+}
+
+// handleEventImage serves an event's image. Pages link to it with the time
+// of the upload in the query, so a new image gets a new URL.
+func (s *Service) handleEventImage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	image, err := s.store.GetEventImage(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event image", apperr.FromDB(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", image.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(image.Data)
+}
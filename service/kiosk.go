@@ -0,0 +1,205 @@
+package service
+
+import (
+	cryptoRand "crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/router"
+	"giveaway-tool/validate"
+)
+
+const kioskSession = "kiosk"
+
+// kioskEvent returns the event of a kiosk request if the session holds the
+// event's current kiosk token. Rotating or revoking the token in the admin
+// UI therefore locks out every tablet using the old one.
+func (s *Service) kioskEvent(r *http.Request) (*sqlc.Events, error) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return nil, apperr.Validation("Invalid event ID")
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		return nil, apperr.FromDB(err)
+	}
+
+	session, _ := s.sessionStore.Get(r, kioskSession)
+	token, _ := session.Values[kioskTokenKey(eventID)].(string)
+	if !validKioskToken(event, token) {
+		return nil, apperr.Unauthorized("Kiosk access denied")
+	}
+	return event, nil
+}
+
+func kioskTokenKey(eventID int64) string {
+	return "token:" + strconv.FormatInt(eventID, 10)
+}
+
+func validKioskToken(event *sqlc.Events, token string) bool {
+	return event.KioskToken.Valid && token != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(event.KioskToken.String)) == 1
+}
+
+type kioskData struct {
+	Event *sqlc.Events
+	// TicketTypes are picked by volunteers, who take payment at the door
+	TicketTypes []ticketTypeOption
+}
+
+// handleKioskPage shows the on-site registration page. Opening the kiosk
+// link with ?token= stores the token in the session and redirects, so the
+// token doesn't stay visible in the address bar.
+func (s *Service) handleKioskPage(w http.ResponseWriter, r *http.Request) {
+	if token := r.URL.Query().Get("token"); token != "" {
+		eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+			return
+		}
+
+		session, _ := s.sessionStore.Get(r, kioskSession)
+		session.Values[kioskTokenKey(eventID)] = token
+		if err := session.Save(r, w); err != nil {
+			s.renderError(w, r, "Failed to save kiosk session", err)
+			return
+		}
+		http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+		return
+	}
+
+	event, err := s.kioskEvent(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to open kiosk", err)
+		return
+	}
+
+	ticketTypes, err := ticketTypeOptions(r.Context(), s.store, event.ID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get ticket types", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "kiosk", kioskData{Event: event, TicketTypes: ticketTypes})
+}
+
+// handleKioskRegister registers a participant at the entrance and checks
+// them in straight away.
+func (s *Service) handleKioskRegister(w http.ResponseWriter, r *http.Request) {
+	event, err := s.kioskEvent(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to open kiosk", err)
+		return
+	}
+
+	form := validate.NewForm(r)
+	ticketTypeID, _ := form.OptionalInt("ticket_type_id", 1, math.MaxInt)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid registration", err)
+		return
+	}
+
+	user, err := s.register(r.Context(), event, registrationRequest{
+		Name:         r.FormValue("name"),
+		Username:     r.FormValue("username"),
+		TicketTypeID: int64(ticketTypeID),
+	}, consent.SourceKiosk)
+	if err != nil {
+		s.renderError(w, r, "Failed to register participant", err)
+		return
+	}
+
+	user, seat, err := s.checkIn(r.Context(), user)
+	if err != nil {
+		s.renderError(w, r, "Failed to check in participant", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Registered participant at kiosk",
+		slog.Int64("event_id", event.ID), slog.Int64("user_id", user.ID))
+
+	fmt.Fprintf(w, successHTML, fmt.Sprintf("Вітаємо, %s! Твій номер квитка: №%d, код для входу: %s%s", user.Name, user.TicketNumber, user.CheckInCode, seatText(seat)))
+}
+
+func (s *Service) handleKioskCheckIn(w http.ResponseWriter, r *http.Request) {
+	event, err := s.kioskEvent(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to open kiosk", err)
+		return
+	}
+
+	s.checkInAtDoor(w, r, "ip:"+router.ClientIP(r), event.ID)
+}
+
+// checkInAtDoor checks in the participant whose ticket number or check-in
+// code was typed into the ticket field, as volunteers at the entrance do.
+// client is who is checking in, for findByCheckInCode.
+func (s *Service) checkInAtDoor(w http.ResponseWriter, r *http.Request, client string, eventID int64) {
+	var (
+		user *sqlc.Users
+		err  error
+	)
+	ticket := r.FormValue("ticket")
+	if ticketNumber, convErr := strconv.Atoi(ticket); convErr == nil {
+		user, err = s.store.GetUserByTicketNumber(r.Context(), &sqlc.GetUserByTicketNumberParams{
+			EventID:      eventID,
+			TicketNumber: int32(ticketNumber),
+		})
+		err = apperr.FromDB(err)
+	} else {
+		user, err = s.findByCheckInCode(r, client, eventID, ticket)
+	}
+	if err != nil {
+		s.renderError(w, r, "Failed to find participant", err)
+		return
+	}
+
+	if user.CheckedInAt.Valid {
+		fmt.Fprintf(w, successHTML, fmt.Sprintf("%s вже пройшов реєстрацію о %s%s", user.Name, user.CheckedInAt.Time.Format("15:04"), seatText(s.seatLabel(r.Context(), user.ID))))
+		return
+	}
+
+	user, seat, err := s.checkIn(r.Context(), user)
+	if err != nil {
+		s.renderError(w, r, "Failed to check in participant", apperr.FromDB(err))
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, fmt.Sprintf("Вітаємо, %s! Квиток №%d%s", user.Name, user.TicketNumber, seatText(seat)))
+}
+
+// handleRotateKioskToken issues a new kiosk link for the event, or revokes
+// kiosk access when revoke=true.
+func (s *Service) handleRotateKioskToken(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	token := sql.NullString{}
+	if r.FormValue("revoke") != "true" {
+		token = sql.NullString{String: cryptoRand.Text(), Valid: true}
+	}
+
+	event, err := s.store.SetEventKioskToken(r.Context(), &sqlc.SetEventKioskTokenParams{
+		ID:         eventID,
+		KioskToken: token,
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update kiosk token", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_kiosk", event)
+}
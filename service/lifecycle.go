@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+)
+
+// statusLabels name the event statuses in the admin panel.
+var statusLabels = map[string]string{
+	lifecycle.Draft:            "Чернетка",
+	lifecycle.Published:        "Опубліковано",
+	lifecycle.RegistrationOpen: "Реєстрація відкрита",
+	lifecycle.Closed:           "Реєстрацію закрито",
+}
+
+// statusLabel returns the admin panel's name for an event status.
+func statusLabel(status string) string {
+	if label, ok := statusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+// moveEvent moves the event to status like lifecycle.Move, recording the
+// change in the audit log.
+func moveEvent(ctx context.Context, tx store.Store, eventID int64, status string) (*sqlc.Events, error) {
+	before, err := tx.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	event, err := lifecycle.Move(ctx, tx, eventID, status)
+	if err != nil {
+		return nil, err
+	}
+	return event, audit.Record(ctx, tx, audit.EventStatusSet, eventID, before, event)
+}
+
+// setRegistrationWindow sets the event's registration window, recording
+// the change in the audit log.
+func setRegistrationWindow(ctx context.Context, tx store.Store, arg *sqlc.SetEventRegistrationWindowParams) (*sqlc.Events, error) {
+	before, err := tx.GetEventForUpdate(ctx, arg.ID)
+	if err != nil {
+		return nil, err
+	}
+	event, err := tx.SetEventRegistrationWindow(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	return event, audit.Record(ctx, tx, audit.RegistrationWindowSet, arg.ID, before, event)
+}
+
+// handleSetEventStatus moves the event to the next status of its
+// lifecycle, or back a step, and renders its status card again.
+func (s *Service) handleSetEventStatus(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	status := r.FormValue("status")
+	if !lifecycle.Valid(status) {
+		s.renderError(w, r, "Invalid event status", apperr.Validation("Invalid event status"))
+		return
+	}
+
+	var event *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		event, err = moveEvent(r.Context(), tx, eventID, status)
+		return err
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update event status", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Changed event status",
+		slog.Int64("event_id", eventID), slog.String("status", status))
+
+	s.runTemplate(w, r, "admin_event_status", event)
+}
+
+// handleSetRegistrationWindow sets when the event starts and stops taking
+// registrations. Either end may be left empty; extending registration is
+// moving its end later.
+func (s *Service) handleSetRegistrationWindow(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	arg := &sqlc.SetEventRegistrationWindowParams{ID: eventID}
+	for field, t := range map[string]*sql.NullTime{
+		"registration_opens_at":  &arg.RegistrationOpensAt,
+		"registration_closes_at": &arg.RegistrationClosesAt,
+	} {
+		if v := r.FormValue(field); v != "" {
+			parsed, err := time.Parse("2006-01-02T15:04", v)
+			if err != nil {
+				s.renderError(w, r, "Invalid registration window", apperr.Validation("Invalid date"))
+				return
+			}
+			*t = sql.NullTime{Time: parsed, Valid: true}
+		}
+	}
+	if arg.RegistrationOpensAt.Valid && arg.RegistrationClosesAt.Valid && !arg.RegistrationClosesAt.Time.After(arg.RegistrationOpensAt.Time) {
+		s.renderError(w, r, "Invalid registration window", apperr.Validation("Registration must close after it opens"))
+		return
+	}
+
+	var event *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		event, err = setRegistrationWindow(r.Context(), tx, arg)
+		return err
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update registration window", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_registration_window", event)
+}
+
+// handleCloseRegistrationNow ends the event's registration window early.
+// Setting a later end in the window opens it again.
+func (s *Service) handleCloseRegistrationNow(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	var event *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		current, err := tx.GetEventForUpdate(r.Context(), eventID)
+		if err != nil {
+			return err
+		}
+		event, err = setRegistrationWindow(r.Context(), tx, &sqlc.SetEventRegistrationWindowParams{
+			ID:                   eventID,
+			RegistrationOpensAt:  current.RegistrationOpensAt,
+			RegistrationClosesAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to close registration", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Closed registration early", slog.Int64("event_id", eventID))
+
+	s.runTemplate(w, r, "admin_registration_window", event)
+}
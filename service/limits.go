@@ -0,0 +1,50 @@
+package service
+
+// Limits on user input. Request bodies are capped for every route; the
+// rest are checked by handlers through the validate package.
+const (
+	maxBodySize = 64 << 10
+	// maxImportSize caps event export files, which hold every participant
+	maxImportSize = 16 << 20
+	// maxImageSize caps event images, which are stored in the database
+	maxImageSize = 2 << 20
+	// maxEventFormSize caps the event forms, which may carry an image
+	maxEventFormSize = maxImageSize + maxBodySize
+
+	maxNameLength        = 100
+	maxDescriptionLength = 2000
+	// Telegram usernames are at most 32 characters
+	maxUsernameLength = 32
+	// bcrypt ignores everything past 72 bytes of a password
+	minPasswordLength = 8
+	maxPasswordLength = 72
+	maxPhoneLength    = 20
+	maxURLLength      = 2048
+	// Telegram rejects longer messages
+	maxMessageLength = 4096
+
+	maxWinners = 1000
+	// maxPrizeLength caps a prize in the winners announcement
+	maxPrizeLength = 100
+	// maxUserEntries caps the entries an admin can give one participant
+	maxUserEntries = 100
+	// maxBonusEntries caps the bonus of entry rules and share links
+	maxBonusEntries = 100
+	// maxShareClicks caps the visitors a share bonus can require
+	maxShareClicks = 1000
+	// maxPromoCodeLength caps promo codes, which people type by hand
+	maxPromoCodeLength = 32
+
+	// maxBulkUsers caps the participants one bulk action changes
+	maxBulkUsers = 1000
+
+	maxTagLength   = 32
+	maxTagsPerUser = 10
+	maxNoteLength  = 500
+	// maxCategoriesPerEvent caps the categories of one event, which are
+	// as long as tags
+	maxCategoriesPerEvent = 5
+
+	// maxAccessLinkHours caps how long a volunteer's access link is valid
+	maxAccessLinkHours = 72
+)
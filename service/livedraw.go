@@ -0,0 +1,388 @@
+package service
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/validate"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// liveDrawPingInterval is how often projector connections are pinged,
+	// so proxies don't close them while the admin is between winners
+	liveDrawPingInterval = 30 * time.Second
+	liveDrawWriteTimeout = 10 * time.Second
+)
+
+// upgrader accepts WebSocket connections from pages of this site only.
+var upgrader = websocket.Upgrader{}
+
+// liveDraws are the events' live draws, which an admin runs from the
+// control panel while projector pages reveal the winners one by one. The
+// zero value is ready to use.
+type liveDraws struct {
+	mu    sync.Mutex
+	rooms map[int64]*liveDrawRoom
+}
+
+// room returns the live draw room of the event.
+func (l *liveDraws) room(eventID int64) *liveDrawRoom {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rooms == nil {
+		l.rooms = make(map[int64]*liveDrawRoom)
+	}
+	room, ok := l.rooms[eventID]
+	if !ok {
+		room = &liveDrawRoom{viewers: make(map[chan liveDrawState]struct{})}
+		l.rooms[eventID] = room
+	}
+	return room
+}
+
+// liveDrawRoom holds an event's live draw and the projector pages
+// following it. Its winners stay on the projectors after the draw is
+// saved, until the next one starts.
+type liveDrawRoom struct {
+	mu sync.Mutex
+	// pool is nil unless a draw is running
+	pool    *drawPool
+	winners []*sqlc.Users
+	viewers map[chan liveDrawState]struct{}
+}
+
+// liveDrawState is what projector pages show.
+type liveDrawState struct {
+	Running bool             `json:"running"`
+	Winners []liveDrawWinner `json:"winners"`
+}
+
+type liveDrawWinner struct {
+	Position     int    `json:"position"`
+	TicketNumber int32  `json:"ticket_number"`
+	Name         string `json:"name"`
+}
+
+// state returns what the projectors show. The room must be locked.
+func (room *liveDrawRoom) state() liveDrawState {
+	state := liveDrawState{Running: room.pool != nil, Winners: make([]liveDrawWinner, len(room.winners))}
+	for i, winner := range room.winners {
+		state.Winners[i] = liveDrawWinner{Position: i + 1, TicketNumber: winner.TicketNumber, Name: winner.Name}
+	}
+	return state
+}
+
+// broadcast sends the state to every projector. It never blocks: a
+// projector that isn't keeping up only gets the latest state. The room
+// must be locked.
+func (room *liveDrawRoom) broadcast() {
+	state := room.state()
+	for ch := range room.viewers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- state
+	}
+}
+
+// watch returns a channel receiving the current state and every later
+// one, and a function to stop watching.
+func (room *liveDrawRoom) watch() (<-chan liveDrawState, func()) {
+	ch := make(chan liveDrawState, 1)
+	room.mu.Lock()
+	room.viewers[ch] = struct{}{}
+	ch <- room.state()
+	room.mu.Unlock()
+
+	return ch, func() {
+		room.mu.Lock()
+		delete(room.viewers, ch)
+		room.mu.Unlock()
+	}
+}
+
+// liveDrawControls is the control panel of a live draw. Result is set
+// once the draw is saved.
+type liveDrawControls struct {
+	EventID int64
+	Running bool
+	Winners []*sqlc.Users
+	// Left is how many participants can still win
+	Left   int
+	Result *winnersData
+}
+
+// controls returns the control panel of the room. The room must be locked.
+func (room *liveDrawRoom) controls(eventID int64) liveDrawControls {
+	controls := liveDrawControls{EventID: eventID, Running: room.pool != nil}
+	if room.pool != nil {
+		controls.Winners = room.winners
+		controls.Left = room.pool.candidates
+	}
+	return controls
+}
+
+// liveDrawEventID returns the event ID of a live draw route.
+func liveDrawEventID(r *http.Request) (int64, error) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return 0, apperr.Validation("Invalid event ID")
+	}
+	return eventID, nil
+}
+
+// renderLiveDrawError shows err above the control panel instead of in
+// place of it.
+func (s *Service) renderLiveDrawError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	w.Header().Set("HX-Retarget", "#live-draw-error")
+	w.Header().Set("HX-Reswap", "innerHTML")
+	s.renderError(w, r, msg, err)
+}
+
+func (s *Service) handleLiveDrawPage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := liveDrawEventID(r)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", err)
+		return
+	}
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	tags, err := s.store.GetEventTags(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get tags", apperr.FromDB(err))
+		return
+	}
+
+	room := s.liveDraws.room(eventID)
+	room.mu.Lock()
+	controls := room.controls(eventID)
+	room.mu.Unlock()
+
+	s.runTemplate(w, r, "admin_live_draw", struct {
+		Event    *sqlc.Events
+		Tags     []string
+		Controls liveDrawControls
+	}{event, tags, controls})
+}
+
+// handleStartLiveDraw takes a snapshot of who can win and clears the
+// projectors for the winners to come.
+func (s *Service) handleStartLiveDraw(w http.ResponseWriter, r *http.Request) {
+	eventID, err := liveDrawEventID(r)
+	if err != nil {
+		s.renderLiveDrawError(w, r, "Invalid event ID", err)
+		return
+	}
+	form := validate.NewForm(r)
+	opts := drawOptions{
+		Filter: tagFilter{
+			Include: normalizeTags(form.List("include_tags", maxTagsPerUser, maxTagLength)),
+			Exclude: normalizeTags(form.List("exclude_tags", maxTagsPerUser, maxTagLength)),
+		},
+		OnlyReachable: r.FormValue("reachable") == "true",
+	}
+	if err := form.Err(); err != nil {
+		s.renderLiveDrawError(w, r, "Invalid live draw", err)
+		return
+	}
+
+	room := s.liveDraws.room(eventID)
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	if room.pool != nil {
+		s.renderLiveDrawError(w, r, "Live draw already running", apperr.Conflict("A live draw is already running. Finish or cancel it first"))
+		return
+	}
+	pool, err := s.newDrawPool(r.Context(), eventID, opts)
+	if err != nil {
+		s.renderLiveDrawError(w, r, "Failed to start live draw", apperr.FromDB(err))
+		return
+	}
+	if pool.candidates == 0 {
+		s.renderLiveDrawError(w, r, "No one to draw", apperr.Validation("No participants can win this draw"))
+		return
+	}
+	room.pool = pool
+	room.winners = nil
+	room.broadcast()
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Started live draw",
+		slog.Int64("event_id", eventID), slog.Int("candidates", pool.candidates))
+	s.runTemplate(w, r, "live_draw_controls", room.controls(eventID))
+}
+
+// handleNextLiveWinner picks the next winner and reveals them on the
+// projectors.
+func (s *Service) handleNextLiveWinner(w http.ResponseWriter, r *http.Request) {
+	eventID, err := liveDrawEventID(r)
+	if err != nil {
+		s.renderLiveDrawError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	room := s.liveDraws.room(eventID)
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	if room.pool == nil {
+		s.renderLiveDrawError(w, r, "No live draw running", apperr.Conflict("The live draw has ended. Start a new one"))
+		return
+	}
+	if len(room.winners) >= maxWinners {
+		s.renderLiveDrawError(w, r, "Too many winners", apperr.Validation("This draw can't have more winners"))
+		return
+	}
+	id, ok := room.pool.next()
+	if !ok {
+		s.renderLiveDrawError(w, r, "No one left to draw", apperr.Conflict("Every participant has already won"))
+		return
+	}
+	winner, err := s.store.GetUserByID(r.Context(), id)
+	if err != nil {
+		s.renderLiveDrawError(w, r, "Failed to get winner", apperr.FromDB(err))
+		return
+	}
+	room.winners = append(room.winners, winner)
+	room.broadcast()
+
+	s.runTemplate(w, r, "live_draw_controls", room.controls(eventID))
+}
+
+// handleFinishLiveDraw saves the draw with the winners revealed so far and
+// notifies them, like a draw run at once.
+func (s *Service) handleFinishLiveDraw(w http.ResponseWriter, r *http.Request) {
+	eventID, err := liveDrawEventID(r)
+	if err != nil {
+		s.renderLiveDrawError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	room := s.liveDraws.room(eventID)
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	if room.pool == nil {
+		s.renderLiveDrawError(w, r, "No live draw running", apperr.Conflict("The live draw has ended. Start a new one"))
+		return
+	}
+	if len(room.winners) == 0 {
+		s.renderLiveDrawError(w, r, "No winners drawn", apperr.Validation("Draw at least one winner first"))
+		return
+	}
+	result, err := s.saveDraw(r.Context(), room.pool, room.winners)
+	if err != nil {
+		s.renderLiveDrawError(w, r, "Failed to save live draw", apperr.FromDB(err))
+		return
+	}
+	room.pool = nil
+	room.broadcast()
+
+	controls := room.controls(eventID)
+	winners := newWinnersData(eventID, result)
+	controls.Result = &winners
+	s.runTemplate(w, r, "live_draw_controls", controls)
+}
+
+// handleCancelLiveDraw drops the running draw without saving it, and
+// clears the projectors.
+func (s *Service) handleCancelLiveDraw(w http.ResponseWriter, r *http.Request) {
+	eventID, err := liveDrawEventID(r)
+	if err != nil {
+		s.renderLiveDrawError(w, r, "Invalid event ID", err)
+		return
+	}
+
+	room := s.liveDraws.room(eventID)
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	room.pool = nil
+	room.winners = nil
+	room.broadcast()
+
+	s.runTemplate(w, r, "live_draw_controls", room.controls(eventID))
+}
+
+// handleProjectorPage shows the winners of the event's live draw to the
+// audience as they are drawn.
+func (s *Service) handleProjectorPage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := liveDrawEventID(r)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", err)
+		return
+	}
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	s.runTemplate(w, r, "live_draw_projector", event)
+}
+
+// handleLiveDrawSocket sends the state of the event's live draw to a
+// projector page over a WebSocket, first the current one and then each
+// change, until the page closes or the server shuts down. The route is
+// registered without the request timeout.
+func (s *Service) handleLiveDrawSocket(w http.ResponseWriter, r *http.Request) {
+	eventID, err := liveDrawEventID(r)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", err)
+		return
+	}
+	if _, err := s.store.GetEventByID(r.Context(), eventID); err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+
+	// The upgrader answers failed handshakes itself
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelWarn, "Failed to open live draw socket", slog.Any("error", err))
+		return
+	}
+	defer conn.Close()
+
+	states, stop := s.liveDraws.room(eventID).watch()
+	defer stop()
+
+	// Projectors send nothing, but reading notices when they go away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(liveDrawPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-s.stopping:
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""),
+				time.Now().Add(liveDrawWriteTimeout))
+			return
+		case <-ping.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(liveDrawWriteTimeout))
+		case state := <-states:
+			conn.SetWriteDeadline(time.Now().Add(liveDrawWriteTimeout))
+			err = conn.WriteJSON(state)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/authz"
+	"giveaway-tool/logging"
+	"giveaway-tool/notify"
+	"giveaway-tool/store"
+)
+
+// outboxRetention is how long delivered and abandoned bot messages are kept
+// for troubleshooting.
+const outboxRetention = 30 * 24 * time.Hour
+
+// trashRetention is how long deleted events and participants can be
+// restored before they are removed for good.
+const trashRetention = 30 * 24 * time.Hour
+
+// errDryRun rolls back a cleanup that only reports what it would remove.
+var errDryRun = errors.New("dry run")
+
+// cleanupReport counts the rows a cleanup removed or, on a dry run, would
+// remove.
+type cleanupReport struct {
+	DryRun          bool
+	IdempotencyKeys int64
+	OutboxMessages  int64
+	// Webhooks counts delivered and abandoned webhook calls, kept as long
+	// as bot messages
+	Webhooks int64
+	// NotifyPrefs counts notification preferences that listed deleted
+	// events
+	NotifyPrefs int64
+	// LoginTokens counts expired admin login links
+	LoginTokens int64
+	// Sessions counts expired browser sessions
+	Sessions int64
+	// DeletedEvents and DeletedUsers count what stayed in the trash past
+	// its retention
+	DeletedEvents int64
+	DeletedUsers  int64
+}
+
+func (r cleanupReport) Total() int64 {
+	return r.IdempotencyKeys + r.OutboxMessages + r.Webhooks + r.NotifyPrefs + r.LoginTokens + r.Sessions +
+		r.DeletedEvents + r.DeletedUsers
+}
+
+// cleanup removes data nothing needs anymore and empties the trash of
+// what was deleted before trashRetention. Rows of purged events go with
+// them through foreign keys, so only data without one is handled here.
+func cleanup(ctx context.Context, tx store.Store, now time.Time) (cleanupReport, error) {
+	var report cleanupReport
+	var err error
+	if report.IdempotencyKeys, err = tx.DeleteIdempotencyKeysBefore(ctx, now.Add(-idempotencyWindow)); err != nil {
+		return report, err
+	}
+	if report.OutboxMessages, err = tx.DeleteOutboxBefore(ctx, now.Add(-outboxRetention)); err != nil {
+		return report, err
+	}
+	if report.Webhooks, err = tx.DeleteWebhooksBefore(ctx, now.Add(-outboxRetention)); err != nil {
+		return report, err
+	}
+	if report.DeletedUsers, err = tx.PurgeDeletedUsersBefore(ctx, now.Add(-trashRetention)); err != nil {
+		return report, err
+	}
+	if report.DeletedEvents, err = tx.PurgeDeletedEventsBefore(ctx, now.Add(-trashRetention)); err != nil {
+		return report, err
+	}
+	if report.NotifyPrefs, err = tx.PruneNotifyEventIDs(ctx); err != nil {
+		return report, err
+	}
+	if report.LoginTokens, err = tx.DeleteAdminLoginTokensBefore(ctx, now); err != nil {
+		return report, err
+	}
+	if report.Sessions, err = tx.DeleteSessionsBefore(ctx, now); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// runCleanup runs cleanup in a transaction, rolling it back on a dry run.
+func (s *Service) runCleanup(ctx context.Context, dryRun bool) (cleanupReport, error) {
+	var report cleanupReport
+	err := s.store.InTx(ctx, func(tx store.Store) error {
+		var err error
+		if report, err = cleanup(ctx, tx, time.Now()); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	report.DryRun = dryRun
+	return report, err
+}
+
+// runMaintenance cleans up every hour until ctx is done.
+func (s *Service) runMaintenance(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.runCleanup(ctx, false)
+			if err != nil {
+				s.logger.LogAttrs(ctx, slog.LevelError, "Failed to clean up", slog.Any("error", err))
+				notify.JobFailed("очищення даних", err)
+				continue
+			}
+			if report.Total() > 0 {
+				s.logger.LogAttrs(ctx, slog.LevelInfo, "Cleaned up",
+					slog.Int64("idempotency_keys", report.IdempotencyKeys),
+					slog.Int64("outbox_messages", report.OutboxMessages),
+					slog.Int64("webhooks", report.Webhooks),
+					slog.Int64("notify_prefs", report.NotifyPrefs),
+					slog.Int64("login_tokens", report.LoginTokens),
+					slog.Int64("sessions", report.Sessions),
+					slog.Int64("deleted_events", report.DeletedEvents),
+					slog.Int64("deleted_users", report.DeletedUsers))
+			}
+		}
+	}
+}
+
+type maintenanceData struct {
+	Report cleanupReport
+	Role   authz.Role
+}
+
+// handleMaintenancePage shows what the next cleanup would remove.
+func (s *Service) handleMaintenancePage(w http.ResponseWriter, r *http.Request) {
+	report, err := s.runCleanup(r.Context(), true)
+	if err != nil {
+		s.renderError(w, r, "Failed to check for stale data", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_maintenance", maintenanceData{
+		Report: report,
+		Role:   authz.RoleFromContext(r.Context()),
+	})
+}
+
+// handleCleanup cleans up on demand.
+func (s *Service) handleCleanup(w http.ResponseWriter, r *http.Request) {
+	report, err := s.runCleanup(r.Context(), false)
+	if err != nil {
+		s.renderError(w, r, "Failed to clean up", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Cleaned up on demand",
+		slog.Int64("idempotency_keys", report.IdempotencyKeys),
+		slog.Int64("outbox_messages", report.OutboxMessages),
+		slog.Int64("webhooks", report.Webhooks),
+		slog.Int64("notify_prefs", report.NotifyPrefs),
+		slog.Int64("login_tokens", report.LoginTokens),
+		slog.Int64("deleted_events", report.DeletedEvents),
+		slog.Int64("deleted_users", report.DeletedUsers))
+
+	s.runTemplate(w, r, "admin_cleanup_report", report)
+}
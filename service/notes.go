@@ -0,0 +1,45 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/validate"
+)
+
+// handleSetUserNotes saves the admin notes on a participant. Notes are
+// never shown to participants.
+func (s *Service) handleSetUserNotes(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("eventID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("userID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid user ID", apperr.Validation("Invalid user ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	notes := form.Text("notes", maxNoteLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid notes", err)
+		return
+	}
+
+	user, err := s.store.SetUserNotes(r.Context(), &sqlc.SetUserNotesParams{
+		ID:      userID,
+		EventID: eventID,
+		Notes:   notes,
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update notes", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_user_notes", user)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/notify"
+	"giveaway-tool/validate"
+)
+
+type notificationKindOption struct {
+	notify.KindInfo
+	Enabled bool
+}
+
+type notificationEventOption struct {
+	*sqlc.Events
+	Selected bool
+}
+
+type notificationPrefsData struct {
+	Subscription *sqlc.DigestSubscriptions
+	Kinds        []notificationKindOption
+	Events       []notificationEventOption
+	Weekdays     []string
+}
+
+func (s *Service) notificationPrefsData(ctx context.Context, id int64) (*notificationPrefsData, error) {
+	sub, err := s.store.GetDigestSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.store.GetEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &notificationPrefsData{Subscription: sub, Weekdays: weekdays}
+	for _, info := range notify.Kinds {
+		data.Kinds = append(data.Kinds, notificationKindOption{
+			KindInfo: info,
+			Enabled:  slices.Contains(sub.NotifyKinds, string(info.Kind)),
+		})
+	}
+	for _, event := range events {
+		data.Events = append(data.Events, notificationEventOption{
+			Events:   event,
+			Selected: slices.Contains(sub.NotifyEventIds, event.ID),
+		})
+	}
+	return data, nil
+}
+
+func (s *Service) handleNotificationPrefsPage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid subscription ID", apperr.Validation("Invalid subscription ID"))
+		return
+	}
+
+	data, err := s.notificationPrefsData(r.Context(), id)
+	if err != nil {
+		s.renderError(w, r, "Failed to get notification preferences", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_notification_prefs", data)
+}
+
+func (s *Service) handleUpdateNotificationPrefs(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid subscription ID", apperr.Validation("Invalid subscription ID"))
+		return
+	}
+
+	if err := validate.NewForm(r).Err(); err != nil {
+		s.renderError(w, r, "Invalid notification preferences", err)
+		return
+	}
+
+	schedule, err := parseDigestSchedule(r)
+	if err != nil {
+		s.renderError(w, r, "Invalid notification preferences", err)
+		return
+	}
+
+	arg := &sqlc.UpdateNotificationPreferencesParams{
+		ID:               id,
+		ChatID:           schedule.ChatID,
+		Weekday:          schedule.Weekday,
+		Hour:             schedule.Hour,
+		WeeklyDigest:     r.FormValue("weekly_digest") == "true",
+		IncludeAnomalies: r.FormValue("include_anomalies") == "true",
+		NotifyKinds:      []string{},
+		NotifyEventIds:   []int64{},
+	}
+	for _, kind := range r.Form["kinds"] {
+		if !notify.IsKnownKind(notify.Kind(kind)) {
+			s.renderError(w, r, "Invalid notification preferences", apperr.Validation("Unknown notification kind"))
+			return
+		}
+		arg.NotifyKinds = append(arg.NotifyKinds, kind)
+	}
+	for _, v := range r.Form["events"] {
+		eventID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			s.renderError(w, r, "Invalid notification preferences", apperr.Validation("Invalid event ID"))
+			return
+		}
+		arg.NotifyEventIds = append(arg.NotifyEventIds, eventID)
+	}
+
+	if _, err := s.store.UpdateNotificationPreferences(r.Context(), arg); err != nil {
+		s.renderError(w, r, "Failed to update notification preferences", apperr.FromDB(err))
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, "Налаштування збережено")
+}
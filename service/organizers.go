@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/authz"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+// organizerID returns the ID of the admin of ctx when they only see the
+// events they organize, or 0 when they see every event.
+func organizerID(ctx context.Context) int64 {
+	admin := currentAdmin(ctx)
+	if admin == nil || authz.AllEvents(authz.RoleFromContext(ctx)) {
+		return 0
+	}
+	return admin.ID
+}
+
+// organizedEvents returns the events the admin of ctx may see. events may
+// be shared with the store cache, so it is left as it is.
+func organizedEvents(ctx context.Context, events []*sqlc.Events) []*sqlc.Events {
+	return slices.DeleteFunc(slices.Clone(events), func(event *sqlc.Events) bool {
+		return !authz.EventAllowed(ctx, event.ID)
+	})
+}
+
+// organizeNewEvent adds the admin of ctx to the organizers of the event they
+// just created if they only see the events they organize, so it doesn't
+// disappear from their dashboard. It returns the name they were added
+// under, or "" if they weren't added.
+func organizeNewEvent(ctx context.Context, tx store.Store, eventID int64) (string, error) {
+	admin := currentAdmin(ctx)
+	if organizerID(ctx) == 0 {
+		return "", nil
+	}
+	if _, err := tx.CreateEventOrganizer(ctx, &sqlc.CreateEventOrganizerParams{
+		EventID: eventID,
+		Name:    admin.Username,
+		AdminID: sql.NullInt64{Int64: admin.ID, Valid: true},
+	}); err != nil {
+		return "", err
+	}
+	return admin.Username, nil
+}
+
+type eventOrganizersData struct {
+	Event      *sqlc.Events
+	Organizers []*sqlc.EventOrganizers
+}
+
+func (s *Service) renderEventOrganizers(w http.ResponseWriter, r *http.Request, eventID int64) {
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+
+	organizers, err := s.store.GetEventOrganizers(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get organizers", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_event_organizers", eventOrganizersData{Event: event, Organizers: organizers})
+}
+
+func (s *Service) handleAddEventOrganizer(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	arg := &sqlc.CreateEventOrganizerParams{
+		EventID:        eventID,
+		Name:           form.RequiredText("name", maxNameLength),
+		Username:       strings.TrimPrefix(form.Text("username", maxUsernameLength+1), "@"),
+		Responsibility: form.Text("responsibility", maxNameLength),
+	}
+	adminUsername := form.Text("admin", maxUsernameLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid organizer", err)
+		return
+	}
+
+	// Organizers linked to an admin account give that admin the event
+	if adminUsername != "" {
+		admin, err := s.store.GetAdminByUsername(r.Context(), adminUsername)
+		if errors.Is(err, sql.ErrNoRows) {
+			s.renderError(w, r, "Unknown admin", apperr.Validation("There is no admin "+adminUsername))
+			return
+		}
+		if err != nil {
+			s.renderError(w, r, "Failed to get admin", apperr.FromDB(err))
+			return
+		}
+		arg.AdminID = sql.NullInt64{Int64: admin.ID, Valid: true}
+	}
+
+	if _, err := s.store.CreateEventOrganizer(r.Context(), arg); err != nil {
+		s.renderError(w, r, "Failed to add organizer", apperr.FromDB(err))
+		return
+	}
+
+	s.renderEventOrganizers(w, r, eventID)
+}
+
+func (s *Service) handleRemoveEventOrganizer(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	organizerID, err := strconv.ParseInt(r.PathValue("organizerID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid organizer ID", apperr.Validation("Invalid organizer ID"))
+		return
+	}
+
+	if err := s.store.DeleteEventOrganizer(r.Context(), &sqlc.DeleteEventOrganizerParams{
+		ID:      organizerID,
+		EventID: eventID,
+	}); err != nil {
+		s.renderError(w, r, "Failed to remove organizer", apperr.FromDB(err))
+		return
+	}
+
+	s.renderEventOrganizers(w, r, eventID)
+}
@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/store/memory"
+)
+
+func TestOrganizerScope(t *testing.T) {
+	st := memory.New()
+	server := newServer(t, st)
+	newAdmin(t, st, "owner", "owner")
+	ann := newAdmin(t, st, "ann", "admin")
+	newAdmin(t, st, "bob", "moderator")
+	organized := newEvent(t, st, lifecycle.Draft, 0)
+	other := newEvent(t, st, lifecycle.Draft, 0)
+	if _, err := st.CreateEventOrganizer(context.Background(), &sqlc.CreateEventOrganizerParams{
+		EventID: organized.ID,
+		Name:    "Ann",
+		AdminID: sql.NullInt64{Int64: ann.ID, Valid: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		admin string
+		path  string
+		want  int
+	}{
+		{name: "owner", admin: "owner", path: fmt.Sprintf("/admin/events/%d", other.ID), want: http.StatusOK},
+		{name: "organizer", admin: "ann", path: fmt.Sprintf("/admin/events/%d", organized.ID), want: http.StatusOK},
+		{name: "not organizer", admin: "ann", path: fmt.Sprintf("/admin/events/%d", other.ID), want: http.StatusForbidden},
+		{name: "not organizer export", admin: "ann", path: fmt.Sprintf("/admin/events/%d/participants.csv", other.ID), want: http.StatusForbidden},
+		{name: "not organizer API", admin: "ann", path: fmt.Sprintf("/api/v1/events/%d", other.ID), want: http.StatusForbidden},
+		{name: "organizer of none", admin: "bob", path: fmt.Sprintf("/admin/events/%d", organized.ID), want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := signIn(t, server.URL, tt.admin)
+			resp, err := client.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	t.Run("dashboard", func(t *testing.T) {
+		client, _ := signIn(t, server.URL, "ann")
+		resp, err := client.Get(server.URL + "/admin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(body), fmt.Sprintf(`href="/admin/events/%d"`, organized.ID)) {
+			t.Error("organized event missing from the dashboard")
+		}
+		if strings.Contains(string(body), fmt.Sprintf(`href="/admin/events/%d"`, other.ID)) {
+			t.Error("other event listed on the dashboard")
+		}
+	})
+
+	t.Run("created event", func(t *testing.T) {
+		client, csrfToken := signIn(t, server.URL, "ann")
+		r, err := http.NewRequest(http.MethodPost, server.URL+"/api/v1/events", strings.NewReader(`{"name":"Meetup","date":"2030-01-01T18:00:00Z"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("X-CSRF-Token", csrfToken)
+		resp, err := client.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("status %d, want %d", resp.StatusCode, http.StatusCreated)
+		}
+
+		eventIDs, err := st.GetOrganizedEventIDs(context.Background(), ann.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(eventIDs) != 2 || !slices.Contains(eventIDs, organized.ID) {
+			t.Errorf("organizes events %v, want %d and the new one", eventIDs, organized.ID)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/sessionstore"
+	"giveaway-tool/validate"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordPath is where admins change their password, the one admin page
+// those who must change it can open.
+const passwordPath = "/admin/password"
+
+type passwordData struct {
+	// HasPassword is false for admins who sign in with login links only,
+	// who have no current password to give
+	HasPassword bool
+	MustChange  bool
+}
+
+// handlePasswordPage shows the form for the signed-in admin to change
+// their password.
+func (s *Service) handlePasswordPage(w http.ResponseWriter, r *http.Request) {
+	admin := currentAdmin(r.Context())
+	s.runTemplate(w, r, "admin_password", passwordData{
+		HasPassword: admin.PasswordHash.Valid,
+		MustChange:  admin.MustChangePassword,
+	})
+}
+
+// handleChangePassword replaces the signed-in admin's password once they
+// give the current one, and signs them out of their other sessions.
+func (s *Service) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.renderError(w, r, "Invalid password", validate.BodyError(err))
+		return
+	}
+	admin := currentAdmin(r.Context())
+	current := r.FormValue("current_password")
+	password := r.FormValue("password")
+
+	if admin.PasswordHash.Valid {
+		if bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash.String), []byte(current)) != nil {
+			s.renderError(w, r, "Invalid password", apperr.Validation("Current password is wrong"))
+			return
+		}
+		if password == current {
+			s.renderError(w, r, "Invalid password", apperr.Validation("Choose a password different from the current one"))
+			return
+		}
+	}
+	if password != r.FormValue("confirm_password") {
+		s.renderError(w, r, "Invalid password", apperr.Validation("Passwords don't match"))
+		return
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		s.renderError(w, r, "Invalid password", err)
+		return
+	}
+
+	if err := s.store.SetAdminPassword(r.Context(), &sqlc.SetAdminPasswordParams{
+		ID:           admin.ID,
+		PasswordHash: sql.NullString{String: hash, Valid: true},
+	}); err != nil {
+		s.renderError(w, r, "Failed to set password", apperr.FromDB(err))
+		return
+	}
+
+	// Whoever knew the old password may be signed in with it elsewhere
+	session, _ := s.sessionStore.Get(r, "session")
+	ended, err := s.store.DeleteOtherAdminSessions(r.Context(), &sqlc.DeleteOtherAdminSessionsParams{
+		AdminID:   sql.NullInt64{Int64: admin.ID, Valid: true},
+		TokenHash: sessionstore.HashToken(session.ID),
+	})
+	if err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to end other sessions", slog.Any("error", err))
+	}
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Admin changed password", slog.Int64("sessions_ended", ended))
+
+	if admin.MustChangePassword {
+		w.Header().Set("HX-Redirect", "/admin")
+		return
+	}
+	fmt.Fprintf(w, successHTML, "Пароль змінено. Інші сеанси завершено.")
+}
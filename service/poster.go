@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/telegram"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// posterQRSize is the side of poster QR codes in pixels, enough for about
+// 9 cm printed at 300 dpi.
+const posterQRSize = 1024
+
+// botURL returns the link that opens the Telegram bot participants
+// register with, or "" if there is no bot to open.
+func (s *Service) botURL() string {
+	if s.botUsername == "" {
+		return ""
+	}
+	return "https://t.me/" + s.botUsername
+}
+
+// botStartURL returns the link that opens the bot to register for the
+// event, or "" if there is no bot to open.
+func (s *Service) botStartURL(eventID int64) string {
+	if s.botUsername == "" {
+		return ""
+	}
+	return s.botURL() + "?start=" + telegram.StartPayload(eventID)
+}
+
+// handleEventQRCode returns a PNG QR code of the bot link for the event,
+// for posters at the venue. It uses the highest error correction, so a
+// creased or smudged print still scans.
+func (s *Service) handleEventQRCode(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+	if _, err := s.store.GetEventByID(r.Context(), eventID); err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	url := s.botStartURL(eventID)
+	if url == "" {
+		s.renderError(w, r, "No bot to link to", apperr.NotFound("The Telegram bot isn't connected"))
+		return
+	}
+
+	png, err := qrcode.Encode(url, qrcode.Highest, posterQRSize)
+	if err != nil {
+		s.renderError(w, r, "Failed to make QR code", err)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	if r.URL.Query().Get("download") == "true" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-qr.png"`, eventID))
+	}
+	w.Write(png)
+}
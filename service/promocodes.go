@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/promo"
+	"giveaway-tool/validate"
+)
+
+// generatedCodeLength is the length of codes made up when the admin leaves
+// the code empty; short enough to type from a poster.
+const generatedCodeLength = 8
+
+type promoCodesData struct {
+	Event *sqlc.Events
+	Codes []*sqlc.GetPromoCodeStatsRow
+}
+
+func (s *Service) promoCodesData(ctx context.Context, eventID int64) (*promoCodesData, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := s.store.GetPromoCodeStats(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &promoCodesData{Event: event, Codes: codes}, nil
+}
+
+func (s *Service) renderPromoCodes(w http.ResponseWriter, r *http.Request, eventID int64) {
+	data, err := s.promoCodesData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get promo codes", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_promo_codes_table", data)
+}
+
+// handlePromoCodesPage lists the event's promo codes with what each of
+// them brought.
+func (s *Service) handlePromoCodesPage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	data, err := s.promoCodesData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get promo codes", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_promo_codes", data)
+}
+
+func (s *Service) handleCreatePromoCode(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	arg := &sqlc.CreatePromoCodeParams{
+		EventID: eventID,
+		Code:    promo.Normalize(form.Text("code", maxPromoCodeLength)),
+		Kind:    r.FormValue("kind"),
+	}
+	switch arg.Kind {
+	case promo.KindFree:
+	case promo.KindDiscount:
+		arg.Value = int32(form.Int("value", 1, 99))
+	case promo.KindEntries:
+		arg.Value = int32(form.Int("value", 1, maxBonusEntries))
+	default:
+		s.renderError(w, r, "Invalid promo code", apperr.Validation("Invalid promo code kind"))
+		return
+	}
+	if maxUses, ok := form.OptionalInt("max_uses", 1, 100000); ok {
+		arg.MaxUses = int32(maxUses)
+	}
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid promo code", err)
+		return
+	}
+
+	if v := r.FormValue("expires_at"); v != "" {
+		expires, err := time.Parse("2006-01-02T15:04", v)
+		if err != nil {
+			s.renderError(w, r, "Invalid promo code", apperr.Validation("Invalid date"))
+			return
+		}
+		arg.ExpiresAt = sql.NullTime{Time: expires, Valid: true}
+	}
+	if arg.Code == "" {
+		arg.Code = cryptoRand.Text()[:generatedCodeLength]
+	}
+
+	code, err := s.store.CreatePromoCode(r.Context(), arg)
+	if err != nil {
+		s.renderError(w, r, "Failed to create promo code", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Created promo code",
+		slog.Int64("event_id", eventID), slog.Int64("promo_code_id", code.ID), slog.String("kind", code.Kind))
+
+	s.renderPromoCodes(w, r, eventID)
+}
+
+// handleDeletePromoCode deletes a code along with its statistics.
+// Participants keep what the code gave them.
+func (s *Service) handleDeletePromoCode(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	codeID, err := strconv.ParseInt(r.PathValue("codeID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid promo code ID", apperr.Validation("Invalid promo code ID"))
+		return
+	}
+
+	if err := s.store.DeletePromoCode(r.Context(), &sqlc.DeletePromoCodeParams{
+		ID:      codeID,
+		EventID: eventID,
+	}); err != nil {
+		s.renderError(w, r, "Failed to delete promo code", apperr.FromDB(err))
+		return
+	}
+
+	s.renderPromoCodes(w, r, eventID)
+}
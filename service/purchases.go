@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/notify"
+	"giveaway-tool/payments"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+// pendingPurchaseWindow is how long an unpaid checkout keeps its entries
+// reserved against the event's limit.
+const pendingPurchaseWindow = 3600
+
+// formatPrice formats an amount in kopecks as hryvnias.
+func formatPrice(kopecks int32) string {
+	return fmt.Sprintf("%d.%02d грн", kopecks/100, kopecks%100)
+}
+
+func (s *Service) purchasesEnabled(event *sqlc.Events) bool {
+	return s.payments != nil && event.MaxPaidEntries > 0 && event.EntryPrice > 0
+}
+
+// handleBuyEntries starts a checkout for extra entries and sends the
+// participant to the provider's payment page.
+func (s *Service) handleBuyEntries(w http.ResponseWriter, r *http.Request) {
+	user, event, err := s.changeableParticipant(r.Context(), r.PathValue("token"))
+	if err != nil {
+		s.renderError(w, r, "Failed to open self-service page", err)
+		return
+	}
+	if !s.purchasesEnabled(event) {
+		s.renderError(w, r, "Purchases are disabled", apperr.NotFound("Extra entries are not available"))
+		return
+	}
+
+	entries, err := strconv.Atoi(r.FormValue("entries"))
+	if err != nil || entries < 1 || entries > int(event.MaxPaidEntries) {
+		s.renderError(w, r, "Invalid entries", apperr.Validation("Invalid number of entries"))
+		return
+	}
+
+	var purchase *sqlc.EntryPurchases
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		reserved, err := tx.CountReservedPaidEntries(r.Context(), &sqlc.CountReservedPaidEntriesParams{
+			UserID:         user.ID,
+			PendingSeconds: pendingPurchaseWindow,
+		})
+		if err != nil {
+			return err
+		}
+		if reserved+int32(entries) > event.MaxPaidEntries {
+			return apperr.Validation(fmt.Sprintf("You can buy at most %d more entries", max(event.MaxPaidEntries-reserved, 0)))
+		}
+
+		purchase, err = tx.CreateEntryPurchase(r.Context(), &sqlc.CreateEntryPurchaseParams{
+			OrderID: cryptoRand.Text(),
+			UserID:  user.ID,
+			EventID: event.ID,
+			Entries: int32(entries),
+			Amount:  int32(entries) * event.EntryPrice,
+		})
+		return err
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to create purchase", apperr.FromDB(err))
+		return
+	}
+
+	checkoutURL, err := s.payments.CheckoutURL(r.Context(), payments.Order{
+		ID:          purchase.OrderID,
+		Description: fmt.Sprintf("Додаткові шанси (%d) – %s", entries, event.Name),
+		Amount:      purchase.Amount,
+		ResultURL:   s.links.URL(user.ID),
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to start checkout", err)
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Started entry purchase",
+		slog.Int64("user_id", user.ID), slog.String("order_id", purchase.OrderID), slog.Int("entries", entries))
+
+	w.Header().Set("HX-Redirect", checkoutURL)
+}
+
+// handlePaymentCallback settles a ticket order or entry purchase when the
+// provider reports its outcome. Paid tickets register the buyer; paid
+// entries count in every later draw.
+func (s *Service) handlePaymentCallback(w http.ResponseWriter, r *http.Request) {
+	if s.payments == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	callback, err := s.payments.ParseCallback(r)
+	if err != nil {
+		s.renderError(w, r, "Rejected payment callback", apperr.Validation("Invalid callback"))
+		return
+	}
+
+	var user *sqlc.Users
+	switch callback.Status {
+	case payments.StatusPaid:
+		err = s.store.InTx(r.Context(), func(tx store.Store) error {
+			var err error
+			if user, err = settleTicketOrder(r.Context(), tx, callback); err != nil || user != nil {
+				return err
+			}
+			return settlePurchase(r.Context(), tx, callback)
+		})
+	case payments.StatusFailed:
+		if err = s.failTicketOrder(r.Context(), callback.OrderID); err == nil {
+			err = s.store.MarkEntryPurchaseFailed(r.Context(), callback.OrderID)
+		}
+	}
+	if err != nil {
+		s.renderError(w, r, "Failed to settle payment", apperr.FromDB(err))
+		return
+	}
+	if user != nil {
+		notify.Registered(r.Context(), s.store, user)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// settlePurchase marks a purchase paid and credits its entries. Providers
+// retry callbacks, so an already settled purchase is not credited again.
+func settlePurchase(ctx context.Context, tx store.Store, callback *payments.Callback) error {
+	purchase, err := tx.MarkEntryPurchasePaid(ctx, &sqlc.MarkEntryPurchasePaidParams{
+		OrderID:   callback.OrderID,
+		PaymentID: callback.PaymentID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !purchase.UserID.Valid {
+		// The participant cancelled while paying; the payment has to be
+		// refunded by hand
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelWarn, "Payment for cancelled registration",
+			slog.String("order_id", callback.OrderID))
+		return nil
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "Entry purchase paid",
+		slog.Int64("user_id", purchase.UserID.Int64), slog.String("order_id", callback.OrderID))
+
+	return tx.AddUserPaidEntries(ctx, &sqlc.AddUserPaidEntriesParams{
+		ID:      purchase.UserID.Int64,
+		Entries: purchase.Entries,
+	})
+}
+
+func (s *Service) handleSetPaidEntries(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	maxEntries := form.Int("max_paid_entries", 0, maxUserEntries)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid entry limit", err)
+		return
+	}
+	price, err := strconv.ParseFloat(r.FormValue("entry_price"), 64)
+	if err != nil || price < 0 || price > math.MaxInt32/100 {
+		s.renderError(w, r, "Invalid entry price", apperr.Validation("Invalid entry price"))
+		return
+	}
+
+	event, err := s.store.SetEventPaidEntries(r.Context(), &sqlc.SetEventPaidEntriesParams{
+		ID:             eventID,
+		MaxPaidEntries: int32(maxEntries),
+		EntryPrice:     int32(math.Round(price * 100)),
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update paid entries", apperr.FromDB(err))
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, fmt.Sprintf("Збережено: до %d шансів по %s", event.MaxPaidEntries, formatPrice(event.EntryPrice)))
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/router"
+)
+
+const (
+	// maxCheckInCodeFailures is how many unknown check-in codes a client
+	// can enter per checkInCodeWindow before further lookups are refused.
+	maxCheckInCodeFailures = 10
+	checkInCodeWindow      = 15 * time.Minute
+
+	// Default request limits per minute for the public API and the
+	// registration form, overridden by RATE_LIMIT_PER_IP and
+	// RATE_LIMIT_PER_KEY. Clients with an API key or token, like scanner
+	// apps at the entrance, share one address and get their own, higher
+	// limit.
+	defaultRateLimitPerIP  = 60
+	defaultRateLimitPerKey = 600
+)
+
+// failureLimiter counts failed attempts per client in fixed windows, so
+// short codes can't be found by trying them one after another. The zero
+// value is ready to use.
+type failureLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*failureWindow
+}
+
+type failureWindow struct {
+	failures int
+	resetAt  time.Time
+}
+
+// allow reports whether key is still under the failure limit.
+func (l *failureLimiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.windows[key]
+	return !ok || time.Now().After(w.resetAt) || w.failures < maxCheckInCodeFailures
+}
+
+func (l *failureLimiter) fail(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.windows == nil {
+		l.windows = make(map[string]*failureWindow)
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.After(w.resetAt) {
+		// Drop expired windows while starting a new one, so the map only
+		// holds clients that failed recently
+		for k, old := range l.windows {
+			if now.After(old.resetAt) {
+				delete(l.windows, k)
+			}
+		}
+		w = &failureWindow{resetAt: now.Add(checkInCodeWindow)}
+		l.windows[key] = w
+	}
+	w.failures++
+}
+
+// tokenBucket limits requests per client to perMinute, refilled evenly
+// and allowing bursts of up to perMinute requests. A nil *tokenBucket
+// allows everything.
+type tokenBucket struct {
+	perMinute int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newTokenBucket(perMinute int) *tokenBucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &tokenBucket{perMinute: perMinute, buckets: make(map[string]*bucket)}
+}
+
+// take spends a token of key. If none is left, it returns false and how
+// long until the next one.
+func (l *tokenBucket) take(key string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	capacity := float64(l.perMinute)
+	perSecond := capacity / 60
+
+	// Buckets idle for a minute are full again, so they can be dropped
+	// and recreated on demand
+	if now.Sub(l.lastPrune) > time.Minute {
+		for k, b := range l.buckets {
+			if now.Sub(b.updated) > time.Minute {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+	b.updated = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// rateLimitFromEnv reads a per-minute limit from key, where 0 disables the
+// limit. Invalid values fall back to the default.
+func rateLimitFromEnv(ctx context.Context, logger *slog.Logger, key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "Invalid rate limit, using the default",
+			slog.String("key", key), slog.String("value", v), slog.Int("default", fallback))
+		return fallback
+	}
+	return n
+}
+
+// rateLimit answers 429 with Retry-After to clients over their limit,
+// using render to report the error in the format of the route. Requests
+// carrying a valid API key or token are limited per key, others per
+// address.
+func (s *Service) rateLimit(render router.RenderError) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limiter, key := s.ipLimiter, "ip:"+router.ClientIP(r)
+			// Only known keys count, or clients could dodge the address
+			// limit by sending a new made-up key with every request
+			switch apiKey := requestAPIKey(r); {
+			case validAPIKey(apiKey, s.checkInAPIKey):
+				limiter, key = s.keyLimiter, "key:checkin"
+			case validAPIKey(apiKey, s.adminAPIKey):
+				limiter, key = s.keyLimiter, "key:admin"
+			case strings.HasPrefix(apiKey, tokenPrefix):
+				if token, err := s.store.GetTokenByHash(r.Context(), hashToken(apiKey)); err == nil {
+					limiter, key = s.keyLimiter, "token:"+strconv.FormatInt(token.ID, 10)
+				}
+			}
+
+			if ok, retryAfter := limiter.take(key); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				render(w, r, "Rate limit exceeded", apperr.TooManyRequests("Too many requests, please try again later"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestAPIKey returns the key sent as a bearer token or in the X-API-Key
+// header.
+func requestAPIKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+// validAPIKey reports whether key is the configured key want. Nothing
+// matches a key that isn't configured.
+func validAPIKey(key, want string) bool {
+	return want != "" && subtle.ConstantTimeCompare([]byte(key), []byte(want)) == 1
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store/memory"
+)
+
+func TestRateLimit(t *testing.T) {
+	type request struct {
+		addr   string
+		apiKey string
+		want   int
+	}
+	tests := []struct {
+		name     string
+		perIP    int
+		perKey   int
+		requests []request
+	}{
+		{
+			name:  "per address",
+			perIP: 2, perKey: 10,
+			requests: []request{
+				{addr: "192.0.2.1", want: http.StatusOK},
+				{addr: "192.0.2.1", want: http.StatusOK},
+				{addr: "192.0.2.1", want: http.StatusTooManyRequests},
+				{addr: "192.0.2.2", want: http.StatusOK},
+			},
+		},
+		{
+			name:  "token has its own limit",
+			perIP: 1, perKey: 3,
+			requests: []request{
+				{addr: "192.0.2.1", want: http.StatusOK},
+				{addr: "192.0.2.1", apiKey: "gt_valid", want: http.StatusOK},
+				{addr: "192.0.2.1", apiKey: "gt_valid", want: http.StatusOK},
+				{addr: "192.0.2.1", apiKey: "gt_valid", want: http.StatusOK},
+				{addr: "192.0.2.1", apiKey: "gt_valid", want: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name:  "unknown token counts against the address",
+			perIP: 1, perKey: 10,
+			requests: []request{
+				{addr: "192.0.2.1", apiKey: "gt_made_up", want: http.StatusOK},
+				{addr: "192.0.2.1", apiKey: "gt_other", want: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name:  "configured keys share the key limit",
+			perIP: 1, perKey: 2,
+			requests: []request{
+				{addr: "192.0.2.1", apiKey: "checkin-key", want: http.StatusOK},
+				{addr: "192.0.2.2", apiKey: "checkin-key", want: http.StatusOK},
+				{addr: "192.0.2.3", apiKey: "checkin-key", want: http.StatusTooManyRequests},
+				{addr: "192.0.2.1", apiKey: "admin-key", want: http.StatusOK},
+			},
+		},
+		{
+			name:  "disabled",
+			perIP: 0, perKey: 0,
+			requests: []request{
+				{addr: "192.0.2.1", want: http.StatusOK},
+				{addr: "192.0.2.1", want: http.StatusOK},
+				{addr: "192.0.2.1", apiKey: "gt_valid", want: http.StatusOK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			st := memory.New()
+			admin, err := st.CreateAdmin(ctx, &sqlc.CreateAdminParams{Username: "owner", Role: "owner"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := st.CreateToken(ctx, &sqlc.CreateTokenParams{
+				AdminID:   admin.ID,
+				Name:      "scanner",
+				TokenHash: hashToken("gt_valid"),
+			}); err != nil {
+				t.Fatal(err)
+			}
+			s := &Service{
+				store:         st,
+				ipLimiter:     newTokenBucket(tt.perIP),
+				keyLimiter:    newTokenBucket(tt.perKey),
+				checkInAPIKey: "checkin-key",
+				adminAPIKey:   "admin-key",
+			}
+			render := func(w http.ResponseWriter, r *http.Request, msg string, err error) {
+				http.Error(w, msg, apperr.HTTPStatus(err))
+			}
+			handler := s.rateLimit(render)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			for i, req := range tt.requests {
+				r := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/registrations", nil)
+				r.RemoteAddr = req.addr + ":1234"
+				if req.apiKey != "" {
+					r.Header.Set("Authorization", "Bearer "+req.apiKey)
+				}
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, r)
+
+				if w.Code != req.want {
+					t.Errorf("request %d: status %d, want %d", i, w.Code, req.want)
+				}
+				if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+					t.Errorf("request %d: no Retry-After", i)
+				}
+			}
+		})
+	}
+}
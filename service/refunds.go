@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/payments"
+	"giveaway-tool/store"
+)
+
+// refund returns a paid ticket order or entry purchase of the event
+// through the provider and records it as refunded. The participant isn't
+// changed; callers cancel the registration or take the entries off
+// themselves.
+func (s *Service) refund(ctx context.Context, eventID int64, payment *sqlc.GetPaymentsByEventIDRow) error {
+	if s.payments == nil {
+		return apperr.Validation("Refunds need a payment provider")
+	}
+	if payment.Status != "paid" {
+		return apperr.Conflict("Payment is not paid or was already refunded")
+	}
+
+	if err := s.payments.Refund(ctx, payments.Refund{
+		OrderID:   payment.OrderID,
+		PaymentID: payment.PaymentID,
+		Amount:    payment.Amount,
+	}); err != nil {
+		return fmt.Errorf("refund %s: %w", payment.OrderID, err)
+	}
+
+	// The money is back with the payer now, so a failure from here on
+	// only leaves the record behind; it is logged for reconciliation
+	err := s.store.InTx(ctx, func(tx store.Store) error {
+		var err error
+		if payment.Kind == "ticket" {
+			_, err = tx.MarkTicketOrderRefunded(ctx, payment.OrderID)
+		} else {
+			_, err = tx.MarkEntryPurchaseRefunded(ctx, payment.OrderID)
+		}
+		if err != nil {
+			return err
+		}
+		return audit.Record(ctx, tx, audit.PaymentRefunded, eventID, nil, map[string]any{
+			"order_id":      payment.OrderID,
+			"kind":          payment.Kind,
+			"user_id":       payment.UserID,
+			"ticket_number": payment.TicketNumber,
+			"amount":        payment.Amount,
+		})
+	})
+	if err != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Refunded payment not recorded",
+			slog.String("order_id", payment.OrderID), slog.Any("error", err))
+		return err
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "Refunded payment",
+		slog.String("order_id", payment.OrderID), slog.String("kind", payment.Kind), slog.Int("amount", int(payment.Amount)))
+	return nil
+}
+
+// refundParticipant refunds everything the participant paid for the event,
+// returning the refunded total in kopecks.
+func (s *Service) refundParticipant(ctx context.Context, eventID, userID int64) (int32, error) {
+	rows, err := s.store.GetPaymentsByEventID(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int32
+	for _, row := range rows {
+		if row.Status != "paid" || !row.UserID.Valid || row.UserID.Int64 != userID {
+			continue
+		}
+		if err := s.refund(ctx, eventID, row); err != nil {
+			return total, err
+		}
+		total += row.Amount
+	}
+	return total, nil
+}
+
+// applyRefund updates the participant a refunded payment belonged to: a
+// refunded ticket cancels the registration, which frees the place for the
+// waitlist, and refunded entries are taken off. The participant is told
+// through the bot.
+func applyRefund(ctx context.Context, tx store.Store, event *sqlc.Events, payment *sqlc.GetPaymentsByEventIDRow) error {
+	if !payment.UserID.Valid {
+		return nil
+	}
+	user, err := tx.GetUserByID(ctx, payment.UserID.Int64)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var text string
+	if payment.Kind == "ticket" {
+		if err := removeParticipant(ctx, tx, event.ID, user.ID, consent.Deleted, consent.SourceAdmin); err != nil {
+			return err
+		}
+		text = fmt.Sprintf("Кошти за квиток на \"%s\" (%s) повернуто, реєстрацію скасовано.", event.Name, formatPrice(payment.Amount))
+	} else {
+		if err := tx.AddUserPaidEntries(ctx, &sqlc.AddUserPaidEntriesParams{
+			ID:      user.ID,
+			Entries: -min(payment.Entries, user.PaidEntries),
+		}); err != nil {
+			return err
+		}
+		text = fmt.Sprintf("Кошти за додаткові шанси на \"%s\" (%s) повернуто, шанси знято.", event.Name, formatPrice(payment.Amount))
+	}
+
+	if !user.TgID.Valid || user.BotBlockedAt.Valid {
+		return nil
+	}
+	_, err = tx.EnqueueOutboxMessage(ctx, &sqlc.EnqueueOutboxMessageParams{
+		ChatID: user.TgID.Int64,
+		Text:   text,
+	})
+	return err
+}
+
+// refundPayments refunds the given payments of the event and applies them
+// to their participants, stopping at the first failure.
+func (s *Service) refundPayments(ctx context.Context, event *sqlc.Events, rows []*sqlc.GetPaymentsByEventIDRow) (int, error) {
+	refunded := 0
+	for _, row := range rows {
+		if err := s.refund(ctx, event.ID, row); err != nil {
+			return refunded, err
+		}
+		refunded++
+		if err := s.store.InTx(ctx, func(tx store.Store) error {
+			return applyRefund(ctx, tx, event, row)
+		}); err != nil {
+			return refunded, err
+		}
+	}
+	return refunded, nil
+}
+
+func (s *Service) renderPaymentsTable(w http.ResponseWriter, r *http.Request, eventID int64) {
+	data, err := s.paymentsData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get payments", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_payments_table", data)
+}
+
+// handleRefundPayment refunds one payment from the payments page.
+func (s *Service) handleRefundPayment(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	data, err := s.paymentsData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get payments", apperr.FromDB(err))
+		return
+	}
+	var payment *sqlc.GetPaymentsByEventIDRow
+	for _, row := range data.Payments {
+		if row.OrderID == r.PathValue("orderID") {
+			payment = row
+		}
+	}
+	if payment == nil {
+		s.renderError(w, r, "Failed to refund payment", apperr.NotFound("Payment not found"))
+		return
+	}
+
+	if _, err := s.refundPayments(r.Context(), data.Event, []*sqlc.GetPaymentsByEventIDRow{payment}); err != nil {
+		s.renderError(w, r, "Failed to refund payment", apperr.FromDB(err))
+		return
+	}
+
+	s.renderPaymentsTable(w, r, eventID)
+}
+
+// handleRefundAll refunds every paid payment of the event, for when it is
+// cancelled. Buyers of tickets lose their registration.
+func (s *Service) handleRefundAll(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	data, err := s.paymentsData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get payments", apperr.FromDB(err))
+		return
+	}
+	var paid []*sqlc.GetPaymentsByEventIDRow
+	for _, row := range data.Payments {
+		if row.Status == "paid" {
+			paid = append(paid, row)
+		}
+	}
+
+	refunded, err := s.refundPayments(r.Context(), data.Event, paid)
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Refunded event payments",
+		slog.Int64("event_id", eventID), slog.Int("refunded", refunded), slog.Int("paid", len(paid)))
+	if err != nil {
+		s.renderError(w, r, "Failed to refund payments", apperr.FromDB(err))
+		return
+	}
+
+	s.renderPaymentsTable(w, r, eventID)
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/capacity"
+	"giveaway-tool/config"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/notify"
+	"giveaway-tool/promo"
+	"giveaway-tool/registration"
+	"giveaway-tool/store"
+	"giveaway-tool/tickettype"
+	"giveaway-tool/validate"
+)
+
+type registrationRequest struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	// PromoCode is optional
+	PromoCode string `json:"promo_code"`
+	// TicketTypeID is required for events with ticket types
+	TicketTypeID int64 `json:"ticket_type_id"`
+}
+
+type participantResponse struct {
+	ID           int64      `json:"id"`
+	EventID      int64      `json:"event_id"`
+	TicketNumber int32      `json:"ticket_number"`
+	CheckInCode  string     `json:"check_in_code"`
+	Name         string     `json:"name"`
+	Username     string     `json:"username"`
+	TicketTypeID *int64     `json:"ticket_type_id,omitempty"`
+	Seat         string     `json:"seat,omitempty"`
+	CheckedInAt  *time.Time `json:"checked_in_at,omitempty"`
+}
+
+// waitlistResponse is returned instead of a participant when the event is
+// full and the registrant was put on its waitlist.
+type waitlistResponse struct {
+	EventID          int64 `json:"event_id"`
+	WaitlistPosition int32 `json:"waitlist_position"`
+}
+
+func newParticipantResponse(user *sqlc.Users) participantResponse {
+	resp := participantResponse{
+		ID:           user.ID,
+		EventID:      user.EventID,
+		TicketNumber: user.TicketNumber,
+		CheckInCode:  user.CheckInCode,
+		Name:         user.Name,
+		Username:     user.Username,
+	}
+	if user.TicketTypeID.Valid {
+		resp.TicketTypeID = &user.TicketTypeID.Int64
+	}
+	if user.CheckedInAt.Valid {
+		resp.CheckedInAt = &user.CheckedInAt.Time
+	}
+	return resp
+}
+
+type registerPageData struct {
+	Event *sqlc.Events
+	// Price is the ticket price, or "" if registration is free. Events
+	// with TicketTypes have a price per type instead.
+	Price       string
+	TicketTypes []ticketTypeOption
+	// PromoCode fills the promo code in for links shared with one
+	PromoCode string
+}
+
+// ticketTypeOption is a ticket type to pick when registering.
+type ticketTypeOption struct {
+	*sqlc.TicketTypes
+	// Price is "" for free types
+	Price   string
+	SoldOut bool
+}
+
+// ticketTypeOptions lists the event's ticket types for registration forms.
+func ticketTypeOptions(ctx context.Context, st store.Store, eventID int64) ([]ticketTypeOption, error) {
+	ticketTypes, err := st.GetTicketTypesByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]ticketTypeOption, 0, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		option := ticketTypeOption{TicketTypes: ticketType}
+		if ticketType.Price > 0 {
+			option.Price = formatPrice(ticketType.Price)
+		}
+		if ticketType.Capacity > 0 {
+			left, err := tickettype.Left(ctx, st, ticketType)
+			if err != nil {
+				return nil, err
+			}
+			option.SoldOut = left <= 0
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+// openEvent returns the event if public registration is enabled, the event
+// has its registration open and it hasn't started yet.
+func (s *Service) openEvent(ctx context.Context, rawID string) (*sqlc.Events, error) {
+	if !config.FlagEnabled(config.FlagPublicRegistration) {
+		return nil, apperr.NotFound("Registration is not available")
+	}
+
+	eventID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return nil, apperr.Validation("Invalid event ID")
+	}
+
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, apperr.FromDB(err)
+	}
+	if !lifecycle.Public(event) {
+		return nil, apperr.NotFound("Registration is not available")
+	}
+	if event.Date.Before(time.Now()) {
+		return nil, apperr.Validation("Registration for this event is closed")
+	}
+	if err := lifecycle.CheckOpen(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// validate trims the request's fields and checks them.
+func (req *registrationRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Username = strings.TrimPrefix(strings.TrimSpace(req.Username), "@")
+	if req.Name == "" {
+		return apperr.Validation("Name is required")
+	}
+	if err := validate.Length("name", req.Name, maxNameLength); err != nil {
+		return err
+	}
+	if err := validate.Length("promo_code", req.PromoCode, maxPromoCodeLength); err != nil {
+		return err
+	}
+	return validate.Length("username", req.Username, maxUsernameLength)
+}
+
+// register creates a participant and records the consent they gave by
+// registering through source, taking a place of the event and of their
+// ticket type and applying their promo code if they have one. Paid tickets are sold by
+// startTicketOrder instead.
+func (s *Service) register(ctx context.Context, event *sqlc.Events, req registrationRequest, source string) (*sqlc.Users, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	var user *sqlc.Users
+	err := s.store.InTx(ctx, func(tx store.Store) error {
+		ticketType, err := registration.Reserve(ctx, tx, event.ID, req.TicketTypeID)
+		if err != nil {
+			return err
+		}
+		price := tickettype.Price(event, ticketType)
+
+		var code *sqlc.PromoCodes
+		if req.PromoCode != "" {
+			if code, err = promo.Use(ctx, tx, event.ID, price, req.PromoCode); err != nil {
+				return err
+			}
+		}
+		if user, err = createParticipant(ctx, tx, event.ID, ticketType, req, source); err != nil {
+			return err
+		}
+		if code != nil {
+			return promo.Redeem(ctx, tx, code, user, price-promo.Price(code, price))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, apperr.FromDB(err)
+	}
+
+	notify.Registered(ctx, s.store, user)
+	return user, nil
+}
+
+// createParticipant creates a participant of the ticket type, which is nil
+// for events without types, from a validated request in tx, recording
+// their consent and queueing webhooks.
+func createParticipant(ctx context.Context, tx store.Store, eventID int64, ticketType *sqlc.TicketTypes, req registrationRequest, source string) (*sqlc.Users, error) {
+	return registration.Create(ctx, tx, &sqlc.CreateUserParams{
+		Name:     req.Name,
+		Username: req.Username,
+		EventID:  eventID,
+	}, ticketType, source)
+}
+
+// price returns what the request's ticket costs with its promo code, so
+// free tickets are registered without going through checkout. Neither the
+// ticket type nor the code is used up.
+func (s *Service) price(ctx context.Context, event *sqlc.Events, req registrationRequest) (int32, error) {
+	ticketType, err := tickettype.Get(ctx, s.store, event.ID, req.TicketTypeID)
+	if err != nil {
+		return 0, err
+	}
+	price := tickettype.Price(event, ticketType)
+	if price == 0 || req.PromoCode == "" {
+		return price, nil
+	}
+	promoCode, err := promo.Get(ctx, s.store, event.ID, price, req.PromoCode)
+	if err != nil {
+		return 0, err
+	}
+	return promo.Price(promoCode, price), nil
+}
+
+func (s *Service) handleRegisterPage(w http.ResponseWriter, r *http.Request) {
+	event, err := s.openEvent(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.renderError(w, r, "Failed to open registration", err)
+		return
+	}
+
+	event, err = s.translateEvent(r.Context(), event, language(w, r))
+	if err != nil {
+		s.renderError(w, r, "Failed to get translation", apperr.FromDB(err))
+		return
+	}
+
+	data := registerPageData{Event: event, PromoCode: promo.Normalize(r.URL.Query().Get("promo"))}
+	if data.TicketTypes, err = ticketTypeOptions(r.Context(), s.store, event.ID); err != nil {
+		s.renderError(w, r, "Failed to get ticket types", apperr.FromDB(err))
+		return
+	}
+	if len(data.TicketTypes) == 0 && event.TicketPrice > 0 {
+		data.Price = formatPrice(event.TicketPrice)
+	}
+	s.runTemplate(w, r, "register", data)
+}
+
+func (s *Service) handleRegister(w http.ResponseWriter, r *http.Request) {
+	event, err := s.openEvent(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.renderError(w, r, "Failed to open registration", err)
+		return
+	}
+
+	form := validate.NewForm(r)
+	ticketTypeID, _ := form.OptionalInt("ticket_type_id", 1, math.MaxInt)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid registration", err)
+		return
+	}
+
+	req := registrationRequest{
+		Name:         r.FormValue("name"),
+		Username:     r.FormValue("username"),
+		PromoCode:    r.FormValue("promo_code"),
+		TicketTypeID: int64(ticketTypeID),
+	}
+	price, err := s.price(r.Context(), event, req)
+	if err != nil {
+		s.renderError(w, r, "Invalid registration", apperr.FromDB(err))
+		return
+	}
+	if price > 0 {
+		s.startTicketOrder(w, r, event, req)
+		return
+	}
+
+	user, err := s.register(r.Context(), event, req, consent.SourceWebsite)
+	if errors.Is(err, capacity.ErrFull) {
+		position, err := s.joinWaitlist(r.Context(), event, req, consent.SourceWebsite)
+		if err != nil {
+			s.renderError(w, r, "Failed to join waitlist", err)
+			return
+		}
+		fmt.Fprintf(w, successHTML, fmt.Sprintf("Усі місця вже зайняті, тож тебе додано до листа очікування: ти №%d у черзі. Якщо місце звільниться, тебе зареєструють автоматично.", position))
+		return
+	}
+	if err != nil {
+		s.renderError(w, r, "Failed to register participant", err)
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, fmt.Sprintf("Дякуємо! Ти успішно зареєстрований. Твій номер квитка: №%d, код для входу: %s%s", user.TicketNumber, user.CheckInCode, seatText(s.seatLabel(r.Context(), user.ID))))
+}
+
+func (s *Service) handleAPIRegister(w http.ResponseWriter, r *http.Request) {
+	event, err := s.openEvent(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to open registration", err)
+		return
+	}
+
+	var req registrationRequest
+	if err := validate.JSON(r, &req); err != nil {
+		s.renderJSONError(w, r, "Failed to decode registration", err)
+		return
+	}
+	price, err := s.price(r.Context(), event, req)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid registration", apperr.FromDB(err))
+		return
+	}
+	if price > 0 {
+		s.renderJSONError(w, r, "Failed to open registration", apperr.Validation("This ticket is paid, register on the website"))
+		return
+	}
+
+	user, err := s.register(r.Context(), event, req, consent.SourceAPI)
+	if errors.Is(err, capacity.ErrFull) {
+		position, err := s.joinWaitlist(r.Context(), event, req, consent.SourceAPI)
+		if err != nil {
+			s.renderJSONError(w, r, "Failed to join waitlist", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(waitlistResponse{EventID: event.ID, WaitlistPosition: position})
+		return
+	}
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to register participant", err)
+		return
+	}
+
+	participant := newParticipantResponse(user)
+	participant.Seat = s.seatLabel(r.Context(), user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(participant)
+}
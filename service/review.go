@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+)
+
+const (
+	// maxTgIDGap is how close two Telegram account IDs have to be to count
+	// as accounts created in a row
+	maxTgIDGap = 20
+	// registrationBurst is how close in time such accounts have to register
+	registrationBurst = 10 * time.Minute
+)
+
+// flagReasons describes the suspicious-registration heuristics for admins.
+var flagReasons = map[string]string{
+	"same_phone":       "Однаковий номер телефону",
+	"duplicate_name":   "Однакове ім'я",
+	"sequential_tg_id": "Нові акаунти Telegram, зареєстровані поспіль",
+}
+
+type reviewData struct {
+	Event   *sqlc.Events
+	Users   []*sqlc.Users
+	Reasons map[string]string
+}
+
+// flagSuspicious runs the suspicious-registration heuristics over the
+// event's participants who haven't been reviewed yet.
+func flagSuspicious(ctx context.Context, st store.Store, eventID int64) error {
+	flagged, err := st.FlagSuspiciousUsers(ctx, &sqlc.FlagSuspiciousUsersParams{
+		EventID:      eventID,
+		MaxTgIDGap:   maxTgIDGap,
+		BurstSeconds: int32(registrationBurst.Seconds()),
+	})
+	if err == nil && flagged > 0 {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "Flagged suspicious registrations",
+			slog.Int64("event_id", eventID), slog.Int64("users", flagged))
+	}
+	return err
+}
+
+func (s *Service) reviewData(ctx context.Context, eventID int64) (*reviewData, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.store.GetFlaggedUsers(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &reviewData{Event: event, Users: users, Reasons: flagReasons}, nil
+}
+
+func (s *Service) handleReviewPage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	data, err := s.reviewData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get review queue", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_review", data)
+}
+
+func (s *Service) handleScanRegistrations(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	if err := flagSuspicious(r.Context(), s.store, eventID); err != nil {
+		s.renderError(w, r, "Failed to check registrations", apperr.FromDB(err))
+		return
+	}
+
+	s.renderReviewTable(w, r, eventID)
+}
+
+func (s *Service) handleApproveUser(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("userID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid user ID", apperr.Validation("Invalid user ID"))
+		return
+	}
+
+	if _, err := s.store.ApproveUser(r.Context(), &sqlc.ApproveUserParams{
+		ID:      userID,
+		EventID: eventID,
+	}); err != nil {
+		s.renderError(w, r, "Failed to approve participant", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Approved flagged participant",
+		slog.Int64("event_id", eventID), slog.Int64("user_id", userID))
+
+	s.renderReviewTable(w, r, eventID)
+}
+
+func (s *Service) handleRejectUser(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("userID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid user ID", apperr.Validation("Invalid user ID"))
+		return
+	}
+
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		return removeParticipant(r.Context(), tx, eventID, userID, consent.Deleted, consent.SourceAdmin)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to remove participant", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Removed flagged participant",
+		slog.Int64("event_id", eventID), slog.Int64("user_id", userID))
+
+	s.renderReviewTable(w, r, eventID)
+}
+
+func (s *Service) renderReviewTable(w http.ResponseWriter, r *http.Request, eventID int64) {
+	data, err := s.reviewData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get review queue", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_review_table", data)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/validate"
+)
+
+type entryRulesData struct {
+	Event *sqlc.Events
+	Rules []*sqlc.EntryRules
+}
+
+func (s *Service) renderEntryRules(w http.ResponseWriter, r *http.Request, eventID int64) {
+	data, err := s.entryRulesData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get entry rules", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_entry_rules", data)
+}
+
+func (s *Service) entryRulesData(ctx context.Context, eventID int64) (*entryRulesData, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.store.GetEntryRulesByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &entryRulesData{Event: event, Rules: rules}, nil
+}
+
+func (s *Service) handleCreateEntryRule(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	arg := &sqlc.CreateEntryRuleParams{
+		EventID: eventID,
+		Name:    form.RequiredText("name", maxNameLength),
+		Bonus:   int32(form.Int("bonus", 1, maxBonusEntries)),
+	}
+	if maxTicket, ok := form.OptionalInt("max_ticket", 1, math.MaxInt32); ok {
+		arg.MaxTicket = sql.NullInt32{Int32: int32(maxTicket), Valid: true}
+	}
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid rule", err)
+		return
+	}
+
+	if v := r.FormValue("registered_before"); v != "" {
+		before, err := time.Parse("2006-01-02T15:04", v)
+		if err != nil {
+			s.renderError(w, r, "Invalid rule", apperr.Validation("Invalid date"))
+			return
+		}
+		arg.RegisteredBefore = sql.NullTime{Time: before, Valid: true}
+	}
+
+	if !arg.MaxTicket.Valid && !arg.RegisteredBefore.Valid {
+		s.renderError(w, r, "Invalid rule", apperr.Validation("Set a ticket limit or a date"))
+		return
+	}
+
+	rule, err := s.store.CreateEntryRule(r.Context(), arg)
+	if err != nil {
+		s.renderError(w, r, "Failed to create entry rule", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Created entry rule",
+		slog.Int64("event_id", eventID), slog.Int64("rule_id", rule.ID))
+
+	s.renderEntryRules(w, r, eventID)
+}
+
+func (s *Service) handleDeleteEntryRule(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	ruleID, err := strconv.ParseInt(r.PathValue("ruleID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid rule ID", apperr.Validation("Invalid rule ID"))
+		return
+	}
+
+	if err := s.store.DeleteEntryRule(r.Context(), &sqlc.DeleteEntryRuleParams{
+		ID:      ruleID,
+		EventID: eventID,
+	}); err != nil {
+		s.renderError(w, r, "Failed to delete entry rule", apperr.FromDB(err))
+		return
+	}
+
+	s.renderEntryRules(w, r, eventID)
+}
+
+// handleRecalculateEntryBonuses applies the event's current rules to
+// everyone already registered.
+func (s *Service) handleRecalculateEntryBonuses(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	updated, err := s.store.RecalculateEntryBonuses(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to recalculate bonuses", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Recalculated entry bonuses",
+		slog.Int64("event_id", eventID), slog.Int64("users", updated))
+
+	fmt.Fprintf(w, successHTML, fmt.Sprintf("Бонуси перераховано для %d учасників. Онови сторінку, щоб побачити зміни.", updated))
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/notify"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+// broadcastInterval is how often due scheduled broadcasts are queued, and
+// so how late one may be sent.
+const broadcastInterval = 15 * time.Second
+
+// sendScheduledBroadcasts queues scheduled broadcasts once they are due
+// until ctx is done.
+func (s *Service) sendScheduledBroadcasts(ctx context.Context) {
+	ticker := time.NewTicker(broadcastInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.queueDueBroadcasts(ctx, now); err != nil {
+				s.logger.LogAttrs(ctx, slog.LevelError, "Failed to queue scheduled broadcasts", slog.Any("error", err))
+				notify.JobFailed("заплановані розсилки", err)
+			}
+		}
+	}
+}
+
+func (s *Service) queueDueBroadcasts(ctx context.Context, now time.Time) error {
+	broadcasts, err := s.store.GetDueBroadcasts(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, broadcast := range broadcasts {
+		// Claiming the broadcast and queueing its messages together means
+		// it's sent once even if several instances are running
+		err := s.store.InTx(ctx, func(tx store.Store) error {
+			claimed, err := tx.MarkBroadcastQueued(ctx, broadcast.ID)
+			if err != nil || claimed == 0 {
+				return err
+			}
+
+			queued, err := queueBroadcast(ctx, tx, broadcast)
+			if err != nil {
+				return err
+			}
+			s.logger.LogAttrs(ctx, slog.LevelInfo, "Queued scheduled broadcast",
+				slog.Int64("broadcast_id", broadcast.ID), slog.Int64("event_id", broadcast.EventID), slog.Int("recipients", queued))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type scheduledBroadcastsData struct {
+	Event      *sqlc.Events
+	Broadcasts []*sqlc.Broadcasts
+	TimeZone   string
+}
+
+func (s *Service) scheduledBroadcastsData(ctx context.Context, eventID int64) (*scheduledBroadcastsData, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	broadcasts, err := s.store.GetBroadcastsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &scheduledBroadcastsData{
+		Event:      event,
+		Broadcasts: broadcasts,
+		TimeZone:   time.Now().Format("MST"),
+	}, nil
+}
+
+func (s *Service) renderScheduledBroadcasts(w http.ResponseWriter, r *http.Request, eventID int64) {
+	data, err := s.scheduledBroadcastsData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get scheduled broadcasts", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_scheduled_broadcasts", data)
+}
+
+// broadcastForm reads a scheduled broadcast's text and send time. The time
+// is in the server's time zone, like the digest schedule.
+func broadcastForm(r *http.Request) (string, time.Time, error) {
+	form := validate.NewForm(r)
+	text := form.RequiredText("text", maxMessageLength)
+	if err := form.Err(); err != nil {
+		return "", time.Time{}, err
+	}
+
+	sendAt, err := time.ParseInLocation("2006-01-02T15:04", r.FormValue("send_at"), time.Local)
+	if err != nil {
+		return "", time.Time{}, apperr.Validation("Invalid send time")
+	}
+	if !sendAt.After(time.Now()) {
+		return "", time.Time{}, apperr.Validation("Send time must be in the future")
+	}
+	return text, sendAt, nil
+}
+
+// handleScheduleBroadcast saves a broadcast to be queued at the chosen time.
+func (s *Service) handleScheduleBroadcast(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	text, sendAt, err := broadcastForm(r)
+	if err != nil {
+		s.renderError(w, r, "Invalid broadcast", err)
+		return
+	}
+
+	if _, err := s.store.CreateBroadcast(r.Context(), &sqlc.CreateBroadcastParams{
+		EventID: eventID,
+		Text:    text,
+		SendAt:  sendAt,
+	}); err != nil {
+		s.renderError(w, r, "Failed to schedule broadcast", apperr.FromDB(err))
+		return
+	}
+
+	s.renderScheduledBroadcasts(w, r, eventID)
+}
+
+// handleUpdateBroadcast changes a scheduled broadcast that hasn't been
+// sent yet.
+func (s *Service) handleUpdateBroadcast(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+	broadcastID, err := strconv.ParseInt(r.PathValue("broadcastID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid broadcast ID", apperr.Validation("Invalid broadcast ID"))
+		return
+	}
+
+	text, sendAt, err := broadcastForm(r)
+	if err != nil {
+		s.renderError(w, r, "Invalid broadcast", err)
+		return
+	}
+
+	updated, err := s.store.UpdateBroadcast(r.Context(), &sqlc.UpdateBroadcastParams{
+		ID:      broadcastID,
+		EventID: eventID,
+		Text:    text,
+		SendAt:  sendAt,
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update broadcast", apperr.FromDB(err))
+		return
+	}
+	if updated == 0 {
+		s.renderError(w, r, "Failed to update broadcast", apperr.Conflict("Broadcast was already sent or cancelled"))
+		return
+	}
+
+	s.renderScheduledBroadcasts(w, r, eventID)
+}
+
+// handleCancelBroadcast deletes a scheduled broadcast that hasn't been
+// sent yet.
+func (s *Service) handleCancelBroadcast(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+	broadcastID, err := strconv.ParseInt(r.PathValue("broadcastID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid broadcast ID", apperr.Validation("Invalid broadcast ID"))
+		return
+	}
+
+	deleted, err := s.store.DeleteBroadcast(r.Context(), &sqlc.DeleteBroadcastParams{ID: broadcastID, EventID: eventID})
+	if err != nil {
+		s.renderError(w, r, "Failed to cancel broadcast", apperr.FromDB(err))
+		return
+	}
+	if deleted == 0 {
+		s.renderError(w, r, "Failed to cancel broadcast", apperr.Conflict("Broadcast was already sent or cancelled"))
+		return
+	}
+
+	s.renderScheduledBroadcasts(w, r, eventID)
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/seating"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+const (
+	// maxSeatsPerRequest caps the seats added at once
+	maxSeatsPerRequest = 1000
+	maxSeatLabelLength = 20
+)
+
+// checkIn marks the participant as arrived and returns the label of the
+// seat they are to take, or "" if they have none. They are seated first if
+// the event seats participants at check-in.
+func (s *Service) checkIn(ctx context.Context, user *sqlc.Users) (*sqlc.Users, string, error) {
+	var seat *sqlc.Seats
+	err := s.store.InTx(ctx, func(tx store.Store) error {
+		var err error
+		if user, err = tx.CheckInUser(ctx, user.ID); err != nil {
+			return err
+		}
+		seat, err = seating.Assign(ctx, tx, user, seating.AtCheckIn)
+		return err
+	})
+	if err != nil || seat == nil {
+		return user, "", err
+	}
+	return user, seat.Label, nil
+}
+
+// seatLabel returns the participant's seat for showing it next to their
+// ticket, or "" if they have none. Failures only leave the seat out, since
+// it is shown again at check-in.
+func (s *Service) seatLabel(ctx context.Context, userID int64) string {
+	seat, err := seating.Of(ctx, s.store, userID)
+	if err != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get seat",
+			slog.Int64("user_id", userID), slog.Any("error", err))
+		return ""
+	}
+	if seat == nil {
+		return ""
+	}
+	return seat.Label
+}
+
+// seatText is the seat part of the messages showing a participant's
+// ticket.
+func seatText(label string) string {
+	if label == "" {
+		return ""
+	}
+	return ", місце: " + label
+}
+
+// userSeat is a participant's seat in the participants table.
+type userSeat struct {
+	EventID int64
+	UserID  int64
+	// Label is "" for participants without a seat
+	Label string
+}
+
+// userSeats maps the event's participants to their seats for the
+// participants table. It is nil for events without seats.
+func userSeats(eventID int64, users []*sqlc.Users, seats []*sqlc.GetSeatsByEventIDRow) map[int64]*userSeat {
+	if len(seats) == 0 {
+		return nil
+	}
+	result := make(map[int64]*userSeat, len(users))
+	for _, user := range users {
+		result[user.ID] = &userSeat{EventID: eventID, UserID: user.ID}
+	}
+	for _, seat := range seats {
+		if s, ok := result[seat.Seats.UserID.Int64]; ok && seat.Seats.UserID.Valid {
+			s.Label = seat.Seats.Label
+		}
+	}
+	return result
+}
+
+// seatLabels maps participants to the labels of their seats.
+func seatLabels(seats []*sqlc.GetSeatsByEventIDRow) map[int64]string {
+	labels := make(map[int64]string, len(seats))
+	for _, seat := range seats {
+		if seat.Seats.UserID.Valid {
+			labels[seat.Seats.UserID.Int64] = seat.Seats.Label
+		}
+	}
+	return labels
+}
+
+type seatsData struct {
+	Event *sqlc.Events
+	Seats []*sqlc.GetSeatsByEventIDRow
+	// Free is how many seats are free and Unseated how many participants
+	// have no seat
+	Free     int
+	Unseated int64
+}
+
+func (s *Service) seatsData(ctx context.Context, eventID int64) (*seatsData, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	seats, err := s.store.GetSeatsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	participants, err := s.store.CountUsersByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &seatsData{Event: event, Seats: seats, Unseated: participants}
+	for _, seat := range seats {
+		if seat.Seats.UserID.Valid {
+			data.Unseated--
+		} else {
+			data.Free++
+		}
+	}
+	return data, nil
+}
+
+func (s *Service) renderSeats(w http.ResponseWriter, r *http.Request, eventID int64) {
+	data, err := s.seatsData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get seats", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_seats_table", data)
+}
+
+// handleSeatsPage lists the event's seats with who sits in them.
+func (s *Service) handleSeatsPage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	data, err := s.seatsData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get seats", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_seats", data)
+}
+
+// handleCreateSeats adds seats from a list of labels, or a row of them
+// when a row prefix and count are given. Labels the event already has are
+// skipped.
+func (s *Service) handleCreateSeats(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	labels := form.List("labels", maxSeatsPerRequest, maxSeatLabelLength)
+	row := form.Text("row", maxSeatLabelLength-4)
+	if count, ok := form.OptionalInt("count", 1, maxSeatsPerRequest); ok {
+		labels = append(labels, seating.Row(row, count)...)
+	}
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid seats", err)
+		return
+	}
+	if len(labels) == 0 {
+		s.renderError(w, r, "Invalid seats", apperr.Validation("Enter seat labels or a row"))
+		return
+	}
+	if len(labels) > maxSeatsPerRequest {
+		s.renderError(w, r, "Invalid seats", apperr.Validation(fmt.Sprintf("At most %d seats can be added at once", maxSeatsPerRequest)))
+		return
+	}
+
+	created, err := s.store.CreateSeats(r.Context(), &sqlc.CreateSeatsParams{EventID: eventID, Labels: labels})
+	if err != nil {
+		s.renderError(w, r, "Failed to create seats", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Created seats",
+		slog.Int64("event_id", eventID), slog.Int64("created", created))
+
+	s.renderSeats(w, r, eventID)
+}
+
+// handleDeleteSeat deletes a seat. Its participant is left without one.
+func (s *Service) handleDeleteSeat(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	seatID, err := strconv.ParseInt(r.PathValue("seatID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid seat ID", apperr.Validation("Invalid seat ID"))
+		return
+	}
+
+	if err := s.store.DeleteSeat(r.Context(), &sqlc.DeleteSeatParams{ID: seatID, EventID: eventID}); err != nil {
+		s.renderError(w, r, "Failed to delete seat", apperr.FromDB(err))
+		return
+	}
+
+	s.renderSeats(w, r, eventID)
+}
+
+// handleSetSeatAssignment sets when participants of the event are seated.
+func (s *Service) handleSetSeatAssignment(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	assignment := r.FormValue("seat_assignment")
+	if !seating.ValidAssignment(assignment) {
+		s.renderError(w, r, "Invalid seat assignment", apperr.Validation("Invalid seat assignment"))
+		return
+	}
+
+	if _, err := s.store.SetEventSeatAssignment(r.Context(), &sqlc.SetEventSeatAssignmentParams{
+		ID:             eventID,
+		SeatAssignment: assignment,
+	}); err != nil {
+		s.renderError(w, r, "Failed to update seat assignment", apperr.FromDB(err))
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, "Збережено")
+}
+
+// handleSeatEveryone gives free seats to the participants without one, in
+// order of registration, for events seated by hand or that got their seats
+// after registration opened.
+func (s *Service) handleSeatEveryone(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	seated := 0
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		users, err := tx.GetUsersByEventID(r.Context(), eventID)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			seat, err := seating.AssignFree(r.Context(), tx, user)
+			if err != nil {
+				return err
+			}
+			if seat != nil {
+				seated++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to seat participants", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Seated participants",
+		slog.Int64("event_id", eventID), slog.Int("seated", seated))
+
+	s.renderSeats(w, r, eventID)
+}
+
+// eventUser returns the participant of a user row route.
+func (s *Service) eventUser(r *http.Request) (*sqlc.Users, error) {
+	eventID, err := strconv.ParseInt(r.PathValue("eventID"), 10, 64)
+	if err != nil {
+		return nil, apperr.Validation("Invalid event ID")
+	}
+	userID, err := strconv.ParseInt(r.PathValue("userID"), 10, 64)
+	if err != nil {
+		return nil, apperr.Validation("Invalid user ID")
+	}
+
+	user, err := s.store.GetUserByID(r.Context(), userID)
+	if err != nil {
+		return nil, apperr.FromDB(err)
+	}
+	if user.EventID != eventID {
+		return nil, apperr.NotFound("Participant not found")
+	}
+	return user, nil
+}
+
+// handleSetUserSeat moves a participant to the seat with the given label,
+// swapping with whoever sat there.
+func (s *Service) handleSetUserSeat(w http.ResponseWriter, r *http.Request) {
+	user, err := s.eventUser(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get participant", err)
+		return
+	}
+
+	label := strings.TrimSpace(r.FormValue("seat"))
+	if label == "" {
+		s.renderError(w, r, "Invalid seat", apperr.Validation("Enter a seat"))
+		return
+	}
+
+	var swapped int64
+	if err := s.store.InTx(r.Context(), func(tx store.Store) error {
+		var err error
+		swapped, err = seating.Move(r.Context(), tx, user, label)
+		return err
+	}); err != nil {
+		s.renderError(w, r, "Failed to move participant", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Moved participant",
+		slog.Int64("user_id", user.ID), slog.String("seat", label), slog.Int64("swapped_with", swapped))
+
+	// The other participant's row changed too
+	if swapped != 0 {
+		w.Header().Set("HX-Refresh", "true")
+	}
+	s.runTemplate(w, r, "admin_user_seat", &userSeat{EventID: user.EventID, UserID: user.ID, Label: label})
+}
+
+// handleReleaseUserSeat frees a participant's seat.
+func (s *Service) handleReleaseUserSeat(w http.ResponseWriter, r *http.Request) {
+	user, err := s.eventUser(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get participant", err)
+		return
+	}
+
+	if err := s.store.ReleaseSeat(r.Context(), user.ID); err != nil {
+		s.renderError(w, r, "Failed to release seat", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_user_seat", &userSeat{EventID: user.EventID, UserID: user.ID})
+}
+
+type badge struct {
+	User       *sqlc.Users
+	TicketType string
+	Seat       string
+}
+
+type badgesData struct {
+	Event  *sqlc.Events
+	Badges []badge
+}
+
+// handleBadges shows a printable badge for every participant, with their
+// ticket number, type and seat.
+func (s *Service) handleBadges(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	users, err := s.store.GetUsersByEventID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get users", apperr.FromDB(err))
+		return
+	}
+	ticketTypes, err := s.store.GetTicketTypesByEventID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get ticket types", apperr.FromDB(err))
+		return
+	}
+	seats, err := s.store.GetSeatsByEventID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get seats", apperr.FromDB(err))
+		return
+	}
+
+	typeNames := make(map[int64]string, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		typeNames[ticketType.ID] = ticketType.Name
+	}
+	labels := seatLabels(seats)
+
+	data := badgesData{Event: event, Badges: make([]badge, 0, len(users))}
+	for _, user := range users {
+		data.Badges = append(data.Badges, badge{
+			User:       user,
+			TicketType: typeNames[user.TicketTypeID.Int64],
+			Seat:       labels[user.ID],
+		})
+	}
+
+	s.runTemplate(w, r, "admin_badges", data)
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+type selfServiceData struct {
+	User  *sqlc.Users
+	Event *sqlc.Events
+	Token string
+	// Seat is the participant's seat, or "" if they have none
+	Seat string
+	// EntriesLeft is how many extra entries the participant can still buy,
+	// 0 when purchases are disabled.
+	EntriesLeft int32
+	EntryPrice  string
+}
+
+// linkParticipant returns the participant a self-service link was issued
+// for. Links of cancelled registrations stop working, since the participant
+// no longer exists.
+func (s *Service) linkParticipant(ctx context.Context, token string) (*sqlc.Users, *sqlc.Events, error) {
+	if s.links == nil {
+		return nil, nil, apperr.NotFound("Self-service is not available")
+	}
+
+	userID, err := s.links.Verify(token)
+	if err != nil {
+		return nil, nil, apperr.Unauthorized("This link is invalid or has expired")
+	}
+
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, nil, apperr.FromDB(err)
+	}
+
+	event, err := s.store.GetEventByID(ctx, user.EventID)
+	if err != nil {
+		return nil, nil, apperr.FromDB(err)
+	}
+	return user, event, nil
+}
+
+// changeableParticipant is linkParticipant for changes, which are only
+// allowed until the event starts.
+func (s *Service) changeableParticipant(ctx context.Context, token string) (*sqlc.Users, *sqlc.Events, error) {
+	user, event, err := s.linkParticipant(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if event.Date.Before(time.Now()) {
+		return nil, nil, apperr.Validation("The event has already started")
+	}
+	return user, event, nil
+}
+
+func (s *Service) handleSelfServicePage(w http.ResponseWriter, r *http.Request) {
+	user, event, err := s.linkParticipant(r.Context(), r.PathValue("token"))
+	if err != nil {
+		s.renderError(w, r, "Failed to open self-service page", err)
+		return
+	}
+
+	data := selfServiceData{
+		User:  user,
+		Event: event,
+		Token: r.PathValue("token"),
+		Seat:  s.seatLabel(r.Context(), user.ID),
+	}
+	if s.purchasesEnabled(event) {
+		data.EntriesLeft = max(event.MaxPaidEntries-user.PaidEntries, 0)
+		data.EntryPrice = formatPrice(event.EntryPrice)
+	}
+
+	s.runTemplate(w, r, "self_service", data)
+}
+
+func (s *Service) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.changeableParticipant(r.Context(), r.PathValue("token"))
+	if err != nil {
+		s.renderError(w, r, "Failed to open self-service page", err)
+		return
+	}
+
+	form := validate.NewForm(r)
+	name := form.RequiredText("name", maxNameLength)
+	phone := form.Text("phone", maxPhoneLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid profile", err)
+		return
+	}
+	if strings.Trim(phone, "+0123456789 -()") != "" {
+		s.renderError(w, r, "Invalid phone", apperr.Validation("Invalid phone number"))
+		return
+	}
+
+	if _, err := s.store.UpdateUserProfile(r.Context(), &sqlc.UpdateUserProfileParams{
+		ID:    user.ID,
+		Name:  name,
+		Phone: phone,
+	}); err != nil {
+		s.renderError(w, r, "Failed to update profile", apperr.FromDB(err))
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, "Дані збережено")
+}
+
+func (s *Service) handleConfirmAttendance(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.changeableParticipant(r.Context(), r.PathValue("token"))
+	if err != nil {
+		s.renderError(w, r, "Failed to open self-service page", err)
+		return
+	}
+
+	if _, err := s.store.ConfirmUserAttendance(r.Context(), user.ID); err != nil {
+		s.renderError(w, r, "Failed to confirm attendance", apperr.FromDB(err))
+		return
+	}
+
+	fmt.Fprintf(w, successHTML, "Дякуємо! Чекаємо на тебе")
+}
+
+func (s *Service) handleCancelRegistration(w http.ResponseWriter, r *http.Request) {
+	user, event, err := s.changeableParticipant(r.Context(), r.PathValue("token"))
+	if err != nil {
+		s.renderError(w, r, "Failed to open self-service page", err)
+		return
+	}
+
+	// Payments are refunded first, so a participant whose refund fails
+	// keeps their place and can try again
+	refunded, err := s.refundParticipant(r.Context(), event.ID, user.ID)
+	if err != nil {
+		s.renderError(w, r, "Failed to refund payments", apperr.FromDB(err))
+		return
+	}
+
+	if err := s.store.InTx(r.Context(), func(tx store.Store) error {
+		return removeParticipant(r.Context(), tx, event.ID, user.ID, consent.DeletionRequested, consent.SourceSelfService)
+	}); err != nil {
+		s.renderError(w, r, "Failed to cancel registration", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Participant cancelled registration",
+		slog.Int64("event_id", event.ID), slog.Int64("user_id", user.ID), slog.Int("refunded", int(refunded)))
+
+	if refunded > 0 {
+		fmt.Fprintf(w, successHTML, fmt.Sprintf("Реєстрацію скасовано, %s повернуто", formatPrice(refunded)))
+		return
+	}
+	fmt.Fprintf(w, successHTML, "Реєстрацію скасовано")
+}
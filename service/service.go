@@ -1,23 +1,47 @@
 package service
 
 import (
+	"bytes"
 	"context"
-	cryptoRand "crypto/rand"
 	"database/sql"
 	"embed"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log/slog"
-	"math/rand/v2"
+	"math"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/authz"
+	"giveaway-tool/capacity"
 	"giveaway-tool/config"
+	"giveaway-tool/consent"
 	"giveaway-tool/database/sqlc"
+	"giveaway-tool/demo"
+	"giveaway-tool/gcal"
+	"giveaway-tool/i18n"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/logging"
+	"giveaway-tool/magiclink"
+	"giveaway-tool/markdown"
+	"giveaway-tool/payments"
+	"giveaway-tool/router"
+	"giveaway-tool/sessionstore"
+	"giveaway-tool/slug"
+	"giveaway-tool/static"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
 
 	"github.com/gorilla/sessions"
 	"github.com/skip2/go-qrcode"
@@ -26,90 +50,110 @@ import (
 //go:embed templates
 var templates embed.FS
 
+// requestTimeout bounds how long a single request may spend on database work.
+const requestTimeout = 15 * time.Second
+
+// publicPageMaxAge is how long browsers and proxies may show a public page
+// before asking whether it changed.
+const publicPageMaxAge = 30 * time.Second
+
+// dashboardPageSize and participantsPageSize are how many events and
+// participants the admin pages show before "load more".
+const (
+	dashboardPageSize    = 20
+	participantsPageSize = 100
+	auditPageSize        = 50
+)
+
 type Service struct {
 	router       *http.ServeMux
 	logger       *slog.Logger
 	tmpl         *template.Template
-	queries      *sqlc.Queries
-	sessionStore *sessions.CookieStore
-	adminData    *AdminData
-}
-
-// generateRandomKey generates a random key for session encryption
-func generateRandomKey(length int) ([]byte, error) {
-	key := make([]byte, length)
-	_, err := cryptoRand.Read(key)
-	if err != nil {
-		return nil, err
-	}
-	return key, nil
+	store        store.Store
+	sessionStore *sessionstore.Store
+
+	liveDraws        liveDraws
+	checkInCodeFails failureLimiter
+	// ipLimiter and keyLimiter rate limit the public API and registration
+	// form; either is nil when its limit is disabled
+	ipLimiter  *tokenBucket
+	keyLimiter *tokenBucket
+	// lastErrorNotice is the Unix time of the last server error
+	// notification
+	lastErrorNotice atomic.Int64
+	// checkInAPIKey authenticates scanner apps; the check-in API is
+	// disabled when it is empty
+	checkInAPIKey string
+	// adminAPIKey authenticates tools managing events through the JSON
+	// API; the API is disabled when it is empty
+	adminAPIKey string
+	// links verifies participants' self-service links; the page is
+	// disabled when it is nil
+	links *magiclink.Signer
+	// payments sells tickets and extra raffle entries; both are disabled
+	// when it is nil
+	payments payments.Provider
+	// publicURL is where participants come back to after paying; paid
+	// tickets are disabled when it is empty
+	publicURL string
+	// botUsername is the Telegram bot participants register with; pages
+	// don't link to the bot when it is empty
+	botUsername string
+	// archiveAfter is how long after its date an event is archived; events
+	// are never archived when it is zero
+	archiveAfter time.Duration
+	// announcementChannel is the Telegram channel winner announcements are
+	// posted to; posting is disabled when it is 0
+	announcementChannel int64
+	// calendar is the community Google Calendar events are synced to;
+	// syncing is disabled when it is nil
+	calendar *gcal.Client
+	// webhookSecret signs webhook calls; they are sent unsigned when it is
+	// empty
+	webhookSecret string
+	// stopping is closed when the server starts shutting down, so streams
+	// end instead of holding up the shutdown
+	stopping <-chan struct{}
 }
 
-func Start(router *http.ServeMux, logger *slog.Logger, db *sql.DB) {
-	// Get session key from environment or generate a new one
-	var sessionKey []byte
-	sessionKeyStr := os.Getenv("SESSION_KEY")
-
-	if sessionKeyStr != "" {
-		// If provided in environment, decode from base64
-		var err error
-		sessionKey, err = base64.StdEncoding.DecodeString(sessionKeyStr)
-		if err != nil {
-			logger.LogAttrs(context.Background(), slog.LevelError,
-				"Failed to decode SESSION_KEY from base64, generating a new one",
-				slog.Any("error", err))
-			sessionKey = nil
-		}
-	}
-
-	// If we don't have a valid key yet, generate one
-	if len(sessionKey) < 32 {
-		var err error
-		sessionKey, err = generateRandomKey(32)
-		if err != nil {
-			logger.LogAttrs(context.Background(), slog.LevelError,
-				"Failed to generate random session key", slog.Any("error", err))
-			panic(err)
-		}
-
-		// Log the generated key so it can be saved for future use
-		encodedKey := base64.StdEncoding.EncodeToString(sessionKey)
-		logger.LogAttrs(context.Background(), slog.LevelWarn,
-			"Generated new session key. For persistence across restarts, set SESSION_KEY environment variable",
-			slog.String("generated_key", encodedKey))
-	}
-
-	// Get admin credentials from environment or use defaults
-	adminUsername := os.Getenv("ADMIN_USERNAME")
-	if adminUsername == "" {
-		adminUsername = "admin"
-		logger.LogAttrs(context.Background(), slog.LevelWarn,
-			"Using default admin username. Set ADMIN_USERNAME environment variable in production.")
-	}
-
-	adminPassword := os.Getenv("ADMIN_PASSWORD")
-	if adminPassword == "" {
-		adminPassword = "password"
-		logger.LogAttrs(context.Background(), slog.LevelWarn,
-			"Using default admin password. Set ADMIN_PASSWORD environment variable in production.")
-	}
-
+func Start(ctx context.Context, mux *http.ServeMux, logger *slog.Logger, st store.Store, botUsername string) {
 	svc := &Service{
-		router:       router,
-		logger:       logger,
-		queries:      sqlc.New(db),
-		sessionStore: sessions.NewCookieStore(sessionKey),
-		adminData: &AdminData{
-			Username: adminUsername,
-			Password: adminPassword,
-		},
-	}
-
-	// Configure session store
+		router:              mux,
+		logger:              logger,
+		store:               st,
+		sessionStore:        sessionstore.New(st),
+		checkInAPIKey:       os.Getenv("CHECKIN_API_KEY"),
+		adminAPIKey:         os.Getenv("ADMIN_API_KEY"),
+		links:               magiclink.FromEnv(),
+		payments:            payments.FromEnv(),
+		publicURL:           strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/"),
+		botUsername:         botUsername,
+		ipLimiter:           newTokenBucket(rateLimitFromEnv(ctx, logger, "RATE_LIMIT_PER_IP", defaultRateLimitPerIP)),
+		keyLimiter:          newTokenBucket(rateLimitFromEnv(ctx, logger, "RATE_LIMIT_PER_KEY", defaultRateLimitPerKey)),
+		archiveAfter:        archiveAfterFromEnv(ctx, logger),
+		announcementChannel: announcementChannelFromEnv(ctx, logger),
+		calendar:            calendarFromEnv(ctx, logger),
+		webhookSecret:       os.Getenv("WEBHOOK_SECRET"),
+		stopping:            ctx.Done(),
+	}
+
+	if missing := static.Missing(); len(missing) > 0 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "Static assets not built, pages load htmx from its CDN and have no styles. Run go generate ./static",
+			slog.Any("missing", missing))
+	}
+
+	if err := svc.bootstrapAdmin(ctx); err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "Failed to create owner account from environment", slog.Any("error", err))
+	}
+
+	// Configure session store. With TLS_DOMAINS the app serves HTTPS
+	// itself, so the cookie is only sent back over HTTPS. Behind an HTTPS
+	// proxy, sessionstore.Save marks it so per request
 	svc.sessionStore.Options = &sessions.Options{
 		Path:     "/",
-		MaxAge:   86400 * 7, // 1 week
+		MaxAge:   sessionMaxAgeFromEnv(ctx, logger),
 		HttpOnly: true,
+		Secure:   os.Getenv("TLS_DOMAINS") != "",
 	}
 
 	tmpl := template.New("base")
@@ -119,15 +163,27 @@ func Start(router *http.ServeMux, logger *slog.Logger, db *sql.DB) {
 		"toJSON": func(v any) string {
 			b, err := json.Marshal(v)
 			if err != nil {
-				svc.logger.LogAttrs(context.Background(), slog.LevelError, "Failed to marshal to JSON", slog.Any("error", err))
+				svc.logger.LogAttrs(ctx, slog.LevelError, "Failed to marshal to JSON", slog.Any("error", err))
 				return ""
 			}
 			return string(b)
 		},
 		"now": time.Now,
+		"can": authz.Allowed,
 		"formatFloat": func(f float64) string {
 			return fmt.Sprintf("%.2f", f)
 		},
+		"formatPrice":  formatPrice,
+		"demoMode":     demo.Enabled,
+		"markdown":     markdown.HTML,
+		"languages":    func() []i18n.Language { return i18n.Languages },
+		"consentText":  func() string { return consent.Text },
+		"statusLabel":  statusLabel,
+		"nextStatuses": lifecycle.Next,
+		"registrationOpen": func(event *sqlc.Events) bool {
+			return lifecycle.CheckOpen(event) == nil
+		},
+		"asset": static.URL,
 	})
 
 	// Parse templates
@@ -135,72 +191,403 @@ func Start(router *http.ServeMux, logger *slog.Logger, db *sql.DB) {
 
 	svc.tmpl = tmpl
 
+	proxy := router.ProxyFromEnv()
+	base := router.New(svc.router, router.RealIP(proxy), router.Logging(logger), router.Tracing, router.Recovery(svc.renderError), router.Compress, router.Timeout(requestTimeout))
+	// The streams stay open as long as the admin panel does, so they skip
+	// the request timeout
+	streams := router.New(svc.router, router.RealIP(proxy), router.Logging(logger), router.Tracing, router.Recovery(svc.renderError), svc.requireRole(authz.Viewer))
+	streams.HandleFunc("GET /admin/notifications/stream", svc.handleNotificationStream)
+	streams.HandleFunc("GET /admin/events/{id}/stream", svc.handleEventStream)
+	streams.HandleFunc("GET /admin/events/{id}/live-draw/ws", svc.handleLiveDrawSocket)
+	root := base.Group(router.MaxBodySize(maxBodySize))
+
+	// CSS and JS of the pages, cached by browsers until they change
+	base.Handle("GET "+static.Prefix, static.Handler())
+
 	// Public routes
-	svc.router.HandleFunc("GET /", svc.handleEvents)
-	svc.router.HandleFunc("GET /login", svc.handleLoginPage)
-	svc.router.HandleFunc("POST /login", svc.handleLogin)
-	svc.router.HandleFunc("GET /logout", svc.handleLogout)
+	public := root.Group(router.CSRF(svc.renderError))
+	pages := public.Group(router.Cache(publicPageMaxAge))
+	pages.HandleFunc("GET /", svc.handleEvents)
+	pages.HandleFunc("GET /events/{id}/image", svc.handleEventImage)
+	pages.HandleFunc("GET /events/{slug}", svc.handleEventPage)
+	pages.HandleFunc("GET /events.ics", svc.handleEventsCalendar)
+	pages.HandleFunc("GET /events.atom", svc.handleEventsFeed)
+	// Registration counters other sites show in an iframe or read as JSON
+	pages.HandleFunc("GET /embed/events/{id}/counter", svc.handleEmbedCounter)
+	pages.HandleFunc("GET /embed/events/{id}/counter.json", svc.handleEmbedCounterJSON)
+	public.HandleFunc("GET /login", svc.handleLoginPage)
+	public.HandleFunc("POST /login", svc.handleLogin)
+	public.Group(svc.rateLimit(svc.renderError)).HandleFunc("POST /login/link", svc.handleRequestLoginLink)
+	public.HandleFunc("GET /login/{token}", svc.handleLoginLink)
+	public.HandleFunc("GET /logout", svc.handleLogout)
 
 	//Public QR Code generator
-	svc.router.HandleFunc("GET /qr-code", svc.handleQRCodePage)
-	svc.router.HandleFunc("POST /qr-code", svc.handleQRCodeGeneration)
-
-	// Admin routes - protected by middleware
-	svc.router.HandleFunc("GET /admin", svc.requireAdmin(svc.handleAdminDashboard))
-	svc.router.HandleFunc("GET /admin/events/{id}", svc.requireAdmin(svc.handleGetEvent))
-	svc.router.HandleFunc("PUT /admin/events/{id}", svc.requireAdmin(svc.handleUpdateEvent))
-	svc.router.HandleFunc("POST /admin/events/{id}/current", svc.requireAdmin(svc.handleSetCurrentEvent))
-	svc.router.HandleFunc("POST /admin/events/{id}/winners", svc.requireAdmin(svc.handleGetWinners))
-	svc.router.HandleFunc("GET /admin/event", svc.requireAdmin(svc.handleCreateEventPage))
-	svc.router.HandleFunc("POST /admin/event", svc.requireAdmin(svc.handleCreateEvent))
-	svc.router.HandleFunc("DELETE /admin/events/{id}", svc.requireAdmin(svc.handleDeleteEvent))
-	svc.router.HandleFunc("DELETE /admin/events/{eventID}/users/{userID}", svc.requireAdmin(svc.handleDeleteEventUser))
-	svc.router.HandleFunc("PATCH /admin/events/{eventID}/users/{userID}", svc.requireAdmin(svc.handleUpdateUserCount))
-}
-
-// Middleware to check if user is admin
-func (s *Service) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		session, err := s.sessionStore.Get(r, "session")
-		if err != nil {
-			s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to get session", slog.Any("error", err))
-			http.Redirect(w, r, "/login", http.StatusSeeOther)
-			return
-		}
+	public.HandleFunc("GET /qr-code", svc.handleQRCodePage)
+	public.HandleFunc("POST /qr-code", svc.handleQRCodeGeneration)
+
+	// Winners of past events that opted in
+	pages.HandleFunc("GET /winners", svc.handleWinnersWall)
+
+	// Public registration, available when the public_registration flag is on
+	pages.HandleFunc("GET /events/{id}/register", svc.handleRegisterPage)
+	public.Group(svc.rateLimit(svc.renderError), svc.idempotent).HandleFunc("POST /events/{id}/register", svc.handleRegister)
+	// Buyers of paid tickets come back here from the payment page
+	public.HandleFunc("GET /orders/{orderID}", svc.handleTicketOrderPage)
+
+	// Kiosk pages authenticate with a per-event token instead of the admin session
+	public.HandleFunc("GET /kiosk/{id}", svc.handleKioskPage)
+	public.HandleFunc("POST /kiosk/{id}/register", svc.handleKioskRegister)
+	public.HandleFunc("POST /kiosk/{id}/checkin", svc.handleKioskCheckIn)
+
+	// Volunteer pages authenticate with a signed, time-limited access link
+	public.HandleFunc("GET /access/{token}", svc.handleAccessLink)
+	public.HandleFunc("GET /volunteer/events/{id}", svc.handleVolunteerPage)
+	public.HandleFunc("POST /volunteer/events/{id}/checkin", svc.handleVolunteerCheckIn)
+
+	// Participant self-service, opened with a signed link from the bot
+	public.HandleFunc("GET /me/{token}", svc.handleSelfServicePage)
+	public.HandleFunc("POST /me/{token}", svc.handleUpdateProfile)
+	public.HandleFunc("POST /me/{token}/confirm", svc.handleConfirmAttendance)
+	public.HandleFunc("POST /me/{token}/cancel", svc.handleCancelRegistration)
+	public.HandleFunc("POST /me/{token}/entries", svc.handleBuyEntries)
+
+	// Participants' share links
+	public.HandleFunc("GET /s/{code}", svc.handleShareLink)
+	// Short links of promo channels, uncached so every visit is counted
+	public.HandleFunc("GET /e/{code}", svc.handleShortLink)
+
+	// Admin routes - protected by middleware. Viewers may only look,
+	// moderators also look after participants and the rest needs an admin.
+	// Changes must carry the session's CSRF token.
+	viewer := root.Group(router.CSRF(svc.renderError), svc.requireRole(authz.Viewer), svc.requireCSRFToken)
+	moderator := root.Group(router.CSRF(svc.renderError), svc.requireRole(authz.Moderator), svc.requireCSRFToken)
+	admin := root.Group(router.CSRF(svc.renderError), svc.requireRole(authz.Admin), svc.requireCSRFToken)
+	viewer.HandleFunc("GET /admin", svc.handleAdminDashboard)
+	viewer.HandleFunc("GET /admin/events/{id}", svc.handleGetEvent)
+	viewer.HandleFunc("GET /admin/events/{id}/users", svc.handleGetEventUsers)
+	admin.HandleFunc("POST /admin/events/{id}/current", svc.handleSetCurrentEvent)
+	admin.HandleFunc("POST /admin/events/{id}/status", svc.handleSetEventStatus)
+	admin.HandleFunc("POST /admin/events/{id}/registration-window", svc.handleSetRegistrationWindow)
+	admin.HandleFunc("POST /admin/events/{id}/registration-window/close", svc.handleCloseRegistrationNow)
+	draw := admin.Group(svc.authorize(authz.RunDraw))
+	draw.HandleFunc("POST /admin/events/{id}/winners", svc.handleGetWinners)
+	draw.HandleFunc("GET /admin/events/{id}/live-draw", svc.handleLiveDrawPage)
+	draw.HandleFunc("POST /admin/events/{id}/live-draw", svc.handleStartLiveDraw)
+	draw.HandleFunc("POST /admin/events/{id}/live-draw/next", svc.handleNextLiveWinner)
+	draw.HandleFunc("POST /admin/events/{id}/live-draw/finish", svc.handleFinishLiveDraw)
+	draw.HandleFunc("POST /admin/events/{id}/live-draw/cancel", svc.handleCancelLiveDraw)
+	viewer.HandleFunc("GET /admin/events/{id}/projector", svc.handleProjectorPage)
+	draw.HandleFunc("POST /admin/events/{id}/draws/{drawID}/announcement", svc.handleComposeAnnouncement)
+	draw.HandleFunc("POST /admin/events/{id}/draws/{drawID}/announcement/post", svc.handlePostAnnouncement)
+	admin.HandleFunc("POST /admin/events/{id}/broadcast", svc.handleBroadcast)
+	admin.HandleFunc("POST /admin/events/{id}/broadcast/preview", svc.handleBroadcastPreview)
+	admin.HandleFunc("POST /admin/events/{id}/broadcasts", svc.handleScheduleBroadcast)
+	admin.HandleFunc("PUT /admin/events/{id}/broadcasts/{broadcastID}", svc.handleUpdateBroadcast)
+	admin.HandleFunc("DELETE /admin/events/{id}/broadcasts/{broadcastID}", svc.handleCancelBroadcast)
+	viewer.HandleFunc("GET /admin/events/{id}/broadcasts/{broadcastID}", svc.handleBroadcastPage)
+	viewer.HandleFunc("GET /admin/events/{id}/broadcasts/{broadcastID}/status", svc.handleBroadcastStatus)
+	admin.HandleFunc("POST /admin/events/{id}/broadcasts/{broadcastID}/retry", svc.handleRetryBroadcast)
+	viewer.HandleFunc("GET /admin/events/{id}/broadcasts/{broadcastID}/deliveries.csv", svc.handleExportDeliveries)
+	admin.HandleFunc("POST /admin/events/{id}/copy-participants", svc.handleCopyParticipants)
+	viewer.HandleFunc("GET /admin/events/{id}/participants.csv", svc.handleExportParticipants)
+	viewer.HandleFunc("GET /admin/events/{id}/participants.xlsx", svc.handleExportParticipantsXLSX)
+	viewer.HandleFunc("GET /admin/events.xlsx", svc.handleExportEventsXLSX)
+	viewer.HandleFunc("GET /admin/events/{id}/consent.csv", svc.handleExportConsentLog)
+	viewer.HandleFunc("GET /admin/events/{id}/qr.png", svc.handleEventQRCode)
+	admin.HandleFunc("POST /admin/events/{id}/kiosk-token", svc.handleRotateKioskToken)
+	admin.HandleFunc("POST /admin/events/{id}/access-links", svc.handleCreateAccessLink)
+	admin.HandleFunc("POST /admin/events/{id}/paid-entries", svc.handleSetPaidEntries)
+	admin.HandleFunc("POST /admin/events/{id}/ticket-price", svc.handleSetTicketPrice)
+	admin.HandleFunc("POST /admin/events/{id}/capacity", svc.handleSetCapacity)
+	viewer.HandleFunc("GET /admin/events/{id}/payments", svc.handlePaymentsPage)
+	admin.HandleFunc("POST /admin/events/{id}/payments/refund", svc.handleRefundAll)
+	admin.HandleFunc("POST /admin/events/{id}/payments/{orderID}/refund", svc.handleRefundPayment)
+	viewer.HandleFunc("GET /admin/events/{id}/promo-codes", svc.handlePromoCodesPage)
+	admin.HandleFunc("POST /admin/events/{id}/promo-codes", svc.handleCreatePromoCode)
+	admin.HandleFunc("DELETE /admin/events/{id}/promo-codes/{codeID}", svc.handleDeletePromoCode)
+	viewer.HandleFunc("GET /admin/events/{id}/short-links", svc.handleShortLinksPage)
+	admin.HandleFunc("POST /admin/events/{id}/short-links", svc.handleCreateShortLink)
+	admin.HandleFunc("DELETE /admin/events/{id}/short-links/{linkID}", svc.handleDeleteShortLink)
+	viewer.HandleFunc("GET /admin/events/{id}/ticket-types", svc.handleTicketTypesPage)
+	admin.HandleFunc("POST /admin/events/{id}/ticket-types", svc.handleCreateTicketType)
+	admin.HandleFunc("DELETE /admin/events/{id}/ticket-types/{typeID}", svc.handleDeleteTicketType)
+	viewer.HandleFunc("GET /admin/events/{id}/seats", svc.handleSeatsPage)
+	admin.HandleFunc("POST /admin/events/{id}/seats", svc.handleCreateSeats)
+	admin.HandleFunc("DELETE /admin/events/{id}/seats/{seatID}", svc.handleDeleteSeat)
+	admin.HandleFunc("POST /admin/events/{id}/seats/assignment", svc.handleSetSeatAssignment)
+	admin.HandleFunc("POST /admin/events/{id}/seats/assign", svc.handleSeatEveryone)
+	viewer.HandleFunc("GET /admin/events/{id}/badges", svc.handleBadges)
+	admin.HandleFunc("POST /admin/events/{id}/rules", svc.handleCreateEntryRule)
+	admin.HandleFunc("DELETE /admin/events/{id}/rules/{ruleID}", svc.handleDeleteEntryRule)
+	admin.HandleFunc("POST /admin/events/{id}/rules/recalculate", svc.handleRecalculateEntryBonuses)
+	admin.HandleFunc("POST /admin/events/{id}/organizers", svc.handleAddEventOrganizer)
+	admin.HandleFunc("DELETE /admin/events/{id}/organizers/{organizerID}", svc.handleRemoveEventOrganizer)
+	viewer.HandleFunc("GET /admin/events/{id}/shares", svc.handleSharesPage)
+	admin.HandleFunc("POST /admin/events/{id}/share-bonus", svc.handleSetShareBonus)
+	viewer.HandleFunc("GET /admin/events/{id}/review", svc.handleReviewPage)
+	moderator.HandleFunc("POST /admin/events/{id}/review/scan", svc.handleScanRegistrations)
+	moderator.HandleFunc("POST /admin/events/{id}/review/{userID}/approve", svc.handleApproveUser)
+	moderator.HandleFunc("DELETE /admin/events/{id}/review/{userID}", svc.handleRejectUser)
+	admin.HandleFunc("POST /admin/events/{id}/show-winners", svc.handleSetShowWinners)
+	admin.HandleFunc("POST /admin/events/{id}/translations/{lang}", svc.handleSaveEventTranslation)
+	admin.HandleFunc("POST /admin/events/{id}/template", svc.handleSaveEventTemplate)
+	viewer.HandleFunc("GET /admin/events/{id}/export.json", svc.handleExportEvent)
+	admin.HandleFunc("POST /admin/events/{id}/duplicate", svc.handleDuplicateEvent)
+	admin.HandleFunc("POST /admin/events/{id}/categories", svc.handleSetEventCategories)
+	// Imports carry every participant of an event, so they get a bigger body limit
+	base.Group(router.MaxBodySize(maxImportSize), router.CSRF(svc.renderError), svc.requireRole(authz.Admin), svc.requireCSRFToken).HandleFunc("POST /admin/events/import", svc.handleImportEvent)
+	// Event forms may carry an image, so they get a bigger body limit too
+	uploads := base.Group(router.MaxBodySize(maxEventFormSize), router.CSRF(svc.renderError), svc.requireRole(authz.Admin), svc.requireCSRFToken)
+	uploads.HandleFunc("PUT /admin/events/{id}", svc.handleUpdateEvent)
+	uploads.HandleFunc("POST /admin/event", svc.handleCreateEvent)
+	admin.HandleFunc("GET /admin/event", svc.handleCreateEventPage)
+	deletion := admin.Group(svc.authorize(authz.DeleteEvent))
+	deletion.HandleFunc("DELETE /admin/events/{id}", svc.handleDeleteEvent)
+	deletion.HandleFunc("POST /admin/trash/events/{id}/restore", svc.handleRestoreEvent)
+	archive := admin.Group(svc.authorize(authz.ArchiveEvent))
+	archive.HandleFunc("POST /admin/events/{id}/archive", svc.handleArchiveEvent)
+	archive.HandleFunc("DELETE /admin/events/{id}/archive", svc.handleUnarchiveEvent)
+	moderator.HandleFunc("POST /admin/events/{id}/checkin", svc.handleAdminCheckIn)
+	moderator.HandleFunc("DELETE /admin/events/{eventID}/users/{userID}", svc.handleDeleteEventUser)
+	moderator.HandleFunc("PATCH /admin/events/{eventID}/users/{userID}", svc.handleUpdateUserCount)
+	moderator.HandleFunc("POST /admin/events/{id}/users/bulk", svc.handleBulkUpdateUsers)
+	moderator.HandleFunc("POST /admin/events/{eventID}/users/{userID}/waitlist", svc.handleMoveUserToWaitlist)
+	moderator.HandleFunc("POST /admin/events/{eventID}/users/{userID}/tags", svc.handleSetUserTags)
+	moderator.HandleFunc("POST /admin/events/{eventID}/users/{userID}/notes", svc.handleSetUserNotes)
+	moderator.HandleFunc("POST /admin/events/{eventID}/users/{userID}/seat", svc.handleSetUserSeat)
+	moderator.HandleFunc("DELETE /admin/events/{eventID}/users/{userID}/seat", svc.handleReleaseUserSeat)
+	viewer.HandleFunc("GET /admin/events/{id}/waitlist", svc.handleWaitlistPage)
+	admin.HandleFunc("POST /admin/events/{id}/waitlist/auto-promote", svc.handleSetWaitlistAutoPromote)
+	moderator.HandleFunc("POST /admin/events/{id}/waitlist/{entryID}/promote", svc.handlePromoteWaitlistEntry)
+	moderator.HandleFunc("DELETE /admin/events/{id}/waitlist/{entryID}", svc.handleRemoveWaitlistEntry)
+	viewer.HandleFunc("GET /admin/flags", svc.handleFlagsPage)
+	admin.HandleFunc("POST /admin/flags/{name}", svc.handleSetFlag)
+	viewer.HandleFunc("GET /admin/templates", svc.handleEventTemplatesPage)
+	admin.HandleFunc("PUT /admin/templates/{id}", svc.handleUpdateEventTemplate)
+	admin.HandleFunc("DELETE /admin/templates/{id}", svc.handleDeleteEventTemplate)
+	admin.HandleFunc("POST /admin/templates/{id}/events", svc.handleCreateEventFromTemplate)
+	viewer.HandleFunc("GET /admin/digest", svc.handleDigestPage)
+	admin.HandleFunc("POST /admin/digest", svc.handleCreateDigestSubscription)
+	viewer.HandleFunc("GET /admin/digest/{id}", svc.handleNotificationPrefsPage)
+	admin.HandleFunc("POST /admin/digest/{id}", svc.handleUpdateNotificationPrefs)
+	admin.HandleFunc("DELETE /admin/digest/{id}", svc.handleDeleteDigestSubscription)
+	admin.HandleFunc("POST /admin/digest/{id}/send", svc.handleSendDigest)
+	viewer.HandleFunc("GET /admin/maintenance", svc.handleMaintenancePage)
+	viewer.HandleFunc("GET /admin/trash", svc.handleTrashPage)
+	moderator.HandleFunc("POST /admin/trash/users/{id}/restore", svc.handleRestoreUser)
+	admin.HandleFunc("GET /admin/audit", svc.handleAuditPage)
+	// Every admin may mint tokens, limited to what their role allows
+	viewer.HandleFunc("GET /admin/tokens", svc.handleTokensPage)
+	viewer.HandleFunc("POST /admin/tokens", svc.handleCreateToken)
+	viewer.HandleFunc("DELETE /admin/tokens/{id}", svc.handleDeleteToken)
+	viewer.HandleFunc("GET /admin/sessions", svc.handleSessionsPage)
+	viewer.HandleFunc("DELETE /admin/sessions/{id}", svc.handleRevokeSession)
+	viewer.HandleFunc("GET "+passwordPath, svc.handlePasswordPage)
+	viewer.HandleFunc("POST "+passwordPath, svc.handleChangePassword)
+	admins := admin.Group(svc.authorize(authz.ManageAdmins))
+	admins.HandleFunc("GET /admin/users", svc.handleAdminsPage)
+	admins.HandleFunc("POST /admin/users", svc.handleCreateAdmin)
+	admins.HandleFunc("POST /admin/users/{id}/password", svc.handleSetAdminPassword)
+	admins.HandleFunc("DELETE /admin/users/{id}", svc.handleDeleteAdmin)
+	admin.Group(svc.authorize(authz.RunCleanup)).HandleFunc("POST /admin/maintenance/cleanup", svc.handleCleanup)
+
+	// JSON API routes, callable from the browser by the allowed origins
+	api := root.Group(router.CORS(router.CORSFromEnv(), svc.renderJSONError))
+	api.HandleFunc("OPTIONS /api/v1/", func(http.ResponseWriter, *http.Request) {})
+	api.HandleFunc("GET /api/v1/health", svc.handleHealth)
+	limited := api.Group(svc.rateLimit(svc.renderJSONError))
+	limited.Group(svc.idempotent).HandleFunc("POST /api/v1/events/{id}/registrations", svc.handleAPIRegister)
+	limited.Group(svc.requireAPIKey).HandleFunc("POST /api/v1/events/{id}/checkin", svc.handleCheckIn)
+	limited.Group(svc.requireAPIKey).HandleFunc("GET /api/v1/events/{id}/registrations", svc.handlePollRegistrations)
+	// Event management for external tools, authenticated with ADMIN_API_KEY,
+	// an API token or the admin session
+	manage := limited.Group(svc.requireAPIAuth)
+	readEvents := manage.Group(svc.requireScope(authz.ReadEvents))
+	writeEvents := manage.Group(svc.requireScope(authz.WriteEvents))
+	readParticipants := manage.Group(svc.requireScope(authz.ReadParticipants))
+	writeParticipants := manage.Group(svc.requireScope(authz.WriteParticipants))
+	readEvents.HandleFunc("GET /api/v1/events", svc.handleAPIListEvents)
+	writeEvents.Group(svc.idempotent).HandleFunc("POST /api/v1/events", svc.handleAPICreateEvent)
+	readEvents.HandleFunc("GET /api/v1/events/{id}", svc.handleAPIGetEvent)
+	writeEvents.HandleFunc("PUT /api/v1/events/{id}", svc.handleAPIUpdateEvent)
+	manage.Group(svc.requireScope(authz.DeleteEvents)).HandleFunc("DELETE /api/v1/events/{id}", svc.handleAPIDeleteEvent)
+	writeEvents.HandleFunc("POST /api/v1/events/{id}/status", svc.handleAPISetEventStatus)
+	readParticipants.HandleFunc("GET /api/v1/events/{id}/participants", svc.handleAPIListParticipants)
+	writeParticipants.Group(svc.idempotent).HandleFunc("POST /api/v1/events/{id}/participants", svc.handleAPICreateParticipant)
+	readParticipants.HandleFunc("GET /api/v1/events/{eventID}/participants/{userID}", svc.handleAPIGetParticipant)
+	writeParticipants.HandleFunc("PUT /api/v1/events/{eventID}/participants/{userID}", svc.handleAPIUpdateParticipant)
+	writeParticipants.HandleFunc("DELETE /api/v1/events/{eventID}/participants/{userID}", svc.handleAPIDeleteParticipant)
+	readParticipants.HandleFunc("GET /api/v1/events/{id}/winners", svc.handleAPIListWinners)
+	manage.Group(svc.requireScope(authz.RunDraws), svc.idempotent).HandleFunc("POST /api/v1/events/{id}/winners", svc.handleAPIRunDraw)
+	// Called by the payment provider, which authenticates with a signature
+	root.HandleFunc("POST /payments/callback", svc.handlePaymentCallback)
+
+	go svc.runMaintenance(ctx)
+	go svc.sendDigests(ctx)
+	go svc.archiveEvents(ctx)
+	go svc.sendScheduledBroadcasts(ctx)
+	go svc.syncCalendar(ctx)
+	go svc.deliverWebhooks(ctx)
+}
 
-		// Check if user is authenticated as admin
-		isAdmin, ok := session.Values["isAdmin"].(bool)
-		if !ok || !isAdmin {
-			http.Redirect(w, r, "/login", http.StatusSeeOther)
-			return
-		}
+// requireRole signs in admins from their session and rejects those whose
+// role is less privileged than min, or who don't organize the event the
+// route is about. Admins who must change their password are sent to do so.
+func (s *Service) requireRole(min authz.Role) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			admin, err := s.sessionAdmin(r)
+			if err != nil {
+				s.renderError(w, r, "Failed to get admin", apperr.FromDB(err))
+				return
+			}
+			if admin == nil {
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+				return
+			}
+			// Admins who must change their password do that first
+			if admin.MustChangePassword && r.URL.Path != passwordPath {
+				if r.Header.Get("HX-Request") == "true" {
+					w.Header().Set("HX-Redirect", passwordPath)
+					return
+				}
+				http.Redirect(w, r, passwordPath, http.StatusSeeOther)
+				return
+			}
+			ctx, err := s.withAdmin(r.Context(), admin)
+			if err != nil {
+				s.renderError(w, r, "Failed to get organized events", apperr.FromDB(err))
+				return
+			}
 
-		next(w, r)
+			if !authz.AtLeast(authz.RoleFromContext(ctx), min) {
+				s.renderError(w, r.WithContext(ctx), "Action not allowed", apperr.Forbidden("Your role doesn't allow this"))
+				return
+			}
+			if eventID := pathEventID(r); eventID != 0 && !authz.EventAllowed(ctx, eventID) {
+				s.renderError(w, r.WithContext(ctx), "Event not allowed", errNotOrganizer)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// sessionAdmin returns the admin signed in with the session of r, or nil
+// if there is none.
+func (s *Service) sessionAdmin(r *http.Request) (*sqlc.Admins, error) {
+	session, err := s.sessionStore.Get(r, "session")
+	if err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to get session", slog.Any("error", err))
+		return nil, nil
+	}
+
+	// Check if user is authenticated as admin
+	isAdmin, ok := session.Values["isAdmin"].(bool)
+	if !ok || !isAdmin {
+		return nil, nil
+	}
+
+	// Every admin has an account, so sessions from before accounts
+	// existed have to sign in again. Removed admins lose access right
+	// away, and role changes apply on their next request.
+	adminID, ok := session.Values["adminID"].(int64)
+	if !ok {
+		return nil, nil
+	}
+	admin, err := s.store.GetAdminByID(r.Context(), adminID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
+
+// errNotOrganizer rejects admins from the pages and API of events they
+// don't organize.
+var errNotOrganizer = apperr.Forbidden("You aren't an organizer of this event")
+
+// pathEventID returns the ID in the path of admin and API routes about one
+// event, or 0 for other routes.
+func pathEventID(r *http.Request) int64 {
+	if !strings.HasPrefix(r.URL.Path, "/admin/events/") && !strings.HasPrefix(r.URL.Path, "/api/v1/events/") {
+		return 0
+	}
+	rawID := r.PathValue("eventID")
+	if rawID == "" {
+		rawID = r.PathValue("id")
+	}
+	eventID, _ := strconv.ParseInt(rawID, 10, 64)
+	return eventID
+}
+
+// authorize rejects requests from admins whose role may not perform action.
+// It goes after requireRole, which puts the role in the context.
+func (s *Service) authorize(action authz.Action) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !authz.Allowed(authz.RoleFromContext(r.Context()), action) {
+				s.renderError(w, r, "Action not allowed", apperr.Forbidden("Your role doesn't allow this"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
 	}
 }
 
+// runTemplate renders the template name into a buffer first, so a template
+// that fails halfway sends an error instead of half a page with 200 OK.
 func (s *Service) runTemplate(w http.ResponseWriter, r *http.Request, name string, data any) {
-	w.Header().Set("Content-Type", "text/html")
-	if err := s.tmpl.ExecuteTemplate(w, name, data); err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to execute template", slog.Any("error", err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	var buf bytes.Buffer
+	if err := s.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		s.renderError(w, r, "Failed to execute template", err)
+		return
 	}
+	w.Header().Set("Content-Type", "text/html")
+	buf.WriteTo(w)
+}
+
+func (s *Service) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
 
 func (s *Service) handleEvents(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	events, err := s.store.GetEvents(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get events", apperr.FromDB(err))
 		return
 	}
+	// Past events are listed only when asked for, and drafts never. The
+	// list may be shared with the store cache, so filter a copy
+	archived := r.URL.Query().Get("archived") == "true"
+	events = slices.DeleteFunc(slices.Clone(events), func(event *sqlc.Events) bool {
+		return event.ArchivedAt.Valid != archived || !lifecycle.Public(event)
+	})
+	categories := eventCategories(events)
+	category := strings.ToLower(r.URL.Query().Get("category"))
+	if category != "" {
+		events = slices.DeleteFunc(events, func(event *sqlc.Events) bool { return !slices.Contains(event.Categories, category) })
+	}
 
-	events, err := s.queries.GetEvents(r.Context())
+	lang := language(w, r)
+	events, err = s.translateEvents(r.Context(), events, lang)
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to get events", slog.Any("error", err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		s.renderError(w, r, "Failed to get translations", apperr.FromDB(err))
 		return
 	}
 
-	// Check if user is admin
+	places, err := capacity.Places(r.Context(), s.store, events)
+	if err != nil {
+		s.renderError(w, r, "Failed to count places", apperr.FromDB(err))
+		return
+	}
+
+	// Check if user is admin, which makes the page differ by session
+	w.Header().Add("Vary", "Cookie")
 	session, err := s.sessionStore.Get(r, "session")
 	isAdmin := false
 	if err == nil {
@@ -208,61 +595,61 @@ func (s *Service) handleEvents(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.runTemplate(w, r, "events", Data{
-		Events:         events,
-		CurrentEventID: config.GetCurrentEventID(),
-		IsAdmin:        isAdmin,
+		Events:             events,
+		CurrentEventID:     config.GetCurrentEventID(),
+		BotURL:             s.botURL(),
+		IsAdmin:            isAdmin,
+		PublicRegistration: config.FlagEnabled(config.FlagPublicRegistration),
+		Language:           lang,
+		Archived:           archived,
+		Category:           category,
+		Categories:         categories,
+		Places:             places,
 	})
 }
 
 func (s *Service) handleLoginPage(w http.ResponseWriter, r *http.Request) {
-	s.runTemplate(w, r, "login", nil)
+	s.runTemplate(w, r, "login", struct{ SessionDays int }{
+		SessionDays: s.sessionStore.Options.MaxAge / (24 * 60 * 60),
+	})
 }
 
 func (s *Service) handleLogin(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	if err := r.ParseForm(); err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to parse form", slog.Any("error", err))
-		fmt.Fprintf(w, errHTML, "Invalid form submission")
+		s.renderError(w, r, "Invalid login", validate.BodyError(err))
 		return
 	}
 
 	username := r.FormValue("username")
 	password := r.FormValue("password")
+	r = r.WithContext(logging.With(r.Context(), slog.String("username", username)))
 
-	// Check credentials
-	if username == s.adminData.Username && password == s.adminData.Password {
-		// Set user as authenticated in session
-		session, _ := s.sessionStore.Get(r, "session")
-
-		session.Values["isAdmin"] = true
-		if err := session.Save(r, w); err != nil {
-			s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to save session", slog.Any("error", err))
-			fmt.Fprintf(w, errHTML, "Failed to save session. Please try again.")
-			return
-		}
+	admin, err := s.checkPassword(r.Context(), username, password)
+	if err != nil {
+		s.renderError(w, r, "Failed sign in", err)
+		return
+	}
 
-		// If this is an HTMX request, respond with a redirect instruction
-		if r.Header.Get("HX-Request") == "true" {
-			w.Header().Set("HX-Redirect", "/admin")
-			return
-		}
+	// Set user as authenticated in session
+	if err := s.signIn(w, r, admin, r.FormValue("remember") == "true"); err != nil {
+		s.renderError(w, r, "Failed to save session", err)
+		return
+	}
 
-		// Otherwise do a standard redirect
-		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+	// If this is an HTMX request, respond with a redirect instruction
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/admin")
 		return
 	}
 
-	fmt.Fprintf(w, errHTML, "Invalid username or password")
+	// Otherwise do a standard redirect
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
 }
 
 func (s *Service) handleLogout(w http.ResponseWriter, r *http.Request) {
 	session, err := s.sessionStore.Get(r, "session")
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to get session", slog.Any("error", err))
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to get session", slog.Any("error", err))
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
 	}
@@ -270,61 +657,97 @@ func (s *Service) handleLogout(w http.ResponseWriter, r *http.Request) {
 	// Revoke authentication
 	session.Values["isAdmin"] = false
 	session.Options.MaxAge = -1 // Delete the cookie
+	s.setCSRFCookie(w, r, "", -1)
 
 	if err := session.Save(r, w); err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to save session", slog.Any("error", err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		s.renderError(w, r, "Failed to save session", err)
 		return
 	}
 
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
+// handleAdminDashboard lists the active events or the archive a page at a
+// time. HTMX requests for later pages get only the events, to append to
+// the list.
 func (s *Service) handleAdminDashboard(w http.ResponseWriter, r *http.Request) {
-	events, err := s.queries.GetEvents(r.Context())
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	// The dashboard lists either the active events or the archive
+	archived := r.URL.Query().Get("archived") == "true"
+	category := strings.ToLower(r.URL.Query().Get("category"))
+
+	// One extra event tells whether there is a next page
+	events, err := s.store.GetEventsPage(r.Context(), &sqlc.GetEventsPageParams{
+		Archived:    archived,
+		Category:    category,
+		OrganizerID: organizerID(r.Context()),
+		PageOffset:  int32((page - 1) * dashboardPageSize),
+		PageSize:    dashboardPageSize + 1,
+	})
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to get events", slog.Any("error", err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		s.renderError(w, r, "Failed to get events", apperr.FromDB(err))
 		return
 	}
+	nextPage := 0
+	if len(events) > dashboardPageSize {
+		events = events[:dashboardPageSize]
+		nextPage = page + 1
+	}
 
-	s.runTemplate(w, r, "admin_events", Data{
-		Events:  events,
-		IsAdmin: true,
-	})
-}
+	counts := make(map[int64]int64, len(events))
+	for _, event := range events {
+		count, err := s.store.CountUsersByEventID(r.Context(), event.ID)
+		if err != nil {
+			s.renderError(w, r, "Failed to count users", apperr.FromDB(err))
+			return
+		}
+		counts[event.ID] = count
+	}
 
-func (s *Service) handleCreateEventPage(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	data := Data{
+		Events:   events,
+		Counts:   counts,
+		IsAdmin:  true,
+		Archived: archived,
+		Role:     authz.RoleFromContext(r.Context()),
+		NextPage: nextPage,
+		Category: category,
+	}
+	if page > 1 && r.Header.Get("HX-Request") == "true" {
+		s.runTemplate(w, r, "admin_events_page", data)
+		return
+	}
+	if data.Categories, err = s.store.GetEventCategories(r.Context(), archived); err != nil {
+		s.renderError(w, r, "Failed to get categories", apperr.FromDB(err))
 		return
 	}
+	s.runTemplate(w, r, "admin_events", data)
+}
 
+func (s *Service) handleCreateEventPage(w http.ResponseWriter, r *http.Request) {
 	// Render the create event page
 	s.runTemplate(w, r, "admin_create_event", nil)
 }
 
 func (s *Service) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
-	s.logger.LogAttrs(r.Context(), slog.LevelInfo, "Handling create event")
-	// Parse form data for new event
-	if err := r.ParseForm(); err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to parse form", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
-		return
-	}
-
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Handling create event")
 	// Extract event details from form
-	name := r.FormValue("name")
-	description := r.FormValue("description")
-
-	if name == "" {
-		fmt.Fprintf(w, errHTML, "Event name is required")
+	form := validate.NewForm(r)
+	name := form.RequiredText("name", maxNameLength)
+	description := form.RichText("description", maxDescriptionLength)
+	eventSlug := form.Text("slug", maxNameLength)
+	image, imageType := form.File("image", maxImageSize, imageTypes...)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid event", err)
 		return
 	}
 
 	// Get the date from the form
 	formDate := r.FormValue("date")
-	s.logger.LogAttrs(r.Context(), slog.LevelInfo, "Received date", slog.String("date", formDate))
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Received date", slog.String("date", formDate))
 
 	// Parse the date - corrected order of arguments
 	// For datetime-local inputs, the format is typically "2006-01-02T15:04"
@@ -333,34 +756,59 @@ func (s *Service) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
 		// Try alternative format if the first one fails
 		date, err = time.Parse("2006-01-02 15:04:05", formDate)
 		if err != nil {
-			s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to parse date",
-				slog.String("input", formDate),
-				slog.Any("error", err))
-			fmt.Fprintf(w, errHTML, "Invalid date format. Please use YYYY-MM-DDTHH:MM format.")
+			s.renderError(w, r, "Invalid date", apperr.Validation("Invalid date format. Please use YYYY-MM-DDTHH:MM format."))
 			return
 		}
 	}
 
-	s.logger.LogAttrs(r.Context(), slog.LevelInfo, "Parsed date successfully",
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Parsed date successfully",
 		slog.String("original", formDate),
 		slog.Time("parsed", date))
 
 	// Create event in database
-	_, err = s.queries.CreateEvent(r.Context(), &sqlc.CreateEventParams{
-		Name:        name,
-		Description: sql.NullString{String: description, Valid: description != ""},
-		Date:        date,
+	var event *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		var err error
+		event, err = tx.CreateEvent(r.Context(), &sqlc.CreateEventParams{
+			Name:        name,
+			Description: sql.NullString{String: description, Valid: description != ""},
+			Date:        date,
+		})
+		if err != nil {
+			return err
+		}
+		if event, err = slug.Assign(r.Context(), tx, event, eventSlug); err != nil {
+			return err
+		}
+		if err := audit.Record(r.Context(), tx, audit.EventCreated, event.ID, nil, event); err != nil {
+			return err
+		}
+		if _, err := organizeNewEvent(r.Context(), tx, event.ID); err != nil {
+			return err
+		}
+		return updateEventImage(r.Context(), tx, event.ID, image, imageType, false)
 	})
 
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to create event", slog.Any("error", err))
-		fmt.Fprintf(w, errHTML, "Failed to create event: "+err.Error())
+		s.renderError(w, r, "Failed to create event", apperr.FromDB(err))
 		return
 	}
 
-	// If this is an HTMX request, respond with a redirect instruction
+	// The dashboard gets the new event's card on top of the list, keeping
+	// its place; other pages go to the dashboard
 	if r.Header.Get("HX-Request") == "true" {
-		w.Header().Set("HX-Redirect", "/admin")
+		if !onActiveDashboard(r) {
+			w.Header().Set("HX-Redirect", "/admin")
+			return
+		}
+		w.Header().Set("HX-Retarget", "#events")
+		w.Header().Set("HX-Reswap", "afterbegin")
+		s.runTemplate(w, r, "event_created", Data{
+			Events:  []*sqlc.Events{event},
+			Counts:  map[int64]int64{event.ID: 0},
+			IsAdmin: true,
+			Role:    authz.RoleFromContext(r.Context()),
+		})
 		return
 	}
 
@@ -369,99 +817,327 @@ func (s *Service) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Service) handleGetEvent(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// Extract event ID from URL
 	eventID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid event ID", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
 		return
 	}
 
 	// Delete event from database
-	event, err := s.queries.GetEventByID(r.Context(), int64(eventID))
+	event, err := s.store.GetEventByID(r.Context(), int64(eventID))
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+
+	view := newUsersView(r)
+	page, err := s.usersPage(r.Context(), event, view, 0)
+	if err != nil {
+		s.renderError(w, r, "Failed to get users", apperr.FromDB(err))
+		return
+	}
+	total, err := s.store.CountUsersWithTag(r.Context(), &sqlc.CountUsersWithTagParams{EventID: event.ID, Tag: view.Tag})
+	if err != nil {
+		s.renderError(w, r, "Failed to count users", apperr.FromDB(err))
+		return
+	}
+	tags, err := s.store.GetEventTags(r.Context(), event.ID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get tags", apperr.FromDB(err))
+		return
+	}
+	reach, err := s.store.CountUsersReach(r.Context(), event.ID)
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to get event", slog.Any("error", err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		s.renderError(w, r, "Failed to count reachable users", apperr.FromDB(err))
 		return
 	}
 
-	users, err := s.queries.GetUsersByEventID(r.Context(), int64(eventID))
+	// Other events are offered as sources for copying participants
+	events, err := s.store.GetEvents(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get events", apperr.FromDB(err))
+		return
+	}
+	events = organizedEvents(r.Context(), events)
+
+	rules, err := s.store.GetEntryRulesByEventID(r.Context(), event.ID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get entry rules", apperr.FromDB(err))
+		return
+	}
+	organizers, err := s.store.GetEventOrganizers(r.Context(), event.ID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get organizers", apperr.FromDB(err))
+		return
+	}
+
+	translations, err := s.eventTranslations(r.Context(), event)
+	if err != nil {
+		s.renderError(w, r, "Failed to get translations", apperr.FromDB(err))
+		return
+	}
+	broadcasts, err := s.store.GetBroadcastsByEventID(r.Context(), event.ID)
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to get users", slog.Any("error", err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		s.renderError(w, r, "Failed to get scheduled broadcasts", apperr.FromDB(err))
 		return
 	}
 
 	type eventData struct {
-		Event *sqlc.Events  `json:"event"`
-		Users []*sqlc.Users `json:"users"`
+		// usersPage is the first page of the participants table
+		*usersPage
+		// Total is how many participants there are with Tag
+		Total  int64          `json:"total"`
+		Events []*sqlc.Events `json:"events"`
+		// EntryPrice and TicketPrice are the prices of a paid entry and a
+		// ticket in hryvnias, for the forms
+		EntryPrice  string                  `json:"entry_price"`
+		TicketPrice string                  `json:"ticket_price"`
+		Rules       []*sqlc.EntryRules      `json:"rules"`
+		Organizers  []*sqlc.EventOrganizers `json:"organizers"`
+		// Translations has a form for every language the event can be
+		// translated to
+		Translations []*eventTranslation `json:"-"`
+		// Tags are all tags used in the event
+		Tags []string `json:"tags"`
+		// Broadcasts are the event's scheduled broadcasts, with send times
+		// in TimeZone
+		Broadcasts []*sqlc.Broadcasts `json:"broadcasts"`
+		TimeZone   string             `json:"-"`
+		// Reachable is how many participants broadcasts are sent to;
+		// Blocked is how many are left out because they blocked the bot
+		Reachable int64 `json:"reachable"`
+		Blocked   int64 `json:"blocked"`
+		// Places is how many places are left, for events with a capacity
+		Places int32 `json:"places"`
+		// CounterURL is the registration counter other sites embed
+		CounterURL string `json:"-"`
+	}
+
+	var places int32
+	if event.Capacity > 0 {
+		if places, err = capacity.Left(r.Context(), s.store, event); err != nil {
+			s.renderError(w, r, "Failed to count places", apperr.FromDB(err))
+			return
+		}
 	}
 
 	s.runTemplate(w, r, "admin_event", eventData{
-		Event: event,
-		Users: users,
+		usersPage:    page,
+		Total:        total,
+		Events:       events,
+		EntryPrice:   fmt.Sprintf("%.2f", float64(event.EntryPrice)/100),
+		TicketPrice:  fmt.Sprintf("%.2f", float64(event.TicketPrice)/100),
+		Rules:        rules,
+		Organizers:   organizers,
+		Translations: translations,
+		Tags:         tags,
+		Broadcasts:   broadcasts,
+		TimeZone:     time.Now().Format("MST"),
+		Reachable:    reach.Reachable,
+		Blocked:      reach.Blocked,
+		Places:       places,
+		CounterURL:   s.siteURL(r) + "/embed/events/" + strconv.FormatInt(event.ID, 10) + "/counter",
 	})
 }
 
-func (s *Service) handleDeleteEvent(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// userSortKeys are the columns the admin participants table can be sorted
+// by. Unsorted, it lists participants in the order they registered.
+var userSortKeys = []string{"name", "username", "n", "registered"}
+
+// usersView is how the admin participants table is filtered and sorted.
+type usersView struct {
+	// Tag keeps the participants with the tag, if set
+	Tag string `json:"tag"`
+	// Query keeps the participants whose name or username contains it
+	Query string `json:"query"`
+	// Sort is one of userSortKeys, or "" for the order of registration
+	Sort string `json:"sort"`
+	Desc bool   `json:"desc"`
+}
+
+func newUsersView(r *http.Request) usersView {
+	view := usersView{
+		Tag:   strings.ToLower(r.FormValue("tag")),
+		Query: strings.TrimSpace(r.FormValue("q")),
+		Desc:  r.FormValue("desc") == "true",
+	}
+	if sort := r.FormValue("sort"); slices.Contains(userSortKeys, sort) {
+		view.Sort = sort
+	}
+	return view
+}
+
+// SortVals returns the hx-vals of the header sorting by key: ascending
+// first, descending when clicked again.
+func (v usersView) SortVals(key string) string {
+	vals, _ := json.Marshal(map[string]string{
+		"sort": key,
+		"desc": strconv.FormatBool(v.Sort == key && !v.Desc),
+	})
+	return string(vals)
+}
+
+// SortMark returns the arrow shown next to the header sorting by key.
+func (v usersView) SortMark(key string) string {
+	switch {
+	case v.Sort != key:
+		return ""
+	case v.Desc:
+		return "↓"
+	}
+	return "↑"
+}
+
+// usersPage is a page of an event's participants table.
+type usersPage struct {
+	usersView
+	Event     *sqlc.Events     `json:"event"`
+	Users     []*sqlc.Users    `json:"users"`
+	RuleNames map[int64]string `json:"-"`
+	TypeNames map[int64]string `json:"-"`
+	// Seats is nil for events without seats
+	Seats map[int64]*userSeat `json:"-"`
+	// NextOffset is where the next page starts, 0 on the last page
+	NextOffset int `json:"next_offset"`
+	// Role is the signed-in admin's, to hide what they may not change
+	Role authz.Role `json:"-"`
+}
+
+// usersPage gets the page of the event's participants in view starting
+// at offset.
+func (s *Service) usersPage(ctx context.Context, event *sqlc.Events, view usersView, offset int) (*usersPage, error) {
+	// One extra participant tells whether there is a next page
+	users, err := s.store.GetUsersPage(ctx, &sqlc.GetUsersPageParams{
+		EventID:    event.ID,
+		Tag:        view.Tag,
+		Query:      view.Query,
+		Sort:       view.Sort,
+		Descending: view.Desc,
+		PageOffset: int32(offset),
+		PageSize:   participantsPageSize + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	page := &usersPage{usersView: view, Event: event, Role: authz.RoleFromContext(ctx)}
+	if len(users) > participantsPageSize {
+		users = users[:participantsPageSize]
+		page.NextOffset = offset + participantsPageSize
+	}
+	page.Users = users
+
+	rules, err := s.store.GetEntryRulesByEventID(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	page.RuleNames = make(map[int64]string, len(rules))
+	for _, rule := range rules {
+		page.RuleNames[rule.ID] = rule.Name
+	}
+	ticketTypes, err := s.store.GetTicketTypesByEventID(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	page.TypeNames = make(map[int64]string, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		page.TypeNames[ticketType.ID] = ticketType.Name
+	}
+	seats, err := s.store.GetSeatsByEventID(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	page.Seats = userSeats(event.ID, users, seats)
+	return page, nil
+}
+
+// handleGetEventUsers renders the event's participants table for the
+// search box and the sorting headers, or only the rows of a later page for
+// "load more".
+func (s *Service) handleGetEventUsers(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+	var offset int
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 || offset > math.MaxInt32 {
+			s.renderError(w, r, "Invalid offset", apperr.Validation("Invalid offset"))
+			return
+		}
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	page, err := s.usersPage(r.Context(), event, newUsersView(r), offset)
+	if err != nil {
+		s.renderError(w, r, "Failed to get users", apperr.FromDB(err))
 		return
 	}
 
+	if offset > 0 {
+		s.runTemplate(w, r, "admin_event_users", page)
+		return
+	}
+	s.runTemplate(w, r, "admin_event_users_table", page)
+}
+
+func (s *Service) handleDeleteEvent(w http.ResponseWriter, r *http.Request) {
 	// Extract event ID from URL
 	eventID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid event ID", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
 		return
 	}
 
-	// Delete event from database
-	err = s.queries.DeleteEvent(r.Context(), int64(eventID))
+	event, err := s.store.GetEventByID(r.Context(), int64(eventID))
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to delete event", slog.Any("error", err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
 		return
 	}
 
-	if r.Header.Get("HX-Request") == "true" {
-		w.Header().Set("HX-Redirect", "/admin")
+	// Delete event from database
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		if err := tx.DeleteEvent(r.Context(), event.ID); err != nil {
+			return err
+		}
+		return audit.Record(r.Context(), tx, audit.EventDeleted, event.ID, event, nil)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to delete event", apperr.FromDB(err))
 		return
 	}
+	s.removeFromCalendar(r.Context(), event)
+	// The empty response removes the event's card from the dashboard
 }
 
 func (s *Service) handleUpdateEvent(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// Extract event ID from URL
 	eventID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid event ID", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
 		return
 	}
 
 	// Parse form data for updated event details
-	if err := r.ParseForm(); err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to parse form", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
+	form := validate.NewForm(r)
+	name := form.RequiredText("name", maxNameLength)
+	description := form.RichText("description", maxDescriptionLength)
+	eventSlug := form.Text("slug", maxNameLength)
+	image, imageType := form.File("image", maxImageSize, imageTypes...)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid event", err)
 		return
 	}
+	removeImage := r.FormValue("remove_image") == "on"
 
-	name := r.FormValue("name")
-	description := r.FormValue("description")
-
-	if name == "" {
-		fmt.Fprintf(w, errHTML, "Event name is required")
+	version, err := strconv.Atoi(r.FormValue("version"))
+	if err != nil {
+		s.renderError(w, r, "Invalid event version", apperr.Validation("Invalid event version"))
 		return
 	}
 
@@ -469,16 +1145,16 @@ func (s *Service) handleUpdateEvent(w http.ResponseWriter, r *http.Request) {
 		ID:          int64(eventID),
 		Name:        name,
 		Description: sql.NullString{String: description, Valid: description != ""},
+		Version:     int32(version),
 	}
 
 	formDate := r.FormValue("date")
-	s.logger.LogAttrs(r.Context(), slog.LevelInfo, "Received date", slog.String("date", formDate))
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Received date", slog.String("date", formDate))
 
 	if formDate == "" {
-		event, err := s.queries.GetEventByID(r.Context(), int64(eventID))
+		event, err := s.store.GetEventByID(r.Context(), int64(eventID))
 		if err != nil {
-			s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to get event", slog.Any("error", err))
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
 			return
 		}
 		updateReq.Date = event.Date
@@ -487,206 +1163,166 @@ func (s *Service) handleUpdateEvent(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			date, err = time.Parse("2006-01-02 15:04:05", formDate)
 			if err != nil {
-				s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to parse date",
-					slog.String("input", formDate),
-					slog.Any("error", err))
-				fmt.Fprintf(w, errHTML, "Invalid date format. Please use YYYY-MM-DDTHH:MM format.")
+				s.renderError(w, r, "Invalid date", apperr.Validation("Invalid date format. Please use YYYY-MM-DDTHH:MM format."))
 				return
 			}
 		}
 		updateReq.Date = date
 	}
 
-	_, err = s.queries.UpdateEvent(r.Context(), updateReq)
+	var updated *sqlc.Events
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		before, err := tx.GetEventByID(r.Context(), updateReq.ID)
+		if err != nil {
+			return err
+		}
+		after, err := tx.UpdateEvent(r.Context(), updateReq)
+		if err != nil {
+			return err
+		}
+		if after, err = slug.Assign(r.Context(), tx, after, eventSlug); err != nil {
+			return err
+		}
+		if err := audit.Record(r.Context(), tx, audit.EventUpdated, updateReq.ID, before, after); err != nil {
+			return err
+		}
+		if err := updateEventImage(r.Context(), tx, updateReq.ID, image, imageType, removeImage); err != nil {
+			return err
+		}
+		updated, err = tx.GetEventByID(r.Context(), updateReq.ID)
+		return err
+	})
 
+	if errors.Is(err, sql.ErrNoRows) {
+		// No row matched the id and version: either the event is gone or
+		// someone else saved it since this form was loaded
+		if _, getErr := s.store.GetEventByID(r.Context(), int64(eventID)); getErr == nil {
+			logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelWarn, "Stale event update",
+				slog.Int("version", version))
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusConflict)
+			s.runTemplate(w, r, "event_conflict", nil)
+			return
+		}
+	}
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to update event", slog.Any("error", err))
-		fmt.Fprintf(w, errHTML, "Failed to update event: "+err.Error())
+		s.renderError(w, r, "Failed to update event", apperr.FromDB(err))
 		return
 	}
 
-	// If this is an HTMX request, respond with a redirect instruction
-	if r.Header.Get("HX-Request") == "true" {
-		w.Header().Set("HX-Redirect", "/admin")
-		return
-	}
+	// The form is swapped for one with the new version, so the admin can
+	// keep editing where they are
+	w.Header().Set("HX-Retarget", "#event-edit-form")
+	w.Header().Set("HX-Reswap", "outerHTML")
+	s.runTemplate(w, r, "event_saved", updated)
 }
 
-func (s *Service) handleSetCurrentEvent(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+// onActiveDashboard reports whether the HTMX request r was sent from the
+// dashboard listing active events of every category, where new events
+// show up.
+func onActiveDashboard(r *http.Request) bool {
+	current, err := url.Parse(r.Header.Get("HX-Current-URL"))
+	if err != nil {
+		return false
 	}
+	query := current.Query()
+	return current.Path == "/admin" && query.Get("archived") != "true" && query.Get("category") == ""
+}
 
+func (s *Service) handleSetCurrentEvent(w http.ResponseWriter, r *http.Request) {
 	eventID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid event ID", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
 		return
 	}
 
-	config.SetCurrentEventID(int64(eventID))
+	previous := config.GetCurrentEventID()
+	config.SetCurrentEventID(r.Context(), int64(eventID))
+	if err := audit.Record(r.Context(), s.store, audit.CurrentEventSet, int64(eventID),
+		map[string]int64{"current_event_id": previous}, map[string]int64{"current_event_id": int64(eventID)}); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to record audit log", slog.Any("error", err))
+	}
 
 	fmt.Fprintf(w, successHTML, "Current event set successfully")
 }
 
 func (s *Service) handleDeleteEventUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	eventID, err := strconv.Atoi(r.PathValue("eventID"))
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid event ID", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
 		return
 	}
 
 	userID, err := strconv.Atoi(r.PathValue("userID"))
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid user ID", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		s.renderError(w, r, "Invalid user ID", apperr.Validation("Invalid user ID"))
 		return
 	}
 
-	err = s.queries.DeleteUsersByIdAndEventId(r.Context(), &sqlc.DeleteUsersByIdAndEventIdParams{
-		ID:      int64(userID),
-		EventID: int64(eventID),
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		return removeParticipant(r.Context(), tx, int64(eventID), int64(userID), consent.Deleted, consent.SourceAdmin)
 	})
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to delete user", slog.Any("error", err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		s.renderError(w, r, "Failed to delete user", apperr.FromDB(err))
 		return
 	}
 }
 
 func (s *Service) handleGetWinners(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	countStr := r.FormValue("count")
-	if countStr == "" {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Count is required")
-		fmt.Fprintf(w, errHTML, "Count is required")
-		return
+	form := validate.NewForm(r)
+	opts := drawOptions{
+		Count: form.Int("count", 1, maxWinners),
+		Filter: tagFilter{
+			Include: normalizeTags(form.List("include_tags", maxTagsPerUser, maxTagLength)),
+			Exclude: normalizeTags(form.List("exclude_tags", maxTagsPerUser, maxTagLength)),
+		},
+		OnlyReachable: r.FormValue("reachable") == "true",
 	}
-
-	count, err := strconv.Atoi(countStr)
-	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid count", slog.Any("error", err))
-		fmt.Fprintf(w, errHTML, "Invalid count")
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid winners count", err)
 		return
 	}
 
 	eventID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid event ID", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
 		return
 	}
 
-	winners := make([]*sqlc.Users, 0)
-
-	//get all event users
-	users, err := s.queries.GetUsersByEventID(r.Context(), int64(eventID))
+	result, err := s.runDraw(r.Context(), int64(eventID), opts)
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to get users", slog.Any("error", err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		s.renderError(w, r, "Failed to run draw", apperr.FromDB(err))
 		return
 	}
 
-	n := len(users)
-	for i := range n {
-		n := users[i].N
-		if n > 1 {
-			for range n - 1 {
-				users = append(users, users[i])
-			}
-		}
-	}
-
-	// Determine how many winners to select (minimum of count and available users)
-	winnersCount := count
-	if winnersCount > len(users) {
-		winnersCount = len(users)
-	}
-
-	seen := make(map[int64]bool)
-
-	// Select random winners
-	for i := 0; i < winnersCount; i++ {
-		if len(users) == 0 {
-			break
-		}
-
-		// Pick a random index within the valid range
-		index := rand.IntN(len(users))
-
-		if seen[users[index].ID] {
-			i--
-			continue
-		}
-
-		winners = append(winners, users[index])
-		seen[users[index].ID] = true
-
-		// Remove the selected user from the pool
-		users = append(users[:index], users[index+1:]...)
-	}
-
-	type winnersData struct {
-		Users []*sqlc.Users `json:"event"`
-	}
-	s.runTemplate(w, r, "winners", winnersData{
-		Users: winners,
-	})
+	s.runTemplate(w, r, "winners", newWinnersData(int64(eventID), result))
 }
 
 func (s *Service) handleUpdateUserCount(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPatch {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	eventID, err := strconv.Atoi(r.PathValue("eventID"))
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid event ID", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
 		return
 	}
 
 	userID, err := strconv.Atoi(r.PathValue("userID"))
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid user ID", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
-		return
-	}
-
-	err = r.ParseForm()
-	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to parse form", slog.Any("error", err))
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		s.renderError(w, r, "Invalid user ID", apperr.Validation("Invalid user ID"))
 		return
 	}
 
-	n, err := strconv.Atoi(r.FormValue("n"))
-	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Invalid count", slog.Any("error", err))
-		fmt.Fprintf(w, errHTML, "Invalid count")
+	form := validate.NewForm(r)
+	n := form.Int("n", 1, maxUserEntries)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid count", err)
 		return
 	}
 
-	err = s.queries.UpdateUserN(r.Context(), &sqlc.UpdateUserNParams{
-		ID:      int64(userID),
-		EventID: int64(eventID),
-		N:       int32(n),
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		return setUserEntries(r.Context(), tx, int64(eventID), int64(userID), int32(n))
 	})
 	if err != nil {
-		s.logger.LogAttrs(r.Context(), slog.LevelError, "Failed to delete user", slog.Any("error", err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		s.renderError(w, r, "Failed to update user count", apperr.FromDB(err))
 		return
 	}
 
@@ -694,36 +1330,27 @@ func (s *Service) handleUpdateUserCount(w http.ResponseWriter, r *http.Request)
 }
 
 func (s *Service) handleQRCodePage(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	s.runTemplate(w, r, "qrcode-page", nil)
 }
 
 func (s *Service) handleQRCodeGeneration(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	form := validate.NewForm(r)
+	url := form.RequiredText("url", maxURLLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid QR code URL", err)
 		return
 	}
 
-	if err := r.ParseForm(); err != nil {
-		fmt.Fprintln(w, "Error:", err)
-		return
-	}
-
-	url := r.FormValue("url")
-
+	// The only input qrcode rejects is more than a code can hold
 	qr, err := qrcode.New(url, qrcode.Medium)
 	if err != nil {
-		fmt.Fprintln(w, "Error:", err)
+		s.renderError(w, r, "Failed to create QR code", apperr.Validation("The URL is too long for a QR code"))
 		return
 	}
 
 	png, err := qr.PNG(512)
 	if err != nil {
-		fmt.Fprintln(w, "Error:", err)
+		s.renderError(w, r, "Failed to render QR code", err)
 		return
 	}
 
@@ -0,0 +1,164 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"giveaway-tool/config"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/service"
+	"giveaway-tool/store/memory"
+)
+
+func TestAPIRegister(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		capacity int32
+		disabled bool
+		body     string
+		want     int
+		// stored and queued are how many participants and waitlist
+		// entries the event has after the request
+		stored int
+		queued int
+	}{
+		{name: "open", status: lifecycle.RegistrationOpen, body: `{"name":"Ann","username":"@ann"}`, want: http.StatusCreated, stored: 1},
+		{name: "full", status: lifecycle.RegistrationOpen, capacity: 1, body: `{"name":"Ann"}`, want: http.StatusAccepted, stored: 1, queued: 1},
+		{name: "no name", status: lifecycle.RegistrationOpen, body: `{"name":"  "}`, want: http.StatusBadRequest},
+		{name: "not json", status: lifecycle.RegistrationOpen, body: `name=Ann`, want: http.StatusBadRequest},
+		{name: "draft", status: lifecycle.Draft, body: `{"name":"Ann"}`, want: http.StatusNotFound},
+		{name: "not open yet", status: lifecycle.Published, body: `{"name":"Ann"}`, want: http.StatusConflict},
+		{name: "registration disabled", status: lifecycle.RegistrationOpen, disabled: true, body: `{"name":"Ann"}`, want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			st := memory.New()
+			server := newServer(t, st)
+			if err := config.SetFlag(ctx, st, config.FlagPublicRegistration, !tt.disabled); err != nil {
+				t.Fatal(err)
+			}
+			event := newEvent(t, st, tt.status, tt.capacity)
+			if tt.capacity > 0 {
+				// Someone registered before and took the only place
+				if _, err := st.CreateUser(ctx, &sqlc.CreateUserParams{Name: "Bob", EventID: event.ID, CheckInCode: "taken"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			resp, err := http.Post(fmt.Sprintf("%s/api/v1/events/%d/registrations", server.URL, event.ID), "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf("status %d, want %d: %s", resp.StatusCode, tt.want, body)
+			}
+
+			switch resp.StatusCode {
+			case http.StatusCreated:
+				var participant struct {
+					ID          int64  `json:"id"`
+					EventID     int64  `json:"event_id"`
+					CheckInCode string `json:"check_in_code"`
+					Username    string `json:"username"`
+				}
+				if err := json.NewDecoder(resp.Body).Decode(&participant); err != nil {
+					t.Fatal(err)
+				}
+				if participant.EventID != event.ID || participant.CheckInCode == "" || participant.Username != "ann" {
+					t.Errorf("participant = %+v", participant)
+				}
+			case http.StatusAccepted:
+				var entry struct {
+					WaitlistPosition int32 `json:"waitlist_position"`
+				}
+				if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+					t.Fatal(err)
+				}
+				if entry.WaitlistPosition != 1 {
+					t.Errorf("waitlist position %d, want 1", entry.WaitlistPosition)
+				}
+			}
+
+			users, err := st.GetUsersByEventID(ctx, event.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(users) != tt.stored {
+				t.Errorf("%d participants stored, want %d", len(users), tt.stored)
+			}
+			waitlist, err := st.GetWaitlistByEventID(ctx, event.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(waitlist) != tt.queued {
+				t.Errorf("%d waitlist entries, want %d", len(waitlist), tt.queued)
+			}
+		})
+	}
+}
+
+// newServer serves the app from st until the test ends.
+func newServer(t *testing.T, st *memory.Store) *httptest.Server {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	mux := http.NewServeMux()
+	service.Start(ctx, mux, slog.New(slog.NewTextHandler(io.Discard, nil)), st, "event_bot")
+	server := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		server.Close()
+		cancel()
+	})
+	return server
+}
+
+func newEvent(t *testing.T, st *memory.Store, status string, places int32) *sqlc.Events {
+	t.Helper()
+	ctx := context.Background()
+	event, err := st.CreateEvent(ctx, &sqlc.CreateEventParams{Name: "Event", Date: time.Now().Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event, err = st.SetEventStatus(ctx, &sqlc.SetEventStatusParams{ID: event.ID, Status: status}); err != nil {
+		t.Fatal(err)
+	}
+	if event, err = st.SetEventCapacity(ctx, &sqlc.SetEventCapacityParams{ID: event.ID, Capacity: places}); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestPages(t *testing.T) {
+	st := memory.New()
+	server := newServer(t, st)
+	newEvent(t, st, lifecycle.RegistrationOpen, 0)
+
+	for _, path := range []string{"/", "/login", "/qr-code", "/winners"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s: status %d: %s", path, resp.StatusCode, body)
+		}
+		if !strings.HasSuffix(strings.TrimSpace(string(body)), "</html>") {
+			t.Errorf("GET %s: page cut short", path)
+		}
+	}
+}
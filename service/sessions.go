@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/authz"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/sessionstore"
+)
+
+// defaultSessionDays is how long admins who ask to be remembered stay
+// signed in.
+const defaultSessionDays = 7
+
+// sessionMaxAgeFromEnv reads how long remembered sessions last from
+// SESSION_MAX_AGE_DAYS, in seconds as cookies take it.
+func sessionMaxAgeFromEnv(ctx context.Context, logger *slog.Logger) int {
+	days := defaultSessionDays
+	if v := os.Getenv("SESSION_MAX_AGE_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			logger.LogAttrs(ctx, slog.LevelWarn, "Invalid SESSION_MAX_AGE_DAYS, using the default",
+				slog.String("value", v), slog.Int("default", defaultSessionDays))
+		} else {
+			days = n
+		}
+	}
+	return days * 24 * 60 * 60
+}
+
+type sessionRow struct {
+	*sqlc.GetAdminSessionsRow
+	// Current is the session the page was opened with
+	Current bool
+}
+
+type sessionsData struct {
+	Sessions []sessionRow
+	// AllAdmins is set when the sessions of every admin are listed, not
+	// only those of the signed-in one
+	AllAdmins bool
+}
+
+// adminSessions returns the sessions the signed-in admin may see and
+// revoke: their own, or everyone's if they manage admins.
+func (s *Service) adminSessions(r *http.Request) (*sessionsData, error) {
+	ctx := r.Context()
+	rows, err := s.store.GetAdminSessions(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	current := ""
+	if session, err := s.sessionStore.Get(r, "session"); err == nil && session.ID != "" {
+		current = sessionstore.HashToken(session.ID)
+	}
+
+	admin := currentAdmin(ctx)
+	data := &sessionsData{AllAdmins: authz.Allowed(authz.RoleFromContext(ctx), authz.ManageAdmins)}
+	for _, row := range rows {
+		if !data.AllAdmins && row.AdminID.Int64 != admin.ID {
+			continue
+		}
+		data.Sessions = append(data.Sessions, sessionRow{GetAdminSessionsRow: row, Current: row.TokenHash == current})
+	}
+	return data, nil
+}
+
+// handleSessionsPage lists the devices admins are signed in on.
+func (s *Service) handleSessionsPage(w http.ResponseWriter, r *http.Request) {
+	data, err := s.adminSessions(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get sessions", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_sessions", data)
+}
+
+// handleRevokeSession signs a device out. Revoking the current session
+// signs the admin out on their next request.
+func (s *Service) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid session ID", apperr.Validation("Invalid session ID"))
+		return
+	}
+
+	data, err := s.adminSessions(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get sessions", apperr.FromDB(err))
+		return
+	}
+	var revoked *sessionRow
+	for i := range data.Sessions {
+		if data.Sessions[i].ID == id {
+			revoked = &data.Sessions[i]
+		}
+	}
+	if revoked == nil {
+		s.renderError(w, r, "Failed to revoke session", apperr.NotFound("Session not found"))
+		return
+	}
+
+	if _, err := s.store.DeleteSessionByID(r.Context(), id); err != nil {
+		s.renderError(w, r, "Failed to revoke session", apperr.FromDB(err))
+		return
+	}
+	if err := audit.Record(r.Context(), s.store, audit.SessionRevoked, 0,
+		map[string]string{"admin": revoked.Username, "user_agent": revoked.UserAgent, "ip": revoked.Ip}, nil); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to record audit log", slog.Any("error", err))
+	}
+
+	if revoked.Current {
+		w.Header().Set("HX-Redirect", "/login")
+		return
+	}
+	data, err = s.adminSessions(r)
+	if err != nil {
+		s.renderError(w, r, "Failed to get sessions", apperr.FromDB(err))
+		return
+	}
+	s.runTemplate(w, r, "admin_sessions_list", data)
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/config"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/router"
+	"giveaway-tool/store"
+	"giveaway-tool/validate"
+)
+
+// maxSharesPerVisitor caps how many participants' links one visitor can
+// count towards in an event, so a single person can't hand out bonuses by
+// opening everyone's links.
+const maxSharesPerVisitor = 3
+
+type sharesData struct {
+	Event *sqlc.Events
+	Rows  []*sqlc.GetShareReportRow
+}
+
+const (
+	// visitorCookie holds the signed token that marks a browser as one that
+	// opened a share link before.
+	visitorCookie = "visitor"
+	// visitorCookieTTL is how long the visitor cookie lasts.
+	visitorCookieTTL = 365 * 24 * time.Hour
+	// visitorCheckParam is added to the share link when redirecting back to
+	// it after setting the cookie, so browsers that refuse cookies aren't
+	// sent round in circles.
+	visitorCheckParam = "v"
+)
+
+// visitorHash identifies a share link visitor by their real address,
+// without keeping it. The User-Agent is left out, since clients choose it.
+func visitorHash(r *http.Request) string {
+	sum := sha256.Sum256([]byte(router.ClientIP(r)))
+	return hex.EncodeToString(sum[:])
+}
+
+// hasVisitorCookie reports whether r carries a visitor cookie the app
+// signed.
+func (s *Service) hasVisitorCookie(r *http.Request) bool {
+	cookie, err := r.Cookie(visitorCookie)
+	if err != nil {
+		return false
+	}
+	_, err = s.links.VerifyVisitor(cookie.Value)
+	return err == nil
+}
+
+// handleShareLink counts a visit to a participant's share link and sends
+// the visitor on to registration. Visits are counted once per visitor, and
+// the participant gets the event's share bonus once enough distinct
+// visitors have opened the link. Only browsers that keep the signed visitor
+// cookie are counted, so scripts can't make up visits, and none are
+// without MAGIC_LINK_SECRET to sign it.
+func (s *Service) handleShareLink(w http.ResponseWriter, r *http.Request) {
+	user, err := s.store.GetUserByShareCode(r.Context(), r.PathValue("code"))
+	if err != nil {
+		s.renderError(w, r, "Failed to open share link", apperr.FromDB(err))
+		return
+	}
+
+	switch {
+	case s.links == nil:
+	case s.hasVisitorCookie(r):
+		if err := s.countShareClick(r.Context(), user, visitorHash(r)); err != nil {
+			// The visitor should still get to registration
+			logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to count share click",
+				slog.Int64("user_id", user.ID), slog.Any("error", err))
+		}
+	case r.URL.Query().Get(visitorCheckParam) == "":
+		// The visit counts once the browser comes back with the cookie
+		http.SetCookie(w, &http.Cookie{
+			Name:     visitorCookie,
+			Value:    s.links.VisitorToken(cryptoRand.Text(), visitorCookieTTL),
+			Path:     "/s/",
+			MaxAge:   int(visitorCookieTTL.Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		http.Redirect(w, r, r.URL.Path+"?"+visitorCheckParam+"=1", http.StatusSeeOther)
+		return
+	}
+
+	target := "/"
+	if config.FlagEnabled(config.FlagPublicRegistration) {
+		target = "/events/" + strconv.FormatInt(user.EventID, 10) + "/register"
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+func (s *Service) countShareClick(ctx context.Context, user *sqlc.Users, visitor string) error {
+	return s.store.InTx(ctx, func(tx store.Store) error {
+		event, err := tx.GetEventByID(ctx, user.EventID)
+		if err != nil || event.ShareClicksRequired == 0 {
+			return err
+		}
+
+		visited, err := tx.CountShareClicksByVisitor(ctx, &sqlc.CountShareClicksByVisitorParams{
+			EventID:     user.EventID,
+			VisitorHash: visitor,
+		})
+		if err != nil || visited >= maxSharesPerVisitor {
+			return err
+		}
+
+		recorded, err := tx.RecordShareClick(ctx, &sqlc.RecordShareClickParams{
+			UserID:      user.ID,
+			EventID:     user.EventID,
+			VisitorHash: visitor,
+		})
+		if err != nil || recorded == 0 {
+			return err
+		}
+
+		clicks, err := tx.CountShareClicks(ctx, user.ID)
+		if err != nil || clicks < int64(event.ShareClicksRequired) {
+			return err
+		}
+
+		granted, err := tx.GrantShareBonus(ctx, &sqlc.GrantShareBonusParams{
+			ID:      user.ID,
+			Entries: event.ShareBonus,
+		})
+		if err == nil && granted > 0 {
+			logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "Granted share bonus",
+				slog.Int64("event_id", user.EventID),
+				slog.Int64("user_id", user.ID),
+				slog.Int64("clicks", clicks))
+		}
+		return err
+	})
+}
+
+func (s *Service) sharesData(ctx context.Context, eventID int64) (*sharesData, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.store.GetShareReport(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &sharesData{Event: event, Rows: rows}, nil
+}
+
+func (s *Service) handleSharesPage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	data, err := s.sharesData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get share report", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_shares", data)
+}
+
+func (s *Service) handleSetShareBonus(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	clicks := form.Int("clicks_required", 0, maxShareClicks)
+	bonus := form.Int("bonus", 1, maxBonusEntries)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid share settings", err)
+		return
+	}
+
+	event, err := s.store.SetEventShareBonus(r.Context(), &sqlc.SetEventShareBonusParams{
+		ID:                  eventID,
+		ShareClicksRequired: int32(clicks),
+		ShareBonus:          int32(bonus),
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update share settings", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_share_settings", event)
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/logging"
+	"giveaway-tool/slug"
+	"giveaway-tool/validate"
+)
+
+// Where short links lead.
+const (
+	shortLinkBot  = "bot"
+	shortLinkPage = "page"
+)
+
+type shortLinksData struct {
+	Event *sqlc.Events
+	Links []*sqlc.ShortLinks
+	// SiteURL is prepended to the codes to show the full links
+	SiteURL string
+}
+
+func (s *Service) shortLinksData(ctx context.Context, eventID int64) (*shortLinksData, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.store.GetShortLinksByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &shortLinksData{Event: event, Links: links}, nil
+}
+
+func (s *Service) renderShortLinks(w http.ResponseWriter, r *http.Request, name string, eventID int64) {
+	data, err := s.shortLinksData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get short links", apperr.FromDB(err))
+		return
+	}
+	data.SiteURL = s.siteURL(r)
+	s.runTemplate(w, r, name, data)
+}
+
+// handleShortLinksPage lists the event's short links with how many visits
+// each of them brought.
+func (s *Service) handleShortLinksPage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+	s.renderShortLinks(w, r, "admin_short_links", eventID)
+}
+
+// handleCreateShortLink adds a short link for one promo channel. Codes are
+// made URL-safe like slugs, and made up when left empty.
+func (s *Service) handleCreateShortLink(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	arg := &sqlc.CreateShortLinkParams{
+		EventID: eventID,
+		Code:    slug.Make(form.Text("code", maxPromoCodeLength)),
+		Channel: form.RequiredText("channel", maxNameLength),
+		Target:  r.FormValue("target"),
+	}
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid short link", err)
+		return
+	}
+	if arg.Target != shortLinkBot && arg.Target != shortLinkPage {
+		s.renderError(w, r, "Invalid short link", apperr.Validation("Invalid short link target"))
+		return
+	}
+	if arg.Code == "" {
+		arg.Code = strings.ToLower(cryptoRand.Text()[:generatedCodeLength])
+	}
+
+	link, err := s.store.CreateShortLink(r.Context(), arg)
+	if err != nil {
+		s.renderError(w, r, "Failed to create short link", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Created short link",
+		slog.Int64("event_id", eventID), slog.Int64("short_link_id", link.ID), slog.String("code", link.Code))
+
+	s.renderShortLinks(w, r, "admin_short_links_table", eventID)
+}
+
+// handleDeleteShortLink deletes a link along with its clicks. The code is
+// free for another link afterwards.
+func (s *Service) handleDeleteShortLink(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+	linkID, err := strconv.ParseInt(r.PathValue("linkID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid short link ID", apperr.Validation("Invalid short link ID"))
+		return
+	}
+
+	if err := s.store.DeleteShortLink(r.Context(), &sqlc.DeleteShortLinkParams{
+		ID:      linkID,
+		EventID: eventID,
+	}); err != nil {
+		s.renderError(w, r, "Failed to delete short link", apperr.FromDB(err))
+		return
+	}
+
+	s.renderShortLinks(w, r, "admin_short_links_table", eventID)
+}
+
+// handleShortLink counts a visit through the short link and sends the
+// visitor to the bot or the event's public page. Links of events that are
+// deleted or not published lead nowhere and count nothing. The redirect is
+// temporary, so browsers come back and every visit is counted.
+func (s *Service) handleShortLink(w http.ResponseWriter, r *http.Request) {
+	link, err := s.store.GetShortLinkByCode(r.Context(), r.PathValue("code"))
+	if err != nil {
+		s.renderError(w, r, "Failed to open short link", apperr.FromDB(err))
+		return
+	}
+	// Deleted events aren't found
+	event, err := s.store.GetEventByID(r.Context(), link.EventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	if !lifecycle.Public(event) {
+		s.renderError(w, r, "Event is not published", apperr.NotFound("Event not found"))
+		return
+	}
+	if err := s.store.ClickShortLink(r.Context(), link.ID); err != nil {
+		// The visitor should still get to the event
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to count short link click",
+			slog.Int64("short_link_id", link.ID), slog.Any("error", err))
+	}
+
+	// Links to the bot go to the event page while the bot isn't connected
+	target := s.botStartURL(event.ID)
+	if link.Target == shortLinkPage || target == "" {
+		target = s.eventPageURL(r, event)
+	}
+	http.Redirect(w, r, target, http.StatusFound)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/authz"
+	"giveaway-tool/livecount"
+	"giveaway-tool/notify"
+)
+
+// streamKeepAlive is how often an idle stream sends a comment, so proxies
+// don't close the connection.
+const streamKeepAlive = 30 * time.Second
+
+// handleNotificationStream pushes live notices to the admin panel as
+// server-sent events until the browser disconnects or the server shuts
+// down. The route is registered without the request timeout.
+func (s *Service) handleNotificationStream(w http.ResponseWriter, r *http.Request) {
+	notices, unsubscribe := notify.Subscribe()
+	defer unsubscribe()
+
+	rc := http.NewResponseController(w)
+	if err := startStream(w, rc); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.stopping:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case notice := <-notices:
+			// Admins hear only about the events they organize
+			if notice.EventID != 0 && !authz.EventAllowed(r.Context(), notice.EventID) {
+				continue
+			}
+			data, err := json.Marshal(notice)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: notice\ndata: %s\n\n", data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// handleEventStream pushes the participant count of an event as
+// server-sent events, first the current one and then each change, so the
+// admin pages follow registrations live. The route is registered without
+// the request timeout.
+func (s *Service) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+	// Subscribing before counting keeps registrations made in between
+	// from being missed
+	counts, unsubscribe := livecount.Subscribe(eventID)
+	defer unsubscribe()
+	if _, err := s.store.GetEventByID(r.Context(), eventID); err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+	count, err := s.store.CountUsersByEventID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to count users", apperr.FromDB(err))
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := startStream(w, rc); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	fmt.Fprintf(w, "event: count\ndata: %d\n\n", count)
+	for {
+		if err := rc.Flush(); err != nil {
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.stopping:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case count := <-counts:
+			fmt.Fprintf(w, "event: count\ndata: %d\n\n", count)
+		}
+	}
+}
+
+// startStream sends the headers of a server-sent event stream.
+func startStream(w http.ResponseWriter, rc *http.ResponseController) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	// Keep nginx and similar proxies from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	return rc.Flush()
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/validate"
+)
+
+// normalizeTags lowercases tags so "VIP" and "vip" are the same label, and
+// sorts them without duplicates.
+func normalizeTags(tags []string) []string {
+	for i, tag := range tags {
+		tags[i] = strings.ToLower(tag)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// tagFilter selects participants by tag for draws and exports.
+type tagFilter struct {
+	// Include keeps only participants with at least one of these tags
+	Include []string
+	// Exclude drops participants with any of these tags
+	Exclude []string
+}
+
+func (f tagFilter) match(tags []string) bool {
+	if len(f.Include) > 0 && !slices.ContainsFunc(f.Include, func(tag string) bool { return slices.Contains(tags, tag) }) {
+		return false
+	}
+	return !slices.ContainsFunc(f.Exclude, func(tag string) bool { return slices.Contains(tags, tag) })
+}
+
+// handleSetUserTags replaces the tags of a participant.
+func (s *Service) handleSetUserTags(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("eventID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("userID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid user ID", apperr.Validation("Invalid user ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	tags := form.List("tags", maxTagsPerUser, maxTagLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid tags", err)
+		return
+	}
+
+	user, err := s.store.SetUserTags(r.Context(), &sqlc.SetUserTagsParams{
+		ID:      userID,
+		EventID: eventID,
+		Tags:    normalizeTags(tags),
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update tags", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_user_tags", user)
+}
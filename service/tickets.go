@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/capacity"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/payments"
+	"giveaway-tool/promo"
+	"giveaway-tool/store"
+	"giveaway-tool/tickettype"
+)
+
+// ticketOrderURL is where the buyer returns after paying and sees their
+// ticket once the payment is confirmed. The random order ID is what lets
+// them see it.
+func (s *Service) ticketOrderURL(orderID string) string {
+	return s.publicURL + "/orders/" + orderID
+}
+
+// startTicketOrder saves a paid registration from the web form and sends
+// the buyer to the provider's payment page. The participant is created by
+// the provider's callback once the payment is confirmed.
+func (s *Service) startTicketOrder(w http.ResponseWriter, r *http.Request, event *sqlc.Events, req registrationRequest) {
+	if s.payments == nil || s.publicURL == "" {
+		s.renderError(w, r, "Payments are disabled", apperr.NotFound("Paid registration is not available"))
+		return
+	}
+	if err := req.validate(); err != nil {
+		s.renderError(w, r, "Invalid registration", err)
+		return
+	}
+
+	var order *sqlc.TicketOrders
+	err := s.store.InTx(r.Context(), func(tx store.Store) error {
+		// The order holds a place of the event and its type until it is
+		// paid or the checkout runs out
+		if err := capacity.Reserve(r.Context(), tx, event.ID); err != nil {
+			return err
+		}
+		ticketType, err := tickettype.Reserve(r.Context(), tx, event.ID, req.TicketTypeID)
+		if err != nil {
+			return err
+		}
+		price := tickettype.Price(event, ticketType)
+		amount := price
+
+		var code *sqlc.PromoCodes
+		if req.PromoCode != "" {
+			if code, err = promo.Use(r.Context(), tx, event.ID, price, req.PromoCode); err != nil {
+				return err
+			}
+			amount = promo.Price(code, price)
+		}
+
+		order, err = tx.CreateTicketOrder(r.Context(), &sqlc.CreateTicketOrderParams{
+			OrderID:      cryptoRand.Text(),
+			EventID:      event.ID,
+			Name:         req.Name,
+			Username:     req.Username,
+			Amount:       amount,
+			TicketTypeID: tickettype.ID(ticketType),
+		})
+		if err != nil || code == nil {
+			return err
+		}
+		return promo.Reserve(r.Context(), tx, code, order.OrderID, price-amount)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to create ticket order", apperr.FromDB(err))
+		return
+	}
+
+	checkoutURL, err := s.payments.CheckoutURL(r.Context(), payments.Order{
+		ID:          order.OrderID,
+		Description: fmt.Sprintf("Квиток – %s", event.Name),
+		Amount:      order.Amount,
+		ResultURL:   s.ticketOrderURL(order.OrderID),
+	})
+	if err != nil {
+		// The order can't be paid, so it gives its promo code back
+		if err := s.failTicketOrder(r.Context(), order.OrderID); err != nil {
+			logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to cancel ticket order",
+				slog.String("order_id", order.OrderID), slog.Any("error", err))
+		}
+		s.renderError(w, r, "Failed to start checkout", err)
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Started ticket order",
+		slog.Int64("event_id", event.ID), slog.String("order_id", order.OrderID))
+
+	w.Header().Set("HX-Redirect", checkoutURL)
+	// Replays of the request only get the body, so it links to the
+	// checkout too
+	fmt.Fprintf(w, successHTML, fmt.Sprintf(`Переходимо до оплати… Якщо сторінка не відкрилася, <a href="%s" class="underline">натисни тут</a>.`, template.HTMLEscapeString(checkoutURL)))
+}
+
+// settleTicketOrder registers the buyer of a paid ticket order. It returns
+// nil if the callback isn't about a pending ticket order, which includes
+// orders already settled by an earlier callback.
+func settleTicketOrder(ctx context.Context, tx store.Store, callback *payments.Callback) (*sqlc.Users, error) {
+	order, err := tx.MarkTicketOrderPaid(ctx, &sqlc.MarkTicketOrderPaidParams{
+		OrderID:   callback.OrderID,
+		PaymentID: callback.PaymentID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The buyer has paid, so they are registered even if the event
+	// started while they were paying or it or their type was filled up by
+	// hand
+	var ticketType *sqlc.TicketTypes
+	if order.TicketTypeID.Valid {
+		ticketType, err = tx.GetTicketType(ctx, &sqlc.GetTicketTypeParams{ID: order.TicketTypeID.Int64, EventID: order.EventID})
+		if err != nil {
+			return nil, err
+		}
+	}
+	user, err := createParticipant(ctx, tx, order.EventID, ticketType, registrationRequest{
+		Name:     order.Name,
+		Username: order.Username,
+	}, consent.SourceWebsite)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.SetTicketOrderUser(ctx, &sqlc.SetTicketOrderUserParams{ID: order.ID, UserID: user.ID}); err != nil {
+		return nil, err
+	}
+	if err := promo.Settle(ctx, tx, order.OrderID, user); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "Ticket order paid",
+		slog.Int64("user_id", user.ID), slog.String("order_id", callback.OrderID))
+	return user, nil
+}
+
+// failTicketOrder records that a ticket order won't be paid, giving back
+// the use of its promo code.
+func (s *Service) failTicketOrder(ctx context.Context, orderID string) error {
+	if err := s.store.MarkTicketOrderFailed(ctx, orderID); err != nil {
+		return err
+	}
+	return s.store.ReleasePromoRedemption(ctx, orderID)
+}
+
+type ticketOrderData struct {
+	Order *sqlc.TicketOrders
+	Event *sqlc.Events
+	// User is the registered participant once the order is paid, and Seat
+	// their seat, if any
+	User  *sqlc.Users
+	Seat  string
+	Price string
+	// SelfServiceURL is the participant's self-service link, or "" if
+	// links aren't configured
+	SelfServiceURL string
+}
+
+// handleTicketOrderPage shows a ticket order's status to the buyer coming
+// back from the payment page. It reloads itself until the provider's
+// callback arrives.
+func (s *Service) handleTicketOrderPage(w http.ResponseWriter, r *http.Request) {
+	order, err := s.store.GetTicketOrder(r.Context(), r.PathValue("orderID"))
+	if err != nil {
+		s.renderError(w, r, "Failed to get ticket order", apperr.FromDB(err))
+		return
+	}
+	event, err := s.store.GetEventByID(r.Context(), order.EventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+
+	data := ticketOrderData{Order: order, Event: event, Price: formatPrice(order.Amount)}
+	if order.UserID.Valid {
+		if data.User, err = s.store.GetUserByID(r.Context(), order.UserID.Int64); err != nil {
+			s.renderError(w, r, "Failed to get participant", apperr.FromDB(err))
+			return
+		}
+		data.Seat = s.seatLabel(r.Context(), data.User.ID)
+		if s.links != nil {
+			data.SelfServiceURL = s.links.URL(data.User.ID)
+		}
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	s.runTemplate(w, r, "ticket_order", data)
+}
+
+func (s *Service) handleSetTicketPrice(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	price, err := strconv.ParseFloat(r.FormValue("ticket_price"), 64)
+	if err != nil || price < 0 || price > math.MaxInt32/100 {
+		s.renderError(w, r, "Invalid ticket price", apperr.Validation("Invalid ticket price"))
+		return
+	}
+	if price > 0 && s.payments == nil {
+		s.renderError(w, r, "Payments are disabled", apperr.Validation("Paid tickets need a payment provider"))
+		return
+	}
+
+	event, err := s.store.SetEventTicketPrice(r.Context(), &sqlc.SetEventTicketPriceParams{
+		ID:          eventID,
+		TicketPrice: int32(math.Round(price * 100)),
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update ticket price", apperr.FromDB(err))
+		return
+	}
+
+	if event.TicketPrice == 0 {
+		fmt.Fprintf(w, successHTML, "Збережено: реєстрація безкоштовна")
+		return
+	}
+	fmt.Fprintf(w, successHTML, fmt.Sprintf("Збережено: квиток коштує %s", formatPrice(event.TicketPrice)))
+}
+
+type paymentsData struct {
+	Event    *sqlc.Events
+	Payments []*sqlc.GetPaymentsByEventIDRow
+	// Paid and Refunded are the totals of paid and refunded payments
+	Paid     string
+	Refunded string
+	// Refunds is false when no provider is configured to refund through
+	Refunds bool
+}
+
+func (s *Service) paymentsData(ctx context.Context, eventID int64) (*paymentsData, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.store.GetPaymentsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var paid, refunded int32
+	for _, row := range rows {
+		switch row.Status {
+		case "paid":
+			paid += row.Amount
+		case "refunded":
+			refunded += row.Amount
+		}
+	}
+	return &paymentsData{
+		Event:    event,
+		Payments: rows,
+		Paid:     formatPrice(paid),
+		Refunded: formatPrice(refunded),
+		Refunds:  s.payments != nil,
+	}, nil
+}
+
+// handlePaymentsPage lists the event's ticket orders and entry purchases.
+func (s *Service) handlePaymentsPage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	data, err := s.paymentsData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get payments", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_payments", data)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/validate"
+)
+
+// maxTicketTypeCapacity caps the places of one ticket type.
+const maxTicketTypeCapacity = 100000
+
+type ticketTypesData struct {
+	Event *sqlc.Events
+	Types []*sqlc.GetTicketTypeStatsRow
+}
+
+func (s *Service) ticketTypesData(ctx context.Context, eventID int64) (*ticketTypesData, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := s.store.GetTicketTypeStats(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &ticketTypesData{Event: event, Types: types}, nil
+}
+
+func (s *Service) renderTicketTypes(w http.ResponseWriter, r *http.Request, eventID int64) {
+	data, err := s.ticketTypesData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get ticket types", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_ticket_types_table", data)
+}
+
+// handleTicketTypesPage lists the event's ticket types with how many
+// participants each of them has.
+func (s *Service) handleTicketTypesPage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	data, err := s.ticketTypesData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get ticket types", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_ticket_types", data)
+}
+
+func (s *Service) handleCreateTicketType(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	arg := &sqlc.CreateTicketTypeParams{
+		EventID: eventID,
+		Name:    form.RequiredText("name", maxNameLength),
+		Weight:  int32(form.Int("weight", 1, maxUserEntries)),
+	}
+	if capacity, ok := form.OptionalInt("capacity", 1, maxTicketTypeCapacity); ok {
+		arg.Capacity = int32(capacity)
+	}
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid ticket type", err)
+		return
+	}
+
+	if v := r.FormValue("price"); v != "" {
+		price, err := strconv.ParseFloat(v, 64)
+		if err != nil || price < 0 || price > math.MaxInt32/100 {
+			s.renderError(w, r, "Invalid ticket type", apperr.Validation("Invalid ticket price"))
+			return
+		}
+		arg.Price = int32(math.Round(price * 100))
+	}
+	if arg.Price > 0 && s.payments == nil {
+		s.renderError(w, r, "Payments are disabled", apperr.Validation("Paid tickets need a payment provider"))
+		return
+	}
+
+	ticketType, err := s.store.CreateTicketType(r.Context(), arg)
+	if err != nil {
+		s.renderError(w, r, "Failed to create ticket type", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Created ticket type",
+		slog.Int64("event_id", eventID), slog.Int64("ticket_type_id", ticketType.ID))
+
+	s.renderTicketTypes(w, r, eventID)
+}
+
+// handleDeleteTicketType deletes a ticket type. Its participants stay
+// registered without a type and keep their entries.
+func (s *Service) handleDeleteTicketType(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	typeID, err := strconv.ParseInt(r.PathValue("typeID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid ticket type ID", apperr.Validation("Invalid ticket type ID"))
+		return
+	}
+
+	if err := s.store.DeleteTicketType(r.Context(), &sqlc.DeleteTicketTypeParams{
+		ID:      typeID,
+		EventID: eventID,
+	}); err != nil {
+		s.renderError(w, r, "Failed to delete ticket type", apperr.FromDB(err))
+		return
+	}
+
+	s.renderTicketTypes(w, r, eventID)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/authz"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/validate"
+)
+
+// tokenPrefix starts every API token, so leaked ones are easy to search
+// for.
+const tokenPrefix = "gt_"
+
+// tokenTouchInterval is how often a token's last use is saved, so busy
+// scripts don't write on every call.
+const tokenTouchInterval = time.Minute
+
+// hashToken returns what is stored of token. Tokens are random, so a fast
+// hash is enough.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenContext returns a copy of ctx acting as the admin who minted token,
+// limited to its scopes.
+func (s *Service) tokenContext(ctx context.Context, token string) (context.Context, error) {
+	stored, err := s.store.GetTokenByHash(ctx, hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperr.Unauthorized("Invalid API token")
+	}
+	if err != nil {
+		return nil, apperr.FromDB(err)
+	}
+	admin, err := s.store.GetAdminByID(ctx, stored.AdminID)
+	if err != nil {
+		return nil, apperr.FromDB(err)
+	}
+
+	if !stored.LastUsedAt.Valid || time.Since(stored.LastUsedAt.Time) >= tokenTouchInterval {
+		if err := s.store.TouchToken(ctx, &sqlc.TouchTokenParams{
+			ID:         stored.ID,
+			LastUsedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}); err != nil {
+			logging.FromContext(ctx).LogAttrs(ctx, slog.LevelWarn, "Failed to save token use", slog.Any("error", err))
+		}
+	}
+
+	scopes := make([]authz.Scope, 0, len(stored.Scopes))
+	for _, scope := range stored.Scopes {
+		scopes = append(scopes, authz.Scope(scope))
+	}
+	if ctx, err = s.withAdmin(ctx, admin); err != nil {
+		return nil, apperr.FromDB(err)
+	}
+	ctx = logging.With(ctx, slog.String("token", stored.Name))
+	ctx = context.WithValue(ctx, tokenKey{}, stored.ID)
+	return authz.WithScopes(ctx, scopes), nil
+}
+
+type tokenKey struct{}
+
+// currentTokenID returns the ID of the token stored by tokenContext, or 0
+// for requests made without one.
+func currentTokenID(ctx context.Context) int64 {
+	id, _ := ctx.Value(tokenKey{}).(int64)
+	return id
+}
+
+type tokensData struct {
+	Tokens []*sqlc.Tokens
+	// Scopes are those the admin's role lets their tokens have
+	Scopes []authz.Scope
+	// Created is a token just minted, shown this once
+	Created string
+}
+
+func (s *Service) tokensData(ctx context.Context) (*tokensData, error) {
+	admin := currentAdmin(ctx)
+	tokens, err := s.store.GetTokensByAdminID(ctx, admin.ID)
+	if err != nil {
+		return nil, err
+	}
+	role := authz.RoleFromContext(ctx)
+	return &tokensData{
+		Tokens: tokens,
+		Scopes: slices.DeleteFunc(slices.Clone(authz.Scopes), func(scope authz.Scope) bool { return !authz.Grants(role, scope) }),
+	}, nil
+}
+
+// handleTokensPage lists the API tokens of the signed-in admin.
+func (s *Service) handleTokensPage(w http.ResponseWriter, r *http.Request) {
+	data, err := s.tokensData(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get tokens", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_tokens", data)
+}
+
+// handleCreateToken mints an API token for the signed-in admin with the
+// scopes checked in the form. The token is shown once and only its hash
+// is kept.
+func (s *Service) handleCreateToken(w http.ResponseWriter, r *http.Request) {
+	form := validate.NewForm(r)
+	name := form.RequiredText("name", maxNameLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid token", err)
+		return
+	}
+
+	role := authz.RoleFromContext(r.Context())
+	scopes := r.Form["scope"]
+	if len(scopes) == 0 {
+		s.renderError(w, r, "Invalid token", apperr.Validation("Choose at least one scope"))
+		return
+	}
+	for _, scope := range scopes {
+		if !authz.Grants(role, authz.Scope(scope)) {
+			s.renderError(w, r, "Invalid token", apperr.Forbidden("Your role doesn't allow the "+scope+" scope"))
+			return
+		}
+	}
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	secret := tokenPrefix + cryptoRand.Text()
+	token, err := s.store.CreateToken(r.Context(), &sqlc.CreateTokenParams{
+		AdminID:   currentAdmin(r.Context()).ID,
+		Name:      name,
+		TokenHash: hashToken(secret),
+		Scopes:    scopes,
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to create token", apperr.FromDB(err))
+		return
+	}
+	if err := audit.Record(r.Context(), s.store, audit.TokenCreated, 0, nil, token); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to record audit log", slog.Any("error", err))
+	}
+
+	data, err := s.tokensData(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get tokens", apperr.FromDB(err))
+		return
+	}
+	data.Created = secret
+	s.runTemplate(w, r, "admin_tokens_list", data)
+}
+
+// handleDeleteToken revokes one of the signed-in admin's tokens.
+func (s *Service) handleDeleteToken(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid token ID", apperr.Validation("Invalid token ID"))
+		return
+	}
+
+	deleted, err := s.store.DeleteToken(r.Context(), &sqlc.DeleteTokenParams{
+		ID:      id,
+		AdminID: currentAdmin(r.Context()).ID,
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to delete token", apperr.FromDB(err))
+		return
+	}
+	if deleted == 0 {
+		s.renderError(w, r, "Failed to delete token", apperr.NotFound("Token not found"))
+		return
+	}
+	if err := audit.Record(r.Context(), s.store, audit.TokenRevoked, 0,
+		map[string]int64{"id": id}, nil); err != nil {
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "Failed to record audit log", slog.Any("error", err))
+	}
+
+	data, err := s.tokensData(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get tokens", apperr.FromDB(err))
+		return
+	}
+	s.runTemplate(w, r, "admin_tokens_list", data)
+}
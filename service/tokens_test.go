@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store/memory"
+)
+
+func TestAPITokenScopes(t *testing.T) {
+	const event = `{"name":"Meetup","date":"2030-01-01T18:00:00Z"}`
+	tests := []struct {
+		name   string
+		role   string
+		scopes []string
+		// token is sent instead of the minted one when set
+		token   string
+		noToken bool
+		method  string
+		body    string
+		want    int
+	}{
+		{name: "read", role: "owner", scopes: []string{"events:read"}, method: http.MethodGet, want: http.StatusOK},
+		{name: "write", role: "admin", scopes: []string{"events:read", "events:write"}, method: http.MethodPost, body: event, want: http.StatusCreated},
+		{name: "read only", role: "owner", scopes: []string{"events:read"}, method: http.MethodPost, body: event, want: http.StatusForbidden},
+		{name: "other scope", role: "owner", scopes: []string{"participants:read"}, method: http.MethodGet, want: http.StatusForbidden},
+		{name: "scope beyond role", role: "viewer", scopes: []string{"events:write"}, method: http.MethodPost, body: event, want: http.StatusForbidden},
+		{name: "unknown token", role: "owner", scopes: []string{"events:read"}, token: "gt_made_up", method: http.MethodGet, want: http.StatusUnauthorized},
+		{name: "no token", role: "owner", scopes: []string{"events:read"}, noToken: true, method: http.MethodGet, want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := memory.New()
+			server := newServer(t, st)
+			admin := newAdmin(t, st, "owner", tt.role)
+			token := "gt_" + tt.name
+			sum := sha256.Sum256([]byte(token))
+			if _, err := st.CreateToken(context.Background(), &sqlc.CreateTokenParams{
+				AdminID:   admin.ID,
+				Name:      "script",
+				TokenHash: hex.EncodeToString(sum[:]),
+				Scopes:    tt.scopes,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.token != "" {
+				token = tt.token
+			}
+
+			r, err := http.NewRequest(tt.method, server.URL+"/api/v1/events", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Header.Set("Content-Type", "application/json")
+			if !tt.noToken {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+			resp, err := http.DefaultClient.Do(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/i18n"
+	"giveaway-tool/validate"
+)
+
+// language picks the language of a public page: the one chosen with ?lang=,
+// or else the one the browser prefers.
+func language(w http.ResponseWriter, r *http.Request) string {
+	w.Header().Add("Vary", "Accept-Language")
+	if lang := i18n.Normalize(r.URL.Query().Get("lang")); lang != "" {
+		return lang
+	}
+	return i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// translations returns the translations to lang by event ID.
+func (s *Service) translations(ctx context.Context, lang string) (map[int64]*sqlc.EventTranslations, error) {
+	byEvent := make(map[int64]*sqlc.EventTranslations)
+	if lang == i18n.Default {
+		return byEvent, nil
+	}
+
+	translations, err := s.store.GetTranslationsByLanguage(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+	for _, translation := range translations {
+		byEvent[translation.EventID] = translation
+	}
+	return byEvent, nil
+}
+
+// translateEvents returns copies of events in lang. The events themselves
+// may be shared with the store cache and are left as they are.
+func (s *Service) translateEvents(ctx context.Context, events []*sqlc.Events, lang string) ([]*sqlc.Events, error) {
+	if lang == i18n.Default {
+		return events, nil
+	}
+
+	byEvent, err := s.translations(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+	translated := make([]*sqlc.Events, len(events))
+	for i, event := range events {
+		translated[i] = i18n.Translate(event, byEvent[event.ID])
+	}
+	return translated, nil
+}
+
+// translateEvent returns a copy of event in lang.
+func (s *Service) translateEvent(ctx context.Context, event *sqlc.Events, lang string) (*sqlc.Events, error) {
+	if lang == i18n.Default {
+		return event, nil
+	}
+
+	translation, err := s.store.GetEventTranslation(ctx, &sqlc.GetEventTranslationParams{EventID: event.ID, Language: lang})
+	if errors.Is(err, sql.ErrNoRows) {
+		return event, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Translate(event, translation), nil
+}
+
+// eventTranslation is one language's form on the admin event page, shown
+// next to the original text.
+type eventTranslation struct {
+	EventID             int64
+	Language            i18n.Language
+	Name                string
+	Description         string
+	OriginalName        string
+	OriginalDescription string
+}
+
+// eventTranslations lists a form for every language the event can be
+// translated to, filled in where a translation exists.
+func (s *Service) eventTranslations(ctx context.Context, event *sqlc.Events) ([]*eventTranslation, error) {
+	saved, err := s.store.GetEventTranslations(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make([]*eventTranslation, 0, len(i18n.Translatable()))
+	for _, lang := range i18n.Translatable() {
+		t := &eventTranslation{
+			EventID:             event.ID,
+			Language:            lang,
+			OriginalName:        event.Name,
+			OriginalDescription: event.Description.String,
+		}
+		for _, translation := range saved {
+			if translation.Language == lang.Code {
+				t.Name, t.Description = translation.Name, translation.Description
+			}
+		}
+		translations = append(translations, t)
+	}
+	return translations, nil
+}
+
+// handleSaveEventTranslation saves the event's name and description in one
+// language. Clearing both fields removes the translation.
+func (s *Service) handleSaveEventTranslation(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	lang, ok := i18n.Find(r.PathValue("lang"))
+	if !ok || lang.Code == i18n.Default {
+		s.renderError(w, r, "Invalid language", apperr.Validation("Unsupported language"))
+		return
+	}
+
+	form := validate.NewForm(r)
+	name := form.Text("name", maxNameLength)
+	description := form.RichText("description", maxDescriptionLength)
+	if err := form.Err(); err != nil {
+		s.renderError(w, r, "Invalid translation", err)
+		return
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+
+	if name == "" && description == "" {
+		err = s.store.DeleteEventTranslation(r.Context(), &sqlc.DeleteEventTranslationParams{EventID: eventID, Language: lang.Code})
+	} else {
+		_, err = s.store.UpsertEventTranslation(r.Context(), &sqlc.UpsertEventTranslationParams{
+			EventID:     eventID,
+			Language:    lang.Code,
+			Name:        name,
+			Description: description,
+		})
+	}
+	if err != nil {
+		s.renderError(w, r, "Failed to save translation", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_event_translation", &eventTranslation{
+		EventID:             eventID,
+		Language:            lang,
+		Name:                name,
+		Description:         description,
+		OriginalName:        event.Name,
+		OriginalDescription: event.Description.String,
+	})
+}
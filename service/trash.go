@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/authz"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+)
+
+type trashData struct {
+	Events []*sqlc.Events
+	Users  []*sqlc.GetDeletedUsersRow
+	Role   authz.Role
+}
+
+func (s *Service) trashData(ctx context.Context) (*trashData, error) {
+	events, err := s.store.GetDeletedEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.store.GetDeletedUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users = slices.DeleteFunc(users, func(user *sqlc.GetDeletedUsersRow) bool { return !authz.EventAllowed(ctx, user.Users.EventID) })
+	return &trashData{Events: organizedEvents(ctx, events), Users: users, Role: authz.RoleFromContext(ctx)}, nil
+}
+
+// handleTrashPage lists the deleted events and participants that can still
+// be restored.
+func (s *Service) handleTrashPage(w http.ResponseWriter, r *http.Request) {
+	data, err := s.trashData(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get trash", apperr.FromDB(err))
+		return
+	}
+	s.runTemplate(w, r, "admin_trash", data)
+}
+
+// handleRestoreEvent takes the event out of the trash with its
+// participants and draws.
+func (s *Service) handleRestoreEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	if _, err := s.store.RestoreEvent(r.Context(), eventID); err != nil {
+		s.renderError(w, r, "Failed to restore event", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Restored event", slog.Int64("event_id", eventID))
+
+	s.renderTrash(w, r)
+}
+
+// handleRestoreUser takes the participant out of the trash. It fails with
+// a conflict if they have registered for the event again since.
+func (s *Service) handleRestoreUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid user ID", apperr.Validation("Invalid user ID"))
+		return
+	}
+
+	var user *sqlc.Users
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		var err error
+		if user, err = tx.RestoreUser(r.Context(), userID); err != nil {
+			return err
+		}
+		return consent.Record(r.Context(), tx, user, consent.Restored, consent.SourceAdmin)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to restore user", apperr.FromDB(err))
+		return
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Restored user",
+		slog.Int64("event_id", user.EventID), slog.Int64("user_id", userID))
+
+	s.renderTrash(w, r)
+}
+
+// renderTrash renders the trash lists again after a restore.
+func (s *Service) renderTrash(w http.ResponseWriter, r *http.Request) {
+	data, err := s.trashData(r.Context())
+	if err != nil {
+		s.renderError(w, r, "Failed to get trash", apperr.FromDB(err))
+		return
+	}
+	s.runTemplate(w, r, "admin_trash_lists", data)
+}
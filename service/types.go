@@ -1,6 +1,9 @@
 package service
 
-import "giveaway-tool/database/sqlc"
+import (
+	"giveaway-tool/authz"
+	"giveaway-tool/database/sqlc"
+)
 
 const errHTML = `
 <div class="bg-red-50 border-l-4 border-red-500 p-4" id="error">
@@ -47,13 +50,29 @@ const votesForm = `
 	</form>
 `
 
-type AdminData struct {
-	Username string
-	Password string
-}
-
 type Data struct {
-	Events         []*sqlc.Events `json:"events"`
-	CurrentEventID int64          `json:"current_event_id"`
-	IsAdmin        bool           `json:"isAdmin"`
+	Events         []*sqlc.Events  `json:"events"`
+	Counts         map[int64]int64 `json:"counts"`
+	CurrentEventID int64           `json:"current_event_id"`
+	IsAdmin        bool            `json:"isAdmin"`
+	// PublicRegistration enables the website registration form for upcoming events
+	PublicRegistration bool `json:"public_registration"`
+	// Archived is set when the events page or the admin dashboard lists
+	// archived events
+	Archived bool `json:"archived"`
+	// Language is the one public pages show events in
+	Language string `json:"language"`
+	// Role of the signed-in admin, used to disable actions they may not take
+	Role authz.Role `json:"-"`
+	// NextPage is the dashboard page to load more events from, 0 on the
+	// last page
+	NextPage int `json:"next_page"`
+	// Category is the one the events are filtered by, empty for all of
+	// them; Categories are those to choose from
+	Category   string   `json:"category"`
+	Categories []string `json:"categories"`
+	// Places are the places left of events with a capacity
+	Places map[int64]int32 `json:"places"`
+	// BotURL opens the Telegram bot, empty if there is none
+	BotURL string `json:"-"`
 }
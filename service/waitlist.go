@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/audit"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/logging"
+	"giveaway-tool/store"
+	"giveaway-tool/waitlist"
+)
+
+type waitlistData struct {
+	Event   *sqlc.Events
+	Entries []*sqlc.GetWaitlistByEventIDRow
+}
+
+// removeParticipant moves a participant to the trash, recording action in
+// the consent log, and, if the event has a capacity or automatic promotion
+// on, gives the freed place to the first person on the waitlist.
+func removeParticipant(ctx context.Context, tx store.Store, eventID, userID int64, action consent.Action, source string) error {
+	user, err := tx.GetUserByID(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err == nil && user.EventID == eventID {
+		if err := consent.Record(ctx, tx, user, action, source); err != nil {
+			return err
+		}
+		// Only the ticket is logged, since the participant's data goes
+		// to the trash and may be deleted for good
+		if source == consent.SourceAdmin {
+			if err := audit.Record(ctx, tx, audit.UserDeleted, eventID, map[string]any{
+				"user_id":       user.ID,
+				"ticket_number": user.TicketNumber,
+			}, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Participants who asked for their data to be deleted are deleted for
+	// good rather than kept in the trash
+	if action == consent.DeletionRequested {
+		if err == nil && user.EventID == eventID {
+			if err := tx.DeleteUser(ctx, userID); err != nil {
+				return err
+			}
+		}
+	} else if err := tx.DeleteUsersByIdAndEventId(ctx, &sqlc.DeleteUsersByIdAndEventIdParams{
+		ID:      userID,
+		EventID: eventID,
+	}); err != nil {
+		return err
+	}
+
+	event, err := tx.GetEventByID(ctx, eventID)
+	if err != nil || !event.WaitlistAutoPromote && event.Capacity == 0 {
+		return err
+	}
+	promoted, err := waitlist.PromoteNext(ctx, tx, eventID)
+	if err == nil && promoted != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "Promoted from waitlist",
+			slog.Int64("event_id", eventID), slog.Int64("user_id", promoted.ID))
+	}
+	return err
+}
+
+// joinWaitlist puts someone who tried to register for a full event through
+// source on its waitlist and returns their position. They are promoted
+// with the request's ticket type, which the caller has checked.
+func (s *Service) joinWaitlist(ctx context.Context, event *sqlc.Events, req registrationRequest, source string) (int32, error) {
+	var position int32
+	err := s.store.InTx(ctx, func(tx store.Store) error {
+		entry, p, err := waitlist.Join(ctx, tx, &sqlc.AddToWaitlistParams{
+			EventID:       event.ID,
+			Name:          req.Name,
+			Username:      req.Username,
+			TicketTypeID:  sql.NullInt64{Int64: req.TicketTypeID, Valid: req.TicketTypeID != 0},
+			ConsentSource: source,
+		})
+		if err != nil {
+			return err
+		}
+		position = p
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "Joined waitlist",
+			slog.Int64("event_id", event.ID), slog.Int64("entry_id", entry.ID))
+		return nil
+	})
+	return position, apperr.FromDB(err)
+}
+
+func (s *Service) waitlistData(ctx context.Context, eventID int64) (*waitlistData, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.store.GetWaitlistByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &waitlistData{Event: event, Entries: entries}, nil
+}
+
+func (s *Service) handleWaitlistPage(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	data, err := s.waitlistData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get waitlist", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_waitlist", data)
+}
+
+func (s *Service) handlePromoteWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	entryID, err := strconv.ParseInt(r.PathValue("entryID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid waitlist entry ID", apperr.Validation("Invalid waitlist entry ID"))
+		return
+	}
+
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		entry, err := tx.GetWaitlistEntry(r.Context(), &sqlc.GetWaitlistEntryParams{ID: entryID, EventID: eventID})
+		if err != nil {
+			return err
+		}
+
+		user, err := waitlist.Promote(r.Context(), tx, entry)
+		if err != nil {
+			return err
+		}
+
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "Promoted from waitlist",
+			slog.Int64("event_id", eventID), slog.Int64("user_id", user.ID))
+		return nil
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to promote waitlist entry", apperr.FromDB(err))
+		return
+	}
+
+	s.renderWaitlistTable(w, r, eventID)
+}
+
+func (s *Service) handleRemoveWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	entryID, err := strconv.ParseInt(r.PathValue("entryID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid waitlist entry ID", apperr.Validation("Invalid waitlist entry ID"))
+		return
+	}
+
+	if err := s.store.DeleteWaitlistEntry(r.Context(), &sqlc.DeleteWaitlistEntryParams{
+		ID:      entryID,
+		EventID: eventID,
+	}); err != nil {
+		s.renderError(w, r, "Failed to remove waitlist entry", apperr.FromDB(err))
+		return
+	}
+
+	s.renderWaitlistTable(w, r, eventID)
+}
+
+// renderWaitlistTable re-renders the table after a change, since the
+// positions of everyone behind the changed entry shift.
+func (s *Service) renderWaitlistTable(w http.ResponseWriter, r *http.Request, eventID int64) {
+	data, err := s.waitlistData(r.Context(), eventID)
+	if err != nil {
+		s.renderError(w, r, "Failed to get waitlist", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_waitlist_table", data)
+}
+
+func (s *Service) handleSetWaitlistAutoPromote(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	event, err := s.store.SetEventWaitlistAutoPromote(r.Context(), &sqlc.SetEventWaitlistAutoPromoteParams{
+		ID:                  eventID,
+		WaitlistAutoPromote: r.FormValue("enabled") == "true",
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update auto-promotion", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_waitlist_auto_promote", event)
+}
+
+// handleMoveUserToWaitlist moves a participant to the end of the event's
+// waitlist.
+func (s *Service) handleMoveUserToWaitlist(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("eventID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("userID"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid user ID", apperr.Validation("Invalid user ID"))
+		return
+	}
+
+	err = s.store.InTx(r.Context(), func(tx store.Store) error {
+		user, err := tx.GetUserByID(r.Context(), userID)
+		if err != nil {
+			return err
+		}
+		if user.EventID != eventID {
+			return sql.ErrNoRows
+		}
+
+		if _, err := tx.AddToWaitlist(r.Context(), &sqlc.AddToWaitlistParams{
+			EventID:       eventID,
+			Name:          user.Name,
+			Username:      user.Username,
+			TgID:          user.TgID,
+			TicketTypeID:  user.TicketTypeID,
+			ConsentSource: consent.SourceAdmin,
+		}); err != nil {
+			return err
+		}
+
+		// They're kept on the waitlist, so there's nothing to restore
+		return tx.DeleteUser(r.Context(), userID)
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to move user to waitlist", apperr.FromDB(err))
+		return
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/notify"
+	"giveaway-tool/webhook"
+)
+
+const (
+	webhookInterval  = 5 * time.Second
+	webhookBatchSize = 20
+	// webhookLease keeps a claimed call from being sent twice, like the
+	// bot's outbox lease
+	webhookLease       = 60
+	webhookMaxAttempts = 5
+	// pollPageSize is how many registrations the polling API returns at once
+	pollPageSize = 100
+)
+
+// webhookClient sends webhook calls. Receivers that take longer than its
+// timeout are retried.
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// deliverWebhooks sends queued webhook calls until ctx is done.
+func (s *Service) deliverWebhooks(ctx context.Context) {
+	ticker := time.NewTicker(webhookInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.sendWebhookBatch(ctx); err != nil {
+				s.logger.LogAttrs(ctx, slog.LevelError, "Failed to deliver webhooks", slog.Any("error", err))
+				notify.JobFailed("надсилання вебхуків", err)
+			}
+		}
+	}
+}
+
+func (s *Service) sendWebhookBatch(ctx context.Context) error {
+	calls, err := s.store.ClaimWebhooks(ctx, &sqlc.ClaimWebhooksParams{
+		LeaseSeconds: webhookLease,
+		BatchSize:    webhookBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, call := range calls {
+		retry, err := s.sendWebhook(ctx, call)
+		if err == nil {
+			if err := s.store.MarkWebhookSent(ctx, call.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		attempt := call.Attempts + 1
+		giveUp := !retry || attempt >= webhookMaxAttempts
+		s.logger.LogAttrs(ctx, slog.LevelWarn, "Failed to send webhook", slog.Int64("webhook_id", call.ID),
+			slog.Int("attempt", int(attempt)), slog.Bool("give_up", giveUp), slog.Any("error", err))
+
+		// Back off exponentially: 10s, 20s, 40s, ...
+		if err := s.store.MarkWebhookFailed(ctx, &sqlc.MarkWebhookFailedParams{
+			ID:                call.ID,
+			LastError:         sql.NullString{String: err.Error(), Valid: true},
+			RetryAfterSeconds: 10 << (attempt - 1),
+			GiveUp:            giveUp,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendWebhook posts a call's payload. When WEBHOOK_SECRET is set the body
+// is signed with it, so receivers can check the call came from us. retry
+// is false for errors that won't go away, like a Zap that was turned off.
+func (s *Service) sendWebhook(ctx context.Context, call *sqlc.Webhooks) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, call.Url, bytes.NewBufferString(call.Payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", strconv.FormatInt(call.ID, 10))
+	if s.webhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+		mac.Write([]byte(call.Payload))
+		req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("webhook receiver returned %s", resp.Status)
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests:
+		return true, err
+	case resp.StatusCode < 500:
+		return false, err
+	}
+	return true, err
+}
+
+// handlePollRegistrations lists an event's registrations for polling
+// triggers. Without a cursor it returns the newest ones first, which is
+// what Zapier and Make expect; with ?cursor=<id> it returns those
+// registered after that one, oldest first, for clients that page through.
+func (s *Service) handlePollRegistrations(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderJSONError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	var cursor int64
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		if cursor, err = strconv.ParseInt(v, 10, 64); err != nil {
+			s.renderJSONError(w, r, "Invalid cursor", apperr.Validation("Invalid cursor"))
+			return
+		}
+	}
+
+	event, err := s.store.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get event", apperr.FromDB(err))
+		return
+	}
+
+	var users []*sqlc.Users
+	if cursor > 0 {
+		users, err = s.store.GetUsersByEventIDAfter(r.Context(), &sqlc.GetUsersByEventIDAfterParams{
+			EventID:  eventID,
+			AfterID:  cursor,
+			PageSize: pollPageSize,
+		})
+	} else {
+		users, err = s.store.GetLatestUsersByEventID(r.Context(), &sqlc.GetLatestUsersByEventIDParams{
+			EventID:  eventID,
+			PageSize: pollPageSize,
+		})
+	}
+	if err != nil {
+		s.renderJSONError(w, r, "Failed to get participants", apperr.FromDB(err))
+		return
+	}
+
+	registrations := make([]webhook.Registration, len(users))
+	for i, user := range users {
+		registrations[i] = webhook.NewRegistration(event, user)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(registrations)
+}
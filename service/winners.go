@@ -0,0 +1,95 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+)
+
+// winnersWallPageSize is how many events the wall of fame shows per page.
+const winnersWallPageSize = 10
+
+type wallEvent struct {
+	ID      int64
+	Name    string
+	Date    time.Time
+	Winners []*sqlc.GetPublicWinnersRow
+}
+
+type winnersWallData struct {
+	Events   []*wallEvent
+	Page     int
+	PrevPage int
+	NextPage int
+	Language string
+}
+
+// handleWinnersWall renders the public wall of fame: winners of the latest
+// draw of every past event the organizers chose to show.
+func (s *Service) handleWinnersWall(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// One extra event tells whether there is a next page
+	rows, err := s.store.GetPublicWinners(r.Context(), &sqlc.GetPublicWinnersParams{
+		PageOffset: int32((page - 1) * winnersWallPageSize),
+		PageSize:   winnersWallPageSize + 1,
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to get winners", apperr.FromDB(err))
+		return
+	}
+
+	lang := language(w, r)
+	translations, err := s.translations(r.Context(), lang)
+	if err != nil {
+		s.renderError(w, r, "Failed to get translations", apperr.FromDB(err))
+		return
+	}
+
+	data := winnersWallData{Page: page, Language: lang}
+	for _, row := range rows {
+		if n := len(data.Events); n == 0 || data.Events[n-1].ID != row.EventID {
+			name := row.EventName
+			if t, ok := translations[row.EventID]; ok && t.Name != "" {
+				name = t.Name
+			}
+			data.Events = append(data.Events, &wallEvent{ID: row.EventID, Name: name, Date: row.EventDate})
+		}
+		event := data.Events[len(data.Events)-1]
+		event.Winners = append(event.Winners, row)
+	}
+	if len(data.Events) > winnersWallPageSize {
+		data.Events = data.Events[:winnersWallPageSize]
+		data.NextPage = page + 1
+	}
+	if page > 1 {
+		data.PrevPage = page - 1
+	}
+
+	s.runTemplate(w, r, "winners_wall", data)
+}
+
+func (s *Service) handleSetShowWinners(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.renderError(w, r, "Invalid event ID", apperr.Validation("Invalid event ID"))
+		return
+	}
+
+	event, err := s.store.SetEventShowWinners(r.Context(), &sqlc.SetEventShowWinnersParams{
+		ID:          eventID,
+		ShowWinners: r.FormValue("enabled") == "true",
+	})
+	if err != nil {
+		s.renderError(w, r, "Failed to update wall of fame", apperr.FromDB(err))
+		return
+	}
+
+	s.runTemplate(w, r, "admin_show_winners", event)
+}
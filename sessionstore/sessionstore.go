@@ -0,0 +1,151 @@
+// Package sessionstore keeps browser sessions in the database with only a
+// random token in the cookie, so sessions survive restarts and key changes,
+// can be listed and revoked, and aren't limited by the size of a cookie.
+package sessionstore
+
+import (
+	"bytes"
+	cryptoRand "crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/router"
+	"giveaway-tool/store"
+
+	"github.com/gorilla/sessions"
+)
+
+// browserSessionLifetime is how long sessions whose cookie lasts until the
+// browser is closed are kept, since the server can't tell when that is.
+const browserSessionLifetime = 24 * time.Hour
+
+// maxUserAgentLength caps the user agent kept to tell sessions apart.
+const maxUserAgentLength = 200
+
+// record is what is stored of a session. MaxAge is kept so that saving a
+// session again doesn't turn a browser session into a lasting one.
+type record struct {
+	Values map[any]any
+	MaxAge int
+}
+
+// Store is a sessions.Store saving sessions with the store.
+type Store struct {
+	st      store.SessionStore
+	Options *sessions.Options
+}
+
+// New returns a Store whose sessions last until the browser is closed
+// unless Options are changed.
+func New(st store.SessionStore) *Store {
+	return &Store{
+		st:      st,
+		Options: &sessions.Options{Path: "/", HttpOnly: true},
+	}
+}
+
+// HashToken returns what is stored of a session's token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the session named name of r, cached for the rest of the
+// request.
+func (s *Store) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New loads the session named name of r, or returns a new one if r has
+// none or it expired.
+func (s *Store) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	options := *s.Options
+	session.Options = &options
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	stored, err := s.st.GetSession(r.Context(), &sqlc.GetSessionParams{
+		TokenHash: HashToken(cookie.Value),
+		Now:       time.Now(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return session, nil
+	}
+	if err != nil {
+		return session, err
+	}
+	var saved record
+	if err := gob.NewDecoder(bytes.NewReader(stored.Data)).Decode(&saved); err != nil {
+		return session, err
+	}
+	if saved.Values != nil {
+		session.Values = saved.Values
+	}
+	session.Options.MaxAge = saved.MaxAge
+	session.ID = cookie.Value
+	session.IsNew = false
+	return session, nil
+}
+
+// Save stores session and sets its cookie, or deletes both if its MaxAge
+// is negative. Sessions get their token when first saved; clear the ID to
+// give a session a new one, as when an admin signs in. Cookies of requests
+// made over HTTPS are only sent back over HTTPS.
+func (s *Store) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	session.Options.Secure = session.Options.Secure || router.IsHTTPS(r)
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			if err := s.st.DeleteSession(r.Context(), HashToken(session.ID)); err != nil {
+				return err
+			}
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(record{Values: session.Values, MaxAge: session.Options.MaxAge}); err != nil {
+		return err
+	}
+	if session.ID == "" {
+		session.ID = cryptoRand.Text()
+	}
+	lifetime := browserSessionLifetime
+	if session.Options.MaxAge > 0 {
+		lifetime = time.Duration(session.Options.MaxAge) * time.Second
+	}
+	// Sessions are listed by the admin signed in with them
+	var adminID sql.NullInt64
+	if isAdmin, _ := session.Values["isAdmin"].(bool); isAdmin {
+		adminID.Int64, adminID.Valid = session.Values["adminID"].(int64)
+	}
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+	}
+
+	if err := s.st.SaveSession(r.Context(), &sqlc.SaveSessionParams{
+		TokenHash: HashToken(session.ID),
+		Name:      session.Name(),
+		AdminID:   adminID,
+		Data:      data.Bytes(),
+		UserAgent: userAgent,
+		Ip:        router.ClientIP(r),
+		ExpiresAt: time.Now().Add(lifetime),
+	}); err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), session.ID, session.Options))
+	return nil
+}
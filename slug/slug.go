@@ -0,0 +1,118 @@
+// Package slug gives events the short, readable names their public pages
+// are found by, like /events/kviz-vechir-fitki. Ukrainian names are
+// transliterated by the official rules of 2010.
+package slug
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+// MaxLength caps slugs so they still fit a Telegram start parameter, which
+// takes at most 64 characters, after a -N suffix is added.
+const MaxLength = 56
+
+// fallback is the slug of events whose names have nothing to make one of.
+const fallback = "event"
+
+// latin spells the Ukrainian letters in Latin. Letters with a different
+// spelling at the start of a word are in initial.
+var latin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d", 'е': "e",
+	'є': "ie", 'ж': "zh", 'з': "z", 'и': "y", 'і': "i", 'ї': "i", 'й': "i",
+	'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch",
+	'ш': "sh", 'щ': "shch", 'ь': "", 'ю': "iu", 'я': "ia",
+	// Russian letters turn up in names too
+	'ё': "io", 'ъ': "", 'ы': "y", 'э': "e",
+	// Apostrophes are dropped rather than splitting the word
+	'\'': "", '’': "", 'ʼ': "",
+}
+
+var initial = map[rune]string{
+	'є': "ye", 'ї': "yi", 'й': "y", 'ю': "yu", 'я': "ya",
+}
+
+// Make turns s into a slug: lowercase Latin letters and digits, with
+// words joined by dashes. It returns "" if s has no letters or digits.
+func Make(s string) string {
+	var b strings.Builder
+	wordStart := true
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			wordStart = false
+			continue
+		case wordStart && initial[r] != "":
+			b.WriteString(initial[r])
+			wordStart = false
+			continue
+		}
+		if spelled, ok := latin[r]; ok {
+			b.WriteString(spelled)
+			wordStart = wordStart && spelled == ""
+			continue
+		}
+		// Anything else separates words
+		if !wordStart && !unicode.IsMark(r) {
+			b.WriteByte('-')
+			wordStart = true
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > MaxLength {
+		slug = strings.TrimRight(slug[:MaxLength], "-")
+	}
+	return slug
+}
+
+// Assign gives the event the slug made from requested, or from its name
+// when requested is empty, adding -2, -3 and so on until no other event
+// has it. An event that already has that slug, with or without a suffix,
+// keeps it, so its shared links keep working.
+func Assign(ctx context.Context, tx store.Store, event *sqlc.Events, requested string) (*sqlc.Events, error) {
+	base := Make(requested)
+	if base == "" {
+		base = Make(event.Name)
+	}
+	if base == "" {
+		base = fallback
+	}
+	if event.Slug.Valid && hasBase(event.Slug.String, base) {
+		return event, nil
+	}
+
+	slug := base
+	for n := 2; ; n++ {
+		taken, err := tx.EventSlugTaken(ctx, &sqlc.EventSlugTakenParams{Slug: slug, ID: event.ID})
+		if err != nil {
+			return nil, err
+		}
+		if !taken {
+			break
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	return tx.SetEventSlug(ctx, &sqlc.SetEventSlugParams{Slug: slug, ID: event.ID})
+}
+
+// hasBase reports whether slug is base, possibly with a -N suffix.
+func hasBase(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
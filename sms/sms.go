@@ -0,0 +1,69 @@
+// Package sms sends text messages through an SMS provider. They are the
+// fallback for important messages Telegram couldn't deliver.
+package sms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrInvalidPhone is returned for phone numbers that can't be dialled.
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+type Sender interface {
+	// Send texts the phone number, which is in international format
+	// without the plus, e.g. 380671234567.
+	Send(ctx context.Context, phone, text string) error
+}
+
+// client is shared by the providers; sends that hang are abandoned rather
+// than holding up the outbox.
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// FromEnv returns the provider configured by TURBOSMS_TOKEN or by
+// TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM, or nil if neither
+// is set, in which case there is no SMS fallback.
+func FromEnv() Sender {
+	if token := os.Getenv("TURBOSMS_TOKEN"); token != "" {
+		sender := os.Getenv("TURBOSMS_SENDER")
+		if sender == "" {
+			sender = "FITKI"
+		}
+		return &TurboSMS{Token: token, Sender: sender}
+	}
+
+	sid := os.Getenv("TWILIO_ACCOUNT_SID")
+	token := os.Getenv("TWILIO_AUTH_TOKEN")
+	from := os.Getenv("TWILIO_FROM")
+	if sid != "" && token != "" && from != "" {
+		return &Twilio{AccountSID: sid, AuthToken: token, From: from}
+	}
+	return nil
+}
+
+// Normalize turns a phone number as participants type it into the format
+// Send takes. Numbers without a country code are taken to be Ukrainian.
+func Normalize(phone string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+
+	switch {
+	case len(digits) == 10 && digits[0] == '0':
+		digits = "38" + digits
+	case len(digits) == 9:
+		digits = "380" + digits
+	}
+	// E.164 numbers have at most 15 digits
+	if len(digits) < 10 || len(digits) > 15 {
+		return "", ErrInvalidPhone
+	}
+	return digits, nil
+}
@@ -0,0 +1,66 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const turboSMSSendURL = "https://api.turbosms.ua/message/send.json"
+
+// TurboSMS implements Sender with the TurboSMS HTTP API. Sender is the
+// alpha name registered with TurboSMS that messages come from.
+type TurboSMS struct {
+	Token  string
+	Sender string
+}
+
+type turboSMSRequest struct {
+	Recipients []string `json:"recipients"`
+	SMS        struct {
+		Sender string `json:"sender"`
+		Text   string `json:"text"`
+	} `json:"sms"`
+}
+
+type turboSMSResponse struct {
+	Code   int    `json:"response_code"`
+	Status string `json:"response_status"`
+}
+
+func (t *TurboSMS) Send(ctx context.Context, phone, text string) error {
+	var body turboSMSRequest
+	body.Recipients = []string{phone}
+	body.SMS.Sender = t.Sender
+	body.SMS.Text = text
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, turboSMSSendURL, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result turboSMSResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("turbosms: %s: %w", resp.Status, err)
+	}
+	// 0 is OK and 8xx are the message accepted statuses; anything else is
+	// an error
+	if result.Code != 0 && (result.Code < 800 || result.Code >= 900) {
+		return fmt.Errorf("turbosms: %d %s", result.Code, result.Status)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const twilioAPIURL = "https://api.twilio.com/2010-04-01/Accounts/"
+
+// Twilio implements Sender with Twilio's Messages API. From is the Twilio
+// number or alphanumeric sender ID messages come from.
+type Twilio struct {
+	AccountSID string
+	AuthToken  string
+	From       string
+}
+
+type twilioError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (t *Twilio) Send(ctx context.Context, phone, text string) error {
+	form := url.Values{
+		"To":   {"+" + phone},
+		"From": {t.From},
+		"Body": {text},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, twilioAPIURL+url.PathEscape(t.AccountSID)+"/Messages.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.AccountSID, t.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var result twilioError
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.Message == "" {
+			return fmt.Errorf("twilio: %s", resp.Status)
+		}
+		return fmt.Errorf("twilio: %d %s", result.Code, result.Message)
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+// Admin routes reject changes without the session's CSRF token, which
+// the server leaves in a cookie only this site can read
+document.addEventListener('htmx:configRequest', function(event) {
+    var match = document.cookie.match(/(?:^|; )csrf_token=([^;]*)/);
+    if (match) event.detail.headers['X-CSRF-Token'] = decodeURIComponent(match[1]);
+});
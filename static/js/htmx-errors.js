@@ -0,0 +1,7 @@
+// Swap error fragments returned with 4xx/5xx statuses instead of dropping them
+document.addEventListener('htmx:beforeSwap', function(event) {
+    if (event.detail.xhr.status >= 400) {
+        event.detail.shouldSwap = true;
+        event.detail.isError = false;
+    }
+});
@@ -0,0 +1,17 @@
+// Elements with data-live-count="<event ID>" show the event's participant
+// count as registrations come in, including those swapped in later
+htmx.onLoad(function (content) {
+    if (!window.EventSource) return;
+    var elements = content.querySelectorAll("[data-live-count]");
+    elements.forEach(function (el) {
+        var source = new EventSource("/admin/events/" + el.dataset.liveCount + "/stream");
+        source.addEventListener("count", function (evt) {
+            // Stop following events whose card was removed
+            if (!document.body.contains(el)) {
+                source.close();
+                return;
+            }
+            el.textContent = evt.data;
+        });
+    });
+});
@@ -0,0 +1,20 @@
+// Live notices from the server: new registrations, draws and failed jobs
+(function () {
+    if (!window.EventSource) return;
+    var colors = {
+        registration: "border-indigo-500",
+        milestone: "border-green-500",
+        draw: "border-amber-500",
+        error: "border-red-500"
+    };
+    var source = new EventSource("/admin/notifications/stream");
+    source.addEventListener("notice", function (evt) {
+        var notice = JSON.parse(evt.data);
+        var toast = document.createElement("div");
+        toast.className = "bg-white shadow-lg rounded-md border-l-4 p-3 text-sm text-gray-800 whitespace-pre-line cursor-pointer " + (colors[notice.kind] || "border-gray-400");
+        toast.textContent = notice.text;
+        toast.onclick = function () { toast.remove(); };
+        document.getElementById("notices").appendChild(toast);
+        setTimeout(function () { toast.remove(); }, notice.kind === "error" ? 30000 : 8000);
+    });
+})();
@@ -0,0 +1,48 @@
+// Shows the winners of the live draw as the admin draws them, over a
+// WebSocket that is reopened whenever it drops
+(function () {
+    var eventID = document.body.dataset.eventId;
+    var status = document.getElementById("projector-status");
+    var latest = document.getElementById("projector-latest");
+    var list = document.getElementById("projector-winners");
+    var shown = 0;
+
+    function render(state) {
+        var winners = state.winners;
+        if (winners.length === 0) {
+            status.textContent = state.running ? "Хто ж переможе?" : "Розіграш незабаром почнеться";
+            status.classList.remove("hidden");
+            latest.classList.add("hidden");
+            list.innerHTML = "";
+            shown = 0;
+            return;
+        }
+
+        var last = winners[winners.length - 1];
+        status.classList.add("hidden");
+        latest.classList.remove("hidden");
+        document.getElementById("projector-position").textContent = "№" + last.position;
+        document.getElementById("projector-ticket").textContent = "№" + last.ticket_number;
+        document.getElementById("projector-name").textContent = last.name;
+        if (winners.length > shown) {
+            latest.animate([{ opacity: 0, transform: "scale(0.5)" }, { opacity: 1, transform: "scale(1)" }], { duration: 600, easing: "ease-out" });
+        }
+        shown = winners.length;
+
+        list.innerHTML = "";
+        winners.slice(0, -1).forEach(function (winner) {
+            var item = document.createElement("li");
+            item.className = "bg-indigo-800 rounded-lg px-4 py-2";
+            item.textContent = winner.position + ". №" + winner.ticket_number + " " + winner.name;
+            list.appendChild(item);
+        });
+    }
+
+    function connect() {
+        var scheme = location.protocol === "https:" ? "wss://" : "ws://";
+        var socket = new WebSocket(scheme + location.host + "/admin/events/" + eventID + "/live-draw/ws");
+        socket.onmessage = function (evt) { render(JSON.parse(evt.data)); };
+        socket.onclose = function () { setTimeout(connect, 2000); };
+    }
+    connect();
+})();
@@ -0,0 +1,138 @@
+// Package static serves the CSS, JS and images embedded in the binary.
+// Their URLs carry a hash of their content, so browsers can cache them for
+// good and still fetch new versions as soon as a deploy changes them.
+//
+// htmx and the Tailwind stylesheet are served from here too, instead of from
+// CDNs, so pages don't depend on other sites. They aren't committed: "go
+// generate ./static" builds them into js and css with npm, as the Dockerfile
+// does. Tailwind keeps only the classes found in the templates and handlers,
+// so the build is run again after changing them. Binaries built without
+// them still serve every page, see Missing.
+package static
+
+//go:generate sh -c "cd .. && npm install --no-audit --no-fund && npm run assets"
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Prefix is the path assets are served under.
+const Prefix = "/static/"
+
+// immutable is the Cache-Control of hashed URLs, whose content never
+// changes.
+const immutable = "public, max-age=31536000, immutable"
+
+//go:embed css js
+var files embed.FS
+
+type asset struct {
+	name    string
+	content []byte
+	// hash is a prefix of the SHA-256 of content
+	hash string
+}
+
+// built lists the assets made by go generate, with the CDN copy linked
+// instead when one is missing. Pages go without the stylesheet, which no
+// CDN has with just the classes used here.
+var built = map[string]string{
+	"js/htmx.min.js":   "https://unpkg.com/htmx.org@1.9.6/dist/htmx.min.js",
+	"css/tailwind.css": "",
+}
+
+var (
+	// assets are keyed by name, such as "js/csrf.js"
+	assets = make(map[string]*asset)
+	// hashed maps URLs with hashes to their assets
+	hashed = make(map[string]*asset)
+)
+
+func init() {
+	err := fs.WalkDir(files, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := files.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		a := &asset{name: name, content: content, hash: hex.EncodeToString(sum[:6])}
+		assets[name] = a
+		hashed[hashedURL(a)] = a
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// hashedURL returns the URL of a with its hash before the extension, like
+// /static/js/csrf.1a2b3c4d5e6f.js.
+func hashedURL(a *asset) string {
+	ext := path.Ext(a.name)
+	return Prefix + strings.TrimSuffix(a.name, ext) + "." + a.hash + ext
+}
+
+// URL returns the URL to link the asset name with, such as "js/csrf.js",
+// for the asset template func. It fails for names that aren't embedded so
+// that typos break the page instead of a script silently missing, except
+// for the built assets, which are linked from their CDN or left out.
+func URL(name string) (string, error) {
+	a, ok := assets[name]
+	if ok {
+		return hashedURL(a), nil
+	}
+	fallback, ok := built[name]
+	if !ok {
+		return "", fmt.Errorf("no static asset %q", name)
+	}
+	if fallback == "" {
+		// Served as not found, so the page renders unstyled
+		return Prefix + name, nil
+	}
+	return fallback, nil
+}
+
+// Missing returns the built assets the binary was built without, which go
+// generate makes.
+func Missing() []string {
+	var missing []string
+	for name := range built {
+		if _, ok := assets[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+// Handler serves the assets under Prefix. Hashed URLs are cached for a
+// year; plain names, as linked from outside the templates, are revalidated
+// on each use.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, ok := hashed[r.URL.Path]
+		if ok {
+			w.Header().Set("Cache-Control", immutable)
+		} else if a, ok = assets[strings.TrimPrefix(r.URL.Path, Prefix)]; ok {
+			w.Header().Set("Cache-Control", "no-cache")
+		} else {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"`+a.hash+`"`)
+		http.ServeContent(w, r, a.name, time.Time{}, bytes.NewReader(a.content))
+	})
+}
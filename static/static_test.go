@@ -0,0 +1,70 @@
+package static_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"giveaway-tool/static"
+)
+
+func TestURL(t *testing.T) {
+	got, err := static.URL("js/csrf.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^/static/js/csrf\.[0-9a-f]{12}\.js$`).MatchString(got) {
+		t.Errorf("URL(js/csrf.js) = %s, want a hashed URL", got)
+	}
+
+	if _, err := static.URL("js/missing.js"); err == nil {
+		t.Error("URL(js/missing.js) = nil error for an asset that doesn't exist")
+	}
+}
+
+func TestURLBuilt(t *testing.T) {
+	for _, name := range []string{"js/htmx.min.js", "css/tailwind.css"} {
+		got, err := static.URL(name)
+		if err != nil {
+			t.Fatalf("URL(%s) = %v, pages must render without built assets", name, err)
+		}
+		if !slices.Contains(static.Missing(), name) {
+			if !strings.HasPrefix(got, static.Prefix) {
+				t.Errorf("URL(%s) = %s for a built asset", name, got)
+			}
+			continue
+		}
+		t.Logf("%s not built, linked as %s", name, got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	hashed, err := static.URL("js/csrf.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path  string
+		want  int
+		cache string
+	}{
+		{path: hashed, want: http.StatusOK, cache: "public, max-age=31536000, immutable"},
+		{path: "/static/js/csrf.js", want: http.StatusOK, cache: "no-cache"},
+		{path: "/static/js/csrf.000000000000.js", want: http.StatusNotFound},
+		{path: "/static/js/missing.js", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		static.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.want {
+			t.Errorf("GET %s: status %d, want %d", tt.path, w.Code, tt.want)
+		}
+		if got := w.Header().Get("Cache-Control"); got != tt.cache {
+			t.Errorf("GET %s: Cache-Control %q, want %q", tt.path, got, tt.cache)
+		}
+	}
+}
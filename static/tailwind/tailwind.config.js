@@ -0,0 +1,11 @@
+// Builds static/css/tailwind.css with the classes the pages use, which
+// are found in the templates, in the HTML the handlers write and in the
+// scripts that add classes. Run "npm run tailwind" after changing them.
+module.exports = {
+  content: [
+    "./service/templates/**/*.htmx",
+    "./service/**/*.go",
+    "./static/js/**/*.js",
+  ],
+  plugins: [require("@tailwindcss/typography")],
+};
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"giveaway-tool/database/sqlc"
+)
+
+// DefaultChunkSize is the number of rows CreateUsers inserts per statement
+// when no chunk size is given.
+const DefaultChunkSize = 500
+
+type NewUser struct {
+	Name     string
+	Username string
+	TgID     sql.NullInt64
+}
+
+// ChunkError reports a chunk of a batch insert that failed. Start and End
+// are zero-based row indexes into the input, End exclusive.
+type ChunkError struct {
+	Start int
+	End   int
+	Err   error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("rows %d-%d: %v", e.Start+1, e.End, e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+type BatchResult struct {
+	// Inserted counts the new rows. Rows skipped because the participant
+	// was already registered for the event are not counted.
+	Inserted int64
+	Errors   []*ChunkError
+}
+
+// CreateUsers inserts users into an event in chunks, each in its own
+// transaction, so a bad chunk is reported without losing the others.
+func CreateUsers(ctx context.Context, st Store, eventID int64, users []NewUser, chunkSize int) *BatchResult {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	result := &BatchResult{}
+	for start := 0; start < len(users); start += chunkSize {
+		end := min(start+chunkSize, len(users))
+
+		if err := ctx.Err(); err != nil {
+			result.Errors = append(result.Errors, &ChunkError{Start: start, End: len(users), Err: err})
+			break
+		}
+
+		arg := &sqlc.CreateUsersBatchParams{
+			Names:        make([]string, 0, end-start),
+			Usernames:    make([]string, 0, end-start),
+			TgIds:        make([]int64, 0, end-start),
+			CheckInCodes: make([]string, 0, end-start),
+			EventID:      eventID,
+		}
+		for _, user := range users[start:end] {
+			arg.Names = append(arg.Names, user.Name)
+			arg.Usernames = append(arg.Usernames, user.Username)
+			arg.TgIds = append(arg.TgIds, user.TgID.Int64)
+			arg.CheckInCodes = append(arg.CheckInCodes, NewCheckInCode())
+		}
+
+		err := st.InTx(ctx, func(tx Store) error {
+			inserted, err := tx.CreateUsersBatch(ctx, arg)
+			if err != nil {
+				return err
+			}
+			result.Inserted += inserted
+			return nil
+		})
+		if err != nil {
+			result.Errors = append(result.Errors, &ChunkError{Start: start, End: end, Err: err})
+		}
+	}
+
+	return result
+}
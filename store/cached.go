@@ -0,0 +1,242 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"giveaway-tool/cache"
+	"giveaway-tool/database/sqlc"
+)
+
+// CachedStore serves hot reads (the events list, single events and
+// participant counts) from memory and drops the affected entries whenever
+// they are changed through the store.
+type CachedStore struct {
+	Store
+
+	events *cache.Cache[struct{}, []*sqlc.Events]
+	event  *cache.Cache[int64, *sqlc.Events]
+	counts *cache.Cache[int64, int64]
+}
+
+func NewCached(inner Store, ttl time.Duration) *CachedStore {
+	return &CachedStore{
+		Store:  inner,
+		events: cache.New[struct{}, []*sqlc.Events](ttl),
+		event:  cache.New[int64, *sqlc.Events](ttl),
+		counts: cache.New[int64, int64](ttl),
+	}
+}
+
+// Invalidate drops every cached entry.
+func (s *CachedStore) Invalidate() {
+	s.events.Purge()
+	s.event.Purge()
+	s.counts.Purge()
+}
+
+func (s *CachedStore) InTx(ctx context.Context, fn func(Store) error) error {
+	// Writes inside the transaction bypass the cache, so drop it afterwards
+	defer s.Invalidate()
+	return s.Store.InTx(ctx, fn)
+}
+
+func (s *CachedStore) GetEvents(ctx context.Context) ([]*sqlc.Events, error) {
+	if events, ok := s.events.Get(struct{}{}); ok {
+		return events, nil
+	}
+
+	events, err := s.Store.GetEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.events.Set(struct{}{}, events)
+	return events, nil
+}
+
+func (s *CachedStore) GetEventByID(ctx context.Context, id int64) (*sqlc.Events, error) {
+	if event, ok := s.event.Get(id); ok {
+		return event, nil
+	}
+
+	event, err := s.Store.GetEventByID(ctx, id)
+	if err != nil {
+		return event, err
+	}
+	s.event.Set(id, event)
+	return event, nil
+}
+
+func (s *CachedStore) CountUsersByEventID(ctx context.Context, eventID int64) (int64, error) {
+	if count, ok := s.counts.Get(eventID); ok {
+		return count, nil
+	}
+
+	count, err := s.Store.CountUsersByEventID(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+	s.counts.Set(eventID, count)
+	return count, nil
+}
+
+func (s *CachedStore) CreateEvent(ctx context.Context, arg *sqlc.CreateEventParams) (*sqlc.Events, error) {
+	defer s.events.Purge()
+	return s.Store.CreateEvent(ctx, arg)
+}
+
+func (s *CachedStore) UpdateEvent(ctx context.Context, arg *sqlc.UpdateEventParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.UpdateEvent(ctx, arg)
+}
+
+func (s *CachedStore) DeleteEvent(ctx context.Context, id int64) error {
+	defer s.invalidateEvent(id)
+	return s.Store.DeleteEvent(ctx, id)
+}
+
+func (s *CachedStore) RestoreEvent(ctx context.Context, id int64) (*sqlc.Events, error) {
+	defer s.invalidateEvent(id)
+	return s.Store.RestoreEvent(ctx, id)
+}
+
+func (s *CachedStore) PurgeDeletedEventsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	defer s.Invalidate()
+	return s.Store.PurgeDeletedEventsBefore(ctx, cutoff)
+}
+
+func (s *CachedStore) SetEventWaitlistAutoPromote(ctx context.Context, arg *sqlc.SetEventWaitlistAutoPromoteParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventWaitlistAutoPromote(ctx, arg)
+}
+
+func (s *CachedStore) SetEventKioskToken(ctx context.Context, arg *sqlc.SetEventKioskTokenParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventKioskToken(ctx, arg)
+}
+
+func (s *CachedStore) SetEventPaidEntries(ctx context.Context, arg *sqlc.SetEventPaidEntriesParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventPaidEntries(ctx, arg)
+}
+
+func (s *CachedStore) SetEventTicketPrice(ctx context.Context, arg *sqlc.SetEventTicketPriceParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventTicketPrice(ctx, arg)
+}
+
+func (s *CachedStore) SetEventShareBonus(ctx context.Context, arg *sqlc.SetEventShareBonusParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventShareBonus(ctx, arg)
+}
+
+func (s *CachedStore) SetEventShowWinners(ctx context.Context, arg *sqlc.SetEventShowWinnersParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventShowWinners(ctx, arg)
+}
+
+func (s *CachedStore) SetEventSeatAssignment(ctx context.Context, arg *sqlc.SetEventSeatAssignmentParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventSeatAssignment(ctx, arg)
+}
+
+func (s *CachedStore) SetEventCategories(ctx context.Context, arg *sqlc.SetEventCategoriesParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventCategories(ctx, arg)
+}
+
+func (s *CachedStore) SetEventCapacity(ctx context.Context, arg *sqlc.SetEventCapacityParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventCapacity(ctx, arg)
+}
+
+func (s *CachedStore) SetEventStatus(ctx context.Context, arg *sqlc.SetEventStatusParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventStatus(ctx, arg)
+}
+
+func (s *CachedStore) SetEventSlug(ctx context.Context, arg *sqlc.SetEventSlugParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventSlug(ctx, arg)
+}
+
+func (s *CachedStore) SetEventRegistrationWindow(ctx context.Context, arg *sqlc.SetEventRegistrationWindowParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventRegistrationWindow(ctx, arg)
+}
+
+func (s *CachedStore) SetEventImage(ctx context.Context, arg *sqlc.SetEventImageParams) (*sqlc.Events, error) {
+	defer s.invalidateEvent(arg.EventID)
+	return s.Store.SetEventImage(ctx, arg)
+}
+
+func (s *CachedStore) DeleteEventImage(ctx context.Context, eventID int64) (*sqlc.Events, error) {
+	defer s.invalidateEvent(eventID)
+	return s.Store.DeleteEventImage(ctx, eventID)
+}
+
+func (s *CachedStore) SyncLastTicketNumber(ctx context.Context, id int64) error {
+	defer s.invalidateEvent(id)
+	return s.Store.SyncLastTicketNumber(ctx, id)
+}
+
+func (s *CachedStore) ArchiveEventsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	defer s.Invalidate()
+	return s.Store.ArchiveEventsBefore(ctx, cutoff)
+}
+
+func (s *CachedStore) ArchiveEvent(ctx context.Context, id int64) (*sqlc.Events, error) {
+	defer s.invalidateEvent(id)
+	return s.Store.ArchiveEvent(ctx, id)
+}
+
+func (s *CachedStore) UnarchiveEvent(ctx context.Context, id int64) (*sqlc.Events, error) {
+	defer s.invalidateEvent(id)
+	return s.Store.UnarchiveEvent(ctx, id)
+}
+
+func (s *CachedStore) SetEventCalendarSynced(ctx context.Context, arg *sqlc.SetEventCalendarSyncedParams) error {
+	defer s.invalidateEvent(arg.ID)
+	return s.Store.SetEventCalendarSynced(ctx, arg)
+}
+
+func (s *CachedStore) invalidateEvent(id int64) {
+	s.events.Purge()
+	s.event.Delete(id)
+	s.counts.Delete(id)
+}
+
+func (s *CachedStore) CreateUser(ctx context.Context, arg *sqlc.CreateUserParams) (*sqlc.Users, error) {
+	defer s.counts.Delete(arg.EventID)
+	return s.Store.CreateUser(ctx, arg)
+}
+
+func (s *CachedStore) CreateUsersBatch(ctx context.Context, arg *sqlc.CreateUsersBatchParams) (int64, error) {
+	defer s.counts.Delete(arg.EventID)
+	return s.Store.CreateUsersBatch(ctx, arg)
+}
+
+func (s *CachedStore) ImportUser(ctx context.Context, arg *sqlc.ImportUserParams) (*sqlc.Users, error) {
+	defer s.counts.Delete(arg.EventID)
+	return s.Store.ImportUser(ctx, arg)
+}
+
+func (s *CachedStore) DeleteUser(ctx context.Context, id int64) error {
+	defer s.counts.Purge()
+	return s.Store.DeleteUser(ctx, id)
+}
+
+func (s *CachedStore) DeleteUsersByIdAndEventId(ctx context.Context, arg *sqlc.DeleteUsersByIdAndEventIdParams) error {
+	defer s.counts.Delete(arg.EventID)
+	return s.Store.DeleteUsersByIdAndEventId(ctx, arg)
+}
+
+func (s *CachedStore) RestoreUser(ctx context.Context, id int64) (*sqlc.Users, error) {
+	defer s.counts.Purge()
+	return s.Store.RestoreUser(ctx, id)
+}
+
+func (s *CachedStore) PurgeDeletedUsersBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	defer s.counts.Purge()
+	return s.Store.PurgeDeletedUsersBefore(ctx, cutoff)
+}
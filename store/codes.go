@@ -0,0 +1,37 @@
+package store
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// CheckInCodeLength is the length of the short codes participants can give
+// at the entrance instead of showing their ticket QR code.
+const CheckInCodeLength = 6
+
+// checkInCodeAlphabet leaves out 0/O and 1/I, which are easy to mix up when
+// a code is read off a screen. It has 32 letters, so byte%32 is unbiased.
+const checkInCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// NewCheckInCode returns a random check-in code. Codes are unique per event;
+// with 32^6 possible codes a collision is rare enough to surface as a
+// failed registration rather than be retried.
+func NewCheckInCode() string {
+	b := make([]byte, CheckInCodeLength)
+	rand.Read(b)
+	for i := range b {
+		b[i] = checkInCodeAlphabet[b[i]%byte(len(checkInCodeAlphabet))]
+	}
+	return string(b)
+}
+
+// NormalizeCheckInCode upper-cases a typed-in code and drops the spaces and
+// dashes people add when reading it out.
+func NormalizeCheckInCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"iter"
+
+	"giveaway-tool/database/sqlc"
+)
+
+// DefaultPageSize is the number of participants EventUsers loads per query
+// when no page size is given.
+const DefaultPageSize = 1000
+
+// EventUsers iterates over an event's participants ordered by ID, loading
+// them a page at a time so large events are never held in memory at once.
+// Iteration stops at the first error, which is yielded with a nil user.
+func EventUsers(ctx context.Context, st UserStore, eventID int64, pageSize int) iter.Seq2[*sqlc.Users, error] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return func(yield func(*sqlc.Users, error) bool) {
+		var afterID int64
+		for {
+			users, err := st.GetUsersByEventIDAfter(ctx, &sqlc.GetUsersByEventIDAfterParams{
+				EventID:  eventID,
+				AfterID:  afterID,
+				PageSize: int32(pageSize),
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, user := range users {
+				if !yield(user, nil) {
+					return
+				}
+			}
+
+			if len(users) < pageSize {
+				return
+			}
+			afterID = users[len(users)-1].ID
+		}
+	}
+}
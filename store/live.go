@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/livecount"
+)
+
+// LiveStore publishes an event's participant count to livecount whenever
+// participants join or leave it through the store. Changes made in a
+// transaction are published once it commits.
+type LiveStore struct {
+	Store
+
+	// changed has the events whose participants changed in the current
+	// transaction; it is nil outside of one
+	changed map[int64]struct{}
+}
+
+func NewLive(inner Store) *LiveStore {
+	return &LiveStore{Store: inner}
+}
+
+func (s *LiveStore) InTx(ctx context.Context, fn func(Store) error) error {
+	// Nested calls reuse the outer transaction
+	if s.changed != nil {
+		return fn(s)
+	}
+
+	changed := make(map[int64]struct{})
+	err := s.Store.InTx(ctx, func(tx Store) error {
+		return fn(&LiveStore{Store: tx, changed: changed})
+	})
+	if err != nil {
+		return err
+	}
+	for eventID := range changed {
+		s.publish(ctx, eventID)
+	}
+	return nil
+}
+
+// usersChanged publishes the event's count, or waits for the transaction
+// to commit.
+func (s *LiveStore) usersChanged(ctx context.Context, eventID int64) {
+	if s.changed != nil {
+		s.changed[eventID] = struct{}{}
+		return
+	}
+	s.publish(ctx, eventID)
+}
+
+func (s *LiveStore) publish(ctx context.Context, eventID int64) {
+	if !livecount.Watched(eventID) {
+		return
+	}
+	// A failed count is only missed by the live pages, which get the
+	// next one
+	if count, err := s.Store.CountUsersByEventID(ctx, eventID); err == nil {
+		livecount.Publish(eventID, count)
+	}
+}
+
+func (s *LiveStore) CreateUser(ctx context.Context, arg *sqlc.CreateUserParams) (*sqlc.Users, error) {
+	user, err := s.Store.CreateUser(ctx, arg)
+	if err == nil {
+		s.usersChanged(ctx, arg.EventID)
+	}
+	return user, err
+}
+
+func (s *LiveStore) CreateUsersBatch(ctx context.Context, arg *sqlc.CreateUsersBatchParams) (int64, error) {
+	created, err := s.Store.CreateUsersBatch(ctx, arg)
+	if err == nil && created > 0 {
+		s.usersChanged(ctx, arg.EventID)
+	}
+	return created, err
+}
+
+func (s *LiveStore) ImportUser(ctx context.Context, arg *sqlc.ImportUserParams) (*sqlc.Users, error) {
+	user, err := s.Store.ImportUser(ctx, arg)
+	if err == nil {
+		s.usersChanged(ctx, arg.EventID)
+	}
+	return user, err
+}
+
+func (s *LiveStore) RestoreUser(ctx context.Context, id int64) (*sqlc.Users, error) {
+	user, err := s.Store.RestoreUser(ctx, id)
+	if err == nil {
+		s.usersChanged(ctx, user.EventID)
+	}
+	return user, err
+}
+
+func (s *LiveStore) DeleteUser(ctx context.Context, id int64) error {
+	// The event is looked up first, as the participant is gone afterwards
+	user, lookupErr := s.Store.GetUserByID(ctx, id)
+	if err := s.Store.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	if lookupErr == nil {
+		s.usersChanged(ctx, user.EventID)
+	}
+	return nil
+}
+
+func (s *LiveStore) DeleteUsersByIdAndEventId(ctx context.Context, arg *sqlc.DeleteUsersByIdAndEventIdParams) error {
+	if err := s.Store.DeleteUsersByIdAndEventId(ctx, arg); err != nil {
+		return err
+	}
+	s.usersChanged(ctx, arg.EventID)
+	return nil
+}
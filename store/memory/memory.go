@@ -0,0 +1,4071 @@
+// Package memory provides an in-memory store.Store used by tests and local
+// development without a database.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+
+	"github.com/lib/pq"
+)
+
+type Store struct {
+	mu   sync.Mutex
+	txMu sync.Mutex
+
+	nextID       int64
+	events       map[int64]sqlc.Events
+	users        map[int64]sqlc.Users
+	draws        map[int64]sqlc.Draws
+	drawWinners  map[int64][]sqlc.DrawWinners
+	flags        map[string]sqlc.FeatureFlags
+	waitlist     map[int64]sqlc.Waitlist
+	outbox       map[int64]sqlc.Outbox
+	rules        map[int64]sqlc.EntryRules
+	organizers   map[int64]sqlc.EventOrganizers
+	translations map[translationKey]sqlc.EventTranslations
+	images       map[int64]sqlc.EventImages
+	shareClicks  map[shareClick]sqlc.ShareClicks
+	purchases    map[int64]sqlc.EntryPurchases
+	ticketOrders map[int64]sqlc.TicketOrders
+	promoCodes   map[int64]sqlc.PromoCodes
+	redemptions  map[int64]sqlc.PromoRedemptions
+	shortLinks   map[int64]sqlc.ShortLinks
+	ticketTypes  map[int64]sqlc.TicketTypes
+	seats        map[int64]sqlc.Seats
+	templates    map[int64]sqlc.EventTemplates
+	tmplRules    map[int64]sqlc.EventTemplateRules
+	digests      map[int64]sqlc.DigestSubscriptions
+	idempotency  map[idempotencyKey]sqlc.IdempotencyKeys
+	admins       map[int64]sqlc.Admins
+	loginTokens  map[string]sqlc.AdminLoginTokens
+	tokens       map[int64]sqlc.Tokens
+	sessions     map[int64]sqlc.Sessions
+	broadcasts   map[int64]sqlc.Broadcasts
+	deliveries   map[int64]sqlc.BroadcastDeliveries
+	consentLog   map[int64]sqlc.ConsentLog
+	auditLog     map[int64]sqlc.AuditLog
+	webhooks     map[int64]sqlc.Webhooks
+}
+
+type shareClick struct {
+	userID      int64
+	visitorHash string
+}
+
+type translationKey struct {
+	eventID  int64
+	language string
+}
+
+type idempotencyKey struct {
+	scope, key string
+}
+
+var _ store.Store = (*Store)(nil)
+
+func New() *Store {
+	return &Store{
+		events:       make(map[int64]sqlc.Events),
+		users:        make(map[int64]sqlc.Users),
+		draws:        make(map[int64]sqlc.Draws),
+		drawWinners:  make(map[int64][]sqlc.DrawWinners),
+		flags:        make(map[string]sqlc.FeatureFlags),
+		waitlist:     make(map[int64]sqlc.Waitlist),
+		outbox:       make(map[int64]sqlc.Outbox),
+		rules:        make(map[int64]sqlc.EntryRules),
+		organizers:   make(map[int64]sqlc.EventOrganizers),
+		translations: make(map[translationKey]sqlc.EventTranslations),
+		images:       make(map[int64]sqlc.EventImages),
+		shareClicks:  make(map[shareClick]sqlc.ShareClicks),
+		purchases:    make(map[int64]sqlc.EntryPurchases),
+		ticketOrders: make(map[int64]sqlc.TicketOrders),
+		promoCodes:   make(map[int64]sqlc.PromoCodes),
+		redemptions:  make(map[int64]sqlc.PromoRedemptions),
+		shortLinks:   make(map[int64]sqlc.ShortLinks),
+		ticketTypes:  make(map[int64]sqlc.TicketTypes),
+		seats:        make(map[int64]sqlc.Seats),
+		templates:    make(map[int64]sqlc.EventTemplates),
+		tmplRules:    make(map[int64]sqlc.EventTemplateRules),
+		digests:      make(map[int64]sqlc.DigestSubscriptions),
+		idempotency:  make(map[idempotencyKey]sqlc.IdempotencyKeys),
+		admins:       make(map[int64]sqlc.Admins),
+		loginTokens:  make(map[string]sqlc.AdminLoginTokens),
+		tokens:       make(map[int64]sqlc.Tokens),
+		sessions:     make(map[int64]sqlc.Sessions),
+		broadcasts:   make(map[int64]sqlc.Broadcasts),
+		deliveries:   make(map[int64]sqlc.BroadcastDeliveries),
+		consentLog:   make(map[int64]sqlc.ConsentLog),
+		auditLog:     make(map[int64]sqlc.AuditLog),
+		webhooks:     make(map[int64]sqlc.Webhooks),
+	}
+}
+
+// InTx snapshots the store and restores the snapshot if fn fails. Only one
+// transaction runs at a time.
+func (s *Store) InTx(ctx context.Context, fn func(store.Store) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.Lock()
+	events := maps.Clone(s.events)
+	users := maps.Clone(s.users)
+	draws := maps.Clone(s.draws)
+	drawWinners := maps.Clone(s.drawWinners)
+	flags := maps.Clone(s.flags)
+	waitlist := maps.Clone(s.waitlist)
+	outbox := maps.Clone(s.outbox)
+	rules := maps.Clone(s.rules)
+	organizers := maps.Clone(s.organizers)
+	translations := maps.Clone(s.translations)
+	images := maps.Clone(s.images)
+	shareClicks := maps.Clone(s.shareClicks)
+	purchases := maps.Clone(s.purchases)
+	ticketOrders := maps.Clone(s.ticketOrders)
+	promoCodes := maps.Clone(s.promoCodes)
+	redemptions := maps.Clone(s.redemptions)
+	shortLinks := maps.Clone(s.shortLinks)
+	ticketTypes := maps.Clone(s.ticketTypes)
+	seats := maps.Clone(s.seats)
+	templates := maps.Clone(s.templates)
+	tmplRules := maps.Clone(s.tmplRules)
+	digests := maps.Clone(s.digests)
+	idempotency := maps.Clone(s.idempotency)
+	admins := maps.Clone(s.admins)
+	loginTokens := maps.Clone(s.loginTokens)
+	tokens := maps.Clone(s.tokens)
+	sessions := maps.Clone(s.sessions)
+	broadcasts := maps.Clone(s.broadcasts)
+	deliveries := maps.Clone(s.deliveries)
+	consentLog := maps.Clone(s.consentLog)
+	auditLog := maps.Clone(s.auditLog)
+	webhooks := maps.Clone(s.webhooks)
+	nextID := s.nextID
+	s.mu.Unlock()
+
+	if err := fn(txStore{s}); err != nil {
+		s.mu.Lock()
+		s.events = events
+		s.users = users
+		s.draws = draws
+		s.drawWinners = drawWinners
+		s.flags = flags
+		s.waitlist = waitlist
+		s.outbox = outbox
+		s.rules = rules
+		s.organizers = organizers
+		s.translations = translations
+		s.images = images
+		s.shareClicks = shareClicks
+		s.purchases = purchases
+		s.ticketOrders = ticketOrders
+		s.promoCodes = promoCodes
+		s.redemptions = redemptions
+		s.shortLinks = shortLinks
+		s.ticketTypes = ticketTypes
+		s.seats = seats
+		s.templates = templates
+		s.tmplRules = tmplRules
+		s.digests = digests
+		s.idempotency = idempotency
+		s.admins = admins
+		s.loginTokens = loginTokens
+		s.tokens = tokens
+		s.sessions = sessions
+		s.broadcasts = broadcasts
+		s.deliveries = deliveries
+		s.consentLog = consentLog
+		s.auditLog = auditLog
+		s.webhooks = webhooks
+		s.nextID = nextID
+		s.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// txStore is handed to InTx callbacks so nested InTx calls join the running
+// transaction instead of deadlocking on txMu.
+type txStore struct {
+	*Store
+}
+
+func (t txStore) InTx(ctx context.Context, fn func(store.Store) error) error {
+	return fn(t)
+}
+
+func (s *Store) id() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+func now() sql.NullTime {
+	return sql.NullTime{Time: time.Now(), Valid: true}
+}
+
+func byCreatedAtDesc[T any](createdAt func(T) time.Time) func(a, b T) int {
+	return func(a, b T) int {
+		return createdAt(b).Compare(createdAt(a))
+	}
+}
+
+func uniqueViolation(constraint string) error {
+	return &pq.Error{
+		Code:       "23505",
+		Message:    "duplicate key value violates unique constraint \"" + constraint + "\"",
+		Constraint: constraint,
+	}
+}
+
+func (s *Store) CreateEvent(ctx context.Context, arg *sqlc.CreateEventParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := sqlc.Events{
+		ID:             s.id(),
+		Name:           arg.Name,
+		Description:    arg.Description,
+		Date:           arg.Date,
+		CreatedAt:      now(),
+		Version:        1,
+		SeatAssignment: "registration",
+		Categories:     []string{},
+		Status:         "draft",
+	}
+	s.events[event.ID] = event
+	return &event, nil
+}
+
+func (s *Store) UpdateEvent(ctx context.Context, arg *sqlc.UpdateEventParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok || event.Version != arg.Version {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.Name = arg.Name
+	event.Description = arg.Description
+	event.Date = arg.Date
+	event.Version++
+	s.events[event.ID] = event
+	return &event, nil
+}
+
+func (s *Store) GetEvents(ctx context.Context) ([]*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]*sqlc.Events, 0, len(s.events))
+	for _, event := range s.events {
+		if !event.DeletedAt.Valid {
+			events = append(events, &event)
+		}
+	}
+	slices.SortFunc(events, byCreatedAtDesc(func(e *sqlc.Events) time.Time { return e.CreatedAt.Time }))
+	return events, nil
+}
+
+func (s *Store) GetEventsPage(ctx context.Context, arg *sqlc.GetEventsPageParams) ([]*sqlc.Events, error) {
+	events, _ := s.GetEvents(ctx)
+	organized, _ := s.GetOrganizedEventIDs(ctx, arg.OrganizerID)
+	events = slices.DeleteFunc(events, func(event *sqlc.Events) bool {
+		return event.ArchivedAt.Valid != arg.Archived || (arg.Category != "" && !slices.Contains(event.Categories, arg.Category)) ||
+			(arg.OrganizerID != 0 && !slices.Contains(organized, event.ID))
+	})
+	start := min(int(arg.PageOffset), len(events))
+	end := min(start+int(arg.PageSize), len(events))
+	return events[start:end], nil
+}
+
+func (s *Store) GetEventByID(ctx context.Context, id int64) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[id]
+	if !ok || event.DeletedAt.Valid {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	return &event, nil
+}
+
+func (s *Store) GetEventBySlug(ctx context.Context, slug string) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range s.events {
+		if event.Slug.Valid && event.Slug.String == slug && !event.DeletedAt.Valid {
+			return &event, nil
+		}
+	}
+	return &sqlc.Events{}, sql.ErrNoRows
+}
+
+func (s *Store) EventSlugTaken(ctx context.Context, arg *sqlc.EventSlugTakenParams) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range s.events {
+		if event.Slug.Valid && event.Slug.String == arg.Slug && event.ID != arg.ID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Store) SetEventSlug(ctx context.Context, arg *sqlc.SetEventSlugParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok || event.DeletedAt.Valid {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	for _, other := range s.events {
+		if other.ID != arg.ID && other.Slug.Valid && other.Slug.String == arg.Slug {
+			return &sqlc.Events{}, uniqueViolation("events_slug_key")
+		}
+	}
+	event.Slug = sql.NullString{String: arg.Slug, Valid: true}
+	s.events[arg.ID] = event
+	return &event, nil
+}
+
+func (s *Store) GetLastEvent(ctx context.Context) (*sqlc.Events, error) {
+	events, _ := s.GetEvents(ctx)
+	if len(events) == 0 {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	return events[0], nil
+}
+
+func (s *Store) DeleteEvent(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event, ok := s.events[id]; ok && !event.DeletedAt.Valid {
+		event.DeletedAt = now()
+		s.events[id] = event
+	}
+	return nil
+}
+
+func (s *Store) GetDeletedEvents(ctx context.Context) ([]*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]*sqlc.Events, 0)
+	for _, event := range s.events {
+		if event.DeletedAt.Valid {
+			events = append(events, &event)
+		}
+	}
+	slices.SortFunc(events, func(a, b *sqlc.Events) int {
+		return cmp.Or(b.DeletedAt.Time.Compare(a.DeletedAt.Time), cmp.Compare(b.ID, a.ID))
+	})
+	return events, nil
+}
+
+func (s *Store) RestoreEvent(ctx context.Context, id int64) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[id]
+	if !ok || !event.DeletedAt.Valid {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.DeletedAt = sql.NullTime{}
+	event.CalendarEventID = sql.NullString{}
+	event.Version++
+	s.events[id] = event
+	return &event, nil
+}
+
+func (s *Store) PurgeDeletedEventsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, event := range s.events {
+		if event.DeletedAt.Valid && event.DeletedAt.Time.Before(cutoff) {
+			s.purgeEvent(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// purgeEvent removes the event with everything that references it, as the
+// foreign keys cascade.
+func (s *Store) purgeEvent(id int64) {
+	delete(s.events, id)
+	delete(s.images, id)
+	for userID, user := range s.users {
+		if user.EventID == id {
+			delete(s.users, userID)
+		}
+	}
+	for entryID, entry := range s.waitlist {
+		if entry.EventID == id {
+			delete(s.waitlist, entryID)
+		}
+	}
+	for ruleID, rule := range s.rules {
+		if rule.EventID == id {
+			delete(s.rules, ruleID)
+		}
+	}
+	for organizerID, organizer := range s.organizers {
+		if organizer.EventID == id {
+			delete(s.organizers, organizerID)
+		}
+	}
+	for key := range s.translations {
+		if key.eventID == id {
+			delete(s.translations, key)
+		}
+	}
+	for key, click := range s.shareClicks {
+		if click.EventID == id {
+			delete(s.shareClicks, key)
+		}
+	}
+	for purchaseID, purchase := range s.purchases {
+		if purchase.EventID == id {
+			delete(s.purchases, purchaseID)
+		}
+	}
+	for orderID, order := range s.ticketOrders {
+		if order.EventID == id {
+			delete(s.ticketOrders, orderID)
+		}
+	}
+	for codeID, code := range s.promoCodes {
+		if code.EventID == id {
+			delete(s.promoCodes, codeID)
+		}
+	}
+	for redemptionID, redemption := range s.redemptions {
+		if _, ok := s.promoCodes[redemption.PromoCodeID]; !ok {
+			delete(s.redemptions, redemptionID)
+		}
+	}
+	for linkID, link := range s.shortLinks {
+		if link.EventID == id {
+			delete(s.shortLinks, linkID)
+		}
+	}
+	for typeID, ticketType := range s.ticketTypes {
+		if ticketType.EventID == id {
+			delete(s.ticketTypes, typeID)
+		}
+	}
+	for seatID, seat := range s.seats {
+		if seat.EventID == id {
+			delete(s.seats, seatID)
+		}
+	}
+	for drawID, draw := range s.draws {
+		if draw.EventID == id {
+			delete(s.draws, drawID)
+			delete(s.drawWinners, drawID)
+		}
+	}
+	for broadcastID, broadcast := range s.broadcasts {
+		if broadcast.EventID == id {
+			delete(s.broadcasts, broadcastID)
+		}
+	}
+	for deliveryID, delivery := range s.deliveries {
+		if _, ok := s.broadcasts[delivery.BroadcastID]; !ok {
+			delete(s.deliveries, deliveryID)
+			s.detachOutbox(deliveryID)
+		}
+	}
+	for recordID, record := range s.consentLog {
+		if record.EventID == id {
+			delete(s.consentLog, recordID)
+		}
+	}
+}
+
+func (s *Store) SetEventWaitlistAutoPromote(ctx context.Context, arg *sqlc.SetEventWaitlistAutoPromoteParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.WaitlistAutoPromote = arg.WaitlistAutoPromote
+	s.events[event.ID] = event
+	return &event, nil
+}
+
+func (s *Store) SetEventPaidEntries(ctx context.Context, arg *sqlc.SetEventPaidEntriesParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.MaxPaidEntries = arg.MaxPaidEntries
+	event.EntryPrice = arg.EntryPrice
+	s.events[event.ID] = event
+	return &event, nil
+}
+
+func (s *Store) SetEventTicketPrice(ctx context.Context, arg *sqlc.SetEventTicketPriceParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.TicketPrice = arg.TicketPrice
+	s.events[event.ID] = event
+	return &event, nil
+}
+
+func (s *Store) SetEventShareBonus(ctx context.Context, arg *sqlc.SetEventShareBonusParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.ShareClicksRequired = arg.ShareClicksRequired
+	event.ShareBonus = arg.ShareBonus
+	s.events[event.ID] = event
+	return &event, nil
+}
+
+func (s *Store) SetEventSeatAssignment(ctx context.Context, arg *sqlc.SetEventSeatAssignmentParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.SeatAssignment = arg.SeatAssignment
+	s.events[event.ID] = event
+	return &event, nil
+}
+
+func (s *Store) SetEventShowWinners(ctx context.Context, arg *sqlc.SetEventShowWinnersParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.ShowWinners = arg.ShowWinners
+	s.events[event.ID] = event
+	return &event, nil
+}
+
+func (s *Store) SyncLastTicketNumber(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[id]
+	if !ok {
+		return nil
+	}
+	for _, user := range s.users {
+		if user.EventID == id {
+			event.LastTicketNumber = max(event.LastTicketNumber, user.TicketNumber)
+		}
+	}
+	s.events[id] = event
+	return nil
+}
+
+func (s *Store) ArchiveEventsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var archived int64
+	for id, event := range s.events {
+		if event.ArchivedAt.Valid || event.UnarchivedAt.Valid || !event.Date.Before(cutoff) {
+			continue
+		}
+		event.ArchivedAt = now()
+		s.events[id] = event
+		archived++
+	}
+	return archived, nil
+}
+
+func (s *Store) ArchiveEvent(ctx context.Context, id int64) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[id]
+	if !ok {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	if !event.ArchivedAt.Valid {
+		event.ArchivedAt = now()
+	}
+	s.events[id] = event
+	return &event, nil
+}
+
+func (s *Store) UnarchiveEvent(ctx context.Context, id int64) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[id]
+	if !ok {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.ArchivedAt = sql.NullTime{}
+	event.UnarchivedAt = now()
+	s.events[id] = event
+	return &event, nil
+}
+
+func (s *Store) SetEventCategories(ctx context.Context, arg *sqlc.SetEventCategoriesParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok || event.DeletedAt.Valid {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.Categories = slices.Clone(arg.Categories)
+	s.events[arg.ID] = event
+	return &event, nil
+}
+
+func (s *Store) GetEventCategories(ctx context.Context, archived bool) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categories := make([]string, 0)
+	for _, event := range s.events {
+		if event.ArchivedAt.Valid == archived && !event.DeletedAt.Valid {
+			categories = append(categories, event.Categories...)
+		}
+	}
+	slices.Sort(categories)
+	return slices.Compact(categories), nil
+}
+
+func (s *Store) SetEventCapacity(ctx context.Context, arg *sqlc.SetEventCapacityParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok || event.DeletedAt.Valid {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.Capacity = arg.Capacity
+	s.events[arg.ID] = event
+	return &event, nil
+}
+
+func (s *Store) SetEventStatus(ctx context.Context, arg *sqlc.SetEventStatusParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok || event.DeletedAt.Valid {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.Status = arg.Status
+	s.events[arg.ID] = event
+	return &event, nil
+}
+
+func (s *Store) SetEventRegistrationWindow(ctx context.Context, arg *sqlc.SetEventRegistrationWindowParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok || event.DeletedAt.Valid {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.RegistrationOpensAt = arg.RegistrationOpensAt
+	event.RegistrationClosesAt = arg.RegistrationClosesAt
+	s.events[arg.ID] = event
+	return &event, nil
+}
+
+// GetEventForUpdate needs no lock, since transactions already run one at a
+// time.
+func (s *Store) GetEventForUpdate(ctx context.Context, id int64) (*sqlc.Events, error) {
+	return s.GetEventByID(ctx, id)
+}
+
+func (s *Store) CountEventTaken(ctx context.Context, arg *sqlc.CountEventTakenParams) (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var taken int32
+	for _, user := range s.users {
+		if user.EventID == arg.ID && !user.DeletedAt.Valid {
+			taken++
+		}
+	}
+	since := time.Now().Add(-time.Duration(arg.PendingSeconds) * time.Second)
+	for _, order := range s.ticketOrders {
+		if order.EventID == arg.ID && order.Status == "pending" && order.CreatedAt.After(since) {
+			taken++
+		}
+	}
+	return taken, nil
+}
+
+func (s *Store) SetEventImage(ctx context.Context, arg *sqlc.SetEventImageParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.EventID]
+	if !ok {
+		return &sqlc.Events{}, &pq.Error{Code: "23503", Message: "insert or update on table \"event_images\" violates foreign key constraint \"event_images_event_id_fkey\""}
+	}
+	if event.DeletedAt.Valid {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	image := sqlc.EventImages{
+		EventID:     arg.EventID,
+		ContentType: arg.ContentType,
+		Data:        slices.Clone(arg.Data),
+		UpdatedAt:   time.Now(),
+	}
+	s.images[arg.EventID] = image
+	event.ImageUpdatedAt = sql.NullTime{Time: image.UpdatedAt, Valid: true}
+	s.events[arg.EventID] = event
+	return &event, nil
+}
+
+func (s *Store) GetEventImage(ctx context.Context, eventID int64) (*sqlc.EventImages, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	image, ok := s.images[eventID]
+	if !ok || s.events[eventID].DeletedAt.Valid {
+		return &sqlc.EventImages{}, sql.ErrNoRows
+	}
+	return &image, nil
+}
+
+func (s *Store) DeleteEventImage(ctx context.Context, eventID int64) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.images, eventID)
+	event, ok := s.events[eventID]
+	if !ok || event.DeletedAt.Valid {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.ImageUpdatedAt = sql.NullTime{}
+	s.events[eventID] = event
+	return &event, nil
+}
+
+func (s *Store) GetEventsToSyncToCalendar(ctx context.Context) ([]*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]*sqlc.Events, 0)
+	for _, event := range s.events {
+		if event.CalendarSyncedVersion != event.Version && !event.ArchivedAt.Valid && !event.DeletedAt.Valid {
+			events = append(events, &event)
+		}
+	}
+	slices.SortFunc(events, func(a, b *sqlc.Events) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+// LockEventForCalendarSync has nothing to lock, since transactions run
+// one at a time.
+func (s *Store) LockEventForCalendarSync(ctx context.Context, id int64) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[id]
+	if !ok || event.CalendarSyncedVersion == event.Version || event.DeletedAt.Valid {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	return &event, nil
+}
+
+func (s *Store) SetEventCalendarSynced(ctx context.Context, arg *sqlc.SetEventCalendarSyncedParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event, ok := s.events[arg.ID]; ok {
+		event.CalendarEventID = arg.CalendarEventID
+		event.CalendarSyncedVersion = arg.CalendarSyncedVersion
+		s.events[arg.ID] = event
+	}
+	return nil
+}
+
+func (s *Store) SetEventKioskToken(ctx context.Context, arg *sqlc.SetEventKioskTokenParams) (*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.ID]
+	if !ok {
+		return &sqlc.Events{}, sql.ErrNoRows
+	}
+	event.KioskToken = arg.KioskToken
+	s.events[event.ID] = event
+	return &event, nil
+}
+
+func (s *Store) CountUsersByEventID(ctx context.Context, eventID int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.eventUsers(eventID, func(sqlc.Users) bool { return true }))), nil
+}
+
+func (s *Store) CreateUser(ctx context.Context, arg *sqlc.CreateUserParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[arg.EventID]
+	if !ok {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	for _, user := range s.users {
+		if arg.TgID.Valid && user.TgID == arg.TgID && user.EventID == arg.EventID && !user.DeletedAt.Valid {
+			return &sqlc.Users{}, uniqueViolation("unique_tg_event_id")
+		}
+		if user.CheckInCode == arg.CheckInCode && user.EventID == arg.EventID {
+			return &sqlc.Users{}, uniqueViolation("unique_check_in_code_event_id")
+		}
+	}
+
+	event.LastTicketNumber++
+	s.events[event.ID] = event
+
+	createdAt := now()
+	bonus, ruleIDs := s.entryBonus(arg.EventID, event.LastTicketNumber, createdAt.Time)
+	user := sqlc.Users{
+		ID:             s.id(),
+		Name:           arg.Name,
+		Username:       arg.Username,
+		TgID:           arg.TgID,
+		EventID:        arg.EventID,
+		CreatedAt:      createdAt,
+		N:              1,
+		TicketNumber:   event.LastTicketNumber,
+		CheckInCode:    arg.CheckInCode,
+		BonusEntries:   bonus,
+		AppliedRuleIds: ruleIDs,
+		Tags:           []string{},
+	}
+	s.users[user.ID] = user
+	return &user, nil
+}
+
+func (s *Store) ImportUser(ctx context.Context, arg *sqlc.ImportUserParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &sqlc.Users{}, &pq.Error{Code: "23503", Message: "insert or update on table \"users\" violates foreign key constraint \"users_event_id_fkey\""}
+	}
+	for _, user := range s.users {
+		if user.EventID != arg.EventID {
+			continue
+		}
+		if arg.TgID.Valid && user.TgID == arg.TgID && !user.DeletedAt.Valid {
+			return &sqlc.Users{}, uniqueViolation("unique_tg_event_id")
+		}
+		if user.CheckInCode == arg.CheckInCode {
+			return &sqlc.Users{}, uniqueViolation("unique_check_in_code_event_id")
+		}
+		if user.TicketNumber == arg.TicketNumber {
+			return &sqlc.Users{}, uniqueViolation("unique_ticket_event_id")
+		}
+	}
+
+	user := sqlc.Users{
+		ID:                    s.id(),
+		Name:                  arg.Name,
+		Username:              arg.Username,
+		TgID:                  arg.TgID,
+		EventID:               arg.EventID,
+		CreatedAt:             arg.CreatedAt,
+		N:                     arg.N,
+		TicketNumber:          arg.TicketNumber,
+		CheckedInAt:           arg.CheckedInAt,
+		CheckInCode:           arg.CheckInCode,
+		Phone:                 arg.Phone,
+		AttendanceConfirmedAt: arg.AttendanceConfirmedAt,
+		PaidEntries:           arg.PaidEntries,
+		BonusEntries:          arg.BonusEntries,
+		AppliedRuleIds:        slices.Clone(arg.AppliedRuleIds),
+		ShareEntries:          arg.ShareEntries,
+		ShareBonusGrantedAt:   arg.ShareBonusGrantedAt,
+		PromoEntries:          arg.PromoEntries,
+		TicketTypeID:          arg.TicketTypeID,
+		FlagReason:            arg.FlagReason,
+		ReviewedAt:            arg.ReviewedAt,
+		Tags:                  slices.Clone(arg.Tags),
+		Notes:                 arg.Notes,
+		BotBlockedAt:          arg.BotBlockedAt,
+	}
+	s.users[user.ID] = user
+	return &user, nil
+}
+
+func (s *Store) CreateUsersBatch(ctx context.Context, arg *sqlc.CreateUsersBatchParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(arg.Usernames) != len(arg.Names) || len(arg.TgIds) != len(arg.Names) || len(arg.CheckInCodes) != len(arg.Names) {
+		return 0, &pq.Error{Code: "23502", Message: "null value in column of relation \"users\" violates not-null constraint"}
+	}
+	event, ok := s.events[arg.EventID]
+	if !ok {
+		return 0, nil
+	}
+	base := event.LastTicketNumber
+	event.LastTicketNumber += int32(len(arg.Names))
+	s.events[event.ID] = event
+
+	taken := make(map[int64]bool)
+	codes := make(map[string]bool)
+	for _, user := range s.users {
+		if user.EventID == arg.EventID && user.TgID.Valid && !user.DeletedAt.Valid {
+			taken[user.TgID.Int64] = true
+		}
+		if user.EventID == arg.EventID {
+			codes[user.CheckInCode] = true
+		}
+	}
+	for _, code := range arg.CheckInCodes {
+		if codes[code] {
+			return 0, uniqueViolation("unique_check_in_code_event_id")
+		}
+		codes[code] = true
+	}
+
+	var inserted int64
+	for i, name := range arg.Names {
+		tgID := sql.NullInt64{Int64: arg.TgIds[i], Valid: arg.TgIds[i] != 0}
+		if tgID.Valid {
+			if taken[tgID.Int64] {
+				continue
+			}
+			taken[tgID.Int64] = true
+		}
+
+		createdAt := now()
+		bonus, ruleIDs := s.entryBonus(arg.EventID, base+int32(i)+1, createdAt.Time)
+		user := sqlc.Users{
+			ID:             s.id(),
+			Name:           name,
+			Username:       arg.Usernames[i],
+			TgID:           tgID,
+			EventID:        arg.EventID,
+			CreatedAt:      createdAt,
+			N:              1,
+			TicketNumber:   base + int32(i) + 1,
+			CheckInCode:    arg.CheckInCodes[i],
+			BonusEntries:   bonus,
+			AppliedRuleIds: ruleIDs,
+			Tags:           []string{},
+		}
+		s.users[user.ID] = user
+		inserted++
+	}
+	return inserted, nil
+}
+
+func (s *Store) DeleteUser(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeUser(id)
+	return nil
+}
+
+// purgeUser removes the participant, detaching the rows that keep a
+// reference to them as the foreign keys do.
+func (s *Store) purgeUser(id int64) {
+	delete(s.users, id)
+	s.detachPurchases(id)
+	s.detachTicketOrders(id)
+	s.detachSeats(id)
+	s.detachRedemptions(id)
+	s.detachDeliveries(id)
+	s.detachConsentLog(id)
+	s.detachOutboxSMS(id)
+	s.deleteShareClicks(id)
+}
+
+func (s *Store) DeleteUsersByIdAndEventId(ctx context.Context, arg *sqlc.DeleteUsersByIdAndEventIdParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user, ok := s.users[arg.ID]; ok && user.EventID == arg.EventID && !user.DeletedAt.Valid {
+		user.DeletedAt = now()
+		s.users[arg.ID] = user
+		s.detachSeats(arg.ID)
+	}
+	return nil
+}
+
+func (s *Store) GetDeletedUsers(ctx context.Context) ([]*sqlc.GetDeletedUsersRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := make([]*sqlc.GetDeletedUsersRow, 0)
+	for _, user := range s.users {
+		event, ok := s.events[user.EventID]
+		if user.DeletedAt.Valid && ok && !event.DeletedAt.Valid {
+			rows = append(rows, &sqlc.GetDeletedUsersRow{Users: user, EventName: event.Name})
+		}
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetDeletedUsersRow) int {
+		return cmp.Or(b.Users.DeletedAt.Time.Compare(a.Users.DeletedAt.Time), cmp.Compare(b.Users.ID, a.Users.ID))
+	})
+	return rows, nil
+}
+
+func (s *Store) RestoreUser(ctx context.Context, id int64) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok || !user.DeletedAt.Valid {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	for _, other := range s.users {
+		if user.TgID.Valid && other.ID != id && other.EventID == user.EventID && other.TgID == user.TgID && !other.DeletedAt.Valid {
+			return &sqlc.Users{}, uniqueViolation("unique_tg_event_id")
+		}
+	}
+	user.DeletedAt = sql.NullTime{}
+	s.users[id] = user
+	return &user, nil
+}
+
+func (s *Store) PurgeDeletedUsersBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, user := range s.users {
+		if user.DeletedAt.Valid && user.DeletedAt.Time.Before(cutoff) {
+			s.purgeUser(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id int64) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	return &user, nil
+}
+
+func (s *Store) GetUserByUsername(ctx context.Context, username string) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.Username == username && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return &sqlc.Users{}, sql.ErrNoRows
+}
+
+func (s *Store) GetUserByCheckInCode(ctx context.Context, arg *sqlc.GetUserByCheckInCodeParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.EventID == arg.EventID && user.CheckInCode == arg.CheckInCode && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return &sqlc.Users{}, sql.ErrNoRows
+}
+
+func (s *Store) GetUserByTicketNumber(ctx context.Context, arg *sqlc.GetUserByTicketNumberParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.EventID == arg.EventID && user.TicketNumber == arg.TicketNumber && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return &sqlc.Users{}, sql.ErrNoRows
+}
+
+func (s *Store) GetUserByTgIDAndEventID(ctx context.Context, arg *sqlc.GetUserByTgIDAndEventIDParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.EventID == arg.EventID && user.TgID.Valid && user.TgID.Int64 == arg.TgID && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return &sqlc.Users{}, sql.ErrNoRows
+}
+
+func (s *Store) CheckInUser(ctx context.Context, id int64) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	if !user.CheckedInAt.Valid {
+		user.CheckedInAt = now()
+		s.users[id] = user
+	}
+	return &user, nil
+}
+
+func (s *Store) UpdateUserProfile(ctx context.Context, arg *sqlc.UpdateUserProfileParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[arg.ID]
+	if !ok {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	user.Name = arg.Name
+	user.Phone = arg.Phone
+	s.users[arg.ID] = user
+	return &user, nil
+}
+
+func (s *Store) ConfirmUserAttendance(ctx context.Context, id int64) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	if !user.AttendanceConfirmedAt.Valid {
+		user.AttendanceConfirmedAt = now()
+		s.users[id] = user
+	}
+	return &user, nil
+}
+
+func (s *Store) FlagSuspiciousUsers(ctx context.Context, arg *sqlc.FlagSuspiciousUsersParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := s.eventUsers(arg.EventID, func(sqlc.Users) bool { return true })
+	burst := time.Duration(arg.BurstSeconds) * time.Second
+	reason := func(u *sqlc.Users) string {
+		for _, o := range users {
+			if o.ID != u.ID && u.Phone != "" && o.Phone == u.Phone {
+				return "same_phone"
+			}
+		}
+		for _, o := range users {
+			if o.ID != u.ID && strings.EqualFold(strings.TrimSpace(o.Name), strings.TrimSpace(u.Name)) {
+				return "duplicate_name"
+			}
+		}
+		for _, o := range users {
+			if o.ID != u.ID && u.TgID.Valid && o.TgID.Valid &&
+				o.TgID.Int64 >= u.TgID.Int64-arg.MaxTgIDGap && o.TgID.Int64 <= u.TgID.Int64+arg.MaxTgIDGap &&
+				o.CreatedAt.Time.Sub(u.CreatedAt.Time).Abs() <= burst {
+				return "sequential_tg_id"
+			}
+		}
+		return ""
+	}
+
+	var flagged int64
+	for _, u := range users {
+		if u.FlagReason.Valid || u.ReviewedAt.Valid {
+			continue
+		}
+		if r := reason(u); r != "" {
+			user := s.users[u.ID]
+			user.FlagReason = sql.NullString{String: r, Valid: true}
+			s.users[u.ID] = user
+			flagged++
+		}
+	}
+	return flagged, nil
+}
+
+func (s *Store) GetFlaggedUsers(ctx context.Context, eventID int64) ([]*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := s.eventUsers(eventID, func(user sqlc.Users) bool {
+		return user.FlagReason.Valid && !user.ReviewedAt.Valid
+	})
+	slices.SortStableFunc(users, func(a, b *sqlc.Users) int {
+		return cmp.Or(
+			cmp.Compare(a.FlagReason.String, b.FlagReason.String),
+			cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)))
+	})
+	return users, nil
+}
+
+func (s *Store) ApproveUser(ctx context.Context, arg *sqlc.ApproveUserParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[arg.ID]
+	if !ok || user.EventID != arg.EventID {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	if !user.ReviewedAt.Valid {
+		user.ReviewedAt = now()
+		s.users[arg.ID] = user
+	}
+	return &user, nil
+}
+
+func (s *Store) SetUserTags(ctx context.Context, arg *sqlc.SetUserTagsParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[arg.ID]
+	if !ok || user.EventID != arg.EventID {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	user.Tags = slices.Clone(arg.Tags)
+	s.users[arg.ID] = user
+	return &user, nil
+}
+
+func (s *Store) SetUserNotes(ctx context.Context, arg *sqlc.SetUserNotesParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[arg.ID]
+	if !ok || user.EventID != arg.EventID {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	user.Notes = arg.Notes
+	s.users[arg.ID] = user
+	return &user, nil
+}
+
+func (s *Store) AddUserPaidEntries(ctx context.Context, arg *sqlc.AddUserPaidEntriesParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user, ok := s.users[arg.ID]; ok {
+		user.PaidEntries += arg.Entries
+		s.users[arg.ID] = user
+	}
+	return nil
+}
+
+func (s *Store) AddUserPromoEntries(ctx context.Context, arg *sqlc.AddUserPromoEntriesParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user, ok := s.users[arg.ID]; ok {
+		user.PromoEntries += arg.Entries
+		s.users[arg.ID] = user
+	}
+	return nil
+}
+
+func (s *Store) SetUserTicketType(ctx context.Context, arg *sqlc.SetUserTicketTypeParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[arg.ID]
+	if !ok {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	if _, ok := s.ticketTypes[arg.TicketTypeID]; !ok {
+		return &sqlc.Users{}, &pq.Error{Code: "23503", Message: "insert or update on table \"users\" violates foreign key constraint \"users_ticket_type_id_fkey\""}
+	}
+	user.TicketTypeID = sql.NullInt64{Int64: arg.TicketTypeID, Valid: true}
+	user.N = arg.Weight
+	s.users[arg.ID] = user
+	return &user, nil
+}
+
+func (s *Store) SetUserShareCode(ctx context.Context, arg *sqlc.SetUserShareCodeParams) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[arg.ID]
+	if !ok {
+		return &sqlc.Users{}, sql.ErrNoRows
+	}
+	if user.ShareCode.Valid {
+		return &user, nil
+	}
+	for _, other := range s.users {
+		if other.ShareCode.Valid && other.ShareCode.String == arg.ShareCode {
+			return &sqlc.Users{}, uniqueViolation("users_share_code_key")
+		}
+	}
+	user.ShareCode = sql.NullString{String: arg.ShareCode, Valid: true}
+	s.users[arg.ID] = user
+	return &user, nil
+}
+
+func (s *Store) GetUserByShareCode(ctx context.Context, shareCode string) (*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.ShareCode.Valid && user.ShareCode.String == shareCode && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return &sqlc.Users{}, sql.ErrNoRows
+}
+
+func (s *Store) GrantShareBonus(ctx context.Context, arg *sqlc.GrantShareBonusParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[arg.ID]
+	if !ok || user.ShareBonusGrantedAt.Valid {
+		return 0, nil
+	}
+	user.ShareEntries += arg.Entries
+	user.ShareBonusGrantedAt = now()
+	s.users[arg.ID] = user
+	return 1, nil
+}
+
+func (s *Store) eventUsers(eventID int64, match func(sqlc.Users) bool) []*sqlc.Users {
+	users := make([]*sqlc.Users, 0)
+	for _, user := range s.users {
+		if user.EventID == eventID && !user.DeletedAt.Valid && match(user) {
+			users = append(users, &user)
+		}
+	}
+	slices.SortFunc(users, func(a, b *sqlc.Users) int { return cmp.Compare(a.ID, b.ID) })
+	return users
+}
+
+func (s *Store) GetUsersByEventID(ctx context.Context, eventID int64) ([]*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.eventUsers(eventID, func(sqlc.Users) bool { return true }), nil
+}
+
+func (s *Store) GetUsersByEventIDAfter(ctx context.Context, arg *sqlc.GetUsersByEventIDAfterParams) ([]*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := s.eventUsers(arg.EventID, func(user sqlc.Users) bool { return user.ID > arg.AfterID })
+	if len(users) > int(arg.PageSize) {
+		users = users[:arg.PageSize]
+	}
+	return users, nil
+}
+
+// hasTag reports whether the user has tag, with "" matching everyone like
+// the tag filters of the queries.
+func hasTag(user sqlc.Users, tag string) bool {
+	return tag == "" || slices.Contains(user.Tags, tag)
+}
+
+func (s *Store) GetUsersPage(ctx context.Context, arg *sqlc.GetUsersPageParams) ([]*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := strings.ToLower(arg.Query)
+	users := s.eventUsers(arg.EventID, func(user sqlc.Users) bool {
+		return hasTag(user, arg.Tag) &&
+			(strings.Contains(strings.ToLower(user.Name), query) || strings.Contains(strings.ToLower(user.Username), query))
+	})
+	// eventUsers orders by ID, which breaks ties
+	slices.SortStableFunc(users, func(a, b *sqlc.Users) int {
+		var c int
+		switch arg.Sort {
+		case "name":
+			c = cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		case "username":
+			c = cmp.Compare(strings.ToLower(a.Username), strings.ToLower(b.Username))
+		case "n":
+			c = cmp.Compare(a.N, b.N)
+		case "registered":
+			c = a.CreatedAt.Time.Compare(b.CreatedAt.Time)
+		}
+		if arg.Descending {
+			return -c
+		}
+		return c
+	})
+	start := min(int(arg.PageOffset), len(users))
+	end := min(start+int(arg.PageSize), len(users))
+	return users[start:end], nil
+}
+
+func (s *Store) CountUsersWithTag(ctx context.Context, arg *sqlc.CountUsersWithTagParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.eventUsers(arg.EventID, func(user sqlc.Users) bool { return hasTag(user, arg.Tag) }))), nil
+}
+
+func (s *Store) CountUsersReach(ctx context.Context, eventID int64) (*sqlc.CountUsersReachRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var row sqlc.CountUsersReachRow
+	for _, user := range s.eventUsers(eventID, func(sqlc.Users) bool { return true }) {
+		if user.TgID.Valid && !user.BotBlockedAt.Valid {
+			row.Reachable++
+		}
+		if user.BotBlockedAt.Valid {
+			row.Blocked++
+		}
+	}
+	return &row, nil
+}
+
+func (s *Store) GetEventTags(ctx context.Context, eventID int64) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tags := make([]string, 0)
+	for _, user := range s.eventUsers(eventID, func(sqlc.Users) bool { return true }) {
+		tags = append(tags, user.Tags...)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags), nil
+}
+
+func (s *Store) GetLatestUsersByEventID(ctx context.Context, arg *sqlc.GetLatestUsersByEventIDParams) ([]*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := s.eventUsers(arg.EventID, func(sqlc.Users) bool { return true })
+	slices.Reverse(users)
+	if len(users) > int(arg.PageSize) {
+		users = users[:arg.PageSize]
+	}
+	return users, nil
+}
+
+func (s *Store) UpdateUserN(ctx context.Context, arg *sqlc.UpdateUserNParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user, ok := s.users[arg.ID]; ok && user.EventID == arg.EventID {
+		user.N = arg.N
+		s.users[arg.ID] = user
+	}
+	return nil
+}
+
+func (s *Store) CreateDraw(ctx context.Context, arg *sqlc.CreateDrawParams) (*sqlc.Draws, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	draw := sqlc.Draws{
+		ID:           s.id(),
+		EventID:      arg.EventID,
+		WinnersCount: arg.WinnersCount,
+		CreatedAt:    now(),
+		Seed:         arg.Seed,
+	}
+	s.draws[draw.ID] = draw
+	return &draw, nil
+}
+
+func (s *Store) ImportDraw(ctx context.Context, arg *sqlc.ImportDrawParams) (*sqlc.Draws, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	draw := sqlc.Draws{
+		ID:           s.id(),
+		EventID:      arg.EventID,
+		WinnersCount: arg.WinnersCount,
+		CreatedAt:    arg.CreatedAt,
+		Seed:         arg.Seed,
+	}
+	s.draws[draw.ID] = draw
+	return &draw, nil
+}
+
+func (s *Store) CreateDrawWinner(ctx context.Context, arg *sqlc.CreateDrawWinnerParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.drawWinners[arg.DrawID] = append(s.drawWinners[arg.DrawID], sqlc.DrawWinners{
+		DrawID:   arg.DrawID,
+		UserID:   arg.UserID,
+		Position: arg.Position,
+	})
+	return nil
+}
+
+func (s *Store) GetDrawsByEventID(ctx context.Context, eventID int64) ([]*sqlc.Draws, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	draws := make([]*sqlc.Draws, 0)
+	for _, draw := range s.draws {
+		if draw.EventID == eventID {
+			draws = append(draws, &draw)
+		}
+	}
+	slices.SortFunc(draws, byCreatedAtDesc(func(d *sqlc.Draws) time.Time { return d.CreatedAt.Time }))
+	return draws, nil
+}
+
+func (s *Store) GetDrawWinners(ctx context.Context, drawID int64) ([]*sqlc.GetDrawWinnersRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := make([]*sqlc.GetDrawWinnersRow, 0)
+	for _, winner := range s.drawWinners[drawID] {
+		if user, ok := s.users[winner.UserID]; ok && !user.DeletedAt.Valid {
+			rows = append(rows, &sqlc.GetDrawWinnersRow{Users: user, Position: winner.Position})
+		}
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetDrawWinnersRow) int { return cmp.Compare(a.Position, b.Position) })
+	return rows, nil
+}
+
+func (s *Store) GetPublicWinners(ctx context.Context, arg *sqlc.GetPublicWinnersParams) ([]*sqlc.GetPublicWinnersRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shown := make([]sqlc.Events, 0)
+	for _, event := range s.events {
+		if event.ShowWinners && event.Date.Before(time.Now()) && !event.DeletedAt.Valid {
+			shown = append(shown, event)
+		}
+	}
+	slices.SortFunc(shown, func(a, b sqlc.Events) int {
+		return cmp.Or(b.Date.Compare(a.Date), cmp.Compare(b.ID, a.ID))
+	})
+	shown = shown[min(int(arg.PageOffset), len(shown)):]
+	shown = shown[:min(int(arg.PageSize), len(shown))]
+
+	rows := make([]*sqlc.GetPublicWinnersRow, 0)
+	for _, event := range shown {
+		var latest *sqlc.Draws
+		for _, draw := range s.draws {
+			if draw.EventID != event.ID {
+				continue
+			}
+			if latest == nil || draw.CreatedAt.Time.After(latest.CreatedAt.Time) ||
+				(draw.CreatedAt.Time.Equal(latest.CreatedAt.Time) && draw.ID > latest.ID) {
+				latest = &draw
+			}
+		}
+		if latest == nil {
+			continue
+		}
+
+		winners := slices.Clone(s.drawWinners[latest.ID])
+		slices.SortFunc(winners, func(a, b sqlc.DrawWinners) int { return cmp.Compare(a.Position, b.Position) })
+		for _, winner := range winners {
+			user, ok := s.users[winner.UserID]
+			if !ok || user.DeletedAt.Valid {
+				continue
+			}
+			rows = append(rows, &sqlc.GetPublicWinnersRow{
+				EventID:      event.ID,
+				EventName:    event.Name,
+				EventDate:    event.Date,
+				WinnerName:   user.Name,
+				TicketNumber: user.TicketNumber,
+				Position:     winner.Position,
+			})
+		}
+	}
+	return rows, nil
+}
+
+func (s *Store) AddToWaitlist(ctx context.Context, arg *sqlc.AddToWaitlistParams) (*sqlc.Waitlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &sqlc.Waitlist{}, &pq.Error{Code: "23503", Message: "insert or update on table \"waitlist\" violates foreign key constraint \"waitlist_event_id_fkey\""}
+	}
+	for _, entry := range s.waitlist {
+		if arg.TgID.Valid && entry.TgID == arg.TgID && entry.EventID == arg.EventID {
+			return &sqlc.Waitlist{}, uniqueViolation("unique_waitlist_tg_event_id")
+		}
+	}
+
+	entry := sqlc.Waitlist{
+		ID:            s.id(),
+		EventID:       arg.EventID,
+		Name:          arg.Name,
+		Username:      arg.Username,
+		TgID:          arg.TgID,
+		CreatedAt:     time.Now(),
+		TicketTypeID:  arg.TicketTypeID,
+		ConsentSource: arg.ConsentSource,
+	}
+	s.waitlist[entry.ID] = entry
+	return &entry, nil
+}
+
+// eventWaitlist returns the event's waitlist in queue order.
+func (s *Store) eventWaitlist(eventID int64) []sqlc.Waitlist {
+	entries := make([]sqlc.Waitlist, 0)
+	for _, entry := range s.waitlist {
+		if entry.EventID == eventID {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b sqlc.Waitlist) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return entries
+}
+
+func (s *Store) GetWaitlistByEventID(ctx context.Context, eventID int64) ([]*sqlc.GetWaitlistByEventIDRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := make([]*sqlc.GetWaitlistByEventIDRow, 0)
+	for i, entry := range s.eventWaitlist(eventID) {
+		rows = append(rows, &sqlc.GetWaitlistByEventIDRow{Waitlist: entry, Position: int32(i + 1)})
+	}
+	return rows, nil
+}
+
+func (s *Store) GetWaitlistEntry(ctx context.Context, arg *sqlc.GetWaitlistEntryParams) (*sqlc.Waitlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.waitlist[arg.ID]
+	if !ok || entry.EventID != arg.EventID {
+		return &sqlc.Waitlist{}, sql.ErrNoRows
+	}
+	return &entry, nil
+}
+
+func (s *Store) GetNextWaitlistEntry(ctx context.Context, eventID int64) (*sqlc.Waitlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.eventWaitlist(eventID)
+	if len(entries) == 0 {
+		return &sqlc.Waitlist{}, sql.ErrNoRows
+	}
+	return &entries[0], nil
+}
+
+func (s *Store) DeleteWaitlistEntry(ctx context.Context, arg *sqlc.DeleteWaitlistEntryParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.waitlist[arg.ID]; ok && entry.EventID == arg.EventID {
+		delete(s.waitlist, arg.ID)
+	}
+	return nil
+}
+
+func (s *Store) GetWaitlistEntryByTgID(ctx context.Context, arg *sqlc.GetWaitlistEntryByTgIDParams) (*sqlc.Waitlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.eventWaitlist(arg.EventID) {
+		if arg.TgID.Valid && entry.TgID == arg.TgID {
+			return &entry, nil
+		}
+	}
+	return &sqlc.Waitlist{}, sql.ErrNoRows
+}
+
+func (s *Store) GetWaitlistPosition(ctx context.Context, arg *sqlc.GetWaitlistPositionParams) (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.eventWaitlist(arg.EventID)
+	for i, entry := range entries {
+		if entry.ID == arg.ID {
+			return int32(i + 1), nil
+		}
+	}
+	return int32(len(entries)), nil
+}
+
+func (s *Store) GetFeatureFlags(ctx context.Context) ([]*sqlc.FeatureFlags, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flags := make([]*sqlc.FeatureFlags, 0, len(s.flags))
+	for _, flag := range s.flags {
+		flags = append(flags, &flag)
+	}
+	slices.SortFunc(flags, func(a, b *sqlc.FeatureFlags) int { return cmp.Compare(a.Name, b.Name) })
+	return flags, nil
+}
+
+func (s *Store) SetFeatureFlag(ctx context.Context, arg *sqlc.SetFeatureFlagParams) (*sqlc.FeatureFlags, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flag := sqlc.FeatureFlags{
+		Name:      arg.Name,
+		Enabled:   arg.Enabled,
+		UpdatedAt: now(),
+	}
+	s.flags[flag.Name] = flag
+	return &flag, nil
+}
+
+func (s *Store) EnqueueOutboxMessage(ctx context.Context, arg *sqlc.EnqueueOutboxMessageParams) (*sqlc.Outbox, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := sqlc.Outbox{
+		ID:            s.id(),
+		ChatID:        arg.ChatID,
+		Text:          arg.Text,
+		Markdown:      arg.Markdown,
+		DeliveryID:    arg.DeliveryID,
+		SmsUserID:     arg.SmsUserID,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     now(),
+	}
+	s.outbox[msg.ID] = msg
+	return &msg, nil
+}
+
+func (s *Store) ClaimOutboxMessages(ctx context.Context, arg *sqlc.ClaimOutboxMessagesParams) ([]*sqlc.Outbox, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make([]*sqlc.Outbox, 0)
+	for _, msg := range s.outbox {
+		if !msg.SentAt.Valid && !msg.FailedAt.Valid && !msg.NextAttemptAt.After(time.Now()) {
+			pending = append(pending, &msg)
+		}
+	}
+	slices.SortFunc(pending, func(a, b *sqlc.Outbox) int { return cmp.Compare(a.ID, b.ID) })
+	if len(pending) > int(arg.BatchSize) {
+		pending = pending[:arg.BatchSize]
+	}
+
+	lease := time.Now().Add(time.Duration(arg.LeaseSeconds) * time.Second)
+	for _, msg := range pending {
+		msg.NextAttemptAt = lease
+		s.outbox[msg.ID] = *msg
+	}
+	return pending, nil
+}
+
+func (s *Store) MarkOutboxMessageSent(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg, ok := s.outbox[id]; ok {
+		msg.Attempts++
+		msg.SentAt = now()
+		s.outbox[id] = msg
+	}
+	return nil
+}
+
+func (s *Store) MarkOutboxMessageFailed(ctx context.Context, arg *sqlc.MarkOutboxMessageFailedParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg, ok := s.outbox[arg.ID]; ok {
+		msg.Attempts++
+		msg.LastError = arg.LastError
+		msg.NextAttemptAt = time.Now().Add(time.Duration(arg.RetryAfterSeconds) * time.Second)
+		if arg.GiveUp {
+			msg.FailedAt = now()
+		}
+		s.outbox[arg.ID] = msg
+	}
+	return nil
+}
+
+func (s *Store) DeleteOutboxBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.outbox)
+	maps.DeleteFunc(s.outbox, func(_ int64, msg sqlc.Outbox) bool {
+		return (msg.SentAt.Valid && msg.SentAt.Time.Before(before)) ||
+			(msg.FailedAt.Valid && msg.FailedAt.Time.Before(before))
+	})
+	return int64(n - len(s.outbox)), nil
+}
+
+func (s *Store) CreateEntryRule(ctx context.Context, arg *sqlc.CreateEntryRuleParams) (*sqlc.EntryRules, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &sqlc.EntryRules{}, &pq.Error{Code: "23503", Message: "insert or update on table \"entry_rules\" violates foreign key constraint \"entry_rules_event_id_fkey\""}
+	}
+	if !arg.MaxTicket.Valid && !arg.RegisteredBefore.Valid {
+		return &sqlc.EntryRules{}, &pq.Error{Code: "23514", Message: "new row for relation \"entry_rules\" violates check constraint \"entry_rules_condition\""}
+	}
+
+	rule := sqlc.EntryRules{
+		ID:               s.id(),
+		EventID:          arg.EventID,
+		Name:             arg.Name,
+		MaxTicket:        arg.MaxTicket,
+		RegisteredBefore: arg.RegisteredBefore,
+		Bonus:            arg.Bonus,
+		CreatedAt:        time.Now(),
+	}
+	s.rules[rule.ID] = rule
+	return &rule, nil
+}
+
+func (s *Store) GetEntryRulesByEventID(ctx context.Context, eventID int64) ([]*sqlc.EntryRules, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.eventRules(eventID), nil
+}
+
+func (s *Store) DeleteEntryRule(ctx context.Context, arg *sqlc.DeleteEntryRuleParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rule, ok := s.rules[arg.ID]; ok && rule.EventID == arg.EventID {
+		delete(s.rules, arg.ID)
+	}
+	return nil
+}
+
+func (s *Store) CreateEventOrganizer(ctx context.Context, arg *sqlc.CreateEventOrganizerParams) (*sqlc.EventOrganizers, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &sqlc.EventOrganizers{}, &pq.Error{Code: "23503", Message: "insert or update on table \"event_organizers\" violates foreign key constraint \"event_organizers_event_id_fkey\""}
+	}
+	if arg.AdminID.Valid {
+		if _, ok := s.admins[arg.AdminID.Int64]; !ok {
+			return &sqlc.EventOrganizers{}, &pq.Error{Code: "23503", Message: "insert or update on table \"event_organizers\" violates foreign key constraint \"event_organizers_admin_id_fkey\""}
+		}
+	}
+	for _, organizer := range s.organizers {
+		if organizer.EventID == arg.EventID && organizer.Name == arg.Name {
+			return &sqlc.EventOrganizers{}, uniqueViolation("event_organizers_event_id_name_key")
+		}
+		if organizer.EventID == arg.EventID && arg.AdminID.Valid && organizer.AdminID == arg.AdminID {
+			return &sqlc.EventOrganizers{}, uniqueViolation("event_organizers_event_id_admin_id_key")
+		}
+	}
+
+	organizer := sqlc.EventOrganizers{
+		ID:             s.id(),
+		EventID:        arg.EventID,
+		Name:           arg.Name,
+		Username:       arg.Username,
+		Responsibility: arg.Responsibility,
+		CreatedAt:      time.Now(),
+		AdminID:        arg.AdminID,
+	}
+	s.organizers[organizer.ID] = organizer
+	return &organizer, nil
+}
+
+func (s *Store) GetEventOrganizers(ctx context.Context, eventID int64) ([]*sqlc.EventOrganizers, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	organizers := make([]*sqlc.EventOrganizers, 0)
+	for _, organizer := range s.organizers {
+		if organizer.EventID == eventID {
+			organizers = append(organizers, &organizer)
+		}
+	}
+	slices.SortFunc(organizers, func(a, b *sqlc.EventOrganizers) int { return cmp.Compare(a.ID, b.ID) })
+	return organizers, nil
+}
+
+func (s *Store) GetOrganizedEventIDs(ctx context.Context, adminID int64) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	eventIDs := make([]int64, 0)
+	for _, organizer := range s.organizers {
+		if organizer.AdminID.Valid && organizer.AdminID.Int64 == adminID {
+			eventIDs = append(eventIDs, organizer.EventID)
+		}
+	}
+	slices.Sort(eventIDs)
+	return eventIDs, nil
+}
+
+func (s *Store) DeleteEventOrganizer(ctx context.Context, arg *sqlc.DeleteEventOrganizerParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if organizer, ok := s.organizers[arg.ID]; ok && organizer.EventID == arg.EventID {
+		delete(s.organizers, arg.ID)
+	}
+	return nil
+}
+
+func (s *Store) UpsertEventTranslation(ctx context.Context, arg *sqlc.UpsertEventTranslationParams) (*sqlc.EventTranslations, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &sqlc.EventTranslations{}, &pq.Error{Code: "23503", Message: "insert or update on table \"event_translations\" violates foreign key constraint \"event_translations_event_id_fkey\""}
+	}
+	translation := sqlc.EventTranslations{
+		EventID:     arg.EventID,
+		Language:    arg.Language,
+		Name:        arg.Name,
+		Description: arg.Description,
+		UpdatedAt:   time.Now(),
+	}
+	s.translations[translationKey{arg.EventID, arg.Language}] = translation
+	return &translation, nil
+}
+
+func (s *Store) GetEventTranslations(ctx context.Context, eventID int64) ([]*sqlc.EventTranslations, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	translations := make([]*sqlc.EventTranslations, 0)
+	for _, translation := range s.translations {
+		if translation.EventID == eventID {
+			translations = append(translations, &translation)
+		}
+	}
+	slices.SortFunc(translations, func(a, b *sqlc.EventTranslations) int { return strings.Compare(a.Language, b.Language) })
+	return translations, nil
+}
+
+func (s *Store) GetEventTranslation(ctx context.Context, arg *sqlc.GetEventTranslationParams) (*sqlc.EventTranslations, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	translation, ok := s.translations[translationKey{arg.EventID, arg.Language}]
+	if !ok {
+		return &sqlc.EventTranslations{}, sql.ErrNoRows
+	}
+	return &translation, nil
+}
+
+func (s *Store) GetTranslationsByLanguage(ctx context.Context, language string) ([]*sqlc.EventTranslations, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	translations := make([]*sqlc.EventTranslations, 0)
+	for _, translation := range s.translations {
+		if translation.Language == language {
+			translations = append(translations, &translation)
+		}
+	}
+	slices.SortFunc(translations, func(a, b *sqlc.EventTranslations) int { return cmp.Compare(a.EventID, b.EventID) })
+	return translations, nil
+}
+
+func (s *Store) DeleteEventTranslation(ctx context.Context, arg *sqlc.DeleteEventTranslationParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.translations, translationKey{arg.EventID, arg.Language})
+	return nil
+}
+
+func (s *Store) RecalculateEntryBonuses(ctx context.Context, eventID int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var updated int64
+	for id, user := range s.users {
+		if user.EventID != eventID {
+			continue
+		}
+		user.BonusEntries, user.AppliedRuleIds = s.entryBonus(eventID, user.TicketNumber, user.CreatedAt.Time)
+		s.users[id] = user
+		updated++
+	}
+	return updated, nil
+}
+
+func (s *Store) eventRules(eventID int64) []*sqlc.EntryRules {
+	rules := make([]*sqlc.EntryRules, 0)
+	for _, rule := range s.rules {
+		if rule.EventID == eventID {
+			rules = append(rules, &rule)
+		}
+	}
+	slices.SortFunc(rules, func(a, b *sqlc.EntryRules) int { return cmp.Compare(a.ID, b.ID) })
+	return rules
+}
+
+// entryBonus evaluates the event's rules for a participant, like the
+// LATERAL subqueries in CreateUser and RecalculateEntryBonuses.
+func (s *Store) entryBonus(eventID int64, ticketNumber int32, createdAt time.Time) (int32, []int64) {
+	var bonus int32
+	ruleIDs := make([]int64, 0)
+	for _, rule := range s.eventRules(eventID) {
+		if rule.MaxTicket.Valid && ticketNumber > rule.MaxTicket.Int32 {
+			continue
+		}
+		if rule.RegisteredBefore.Valid && !createdAt.Before(rule.RegisteredBefore.Time) {
+			continue
+		}
+		bonus += rule.Bonus
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	return bonus, ruleIDs
+}
+
+func (s *Store) RecordShareClick(ctx context.Context, arg *sqlc.RecordShareClickParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := shareClick{arg.UserID, arg.VisitorHash}
+	if _, ok := s.shareClicks[key]; ok {
+		return 0, nil
+	}
+	s.shareClicks[key] = sqlc.ShareClicks{
+		UserID:      arg.UserID,
+		EventID:     arg.EventID,
+		VisitorHash: arg.VisitorHash,
+		CreatedAt:   time.Now(),
+	}
+	return 1, nil
+}
+
+func (s *Store) CountShareClicks(ctx context.Context, userID int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var clicks int64
+	for key := range s.shareClicks {
+		if key.userID == userID {
+			clicks++
+		}
+	}
+	return clicks, nil
+}
+
+func (s *Store) CountShareClicksByVisitor(ctx context.Context, arg *sqlc.CountShareClicksByVisitorParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var clicks int64
+	for _, click := range s.shareClicks {
+		if click.EventID == arg.EventID && click.VisitorHash == arg.VisitorHash {
+			clicks++
+		}
+	}
+	return clicks, nil
+}
+
+func (s *Store) GetShareReport(ctx context.Context, eventID int64) ([]*sqlc.GetShareReportRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clicks := make(map[int64]int32)
+	for key := range s.shareClicks {
+		clicks[key.userID]++
+	}
+
+	rows := make([]*sqlc.GetShareReportRow, 0)
+	for _, user := range s.eventUsers(eventID, func(user sqlc.Users) bool { return clicks[user.ID] > 0 }) {
+		rows = append(rows, &sqlc.GetShareReportRow{Users: *user, Clicks: clicks[user.ID]})
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetShareReportRow) int {
+		return cmp.Or(cmp.Compare(b.Clicks, a.Clicks), cmp.Compare(a.Users.ID, b.Users.ID))
+	})
+	return rows, nil
+}
+
+func (s *Store) deleteShareClicks(userID int64) {
+	for key := range s.shareClicks {
+		if key.userID == userID {
+			delete(s.shareClicks, key)
+		}
+	}
+}
+
+func (s *Store) CreateEntryPurchase(ctx context.Context, arg *sqlc.CreateEntryPurchaseParams) (*sqlc.EntryPurchases, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[arg.UserID]; !ok {
+		return &sqlc.EntryPurchases{}, &pq.Error{Code: "23503", Message: "insert or update on table \"entry_purchases\" violates foreign key constraint \"entry_purchases_user_id_fkey\""}
+	}
+	for _, purchase := range s.purchases {
+		if purchase.OrderID == arg.OrderID {
+			return &sqlc.EntryPurchases{}, uniqueViolation("entry_purchases_order_id_key")
+		}
+	}
+
+	purchase := sqlc.EntryPurchases{
+		ID:        s.id(),
+		OrderID:   arg.OrderID,
+		UserID:    sql.NullInt64{Int64: arg.UserID, Valid: true},
+		EventID:   arg.EventID,
+		Entries:   arg.Entries,
+		Amount:    arg.Amount,
+		Status:    "pending",
+		CreatedAt: time.Now(),
+	}
+	s.purchases[purchase.ID] = purchase
+	return &purchase, nil
+}
+
+func (s *Store) CountReservedPaidEntries(ctx context.Context, arg *sqlc.CountReservedPaidEntriesParams) (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since := time.Now().Add(-time.Duration(arg.PendingSeconds) * time.Second)
+	var entries int32
+	for _, purchase := range s.purchases {
+		if !purchase.UserID.Valid || purchase.UserID.Int64 != arg.UserID {
+			continue
+		}
+		if purchase.Status == "paid" || (purchase.Status == "pending" && purchase.CreatedAt.After(since)) {
+			entries += purchase.Entries
+		}
+	}
+	return entries, nil
+}
+
+func (s *Store) MarkEntryPurchasePaid(ctx context.Context, arg *sqlc.MarkEntryPurchasePaidParams) (*sqlc.EntryPurchases, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, purchase := range s.purchases {
+		if purchase.OrderID == arg.OrderID && purchase.Status == "pending" {
+			purchase.Status = "paid"
+			purchase.PaidAt = now()
+			purchase.PaymentID = arg.PaymentID
+			s.purchases[id] = purchase
+			return &purchase, nil
+		}
+	}
+	return &sqlc.EntryPurchases{}, sql.ErrNoRows
+}
+
+func (s *Store) MarkEntryPurchaseFailed(ctx context.Context, orderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, purchase := range s.purchases {
+		if purchase.OrderID == orderID && purchase.Status == "pending" {
+			purchase.Status = "failed"
+			s.purchases[id] = purchase
+		}
+	}
+	return nil
+}
+
+func (s *Store) MarkEntryPurchaseRefunded(ctx context.Context, orderID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, purchase := range s.purchases {
+		if purchase.OrderID == orderID && purchase.Status == "paid" {
+			purchase.Status = "refunded"
+			purchase.RefundedAt = now()
+			s.purchases[id] = purchase
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// detachPurchases mirrors ON DELETE SET NULL on entry_purchases.user_id.
+func (s *Store) detachPurchases(userID int64) {
+	for id, purchase := range s.purchases {
+		if purchase.UserID.Valid && purchase.UserID.Int64 == userID {
+			purchase.UserID = sql.NullInt64{}
+			s.purchases[id] = purchase
+		}
+	}
+}
+
+func (s *Store) CreateTicketOrder(ctx context.Context, arg *sqlc.CreateTicketOrderParams) (*sqlc.TicketOrders, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &sqlc.TicketOrders{}, &pq.Error{Code: "23503", Message: "insert or update on table \"ticket_orders\" violates foreign key constraint \"ticket_orders_event_id_fkey\""}
+	}
+	for _, order := range s.ticketOrders {
+		if order.OrderID == arg.OrderID {
+			return &sqlc.TicketOrders{}, uniqueViolation("ticket_orders_order_id_key")
+		}
+	}
+
+	order := sqlc.TicketOrders{
+		ID:           s.id(),
+		OrderID:      arg.OrderID,
+		EventID:      arg.EventID,
+		Name:         arg.Name,
+		Username:     arg.Username,
+		Amount:       arg.Amount,
+		Status:       "pending",
+		CreatedAt:    time.Now(),
+		TicketTypeID: arg.TicketTypeID,
+	}
+	s.ticketOrders[order.ID] = order
+	return &order, nil
+}
+
+func (s *Store) GetTicketOrder(ctx context.Context, orderID string) (*sqlc.TicketOrders, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, order := range s.ticketOrders {
+		if order.OrderID == orderID {
+			return &order, nil
+		}
+	}
+	return &sqlc.TicketOrders{}, sql.ErrNoRows
+}
+
+func (s *Store) MarkTicketOrderPaid(ctx context.Context, arg *sqlc.MarkTicketOrderPaidParams) (*sqlc.TicketOrders, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, order := range s.ticketOrders {
+		if order.OrderID == arg.OrderID && order.Status == "pending" {
+			order.Status = "paid"
+			order.PaidAt = now()
+			order.PaymentID = arg.PaymentID
+			s.ticketOrders[id] = order
+			return &order, nil
+		}
+	}
+	return &sqlc.TicketOrders{}, sql.ErrNoRows
+}
+
+func (s *Store) MarkTicketOrderFailed(ctx context.Context, orderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, order := range s.ticketOrders {
+		if order.OrderID == orderID && order.Status == "pending" {
+			order.Status = "failed"
+			s.ticketOrders[id] = order
+		}
+	}
+	return nil
+}
+
+func (s *Store) MarkTicketOrderRefunded(ctx context.Context, orderID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, order := range s.ticketOrders {
+		if order.OrderID == orderID && order.Status == "paid" {
+			order.Status = "refunded"
+			order.RefundedAt = now()
+			s.ticketOrders[id] = order
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (s *Store) SetTicketOrderUser(ctx context.Context, arg *sqlc.SetTicketOrderUserParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.ticketOrders[arg.ID]
+	if !ok {
+		return nil
+	}
+	order.UserID = sql.NullInt64{Int64: arg.UserID, Valid: true}
+	s.ticketOrders[order.ID] = order
+	return nil
+}
+
+func (s *Store) GetPaymentsByEventID(ctx context.Context, eventID int64) ([]*sqlc.GetPaymentsByEventIDRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticketNumber := func(userID sql.NullInt64) sql.NullInt32 {
+		user, ok := s.users[userID.Int64]
+		if !userID.Valid || !ok {
+			return sql.NullInt32{}
+		}
+		return sql.NullInt32{Int32: user.TicketNumber, Valid: true}
+	}
+
+	var payments []*sqlc.GetPaymentsByEventIDRow
+	for _, order := range s.ticketOrders {
+		if order.EventID != eventID {
+			continue
+		}
+		payments = append(payments, &sqlc.GetPaymentsByEventIDRow{
+			Kind:         "ticket",
+			OrderID:      order.OrderID,
+			PaymentID:    order.PaymentID,
+			UserID:       order.UserID,
+			Name:         order.Name,
+			TicketNumber: ticketNumber(order.UserID),
+			Amount:       order.Amount,
+			Status:       order.Status,
+			CreatedAt:    order.CreatedAt,
+			PaidAt:       order.PaidAt,
+			RefundedAt:   order.RefundedAt,
+		})
+	}
+	for _, purchase := range s.purchases {
+		if purchase.EventID != eventID {
+			continue
+		}
+		payments = append(payments, &sqlc.GetPaymentsByEventIDRow{
+			Kind:         "entries",
+			OrderID:      purchase.OrderID,
+			PaymentID:    purchase.PaymentID,
+			UserID:       purchase.UserID,
+			Name:         s.users[purchase.UserID.Int64].Name,
+			TicketNumber: ticketNumber(purchase.UserID),
+			Entries:      purchase.Entries,
+			Amount:       purchase.Amount,
+			Status:       purchase.Status,
+			CreatedAt:    purchase.CreatedAt,
+			PaidAt:       purchase.PaidAt,
+			RefundedAt:   purchase.RefundedAt,
+		})
+	}
+	slices.SortFunc(payments, func(a, b *sqlc.GetPaymentsByEventIDRow) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return payments, nil
+}
+
+// detachTicketOrders mirrors ON DELETE SET NULL on ticket_orders.user_id.
+func (s *Store) detachTicketOrders(userID int64) {
+	for id, order := range s.ticketOrders {
+		if order.UserID.Valid && order.UserID.Int64 == userID {
+			order.UserID = sql.NullInt64{}
+			s.ticketOrders[id] = order
+		}
+	}
+}
+
+func (s *Store) CreatePromoCode(ctx context.Context, arg *sqlc.CreatePromoCodeParams) (*sqlc.PromoCodes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &sqlc.PromoCodes{}, &pq.Error{Code: "23503", Message: "insert or update on table \"promo_codes\" violates foreign key constraint \"promo_codes_event_id_fkey\""}
+	}
+	for _, code := range s.promoCodes {
+		if code.EventID == arg.EventID && code.Code == arg.Code {
+			return &sqlc.PromoCodes{}, uniqueViolation("promo_codes_event_id_code_key")
+		}
+	}
+
+	code := sqlc.PromoCodes{
+		ID:        s.id(),
+		EventID:   arg.EventID,
+		Code:      arg.Code,
+		Kind:      arg.Kind,
+		Value:     arg.Value,
+		MaxUses:   arg.MaxUses,
+		ExpiresAt: arg.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	s.promoCodes[code.ID] = code
+	return &code, nil
+}
+
+func (s *Store) DeletePromoCode(ctx context.Context, arg *sqlc.DeletePromoCodeParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if code, ok := s.promoCodes[arg.ID]; ok && code.EventID == arg.EventID {
+		delete(s.promoCodes, arg.ID)
+		for id, redemption := range s.redemptions {
+			if redemption.PromoCodeID == arg.ID {
+				delete(s.redemptions, id)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetPromoCode(ctx context.Context, arg *sqlc.GetPromoCodeParams) (*sqlc.PromoCodes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, code := range s.promoCodes {
+		if code.EventID == arg.EventID && code.Code == arg.Code {
+			return &code, nil
+		}
+	}
+	return &sqlc.PromoCodes{}, sql.ErrNoRows
+}
+
+func (s *Store) UsePromoCode(ctx context.Context, arg *sqlc.UsePromoCodeParams) (*sqlc.PromoCodes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, code := range s.promoCodes {
+		if code.EventID != arg.EventID || code.Code != arg.Code {
+			continue
+		}
+		if code.MaxUses > 0 && code.Uses >= code.MaxUses {
+			break
+		}
+		if code.ExpiresAt.Valid && !code.ExpiresAt.Time.After(time.Now()) {
+			break
+		}
+		code.Uses++
+		s.promoCodes[id] = code
+		return &code, nil
+	}
+	return &sqlc.PromoCodes{}, sql.ErrNoRows
+}
+
+func (s *Store) CreatePromoRedemption(ctx context.Context, arg *sqlc.CreatePromoRedemptionParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.promoCodes[arg.PromoCodeID]; !ok {
+		return &pq.Error{Code: "23503", Message: "insert or update on table \"promo_redemptions\" violates foreign key constraint \"promo_redemptions_promo_code_id_fkey\""}
+	}
+
+	redemption := sqlc.PromoRedemptions{
+		ID:          s.id(),
+		PromoCodeID: arg.PromoCodeID,
+		UserID:      arg.UserID,
+		OrderID:     arg.OrderID,
+		Discount:    arg.Discount,
+		Entries:     arg.Entries,
+		CreatedAt:   time.Now(),
+	}
+	s.redemptions[redemption.ID] = redemption
+	return nil
+}
+
+func (s *Store) SetPromoRedemptionUser(ctx context.Context, arg *sqlc.SetPromoRedemptionUserParams) (*sqlc.PromoRedemptions, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, redemption := range s.redemptions {
+		if redemption.OrderID.Valid && redemption.OrderID.String == arg.OrderID {
+			redemption.UserID = sql.NullInt64{Int64: arg.UserID, Valid: true}
+			s.redemptions[id] = redemption
+			return &redemption, nil
+		}
+	}
+	return &sqlc.PromoRedemptions{}, sql.ErrNoRows
+}
+
+func (s *Store) ReleasePromoRedemption(ctx context.Context, orderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	failed := false
+	for _, order := range s.ticketOrders {
+		if order.OrderID == orderID && order.Status == "failed" {
+			failed = true
+		}
+	}
+	if !failed {
+		return nil
+	}
+	for id, redemption := range s.redemptions {
+		if !redemption.OrderID.Valid || redemption.OrderID.String != orderID {
+			continue
+		}
+		delete(s.redemptions, id)
+		if code, ok := s.promoCodes[redemption.PromoCodeID]; ok {
+			code.Uses--
+			s.promoCodes[code.ID] = code
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetPromoCodeStats(ctx context.Context, eventID int64) ([]*sqlc.GetPromoCodeStatsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []*sqlc.GetPromoCodeStatsRow
+	for _, code := range s.promoCodes {
+		if code.EventID != eventID {
+			continue
+		}
+		row := &sqlc.GetPromoCodeStatsRow{PromoCodes: code}
+		for _, redemption := range s.redemptions {
+			if redemption.PromoCodeID != code.ID {
+				continue
+			}
+			if redemption.UserID.Valid {
+				row.Participants++
+			}
+			row.Discount += redemption.Discount
+			row.Entries += redemption.Entries
+			for _, order := range s.ticketOrders {
+				if redemption.OrderID.Valid && order.OrderID == redemption.OrderID.String && order.Status == "paid" {
+					row.Revenue += order.Amount
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetPromoCodeStatsRow) int {
+		return cmp.Or(b.PromoCodes.CreatedAt.Compare(a.PromoCodes.CreatedAt), cmp.Compare(b.PromoCodes.ID, a.PromoCodes.ID))
+	})
+	return rows, nil
+}
+
+func (s *Store) CreateShortLink(ctx context.Context, arg *sqlc.CreateShortLinkParams) (*sqlc.ShortLinks, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &sqlc.ShortLinks{}, &pq.Error{Code: "23503", Message: "insert or update on table \"short_links\" violates foreign key constraint \"short_links_event_id_fkey\""}
+	}
+	for _, link := range s.shortLinks {
+		if link.Code == arg.Code {
+			return &sqlc.ShortLinks{}, uniqueViolation("short_links_code_key")
+		}
+	}
+
+	link := sqlc.ShortLinks{
+		ID:        s.id(),
+		EventID:   arg.EventID,
+		Code:      arg.Code,
+		Channel:   arg.Channel,
+		Target:    arg.Target,
+		CreatedAt: time.Now(),
+	}
+	s.shortLinks[link.ID] = link
+	return &link, nil
+}
+
+func (s *Store) DeleteShortLink(ctx context.Context, arg *sqlc.DeleteShortLinkParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if link, ok := s.shortLinks[arg.ID]; ok && link.EventID == arg.EventID {
+		delete(s.shortLinks, arg.ID)
+	}
+	return nil
+}
+
+func (s *Store) ClickShortLink(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if link, ok := s.shortLinks[id]; ok {
+		link.Clicks++
+		s.shortLinks[id] = link
+	}
+	return nil
+}
+
+func (s *Store) GetShortLinkByCode(ctx context.Context, code string) (*sqlc.ShortLinks, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, link := range s.shortLinks {
+		if link.Code == code {
+			return &link, nil
+		}
+	}
+	return &sqlc.ShortLinks{}, sql.ErrNoRows
+}
+
+func (s *Store) GetShortLinksByEventID(ctx context.Context, eventID int64) ([]*sqlc.ShortLinks, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var links []*sqlc.ShortLinks
+	for _, link := range s.shortLinks {
+		if link.EventID == eventID {
+			links = append(links, &link)
+		}
+	}
+	slices.SortFunc(links, func(a, b *sqlc.ShortLinks) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return links, nil
+}
+
+// detachRedemptions mirrors ON DELETE SET NULL on promo_redemptions.user_id.
+func (s *Store) detachRedemptions(userID int64) {
+	for id, redemption := range s.redemptions {
+		if redemption.UserID.Valid && redemption.UserID.Int64 == userID {
+			redemption.UserID = sql.NullInt64{}
+			s.redemptions[id] = redemption
+		}
+	}
+}
+
+func (s *Store) CreateTicketType(ctx context.Context, arg *sqlc.CreateTicketTypeParams) (*sqlc.TicketTypes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &sqlc.TicketTypes{}, &pq.Error{Code: "23503", Message: "insert or update on table \"ticket_types\" violates foreign key constraint \"ticket_types_event_id_fkey\""}
+	}
+	for _, ticketType := range s.ticketTypes {
+		if ticketType.EventID == arg.EventID && ticketType.Name == arg.Name {
+			return &sqlc.TicketTypes{}, uniqueViolation("ticket_types_event_id_name_key")
+		}
+	}
+
+	ticketType := sqlc.TicketTypes{
+		ID:        s.id(),
+		EventID:   arg.EventID,
+		Name:      arg.Name,
+		Price:     arg.Price,
+		Capacity:  arg.Capacity,
+		Weight:    arg.Weight,
+		CreatedAt: time.Now(),
+	}
+	s.ticketTypes[ticketType.ID] = ticketType
+	return &ticketType, nil
+}
+
+func (s *Store) DeleteTicketType(ctx context.Context, arg *sqlc.DeleteTicketTypeParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticketType, ok := s.ticketTypes[arg.ID]
+	if !ok || ticketType.EventID != arg.EventID {
+		return nil
+	}
+	delete(s.ticketTypes, arg.ID)
+	// ON DELETE SET NULL on users, ticket_orders and waitlist
+	for id, user := range s.users {
+		if user.TicketTypeID.Valid && user.TicketTypeID.Int64 == arg.ID {
+			user.TicketTypeID = sql.NullInt64{}
+			s.users[id] = user
+		}
+	}
+	for id, order := range s.ticketOrders {
+		if order.TicketTypeID.Valid && order.TicketTypeID.Int64 == arg.ID {
+			order.TicketTypeID = sql.NullInt64{}
+			s.ticketOrders[id] = order
+		}
+	}
+	for id, entry := range s.waitlist {
+		if entry.TicketTypeID.Valid && entry.TicketTypeID.Int64 == arg.ID {
+			entry.TicketTypeID = sql.NullInt64{}
+			s.waitlist[id] = entry
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetTicketType(ctx context.Context, arg *sqlc.GetTicketTypeParams) (*sqlc.TicketTypes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticketType, ok := s.ticketTypes[arg.ID]
+	if !ok || ticketType.EventID != arg.EventID {
+		return &sqlc.TicketTypes{}, sql.ErrNoRows
+	}
+	return &ticketType, nil
+}
+
+// GetTicketTypeForUpdate needs no lock, since transactions already run one
+// at a time.
+func (s *Store) GetTicketTypeForUpdate(ctx context.Context, arg *sqlc.GetTicketTypeForUpdateParams) (*sqlc.TicketTypes, error) {
+	return s.GetTicketType(ctx, &sqlc.GetTicketTypeParams{ID: arg.ID, EventID: arg.EventID})
+}
+
+func (s *Store) GetTicketTypesByEventID(ctx context.Context, eventID int64) ([]*sqlc.TicketTypes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ticketTypes []*sqlc.TicketTypes
+	for _, ticketType := range s.ticketTypes {
+		if ticketType.EventID == eventID {
+			ticketTypes = append(ticketTypes, &ticketType)
+		}
+	}
+	slices.SortFunc(ticketTypes, func(a, b *sqlc.TicketTypes) int {
+		return cmp.Or(cmp.Compare(a.Price, b.Price), cmp.Compare(a.ID, b.ID))
+	})
+	return ticketTypes, nil
+}
+
+func (s *Store) CountTicketTypeTaken(ctx context.Context, arg *sqlc.CountTicketTypeTakenParams) (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var taken int32
+	for _, user := range s.users {
+		if user.TicketTypeID.Valid && user.TicketTypeID.Int64 == arg.ID && !user.DeletedAt.Valid {
+			taken++
+		}
+	}
+	since := time.Now().Add(-time.Duration(arg.PendingSeconds) * time.Second)
+	for _, order := range s.ticketOrders {
+		if order.TicketTypeID.Valid && order.TicketTypeID.Int64 == arg.ID && order.Status == "pending" && order.CreatedAt.After(since) {
+			taken++
+		}
+	}
+	return taken, nil
+}
+
+func (s *Store) GetTicketTypeStats(ctx context.Context, eventID int64) ([]*sqlc.GetTicketTypeStatsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []*sqlc.GetTicketTypeStatsRow
+	for _, ticketType := range s.ticketTypes {
+		if ticketType.EventID != eventID {
+			continue
+		}
+		row := &sqlc.GetTicketTypeStatsRow{TicketTypes: ticketType}
+		for _, user := range s.users {
+			if !user.TicketTypeID.Valid || user.TicketTypeID.Int64 != ticketType.ID || user.DeletedAt.Valid {
+				continue
+			}
+			row.Participants++
+			if user.CheckedInAt.Valid {
+				row.CheckedIn++
+			}
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetTicketTypeStatsRow) int {
+		return cmp.Or(cmp.Compare(a.TicketTypes.Price, b.TicketTypes.Price), cmp.Compare(a.TicketTypes.ID, b.TicketTypes.ID))
+	})
+	return rows, nil
+}
+
+func (s *Store) CreateSeats(ctx context.Context, arg *sqlc.CreateSeatsParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return 0, &pq.Error{Code: "23503", Message: "insert or update on table \"seats\" violates foreign key constraint \"seats_event_id_fkey\""}
+	}
+	labels := make(map[string]bool)
+	for _, seat := range s.seats {
+		if seat.EventID == arg.EventID {
+			labels[seat.Label] = true
+		}
+	}
+
+	var created int64
+	for _, label := range arg.Labels {
+		if labels[label] {
+			continue
+		}
+		labels[label] = true
+		seat := sqlc.Seats{
+			ID:        s.id(),
+			EventID:   arg.EventID,
+			Label:     label,
+			CreatedAt: time.Now(),
+		}
+		s.seats[seat.ID] = seat
+		created++
+	}
+	return created, nil
+}
+
+func (s *Store) DeleteSeat(ctx context.Context, arg *sqlc.DeleteSeatParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if seat, ok := s.seats[arg.ID]; ok && seat.EventID == arg.EventID {
+		delete(s.seats, arg.ID)
+	}
+	return nil
+}
+
+func (s *Store) GetSeatsByEventID(ctx context.Context, eventID int64) ([]*sqlc.GetSeatsByEventIDRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []*sqlc.GetSeatsByEventIDRow
+	for _, seat := range s.seats {
+		if seat.EventID != eventID {
+			continue
+		}
+		row := &sqlc.GetSeatsByEventIDRow{Seats: seat}
+		if user, ok := s.users[seat.UserID.Int64]; ok && seat.UserID.Valid {
+			row.UserName = user.Name
+			row.TicketNumber = user.TicketNumber
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetSeatsByEventIDRow) int {
+		return cmp.Compare(a.Seats.ID, b.Seats.ID)
+	})
+	return rows, nil
+}
+
+func (s *Store) GetSeatByUserID(ctx context.Context, userID int64) (*sqlc.Seats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, seat := range s.seats {
+		if seat.UserID.Valid && seat.UserID.Int64 == userID {
+			return &seat, nil
+		}
+	}
+	return &sqlc.Seats{}, sql.ErrNoRows
+}
+
+// GetSeatByLabelForUpdate needs no lock, since transactions already run
+// one at a time.
+func (s *Store) GetSeatByLabelForUpdate(ctx context.Context, arg *sqlc.GetSeatByLabelForUpdateParams) (*sqlc.Seats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, seat := range s.seats {
+		if seat.EventID == arg.EventID && seat.Label == arg.Label {
+			return &seat, nil
+		}
+	}
+	return &sqlc.Seats{}, sql.ErrNoRows
+}
+
+func (s *Store) AssignFreeSeat(ctx context.Context, arg *sqlc.AssignFreeSeatParams) (*sqlc.Seats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var free *sqlc.Seats
+	for _, seat := range s.seats {
+		if seat.UserID.Valid && seat.UserID.Int64 == arg.UserID {
+			return &sqlc.Seats{}, sql.ErrNoRows
+		}
+		if seat.EventID == arg.EventID && !seat.UserID.Valid && (free == nil || seat.ID < free.ID) {
+			free = &seat
+		}
+	}
+	if free == nil {
+		return &sqlc.Seats{}, sql.ErrNoRows
+	}
+	free.UserID = sql.NullInt64{Int64: arg.UserID, Valid: true}
+	s.seats[free.ID] = *free
+	return free, nil
+}
+
+func (s *Store) SetSeatUser(ctx context.Context, arg *sqlc.SetSeatUserParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seat, ok := s.seats[arg.ID]
+	if !ok {
+		return nil
+	}
+	if arg.UserID.Valid {
+		if _, ok := s.users[arg.UserID.Int64]; !ok {
+			return &pq.Error{Code: "23503", Message: "insert or update on table \"seats\" violates foreign key constraint \"seats_user_id_fkey\""}
+		}
+		for _, other := range s.seats {
+			if other.ID != seat.ID && other.UserID == arg.UserID {
+				return uniqueViolation("seats_user_id_key")
+			}
+		}
+	}
+	seat.UserID = arg.UserID
+	s.seats[seat.ID] = seat
+	return nil
+}
+
+func (s *Store) ReleaseSeat(ctx context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.detachSeats(userID)
+	return nil
+}
+
+// detachSeats frees the participant's seat, like ON DELETE SET NULL.
+func (s *Store) detachSeats(userID int64) {
+	for id, seat := range s.seats {
+		if seat.UserID.Valid && seat.UserID.Int64 == userID {
+			seat.UserID = sql.NullInt64{}
+			s.seats[id] = seat
+		}
+	}
+}
+
+func (s *Store) ClaimIdempotencyKey(ctx context.Context, arg *sqlc.ClaimIdempotencyKeyParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := idempotencyKey{arg.Scope, arg.Key}
+	if entry, ok := s.idempotency[k]; ok && entry.CreatedAt.After(arg.StoredBefore) &&
+		(entry.StatusCode != 0 || entry.CreatedAt.After(arg.ClaimedBefore)) {
+		return 0, nil
+	}
+	s.idempotency[k] = sqlc.IdempotencyKeys{
+		Key:         arg.Key,
+		Scope:       arg.Scope,
+		RequestHash: arg.RequestHash,
+		Body:        []byte{},
+		CreatedAt:   time.Now(),
+	}
+	return 1, nil
+}
+
+func (s *Store) ReleaseIdempotencyKey(ctx context.Context, arg *sqlc.ReleaseIdempotencyKeyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := idempotencyKey{arg.Scope, arg.Key}
+	if entry, ok := s.idempotency[k]; ok && entry.StatusCode == 0 {
+		delete(s.idempotency, k)
+	}
+	return nil
+}
+
+func (s *Store) GetIdempotencyKey(ctx context.Context, arg *sqlc.GetIdempotencyKeyParams) (*sqlc.IdempotencyKeys, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.idempotency[idempotencyKey{arg.Scope, arg.Key}]
+	if !ok || !entry.CreatedAt.After(arg.Since) {
+		return &sqlc.IdempotencyKeys{}, sql.ErrNoRows
+	}
+	return &entry, nil
+}
+
+func (s *Store) SaveIdempotencyKey(ctx context.Context, arg *sqlc.SaveIdempotencyKeyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.idempotency[idempotencyKey{arg.Scope, arg.Key}] = sqlc.IdempotencyKeys{
+		Key:         arg.Key,
+		Scope:       arg.Scope,
+		RequestHash: arg.RequestHash,
+		StatusCode:  arg.StatusCode,
+		ContentType: arg.ContentType,
+		Body:        slices.Clone(arg.Body),
+		CreatedAt:   time.Now(),
+	}
+	return nil
+}
+
+func (s *Store) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.idempotency)
+	maps.DeleteFunc(s.idempotency, func(_ idempotencyKey, entry sqlc.IdempotencyKeys) bool {
+		return !entry.CreatedAt.After(before)
+	})
+	return int64(n - len(s.idempotency)), nil
+}
+
+func (s *Store) CreateEventTemplate(ctx context.Context, arg *sqlc.CreateEventTemplateParams) (*sqlc.EventTemplates, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tmpl := range s.templates {
+		if tmpl.Name == arg.Name {
+			return &sqlc.EventTemplates{}, uniqueViolation("event_templates_name_key")
+		}
+	}
+	tmpl := sqlc.EventTemplates{
+		ID:                  s.id(),
+		Name:                arg.Name,
+		Description:         arg.Description,
+		WaitlistAutoPromote: arg.WaitlistAutoPromote,
+		MaxPaidEntries:      arg.MaxPaidEntries,
+		EntryPrice:          arg.EntryPrice,
+		ShareClicksRequired: arg.ShareClicksRequired,
+		ShareBonus:          arg.ShareBonus,
+		CreatedAt:           time.Now(),
+	}
+	s.templates[tmpl.ID] = tmpl
+	return &tmpl, nil
+}
+
+func (s *Store) CreateEventTemplateRule(ctx context.Context, arg *sqlc.CreateEventTemplateRuleParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.templates[arg.TemplateID]; !ok {
+		return &pq.Error{Code: "23503", Message: "insert or update on table \"event_template_rules\" violates foreign key constraint \"event_template_rules_template_id_fkey\""}
+	}
+	if !arg.MaxTicket.Valid && !arg.SecondsBeforeEvent.Valid {
+		return &pq.Error{Code: "23514", Message: "new row for relation \"event_template_rules\" violates check constraint \"event_template_rules_condition\""}
+	}
+	rule := sqlc.EventTemplateRules{
+		ID:                 s.id(),
+		TemplateID:         arg.TemplateID,
+		Name:               arg.Name,
+		MaxTicket:          arg.MaxTicket,
+		SecondsBeforeEvent: arg.SecondsBeforeEvent,
+		Bonus:              arg.Bonus,
+	}
+	s.tmplRules[rule.ID] = rule
+	return nil
+}
+
+func (s *Store) GetEventTemplates(ctx context.Context) ([]*sqlc.GetEventTemplatesRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules := make(map[int64]int32)
+	for _, rule := range s.tmplRules {
+		rules[rule.TemplateID]++
+	}
+
+	rows := make([]*sqlc.GetEventTemplatesRow, 0, len(s.templates))
+	for _, tmpl := range s.templates {
+		rows = append(rows, &sqlc.GetEventTemplatesRow{EventTemplates: tmpl, Rules: rules[tmpl.ID]})
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetEventTemplatesRow) int {
+		return strings.Compare(a.EventTemplates.Name, b.EventTemplates.Name)
+	})
+	return rows, nil
+}
+
+func (s *Store) GetEventTemplate(ctx context.Context, id int64) (*sqlc.EventTemplates, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmpl, ok := s.templates[id]
+	if !ok {
+		return &sqlc.EventTemplates{}, sql.ErrNoRows
+	}
+	return &tmpl, nil
+}
+
+func (s *Store) GetEventTemplateRules(ctx context.Context, templateID int64) ([]*sqlc.EventTemplateRules, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules := make([]*sqlc.EventTemplateRules, 0)
+	for _, rule := range s.tmplRules {
+		if rule.TemplateID == templateID {
+			rules = append(rules, &rule)
+		}
+	}
+	slices.SortFunc(rules, func(a, b *sqlc.EventTemplateRules) int { return cmp.Compare(a.ID, b.ID) })
+	return rules, nil
+}
+
+func (s *Store) UpdateEventTemplate(ctx context.Context, arg *sqlc.UpdateEventTemplateParams) (*sqlc.EventTemplates, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmpl, ok := s.templates[arg.ID]
+	if !ok {
+		return &sqlc.EventTemplates{}, sql.ErrNoRows
+	}
+	for _, other := range s.templates {
+		if other.ID != arg.ID && other.Name == arg.Name {
+			return &sqlc.EventTemplates{}, uniqueViolation("event_templates_name_key")
+		}
+	}
+	tmpl.Name = arg.Name
+	tmpl.Description = arg.Description
+	s.templates[arg.ID] = tmpl
+	return &tmpl, nil
+}
+
+func (s *Store) DeleteEventTemplate(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.templates, id)
+	for ruleID, rule := range s.tmplRules {
+		if rule.TemplateID == id {
+			delete(s.tmplRules, ruleID)
+		}
+	}
+	return nil
+}
+
+func (s *Store) CreateDigestSubscription(ctx context.Context, arg *sqlc.CreateDigestSubscriptionParams) (*sqlc.DigestSubscriptions, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range s.digests {
+		if sub.ChatID == arg.ChatID {
+			return &sqlc.DigestSubscriptions{}, uniqueViolation("digest_subscriptions_chat_id_key")
+		}
+	}
+	sub := sqlc.DigestSubscriptions{
+		ID:               s.id(),
+		Name:             arg.Name,
+		ChatID:           arg.ChatID,
+		Weekday:          arg.Weekday,
+		Hour:             arg.Hour,
+		IncludeAnomalies: arg.IncludeAnomalies,
+		CreatedAt:        time.Now(),
+		WeeklyDigest:     true,
+		NotifyKinds:      []string{},
+		NotifyEventIds:   []int64{},
+	}
+	s.digests[sub.ID] = sub
+	return &sub, nil
+}
+
+func (s *Store) GetDigestSubscriptions(ctx context.Context) ([]*sqlc.DigestSubscriptions, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := make([]*sqlc.DigestSubscriptions, 0, len(s.digests))
+	for _, sub := range s.digests {
+		subs = append(subs, &sub)
+	}
+	slices.SortFunc(subs, func(a, b *sqlc.DigestSubscriptions) int { return cmp.Compare(a.ID, b.ID) })
+	return subs, nil
+}
+
+func (s *Store) GetDigestSubscription(ctx context.Context, id int64) (*sqlc.DigestSubscriptions, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.digests[id]
+	if !ok {
+		return &sqlc.DigestSubscriptions{}, sql.ErrNoRows
+	}
+	return &sub, nil
+}
+
+func (s *Store) PruneNotifyEventIDs(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pruned int64
+	for id, sub := range s.digests {
+		existing := make([]int64, 0, len(sub.NotifyEventIds))
+		for _, eventID := range sub.NotifyEventIds {
+			if _, ok := s.events[eventID]; ok {
+				existing = append(existing, eventID)
+			}
+		}
+		if len(existing) == len(sub.NotifyEventIds) || len(existing) == 0 {
+			continue
+		}
+		slices.Sort(existing)
+		sub.NotifyEventIds = existing
+		s.digests[id] = sub
+		pruned++
+	}
+	return pruned, nil
+}
+
+func (s *Store) DeleteDigestSubscription(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.digests, id)
+	return nil
+}
+
+func (s *Store) MarkDigestSent(ctx context.Context, arg *sqlc.MarkDigestSentParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.digests[arg.ID]
+	if !ok || (sub.LastSentAt.Valid && !sub.LastSentAt.Time.Before(arg.SentBefore)) {
+		return 0, nil
+	}
+	sub.LastSentAt = now()
+	s.digests[arg.ID] = sub
+	return 1, nil
+}
+
+func (s *Store) UpdateNotificationPreferences(ctx context.Context, arg *sqlc.UpdateNotificationPreferencesParams) (*sqlc.DigestSubscriptions, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.digests[arg.ID]
+	if !ok {
+		return &sqlc.DigestSubscriptions{}, sql.ErrNoRows
+	}
+	for _, other := range s.digests {
+		if other.ID != arg.ID && other.ChatID == arg.ChatID {
+			return &sqlc.DigestSubscriptions{}, uniqueViolation("digest_subscriptions_chat_id_key")
+		}
+	}
+	sub.ChatID = arg.ChatID
+	sub.Weekday = arg.Weekday
+	sub.Hour = arg.Hour
+	sub.WeeklyDigest = arg.WeeklyDigest
+	sub.IncludeAnomalies = arg.IncludeAnomalies
+	sub.NotifyKinds = slices.Clone(arg.NotifyKinds)
+	sub.NotifyEventIds = slices.Clone(arg.NotifyEventIds)
+	s.digests[arg.ID] = sub
+	return &sub, nil
+}
+
+func (s *Store) GetNotificationRecipients(ctx context.Context, arg *sqlc.GetNotificationRecipientsParams) ([]*sqlc.DigestSubscriptions, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := make([]*sqlc.DigestSubscriptions, 0)
+	for _, sub := range s.digests {
+		if !slices.Contains(sub.NotifyKinds, arg.Kind) {
+			continue
+		}
+		if arg.EventID == 0 || len(sub.NotifyEventIds) == 0 || slices.Contains(sub.NotifyEventIds, arg.EventID) {
+			subs = append(subs, &sub)
+		}
+	}
+	slices.SortFunc(subs, func(a, b *sqlc.DigestSubscriptions) int { return cmp.Compare(a.ID, b.ID) })
+	return subs, nil
+}
+
+func (s *Store) GetRegistrationsSince(ctx context.Context, since time.Time) ([]*sqlc.GetRegistrationsSinceRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[int64]int32)
+	for _, user := range s.users {
+		if !user.CreatedAt.Time.Before(since) && !user.DeletedAt.Valid && !s.events[user.EventID].DeletedAt.Valid {
+			counts[user.EventID]++
+		}
+	}
+
+	rows := make([]*sqlc.GetRegistrationsSinceRow, 0, len(counts))
+	for eventID, n := range counts {
+		rows = append(rows, &sqlc.GetRegistrationsSinceRow{ID: eventID, Name: s.events[eventID].Name, Registrations: n})
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetRegistrationsSinceRow) int {
+		return cmp.Or(cmp.Compare(b.Registrations, a.Registrations), cmp.Compare(a.ID, b.ID))
+	})
+	return rows, nil
+}
+
+func (s *Store) GetEventsBetween(ctx context.Context, arg *sqlc.GetEventsBetweenParams) ([]*sqlc.Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]*sqlc.Events, 0)
+	for _, event := range s.events {
+		if !event.Date.Before(arg.FromDate) && event.Date.Before(arg.ToDate) && !event.DeletedAt.Valid {
+			events = append(events, &event)
+		}
+	}
+	slices.SortFunc(events, func(a, b *sqlc.Events) int { return a.Date.Compare(b.Date) })
+	return events, nil
+}
+
+func (s *Store) GetDrawsSince(ctx context.Context, since time.Time) ([]*sqlc.GetDrawsSinceRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := make([]*sqlc.GetDrawsSinceRow, 0)
+	for _, draw := range s.draws {
+		if draw.CreatedAt.Time.Before(since) || s.events[draw.EventID].DeletedAt.Valid {
+			continue
+		}
+		rows = append(rows, &sqlc.GetDrawsSinceRow{
+			ID:           draw.ID,
+			EventID:      draw.EventID,
+			WinnersCount: draw.WinnersCount,
+			CreatedAt:    draw.CreatedAt,
+			Seed:         draw.Seed,
+			EventName:    s.events[draw.EventID].Name,
+		})
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetDrawsSinceRow) int { return a.CreatedAt.Time.Compare(b.CreatedAt.Time) })
+	return rows, nil
+}
+
+func (s *Store) GetAnomalyCounts(ctx context.Context, since time.Time) (*sqlc.GetAnomalyCountsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var counts sqlc.GetAnomalyCountsRow
+	for _, user := range s.users {
+		if user.FlagReason.Valid && !user.ReviewedAt.Valid && !user.DeletedAt.Valid {
+			counts.PendingReview++
+		}
+	}
+	for _, message := range s.outbox {
+		if message.FailedAt.Valid && !message.FailedAt.Time.Before(since) {
+			counts.FailedMessages++
+		}
+	}
+	for _, purchase := range s.purchases {
+		if purchase.Status == "failed" && !purchase.CreatedAt.Before(since) {
+			counts.FailedPayments++
+		}
+	}
+	for _, order := range s.ticketOrders {
+		if order.Status == "failed" && !order.CreatedAt.Before(since) {
+			counts.FailedPayments++
+		}
+	}
+	return &counts, nil
+}
+
+func (s *Store) CreateAdmin(ctx context.Context, arg *sqlc.CreateAdminParams) (*sqlc.Admins, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, admin := range s.admins {
+		if admin.Username == arg.Username {
+			return &sqlc.Admins{}, uniqueViolation("admins_username_key")
+		}
+	}
+	admin := sqlc.Admins{
+		ID:                 s.id(),
+		Username:           arg.Username,
+		Role:               arg.Role,
+		ChatID:             arg.ChatID,
+		PasswordHash:       arg.PasswordHash,
+		MustChangePassword: arg.MustChangePassword,
+		CreatedAt:          time.Now(),
+	}
+	s.admins[admin.ID] = admin
+	return &admin, nil
+}
+
+func (s *Store) GetAdmins(ctx context.Context) ([]*sqlc.Admins, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	admins := make([]*sqlc.Admins, 0, len(s.admins))
+	for _, admin := range s.admins {
+		admins = append(admins, &admin)
+	}
+	slices.SortFunc(admins, func(a, b *sqlc.Admins) int { return strings.Compare(a.Username, b.Username) })
+	return admins, nil
+}
+
+func (s *Store) GetAdminByID(ctx context.Context, id int64) (*sqlc.Admins, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	admin, ok := s.admins[id]
+	if !ok {
+		return &sqlc.Admins{}, sql.ErrNoRows
+	}
+	return &admin, nil
+}
+
+func (s *Store) GetAdminByUsername(ctx context.Context, username string) (*sqlc.Admins, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, admin := range s.admins {
+		if admin.Username == username {
+			return &admin, nil
+		}
+	}
+	return &sqlc.Admins{}, sql.ErrNoRows
+}
+
+func (s *Store) SetAdminPassword(ctx context.Context, arg *sqlc.SetAdminPasswordParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	admin, ok := s.admins[arg.ID]
+	if !ok {
+		return nil
+	}
+	admin.PasswordHash = arg.PasswordHash
+	admin.MustChangePassword = false
+	s.admins[arg.ID] = admin
+	return nil
+}
+
+func (s *Store) DeleteAdmin(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.admins, id)
+	for nonce, token := range s.loginTokens {
+		if token.AdminID == id {
+			delete(s.loginTokens, nonce)
+		}
+	}
+	for tokenID, token := range s.tokens {
+		if token.AdminID == id {
+			delete(s.tokens, tokenID)
+		}
+	}
+	for sessionID, session := range s.sessions {
+		if session.AdminID.Valid && session.AdminID.Int64 == id {
+			delete(s.sessions, sessionID)
+		}
+	}
+	for organizerID, organizer := range s.organizers {
+		if organizer.AdminID.Valid && organizer.AdminID.Int64 == id {
+			organizer.AdminID = sql.NullInt64{}
+			s.organizers[organizerID] = organizer
+		}
+	}
+	return nil
+}
+
+func (s *Store) CreateAdminLoginToken(ctx context.Context, arg *sqlc.CreateAdminLoginTokenParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.admins[arg.AdminID]; !ok {
+		return &pq.Error{Code: "23503", Message: "insert or update on table \"admin_login_tokens\" violates foreign key constraint \"admin_login_tokens_admin_id_fkey\""}
+	}
+	if _, ok := s.loginTokens[arg.Nonce]; ok {
+		return uniqueViolation("admin_login_tokens_pkey")
+	}
+	s.loginTokens[arg.Nonce] = sqlc.AdminLoginTokens{
+		Nonce:     arg.Nonce,
+		AdminID:   arg.AdminID,
+		ExpiresAt: arg.ExpiresAt,
+	}
+	return nil
+}
+
+func (s *Store) ConsumeAdminLoginToken(ctx context.Context, arg *sqlc.ConsumeAdminLoginTokenParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.loginTokens[arg.Nonce]
+	if !ok || !token.ExpiresAt.After(arg.Now) {
+		return 0, sql.ErrNoRows
+	}
+	delete(s.loginTokens, arg.Nonce)
+	return token.AdminID, nil
+}
+
+func (s *Store) DeleteAdminLoginTokensBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for nonce, token := range s.loginTokens {
+		if token.ExpiresAt.Before(before) {
+			delete(s.loginTokens, nonce)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) CreateToken(ctx context.Context, arg *sqlc.CreateTokenParams) (*sqlc.Tokens, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.admins[arg.AdminID]; !ok {
+		return &sqlc.Tokens{}, &pq.Error{Code: "23503", Message: "insert or update on table \"tokens\" violates foreign key constraint \"tokens_admin_id_fkey\""}
+	}
+	for _, token := range s.tokens {
+		if token.TokenHash == arg.TokenHash {
+			return &sqlc.Tokens{}, uniqueViolation("tokens_token_hash_key")
+		}
+	}
+	token := sqlc.Tokens{
+		ID:        s.id(),
+		AdminID:   arg.AdminID,
+		Name:      arg.Name,
+		TokenHash: arg.TokenHash,
+		Scopes:    slices.Clone(arg.Scopes),
+		CreatedAt: time.Now(),
+	}
+	s.tokens[token.ID] = token
+	return &token, nil
+}
+
+func (s *Store) GetTokenByHash(ctx context.Context, tokenHash string) (*sqlc.Tokens, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return &sqlc.Tokens{}, sql.ErrNoRows
+}
+
+func (s *Store) GetTokensByAdminID(ctx context.Context, adminID int64) ([]*sqlc.Tokens, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := make([]*sqlc.Tokens, 0)
+	for _, token := range s.tokens {
+		if token.AdminID == adminID {
+			tokens = append(tokens, &token)
+		}
+	}
+	slices.SortFunc(tokens, func(a, b *sqlc.Tokens) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return tokens, nil
+}
+
+func (s *Store) TouchToken(ctx context.Context, arg *sqlc.TouchTokenParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[arg.ID]
+	if !ok {
+		return nil
+	}
+	token.LastUsedAt = arg.LastUsedAt
+	s.tokens[arg.ID] = token
+	return nil
+}
+
+func (s *Store) DeleteToken(ctx context.Context, arg *sqlc.DeleteTokenParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[arg.ID]
+	if !ok || token.AdminID != arg.AdminID {
+		return 0, nil
+	}
+	delete(s.tokens, arg.ID)
+	return 1, nil
+}
+
+func (s *Store) SaveSession(ctx context.Context, arg *sqlc.SaveSessionParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if arg.AdminID.Valid {
+		if _, ok := s.admins[arg.AdminID.Int64]; !ok {
+			return &pq.Error{Code: "23503", Message: "insert or update on table \"sessions\" violates foreign key constraint \"sessions_admin_id_fkey\""}
+		}
+	}
+	for id, session := range s.sessions {
+		if session.TokenHash == arg.TokenHash {
+			session.AdminID = arg.AdminID
+			session.Data = slices.Clone(arg.Data)
+			session.UserAgent = arg.UserAgent
+			session.Ip = arg.Ip
+			session.UpdatedAt = time.Now()
+			session.ExpiresAt = arg.ExpiresAt
+			s.sessions[id] = session
+			return nil
+		}
+	}
+	session := sqlc.Sessions{
+		ID:        s.id(),
+		TokenHash: arg.TokenHash,
+		Name:      arg.Name,
+		AdminID:   arg.AdminID,
+		Data:      slices.Clone(arg.Data),
+		UserAgent: arg.UserAgent,
+		Ip:        arg.Ip,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		ExpiresAt: arg.ExpiresAt,
+	}
+	s.sessions[session.ID] = session
+	return nil
+}
+
+func (s *Store) GetSession(ctx context.Context, arg *sqlc.GetSessionParams) (*sqlc.Sessions, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, session := range s.sessions {
+		if session.TokenHash == arg.TokenHash && session.ExpiresAt.After(arg.Now) {
+			session.Data = slices.Clone(session.Data)
+			return &session, nil
+		}
+	}
+	return &sqlc.Sessions{}, sql.ErrNoRows
+}
+
+func (s *Store) GetAdminSessions(ctx context.Context, now time.Time) ([]*sqlc.GetAdminSessionsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := make([]*sqlc.GetAdminSessionsRow, 0)
+	for _, session := range s.sessions {
+		admin, ok := s.admins[session.AdminID.Int64]
+		if !session.AdminID.Valid || !ok || !session.ExpiresAt.After(now) {
+			continue
+		}
+		rows = append(rows, &sqlc.GetAdminSessionsRow{
+			ID:        session.ID,
+			TokenHash: session.TokenHash,
+			AdminID:   session.AdminID,
+			Username:  admin.Username,
+			UserAgent: session.UserAgent,
+			Ip:        session.Ip,
+			CreatedAt: session.CreatedAt,
+			UpdatedAt: session.UpdatedAt,
+			ExpiresAt: session.ExpiresAt,
+		})
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetAdminSessionsRow) int {
+		return cmp.Or(strings.Compare(a.Username, b.Username), b.UpdatedAt.Compare(a.UpdatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return rows, nil
+}
+
+func (s *Store) DeleteSession(ctx context.Context, tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, session := range s.sessions {
+		if session.TokenHash == tokenHash {
+			delete(s.sessions, id)
+		}
+	}
+	return nil
+}
+
+func (s *Store) DeleteSessionByID(ctx context.Context, id int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[id]; !ok {
+		return 0, nil
+	}
+	delete(s.sessions, id)
+	return 1, nil
+}
+
+func (s *Store) DeleteOtherAdminSessions(ctx context.Context, arg *sqlc.DeleteOtherAdminSessionsParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for id, session := range s.sessions {
+		if session.AdminID == arg.AdminID && session.TokenHash != arg.TokenHash {
+			delete(s.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) DeleteSessionsBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for id, session := range s.sessions {
+		if session.ExpiresAt.Before(before) {
+			delete(s.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) CreateBroadcast(ctx context.Context, arg *sqlc.CreateBroadcastParams) (*sqlc.Broadcasts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &sqlc.Broadcasts{}, &pq.Error{Code: "23503", Message: "insert or update on table \"broadcasts\" violates foreign key constraint \"broadcasts_event_id_fkey\""}
+	}
+
+	broadcast := sqlc.Broadcasts{
+		ID:        s.id(),
+		EventID:   arg.EventID,
+		Text:      arg.Text,
+		SendAt:    arg.SendAt,
+		CreatedAt: time.Now(),
+	}
+	s.broadcasts[broadcast.ID] = broadcast
+	return &broadcast, nil
+}
+
+func (s *Store) GetBroadcastsByEventID(ctx context.Context, eventID int64) ([]*sqlc.Broadcasts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sortedBroadcasts(func(broadcast sqlc.Broadcasts) bool { return broadcast.EventID == eventID }), nil
+}
+
+func (s *Store) UpdateBroadcast(ctx context.Context, arg *sqlc.UpdateBroadcastParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	broadcast, ok := s.broadcasts[arg.ID]
+	if !ok || broadcast.EventID != arg.EventID || broadcast.QueuedAt.Valid {
+		return 0, nil
+	}
+	broadcast.Text = arg.Text
+	broadcast.SendAt = arg.SendAt
+	s.broadcasts[broadcast.ID] = broadcast
+	return 1, nil
+}
+
+func (s *Store) DeleteBroadcast(ctx context.Context, arg *sqlc.DeleteBroadcastParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	broadcast, ok := s.broadcasts[arg.ID]
+	if !ok || broadcast.EventID != arg.EventID || broadcast.QueuedAt.Valid {
+		return 0, nil
+	}
+	delete(s.broadcasts, arg.ID)
+	return 1, nil
+}
+
+func (s *Store) GetDueBroadcasts(ctx context.Context, now time.Time) ([]*sqlc.Broadcasts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sortedBroadcasts(func(broadcast sqlc.Broadcasts) bool {
+		return !broadcast.QueuedAt.Valid && !broadcast.SendAt.After(now)
+	}), nil
+}
+
+func (s *Store) MarkBroadcastQueued(ctx context.Context, id int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	broadcast, ok := s.broadcasts[id]
+	if !ok || broadcast.QueuedAt.Valid {
+		return 0, nil
+	}
+	broadcast.QueuedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	s.broadcasts[broadcast.ID] = broadcast
+	return 1, nil
+}
+
+// sortedBroadcasts returns the broadcasts matching keep by send time.
+func (s *Store) sortedBroadcasts(keep func(sqlc.Broadcasts) bool) []*sqlc.Broadcasts {
+	broadcasts := make([]*sqlc.Broadcasts, 0)
+	for _, broadcast := range s.broadcasts {
+		if keep(broadcast) {
+			broadcasts = append(broadcasts, &broadcast)
+		}
+	}
+	slices.SortFunc(broadcasts, func(a, b *sqlc.Broadcasts) int {
+		return cmp.Or(a.SendAt.Compare(b.SendAt), cmp.Compare(a.ID, b.ID))
+	})
+	return broadcasts
+}
+
+func (s *Store) GetBroadcastByID(ctx context.Context, arg *sqlc.GetBroadcastByIDParams) (*sqlc.Broadcasts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	broadcast, ok := s.broadcasts[arg.ID]
+	if !ok || broadcast.EventID != arg.EventID {
+		return &sqlc.Broadcasts{}, sql.ErrNoRows
+	}
+	return &broadcast, nil
+}
+
+func (s *Store) CreateBroadcastDelivery(ctx context.Context, arg *sqlc.CreateBroadcastDeliveryParams) (*sqlc.BroadcastDeliveries, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.broadcasts[arg.BroadcastID]; !ok {
+		return &sqlc.BroadcastDeliveries{}, &pq.Error{Code: "23503", Message: "insert or update on table \"broadcast_deliveries\" violates foreign key constraint \"broadcast_deliveries_broadcast_id_fkey\""}
+	}
+	if _, ok := s.users[arg.UserID.Int64]; arg.UserID.Valid && !ok {
+		return &sqlc.BroadcastDeliveries{}, &pq.Error{Code: "23503", Message: "insert or update on table \"broadcast_deliveries\" violates foreign key constraint \"broadcast_deliveries_user_id_fkey\""}
+	}
+
+	delivery := sqlc.BroadcastDeliveries{
+		ID:          s.id(),
+		BroadcastID: arg.BroadcastID,
+		UserID:      arg.UserID,
+		ChatID:      arg.ChatID,
+		Text:        arg.Text,
+		Status:      store.DeliveryPending,
+		UpdatedAt:   time.Now(),
+	}
+	s.deliveries[delivery.ID] = delivery
+	return &delivery, nil
+}
+
+func (s *Store) GetBroadcastDeliveries(ctx context.Context, broadcastID int64) ([]*sqlc.GetBroadcastDeliveriesRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := make([]*sqlc.GetBroadcastDeliveriesRow, 0)
+	for _, delivery := range s.deliveries {
+		if delivery.BroadcastID != broadcastID {
+			continue
+		}
+		row := &sqlc.GetBroadcastDeliveriesRow{BroadcastDeliveries: delivery}
+		if user, ok := s.users[delivery.UserID.Int64]; delivery.UserID.Valid && ok {
+			row.Name = sql.NullString{String: user.Name, Valid: true}
+			row.TicketNumber = sql.NullInt32{Int32: user.TicketNumber, Valid: true}
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b *sqlc.GetBroadcastDeliveriesRow) int {
+		return cmp.Compare(a.BroadcastDeliveries.ID, b.BroadcastDeliveries.ID)
+	})
+	return rows, nil
+}
+
+func (s *Store) SetBroadcastDeliveryStatus(ctx context.Context, arg *sqlc.SetBroadcastDeliveryStatusParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if delivery, ok := s.deliveries[arg.ID]; ok {
+		delivery.Status = arg.Status
+		delivery.Error = arg.Error
+		delivery.UpdatedAt = time.Now()
+		s.deliveries[arg.ID] = delivery
+	}
+	return nil
+}
+
+// detachDeliveries mirrors ON DELETE SET NULL on
+// broadcast_deliveries.user_id.
+func (s *Store) detachDeliveries(userID int64) {
+	for id, delivery := range s.deliveries {
+		if delivery.UserID.Valid && delivery.UserID.Int64 == userID {
+			delivery.UserID = sql.NullInt64{}
+			s.deliveries[id] = delivery
+		}
+	}
+}
+
+// detachOutbox mirrors ON DELETE SET NULL on outbox.delivery_id.
+func (s *Store) detachOutbox(deliveryID int64) {
+	for id, msg := range s.outbox {
+		if msg.DeliveryID.Valid && msg.DeliveryID.Int64 == deliveryID {
+			msg.DeliveryID = sql.NullInt64{}
+			s.outbox[id] = msg
+		}
+	}
+}
+
+// detachOutboxSMS mirrors ON DELETE SET NULL on outbox.sms_user_id.
+func (s *Store) detachOutboxSMS(userID int64) {
+	for id, msg := range s.outbox {
+		if msg.SmsUserID.Valid && msg.SmsUserID.Int64 == userID {
+			msg.SmsUserID = sql.NullInt64{}
+			s.outbox[id] = msg
+		}
+	}
+}
+
+func (s *Store) MarkChatBlocked(ctx context.Context, tgID int64) ([]*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flagged := make([]*sqlc.Users, 0)
+	for id, user := range s.users {
+		if user.TgID.Valid && user.TgID.Int64 == tgID && !user.BotBlockedAt.Valid {
+			user.BotBlockedAt = now()
+			s.users[id] = user
+			flagged = append(flagged, &user)
+		}
+	}
+	return flagged, nil
+}
+
+func (s *Store) ClearChatBlocked(ctx context.Context, tgID int64) ([]*sqlc.Users, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cleared := make([]*sqlc.Users, 0)
+	for id, user := range s.users {
+		if user.TgID.Valid && user.TgID.Int64 == tgID && user.BotBlockedAt.Valid {
+			user.BotBlockedAt = sql.NullTime{}
+			s.users[id] = user
+			cleared = append(cleared, &user)
+		}
+	}
+	return cleared, nil
+}
+
+func (s *Store) CreateConsentRecord(ctx context.Context, arg *sqlc.CreateConsentRecordParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[arg.EventID]; !ok {
+		return &pq.Error{Code: "23503", Message: "insert or update on table \"consent_log\" violates foreign key constraint \"consent_log_event_id_fkey\""}
+	}
+	if _, ok := s.users[arg.UserID.Int64]; arg.UserID.Valid && !ok {
+		return &pq.Error{Code: "23503", Message: "insert or update on table \"consent_log\" violates foreign key constraint \"consent_log_user_id_fkey\""}
+	}
+
+	id := s.id()
+	s.consentLog[id] = sqlc.ConsentLog{
+		ID:           id,
+		EventID:      arg.EventID,
+		UserID:       arg.UserID,
+		TicketNumber: arg.TicketNumber,
+		Action:       arg.Action,
+		Source:       arg.Source,
+		Version:      arg.Version,
+		CreatedAt:    time.Now(),
+	}
+	return nil
+}
+
+func (s *Store) GetConsentLogByEventID(ctx context.Context, eventID int64) ([]*sqlc.ConsentLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]*sqlc.ConsentLog, 0)
+	for _, record := range s.consentLog {
+		if record.EventID == eventID {
+			records = append(records, &record)
+		}
+	}
+	slices.SortFunc(records, func(a, b *sqlc.ConsentLog) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return records, nil
+}
+
+// detachConsentLog mirrors ON DELETE SET NULL on consent_log.user_id.
+func (s *Store) detachConsentLog(userID int64) {
+	for id, record := range s.consentLog {
+		if record.UserID.Valid && record.UserID.Int64 == userID {
+			record.UserID = sql.NullInt64{}
+			s.consentLog[id] = record
+		}
+	}
+}
+
+func (s *Store) CreateAuditRecord(ctx context.Context, arg *sqlc.CreateAuditRecordParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.id()
+	s.auditLog[id] = sqlc.AuditLog{
+		ID:        id,
+		Actor:     arg.Actor,
+		Action:    arg.Action,
+		EventID:   arg.EventID,
+		Diff:      arg.Diff,
+		CreatedAt: time.Now(),
+	}
+	return nil
+}
+
+func (s *Store) GetAuditLogPage(ctx context.Context, arg *sqlc.GetAuditLogPageParams) ([]*sqlc.AuditLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var organized []int64
+	for _, organizer := range s.organizers {
+		if organizer.AdminID.Valid && organizer.AdminID.Int64 == arg.OrganizerID {
+			organized = append(organized, organizer.EventID)
+		}
+	}
+	records := make([]*sqlc.AuditLog, 0, len(s.auditLog))
+	for _, record := range s.auditLog {
+		if arg.OrganizerID != 0 && !(record.EventID.Valid && slices.Contains(organized, record.EventID.Int64)) {
+			continue
+		}
+		records = append(records, &record)
+	}
+	slices.SortFunc(records, func(a, b *sqlc.AuditLog) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	start := min(int(arg.PageOffset), len(records))
+	end := min(start+int(arg.PageSize), len(records))
+	return records[start:end], nil
+}
+
+func (s *Store) EnqueueWebhook(ctx context.Context, arg *sqlc.EnqueueWebhookParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook := sqlc.Webhooks{
+		ID:            s.id(),
+		Url:           arg.Url,
+		Payload:       arg.Payload,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	s.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (s *Store) ClaimWebhooks(ctx context.Context, arg *sqlc.ClaimWebhooksParams) ([]*sqlc.Webhooks, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make([]*sqlc.Webhooks, 0)
+	for _, webhook := range s.webhooks {
+		if !webhook.SentAt.Valid && !webhook.FailedAt.Valid && !webhook.NextAttemptAt.After(time.Now()) {
+			pending = append(pending, &webhook)
+		}
+	}
+	slices.SortFunc(pending, func(a, b *sqlc.Webhooks) int { return cmp.Compare(a.ID, b.ID) })
+	if len(pending) > int(arg.BatchSize) {
+		pending = pending[:arg.BatchSize]
+	}
+
+	lease := time.Now().Add(time.Duration(arg.LeaseSeconds) * time.Second)
+	for _, webhook := range pending {
+		webhook.NextAttemptAt = lease
+		s.webhooks[webhook.ID] = *webhook
+	}
+	return pending, nil
+}
+
+func (s *Store) MarkWebhookSent(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if webhook, ok := s.webhooks[id]; ok {
+		webhook.Attempts++
+		webhook.SentAt = now()
+		s.webhooks[id] = webhook
+	}
+	return nil
+}
+
+func (s *Store) MarkWebhookFailed(ctx context.Context, arg *sqlc.MarkWebhookFailedParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if webhook, ok := s.webhooks[arg.ID]; ok {
+		webhook.Attempts++
+		webhook.LastError = arg.LastError
+		webhook.NextAttemptAt = time.Now().Add(time.Duration(arg.RetryAfterSeconds) * time.Second)
+		if arg.GiveUp {
+			webhook.FailedAt = now()
+		}
+		s.webhooks[arg.ID] = webhook
+	}
+	return nil
+}
+
+func (s *Store) DeleteWebhooksBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.webhooks)
+	maps.DeleteFunc(s.webhooks, func(_ int64, webhook sqlc.Webhooks) bool {
+		return (webhook.SentAt.Valid && webhook.SentAt.Time.Before(before)) ||
+			(webhook.FailedAt.Valid && webhook.FailedAt.Time.Before(before))
+	})
+	return int64(n - len(s.webhooks)), nil
+}
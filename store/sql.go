@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"giveaway-tool/database/sqlc"
+)
+
+// SQLStore is the Postgres implementation of Store backed by sqlc queries.
+type SQLStore struct {
+	*sqlc.Queries
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQL(db *sql.DB) *SQLStore {
+	return &SQLStore{
+		Queries: sqlc.New(tracedDB{db}),
+		db:      db,
+	}
+}
+
+func (s *SQLStore) InTx(ctx context.Context, fn func(Store) error) error {
+	// Nested calls reuse the outer transaction
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(&SQLStore{Queries: sqlc.New(tracedDB{tx}), db: s.db, tx: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,343 @@
+// Package store defines the persistence interfaces used by the web service
+// and the Telegram bot, so handlers don't depend on sqlc directly.
+package store
+
+import (
+	"context"
+	"time"
+
+	"giveaway-tool/database/sqlc"
+)
+
+type EventStore interface {
+	CreateEvent(ctx context.Context, arg *sqlc.CreateEventParams) (*sqlc.Events, error)
+	UpdateEvent(ctx context.Context, arg *sqlc.UpdateEventParams) (*sqlc.Events, error)
+	GetEvents(ctx context.Context) ([]*sqlc.Events, error)
+	GetEventsPage(ctx context.Context, arg *sqlc.GetEventsPageParams) ([]*sqlc.Events, error)
+	GetEventByID(ctx context.Context, id int64) (*sqlc.Events, error)
+	GetEventBySlug(ctx context.Context, slug string) (*sqlc.Events, error)
+	EventSlugTaken(ctx context.Context, arg *sqlc.EventSlugTakenParams) (bool, error)
+	SetEventSlug(ctx context.Context, arg *sqlc.SetEventSlugParams) (*sqlc.Events, error)
+	GetLastEvent(ctx context.Context) (*sqlc.Events, error)
+	DeleteEvent(ctx context.Context, id int64) error
+	GetDeletedEvents(ctx context.Context) ([]*sqlc.Events, error)
+	RestoreEvent(ctx context.Context, id int64) (*sqlc.Events, error)
+	PurgeDeletedEventsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	SetEventWaitlistAutoPromote(ctx context.Context, arg *sqlc.SetEventWaitlistAutoPromoteParams) (*sqlc.Events, error)
+	SetEventKioskToken(ctx context.Context, arg *sqlc.SetEventKioskTokenParams) (*sqlc.Events, error)
+	SetEventPaidEntries(ctx context.Context, arg *sqlc.SetEventPaidEntriesParams) (*sqlc.Events, error)
+	SetEventTicketPrice(ctx context.Context, arg *sqlc.SetEventTicketPriceParams) (*sqlc.Events, error)
+	SetEventShareBonus(ctx context.Context, arg *sqlc.SetEventShareBonusParams) (*sqlc.Events, error)
+	SetEventShowWinners(ctx context.Context, arg *sqlc.SetEventShowWinnersParams) (*sqlc.Events, error)
+	SetEventSeatAssignment(ctx context.Context, arg *sqlc.SetEventSeatAssignmentParams) (*sqlc.Events, error)
+	SetEventCategories(ctx context.Context, arg *sqlc.SetEventCategoriesParams) (*sqlc.Events, error)
+	GetEventCategories(ctx context.Context, archived bool) ([]string, error)
+	SetEventCapacity(ctx context.Context, arg *sqlc.SetEventCapacityParams) (*sqlc.Events, error)
+	GetEventForUpdate(ctx context.Context, id int64) (*sqlc.Events, error)
+	CountEventTaken(ctx context.Context, arg *sqlc.CountEventTakenParams) (int32, error)
+	SetEventStatus(ctx context.Context, arg *sqlc.SetEventStatusParams) (*sqlc.Events, error)
+	SetEventRegistrationWindow(ctx context.Context, arg *sqlc.SetEventRegistrationWindowParams) (*sqlc.Events, error)
+	SetEventImage(ctx context.Context, arg *sqlc.SetEventImageParams) (*sqlc.Events, error)
+	GetEventImage(ctx context.Context, eventID int64) (*sqlc.EventImages, error)
+	DeleteEventImage(ctx context.Context, eventID int64) (*sqlc.Events, error)
+	SyncLastTicketNumber(ctx context.Context, id int64) error
+	ArchiveEventsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	ArchiveEvent(ctx context.Context, id int64) (*sqlc.Events, error)
+	UnarchiveEvent(ctx context.Context, id int64) (*sqlc.Events, error)
+	GetEventsToSyncToCalendar(ctx context.Context) ([]*sqlc.Events, error)
+	LockEventForCalendarSync(ctx context.Context, id int64) (*sqlc.Events, error)
+	SetEventCalendarSynced(ctx context.Context, arg *sqlc.SetEventCalendarSyncedParams) error
+}
+
+type UserStore interface {
+	CountUsersByEventID(ctx context.Context, eventID int64) (int64, error)
+	CreateUser(ctx context.Context, arg *sqlc.CreateUserParams) (*sqlc.Users, error)
+	CreateUsersBatch(ctx context.Context, arg *sqlc.CreateUsersBatchParams) (int64, error)
+	DeleteUser(ctx context.Context, id int64) error
+	DeleteUsersByIdAndEventId(ctx context.Context, arg *sqlc.DeleteUsersByIdAndEventIdParams) error
+	GetDeletedUsers(ctx context.Context) ([]*sqlc.GetDeletedUsersRow, error)
+	RestoreUser(ctx context.Context, id int64) (*sqlc.Users, error)
+	PurgeDeletedUsersBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	GetUserByID(ctx context.Context, id int64) (*sqlc.Users, error)
+	GetUserByUsername(ctx context.Context, username string) (*sqlc.Users, error)
+	GetUserByCheckInCode(ctx context.Context, arg *sqlc.GetUserByCheckInCodeParams) (*sqlc.Users, error)
+	GetUserByTicketNumber(ctx context.Context, arg *sqlc.GetUserByTicketNumberParams) (*sqlc.Users, error)
+	GetUserByTgIDAndEventID(ctx context.Context, arg *sqlc.GetUserByTgIDAndEventIDParams) (*sqlc.Users, error)
+	CheckInUser(ctx context.Context, id int64) (*sqlc.Users, error)
+	GetUsersByEventID(ctx context.Context, eventID int64) ([]*sqlc.Users, error)
+	GetUsersByEventIDAfter(ctx context.Context, arg *sqlc.GetUsersByEventIDAfterParams) ([]*sqlc.Users, error)
+	GetLatestUsersByEventID(ctx context.Context, arg *sqlc.GetLatestUsersByEventIDParams) ([]*sqlc.Users, error)
+	GetUsersPage(ctx context.Context, arg *sqlc.GetUsersPageParams) ([]*sqlc.Users, error)
+	CountUsersWithTag(ctx context.Context, arg *sqlc.CountUsersWithTagParams) (int64, error)
+	CountUsersReach(ctx context.Context, eventID int64) (*sqlc.CountUsersReachRow, error)
+	GetEventTags(ctx context.Context, eventID int64) ([]string, error)
+	UpdateUserN(ctx context.Context, arg *sqlc.UpdateUserNParams) error
+	UpdateUserProfile(ctx context.Context, arg *sqlc.UpdateUserProfileParams) (*sqlc.Users, error)
+	ConfirmUserAttendance(ctx context.Context, id int64) (*sqlc.Users, error)
+	AddUserPaidEntries(ctx context.Context, arg *sqlc.AddUserPaidEntriesParams) error
+	AddUserPromoEntries(ctx context.Context, arg *sqlc.AddUserPromoEntriesParams) error
+	SetUserTicketType(ctx context.Context, arg *sqlc.SetUserTicketTypeParams) (*sqlc.Users, error)
+	SetUserShareCode(ctx context.Context, arg *sqlc.SetUserShareCodeParams) (*sqlc.Users, error)
+	GetUserByShareCode(ctx context.Context, shareCode string) (*sqlc.Users, error)
+	GrantShareBonus(ctx context.Context, arg *sqlc.GrantShareBonusParams) (int64, error)
+	FlagSuspiciousUsers(ctx context.Context, arg *sqlc.FlagSuspiciousUsersParams) (int64, error)
+	GetFlaggedUsers(ctx context.Context, eventID int64) ([]*sqlc.Users, error)
+	ApproveUser(ctx context.Context, arg *sqlc.ApproveUserParams) (*sqlc.Users, error)
+	SetUserTags(ctx context.Context, arg *sqlc.SetUserTagsParams) (*sqlc.Users, error)
+	SetUserNotes(ctx context.Context, arg *sqlc.SetUserNotesParams) (*sqlc.Users, error)
+	ImportUser(ctx context.Context, arg *sqlc.ImportUserParams) (*sqlc.Users, error)
+	MarkChatBlocked(ctx context.Context, tgID int64) ([]*sqlc.Users, error)
+	ClearChatBlocked(ctx context.Context, tgID int64) ([]*sqlc.Users, error)
+}
+
+type DrawStore interface {
+	CreateDraw(ctx context.Context, arg *sqlc.CreateDrawParams) (*sqlc.Draws, error)
+	CreateDrawWinner(ctx context.Context, arg *sqlc.CreateDrawWinnerParams) error
+	ImportDraw(ctx context.Context, arg *sqlc.ImportDrawParams) (*sqlc.Draws, error)
+	GetDrawsByEventID(ctx context.Context, eventID int64) ([]*sqlc.Draws, error)
+	GetDrawWinners(ctx context.Context, drawID int64) ([]*sqlc.GetDrawWinnersRow, error)
+	GetPublicWinners(ctx context.Context, arg *sqlc.GetPublicWinnersParams) ([]*sqlc.GetPublicWinnersRow, error)
+}
+
+type WaitlistStore interface {
+	AddToWaitlist(ctx context.Context, arg *sqlc.AddToWaitlistParams) (*sqlc.Waitlist, error)
+	GetWaitlistByEventID(ctx context.Context, eventID int64) ([]*sqlc.GetWaitlistByEventIDRow, error)
+	GetWaitlistEntry(ctx context.Context, arg *sqlc.GetWaitlistEntryParams) (*sqlc.Waitlist, error)
+	GetNextWaitlistEntry(ctx context.Context, eventID int64) (*sqlc.Waitlist, error)
+	DeleteWaitlistEntry(ctx context.Context, arg *sqlc.DeleteWaitlistEntryParams) error
+	GetWaitlistEntryByTgID(ctx context.Context, arg *sqlc.GetWaitlistEntryByTgIDParams) (*sqlc.Waitlist, error)
+	GetWaitlistPosition(ctx context.Context, arg *sqlc.GetWaitlistPositionParams) (int32, error)
+}
+
+type FlagStore interface {
+	GetFeatureFlags(ctx context.Context) ([]*sqlc.FeatureFlags, error)
+	SetFeatureFlag(ctx context.Context, arg *sqlc.SetFeatureFlagParams) (*sqlc.FeatureFlags, error)
+}
+
+type OutboxStore interface {
+	EnqueueOutboxMessage(ctx context.Context, arg *sqlc.EnqueueOutboxMessageParams) (*sqlc.Outbox, error)
+	ClaimOutboxMessages(ctx context.Context, arg *sqlc.ClaimOutboxMessagesParams) ([]*sqlc.Outbox, error)
+	MarkOutboxMessageSent(ctx context.Context, id int64) error
+	MarkOutboxMessageFailed(ctx context.Context, arg *sqlc.MarkOutboxMessageFailedParams) error
+	DeleteOutboxBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type EntryRuleStore interface {
+	CreateEntryRule(ctx context.Context, arg *sqlc.CreateEntryRuleParams) (*sqlc.EntryRules, error)
+	GetEntryRulesByEventID(ctx context.Context, eventID int64) ([]*sqlc.EntryRules, error)
+	DeleteEntryRule(ctx context.Context, arg *sqlc.DeleteEntryRuleParams) error
+	RecalculateEntryBonuses(ctx context.Context, eventID int64) (int64, error)
+}
+
+type OrganizerStore interface {
+	CreateEventOrganizer(ctx context.Context, arg *sqlc.CreateEventOrganizerParams) (*sqlc.EventOrganizers, error)
+	GetEventOrganizers(ctx context.Context, eventID int64) ([]*sqlc.EventOrganizers, error)
+	GetOrganizedEventIDs(ctx context.Context, adminID int64) ([]int64, error)
+	DeleteEventOrganizer(ctx context.Context, arg *sqlc.DeleteEventOrganizerParams) error
+}
+
+type TranslationStore interface {
+	UpsertEventTranslation(ctx context.Context, arg *sqlc.UpsertEventTranslationParams) (*sqlc.EventTranslations, error)
+	GetEventTranslations(ctx context.Context, eventID int64) ([]*sqlc.EventTranslations, error)
+	GetEventTranslation(ctx context.Context, arg *sqlc.GetEventTranslationParams) (*sqlc.EventTranslations, error)
+	GetTranslationsByLanguage(ctx context.Context, language string) ([]*sqlc.EventTranslations, error)
+	DeleteEventTranslation(ctx context.Context, arg *sqlc.DeleteEventTranslationParams) error
+}
+
+type ShareStore interface {
+	RecordShareClick(ctx context.Context, arg *sqlc.RecordShareClickParams) (int64, error)
+	CountShareClicks(ctx context.Context, userID int64) (int64, error)
+	CountShareClicksByVisitor(ctx context.Context, arg *sqlc.CountShareClicksByVisitorParams) (int64, error)
+	GetShareReport(ctx context.Context, eventID int64) ([]*sqlc.GetShareReportRow, error)
+}
+
+type PurchaseStore interface {
+	CreateEntryPurchase(ctx context.Context, arg *sqlc.CreateEntryPurchaseParams) (*sqlc.EntryPurchases, error)
+	CountReservedPaidEntries(ctx context.Context, arg *sqlc.CountReservedPaidEntriesParams) (int32, error)
+	MarkEntryPurchasePaid(ctx context.Context, arg *sqlc.MarkEntryPurchasePaidParams) (*sqlc.EntryPurchases, error)
+	MarkEntryPurchaseFailed(ctx context.Context, orderID string) error
+	MarkEntryPurchaseRefunded(ctx context.Context, orderID string) (int64, error)
+	CreateTicketOrder(ctx context.Context, arg *sqlc.CreateTicketOrderParams) (*sqlc.TicketOrders, error)
+	GetTicketOrder(ctx context.Context, orderID string) (*sqlc.TicketOrders, error)
+	MarkTicketOrderPaid(ctx context.Context, arg *sqlc.MarkTicketOrderPaidParams) (*sqlc.TicketOrders, error)
+	MarkTicketOrderFailed(ctx context.Context, orderID string) error
+	MarkTicketOrderRefunded(ctx context.Context, orderID string) (int64, error)
+	SetTicketOrderUser(ctx context.Context, arg *sqlc.SetTicketOrderUserParams) error
+	GetPaymentsByEventID(ctx context.Context, eventID int64) ([]*sqlc.GetPaymentsByEventIDRow, error)
+}
+
+type PromoCodeStore interface {
+	CreatePromoCode(ctx context.Context, arg *sqlc.CreatePromoCodeParams) (*sqlc.PromoCodes, error)
+	DeletePromoCode(ctx context.Context, arg *sqlc.DeletePromoCodeParams) error
+	GetPromoCode(ctx context.Context, arg *sqlc.GetPromoCodeParams) (*sqlc.PromoCodes, error)
+	UsePromoCode(ctx context.Context, arg *sqlc.UsePromoCodeParams) (*sqlc.PromoCodes, error)
+	CreatePromoRedemption(ctx context.Context, arg *sqlc.CreatePromoRedemptionParams) error
+	SetPromoRedemptionUser(ctx context.Context, arg *sqlc.SetPromoRedemptionUserParams) (*sqlc.PromoRedemptions, error)
+	ReleasePromoRedemption(ctx context.Context, orderID string) error
+	GetPromoCodeStats(ctx context.Context, eventID int64) ([]*sqlc.GetPromoCodeStatsRow, error)
+}
+
+type ShortLinkStore interface {
+	CreateShortLink(ctx context.Context, arg *sqlc.CreateShortLinkParams) (*sqlc.ShortLinks, error)
+	DeleteShortLink(ctx context.Context, arg *sqlc.DeleteShortLinkParams) error
+	ClickShortLink(ctx context.Context, id int64) error
+	GetShortLinkByCode(ctx context.Context, code string) (*sqlc.ShortLinks, error)
+	GetShortLinksByEventID(ctx context.Context, eventID int64) ([]*sqlc.ShortLinks, error)
+}
+
+type TicketTypeStore interface {
+	CreateTicketType(ctx context.Context, arg *sqlc.CreateTicketTypeParams) (*sqlc.TicketTypes, error)
+	DeleteTicketType(ctx context.Context, arg *sqlc.DeleteTicketTypeParams) error
+	GetTicketType(ctx context.Context, arg *sqlc.GetTicketTypeParams) (*sqlc.TicketTypes, error)
+	GetTicketTypeForUpdate(ctx context.Context, arg *sqlc.GetTicketTypeForUpdateParams) (*sqlc.TicketTypes, error)
+	GetTicketTypesByEventID(ctx context.Context, eventID int64) ([]*sqlc.TicketTypes, error)
+	CountTicketTypeTaken(ctx context.Context, arg *sqlc.CountTicketTypeTakenParams) (int32, error)
+	GetTicketTypeStats(ctx context.Context, eventID int64) ([]*sqlc.GetTicketTypeStatsRow, error)
+}
+
+type SeatStore interface {
+	CreateSeats(ctx context.Context, arg *sqlc.CreateSeatsParams) (int64, error)
+	DeleteSeat(ctx context.Context, arg *sqlc.DeleteSeatParams) error
+	GetSeatsByEventID(ctx context.Context, eventID int64) ([]*sqlc.GetSeatsByEventIDRow, error)
+	GetSeatByUserID(ctx context.Context, userID int64) (*sqlc.Seats, error)
+	GetSeatByLabelForUpdate(ctx context.Context, arg *sqlc.GetSeatByLabelForUpdateParams) (*sqlc.Seats, error)
+	AssignFreeSeat(ctx context.Context, arg *sqlc.AssignFreeSeatParams) (*sqlc.Seats, error)
+	SetSeatUser(ctx context.Context, arg *sqlc.SetSeatUserParams) error
+	ReleaseSeat(ctx context.Context, userID int64) error
+}
+
+type EventTemplateStore interface {
+	CreateEventTemplate(ctx context.Context, arg *sqlc.CreateEventTemplateParams) (*sqlc.EventTemplates, error)
+	CreateEventTemplateRule(ctx context.Context, arg *sqlc.CreateEventTemplateRuleParams) error
+	GetEventTemplates(ctx context.Context) ([]*sqlc.GetEventTemplatesRow, error)
+	GetEventTemplate(ctx context.Context, id int64) (*sqlc.EventTemplates, error)
+	GetEventTemplateRules(ctx context.Context, templateID int64) ([]*sqlc.EventTemplateRules, error)
+	UpdateEventTemplate(ctx context.Context, arg *sqlc.UpdateEventTemplateParams) (*sqlc.EventTemplates, error)
+	DeleteEventTemplate(ctx context.Context, id int64) error
+}
+
+type DigestStore interface {
+	CreateDigestSubscription(ctx context.Context, arg *sqlc.CreateDigestSubscriptionParams) (*sqlc.DigestSubscriptions, error)
+	GetDigestSubscriptions(ctx context.Context) ([]*sqlc.DigestSubscriptions, error)
+	GetDigestSubscription(ctx context.Context, id int64) (*sqlc.DigestSubscriptions, error)
+	DeleteDigestSubscription(ctx context.Context, id int64) error
+	PruneNotifyEventIDs(ctx context.Context) (int64, error)
+	MarkDigestSent(ctx context.Context, arg *sqlc.MarkDigestSentParams) (int64, error)
+	GetRegistrationsSince(ctx context.Context, since time.Time) ([]*sqlc.GetRegistrationsSinceRow, error)
+	GetEventsBetween(ctx context.Context, arg *sqlc.GetEventsBetweenParams) ([]*sqlc.Events, error)
+	GetDrawsSince(ctx context.Context, since time.Time) ([]*sqlc.GetDrawsSinceRow, error)
+	GetAnomalyCounts(ctx context.Context, since time.Time) (*sqlc.GetAnomalyCountsRow, error)
+	UpdateNotificationPreferences(ctx context.Context, arg *sqlc.UpdateNotificationPreferencesParams) (*sqlc.DigestSubscriptions, error)
+	GetNotificationRecipients(ctx context.Context, arg *sqlc.GetNotificationRecipientsParams) ([]*sqlc.DigestSubscriptions, error)
+}
+
+type IdempotencyStore interface {
+	ClaimIdempotencyKey(ctx context.Context, arg *sqlc.ClaimIdempotencyKeyParams) (int64, error)
+	GetIdempotencyKey(ctx context.Context, arg *sqlc.GetIdempotencyKeyParams) (*sqlc.IdempotencyKeys, error)
+	SaveIdempotencyKey(ctx context.Context, arg *sqlc.SaveIdempotencyKeyParams) error
+	ReleaseIdempotencyKey(ctx context.Context, arg *sqlc.ReleaseIdempotencyKeyParams) error
+	DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type AdminStore interface {
+	CreateAdmin(ctx context.Context, arg *sqlc.CreateAdminParams) (*sqlc.Admins, error)
+	GetAdmins(ctx context.Context) ([]*sqlc.Admins, error)
+	GetAdminByID(ctx context.Context, id int64) (*sqlc.Admins, error)
+	GetAdminByUsername(ctx context.Context, username string) (*sqlc.Admins, error)
+	SetAdminPassword(ctx context.Context, arg *sqlc.SetAdminPasswordParams) error
+	DeleteAdmin(ctx context.Context, id int64) error
+	CreateAdminLoginToken(ctx context.Context, arg *sqlc.CreateAdminLoginTokenParams) error
+	ConsumeAdminLoginToken(ctx context.Context, arg *sqlc.ConsumeAdminLoginTokenParams) (int64, error)
+	DeleteAdminLoginTokensBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type SessionStore interface {
+	SaveSession(ctx context.Context, arg *sqlc.SaveSessionParams) error
+	GetSession(ctx context.Context, arg *sqlc.GetSessionParams) (*sqlc.Sessions, error)
+	GetAdminSessions(ctx context.Context, now time.Time) ([]*sqlc.GetAdminSessionsRow, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteSessionByID(ctx context.Context, id int64) (int64, error)
+	DeleteOtherAdminSessions(ctx context.Context, arg *sqlc.DeleteOtherAdminSessionsParams) (int64, error)
+	DeleteSessionsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type TokenStore interface {
+	CreateToken(ctx context.Context, arg *sqlc.CreateTokenParams) (*sqlc.Tokens, error)
+	GetTokenByHash(ctx context.Context, tokenHash string) (*sqlc.Tokens, error)
+	GetTokensByAdminID(ctx context.Context, adminID int64) ([]*sqlc.Tokens, error)
+	TouchToken(ctx context.Context, arg *sqlc.TouchTokenParams) error
+	DeleteToken(ctx context.Context, arg *sqlc.DeleteTokenParams) (int64, error)
+}
+
+type BroadcastStore interface {
+	CreateBroadcast(ctx context.Context, arg *sqlc.CreateBroadcastParams) (*sqlc.Broadcasts, error)
+	GetBroadcastsByEventID(ctx context.Context, eventID int64) ([]*sqlc.Broadcasts, error)
+	UpdateBroadcast(ctx context.Context, arg *sqlc.UpdateBroadcastParams) (int64, error)
+	DeleteBroadcast(ctx context.Context, arg *sqlc.DeleteBroadcastParams) (int64, error)
+	GetDueBroadcasts(ctx context.Context, now time.Time) ([]*sqlc.Broadcasts, error)
+	MarkBroadcastQueued(ctx context.Context, id int64) (int64, error)
+	GetBroadcastByID(ctx context.Context, arg *sqlc.GetBroadcastByIDParams) (*sqlc.Broadcasts, error)
+	CreateBroadcastDelivery(ctx context.Context, arg *sqlc.CreateBroadcastDeliveryParams) (*sqlc.BroadcastDeliveries, error)
+	GetBroadcastDeliveries(ctx context.Context, broadcastID int64) ([]*sqlc.GetBroadcastDeliveriesRow, error)
+	SetBroadcastDeliveryStatus(ctx context.Context, arg *sqlc.SetBroadcastDeliveryStatusParams) error
+}
+
+type WebhookStore interface {
+	EnqueueWebhook(ctx context.Context, arg *sqlc.EnqueueWebhookParams) error
+	ClaimWebhooks(ctx context.Context, arg *sqlc.ClaimWebhooksParams) ([]*sqlc.Webhooks, error)
+	MarkWebhookSent(ctx context.Context, id int64) error
+	MarkWebhookFailed(ctx context.Context, arg *sqlc.MarkWebhookFailedParams) error
+	DeleteWebhooksBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type ConsentStore interface {
+	CreateConsentRecord(ctx context.Context, arg *sqlc.CreateConsentRecordParams) error
+	GetConsentLogByEventID(ctx context.Context, eventID int64) ([]*sqlc.ConsentLog, error)
+}
+
+type AuditStore interface {
+	CreateAuditRecord(ctx context.Context, arg *sqlc.CreateAuditRecordParams) error
+	GetAuditLogPage(ctx context.Context, arg *sqlc.GetAuditLogPageParams) ([]*sqlc.AuditLog, error)
+}
+
+// Statuses of a broadcast's message to one participant.
+const (
+	DeliveryPending = "pending"
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed"
+	// DeliveryBlocked is a message Telegram refused because the participant
+	// blocked the bot or deleted their account
+	DeliveryBlocked = "blocked"
+)
+
+type Store interface {
+	EventStore
+	UserStore
+	DrawStore
+	WaitlistStore
+	FlagStore
+	OutboxStore
+	EntryRuleStore
+	OrganizerStore
+	TranslationStore
+	ShareStore
+	PurchaseStore
+	PromoCodeStore
+	ShortLinkStore
+	TicketTypeStore
+	SeatStore
+	EventTemplateStore
+	DigestStore
+	IdempotencyStore
+	AdminStore
+	TokenStore
+	SessionStore
+	BroadcastStore
+	ConsentStore
+	AuditStore
+	WebhookStore
+
+	// InTx runs fn against a Store bound to a single transaction. The
+	// transaction is committed if fn returns nil and rolled back otherwise.
+	InTx(ctx context.Context, fn func(Store) error) error
+}
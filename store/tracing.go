@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedDB records the queries run with db in spans named after the sqlc
+// query. Spans of queries returning rows end before the rows are read.
+type tracedDB struct {
+	db sqlc.DBTX
+}
+
+func (t tracedDB) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := queryName(query)
+	return tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(name)))
+}
+
+func (t tracedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+	result, err := t.db.ExecContext(ctx, query, args...)
+	tracing.Fail(span, err)
+	return result, err
+}
+
+func (t tracedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.db.PrepareContext(ctx, query)
+}
+
+func (t tracedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	tracing.Fail(span, err)
+	return rows, err
+}
+
+func (t tracedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+	row := t.db.QueryRowContext(ctx, query, args...)
+	tracing.Fail(span, row.Err())
+	return row
+}
+
+// queryName returns the name sqlc queries start with, as in
+// "-- name: GetEvents :many", or "query" for others.
+func queryName(query string) string {
+	header, _, _ := strings.Cut(query, "\n")
+	name, ok := strings.CutPrefix(header, "-- name: ")
+	if !ok {
+		return "query"
+	}
+	name, _, _ = strings.Cut(name, " ")
+	return name
+}
@@ -0,0 +1,157 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// ErrBlocked is returned by SendMessage when the chat can't be messaged
+// anymore, because the user blocked the bot or deleted their account.
+// Retrying won't help.
+var ErrBlocked = errors.New("bot can't message the chat")
+
+// Bot is the part of the Telegram Bot API the service uses. NewBot returns
+// the real client; FakeBot runs the same flows without a bot token.
+type Bot interface {
+	// SendMessage sends text to a chat, formatted as Markdown if markdown is
+	// set
+	SendMessage(ctx context.Context, chatID int64, text string, markdown bool) error
+	// SendPhoto sends an image to a chat
+	SendPhoto(ctx context.Context, chatID int64, photo []byte) error
+	// GetUpdates delivers incoming updates until ctx is done
+	GetUpdates(ctx context.Context) (<-chan Update, error)
+	// AnswerCallback acknowledges an inline button press, showing text to
+	// the user if it isn't empty
+	AnswerCallback(ctx context.Context, callbackID, text string) error
+	// Username is the bot's t.me username, or "" for bots users can't open
+	// in Telegram
+	Username() string
+}
+
+// Update is an incoming event. Exactly one of the fields is set.
+type Update struct {
+	Message  *Message
+	Callback *Callback
+}
+
+type Message struct {
+	ChatID   int64
+	FromID   int64
+	Username string
+	// LanguageCode is the IETF tag of the sender's Telegram language, if
+	// known
+	LanguageCode string
+	Text         string
+}
+
+type Callback struct {
+	ID     string
+	ChatID int64
+	FromID int64
+	Data   string
+}
+
+type apiBot struct {
+	api *tgbotapi.BotAPI
+}
+
+// NewBot connects to the Bot API with token, checking it with getMe.
+func NewBot(token string) (Bot, error) {
+	api, err := tgbotapi.NewBotAPI(token)
+	if err != nil {
+		return nil, err
+	}
+	return &apiBot{api: api}, nil
+}
+
+func (b *apiBot) SendMessage(_ context.Context, chatID int64, text string, markdown bool) error {
+	msg := tgbotapi.NewMessage(chatID, text)
+	if markdown {
+		msg.ParseMode = tgbotapi.ModeMarkdown
+	}
+	_, err := b.api.Send(msg)
+	// The Bot API answers 403 Forbidden with a description like "Forbidden:
+	// bot was blocked by the user"
+	if err != nil && strings.HasPrefix(err.Error(), "Forbidden:") {
+		return fmt.Errorf("%w: %s", ErrBlocked, err)
+	}
+	return err
+}
+
+func (b *apiBot) SendPhoto(_ context.Context, chatID int64, photo []byte) error {
+	_, err := b.api.Send(tgbotapi.NewPhotoUpload(chatID, tgbotapi.FileBytes{Name: "photo", Bytes: photo}))
+	if err != nil && strings.HasPrefix(err.Error(), "Forbidden:") {
+		return fmt.Errorf("%w: %s", ErrBlocked, err)
+	}
+	return err
+}
+
+func (b *apiBot) GetUpdates(ctx context.Context) (<-chan Update, error) {
+	updates, err := b.api.GetUpdatesChan(tgbotapi.UpdateConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Update)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				b.api.StopReceivingUpdates()
+				return
+			case update := <-updates:
+				converted, ok := convertUpdate(update)
+				if !ok {
+					continue
+				}
+				select {
+				case out <- converted:
+				case <-ctx.Done():
+					b.api.StopReceivingUpdates()
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (b *apiBot) Username() string {
+	return b.api.Self.UserName
+}
+
+func (b *apiBot) AnswerCallback(_ context.Context, callbackID, text string) error {
+	_, err := b.api.AnswerCallbackQuery(tgbotapi.NewCallback(callbackID, text))
+	return err
+}
+
+// convertUpdate keeps the updates the service handles: private and group
+// messages and button presses.
+func convertUpdate(update tgbotapi.Update) (Update, bool) {
+	switch {
+	case update.Message != nil && update.Message.Chat != nil:
+		msg := &Message{ChatID: update.Message.Chat.ID, Text: update.Message.Text}
+		if update.Message.From != nil {
+			msg.FromID = int64(update.Message.From.ID)
+			msg.Username = update.Message.From.UserName
+			msg.LanguageCode = update.Message.From.LanguageCode
+		}
+		return Update{Message: msg}, true
+	case update.CallbackQuery != nil && update.CallbackQuery.From != nil:
+		callback := &Callback{
+			ID:     update.CallbackQuery.ID,
+			FromID: int64(update.CallbackQuery.From.ID),
+			Data:   update.CallbackQuery.Data,
+		}
+		if update.CallbackQuery.Message != nil && update.CallbackQuery.Message.Chat != nil {
+			callback.ChatID = update.CallbackQuery.Message.Chat.ID
+		}
+		return Update{Callback: callback}, true
+	}
+	return Update{}, false
+}
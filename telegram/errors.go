@@ -0,0 +1,17 @@
+package telegram
+
+import "giveaway-tool/apperr"
+
+// errorReply translates an application error into a reply for the user.
+func errorReply(err error) string {
+	switch apperr.KindOf(err) {
+	case apperr.KindConflict:
+		return "Ти вже зареєстрований!"
+	case apperr.KindNotFound:
+		return "Реєстрація на цей івент зараз недоступна."
+	case apperr.KindValidation:
+		return "Некоректні дані. Спробуй ще раз."
+	default:
+		return "Сталася помилка. Спробуй ще раз."
+	}
+}
@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SentMessage is a message the service sent through a FakeBot.
+type SentMessage struct {
+	ChatID   int64
+	Text     string
+	Markdown bool
+	// Photo is set for photos, which have no text
+	Photo  []byte
+	SentAt time.Time
+}
+
+// FakeBot is an in-memory Bot for demos and end-to-end runs without a bot
+// token. Deliver plays the part of users writing to the bot, and Sent shows
+// what the service replied.
+type FakeBot struct {
+	updates chan Update
+
+	mu       sync.Mutex
+	sent     []SentMessage
+	answered map[string]string
+}
+
+func NewFakeBot() *FakeBot {
+	return &FakeBot{
+		updates:  make(chan Update, 100),
+		answered: make(map[string]string),
+	}
+}
+
+func (b *FakeBot) SendMessage(_ context.Context, chatID int64, text string, markdown bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sent = append(b.sent, SentMessage{ChatID: chatID, Text: text, Markdown: markdown, SentAt: time.Now()})
+	return nil
+}
+
+func (b *FakeBot) SendPhoto(_ context.Context, chatID int64, photo []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sent = append(b.sent, SentMessage{ChatID: chatID, Photo: photo, SentAt: time.Now()})
+	return nil
+}
+
+func (b *FakeBot) GetUpdates(context.Context) (<-chan Update, error) {
+	return b.updates, nil
+}
+
+// Username is "", since the fake bot can't be opened in Telegram.
+func (b *FakeBot) Username() string {
+	return ""
+}
+
+func (b *FakeBot) AnswerCallback(_ context.Context, callbackID, text string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.answered[callbackID] = text
+	return nil
+}
+
+// Deliver queues an update for the service, waiting while the queue is
+// full.
+func (b *FakeBot) Deliver(ctx context.Context, update Update) error {
+	select {
+	case b.updates <- update:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Write delivers a text message from a user in their private chat, whose
+// ID Telegram sets to the user's ID.
+func (b *FakeBot) Write(ctx context.Context, userID int64, username, text string) error {
+	return b.Deliver(ctx, Update{Message: &Message{ChatID: userID, FromID: userID, Username: username, Text: text}})
+}
+
+// Sent returns the messages sent to chatID, oldest first.
+func (b *FakeBot) Sent(chatID int64) []SentMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var sent []SentMessage
+	for _, msg := range b.sent {
+		if msg.ChatID == chatID {
+			sent = append(sent, msg)
+		}
+	}
+	return sent
+}
+
+// Answered reports the text the service answered a button press with.
+func (b *FakeBot) Answered(callbackID string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	text, ok := b.answered[callbackID]
+	return text, ok
+}
+
+// Chats lists the chats the service has sent messages to.
+func (b *FakeBot) Chats() []int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var chats []int64
+	for _, msg := range b.sent {
+		chats = append(chats, msg.ChatID)
+	}
+	slices.Sort(chats)
+	return slices.Compact(chats)
+}
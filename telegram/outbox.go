@@ -0,0 +1,169 @@
+package telegram
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/notify"
+	"giveaway-tool/sms"
+	"giveaway-tool/store"
+)
+
+const (
+	outboxInterval  = 2 * time.Second
+	outboxBatchSize = 20
+	// outboxLease keeps a claimed message hidden from other senders while it
+	// is being delivered. If the process dies mid-send the message becomes
+	// pending again once the lease runs out.
+	outboxLease       = 60
+	outboxMaxAttempts = 5
+)
+
+// deliverOutbox periodically sends pending outbox messages until ctx is done.
+func (s *Service) deliverOutbox(ctx context.Context) {
+	ticker := time.NewTicker(outboxInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.sendOutboxBatch(ctx); err != nil {
+				s.logger.LogAttrs(ctx, slog.LevelError, "Failed to deliver outbox messages", slog.Any("error", err))
+				notify.JobFailed("надсилання повідомлень бота", err)
+			}
+		}
+	}
+}
+
+func (s *Service) sendOutboxBatch(ctx context.Context) error {
+	messages, err := s.store.ClaimOutboxMessages(ctx, &sqlc.ClaimOutboxMessagesParams{
+		LeaseSeconds: outboxLease,
+		BatchSize:    outboxBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		logger := s.logger.With(slog.Int64("outbox_id", message.ID), slog.Int64("chat_id", message.ChatID))
+
+		if err := s.bot.SendMessage(ctx, message.ChatID, message.Text, message.Markdown); err != nil {
+			attempt := message.Attempts + 1
+			blocked := errors.Is(err, ErrBlocked)
+			giveUp := blocked || attempt >= outboxMaxAttempts
+			logger.LogAttrs(ctx, slog.LevelWarn, "Failed to send outbox message",
+				slog.Int("attempt", int(attempt)), slog.Bool("give_up", giveUp), slog.Any("error", err))
+
+			// Back off exponentially: 10s, 20s, 40s, ...
+			if err := s.store.MarkOutboxMessageFailed(ctx, &sqlc.MarkOutboxMessageFailedParams{
+				ID:                message.ID,
+				LastError:         sql.NullString{String: err.Error(), Valid: true},
+				RetryAfterSeconds: 10 << (attempt - 1),
+				GiveUp:            giveUp,
+			}); err != nil {
+				return err
+			}
+			if blocked {
+				if err := s.markBlocked(ctx, message.ChatID); err != nil {
+					return err
+				}
+			}
+			if giveUp {
+				status := store.DeliveryFailed
+				if blocked {
+					status = store.DeliveryBlocked
+				}
+				if err := s.recordDelivery(ctx, message, status, err); err != nil {
+					return err
+				}
+				if err := s.sendSMSFallback(ctx, logger, message); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := s.store.MarkOutboxMessageSent(ctx, message.ID); err != nil {
+			return err
+		}
+		if err := s.recordDelivery(ctx, message, store.DeliverySent, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markBlocked flags the participants registered from a chat that blocked
+// the bot, which counts as opting out of its messages.
+func (s *Service) markBlocked(ctx context.Context, chatID int64) error {
+	return s.store.InTx(ctx, func(tx store.Store) error {
+		users, err := tx.MarkChatBlocked(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if err := consent.Record(ctx, tx, user, consent.OptedOut, consent.SourceTelegram); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// sendSMSFallback texts a message Telegram couldn't deliver to the
+// participant it was enqueued for, if they gave a phone number and an SMS
+// provider is configured. The SMS is tried once; its failure is only
+// logged, since the message has already been given up on.
+func (s *Service) sendSMSFallback(ctx context.Context, logger *slog.Logger, message *sqlc.Outbox) error {
+	if s.sms == nil || !message.SmsUserID.Valid {
+		return nil
+	}
+
+	user, err := s.store.GetUserByID(ctx, message.SmsUserID.Int64)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Phone == "" {
+		return nil
+	}
+	phone, err := sms.Normalize(user.Phone)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "Can't text participant", slog.Int64("user_id", user.ID), slog.Any("error", err))
+		return nil
+	}
+
+	if err := s.sms.Send(ctx, phone, message.Text); err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "Failed to send SMS fallback", slog.Int64("user_id", user.ID), slog.Any("error", err))
+		return nil
+	}
+	logger.LogAttrs(ctx, slog.LevelInfo, "Sent SMS fallback", slog.Int64("user_id", user.ID))
+	return nil
+}
+
+// recordDelivery stores the result of a broadcast's message, if message
+// belongs to one.
+func (s *Service) recordDelivery(ctx context.Context, message *sqlc.Outbox, status string, sendErr error) error {
+	if !message.DeliveryID.Valid {
+		return nil
+	}
+	var errText sql.NullString
+	if sendErr != nil {
+		errText = sql.NullString{String: sendErr.Error(), Valid: true}
+	}
+	return s.store.SetBroadcastDeliveryStatus(ctx, &sqlc.SetBroadcastDeliveryStatusParams{
+		ID:     message.DeliveryID.Int64,
+		Status: status,
+		Error:  errText,
+	})
+}
@@ -2,24 +2,50 @@ package telegram
 
 import (
 	"context"
+	cryptoRand "crypto/rand"
 	"database/sql"
+	"errors"
 	"fmt"
+	"giveaway-tool/apperr"
+	"giveaway-tool/capacity"
 	"giveaway-tool/config"
+	"giveaway-tool/consent"
 	"giveaway-tool/database/sqlc"
+	"giveaway-tool/i18n"
+	"giveaway-tool/lifecycle"
+	"giveaway-tool/logging"
+	"giveaway-tool/magiclink"
+	"giveaway-tool/markdown"
+	"giveaway-tool/notify"
+	"giveaway-tool/promo"
+	"giveaway-tool/registration"
+	"giveaway-tool/seating"
+	"giveaway-tool/sms"
+	"giveaway-tool/store"
+	"giveaway-tool/tickettype"
+	"giveaway-tool/tracing"
+	"giveaway-tool/waitlist"
 	"log/slog"
+	"maps"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-const REGISTERED_ERROR = "pq: duplicate key value violates unique constraint \"unique_tg_event_id\""
+// updateTimeout bounds how long a single update may spend on database work.
+const updateTimeout = 15 * time.Second
 
 type State int64
 
 const (
 	_ State = iota
 	Started
+	WaitingForTicketType
 	WaitingForName
 	Done
 )
@@ -29,112 +55,785 @@ type StateKey struct {
 	EventID int64
 }
 
-type Service struct {
-	mu             sync.Mutex
-	logger         *slog.Logger
-	queries        *sqlc.Queries
-	bot            *tgbotapi.BotAPI
-	welcomeMessage string
-	state          map[StateKey]State
-}
+// startPrefix starts the payload of links that open the bot for an event,
+// as in t.me/<bot>?start=event_42.
+const startPrefix = "event_"
 
-func Start(ctx context.Context, logger *slog.Logger, db *sql.DB) {
-	queries := sqlc.New(db)
-	bot, err := tgbotapi.NewBotAPI(os.Getenv("TELEGRAM_BOT_TOKEN"))
+// pickTTL is how long a chat keeps registering for the event a start link
+// named before it is back on the current event.
+const pickTTL = 24 * time.Hour
 
-	if err != nil {
-		logger.LogAttrs(nil, slog.LevelError, "Failed to create Telegram bot", slog.Any("error", err))
-		return
-	}
+// pick is the event a chat opened the bot for with a start link.
+type pick struct {
+	eventID int64
+	at      time.Time
+}
 
-	currentEventID := config.GetCurrentEventID()
+type Service struct {
+	mu     sync.Mutex
+	logger *slog.Logger
+	store  store.Store
+	bot    Bot
+	state  map[StateKey]State
+	// promo holds the promo codes sent before registering, which are used
+	// when the participant sends their name
+	promo map[StateKey]string
+	// ticketType holds the ticket types picked for events with types
+	ticketType map[StateKey]int64
+	// picks holds the events chats opened the bot for; other chats
+	// register for the current event
+	picks map[int64]pick
+	links *magiclink.Signer
+	// publicURL is where share links point; /share is disabled when it is
+	// empty
+	publicURL string
+	// sms texts important messages Telegram couldn't deliver; there is no
+	// fallback when it is nil
+	sms sms.Sender
+	// running counts the loops and the updates being handled, for Wait
+	running sync.WaitGroup
+}
 
+// Start receives and handles updates from bot until ctx is done. Call Wait
+// on the returned Service to let the updates being handled finish.
+func Start(ctx context.Context, logger *slog.Logger, st store.Store, bot Bot) *Service {
 	svc := &Service{
-		logger:  logger,
-		queries: queries,
-		bot:     bot,
-		state:   make(map[StateKey]State),
-	}
+		logger: logger,
+		store:  st,
+		bot:    bot,
+		state:  make(map[StateKey]State),
+		promo:  make(map[StateKey]string),
+		links:  magiclink.FromEnv(),
 
-	event, err := svc.queries.GetEventByID(ctx, currentEventID)
-	if err != nil {
-		logger.LogAttrs(ctx, slog.LevelError, "Failed to get event by ID", slog.Any("error", err))
-	}
+		ticketType: make(map[StateKey]int64),
+		picks:      make(map[int64]pick),
 
-	svc.welcomeMessage = fmt.Sprintf("Привіт! Я бот для реєстрації на івент ФІТКІ \"%s\".\n\nВведи своє прізвище та ім'я, щоб зареєструватися.", event.Name)
+		publicURL: strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/"),
+		sms:       sms.FromEnv(),
+	}
 
-	go svc.run(ctx)
+	svc.running.Add(3)
+	go func() {
+		defer svc.running.Done()
+		svc.run(ctx)
+	}()
+	go func() {
+		defer svc.running.Done()
+		svc.deliverOutbox(ctx)
+	}()
+	go func() {
+		defer svc.running.Done()
+		svc.pruneStates(ctx)
+	}()
 
 	svc.logger.LogAttrs(ctx, slog.LevelInfo, "Telegram service started")
+	return svc
+}
+
+// Wait blocks until the Service has stopped after its context is done and
+// the updates it was handling are handled, or until ctx is done.
+func (s *Service) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (s *Service) run(ctx context.Context) {
-	updates, err := s.bot.GetUpdatesChan(tgbotapi.UpdateConfig{})
+	updates, err := s.bot.GetUpdates(ctx)
 	if err != nil {
-		s.logger.LogAttrs(nil, slog.LevelError, "Failed to get updates channel", slog.Any("error", err))
+		s.logger.LogAttrs(ctx, slog.LevelError, "Failed to get updates channel", slog.Any("error", err))
 		return
 	}
 
-	for update := range updates {
-		go s.processUpdate(ctx, update)
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.LogAttrs(ctx, slog.LevelInfo, "Telegram service stopped")
+			return
+		case update, ok := <-updates:
+			if !ok {
+				s.logger.LogAttrs(ctx, slog.LevelInfo, "Telegram service stopped")
+				return
+			}
+			// Updates being handled aren't cut off when the service stops,
+			// updateTimeout bounds them instead
+			s.running.Add(1)
+			go func() {
+				defer s.running.Done()
+				s.processUpdate(context.WithoutCancel(ctx), update)
+			}()
+		}
 	}
 }
 
-func (s *Service) processUpdate(ctx context.Context, update tgbotapi.Update) {
+func (s *Service) processUpdate(ctx context.Context, update Update) {
+	// There are no inline buttons yet; answer presses of stale ones so the
+	// client stops waiting
+	if update.Callback != nil {
+		if err := s.bot.AnswerCallback(ctx, update.Callback.ID, ""); err != nil {
+			s.logger.LogAttrs(ctx, slog.LevelError, "Failed to answer callback", slog.Any("error", err))
+		}
+		return
+	}
 	if update.Message == nil {
 		return
 	}
 
-	state := s.getState(update.Message.Chat.ID)
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	ctx = logging.WithLogger(ctx, s.logger.With(slog.Int64("chat_id", update.Message.ChatID)))
+	ctx, span := tracing.Start(ctx, "telegram update",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.Int64("telegram.chat_id", update.Message.ChatID)))
+	defer span.End()
+
+	// Writing to the bot again means the user unblocked it
+	if err := s.clearBlocked(ctx, update.Message.FromID); err != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to clear blocked flag", slog.Any("error", err))
+	}
+
+	// Admins need their chat ID to subscribe to the weekly digest
+	if update.Message.Text == "/chatid" {
+		if err := s.bot.SendMessage(ctx, update.Message.ChatID, fmt.Sprintf("ID цього чату: %d", update.Message.ChatID), false); err != nil {
+			logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to send message", slog.Any("error", err))
+		}
+		return
+	}
+
+	// Links from event pages and posters name the event to register for
+	if eventID := startEventID(update.Message.Text); eventID != 0 {
+		if reply := s.pickEvent(ctx, update.Message.ChatID, eventID); reply != "" {
+			if err := s.bot.SendMessage(ctx, update.Message.ChatID, reply, true); err != nil {
+				logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to send message", slog.Any("error", err))
+			}
+			return
+		}
+	}
+	ctx = logging.With(ctx, slog.Int64("event_id", s.eventID(update.Message.ChatID)))
+
+	state := s.getState(update.Message.ChatID)
 
-	s.logger.LogAttrs(ctx, slog.LevelInfo, "Received message", slog.Any("message", update.Message.Text))
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "Received message", slog.Any("message", update.Message.Text))
 
-	var msg tgbotapi.MessageConfig
+	var reply string
+
+	if fields := strings.Fields(update.Message.Text); len(fields) > 0 && fields[0] == "/promo" {
+		reply = s.promoReply(ctx, update.Message, state, strings.Join(fields[1:], ""))
+		if err := s.bot.SendMessage(ctx, update.Message.ChatID, reply, true); err != nil {
+			logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to send message", slog.Any("error", err))
+		}
+		return
+	}
 
 	switch state {
 	case Started:
-		msg = tgbotapi.NewMessage(update.Message.Chat.ID, s.welcomeMessage)
-		s.setState(update.Message.Chat.ID, WaitingForName)
+		if closed := s.closedEventReply(ctx, update.Message.ChatID); closed != "" {
+			reply = closed
+			break
+		}
+		if paid := s.paidEventReply(ctx, update.Message.ChatID); paid != "" {
+			reply = paid
+			break
+		}
+		if full := s.fullEventReply(ctx, update.Message); full != "" {
+			reply = full
+			break
+		}
+		reply = s.startRegistration(ctx, update.Message)
+	case WaitingForTicketType:
+		reply = s.ticketTypeReply(ctx, update.Message)
 	case WaitingForName:
-		if update.Message.Text == "/start" {
-			msg = tgbotapi.NewMessage(update.Message.Chat.ID, "Вже чекаю на твоє ім'я!")
+		code := s.getPromo(update.Message.ChatID)
+		if paid := s.paidEventReply(ctx, update.Message.ChatID); paid != "" {
+			reply = paid
+		} else if isStart(update.Message.Text) {
+			reply = "Вже чекаю на твоє ім'я!"
+		} else if user, err := s.register(ctx, update.Message, code); errors.Is(err, promo.ErrInvalid) {
+			// The code ran out since it was accepted; registering without
+			// it is up to the participant
+			s.setPromo(update.Message.ChatID, "")
+			reply = "На жаль, промокод уже недійсний або вичерпаний. Надішли своє ім'я ще раз, щоб зареєструватися без нього."
+		} else if errors.Is(err, lifecycle.ErrNotOpen) {
+			// Registration was closed since the user started
+			s.setPromo(update.Message.ChatID, "")
+			s.setTicketType(update.Message.ChatID, 0)
+			s.setState(update.Message.ChatID, Started)
+			reply = s.closedEventReply(ctx, update.Message.ChatID)
+		} else if errors.Is(err, capacity.ErrFull) {
+			// The event is full, or the last places were taken since the
+			// user started, so they queue for one with the type they picked
+			ticketTypeID := s.getTicketType(update.Message.ChatID)
+			s.setPromo(update.Message.ChatID, "")
+			s.setTicketType(update.Message.ChatID, 0)
+			s.setState(update.Message.ChatID, Started)
+			reply = s.joinWaitlist(ctx, update.Message, ticketTypeID)
+		} else if errors.Is(err, tickettype.ErrSoldOut) || errors.Is(err, tickettype.ErrInvalid) || errors.Is(err, tickettype.ErrRequired) {
+			// The type filled up or changed since it was picked, so the
+			// user picks again
+			s.setTicketType(update.Message.ChatID, 0)
+			reply = "На жаль, квитки обраного типу вже недоступні. " + s.startRegistration(ctx, update.Message)
 		} else {
-			if _, err := s.queries.CreateUser(ctx, &sqlc.CreateUserParams{
-				TgID:     int64(update.Message.From.ID),
-				Name:     update.Message.Text,
-				Username: update.Message.From.UserName,
-				EventID:  config.GetCurrentEventID(),
-			}); err != nil {
-				s.logger.LogAttrs(ctx, slog.LevelError, "Failed to create user", slog.Any("error", err))
-				if err.Error() == REGISTERED_ERROR {
-					msg = tgbotapi.NewMessage(update.Message.Chat.ID, "Ти вже зареєстрований!")
-				} else {
-					msg = tgbotapi.NewMessage(update.Message.Chat.ID, "Сталася помилка. Спробуй ще раз.")
-				}
+			if err != nil {
+				err = apperr.FromDB(err)
+				logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to create user", slog.Any("error", err))
+				reply = errorReply(err)
 			} else {
-				msg = tgbotapi.NewMessage(update.Message.Chat.ID, "Дякую! Ти успішно зареєстрований.")
-				s.setState(update.Message.Chat.ID, Done)
+				notify.Registered(ctx, s.store, user)
+				reply = fmt.Sprintf("Дякую! Ти успішно зареєстрований.\n\nТвій номер квитка: №%d\nКод для входу: %s", user.TicketNumber, user.CheckInCode) + s.seatText(ctx, user.ID) + s.selfServiceText(user.ID) + s.shareHint(ctx, user.EventID)
+				if code != "" {
+					reply = "Промокод застосовано! " + reply
+				}
+				s.setState(update.Message.ChatID, Done)
 			}
-			s.setState(update.Message.Chat.ID, Done)
+			s.setPromo(update.Message.ChatID, "")
+			s.setTicketType(update.Message.ChatID, 0)
+			s.setState(update.Message.ChatID, Done)
 		}
 	case Done:
-		msg = tgbotapi.NewMessage(update.Message.Chat.ID, "Ти вже зареєстрований!")
+		user, err := s.store.GetUserByTgIDAndEventID(ctx, &sqlc.GetUserByTgIDAndEventIDParams{
+			EventID: s.eventID(update.Message.ChatID),
+			TgID:    update.Message.FromID,
+		})
+		if err == nil && update.Message.Text == "/share" {
+			reply = s.shareReply(ctx, user, update.Message.LanguageCode)
+			break
+		}
+
+		text := "Ти вже зареєстрований!"
+		// Send a fresh link, since the one from registration may have expired
+		if err == nil {
+			text += s.seatText(ctx, user.ID) + s.selfServiceText(user.ID)
+		}
+		reply = text
 	}
-	msg.ParseMode = tgbotapi.ModeMarkdown
-	if _, err := s.bot.Send(msg); err != nil {
-		s.logger.LogAttrs(ctx, slog.LevelError, "Failed to send message", slog.Any("error", err))
+	if err := s.bot.SendMessage(ctx, update.Message.ChatID, reply, true); err != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to send message", slog.Any("error", err))
 	}
 	return
 }
 
+// register creates the participant who sent their name, recording the
+// consent they gave by registering and using their ticket type and promo
+// code, if any.
+func (s *Service) register(ctx context.Context, message *Message, code string) (*sqlc.Users, error) {
+	ticketTypeID := s.getTicketType(message.ChatID)
+
+	var user *sqlc.Users
+	err := s.store.InTx(ctx, func(tx store.Store) error {
+		event, err := tx.GetEventByID(ctx, s.eventID(message.ChatID))
+		if err != nil {
+			return err
+		}
+		if err := lifecycle.CheckOpen(event); err != nil {
+			return err
+		}
+		ticketType, err := registration.Reserve(ctx, tx, event.ID, ticketTypeID)
+		if err != nil {
+			return err
+		}
+		price := tickettype.Price(event, ticketType)
+
+		var promoCode *sqlc.PromoCodes
+		if code != "" {
+			if promoCode, err = promo.Use(ctx, tx, event.ID, price, code); err != nil {
+				return err
+			}
+		}
+
+		user, err = registration.Create(ctx, tx, &sqlc.CreateUserParams{
+			TgID:     sql.NullInt64{Int64: message.FromID, Valid: true},
+			Name:     message.Text,
+			Username: message.Username,
+			EventID:  event.ID,
+		}, ticketType, consent.SourceTelegram)
+		if err != nil || promoCode == nil {
+			return err
+		}
+		return promo.Redeem(ctx, tx, promoCode, user, price-promo.Price(promoCode, price))
+	})
+	return user, err
+}
+
+// clearBlocked clears the flag of participants registered from a chat that
+// wrote to the bot again, which counts as opting back in.
+func (s *Service) clearBlocked(ctx context.Context, chatID int64) error {
+	return s.store.InTx(ctx, func(tx store.Store) error {
+		users, err := tx.ClearChatBlocked(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if err := consent.Record(ctx, tx, user, consent.OptedIn, consent.SourceTelegram); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// event returns the event with its name and description in the language
+// of the user's Telegram app, falling back to the original text.
+func (s *Service) event(ctx context.Context, eventID int64, languageCode string) (*sqlc.Events, error) {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	lang := i18n.Normalize(languageCode)
+	if lang == "" || lang == i18n.Default {
+		return event, nil
+	}
+	translation, err := s.store.GetEventTranslation(ctx, &sqlc.GetEventTranslationParams{EventID: eventID, Language: lang})
+	if errors.Is(err, sql.ErrNoRows) {
+		return event, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Translate(event, translation), nil
+}
+
+// welcomeMessage introduces the event in the user's language and asks them
+// for what prompt says is needed to register.
+func (s *Service) welcomeMessage(ctx context.Context, eventID int64, languageCode, prompt string) string {
+	event, err := s.event(ctx, eventID, languageCode)
+	if err != nil {
+		err = apperr.FromDB(err)
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get event", slog.Any("error", err))
+		return errorReply(err)
+	}
+	text := fmt.Sprintf("Привіт! Я бот для реєстрації на івент ФІТКІ \"%s\".", markdown.EscapeTelegram(event.Name))
+	if event.Description.String != "" {
+		text += "\n\n" + markdown.Telegram(event.Description.String)
+	}
+	return text + "\n\n" + prompt + "\n\n" + markdown.EscapeTelegram(consent.Text)
+}
+
+// startRegistration welcomes the user and asks them to pick a ticket type
+// for events with types, or else to send their name.
+func (s *Service) startRegistration(ctx context.Context, message *Message) string {
+	eventID := s.eventID(message.ChatID)
+	prompt, err := s.ticketTypesPrompt(ctx, eventID)
+	if err != nil {
+		err = apperr.FromDB(err)
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get ticket types", slog.Any("error", err))
+		return errorReply(err)
+	}
+	s.sendEventImage(ctx, message.ChatID)
+	if prompt == "" {
+		s.setState(message.ChatID, WaitingForName)
+		return s.welcomeMessage(ctx, eventID, message.LanguageCode, "Введи своє прізвище та ім'я, щоб зареєструватися.")
+	}
+	s.setState(message.ChatID, WaitingForTicketType)
+	return s.welcomeMessage(ctx, eventID, message.LanguageCode, prompt)
+}
+
+// sendEventImage sends the chat's event image ahead of the welcome
+// message, if the event has one. The welcome goes out either way.
+func (s *Service) sendEventImage(ctx context.Context, chatID int64) {
+	image, err := s.store.GetEventImage(ctx, s.eventID(chatID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	if err == nil {
+		err = s.bot.SendPhoto(ctx, chatID, image.Data)
+	}
+	if err != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to send event image", slog.Any("error", err))
+	}
+}
+
+// ticketTypesPrompt lists the event's ticket types for the user to pick one
+// by its number, or returns "" for events without types.
+func (s *Service) ticketTypesPrompt(ctx context.Context, eventID int64) (string, error) {
+	ticketTypes, err := s.store.GetTicketTypesByEventID(ctx, eventID)
+	if err != nil || len(ticketTypes) == 0 {
+		return "", err
+	}
+
+	text := "Обери тип квитка й надішли його номер:"
+	for i, ticketType := range ticketTypes {
+		price := "безкоштовно"
+		if ticketType.Price > 0 {
+			price = fmt.Sprintf("%d.%02d грн, оплата на сайті", ticketType.Price/100, ticketType.Price%100)
+		}
+		text += fmt.Sprintf("\n%d. %s (%s)", i+1, markdown.EscapeTelegram(ticketType.Name), price)
+	}
+	return text, nil
+}
+
+// ticketTypeReply handles the number of the ticket type the user picked.
+// Types that are paid even with the user's promo code are sold on the
+// website.
+func (s *Service) ticketTypeReply(ctx context.Context, message *Message) string {
+	event, err := s.store.GetEventByID(ctx, s.eventID(message.ChatID))
+	if err != nil {
+		err = apperr.FromDB(err)
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get event", slog.Any("error", err))
+		return errorReply(err)
+	}
+	ticketTypes, err := s.store.GetTicketTypesByEventID(ctx, event.ID)
+	if err != nil {
+		err = apperr.FromDB(err)
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get ticket types", slog.Any("error", err))
+		return errorReply(err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(message.Text))
+	if err != nil || n < 1 || n > len(ticketTypes) {
+		return fmt.Sprintf("Надішли номер типу квитка від 1 до %d.", len(ticketTypes))
+	}
+	ticketType := ticketTypes[n-1]
+
+	price := ticketType.Price
+	code := s.getPromo(message.ChatID)
+	if code != "" {
+		promoCode, err := promo.Get(ctx, s.store, event.ID, price, code)
+		if err != nil {
+			s.setPromo(message.ChatID, "")
+			return promoErrorReply(ctx, err) + " Обери тип квитка ще раз або зареєструйся без промокоду."
+		}
+		price = promo.Price(promoCode, price)
+	}
+	if price > 0 {
+		text := fmt.Sprintf("Квиток \"%s\" платний, тому його можна придбати лише на сайті.", markdown.EscapeTelegram(ticketType.Name))
+		if s.publicURL != "" {
+			text += fmt.Sprintf(" Зареєструватися й оплатити: %s/events/%d/register", s.publicURL, event.ID)
+			if code != "" {
+				text += "?promo=" + promo.Normalize(code)
+			}
+		}
+		return text + "\n\nАбо надішли номер іншого типу квитка."
+	}
+
+	if ticketType.Capacity > 0 {
+		left, err := tickettype.Left(ctx, s.store, ticketType)
+		if err != nil {
+			err = apperr.FromDB(err)
+			logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to count ticket type", slog.Any("error", err))
+			return errorReply(err)
+		}
+		if left <= 0 {
+			return fmt.Sprintf("Квитки \"%s\" закінчилися. Надішли номер іншого типу квитка.", markdown.EscapeTelegram(ticketType.Name))
+		}
+	}
+
+	s.setTicketType(message.ChatID, ticketType.ID)
+	s.setState(message.ChatID, WaitingForName)
+	return fmt.Sprintf("Обрано квиток \"%s\". Введи своє прізвище та ім'я, щоб зареєструватися.", markdown.EscapeTelegram(ticketType.Name))
+}
+
+// closedEventReply tells users that the chat's event doesn't take
+// registrations, or returns "" if it does.
+func (s *Service) closedEventReply(ctx context.Context, chatID int64) string {
+	event, err := s.store.GetEventByID(ctx, s.eventID(chatID))
+	if err != nil || lifecycle.CheckOpen(event) == nil {
+		return ""
+	}
+	name := markdown.EscapeTelegram(event.Name)
+	switch {
+	case event.Status == lifecycle.Draft:
+		return "Зараз немає івентів, на які відкрита реєстрація."
+	case event.Status == lifecycle.Closed || lifecycle.WindowClosed(event):
+		return fmt.Sprintf("Реєстрацію на \"%s\" закрито.", name)
+	case event.Status == lifecycle.RegistrationOpen && lifecycle.NotYetOpen(event):
+		return fmt.Sprintf("Реєстрація на \"%s\" відкриється %s. Повертайся тоді!", name, event.RegistrationOpensAt.Time.Format("02.01.2006 о 15:04"))
+	}
+	return fmt.Sprintf("Реєстрація на \"%s\" ще не відкрита. Спробуй пізніше!", name)
+}
+
+// fullEventReply handles users starting registration for an event with no
+// places left, asking for their name to put them on the waitlist,
+// or returns "" if the event has places. Events with ticket types return ""
+// too, so the user picks the type they'll be registered with first.
+func (s *Service) fullEventReply(ctx context.Context, message *Message) string {
+	event, err := s.store.GetEventByID(ctx, s.eventID(message.ChatID))
+	if err != nil || event.Capacity == 0 {
+		return ""
+	}
+	left, err := capacity.Left(ctx, s.store, event)
+	if err != nil || left > 0 {
+		return ""
+	}
+
+	// People promoted from the waitlist are registered without the bot
+	// knowing, so they'd otherwise be queued again
+	if _, err := s.store.GetUserByTgIDAndEventID(ctx, &sqlc.GetUserByTgIDAndEventIDParams{
+		EventID: event.ID,
+		TgID:    message.FromID,
+	}); err == nil {
+		s.setState(message.ChatID, Done)
+		return "Ти вже зареєстрований!"
+	}
+
+	name := markdown.EscapeTelegram(event.Name)
+	entry, err := s.store.GetWaitlistEntryByTgID(ctx, &sqlc.GetWaitlistEntryByTgIDParams{
+		TgID:    sql.NullInt64{Int64: message.FromID, Valid: true},
+		EventID: event.ID,
+	})
+	if err == nil {
+		position, err := waitlist.Position(ctx, s.store, entry)
+		if err != nil {
+			err = apperr.FromDB(err)
+			logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get waitlist position", slog.Any("error", err))
+			return errorReply(err)
+		}
+		return fmt.Sprintf("Ти вже в листі очікування на \"%s\": №%d у черзі. Щойно звільниться місце, я тебе зареєструю й повідомлю.", name, position)
+	}
+	if ticketTypes, err := s.store.GetTicketTypesByEventID(ctx, event.ID); err != nil || len(ticketTypes) > 0 {
+		return ""
+	}
+
+	s.setState(message.ChatID, WaitingForName)
+	return fmt.Sprintf("На жаль, усі місця на \"%s\" вже зайняті. Введи своє прізвище та ім'я, щоб стати в лист очікування: щойно звільниться місце, я тебе зареєструю.", name)
+}
+
+// joinWaitlist puts the user who sent their name on the chat's event's
+// waitlist with the ticket type they picked, if any, and tells them their
+// position.
+func (s *Service) joinWaitlist(ctx context.Context, message *Message, ticketTypeID int64) string {
+	var position int32
+	err := s.store.InTx(ctx, func(tx store.Store) error {
+		var err error
+		_, position, err = waitlist.Join(ctx, tx, &sqlc.AddToWaitlistParams{
+			EventID:       s.eventID(message.ChatID),
+			Name:          message.Text,
+			Username:      message.Username,
+			TgID:          sql.NullInt64{Int64: message.FromID, Valid: true},
+			TicketTypeID:  sql.NullInt64{Int64: ticketTypeID, Valid: ticketTypeID != 0},
+			ConsentSource: consent.SourceTelegram,
+		})
+		return err
+	})
+	if err != nil {
+		err = apperr.FromDB(err)
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to join waitlist", slog.Any("error", err))
+		return errorReply(err)
+	}
+	return fmt.Sprintf("Усі місця вже зайняті, тож я додав тебе до листа очікування: ти №%d у черзі. Щойно звільниться місце, я тебе зареєструю й повідомлю.", position)
+}
+
+// paidEventReply returns the reply sending users to the website when the
+// chat's event has paid tickets, which the bot can't sell, or "" if
+// registration is free or the user sent a code for a free ticket.
+func (s *Service) paidEventReply(ctx context.Context, chatID int64) string {
+	event, err := s.store.GetEventByID(ctx, s.eventID(chatID))
+	if err != nil || event.TicketPrice == 0 || s.getPromo(chatID) != "" {
+		return ""
+	}
+	// Events with ticket types have a price per type, asked about when the
+	// user picks one
+	if ticketTypes, err := s.store.GetTicketTypesByEventID(ctx, event.ID); err != nil || len(ticketTypes) > 0 {
+		return ""
+	}
+	text := fmt.Sprintf("Участь у \"%s\" платна, тому реєстрація відкрита лише на сайті.", markdown.EscapeTelegram(event.Name))
+	if s.publicURL != "" {
+		text += fmt.Sprintf("\n\nЗареєструватися й оплатити квиток: %s/events/%d/register", s.publicURL, event.ID)
+	}
+	return text + "\n\nМаєш промокод на безкоштовну участь? Надішли /promo КОД."
+}
+
+// promoReply handles /promo. Registered participants get the code's
+// entries straight away; codes sent before registering are kept until the
+// user sends their name, unless they only work on the website.
+func (s *Service) promoReply(ctx context.Context, message *Message, state State, code string) string {
+	if code == "" {
+		return "Надішли промокод разом із командою, наприклад: /promo КОД"
+	}
+	event, err := s.store.GetEventByID(ctx, s.eventID(message.ChatID))
+	if err != nil {
+		err = apperr.FromDB(err)
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get event", slog.Any("error", err))
+		return errorReply(err)
+	}
+
+	if state == Done {
+		return s.redeemEntries(ctx, message, event, code)
+	}
+
+	price, picked, err := s.ticketPrice(ctx, event, message.ChatID)
+	if err != nil {
+		err = apperr.FromDB(err)
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get ticket types", slog.Any("error", err))
+		return errorReply(err)
+	}
+	promoCode, err := promo.Get(ctx, s.store, event.ID, price, code)
+	if err != nil {
+		return promoErrorReply(ctx, err)
+	}
+	if picked && price > 0 && promo.Price(promoCode, price) > 0 {
+		text := "Цей промокод діє під час оплати квитка на сайті."
+		if s.publicURL != "" {
+			text += fmt.Sprintf(" Зареєструватися з ним: %s/events/%d/register?promo=%s", s.publicURL, event.ID, promoCode.Code)
+		}
+		return text
+	}
+
+	s.setPromo(message.ChatID, promoCode.Code)
+	switch state {
+	case Started:
+		return "Промокод прийнято! " + s.startRegistration(ctx, message)
+	case WaitingForTicketType:
+		return "Промокод прийнято! Надішли номер типу квитка."
+	}
+	return "Промокод прийнято! Введи своє прізвище та ім'я, щоб зареєструватися."
+}
+
+// ticketPrice returns the price of the ticket the user is registering for.
+// For events with ticket types it is the price of the type they picked;
+// until they pick one, picked is false and the price is the highest of the
+// types, so codes for tickets are kept if some type is paid.
+func (s *Service) ticketPrice(ctx context.Context, event *sqlc.Events, chatID int64) (price int32, picked bool, err error) {
+	ticketTypes, err := s.store.GetTicketTypesByEventID(ctx, event.ID)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(ticketTypes) == 0 {
+		return event.TicketPrice, true, nil
+	}
+
+	ticketTypeID := s.getTicketType(chatID)
+	for _, ticketType := range ticketTypes {
+		if ticketType.ID == ticketTypeID {
+			return ticketType.Price, true, nil
+		}
+		price = max(price, ticketType.Price)
+	}
+	return price, false, nil
+}
+
+// errTicketCode rejects codes for tickets sent by participants who are
+// already registered.
+var errTicketCode = errors.New("promo code is for tickets")
+
+// redeemEntries applies a code for extra entries to the registered
+// participant who sent it.
+func (s *Service) redeemEntries(ctx context.Context, message *Message, event *sqlc.Events, code string) string {
+	user, err := s.store.GetUserByTgIDAndEventID(ctx, &sqlc.GetUserByTgIDAndEventIDParams{
+		EventID: event.ID,
+		TgID:    message.FromID,
+	})
+	if err != nil {
+		err = apperr.FromDB(err)
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get user", slog.Any("error", err))
+		return errorReply(err)
+	}
+
+	var promoCode *sqlc.PromoCodes
+	err = s.store.InTx(ctx, func(tx store.Store) error {
+		var err error
+		if promoCode, err = promo.Use(ctx, tx, event.ID, event.TicketPrice, code); err != nil {
+			return err
+		}
+		if promoCode.Kind != promo.KindEntries {
+			return errTicketCode
+		}
+		return promo.Redeem(ctx, tx, promoCode, user, 0)
+	})
+	if errors.Is(err, errTicketCode) {
+		return "Цей промокод діє лише під час реєстрації, а ти вже зареєстрований."
+	}
+	if err != nil {
+		return promoErrorReply(ctx, err)
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "Redeemed promo code",
+		slog.Int64("user_id", user.ID), slog.Int64("promo_code_id", promoCode.ID))
+	return fmt.Sprintf("Промокод застосовано: +%d шанс(ів) у розіграші!", promoCode.Value)
+}
+
+// promoErrorReply translates a failure to use a promo code into a reply.
+func promoErrorReply(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(err, promo.ErrInvalid):
+		return "Промокод недійсний, прострочений або вже вичерпаний."
+	case errors.Is(err, promo.ErrTicketOnly):
+		return "Цей промокод діє лише на платні квитки."
+	}
+	err = apperr.FromDB(err)
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to use promo code", slog.Any("error", err))
+	return errorReply(err)
+}
+
+// seatText returns the message line with the participant's seat, or "" if
+// they have none.
+func (s *Service) seatText(ctx context.Context, userID int64) string {
+	seat, err := seating.Of(ctx, s.store, userID)
+	if err != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get seat",
+			slog.Int64("user_id", userID), slog.Any("error", err))
+		return ""
+	}
+	if seat == nil {
+		return ""
+	}
+	return "\nМісце: " + markdown.EscapeTelegram(seat.Label)
+}
+
+// selfServiceText returns the message part with the participant's
+// self-service link, or "" if links aren't configured.
+func (s *Service) selfServiceText(userID int64) string {
+	if s.links == nil {
+		return ""
+	}
+	return "\n\nКерувати реєстрацією (змінити дані, підтвердити участь чи скасувати): " + s.links.URL(userID)
+}
+
+// shareHint returns the message part inviting the participant to share
+// the event, or "" if share bonuses are off.
+func (s *Service) shareHint(ctx context.Context, eventID int64) string {
+	if s.publicURL == "" {
+		return ""
+	}
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if err != nil || event.ShareClicksRequired == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nЗапроси друзів і отримай +%d шанс(ів) у розіграші: надішли /share, щоб отримати своє посилання.", event.ShareBonus)
+}
+
+// shareReply returns the participant's share link, creating it on first
+// use, as a message they can forward to friends.
+func (s *Service) shareReply(ctx context.Context, user *sqlc.Users, languageCode string) string {
+	event, err := s.event(ctx, user.EventID, languageCode)
+	if err != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get event", slog.Any("error", err))
+		return errorReply(apperr.FromDB(err))
+	}
+	if s.publicURL == "" || event.ShareClicksRequired == 0 {
+		return "Бонус за поширення для цього івенту вимкнено."
+	}
+
+	user, err = s.store.SetUserShareCode(ctx, &sqlc.SetUserShareCodeParams{
+		ID:        user.ID,
+		ShareCode: cryptoRand.Text(),
+	})
+	if err != nil {
+		err = apperr.FromDB(err)
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to create share code", slog.Any("error", err))
+		return errorReply(err)
+	}
+
+	status := fmt.Sprintf("Коли за посиланням перейдуть %d різних людей, ти отримаєш +%d шанс(ів) у розіграші.", event.ShareClicksRequired, event.ShareBonus)
+	if user.ShareBonusGrantedAt.Valid {
+		status = fmt.Sprintf("Бонус уже нараховано: +%d шанс(ів) у розіграші.", user.ShareEntries)
+	}
+	return fmt.Sprintf("%s\n\nПерешли друзям повідомлення нижче 👇\n\nРеєструйся на івент ФІТКІ \"%s\": %s/s/%s", status, markdown.EscapeTelegram(event.Name), s.publicURL, user.ShareCode.String)
+}
+
 func (s *Service) getState(chatID int64) State {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	key := StateKey{
-		ChatID:  chatID,
-		EventID: config.GetCurrentEventID(),
-	}
+	key := s.stateKey(chatID)
 
 	if state, ok := s.state[key]; ok {
 		return state
@@ -147,10 +846,146 @@ func (s *Service) setState(chatID int64, state State) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	key := StateKey{
-		ChatID:  chatID,
-		EventID: config.GetCurrentEventID(),
+	s.state[s.stateKey(chatID)] = state
+}
+
+func (s *Service) getPromo(chatID int64) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.promo[s.stateKey(chatID)]
+}
+
+// setPromo keeps the promo code the user sent, or forgets it if code is "".
+func (s *Service) setPromo(chatID int64, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := s.stateKey(chatID)
+	if code == "" {
+		delete(s.promo, key)
+		return
+	}
+	s.promo[key] = code
+}
+
+func (s *Service) getTicketType(chatID int64) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ticketType[s.stateKey(chatID)]
+}
+
+// setTicketType keeps the ticket type the user picked, or forgets it if id
+// is 0.
+func (s *Service) setTicketType(chatID, id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := s.stateKey(chatID)
+	if id == 0 {
+		delete(s.ticketType, key)
+		return
 	}
+	s.ticketType[key] = id
+}
+
+// pruneStates hourly drops the events picked by chats longer than pickTTL
+// ago, and the conversation states of events other than the current one
+// and those picked, which are never read again.
+func (s *Service) pruneStates(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			maps.DeleteFunc(s.picks, func(_ int64, p pick) bool { return time.Since(p.at) > pickTTL })
+			stale := func(key StateKey) bool { return key != s.stateKey(key.ChatID) }
+			n := len(s.state)
+			maps.DeleteFunc(s.state, func(key StateKey, _ State) bool { return stale(key) })
+			maps.DeleteFunc(s.promo, func(key StateKey, _ string) bool { return stale(key) })
+			maps.DeleteFunc(s.ticketType, func(key StateKey, _ int64) bool { return stale(key) })
+			pruned := n - len(s.state)
+			s.mu.Unlock()
+
+			if pruned > 0 {
+				s.logger.LogAttrs(ctx, slog.LevelInfo, "Pruned chat states", slog.Int("count", pruned))
+			}
+		}
+	}
+}
+
+// stateKey returns the key of the chat's conversation about the event it
+// registers for. s.mu must be held.
+func (s *Service) stateKey(chatID int64) StateKey {
+	eventID := config.GetCurrentEventID()
+	if p, ok := s.picks[chatID]; ok {
+		eventID = p.eventID
+	}
+	return StateKey{ChatID: chatID, EventID: eventID}
+}
+
+// eventID returns the event the chat registers for: the one named by the
+// last start link it was opened with, or else the current event.
+func (s *Service) eventID(chatID int64) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stateKey(chatID).EventID
+}
+
+// pickEvent makes the chat register for the event a start link named for
+// pickTTL. It returns a reply if the event can't be registered for, or "".
+func (s *Service) pickEvent(ctx context.Context, chatID, eventID int64) string {
+	event, err := s.store.GetEventByID(ctx, eventID)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && !lifecycle.Public(event) {
+		return "Не знайшов такого івенту. Перевір посилання на сторінці івенту."
+	}
+	if err != nil {
+		err = apperr.FromDB(err)
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "Failed to get event", slog.Any("error", err))
+		return errorReply(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.picks[chatID] = pick{eventID: eventID, at: time.Now()}
+	return ""
+}
+
+// StartPayload returns the start parameter of links that open the bot to
+// register for the event.
+func StartPayload(eventID int64) string {
+	return startPrefix + strconv.FormatInt(eventID, 10)
+}
+
+// startEventID returns the event named by text if it is the /start command
+// sent by a link with StartPayload, or 0 for other messages. A plain
+// /start keeps the event the chat registers for.
+func startEventID(text string) int64 {
+	if !isStart(text) {
+		return 0
+	}
+	_, payload, _ := strings.Cut(text, " ")
+	rawID, ok := strings.CutPrefix(strings.TrimSpace(payload), startPrefix)
+	if !ok {
+		return 0
+	}
+	eventID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || eventID <= 0 {
+		return 0
+	}
+	return eventID
+}
 
-	s.state[key] = state
+// isStart reports whether text is the /start command. Links to the bot
+// from event pages and posters send it with the event after it.
+func isStart(text string) bool {
+	command, _, _ := strings.Cut(text, " ")
+	return command == "/start"
 }
@@ -0,0 +1,26 @@
+package telegram
+
+import "testing"
+
+func TestStartEventID(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int64
+	}{
+		{name: "start link", text: "/start " + StartPayload(42), want: 42},
+		{name: "plain start", text: "/start", want: 0},
+		{name: "other payload", text: "/start promo", want: 0},
+		{name: "not a number", text: "/start event_abc", want: 0},
+		{name: "negative", text: "/start event_-1", want: 0},
+		{name: "other command", text: "/chatid " + StartPayload(42), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := startEventID(tt.text); got != tt.want {
+				t.Errorf("startEventID(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
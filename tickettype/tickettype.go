@@ -0,0 +1,126 @@
+// Package tickettype sells an event's tickets by type, such as Standard,
+// VIP or Volunteer. Each type has its own price, capacity and weight in
+// the draw; events without types sell one kind of ticket at the event's
+// ticket price.
+package tickettype
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+// pendingOrderWindow is how long an unpaid ticket order holds its place,
+// like the window of entry purchases.
+const pendingOrderWindow = 3600
+
+// ErrRequired is returned when an event with types is registered for
+// without picking one.
+var ErrRequired = apperr.Validation("Choose a ticket type")
+
+// ErrInvalid is returned for types that aren't the event's.
+var ErrInvalid = apperr.Validation("Invalid ticket type")
+
+// ErrSoldOut is returned when a type has no places left.
+var ErrSoldOut = apperr.Conflict("This ticket type is sold out")
+
+// Get returns the event's type with the given ID without taking a place,
+// so the price can be shown before registering. It returns nil for events
+// without types.
+func Get(ctx context.Context, st store.Store, eventID, id int64) (*sqlc.TicketTypes, error) {
+	if id == 0 {
+		return nil, required(ctx, st, eventID)
+	}
+	ticketType, err := st.GetTicketType(ctx, &sqlc.GetTicketTypeParams{ID: id, EventID: eventID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalid
+	}
+	return ticketType, err
+}
+
+// Reserve checks that the type still has a place in tx, which should be
+// the transaction registering the participant or creating their order.
+// The type stays locked until tx ends, so concurrent registrations can't
+// go over its capacity.
+func Reserve(ctx context.Context, tx store.Store, eventID, id int64) (*sqlc.TicketTypes, error) {
+	if id == 0 {
+		return nil, required(ctx, tx, eventID)
+	}
+	ticketType, err := tx.GetTicketTypeForUpdate(ctx, &sqlc.GetTicketTypeForUpdateParams{ID: id, EventID: eventID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ticketType.Capacity == 0 {
+		return ticketType, nil
+	}
+
+	left, err := Left(ctx, tx, ticketType)
+	if err != nil {
+		return nil, err
+	}
+	if left <= 0 {
+		return nil, ErrSoldOut
+	}
+	return ticketType, nil
+}
+
+// Left returns how many places of a limited type are left.
+func Left(ctx context.Context, st store.Store, ticketType *sqlc.TicketTypes) (int32, error) {
+	taken, err := st.CountTicketTypeTaken(ctx, &sqlc.CountTicketTypeTakenParams{
+		ID:             ticketType.ID,
+		PendingSeconds: pendingOrderWindow,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ticketType.Capacity - taken, nil
+}
+
+// Assign gives the participant the type and its weight in the draw. It
+// does nothing for events without types.
+func Assign(ctx context.Context, tx store.Store, user *sqlc.Users, ticketType *sqlc.TicketTypes) (*sqlc.Users, error) {
+	if ticketType == nil {
+		return user, nil
+	}
+	return tx.SetUserTicketType(ctx, &sqlc.SetUserTicketTypeParams{
+		ID:           user.ID,
+		TicketTypeID: ticketType.ID,
+		Weight:       ticketType.Weight,
+	})
+}
+
+// Price returns what a ticket of the type costs, which is the event's
+// ticket price for events without types.
+func Price(event *sqlc.Events, ticketType *sqlc.TicketTypes) int32 {
+	if ticketType == nil {
+		return event.TicketPrice
+	}
+	return ticketType.Price
+}
+
+// ID returns the type's ID for a nullable column.
+func ID(ticketType *sqlc.TicketTypes) sql.NullInt64 {
+	if ticketType == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: ticketType.ID, Valid: true}
+}
+
+// required returns ErrRequired if the event has types.
+func required(ctx context.Context, st store.Store, eventID int64) error {
+	ticketTypes, err := st.GetTicketTypesByEventID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if len(ticketTypes) > 0 {
+		return ErrRequired
+	}
+	return nil
+}
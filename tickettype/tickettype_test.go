@@ -0,0 +1,141 @@
+package tickettype_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+	"giveaway-tool/store/memory"
+	"giveaway-tool/tickettype"
+)
+
+func TestReserve(t *testing.T) {
+	tests := []struct {
+		name string
+		// types lists the capacity of each of the event's types; the
+		// first one is taken by participants
+		types        []int32
+		participants int
+		pending      int
+		// pick is the index of the type asked for, or -1 for none
+		pick    int
+		invalid bool
+		want    error
+	}{
+		{name: "event without types", pick: -1},
+		{name: "type required", types: []int32{0}, pick: -1, want: tickettype.ErrRequired},
+		{name: "unlimited type", types: []int32{0}, participants: 10, pick: 0},
+		{name: "places left", types: []int32{3}, participants: 2, pick: 0},
+		{name: "sold out", types: []int32{2}, participants: 2, pick: 0, want: tickettype.ErrSoldOut},
+		{name: "pending orders hold places", types: []int32{2}, participants: 1, pending: 1, pick: 0, want: tickettype.ErrSoldOut},
+		{name: "other type left", types: []int32{1, 1}, participants: 1, pick: 1},
+		{name: "type of another event", types: []int32{0}, pick: 0, invalid: true, want: tickettype.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			st := memory.New()
+			event := newEvent(t, st)
+			var types []*sqlc.TicketTypes
+			for i, places := range tt.types {
+				ticketType, err := st.CreateTicketType(ctx, &sqlc.CreateTicketTypeParams{
+					EventID:  event.ID,
+					Name:     fmt.Sprint("Type ", i),
+					Capacity: places,
+					Weight:   1,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				types = append(types, ticketType)
+			}
+			for i := range tt.participants {
+				user, err := st.CreateUser(ctx, &sqlc.CreateUserParams{
+					Name:        fmt.Sprint("User ", i),
+					EventID:     event.ID,
+					CheckInCode: store.NewCheckInCode(),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := tickettype.Assign(ctx, st, user, types[0]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for i := range tt.pending {
+				if _, err := st.CreateTicketOrder(ctx, &sqlc.CreateTicketOrderParams{
+					OrderID:      fmt.Sprint("order-", i),
+					EventID:      event.ID,
+					Name:         fmt.Sprint("Buyer ", i),
+					Amount:       100,
+					TicketTypeID: tickettype.ID(types[0]),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			eventID := event.ID
+			if tt.invalid {
+				eventID = newEvent(t, st).ID
+			}
+			var id int64
+			if tt.pick >= 0 {
+				id = types[tt.pick].ID
+			}
+
+			var got *sqlc.TicketTypes
+			err := st.InTx(ctx, func(tx store.Store) error {
+				var err error
+				got, err = tickettype.Reserve(ctx, tx, eventID, id)
+				return err
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Reserve() = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				return
+			}
+			switch {
+			case id == 0 && got != nil:
+				t.Errorf("Reserve() = type %d for an event without types", got.ID)
+			case id != 0 && (got == nil || got.ID != id):
+				t.Errorf("Reserve() = %v, want type %d", got, id)
+			}
+		})
+	}
+}
+
+func TestPrice(t *testing.T) {
+	event := &sqlc.Events{TicketPrice: 500}
+	tests := []struct {
+		name       string
+		ticketType *sqlc.TicketTypes
+		want       int32
+	}{
+		{name: "event price", want: 500},
+		{name: "type price", ticketType: &sqlc.TicketTypes{Price: 1500}, want: 1500},
+		{name: "free type", ticketType: &sqlc.TicketTypes{Price: 0}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tickettype.Price(event, tt.ticketType); got != tt.want {
+				t.Errorf("Price() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func newEvent(t *testing.T, st *memory.Store) *sqlc.Events {
+	t.Helper()
+	event, err := st.CreateEvent(context.Background(), &sqlc.CreateEventParams{Name: "Event", Date: time.Now().Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
@@ -0,0 +1,77 @@
+// Package tracing sends OpenTelemetry traces of requests, bot updates and
+// database queries to an OTLP collector, to see where slow flows such as a
+// registration spend their time.
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"giveaway-tool/logging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serviceName names the app in traces unless OTEL_SERVICE_NAME is set.
+const serviceName = "giveaway-tool"
+
+// Enabled reports whether a collector to export traces to is configured.
+func Enabled() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup exports traces over OTLP/HTTP to the collector configured with the
+// standard OTEL_EXPORTER_OTLP_* variables, and continues traces started by
+// callers that send W3C trace headers. Unless Enabled, spans are dropped
+// without being recorded. The returned func sends the spans not sent yet.
+func Setup(ctx context.Context) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK())
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of the one in ctx, if any, and
+// adds its trace ID to the logger of the returned context so log lines can
+// be matched with traces.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(serviceName).Start(ctx, name, opts...)
+	if span.SpanContext().IsValid() {
+		ctx = logging.With(ctx, slog.String("trace_id", span.SpanContext().TraceID().String()))
+	}
+	return ctx, span
+}
+
+// Fail marks span as failed with err, if it isn't nil.
+func Fail(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,245 @@
+// Package validate checks request input against length and range limits
+// and reports the first problem as a typed application error, so every
+// handler rejects bad input with the same kind of message.
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"giveaway-tool/apperr"
+	"giveaway-tool/sanitize"
+)
+
+// Form reads fields from a submitted form. Checks after the first failed
+// one are skipped and return zero values, so handlers can read every field
+// and look at Err once.
+type Form struct {
+	r   *http.Request
+	err error
+}
+
+// maxFormMemory is how much of a multipart form is held in memory before
+// files are spooled to disk. Route body limits keep forms smaller anyway.
+const maxFormMemory = 8 << 20
+
+// NewForm parses the form of r, including multipart forms with files. A
+// body over the size limit is reported by Err as too large.
+func NewForm(r *http.Request) *Form {
+	f := &Form{r: r}
+	var err error
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		err = r.ParseMultipartForm(maxFormMemory)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		f.err = BodyError(err)
+	}
+	return f
+}
+
+// Err returns the first problem found, if any.
+func (f *Form) Err() error {
+	return f.err
+}
+
+// Text returns the trimmed value of field, failing if it is longer than
+// maxLen characters.
+func (f *Form) Text(field string, maxLen int) string {
+	if f.err != nil {
+		return ""
+	}
+	value := strings.TrimSpace(f.r.FormValue(field))
+	f.err = Length(field, value, maxLen)
+	return value
+}
+
+// RequiredText is Text for fields that can't be empty.
+func (f *Form) RequiredText(field string, maxLen int) string {
+	value := f.Text(field, maxLen)
+	if f.err == nil && value == "" {
+		f.err = apperr.Validation(label(field) + " is required")
+	}
+	return value
+}
+
+// RichText is Text for fields that may contain formatting, with the HTML
+// that the sanitize package doesn't allow removed.
+func (f *Form) RichText(field string, maxLen int) string {
+	return sanitize.Markdown(f.Text(field, maxLen))
+}
+
+// Int returns field as an integer between min and max inclusive.
+func (f *Form) Int(field string, min, max int) int {
+	if f.err != nil {
+		return 0
+	}
+	n, err := Range(field, f.r.FormValue(field), min, max)
+	f.err = err
+	return n
+}
+
+// OptionalInt is Int for fields that may be left empty, reporting whether
+// a value was given.
+func (f *Form) OptionalInt(field string, min, max int) (int, bool) {
+	if f.err != nil || strings.TrimSpace(f.r.FormValue(field)) == "" {
+		return 0, false
+	}
+	return f.Int(field, min, max), f.err == nil
+}
+
+// List returns the trimmed, non-empty comma-separated values of field,
+// failing if there are more than maxItems or one is longer than maxLen
+// characters.
+func (f *Form) List(field string, maxItems, maxLen int) []string {
+	if f.err != nil {
+		return nil
+	}
+	var items []string
+	items, f.err = List(field, strings.Split(f.r.FormValue(field), ","), maxItems, maxLen)
+	return items
+}
+
+// IDs returns the values of field, which is sent once per selected item
+// like a group of checkboxes, as IDs. It fails if none or more than
+// maxItems are selected.
+func (f *Form) IDs(field string, maxItems int) []int64 {
+	if f.err != nil {
+		return nil
+	}
+	values := f.r.Form[field]
+	if len(values) == 0 {
+		f.err = apperr.Validation("Select at least one item")
+		return nil
+	}
+	if len(values) > maxItems {
+		f.err = apperr.Validation(fmt.Sprintf("At most %d items can be selected", maxItems))
+		return nil
+	}
+	ids := make([]int64, len(values))
+	for i, value := range values {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			f.err = apperr.Validation(fmt.Sprintf("Invalid %s", strings.ToLower(label(field))))
+			return nil
+		}
+		ids[i] = id
+	}
+	return ids
+}
+
+// File returns the contents and sniffed media type of the file uploaded as
+// field, or nil if no file was chosen. It fails if the file is larger than
+// maxSize bytes or its type isn't one of types.
+func (f *Form) File(field string, maxSize int64, types ...string) ([]byte, string) {
+	if f.err != nil {
+		return nil, ""
+	}
+	file, _, err := f.r.FormFile(field)
+	if errors.Is(err, http.ErrMissingFile) || errors.Is(err, http.ErrNotMultipart) {
+		return nil, ""
+	}
+	if err != nil {
+		f.err = BodyError(err)
+		return nil, ""
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		f.err = BodyError(err)
+		return nil, ""
+	}
+	if len(data) == 0 {
+		return nil, ""
+	}
+	if int64(len(data)) > maxSize {
+		f.err = apperr.Validation(fmt.Sprintf("%s must be at most %d KB", label(field), maxSize>>10))
+		return nil, ""
+	}
+	// The type is sniffed from the contents, since browsers send whatever
+	// the file name suggests
+	contentType := http.DetectContentType(data)
+	if !slices.Contains(types, contentType) {
+		f.err = apperr.Validation(fmt.Sprintf("%s must be one of: %s", label(field), strings.Join(types, ", ")))
+		return nil, ""
+	}
+	return data, contentType
+}
+
+// List is Form.List for values that were already split, such as arrays in
+// JSON bodies.
+func List(field string, values []string, maxItems, maxLen int) ([]string, error) {
+	items := make([]string, 0, len(values))
+	for _, item := range values {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if err := Length(field, item, maxLen); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if len(items) > maxItems {
+		return nil, apperr.Validation(fmt.Sprintf("%s must have at most %d items", label(field), maxItems))
+	}
+	return items, nil
+}
+
+// Length fails if value is longer than maxLen characters.
+func Length(field, value string, maxLen int) error {
+	if utf8.RuneCountInString(value) > maxLen {
+		return apperr.Validation(fmt.Sprintf("%s must be at most %d characters", label(field), maxLen))
+	}
+	return nil
+}
+
+// Range parses value as an integer between min and max inclusive.
+func Range(field, value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < min || n > max {
+		return 0, apperr.Validation(fmt.Sprintf("%s must be a number from %d to %d", label(field), min, max))
+	}
+	return n, nil
+}
+
+// JSON decodes the body of r into v.
+func JSON(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return BodyError(err)
+		}
+		return apperr.Validation("Invalid JSON body")
+	}
+	return nil
+}
+
+// BodyError maps an error from reading a request body onto a typed error,
+// reporting bodies over the size limit as too large.
+func BodyError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return apperr.TooLarge(fmt.Sprintf("Request body must be at most %d bytes", maxBytesErr.Limit))
+	}
+	return apperr.Validation("Invalid form")
+}
+
+// label turns a field name like "max_ticket" into "Max ticket".
+func label(field string) string {
+	s := strings.ReplaceAll(field, "_", " ")
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,132 @@
+// Package waitlist queues people who want to register for a full event and
+// registers them in order as places free up, letting those with a Telegram
+// chat know through the bot.
+package waitlist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"giveaway-tool/capacity"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/markdown"
+	"giveaway-tool/registration"
+	"giveaway-tool/store"
+	"giveaway-tool/tickettype"
+)
+
+// Join puts a person at the end of the event's waitlist and returns their
+// entry with its position, counting from 1. Telegram users already on the
+// waitlist keep their place.
+func Join(ctx context.Context, tx store.Store, arg *sqlc.AddToWaitlistParams) (*sqlc.Waitlist, int32, error) {
+	entry, err := tx.GetWaitlistEntryByTgID(ctx, &sqlc.GetWaitlistEntryByTgIDParams{TgID: arg.TgID, EventID: arg.EventID})
+	if errors.Is(err, sql.ErrNoRows) || !arg.TgID.Valid {
+		entry, err = tx.AddToWaitlist(ctx, arg)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	position, err := Position(ctx, tx, entry)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entry, position, nil
+}
+
+// Position returns the entry's place in the queue, counting from 1.
+func Position(ctx context.Context, st store.Store, entry *sqlc.Waitlist) (int32, error) {
+	return st.GetWaitlistPosition(ctx, &sqlc.GetWaitlistPositionParams{EventID: entry.EventID, ID: entry.ID})
+}
+
+// Promote registers a waitlisted person for the event with the ticket type
+// and consent they gave when joining, takes them off the waitlist and
+// queues a message telling them, if they came from Telegram. It fails with
+// capacity.ErrFull if the event has no place for them, or one of the
+// tickettype errors if their type has none or was deleted.
+func Promote(ctx context.Context, tx store.Store, entry *sqlc.Waitlist) (*sqlc.Users, error) {
+	ticketType, err := registration.Reserve(ctx, tx, entry.EventID, entry.TicketTypeID.Int64)
+	if err != nil {
+		return nil, err
+	}
+	user, err := registration.Create(ctx, tx, &sqlc.CreateUserParams{
+		Name:     entry.Name,
+		Username: entry.Username,
+		TgID:     entry.TgID,
+		EventID:  entry.EventID,
+	}, ticketType, entry.ConsentSource)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.DeleteWaitlistEntry(ctx, &sqlc.DeleteWaitlistEntryParams{
+		ID:      entry.ID,
+		EventID: entry.EventID,
+	}); err != nil {
+		return nil, err
+	}
+	if err := notifyPromoted(ctx, tx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// PromoteNext promotes the first person on the event's waitlist. It returns
+// nil if the waitlist is empty or the event is still full, which happens
+// when its capacity was lowered below the participants it has, and also
+// when the first person's ticket type can't take them; they keep their
+// place at the head of the queue until an admin sorts it out.
+func PromoteNext(ctx context.Context, tx store.Store, eventID int64) (*sqlc.Users, error) {
+	entry, err := tx.GetNextWaitlistEntry(ctx, eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	user, err := Promote(ctx, tx, entry)
+	if errors.Is(err, capacity.ErrFull) || errors.Is(err, tickettype.ErrSoldOut) ||
+		errors.Is(err, tickettype.ErrRequired) || errors.Is(err, tickettype.ErrInvalid) {
+		return nil, nil
+	}
+	return user, err
+}
+
+// Fill promotes people from the waitlist while the event has places left,
+// such as after its capacity was raised. Events without a capacity are
+// left alone, since promoting would empty their waitlist.
+func Fill(ctx context.Context, tx store.Store, eventID int64) ([]*sqlc.Users, error) {
+	event, err := tx.GetEventByID(ctx, eventID)
+	if err != nil || event.Capacity == 0 {
+		return nil, err
+	}
+
+	var promoted []*sqlc.Users
+	for {
+		user, err := PromoteNext(ctx, tx, eventID)
+		if err != nil || user == nil {
+			return promoted, err
+		}
+		promoted = append(promoted, user)
+	}
+}
+
+// notifyPromoted queues the news of the registration for the participant's
+// Telegram chat, sent with the rest of the outbox.
+func notifyPromoted(ctx context.Context, tx store.Store, user *sqlc.Users) error {
+	if !user.TgID.Valid {
+		return nil
+	}
+	event, err := tx.GetEventByID(ctx, user.EventID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.EnqueueOutboxMessage(ctx, &sqlc.EnqueueOutboxMessageParams{
+		ChatID: user.TgID.Int64,
+		Text: fmt.Sprintf("Звільнилося місце! Тебе переведено з листа очікування й зареєстровано на івент \"%s\".\n\nТвій номер квитка: №%d\nКод для входу: %s",
+			markdown.EscapeTelegram(event.Name), user.TicketNumber, user.CheckInCode),
+		Markdown: true,
+	})
+	return err
+}
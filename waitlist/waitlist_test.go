@@ -0,0 +1,243 @@
+package waitlist_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"giveaway-tool/capacity"
+	"giveaway-tool/consent"
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+	"giveaway-tool/store/memory"
+	"giveaway-tool/waitlist"
+)
+
+func TestPromoteNext(t *testing.T) {
+	tests := []struct {
+		name         string
+		capacity     int32
+		participants int
+		// typePlaces is the capacity of the event's ticket type, or -1 for
+		// an event without types
+		typePlaces int32
+		// join lists the entries of the waitlist in queue order
+		join         []entry
+		wantPromoted string
+		wantLeft     int
+	}{
+		{
+			name:       "empty waitlist",
+			capacity:   1,
+			typePlaces: -1,
+		},
+		{
+			name:         "event still full",
+			capacity:     1,
+			participants: 1,
+			typePlaces:   -1,
+			join:         []entry{{name: "Anna", source: consent.SourceWebsite}},
+			wantLeft:     1,
+		},
+		{
+			name:         "first in line",
+			capacity:     2,
+			participants: 1,
+			typePlaces:   -1,
+			join:         []entry{{name: "Anna", source: consent.SourceWebsite}, {name: "Bohdan", source: consent.SourceAPI}},
+			wantPromoted: "Anna",
+			wantLeft:     1,
+		},
+		{
+			name:         "from telegram",
+			capacity:     1,
+			typePlaces:   -1,
+			join:         []entry{{name: "Anna", tgID: 100, source: consent.SourceTelegram}},
+			wantPromoted: "Anna",
+		},
+		{
+			name:         "with ticket type",
+			capacity:     2,
+			typePlaces:   2,
+			join:         []entry{{name: "Anna", typed: true, source: consent.SourceAPI}},
+			wantPromoted: "Anna",
+		},
+		{
+			name:         "ticket type sold out",
+			capacity:     3,
+			participants: 1,
+			typePlaces:   1,
+			join:         []entry{{name: "Anna", typed: true, source: consent.SourceWebsite}},
+			wantLeft:     1,
+		},
+		{
+			name:       "ticket type missing",
+			capacity:   2,
+			typePlaces: 0,
+			join:       []entry{{name: "Anna", source: consent.SourceWebsite}},
+			wantLeft:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			st := memory.New()
+			event, err := st.CreateEvent(ctx, &sqlc.CreateEventParams{Name: "Event", Date: time.Now().Add(24 * time.Hour)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := st.SetEventCapacity(ctx, &sqlc.SetEventCapacityParams{ID: event.ID, Capacity: tt.capacity}); err != nil {
+				t.Fatal(err)
+			}
+			var ticketType *sqlc.TicketTypes
+			if tt.typePlaces >= 0 {
+				if ticketType, err = st.CreateTicketType(ctx, &sqlc.CreateTicketTypeParams{
+					EventID:  event.ID,
+					Name:     "VIP",
+					Capacity: tt.typePlaces,
+					Weight:   3,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for i := range tt.participants {
+				user, err := st.CreateUser(ctx, &sqlc.CreateUserParams{
+					Name:        fmt.Sprint("User ", i),
+					EventID:     event.ID,
+					CheckInCode: store.NewCheckInCode(),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ticketType != nil {
+					if _, err := st.SetUserTicketType(ctx, &sqlc.SetUserTicketTypeParams{ID: user.ID, TicketTypeID: ticketType.ID, Weight: 3}); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			for _, e := range tt.join {
+				arg := &sqlc.AddToWaitlistParams{
+					EventID:       event.ID,
+					Name:          e.name,
+					TgID:          sql.NullInt64{Int64: e.tgID, Valid: e.tgID != 0},
+					ConsentSource: e.source,
+				}
+				if e.typed {
+					arg.TicketTypeID = sql.NullInt64{Int64: ticketType.ID, Valid: true}
+				}
+				if _, _, err := waitlist.Join(ctx, st, arg); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var user *sqlc.Users
+			err = st.InTx(ctx, func(tx store.Store) error {
+				var err error
+				user, err = waitlist.PromoteNext(ctx, tx, event.ID)
+				return err
+			})
+			if err != nil {
+				t.Fatalf("PromoteNext() = %v", err)
+			}
+
+			left, err := st.GetWaitlistByEventID(ctx, event.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(left) != tt.wantLeft {
+				t.Errorf("waitlist has %d entries, want %d", len(left), tt.wantLeft)
+			}
+			if tt.wantPromoted == "" {
+				if user != nil {
+					t.Errorf("PromoteNext() promoted %q, want nobody", user.Name)
+				}
+				return
+			}
+			if user == nil || user.Name != tt.wantPromoted {
+				t.Fatalf("PromoteNext() = %v, want %q", user, tt.wantPromoted)
+			}
+			checkPromoted(t, st, user, tt.join[0], ticketType)
+		})
+	}
+}
+
+type entry struct {
+	name   string
+	tgID   int64
+	typed  bool
+	source string
+}
+
+// checkPromoted checks that the participant was registered like any other,
+// with the consent and ticket type of their waitlist entry.
+func checkPromoted(t *testing.T, st *memory.Store, user *sqlc.Users, e entry, ticketType *sqlc.TicketTypes) {
+	t.Helper()
+	ctx := context.Background()
+
+	if user.CheckInCode == "" {
+		t.Error("promoted participant has no check-in code")
+	}
+	if e.typed && (!user.TicketTypeID.Valid || user.TicketTypeID.Int64 != ticketType.ID) {
+		t.Errorf("promoted participant has ticket type %v, want %d", user.TicketTypeID, ticketType.ID)
+	}
+
+	records, err := st.GetConsentLogByEventID(ctx, user.EventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	consented := false
+	for _, record := range records {
+		if record.UserID.Int64 == user.ID && record.Action == string(consent.Consented) {
+			consented = true
+			if record.Source != e.source {
+				t.Errorf("consent recorded from %q, want %q", record.Source, e.source)
+			}
+		}
+	}
+	if !consented {
+		t.Error("no consent recorded for the promoted participant")
+	}
+
+	messages, err := st.ClaimOutboxMessages(ctx, &sqlc.ClaimOutboxMessagesParams{LeaseSeconds: 60, BatchSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notified := false
+	for _, message := range messages {
+		notified = notified || message.ChatID == e.tgID
+	}
+	if notified != (e.tgID != 0) {
+		t.Errorf("participant notified = %t, want %t", notified, e.tgID != 0)
+	}
+}
+
+func TestPromoteFull(t *testing.T) {
+	ctx := context.Background()
+	st := memory.New()
+	event, err := st.CreateEvent(ctx, &sqlc.CreateEventParams{Name: "Event", Date: time.Now().Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.SetEventCapacity(ctx, &sqlc.SetEventCapacityParams{ID: event.ID, Capacity: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.CreateUser(ctx, &sqlc.CreateUserParams{Name: "User", EventID: event.ID, CheckInCode: store.NewCheckInCode()}); err != nil {
+		t.Fatal(err)
+	}
+	e, _, err := waitlist.Join(ctx, st, &sqlc.AddToWaitlistParams{EventID: event.ID, Name: "Anna", ConsentSource: consent.SourceWebsite})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = st.InTx(ctx, func(tx store.Store) error {
+		_, err := waitlist.Promote(ctx, tx, e)
+		return err
+	})
+	if !errors.Is(err, capacity.ErrFull) {
+		t.Errorf("Promote() = %v, want %v", err, capacity.ErrFull)
+	}
+}
@@ -0,0 +1,81 @@
+// Package webhook tells tools like Zapier and Make about new registrations,
+// so organizers can add participants to their own spreadsheets, CRMs and
+// mailing lists without writing code. Calls are queued in the transaction
+// that registered the participant and sent by the service's webhook job.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"giveaway-tool/database/sqlc"
+	"giveaway-tool/store"
+)
+
+// RegistrationCreated is the type of the payload sent for a new participant.
+const RegistrationCreated = "registration.created"
+
+// Registration is the payload describing a participant, both in webhook
+// calls and in the polling API. It is flat, so no-code tools can map its
+// fields directly, and ID is unique, so they can deduplicate polled items.
+type Registration struct {
+	ID           int64     `json:"id"`
+	Type         string    `json:"type"`
+	EventID      int64     `json:"event_id"`
+	EventName    string    `json:"event_name"`
+	EventDate    time.Time `json:"event_date"`
+	TicketNumber int32     `json:"ticket_number"`
+	Name         string    `json:"name"`
+	Username     string    `json:"username"`
+	Phone        string    `json:"phone"`
+	Telegram     bool      `json:"telegram"`
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+// NewRegistration describes the participant of event.
+func NewRegistration(event *sqlc.Events, user *sqlc.Users) Registration {
+	return Registration{
+		ID:           user.ID,
+		Type:         RegistrationCreated,
+		EventID:      event.ID,
+		EventName:    event.Name,
+		EventDate:    event.Date,
+		TicketNumber: user.TicketNumber,
+		Name:         user.Name,
+		Username:     user.Username,
+		Phone:        user.Phone,
+		Telegram:     user.TgID.Valid,
+		RegisteredAt: user.CreatedAt.Time,
+	}
+}
+
+// URL is where calls are sent, from WEBHOOK_URL. Webhooks are disabled when
+// it is empty.
+func URL() string {
+	return os.Getenv("WEBHOOK_URL")
+}
+
+// Registered queues a call about the new participant, if webhooks are
+// enabled. st should be the transaction that created them, so the call is
+// only sent if the registration is committed.
+func Registered(ctx context.Context, st store.Store, user *sqlc.Users) error {
+	url := URL()
+	if url == "" {
+		return nil
+	}
+
+	event, err := st.GetEventByID(ctx, user.EventID)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(NewRegistration(event, user))
+	if err != nil {
+		return err
+	}
+	return st.EnqueueWebhook(ctx, &sqlc.EnqueueWebhookParams{
+		Url:     url,
+		Payload: string(payload),
+	})
+}
@@ -0,0 +1,308 @@
+// Package xlsx writes Excel workbooks for the participant exports. Sheets
+// are streamed row by row, so large events aren't held in memory. Only
+// what the exports need is supported: text, numbers and times, a bold
+// header row and highlighted rows.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxSheetName is the longest sheet name Excel accepts.
+const maxSheetName = 31
+
+// Style is how a row is shown.
+type Style int
+
+const (
+	Plain Style = iota
+	// Header is bold, and the first Header row of a sheet stays in view
+	// while scrolling
+	Header
+	// Highlight fills the row, to mark winners
+	Highlight
+)
+
+// Cell styles, in the order of cellXfs in styles.xml.
+const (
+	xfPlain = iota
+	xfHeader
+	xfTime
+	xfHighlight
+	xfHighlightTime
+)
+
+// Cell is one value of a row.
+type Cell struct {
+	kind   byte
+	text   string
+	number float64
+	time   time.Time
+}
+
+// Text is a cell with the text s, or an empty cell if s is "". Text is
+// never evaluated as a formula, so participant-supplied values are safe as
+// they are.
+func Text(s string) Cell {
+	if s == "" {
+		return Cell{}
+	}
+	return Cell{kind: 's', text: s}
+}
+
+// Number is a cell with the number n.
+func Number(n int64) Cell {
+	return Cell{kind: 'n', number: float64(n)}
+}
+
+// Time is a cell with t in the server's time zone, or an empty cell if t
+// is zero.
+func Time(t time.Time) Cell {
+	if t.IsZero() {
+		return Cell{}
+	}
+	return Cell{kind: 't', time: t.In(time.Local)}
+}
+
+// Writer writes a workbook to an io.Writer. Rows go to the sheet added
+// last; Close finishes the file.
+type Writer struct {
+	zip    *zip.Writer
+	sheets []string
+	sheet  io.Writer
+	rows   int
+	err    error
+}
+
+// NewWriter starts a workbook written to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zip: zip.NewWriter(w)}
+}
+
+// AddSheet starts a new sheet. Its name is shortened and made unique as
+// Excel requires.
+func (w *Writer) AddSheet(name string) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.err = w.endSheet(); w.err != nil {
+		return w.err
+	}
+
+	w.sheets = append(w.sheets, w.sheetName(name))
+	w.sheet, w.err = w.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if w.err != nil {
+		return w.err
+	}
+	w.rows = 0
+	_, w.err = io.WriteString(w.sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	return w.err
+}
+
+// WriteRow adds a row to the current sheet.
+func (w *Writer) WriteRow(style Style, cells ...Cell) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.sheet == nil {
+		w.err = errors.New("xlsx: no sheet")
+		return w.err
+	}
+
+	var b strings.Builder
+	if w.rows == 0 {
+		// The header row stays in view while scrolling
+		if style == Header {
+			b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+		}
+		b.WriteString("<sheetData>")
+	}
+	w.rows++
+	fmt.Fprintf(&b, `<row r="%d">`, w.rows)
+	for i, cell := range cells {
+		ref := column(i) + strconv.Itoa(w.rows)
+		xf := xfPlain
+		switch {
+		case style == Header:
+			xf = xfHeader
+		case style == Highlight && cell.kind == 't':
+			xf = xfHighlightTime
+		case style == Highlight:
+			xf = xfHighlight
+		case cell.kind == 't':
+			xf = xfTime
+		}
+
+		switch cell.kind {
+		case 's':
+			fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">`, ref, xf)
+			xml.EscapeText(&b, []byte(cell.text))
+			b.WriteString("</t></is></c>")
+		case 'n':
+			fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, xf, strconv.FormatFloat(cell.number, 'f', -1, 64))
+		case 't':
+			fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, xf, strconv.FormatFloat(serial(cell.time), 'f', -1, 64))
+		default:
+			fmt.Fprintf(&b, `<c r="%s" s="%d"/>`, ref, xf)
+		}
+	}
+	b.WriteString("</row>")
+
+	_, w.err = io.WriteString(w.sheet, b.String())
+	return w.err
+}
+
+// Close finishes the workbook. It doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	// Excel won't open workbooks without sheets
+	if len(w.sheets) == 0 {
+		if err := w.AddSheet("Sheet1"); err != nil {
+			return err
+		}
+	}
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	var sheets, rels, types strings.Builder
+	for i, name := range w.sheets {
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(name), i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	// The styles come after the sheets
+	stylesID := len(w.sheets) + 1
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, stylesID)
+
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			types.String() + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			rels.String() + `</Relationships>`},
+		{"xl/styles.xml", styles},
+	}
+	for _, file := range files {
+		f, err := w.zip.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, xml.Header+file.content); err != nil {
+			return err
+		}
+	}
+	return w.zip.Close()
+}
+
+// endSheet closes the elements of the current sheet, if any.
+func (w *Writer) endSheet() error {
+	if w.sheet == nil {
+		return nil
+	}
+	end := "</sheetData></worksheet>"
+	if w.rows == 0 {
+		end = "<sheetData/></worksheet>"
+	}
+	_, err := io.WriteString(w.sheet, end)
+	w.sheet = nil
+	return err
+}
+
+// sheetName makes name a valid sheet name that no earlier sheet has.
+func (w *Writer) sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) || r < ' ' {
+			return ' '
+		}
+		return r
+	}, name)
+	name = strings.Trim(strings.Join(strings.Fields(name), " "), "'")
+	if name == "" {
+		name = "Sheet"
+	}
+
+	unique := truncate(name, maxSheetName)
+	for n := 2; w.hasSheet(unique); n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		unique = truncate(name, maxSheetName-len(suffix)) + suffix
+	}
+	return unique
+}
+
+// hasSheet reports whether a sheet is named name. Excel compares names
+// ignoring case.
+func (w *Writer) hasSheet(name string) bool {
+	for _, sheet := range w.sheets {
+		if strings.EqualFold(sheet, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate shortens s to at most n characters.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// column returns the letters of the column with the zero-based index i.
+func column(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// serial converts t to Excel's date serial number: days since the end of
+// 1899, with the time of day as the fraction.
+func serial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return wall.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)).Hours() / 24
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// styles holds the cell styles in the order of the xf constants. Number
+// format 22 is the locale's date and time.
+const styles = `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FFFFF2CC"/><bgColor indexed="64"/></patternFill></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="5">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="22" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="0" fillId="2" borderId="0" xfId="0" applyFill="1"/>` +
+	`<xf numFmtId="22" fontId="0" fillId="2" borderId="0" xfId="0" applyNumberFormat="1" applyFill="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`